@@ -4,34 +4,90 @@ import (
     "context"
     "fmt"
     "os"
+    "strings"
 
     "github.com/andy/timesink/internal/app"
     "github.com/andy/timesink/internal/cli"
+    "github.com/andy/timesink/internal/config"
 )
 
 func main() {
     // If the user asked for help, avoid initializing the full app (which may prompt)
-    skipInit := false
-    for _, a := range os.Args[1:] {
+    help := false
+    verbose := false
+    // Flags that take a value and are needed before cobra parses flags
+    valueFlags := map[string]string{"--profile": "", "--config": ""}
+    command, subcommand := "", ""
+    args := os.Args[1:]
+    for i := 0; i < len(args); i++ {
+        a := args[i]
         if a == "-h" || a == "--help" || a == "help" {
-            skipInit = true
-            break
+            help = true
+        }
+        // Logging, the profile, and the config file are set up with the app
+        if a == "-v" || a == "--verbose" {
+            verbose = true
+        }
+        name, value, hasValue := strings.Cut(a, "=")
+        if _, ok := valueFlags[name]; ok {
+            if !hasValue && i+1 < len(args) {
+                i++
+                value = args[i]
+            }
+            valueFlags[name] = value
+            continue
+        }
+        if !strings.HasPrefix(a, "-") {
+            if command == "" {
+                command = a
+            } else if subcommand == "" {
+                subcommand = a
+            }
+        }
+    }
+    // Profile and config management work on files, not the database, and
+    // must run even when the config is invalid
+    skipInit := help || command == "profile" || command == "config"
+
+    // Move files left in ~/.config/timesink by versions before the XDG split
+    if !help {
+        moves, err := config.MigrateLegacyLayout()
+        for _, m := range moves {
+            fmt.Fprintf(os.Stderr, "Moved %s to %s\n", m.From, m.To)
+        }
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "failed to migrate files to XDG directories: %v\n", err)
+            os.Exit(1)
         }
     }
 
+    var a *app.App
     if !skipInit {
         ctx := context.Background()
-        a, err := app.New(ctx)
+        var err error
+        profile := config.ResolveProfile(valueFlags["--profile"])
+        a, err = app.New(ctx, app.Options{
+            Profile:    profile,
+            ConfigPath: config.ResolveConfigPath(profile, valueFlags["--config"]),
+            Verbose:    verbose,
+            // Migrating to a chosen version starts from the schema as found
+            SkipMigrations: command == "db" && subcommand == "migrate",
+        })
         if err != nil {
             fmt.Fprintf(os.Stderr, "failed to initialize app: %v\n", err)
             os.Exit(1)
         }
-        defer a.Close()
         cli.SetApp(a)
     }
 
-    if err := cli.Execute(); err != nil {
+    err := cli.Execute()
+    if a != nil {
+        a.Close()
+    }
+    if err != nil {
         fmt.Fprintln(os.Stderr, err)
         os.Exit(1)
     }
+    // Some commands report state through the exit code, e.g. timer status
+    os.Exit(cli.ExitCode())
 }
@@ -3,11 +3,18 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/andy/timesink/internal/config"
 	"github.com/andy/timesink/internal/crypto"
 	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/hooks"
+	"github.com/andy/timesink/internal/logging"
 	"github.com/andy/timesink/internal/repository"
 	"github.com/andy/timesink/internal/service"
 	"golang.org/x/term"
@@ -15,19 +22,52 @@ import (
 
 // App is the dependency injection container for all application components
 type App struct {
-	Config *config.Config
-	DB     *db.DB
+	Config     *config.Config
+	ConfigPath string // File the config was loaded from and saves to
+	Profile    string // Named set of config, database, and key in use
+	DB         *db.DB
+	Logger     *slog.Logger
+
+	logFile io.Closer
+	keyring crypto.Keyring
 
 	// Repositories
-	ClientRepo  repository.ClientRepository
-	EntryRepo   repository.TimeEntryRepository
-	InvoiceRepo repository.InvoiceRepository
-	TimerRepo   repository.TimerRepository
+	ClientRepo     repository.ClientRepository
+	EntryRepo      repository.TimeEntryRepository
+	InvoiceRepo    repository.InvoiceRepository
+	TimerRepo      repository.TimerRepository
+	EstimateRepo   repository.EstimateRepository
+	AttachmentRepo repository.AttachmentRepository
+	ContractRepo   repository.ContractRepository
+	TimeOffRepo    repository.TimeOffRepository
+	DeliveryRepo   repository.DeliveryRepository
+	SearchRepo     repository.SearchRepository
 
 	// Services
-	TimerService   service.TimerService
-	InvoiceService service.InvoiceService
-	ReportService  service.ReportService
+	ClientService     service.ClientService
+	TimerService      service.TimerService
+	InvoiceService    service.InvoiceService
+	ReportService     service.ReportService
+	EstimateService   service.EstimateService
+	AttachmentService service.AttachmentService
+	DeliveryService   service.DeliveryService
+}
+
+// Options selects what an App is built from
+type Options struct {
+	Profile    string // Config, database, and keyring entry to use
+	ConfigPath string // Overrides the profile's config.yaml when set
+	Verbose    bool   // Raises the log level to debug and mirrors the log to stderr
+
+	SkipMigrations bool // Leaves the schema as found, for `db migrate`
+}
+
+// configPath returns the config file to load and save
+func (o Options) configPath() string {
+	if o.ConfigPath != "" {
+		return o.ConfigPath
+	}
+	return config.ProfileConfigPath(o.Profile)
 }
 
 // New creates a new App instance, initializing all dependencies
@@ -38,31 +78,47 @@ type App struct {
 // 4. Running migrations
 // 5. Creating repositories
 // 6. Creating services
-func New(ctx context.Context) (*App, error) {
-	// Load config from default path
-	cfg, err := config.LoadDefault()
+func New(ctx context.Context, opts Options) (*App, error) {
+	// Load config from the profile's directory unless a file was given
+	cfg, err := config.LoadProfileFile(opts.Profile, opts.configPath())
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		hint := "Fix it with `timesink config edit`"
+		if overrides := cfg.Overrides(); len(overrides) > 0 {
+			hint += " or check " + strings.Join(overrides, ", ")
+		}
+		return nil, fmt.Errorf("invalid config %s:\n%w\n%s", opts.configPath(), err, hint)
+	}
 
-	return NewWithConfig(ctx, cfg)
+	return NewWithConfig(ctx, cfg, opts)
 }
 
 // NewWithConfig creates an App with a provided config (useful for testing)
-func NewWithConfig(ctx context.Context, cfg *config.Config) (*App, error) {
+func NewWithConfig(ctx context.Context, cfg *config.Config, opts Options) (*App, error) {
 	// Ensure all necessary directories exist
 	if err := cfg.EnsureDirectories(); err != nil {
 		return nil, fmt.Errorf("failed to create directories: %w", err)
 	}
 
+	logger, logFile, err := logging.New(cfg.Log, opts.Verbose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up logging: %w", err)
+	}
+
 	// Get keyring for secure password storage
-	keyring := crypto.NewKeyring()
+	keyring := crypto.NewKeyring(opts.Profile)
 
 	// Try to get existing encryption key
 	password, err := keyring.GetKey()
 	if err != nil {
 		// No key exists, prompt user to set one
-		fmt.Println("Setting up database encryption for the first time...")
+		if opts.Profile != config.DefaultProfile {
+			fmt.Printf("Setting up database encryption for profile %q...\n", opts.Profile)
+		} else {
+			fmt.Println("Setting up database encryption for the first time...")
+		}
 		password, err = promptForPassword()
 		if err != nil {
 			return nil, fmt.Errorf("failed to set password: %w", err)
@@ -77,41 +133,87 @@ func NewWithConfig(ctx context.Context, cfg *config.Config) (*App, error) {
 	// Open the database with encryption
 	database, err := db.Open(cfg.Database.Path, password)
 	if err != nil {
+		logFile.Close()
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	// Run migrations to ensure schema is up to date
-	if err := database.RunMigrations(); err != nil {
-		database.Close()
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	// Run migrations to ensure schema is up to date, unless the caller is
+	// about to choose the version itself
+	if !opts.SkipMigrations {
+		result, err := database.RunMigrations()
+		if err != nil {
+			database.Close()
+			logFile.Close()
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
+		if len(result.Steps) > 0 {
+			logger.Info("migrated database", "from", result.From, "to", result.To, "backup", result.Backup)
+			if result.Backup != "" {
+				fmt.Fprintf(os.Stderr, "Upgraded the database to schema version %d; the previous one is saved at %s\n",
+					result.To, result.Backup)
+			}
+		}
 	}
 
+	// User hooks run after the changes they announce are saved
+	hookRunner := hooks.NewRunner(cfg.Hooks.Dir, time.Duration(cfg.Hooks.TimeoutSeconds)*time.Second, opts.Profile, logger)
+
 	// Create repositories
 	clientRepo := repository.NewClientRepo(database)
-	entryRepo := repository.NewEntryRepo(database)
+	entryRepo := repository.WithEntryLogging(repository.NewEntryRepo(database), logger)
+	entryRepo = hooks.WrapEntryRepo(entryRepo, hookRunner)
 	invoiceRepo := repository.NewInvoiceRepo(database)
 	timerRepo := repository.NewTimerRepo(database)
+	estimateRepo := repository.NewEstimateRepo(database)
+	attachmentRepo := repository.NewAttachmentRepo(database)
+	contractRepo := repository.NewContractRepo(database)
+	timeOffRepo := repository.NewTimeOffRepo(database)
+	deliveryRepo := repository.NewDeliveryRepo(database)
+	searchRepo := repository.NewSearchRepo(database)
+	uow := repository.NewUnitOfWork(database, logger)
 
 	// Create services with their dependencies
-	timerService := service.NewTimerService(timerRepo, entryRepo, clientRepo)
-	invoiceService := service.NewInvoiceService(invoiceRepo, entryRepo, clientRepo)
-	reportService := service.NewReportService(entryRepo, invoiceRepo)
+	clientService := service.NewClientService(clientRepo, logger)
+	timerService := hooks.WrapTimerService(service.NewTimerService(timerRepo, entryRepo, clientRepo, uow, logger), hookRunner)
+	invoiceService := hooks.WrapInvoiceService(service.NewInvoiceService(invoiceRepo, entryRepo, clientRepo, uow, logger), invoiceRepo, hookRunner)
+	reportService := service.NewReportService(entryRepo, invoiceRepo, timeOffRepo)
+	estimateService := service.NewEstimateService(estimateRepo, invoiceRepo, clientRepo, uow, logger)
+	attachmentService := service.NewAttachmentService(attachmentRepo, invoiceRepo, cfg.Database.AttachmentsDir, logger)
+	deliveryService := service.NewDeliveryService(deliveryRepo, invoiceRepo, clientRepo, uow, logger)
 
 	return &App{
-		Config:         cfg,
-		DB:             database,
-		ClientRepo:     clientRepo,
-		EntryRepo:      entryRepo,
-		InvoiceRepo:    invoiceRepo,
-		TimerRepo:      timerRepo,
-		TimerService:   timerService,
-		InvoiceService: invoiceService,
-		ReportService:  reportService,
+		Config:            cfg,
+		ConfigPath:        opts.configPath(),
+		Profile:           opts.Profile,
+		DB:                database,
+		Logger:            logger,
+		logFile:           logFile,
+		keyring:           keyring,
+		ClientRepo:        clientRepo,
+		EntryRepo:         entryRepo,
+		InvoiceRepo:       invoiceRepo,
+		TimerRepo:         timerRepo,
+		EstimateRepo:      estimateRepo,
+		AttachmentRepo:    attachmentRepo,
+		ContractRepo:      contractRepo,
+		TimeOffRepo:       timeOffRepo,
+		DeliveryRepo:      deliveryRepo,
+		SearchRepo:        searchRepo,
+		ClientService:     clientService,
+		TimerService:      timerService,
+		InvoiceService:    invoiceService,
+		ReportService:     reportService,
+		EstimateService:   estimateService,
+		AttachmentService: attachmentService,
+		DeliveryService:   deliveryService,
 	}, nil
 }
 
 // Close cleanly shuts down the application
 func (a *App) Close() error {
+	if a.logFile != nil {
+		a.logFile.Close()
+	}
 	if a.DB != nil {
 		return a.DB.Close()
 	}
@@ -164,7 +266,10 @@ func (a *App) RecoverTimer(ctx context.Context) error {
 	return a.TimerService.RecoverFromCrash(ctx)
 }
 
-// SaveConfig saves the current configuration to disk
+// SaveConfig validates the current configuration and saves it to disk
 func (a *App) SaveConfig() error {
-	return a.Config.Save(config.DefaultConfigPath())
+	if err := a.Config.Validate(); err != nil {
+		return err
+	}
+	return a.Config.Save(a.ConfigPath)
 }
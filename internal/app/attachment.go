@@ -0,0 +1,49 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// OpenAttachment opens an attachment's stored file with the system's default
+// application. It returns once the viewer has started.
+func (a *App) OpenAttachment(attachment *domain.Attachment) error {
+	path := a.AttachmentService.Path(attachment)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("attachment file missing: %w", err)
+	}
+	if err := a.OpenFile(path); err != nil {
+		return err
+	}
+
+	a.Logger.Info("attachment opened", "attachment_id", attachment.ID)
+	return nil
+}
+
+// OpenFile opens a file with the system's default application. It returns
+// once the viewer has started.
+func (a *App) OpenFile(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("file missing: %w", err)
+	}
+
+	var open *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		open = exec.Command("open", path)
+	case "windows":
+		open = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		open = exec.Command("xdg-open", path)
+	}
+	if err := open.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	// Reap the viewer in the background so it does not linger as a zombie
+	go open.Wait()
+	return nil
+}
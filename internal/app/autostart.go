@@ -0,0 +1,43 @@
+package app
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/andy/timesink/internal/config"
+)
+
+// AutoStart returns the timer.auto_start rule matching dir, or the git
+// repository it is in, and the description of the timer it starts: the
+// rule's, or else the name of the repository or directory. The rule is nil
+// when none matches. Without git installed only paths are matched.
+func (a *App) AutoStart(dir string) (*config.AutoStartRule, string) {
+	root := gitOutput(dir, "rev-parse", "--show-toplevel")
+	remote := ""
+	if root != "" {
+		remote = gitOutput(dir, "remote", "get-url", "origin")
+	}
+
+	rule := a.Config.Timer.AutoStartFor(dir, remote)
+	if rule == nil {
+		return nil, ""
+	}
+	if description := strings.TrimSpace(rule.Description); description != "" {
+		return rule, description
+	}
+	if root != "" {
+		return rule, filepath.Base(root)
+	}
+	return rule, filepath.Base(dir)
+}
+
+// gitOutput runs git in dir and returns its trimmed output, or "" if it
+// fails, e.g. outside a repository
+func gitOutput(dir string, args ...string) string {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"context"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// ContractsByClient loads every contract grouped by client, for flagging
+// entries logged after a client's contracts lapsed
+func (a *App) ContractsByClient(ctx context.Context) (map[int64][]*domain.Contract, error) {
+	contracts, err := a.ContractRepo.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	byClient := make(map[int64][]*domain.Contract)
+	for _, c := range contracts {
+		byClient[c.ClientID] = append(byClient[c.ClientID], c)
+	}
+	return byClient, nil
+}
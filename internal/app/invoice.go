@@ -0,0 +1,54 @@
+package app
+
+import (
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/render"
+)
+
+// InvoiceTaxes returns the tax lines for a new invoice to client from the
+// configured taxes, falling back to default_tax_rate as a single line.
+// Reverse-charge clients get one 0% line carrying the reverse-charge note.
+func (a *App) InvoiceTaxes(client *domain.Client) []*domain.InvoiceTax {
+	cfg := a.Config.Invoice
+
+	if client != nil && client.ReverseCharge {
+		label := "VAT"
+		if len(cfg.Taxes) > 0 {
+			label = cfg.Taxes[0].Label
+		}
+		note := cfg.ReverseChargeNote
+		if note == "" {
+			note = config.DefaultReverseChargeNote
+		}
+		return []*domain.InvoiceTax{{Label: label, Note: note}}
+	}
+
+	taxes := make([]*domain.InvoiceTax, 0, len(cfg.Taxes))
+	for _, tax := range cfg.Taxes {
+		taxes = append(taxes, &domain.InvoiceTax{Label: tax.Label, Rate: tax.Rate})
+	}
+	if len(taxes) == 0 && cfg.DefaultTaxRate > 0 {
+		taxes = append(taxes, &domain.InvoiceTax{Label: "Tax", Rate: cfg.DefaultTaxRate})
+	}
+	return taxes
+}
+
+// InvoiceLabels returns the labels for invoices to client, in the client's
+// language with the configured translations
+func (a *App) InvoiceLabels(client *domain.Client) (render.Labels, error) {
+	language := ""
+	if client != nil {
+		language = client.Language
+	}
+	return render.LabelsFor(language, a.Config.Invoice.Translations)
+}
+
+// InvoiceFooter returns the notes and payment instructions for a new
+// invoice, from config
+func (a *App) InvoiceFooter() domain.InvoiceFooter {
+	return domain.InvoiceFooter{
+		Notes:               a.Config.Invoice.Notes,
+		PaymentInstructions: a.Config.User.PaymentInstructions,
+	}
+}
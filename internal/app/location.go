@@ -0,0 +1,24 @@
+package app
+
+import (
+	"os"
+	"strings"
+
+	"github.com/andy/timesink/internal/config"
+)
+
+// Location returns where a timer started or an entry created now is
+// recorded as done, from timer.location. A hostname or working directory
+// that cannot be read is recorded as empty.
+func (a *App) Location() string {
+	switch location := strings.TrimSpace(a.Config.Timer.Location); location {
+	case config.LocationHostname:
+		name, _ := os.Hostname()
+		return name
+	case config.LocationDirectory:
+		dir, _ := os.Getwd()
+		return dir
+	default:
+		return location
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+)
+
+// ErrWrongPassphrase is returned by Unlock when the passphrase does not
+// match the key in the keyring
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// lockPath is the marker that locks the profile's session. It lives in the
+// state directory so every running timesink sees it.
+func (a *App) lockPath() string {
+	return filepath.Join(config.ProfileStateDir(a.Profile), "locked")
+}
+
+// Lock locks the session: figures stay hidden until the passphrase is
+// entered again. The key itself stays in the keyring.
+func (a *App) Lock() error {
+	path := a.lockPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to lock session: %w", err)
+	}
+	a.Logger.Info("session locked")
+	return nil
+}
+
+// IsLocked reports whether the session is locked
+func (a *App) IsLocked() bool {
+	_, err := os.Stat(a.lockPath())
+	return err == nil
+}
+
+// Unlock checks the passphrase against the keyring and unlocks the session
+func (a *App) Unlock(passphrase string) error {
+	key, err := a.keyring.GetKey()
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(passphrase), []byte(key)) != 1 {
+		a.Logger.Warn("unlock failed")
+		return ErrWrongPassphrase
+	}
+
+	if err := os.Remove(a.lockPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to unlock session: %w", err)
+	}
+	a.Logger.Info("session unlocked")
+	return nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// ClientRatesOn returns, by entry ID, the rate each entry's client charged
+// on the entry's day, or for an entry's role the rate on the client's rate
+// card, for flagging entries billed at another rate. Entries of clients
+// missing from clients, or of roles since taken off the card, are left out.
+func (a *App) ClientRatesOn(ctx context.Context, entries []*domain.TimeEntry, clients map[int64]*domain.Client) (map[int64]float64, error) {
+	history := make(map[int64][]*domain.ClientRate)
+	cards := make(map[int64][]*domain.RoleRate)
+	rates := make(map[int64]float64, len(entries))
+	for _, entry := range entries {
+		client, ok := clients[entry.ClientID]
+		if !ok {
+			continue
+		}
+		if entry.Role != "" {
+			if _, loaded := cards[client.ID]; !loaded {
+				card, err := a.ClientRepo.ListRoleRates(ctx, client.ID)
+				if err != nil {
+					return nil, err
+				}
+				cards[client.ID] = card
+			}
+			if role := domain.FindRole(cards[client.ID], entry.Role); role != nil {
+				rates[entry.ID] = role.HourlyRate
+			}
+			continue
+		}
+		if _, loaded := history[client.ID]; !loaded {
+			clientRates, err := a.ClientRepo.ListRates(ctx, client.ID)
+			if err != nil {
+				return nil, err
+			}
+			history[client.ID] = clientRates
+		}
+		rates[entry.ID] = client.RateOn(history[client.ID], entry.StartTime)
+	}
+	return rates, nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// ResetScope is how much of the database a reset deletes
+type ResetScope string
+
+const (
+	ResetInvoices ResetScope = "invoices" // invoices, unlocking their entries
+	ResetEntries  ResetScope = "entries"  // entries, invoices and timer state
+	ResetAll      ResetScope = "all"      // everything, clients included
+)
+
+// resetTables lists the tables each scope clears, in foreign key order
+var resetTables = map[ResetScope][]string{
+	ResetInvoices: {
+		"invoice_line_items",
+		"invoice_taxes",
+		"invoice_deliveries",
+		"attachments",
+		"invoices",
+	},
+	ResetEntries: {
+		"invoice_line_items",
+		"invoice_taxes",
+		"invoice_deliveries",
+		"attachments",
+		"invoices",
+		"entry_history",
+		"time_entries",
+		"active_timer",
+	},
+	ResetAll: {
+		"invoice_line_items",
+		"invoice_taxes",
+		"invoice_deliveries",
+		"attachments",
+		"invoices",
+		"entry_history",
+		"time_entries",
+		"active_timer",
+		"estimate_line_items",
+		"estimates",
+		"contracts",
+		"time_off",
+		"client_rate_history",
+		"clients",
+	},
+}
+
+// Reset deletes the data in scope, all or nothing, then the stored files of
+// the invoice attachments it cleared. Callers confirm with the user first.
+func (a *App) Reset(ctx context.Context, scope ResetScope) error {
+	tables, ok := resetTables[scope]
+	if !ok {
+		return fmt.Errorf("unknown reset scope %q", scope)
+	}
+
+	tx, err := a.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Clear invoice references from entries before deleting invoices
+	if _, err := tx.ExecContext(ctx, "UPDATE time_entries SET invoice_id = NULL WHERE invoice_id IS NOT NULL"); err != nil {
+		return fmt.Errorf("failed to unlock entries: %w", err)
+	}
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reset: %w", err)
+	}
+
+	dir := filepath.Join(a.Config.Database.AttachmentsDir, string(domain.AttachmentOwnerInvoice))
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove attachment files: %w", err)
+	}
+
+	a.Logger.Warn("data reset", "scope", string(scope))
+	return nil
+}
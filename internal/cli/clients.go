@@ -3,16 +3,19 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/andy/timesink/internal/domain"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var clientsCmd = &cobra.Command{
 	Use:   "clients",
 	Short: "Manage clients",
-	Long:  `List, add, edit, and archive clients.`,
+	Long:  `List, add, edit, and archive clients, review their rate history and rate cards, and track their contracts.`,
 }
 
 var clientsListCmd = &cobra.Command{
@@ -42,10 +45,10 @@ var clientsListCmd = &cobra.Command{
 			if client.IsArchived {
 				status = "Archived"
 			}
-			fmt.Printf("%-5d %-30s $%-14.2f %-10s\n",
+			fmt.Printf("%-5d %-30s %-15s %-10s\n",
 				client.ID,
 				truncate(client.Name, 30),
-				client.HourlyRate,
+				formatMoney(client.HourlyRate),
 				status,
 			)
 		}
@@ -66,26 +69,88 @@ var clientsAddCmd = &cobra.Command{
 		rate, _ := cmd.Flags().GetFloat64("rate")
 		email, _ := cmd.Flags().GetString("email")
 		notes, _ := cmd.Flags().GetString("notes")
+		timesheet, _ := cmd.Flags().GetBool("timesheet")
+		reverseCharge, _ := cmd.Flags().GetBool("reverse-charge")
+		requireApproval, _ := cmd.Flags().GetBool("require-approval")
 
 		client := domain.NewClient(name, rate)
 		client.Email = email
 		client.Notes = notes
+		client.AttachTimesheet = timesheet
+		client.ReverseCharge = reverseCharge
+		client.RequireApproval = requireApproval
+		client.Language = mustGetString(cmd, "language")
+		client.WeeklyDemand, _ = cmd.Flags().GetFloat64("weekly-hours")
+		client.Billing.MinIncrementMinutes, _ = cmd.Flags().GetInt("min-increment")
+		client.Billing.DailyCapHours, _ = cmd.Flags().GetFloat64("daily-cap")
+		client.Billing.OvertimeMultiplier, _ = cmd.Flags().GetFloat64("overtime")
 
 		if err := client.Validate(); err != nil {
 			return fmt.Errorf("invalid client: %w", err)
 		}
+		if _, err := appInstance.InvoiceLabels(client); err != nil {
+			return err
+		}
+		if !confirmNewClientName(ctx, client.Name) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
 
-		if err := appInstance.ClientRepo.Create(ctx, client); err != nil {
+		if err := appInstance.ClientService.Create(ctx, client); err != nil {
 			return fmt.Errorf("failed to create client: %w", err)
 		}
 
 		fmt.Printf("✓ Client created: %s (ID: %d)\n", client.Name, client.ID)
-		fmt.Printf("  Hourly Rate: $%.2f\n", client.HourlyRate)
+		fmt.Printf("  Hourly Rate: %s\n", formatMoney(client.HourlyRate))
+		if !client.Billing.IsZero() {
+			fmt.Printf("  Billing Rules: %s\n", client.Billing.Describe())
+		}
+		if client.ReverseCharge {
+			fmt.Println("  Reverse charge: invoiced without tax")
+		}
+		if client.RequireApproval {
+			fmt.Println("  Approval: only approved entries are invoiced")
+		}
+		if client.Language != "" {
+			fmt.Printf("  Invoice language: %s\n", client.Language)
+		}
+		if client.WeeklyDemand > 0 {
+			fmt.Printf("  Expected demand: %s hours/week\n", formatHours(client.WeeklyDemand))
+		}
 
 		return nil
 	},
 }
 
+// confirmNewClientName asks before adding a client whose name resembles
+// existing ones, in case one of them was meant. A name that only differs
+// in case or spacing is left for ClientService to refuse, and there is no
+// one to ask when stdin is not a terminal.
+func confirmNewClientName(ctx context.Context, name string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true
+	}
+	similar, err := appInstance.ClientService.Similar(ctx, name)
+	if err != nil || len(similar) == 0 {
+		return true
+	}
+	for _, c := range similar {
+		if c.HasName(name) {
+			return true
+		}
+	}
+
+	fmt.Println("Existing clients with a similar name:")
+	for _, c := range similar {
+		archived := ""
+		if c.IsArchived {
+			archived = " (archived)"
+		}
+		fmt.Printf("  %d  %s%s\n", c.ID, c.Name, archived)
+	}
+	return confirmPrompt(fmt.Sprintf("Add %q as a new client anyway?", name))
+}
+
 var clientsEditCmd = &cobra.Command{
 	Use:   "edit [id]",
 	Short: "Edit an existing client",
@@ -111,9 +176,18 @@ var clientsEditCmd = &cobra.Command{
 			name, _ := cmd.Flags().GetString("name")
 			client.Name = name
 		}
-		if cmd.Flags().Changed("rate") {
-			rate, _ := cmd.Flags().GetFloat64("rate")
-			client.HourlyRate = rate
+		// A new rate is recorded in the rate history after the other fields
+		rateChanged := cmd.Flags().Changed("rate")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		effective := time.Now()
+		if cmd.Flags().Changed("effective") {
+			if !rateChanged {
+				return fmt.Errorf("--effective requires --rate")
+			}
+			value, _ := cmd.Flags().GetString("effective")
+			if effective, err = parseDate(value); err != nil {
+				return fmt.Errorf("invalid effective date: %w", err)
+			}
 		}
 		if cmd.Flags().Changed("email") {
 			email, _ := cmd.Flags().GetString("email")
@@ -123,16 +197,119 @@ var clientsEditCmd = &cobra.Command{
 			notes, _ := cmd.Flags().GetString("notes")
 			client.Notes = notes
 		}
+		if cmd.Flags().Changed("timesheet") {
+			timesheet, _ := cmd.Flags().GetBool("timesheet")
+			client.AttachTimesheet = timesheet
+		}
+		if cmd.Flags().Changed("reverse-charge") {
+			client.ReverseCharge, _ = cmd.Flags().GetBool("reverse-charge")
+		}
+		if cmd.Flags().Changed("require-approval") {
+			client.RequireApproval, _ = cmd.Flags().GetBool("require-approval")
+		}
+		if cmd.Flags().Changed("language") {
+			client.Language = mustGetString(cmd, "language")
+			if _, err := appInstance.InvoiceLabels(client); err != nil {
+				return err
+			}
+		}
+		if cmd.Flags().Changed("weekly-hours") {
+			client.WeeklyDemand, _ = cmd.Flags().GetFloat64("weekly-hours")
+		}
+		if cmd.Flags().Changed("min-increment") {
+			client.Billing.MinIncrementMinutes, _ = cmd.Flags().GetInt("min-increment")
+		}
+		if cmd.Flags().Changed("daily-cap") {
+			client.Billing.DailyCapHours, _ = cmd.Flags().GetFloat64("daily-cap")
+		}
+		if cmd.Flags().Changed("overtime") {
+			client.Billing.OvertimeMultiplier, _ = cmd.Flags().GetFloat64("overtime")
+		}
 
 		if err := client.Validate(); err != nil {
 			return fmt.Errorf("invalid client: %w", err)
 		}
 
-		if err := appInstance.ClientRepo.Update(ctx, client); err != nil {
+		if err := appInstance.ClientService.Update(ctx, client); err != nil {
 			return fmt.Errorf("failed to update client: %w", err)
 		}
 
+		if rateChanged {
+			if err := appInstance.ClientRepo.SetRate(ctx, client.ID, rate, effective); err != nil {
+				return fmt.Errorf("failed to set rate: %w", err)
+			}
+		}
+
 		fmt.Printf("✓ Client updated: %s\n", client.Name)
+		if rateChanged {
+			fmt.Printf("  Hourly Rate: %s from %s\n", formatMoney(rate), formatDate(effective))
+		}
+		if cmd.Flags().Changed("min-increment") || cmd.Flags().Changed("daily-cap") || cmd.Flags().Changed("overtime") {
+			fmt.Printf("  Billing Rules: %s\n", client.Billing.Describe())
+		}
+		if cmd.Flags().Changed("reverse-charge") {
+			if client.ReverseCharge {
+				fmt.Println("  Reverse charge: invoiced without tax")
+			} else {
+				fmt.Println("  Reverse charge: off")
+			}
+		}
+		if cmd.Flags().Changed("require-approval") {
+			if client.RequireApproval {
+				fmt.Println("  Approval: only approved entries are invoiced")
+			} else {
+				fmt.Println("  Approval: not required")
+			}
+		}
+		if cmd.Flags().Changed("language") {
+			labels, _ := appInstance.InvoiceLabels(client)
+			fmt.Printf("  Invoice language: %s\n", labels.Language)
+		}
+		if cmd.Flags().Changed("weekly-hours") {
+			fmt.Printf("  Expected demand: %s hours/week\n", formatHours(client.WeeklyDemand))
+		}
+		return nil
+	},
+}
+
+var clientsRatesCmd = &cobra.Command{
+	Use:   "rates [client_id_or_name]",
+	Short: "Show a client's rate history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		rates, err := appInstance.ClientRepo.ListRates(ctx, clientID)
+		if err != nil {
+			return fmt.Errorf("failed to list rates: %w", err)
+		}
+
+		if len(rates) == 0 {
+			fmt.Println("No rate history")
+			return nil
+		}
+
+		now := time.Now()
+		fmt.Printf("%-15s %-15s\n", "Effective From", "Hourly Rate")
+		fmt.Println("----------------------------------------")
+		current := true
+		for _, rate := range rates {
+			// Rates are newest first; the first one already in effect is current
+			note := ""
+			if rate.EffectiveFrom.After(now) {
+				note = "scheduled"
+			} else if current {
+				note = "current"
+				current = false
+			}
+			fmt.Printf("%-15s %-15s %s\n", formatDate(rate.EffectiveFrom), formatMoney(rate.HourlyRate), note)
+		}
+
 		return nil
 	},
 }
@@ -191,6 +368,7 @@ func init() {
 	clientsCmd.AddCommand(clientsListCmd)
 	clientsCmd.AddCommand(clientsAddCmd)
 	clientsCmd.AddCommand(clientsEditCmd)
+	clientsCmd.AddCommand(clientsRatesCmd)
 	clientsCmd.AddCommand(clientsArchiveCmd)
 	clientsCmd.AddCommand(clientsUnarchiveCmd)
 
@@ -202,12 +380,33 @@ func init() {
 	clientsAddCmd.MarkFlagRequired("rate")
 	clientsAddCmd.Flags().String("email", "", "Client email")
 	clientsAddCmd.Flags().String("notes", "", "Notes about the client")
+	clientsAddCmd.Flags().Bool("timesheet", false, "Attach a detailed timesheet to each invoice")
+	clientsAddCmd.Flags().Bool("reverse-charge", false, "Invoice without tax, with a reverse-charge note (EU B2B)")
+	clientsAddCmd.Flags().Bool("require-approval", false, "Only invoice entries the client has approved")
+	clientsAddCmd.Flags().String("language", "", "Language of the labels on invoices, e.g. de or fr (default: en)")
+	clientsAddCmd.Flags().Float64("weekly-hours", 0, "Hours of work the client is expected to need each week, for capacity planning")
+	addBillingFlags(clientsAddCmd)
 
 	// Edit flags
 	clientsEditCmd.Flags().String("name", "", "New name")
 	clientsEditCmd.Flags().Float64("rate", 0, "New hourly rate")
+	clientsEditCmd.Flags().String("effective", "", "Day the new rate takes effect (YYYY-MM-DD, 'today', or 'yesterday'; default: today)")
 	clientsEditCmd.Flags().String("email", "", "New email")
 	clientsEditCmd.Flags().String("notes", "", "New notes")
+	clientsEditCmd.Flags().Bool("timesheet", false, "Attach a detailed timesheet to each invoice (--timesheet=false to stop)")
+	clientsEditCmd.Flags().Bool("reverse-charge", false, "Invoice without tax, with a reverse-charge note (--reverse-charge=false to stop)")
+	clientsEditCmd.Flags().Bool("require-approval", false, "Only invoice entries the client has approved (--require-approval=false to stop)")
+	clientsEditCmd.Flags().String("language", "", "Language of the labels on invoices, e.g. de or fr (\"\" for English)")
+	clientsEditCmd.Flags().Float64("weekly-hours", 0, "Hours of work the client is expected to need each week (0 for none)")
+	addBillingFlags(clientsEditCmd)
+}
+
+// addBillingFlags adds the flags for a client's billing rules, which are
+// applied when invoice totals are calculated
+func addBillingFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("min-increment", 0, "Bill each entry in whole increments of this many minutes, at least one (0 = exact time)")
+	cmd.Flags().Float64("daily-cap", 0, "Billable hours per day before overtime (0 = no cap)")
+	cmd.Flags().Float64("overtime", 0, "Rate multiplier for hours over the daily cap (0 = not billed)")
 }
 
 func truncate(s string, maxLen int) string {
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Check and edit the configuration",
+}
+
+var configCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate config.yaml",
+	Long: `Validate the active config file, with TIMESINK_* environment overrides
+applied, and list every problem found. Exits with status 1 if the config is
+invalid.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, path := cmdConfig(cmd)
+
+		cfg, err := config.LoadProfileFile(profile, path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+
+		if overrides := cfg.Overrides(); len(overrides) > 0 {
+			fmt.Printf("Overridden by environment: %s\n", strings.Join(overrides, ", "))
+		}
+
+		if err := validateConfig(cfg); err != nil {
+			fmt.Printf("✗ %s has problems:\n", path)
+			printConfigProblems(err)
+			exitCode = 1
+			return nil
+		}
+
+		fmt.Printf("✓ %s is valid\n", path)
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.yaml in $EDITOR and validate it",
+	Long: `Open the active config file in $EDITOR (vi if unset). The file is
+validated when the editor exits, with the option to fix any problems.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, path := cmdConfig(cmd)
+
+		// Start from the defaults so there is something to edit
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			cfg, err := config.LoadProfileFile(profile, path)
+			if err != nil {
+				return err
+			}
+			if err := cfg.Save(path); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+
+		editor := os.Getenv("EDITOR")
+		if editor == "" {
+			editor = "vi"
+		}
+
+		for {
+			edit := exec.Command(editor, path)
+			edit.Stdin, edit.Stdout, edit.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := edit.Run(); err != nil {
+				return fmt.Errorf("failed to run %s: %w", editor, err)
+			}
+
+			cfg, err := config.LoadProfileFile(profile, path)
+			if err == nil {
+				err = validateConfig(cfg)
+			}
+			if err == nil {
+				fmt.Printf("✓ %s is valid\n", path)
+				return nil
+			}
+
+			fmt.Printf("✗ %s has problems:\n", path)
+			printConfigProblems(err)
+			if !confirmPrompt("Edit again?") {
+				exitCode = 1
+				return nil
+			}
+		}
+	},
+}
+
+// cmdProfile returns the profile selected for a command that runs without
+// the app, which would otherwise have resolved it
+func cmdProfile(cmd *cobra.Command) string {
+	flag, _ := cmd.Flags().GetString("profile")
+	return config.ResolveProfile(flag)
+}
+
+// cmdConfig returns the profile and config file selected for a command that
+// runs without the app
+func cmdConfig(cmd *cobra.Command) (profile, path string) {
+	profile = cmdProfile(cmd)
+	flag, _ := cmd.Flags().GetString("config")
+	return profile, config.ResolveConfigPath(profile, flag)
+}
+
+// validateConfig checks cfg, and the settings config.Validate cannot check
+// without the packages that use them
+func validateConfig(cfg *config.Config) error {
+	return errors.Join(cfg.Validate(), render.CheckTranslations(cfg.Invoice.Translations))
+}
+
+// printConfigProblems lists the problems in a validation or parse error
+func printConfigProblems(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fmt.Printf("  - %v\n", e)
+		}
+		return
+	}
+	fmt.Printf("  - %v\n", err)
+}
+
+func init() {
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configEditCmd)
+}
@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var clientsContractsCmd = &cobra.Command{
+	Use:   "contracts [client_id_or_name]",
+	Short: "List contracts, or a client's contracts",
+	Long: `Contracts record the engagements you have with clients. The dashboard warns
+when one ends within 30 days, and entries logged after a client's contracts
+ended are flagged. A contract that renews automatically never lapses.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var clientID *int64
+		if len(args) == 1 {
+			id, err := resolveClientID(ctx, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to resolve client: %w", err)
+			}
+			clientID = &id
+		}
+
+		contracts, err := appInstance.ContractRepo.List(ctx, clientID)
+		if err != nil {
+			return fmt.Errorf("failed to list contracts: %w", err)
+		}
+
+		if len(contracts) == 0 {
+			fmt.Println("No contracts found")
+			return nil
+		}
+
+		fmt.Printf("%-5s %-20s %-14s %-14s %-10s %-12s %s\n", "ID", "Client", "Start", "End", "Rate", "Status", "Scope")
+		fmt.Println("------------------------------------------------------------------------------------------------")
+
+		now := time.Now()
+		for _, contract := range contracts {
+			clientName := fmt.Sprintf("Client #%d", contract.ClientID)
+			if client, err := appInstance.ClientRepo.GetByID(ctx, contract.ClientID); err == nil {
+				clientName = client.Name
+			}
+
+			rate := "client"
+			if contract.Rate > 0 {
+				rate = formatMoney(contract.Rate)
+			}
+
+			fmt.Printf("%-5d %-20s %-14s %-14s %-10s %-12s %s\n",
+				contract.ID,
+				truncate(clientName, 20),
+				formatDate(contract.StartDate),
+				formatDate(contract.EndDate),
+				rate,
+				contractStatus(contract, now),
+				truncate(contract.Scope, 30),
+			)
+		}
+
+		return nil
+	},
+}
+
+var clientsAddContractCmd = &cobra.Command{
+	Use:   "add [client_id_or_name]",
+	Short: "Add a contract for a client",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		if !cmd.Flags().Changed("end") {
+			return fmt.Errorf("--end is required")
+		}
+		start := time.Now()
+		if cmd.Flags().Changed("start") {
+			value, _ := cmd.Flags().GetString("start")
+			if start, err = parseDate(value); err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+		}
+		value, _ := cmd.Flags().GetString("end")
+		end, err := parseDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid end date: %w", err)
+		}
+
+		contract := domain.NewContract(clientID, start, end)
+		contract.Rate, _ = cmd.Flags().GetFloat64("rate")
+		contract.Scope, _ = cmd.Flags().GetString("scope")
+		contract.Renews, _ = cmd.Flags().GetBool("renews")
+
+		if err := appInstance.ContractRepo.Create(ctx, contract); err != nil {
+			return fmt.Errorf("failed to add contract: %w", err)
+		}
+
+		fmt.Printf("✓ Contract #%d added: %s to %s\n", contract.ID, formatDate(contract.StartDate), formatDate(contract.EndDate))
+		return nil
+	},
+}
+
+var clientsEditContractCmd = &cobra.Command{
+	Use:   "edit [contract_id]",
+	Short: "Edit a contract",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid contract ID: %w", err)
+		}
+
+		contract, err := appInstance.ContractRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get contract: %w", err)
+		}
+
+		if cmd.Flags().Changed("start") {
+			value, _ := cmd.Flags().GetString("start")
+			start, err := parseDate(value)
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			contract.StartDate = domain.RateDay(start)
+		}
+		if cmd.Flags().Changed("end") {
+			value, _ := cmd.Flags().GetString("end")
+			end, err := parseDate(value)
+			if err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+			contract.EndDate = domain.RateDay(end)
+		}
+		if cmd.Flags().Changed("rate") {
+			contract.Rate, _ = cmd.Flags().GetFloat64("rate")
+		}
+		if cmd.Flags().Changed("scope") {
+			contract.Scope, _ = cmd.Flags().GetString("scope")
+		}
+		if cmd.Flags().Changed("renews") {
+			contract.Renews, _ = cmd.Flags().GetBool("renews")
+		}
+		contract.UpdatedAt = time.Now()
+
+		if err := appInstance.ContractRepo.Update(ctx, contract); err != nil {
+			return fmt.Errorf("failed to update contract: %w", err)
+		}
+
+		fmt.Printf("✓ Contract #%d updated\n", contract.ID)
+		return nil
+	},
+}
+
+var clientsRemoveContractCmd = &cobra.Command{
+	Use:   "remove [contract_id]",
+	Short: "Delete a contract",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid contract ID: %w", err)
+		}
+
+		if err := appInstance.ContractRepo.Delete(context.Background(), id); err != nil {
+			return fmt.Errorf("failed to remove contract: %w", err)
+		}
+
+		fmt.Printf("✓ Contract #%d removed\n", id)
+		return nil
+	},
+}
+
+// contractStatus describes where a contract stands, e.g. "ends in 12d"
+func contractStatus(c *domain.Contract, now time.Time) string {
+	switch {
+	case now.Before(c.StartDate):
+		return "upcoming"
+	case c.Covers(now) && c.EndsWithin(now, domain.ContractWarningDays):
+		if c.Renews {
+			return fmt.Sprintf("renews %dd", c.DaysLeft(now))
+		}
+		return fmt.Sprintf("ends in %dd", c.DaysLeft(now))
+	case c.Covers(now):
+		return "active"
+	default:
+		return "ended"
+	}
+}
+
+func init() {
+	clientsCmd.AddCommand(clientsContractsCmd)
+	clientsContractsCmd.AddCommand(clientsAddContractCmd)
+	clientsContractsCmd.AddCommand(clientsEditContractCmd)
+	clientsContractsCmd.AddCommand(clientsRemoveContractCmd)
+
+	for _, c := range []*cobra.Command{clientsAddContractCmd, clientsEditContractCmd} {
+		c.Flags().String("start", "", "First day of the contract (YYYY-MM-DD, 'today', or 'yesterday'; default: today)")
+		c.Flags().String("end", "", "Last day of the contract (YYYY-MM-DD)")
+		c.Flags().Float64("rate", 0, "Agreed hourly rate, for reference (0: the client's rate)")
+		c.Flags().String("scope", "", "Scope notes")
+		c.Flags().Bool("renews", false, "Renews automatically at the end date (--renews=false to stop)")
+	}
+}
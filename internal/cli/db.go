@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and maintain the database",
+}
+
+var dbStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show database size, row counts, and schema version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stats, err := appInstance.DB.Stats(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to read database stats: %w", err)
+		}
+
+		fmt.Printf("Database:       %s\n", stats.Path)
+		fmt.Printf("Size:           %s", formatBytes(stats.SizeBytes))
+		if stats.WALBytes > 0 {
+			fmt.Printf(" (+ %s write-ahead log)", formatBytes(stats.WALBytes))
+		}
+		fmt.Println()
+		fmt.Printf("Schema version: %d\n", stats.SchemaVersion)
+		if stats.OldestEntry != nil {
+			fmt.Printf("Entries:        %s to %s\n", formatDate(*stats.OldestEntry), formatDate(*stats.NewestEntry))
+		}
+		fmt.Println()
+
+		fmt.Printf("%-20s %10s\n", "Table", "Rows")
+		fmt.Println("-------------------------------")
+		for _, table := range stats.Tables {
+			fmt.Printf("%-20s %10d\n", table.Name, table.Rows)
+		}
+
+		if stats.DeletedEntries > 0 {
+			fmt.Printf("\n%d soft-deleted entries are still stored; remove old ones with `timesink entries purge`.\n",
+				stats.DeletedEntries)
+		}
+		return nil
+	},
+}
+
+var dbVacuumCmd = &cobra.Command{
+	Use:   "vacuum",
+	Short: "Compact the database file",
+	Long: `Compact the database file, reclaiming space left by deleted and purged
+rows and folding the write-ahead log back into the main file.
+
+The database stays encrypted throughout. Vacuuming rewrites the whole file,
+so close any other running timesink before starting.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before := appInstance.DB.SizeBytes()
+		if err := appInstance.DB.Vacuum(context.Background()); err != nil {
+			return err
+		}
+		after := appInstance.DB.SizeBytes()
+
+		appInstance.Logger.Info("database vacuumed", "before_bytes", before, "after_bytes", after)
+		fmt.Printf("✓ Vacuumed database: %s → %s\n", formatBytes(before), formatBytes(after))
+		return nil
+	},
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move the database schema to another version",
+	Long: `Move the database schema to the latest version, or with --to to an older
+one before going back to an older timesink. Other commands upgrade the
+schema on start, so run this with the newer timesink just before replacing it.
+
+Migrations run in a single transaction. The database is first copied beside
+itself as timesink.db.v<version>-<time>.bak; to undo, close timesink and copy
+the backup over the database file. Some migrations cannot be reverted, and
+moving below one of them is refused.
+
+--dry-run lists the steps and runs them without committing, which checks
+they succeed while leaving the database as it was.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := db.LatestVersion()
+		if cmd.Flags().Changed("to") {
+			target, _ = cmd.Flags().GetInt("to")
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		current, err := appInstance.DB.SchemaVersion()
+		if err != nil {
+			return err
+		}
+		steps, err := appInstance.DB.MigrationPlan(target)
+		if err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			fmt.Printf("Schema is already at version %d.\n", current)
+			return nil
+		}
+
+		fmt.Printf("Schema version %d → %d:\n", current, target)
+		for _, step := range steps {
+			arrow := "↑"
+			if step.Down {
+				arrow = "↓"
+			}
+			fmt.Printf("  %s %2d  %s\n", arrow, step.Version, step.Description)
+		}
+
+		if !dryRun && target < current {
+			if !confirmPrompt("Reverting drops the data these migrations added. Continue?") {
+				fmt.Println("Cancelled.")
+				return nil
+			}
+		}
+
+		result, err := appInstance.DB.Migrate(context.Background(), target, dryRun)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			fmt.Println("✓ Dry run succeeded; nothing was changed")
+			return nil
+		}
+
+		appInstance.Logger.Info("database migrated", "from", result.From, "to", result.To, "backup", result.Backup)
+		fmt.Printf("✓ Migrated schema to version %d\n", result.To)
+		if result.Backup != "" {
+			fmt.Printf("  Backup: %s\n", result.Backup)
+		}
+		return nil
+	},
+}
+
+var dbQueryCmd = &cobra.Command{
+	Use:   "query [sql]",
+	Short: "Run a read-only SQL query",
+	Long: `Run a single SELECT, WITH, VALUES, or EXPLAIN statement against the
+database and print the result. The query is read from standard input when
+no argument is given. Writes are refused, both by checking the statement
+and by running it on a connection SQLite holds to read-only.
+
+Pragmas that read can be queried as tables, for example
+SELECT * FROM pragma_table_info('clients'). See ` + "`timesink db schema`" + ` for
+the tables and their columns.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := reportFormatFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		var query string
+		if len(args) == 1 {
+			query = args[0]
+		} else {
+			input, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read query: %w", err)
+			}
+			query = string(input)
+		}
+
+		result, err := appInstance.DB.ReadOnlyQuery(context.Background(), query)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case formatJSON:
+			return printJSON(queryRecords(result))
+		case formatCSV:
+			return printCSV(queryTable{result})
+		}
+		printQueryTable(result)
+		return nil
+	},
+}
+
+var dbSchemaCmd = &cobra.Command{
+	Use:   "schema [table]",
+	Short: "Print the statements that create the database's tables",
+	Long: `Print the CREATE statements for every table, index, trigger, and view,
+or only those of one table. The full-text search indexes' internal tables
+are left out.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table := ""
+		if len(args) == 1 {
+			table = args[0]
+		}
+		objects, err := appInstance.DB.Schema(context.Background(), table)
+		if err != nil {
+			return err
+		}
+		for i, o := range objects {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s;\n", o.SQL)
+		}
+		return nil
+	},
+}
+
+// queryTable adapts a query result to CSV output
+type queryTable struct {
+	*db.QueryResult
+}
+
+func (t queryTable) csvHeader() []string {
+	return t.Columns
+}
+
+func (t queryTable) csvRows() [][]string {
+	rows := make([][]string, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = make([]string, len(row))
+		for j, v := range row {
+			if v != nil {
+				rows[i][j] = formatQueryValue(v)
+			}
+		}
+	}
+	return rows
+}
+
+// queryRecords turns each row of a query result into an object keyed by
+// column name, for JSON output
+func queryRecords(result *db.QueryResult) []map[string]any {
+	records := make([]map[string]any, len(result.Rows))
+	for i, row := range result.Rows {
+		record := make(map[string]any, len(row))
+		for j, v := range row {
+			if b, ok := v.([]byte); ok && utf8.Valid(b) {
+				v = string(b)
+			}
+			record[result.Columns[j]] = v
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// printQueryTable prints a query result as a text table, with long values
+// cut short
+func printQueryTable(result *db.QueryResult) {
+	const maxWidth = 40
+
+	cells := make([][]string, len(result.Rows))
+	widths := make([]int, len(result.Columns))
+	for j, column := range result.Columns {
+		widths[j] = min(len(column), maxWidth)
+	}
+	for i, row := range result.Rows {
+		cells[i] = make([]string, len(row))
+		for j, v := range row {
+			cell := "NULL"
+			if v != nil {
+				cell = strings.ReplaceAll(formatQueryValue(v), "\n", " ")
+			}
+			cells[i][j] = truncate(cell, maxWidth)
+			widths[j] = max(widths[j], len(cells[i][j]))
+		}
+	}
+
+	printRow := func(values []string) {
+		for j, value := range values {
+			if j > 0 {
+				fmt.Print("  ")
+			}
+			fmt.Printf("%-*s", widths[j], value)
+		}
+		fmt.Println()
+	}
+
+	header := make([]string, len(result.Columns))
+	rule := make([]string, len(result.Columns))
+	for j, column := range result.Columns {
+		header[j] = truncate(column, maxWidth)
+		rule[j] = strings.Repeat("-", widths[j])
+	}
+	printRow(header)
+	printRow(rule)
+	for _, row := range cells {
+		printRow(row)
+	}
+
+	if len(result.Rows) == 1 {
+		fmt.Println("(1 row)")
+	} else {
+		fmt.Printf("(%d rows)\n", len(result.Rows))
+	}
+}
+
+// formatQueryValue formats a value read by a query. Blobs that are not text
+// are shown as hex.
+func formatQueryValue(v any) string {
+	switch v := v.(type) {
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+		return "x'" + hex.EncodeToString(v) + "'"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	dbCmd.AddCommand(dbStatsCmd)
+	dbCmd.AddCommand(dbVacuumCmd)
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbQueryCmd)
+	dbCmd.AddCommand(dbSchemaCmd)
+
+	dbMigrateCmd.Flags().Int("to", 0, "Schema version to move to (default: the latest)")
+	dbMigrateCmd.Flags().Bool("dry-run", false, "Run the migrations and roll them back")
+
+	dbQueryCmd.Flags().String("format", string(formatTable), "Output format: table, csv, or json")
+	dbQueryCmd.Flags().Bool("json", false, "Print the rows as JSON (same as --format json)")
+}
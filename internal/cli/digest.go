@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/mail"
+	"github.com/andy/timesink/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize last week: hours, unbilled time and unpaid invoices",
+	Long: `Summarize a week, last week by default: hours logged for each client
+against the week before, billable time not yet invoiced, and invoices
+awaiting payment.
+
+The digest is printed unless --send is given, which e-mails it through the
+server in the smtp section of config.yaml to digest.to (default user.email).
+Run it from cron to get it every Monday:
+
+  0 7 * * 1  timesink digest --send`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		now := time.Now()
+		weekStart := weekMonday(now).AddDate(0, 0, -7)
+		if cmd.Flags().Changed("date") {
+			t, err := parseDate(mustGetString(cmd, "date"))
+			if err != nil {
+				return fmt.Errorf("invalid date: %w", err)
+			}
+			weekStart = weekMonday(t)
+		}
+
+		all, err := appInstance.ClientRepo.List(ctx, true)
+		if err != nil {
+			return fmt.Errorf("failed to list clients: %w", err)
+		}
+		clients := make(map[int64]*domain.Client, len(all))
+		for _, c := range all {
+			clients[c.ID] = c
+		}
+
+		digest, err := appInstance.ReportService.GetDigest(ctx, weekStart, clients, appInstance.Config.Invoice.DefaultDueDays)
+		if err != nil {
+			return fmt.Errorf("failed to build digest: %w", err)
+		}
+
+		opts := render.Options{
+			Locale:     cliLocale(),
+			HourFormat: appInstance.Config.Invoice.HourFormat,
+			From:       appInstance.Config.User,
+			Date:       now,
+		}
+
+		send, _ := cmd.Flags().GetBool("send")
+		if !send {
+			return render.Digest(os.Stdout, digest, opts)
+		}
+
+		cfg := appInstance.Config
+		to := mustGetString(cmd, "to")
+		if to == "" {
+			to = firstNonEmpty(cfg.Digest.To, cfg.User.Email)
+		}
+		if to == "" {
+			return errors.New("no recipient: set digest.to or user.email, or pass --to")
+		}
+		from := firstNonEmpty(cfg.SMTP.From, cfg.User.Email)
+		if from == "" {
+			return errors.New("no sender: set smtp.from or user.email")
+		}
+
+		var body strings.Builder
+		if err := render.Digest(&body, digest, opts); err != nil {
+			return fmt.Errorf("failed to write digest: %w", err)
+		}
+		sender := mail.NewSender(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, from)
+		if err := sender.Send(to, render.DigestSubject(digest, opts), body.String()); err != nil {
+			return fmt.Errorf("failed to send digest: %w", err)
+		}
+
+		appInstance.Logger.Info("digest sent", "to", to, "week", digest.Start.Format("2006-01-02"))
+		fmt.Printf("✓ Digest for the week of %s sent to %s\n", formatDate(digest.Start), to)
+		return nil
+	},
+}
+
+// firstNonEmpty returns the first of values that is not blank
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func init() {
+	digestCmd.Flags().String("date", "", "Any day in the week to summarize (default last week)")
+	digestCmd.Flags().Bool("send", false, "E-mail the digest instead of printing it")
+	digestCmd.Flags().String("to", "", "Recipient for --send (default digest.to, then user.email)")
+}
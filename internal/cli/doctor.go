@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andy/timesink/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the database for inconsistent data",
+	Long: `Check the database for data that no longer adds up: line items that
+disagree with their entries, entries locked to missing or draft invoices,
+invoice totals that differ from their line items, history for removed
+entries, and entries that end before they start.
+
+With --fix, safe repairs are applied in a single transaction. Issued
+(finalized, sent, or paid) invoices are never changed; reopen them first.
+Exits with status 1 if any issue remains.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		doctor := repository.NewDoctor(appInstance.DB)
+		issues, err := doctor.Check(ctx)
+		if err != nil {
+			return err
+		}
+
+		if fix && len(issues) > 0 {
+			fixed, err := doctor.Fix(ctx, issues)
+			if err != nil {
+				return err
+			}
+			if fixed > 0 {
+				appInstance.Logger.Info("doctor repaired data", "fixed", fixed)
+				fmt.Printf("✓ Fixed %d issue(s)\n", fixed)
+			}
+
+			// Report whatever the repairs could not resolve
+			if issues, err = doctor.Check(ctx); err != nil {
+				return err
+			}
+		}
+
+		if len(issues) == 0 {
+			fmt.Println("✓ No issues found")
+			return nil
+		}
+
+		fixable := 0
+		for _, issue := range issues {
+			note := ""
+			if issue.Fixable {
+				fixable++
+				note = " (fixable)"
+			}
+			fmt.Printf("✗ %-18s %s: %s%s\n", issue.Check, issue.Subject, issue.Detail, note)
+		}
+
+		fmt.Printf("\n%d issue(s) found", len(issues))
+		if fixable > 0 {
+			fmt.Printf(", %d fixable with `timesink doctor --fix`", fixable)
+		}
+		fmt.Println()
+
+		exitCode = 1
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("fix", false, "Apply safe repairs")
+}
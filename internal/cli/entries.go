@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -48,55 +50,127 @@ var entriesListCmd = &cobra.Command{
 		}
 
 		includeLocked, _ := cmd.Flags().GetBool("include-locked")
+		showDeleted, _ := cmd.Flags().GetBool("deleted")
+		invoiceID, _ := cmd.Flags().GetInt64("invoice")
+		if invoiceID != 0 && (clientID != nil || start != nil || end != nil) {
+			return fmt.Errorf("--invoice lists every entry on the invoice and cannot be combined with --client, --start or --end")
+		}
 
-		entries, err := appInstance.EntryRepo.List(ctx, clientID, start, end, includeLocked)
+		var entries []*domain.TimeEntry
+		var err error
+		if invoiceID != 0 {
+			if _, err := appInstance.InvoiceService.GetInvoice(ctx, invoiceID); err != nil {
+				return fmt.Errorf("failed to get invoice: %w", err)
+			}
+			entries, err = appInstance.EntryRepo.ListByInvoice(ctx, invoiceID)
+		} else if showDeleted {
+			entries, err = appInstance.EntryRepo.ListDeleted(ctx, clientID)
+		} else {
+			entries, err = appInstance.EntryRepo.List(ctx, clientID, start, end, includeLocked)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to list entries: %w", err)
 		}
+		if cmd.Flags().Changed("location") {
+			location := mustGetString(cmd, "location")
+			matching := entries[:0]
+			for _, entry := range entries {
+				if entry.AtLocation(location) {
+					matching = append(matching, entry)
+				}
+			}
+			entries = matching
+		}
 
 		if len(entries) == 0 {
 			fmt.Println("No entries found")
 			return nil
 		}
 
+		contracts, err := appInstance.ContractsByClient(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load contracts: %w", err)
+		}
+
+		clientIDs := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			clientIDs = append(clientIDs, entry.ClientID)
+		}
+		clients, err := appInstance.ClientRepo.GetByIDs(ctx, clientIDs)
+		if err != nil {
+			return fmt.Errorf("failed to load clients: %w", err)
+		}
+		clientRates, err := appInstance.ClientRatesOn(ctx, entries, clients)
+		if err != nil {
+			return fmt.Errorf("failed to load client rates: %w", err)
+		}
+
 		// Print table header
-		fmt.Printf("%-5s %-15s %-20s %-10s %-12s %-8s\n", "ID", "Client", "Date", "Duration", "Amount", "Status")
-		fmt.Println("--------------------------------------------------------------------------------")
+		fmt.Printf("%-5s %-15s %-20s %-10s %-12s %-9s %-10s %s\n", "ID", "Client", "Date", "Duration", "Amount", "Approval", "Status", "Location")
+		fmt.Println("------------------------------------------------------------------------------------------")
 
 		var totalDuration time.Duration
-		var totalAmount float64
+		var totalAmount domain.Money
+		lapsed := 0
+		var otherRates []*domain.TimeEntry
 
 		// Print entries
 		for _, entry := range entries {
-			client, _ := appInstance.ClientRepo.GetByID(ctx, entry.ClientID)
 			clientName := fmt.Sprintf("Client #%d", entry.ClientID)
-			if client != nil {
+			if client, ok := clients[entry.ClientID]; ok {
 				clientName = client.Name
 			}
 
 			status := "Unbilled"
-			if entry.InvoiceID != nil {
+			if entry.IsDeleted {
+				status = "Deleted"
+			} else if entry.InvoiceID != nil {
 				status = "Invoiced"
 			}
+			if domain.ContractLapsed(contracts[entry.ClientID], entry.StartTime) {
+				status += " !"
+				lapsed++
+			}
 
 			duration := entry.Duration()
 			amount := entry.Amount()
+			amountText := formatMoney(amount.Float())
+			if rate, ok := clientRates[entry.ID]; ok && entry.RateDiffers(rate) {
+				amountText += " *"
+				otherRates = append(otherRates, entry)
+			}
 
-			fmt.Printf("%-5d %-15s %-20s %-10s $%-11.2f %-8s\n",
+			fmt.Printf("%-5d %-15s %-20s %-10s %-12s %-9s %-10s %s\n",
 				entry.ID,
 				truncate(clientName, 15),
-				entry.StartTime.Format("2006-01-02 15:04"),
+				formatDate(entry.StartTime)+entry.StartTime.Format(" 15:04"),
 				formatDuration(duration),
-				amount,
+				amountText,
+				entry.Approval,
 				status,
+				entry.Location,
 			)
 
 			totalDuration += duration
 			totalAmount += amount
 		}
 
-		fmt.Println("--------------------------------------------------------------------------------")
-		fmt.Printf("Total: %d entries, %s, $%.2f\n", len(entries), formatDuration(totalDuration), totalAmount)
+		fmt.Println("------------------------------------------------------------------------------------------")
+		fmt.Printf("Total: %d entries, %s, %s\n", len(entries), formatDuration(totalDuration), formatMoney(totalAmount.Float()))
+		if lapsed > 0 {
+			fmt.Printf("! %d entries logged after the client's contract ended\n", lapsed)
+		}
+		if len(otherRates) > 0 {
+			fmt.Printf("* %d entries billed at a rate other than the client's:\n", len(otherRates))
+			for _, entry := range otherRates {
+				reason := entry.RateReason
+				if reason == "" {
+					reason = "no reason given"
+				}
+				fmt.Printf("  %-5d %s/h instead of %s/h: %s\n",
+					entry.ID, formatMoney(entry.HourlyRate), formatMoney(clientRates[entry.ID]), reason)
+			}
+		}
 		return nil
 	},
 }
@@ -131,7 +205,7 @@ var entriesAddCmd = &cobra.Command{
 			description = args[3]
 		}
 
-		// Get rate (use flag or client's rate)
+		// Get rate (use flag or the client's rate on the entry's day)
 		client, err := appInstance.ClientRepo.GetByID(ctx, clientID)
 		if err != nil {
 			return fmt.Errorf("failed to get client: %w", err)
@@ -140,14 +214,38 @@ var entriesAddCmd = &cobra.Command{
 			return fmt.Errorf("client not found")
 		}
 
-		rate := client.HourlyRate
+		clientRate, err := appInstance.ClientRepo.RateAt(ctx, clientID, startTime)
+		if err != nil {
+			return fmt.Errorf("failed to get client rate: %w", err)
+		}
+		// A role bills at its rate from the client's rate card instead
+		role := ""
+		if cmd.Flags().Changed("role") {
+			roleRate, err := service.FindRoleRate(ctx, appInstance.ClientRepo, client, mustGetString(cmd, "role"))
+			if err != nil {
+				return err
+			}
+			role, clientRate = roleRate.Role, roleRate.HourlyRate
+		}
+		rate := clientRate
 		if cmd.Flags().Changed("rate") {
 			rate, _ = cmd.Flags().GetFloat64("rate")
 		}
 
 		// Create entry
 		entry := domain.NewTimeEntry(clientID, description, rate)
+		if err := entry.SetRate(rate, clientRate, mustGetString(cmd, "rate-reason")); err != nil {
+			return fmt.Errorf("%w with --rate-reason", err)
+		}
+		entry.Role = role
 		entry.StartTime = startTime
+		if nonBillable, _ := cmd.Flags().GetBool("non-billable"); nonBillable {
+			entry.IsBillable = false
+		}
+		entry.Location = appInstance.Location()
+		if cmd.Flags().Changed("location") {
+			entry.Location = strings.TrimSpace(mustGetString(cmd, "location"))
+		}
 		entry.Stop(endTime)
 
 		if err := entry.Validate(); err != nil {
@@ -162,7 +260,16 @@ var entriesAddCmd = &cobra.Command{
 		fmt.Printf("✓ Time entry created (ID: %d)\n", entry.ID)
 		fmt.Printf("  Client: %s\n", client.Name)
 		fmt.Printf("  Duration: %s\n", formatDuration(duration))
-		fmt.Printf("  Amount: $%.2f\n", entry.Amount())
+		fmt.Printf("  Amount: %s\n", formatMoney(entry.Amount().Float()))
+		if entry.Role != "" {
+			fmt.Printf("  Role: %s at %s/h\n", entry.Role, formatMoney(clientRate))
+		}
+		if entry.RateReason != "" {
+			fmt.Printf("  Rate: %s/h instead of %s/h (%s)\n", formatMoney(entry.HourlyRate), formatMoney(clientRate), entry.RateReason)
+		}
+		if entry.Location != "" {
+			fmt.Printf("  Location: %s\n", entry.Location)
+		}
 
 		return nil
 	},
@@ -198,6 +305,9 @@ var entriesEditCmd = &cobra.Command{
 			description, _ := cmd.Flags().GetString("description")
 			entry.Description = description
 		}
+		if cmd.Flags().Changed("location") {
+			entry.Location = strings.TrimSpace(mustGetString(cmd, "location"))
+		}
 
 		reason, _ := cmd.Flags().GetString("reason")
 		if reason == "" {
@@ -243,6 +353,236 @@ var entriesDeleteCmd = &cobra.Command{
 	},
 }
 
+var entriesRestoreCmd = &cobra.Command{
+	Use:   "restore [id]",
+	Short: "Restore a deleted time entry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid entry ID: %w", err)
+		}
+
+		reason, _ := cmd.Flags().GetString("reason")
+		if reason == "" {
+			return fmt.Errorf("--reason flag is required for restoring entries")
+		}
+
+		if err := appInstance.EntryRepo.Restore(ctx, id, reason); err != nil {
+			return fmt.Errorf("failed to restore entry: %w", err)
+		}
+
+		fmt.Printf("✓ Entry restored (ID: %d)\n", id)
+		return nil
+	},
+}
+
+var entriesMarkApprovedCmd = entriesApprovalCmd("mark-approved", domain.ApprovalApproved,
+	"Mark entries as approved by the client")
+var entriesMarkRejectedCmd = entriesApprovalCmd("mark-rejected", domain.ApprovalRejected,
+	"Mark entries as rejected by the client")
+var entriesMarkPendingCmd = entriesApprovalCmd("mark-pending", domain.ApprovalPending,
+	"Mark entries as awaiting the client's approval")
+
+// entriesApprovalCmd builds a command that sets the approval status of the
+// given entries, or of a client's unbilled entries in a date range
+func entriesApprovalCmd(use string, status domain.ApprovalStatus, short string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " [id...]",
+		Short: short,
+		Long: short + `.
+
+Pass entry IDs, or --client with an optional --start and --end to change all
+of the client's unbilled entries in that range. Invoiced entries cannot change.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+
+			var ids []int64
+			for _, arg := range args {
+				id, err := strconv.ParseInt(arg, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid entry ID: %w", err)
+				}
+				ids = append(ids, id)
+			}
+
+			if cmd.Flags().Changed("client") {
+				if len(ids) > 0 {
+					return fmt.Errorf("pass entry IDs or --client, not both")
+				}
+				clientArg, _ := cmd.Flags().GetString("client")
+				clientID, err := resolveClientID(ctx, clientArg)
+				if err != nil {
+					return err
+				}
+
+				var start, end *time.Time
+				if value, _ := cmd.Flags().GetString("start"); value != "" {
+					t, err := parseDate(value)
+					if err != nil {
+						return fmt.Errorf("invalid start date: %w", err)
+					}
+					start = &t
+				}
+				if value, _ := cmd.Flags().GetString("end"); value != "" {
+					t, err := parseDate(value)
+					if err != nil {
+						return fmt.Errorf("invalid end date: %w", err)
+					}
+					end = &t
+				}
+
+				entries, err := appInstance.EntryRepo.List(ctx, &clientID, start, end, false)
+				if err != nil {
+					return fmt.Errorf("failed to list entries: %w", err)
+				}
+				for _, entry := range entries {
+					if !entry.IsRunning() && entry.Approval != status {
+						ids = append(ids, entry.ID)
+					}
+				}
+			} else if len(ids) == 0 {
+				return fmt.Errorf("pass entry IDs or --client")
+			}
+
+			if len(ids) == 0 {
+				fmt.Println("No entries to change")
+				return nil
+			}
+
+			note, _ := cmd.Flags().GetString("note")
+			if err := appInstance.EntryRepo.SetApproval(ctx, ids, status, note); err != nil {
+				return fmt.Errorf("failed to update entries: %w", err)
+			}
+
+			fmt.Printf("✓ %d entries marked %s\n", len(ids), status)
+			return nil
+		},
+	}
+	cmd.Flags().String("client", "", "Change the unbilled entries of this client ID or name")
+	cmd.Flags().String("start", "", "With --client, only entries from this date (YYYY-MM-DD or 'today')")
+	cmd.Flags().String("end", "", "With --client, only entries up to this date (YYYY-MM-DD or 'today')")
+	cmd.Flags().String("note", "", "Note from the client, e.g. why an entry was rejected")
+	return cmd
+}
+
+var entriesPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove old deleted entries",
+	Long: `Permanently remove soft-deleted time entries and their edit history.
+
+Only entries deleted longer ago than --older-than are removed. Entries that
+appear on an invoice are always kept.
+
+Examples:
+  timesink entries purge --deleted --older-than 1y
+  timesink entries purge --deleted --older-than 90d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		deleted, _ := cmd.Flags().GetBool("deleted")
+		if !deleted {
+			return fmt.Errorf("--deleted flag is required (only deleted entries can be purged)")
+		}
+
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		cutoff, err := parseAge(olderThan, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+
+		candidates, err := appInstance.EntryRepo.ListDeleted(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to list deleted entries: %w", err)
+		}
+
+		count := 0
+		for _, entry := range candidates {
+			if entry.UpdatedAt.Before(cutoff) && entry.InvoiceID == nil {
+				count++
+			}
+		}
+
+		if count == 0 {
+			fmt.Printf("No deleted entries older than %s\n", formatDate(cutoff))
+			return nil
+		}
+
+		msg := fmt.Sprintf("This will permanently remove %d deleted entries (deleted before %s) and their history. Continue?",
+			count, formatDate(cutoff))
+		if !confirmPrompt(msg) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		purged, err := appInstance.EntryRepo.PurgeDeleted(ctx, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to purge entries: %w", err)
+		}
+
+		fmt.Printf("✓ Purged %d deleted entries\n", purged)
+		return nil
+	},
+}
+
+var entriesSplitCmd = &cobra.Command{
+	Use:   "split [id]",
+	Short: "Split a time entry into two at a given time",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid entry ID: %w", err)
+		}
+
+		entry, err := appInstance.EntryRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get entry: %w", err)
+		}
+		if entry == nil {
+			return fmt.Errorf("entry not found")
+		}
+
+		if entry.IsLocked() {
+			return fmt.Errorf("cannot split entry: already invoiced")
+		}
+
+		atStr, _ := cmd.Flags().GetString("at")
+		if atStr == "" {
+			return fmt.Errorf("--at flag is required for splitting entries")
+		}
+
+		at, err := parseSplitTime(atStr, entry.StartTime)
+		if err != nil {
+			return fmt.Errorf("invalid split time: %w", err)
+		}
+
+		reason, _ := cmd.Flags().GetString("reason")
+		if reason == "" {
+			reason = fmt.Sprintf("split at %s", at.Format("15:04"))
+		}
+
+		second, err := appInstance.EntryRepo.Split(ctx, id, at, reason)
+		if err != nil {
+			return fmt.Errorf("failed to split entry: %w", err)
+		}
+
+		fmt.Printf("✓ Entry split at %s\n", at.Format("2006-01-02 15:04"))
+		fmt.Printf("  #%d: %s - %s (%s)\n", entry.ID,
+			entry.StartTime.Format("15:04"), at.Format("15:04"),
+			formatDuration(at.Sub(entry.StartTime)))
+		fmt.Printf("  #%d: %s - %s (%s)\n", second.ID,
+			second.StartTime.Format("15:04"), second.EndTime.Format("15:04"),
+			formatDuration(second.Duration()))
+
+		return nil
+	},
+}
+
 var entriesHistoryCmd = &cobra.Command{
 	Use:   "history [id]",
 	Short: "Show edit history for an entry",
@@ -283,35 +623,64 @@ func init() {
 	entriesCmd.AddCommand(entriesAddCmd)
 	entriesCmd.AddCommand(entriesEditCmd)
 	entriesCmd.AddCommand(entriesDeleteCmd)
+	entriesCmd.AddCommand(entriesRestoreCmd)
+	entriesCmd.AddCommand(entriesPurgeCmd)
+	entriesCmd.AddCommand(entriesSplitCmd)
 	entriesCmd.AddCommand(entriesHistoryCmd)
+	entriesCmd.AddCommand(entriesMarkApprovedCmd)
+	entriesCmd.AddCommand(entriesMarkRejectedCmd)
+	entriesCmd.AddCommand(entriesMarkPendingCmd)
 
 	// List flags
 	entriesListCmd.Flags().Int64("client", 0, "Filter by client ID")
 	entriesListCmd.Flags().String("start", "", "Filter by start date (YYYY-MM-DD or 'today')")
 	entriesListCmd.Flags().String("end", "", "Filter by end date (YYYY-MM-DD or 'today')")
 	entriesListCmd.Flags().Bool("include-locked", false, "Include invoiced entries")
+	entriesListCmd.Flags().Bool("deleted", false, "Show deleted entries instead")
+	entriesListCmd.Flags().String("location", "", "Only entries recorded at this location, ignoring case (\"\" for none)")
+	entriesListCmd.Flags().Int64("invoice", 0, "Only the entries billed on this invoice ID")
+	entriesListCmd.MarkFlagsMutuallyExclusive("invoice", "deleted")
 
 	// Add flags
 	entriesAddCmd.Flags().Float64("rate", 0, "Override hourly rate")
+	entriesAddCmd.Flags().String("rate-reason", "", "Why the entry is billed at a rate other than the client's (required with a different --rate)")
+	entriesAddCmd.Flags().Bool("non-billable", false, "Record as non-billable time")
+	entriesAddCmd.Flags().String("location", "", "Where the work was done, e.g. on-site (defaults to timer.location)")
+	entriesAddCmd.Flags().String("role", "", "Bill at this role's rate from the client's rate card")
 
 	// Edit flags
 	entriesEditCmd.Flags().String("description", "", "New description")
+	entriesEditCmd.Flags().String("location", "", "Where the work was done (\"\" to clear)")
 	entriesEditCmd.Flags().String("reason", "", "Reason for edit (required)")
 
 	// Delete flags
 	entriesDeleteCmd.Flags().String("reason", "", "Reason for deletion (required)")
+
+	// Restore flags
+	entriesRestoreCmd.Flags().String("reason", "", "Reason for restore (required)")
+
+	// Purge flags
+	entriesPurgeCmd.Flags().Bool("deleted", false, "Purge soft-deleted entries (required)")
+	entriesPurgeCmd.Flags().String("older-than", "1y", "Only purge entries deleted longer ago than this (e.g. 90d, 6m, 1y)")
+
+	// Split flags
+	entriesSplitCmd.Flags().String("at", "", "Split time (HH:MM on the entry's day, or YYYY-MM-DD HH:MM)")
+	entriesSplitCmd.Flags().String("reason", "", "Reason for split")
 }
 
-// parseDate parses a date string in various formats
+// parseDate parses a date string in various formats, in the local timezone
 func parseDate(s string) (time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
 	switch s {
 	case "today":
-		return time.Now().Truncate(24 * time.Hour), nil
+		return today, nil
 	case "yesterday":
-		return time.Now().Add(-24 * time.Hour).Truncate(24 * time.Hour), nil
+		return today.AddDate(0, 0, -1), nil
 	default:
 		// Try YYYY-MM-DD format
-		t, err := time.Parse("2006-01-02", s)
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("expected format: YYYY-MM-DD, 'today', or 'yesterday'")
 		}
@@ -319,27 +688,63 @@ func parseDate(s string) (time.Time, error) {
 	}
 }
 
-// parseDateTime parses a datetime string in various formats
+// parseDateTime parses a datetime string in various formats, in the local timezone
 func parseDateTime(s string) (time.Time, error) {
 	// Try ISO format with time
-	if t, err := time.Parse("2006-01-02T15:04:05", s); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02T15:04:05", s, time.Local); err == nil {
 		return t, nil
 	}
 
 	// Try date + space + time
-	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.Local); err == nil {
 		return t, nil
 	}
 
 	// Try date + space + time (no seconds)
-	if t, err := time.Parse("2006-01-02 15:04", s); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local); err == nil {
 		return t, nil
 	}
 
 	// Try just date (assume midnight)
-	if t, err := time.Parse("2006-01-02", s); err == nil {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
 		return t, nil
 	}
 
 	return time.Time{}, fmt.Errorf("expected format: YYYY-MM-DD or YYYY-MM-DD HH:MM:SS")
 }
+
+// parseSplitTime parses a split point. A bare HH:MM is taken to be on the same
+// day as the entry's start; anything else goes through parseDateTime.
+func parseSplitTime(s string, day time.Time) (time.Time, error) {
+	if t, err := time.Parse("15:04", s); err == nil {
+		return time.Date(day.Year(), day.Month(), day.Day(),
+			t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+	}
+	return parseDateTime(s)
+}
+
+// parseAge parses a relative age such as "30d", "2w", "6m" or "1y" and returns
+// the point in time that far before now
+func parseAge(s string, now time.Time) (time.Time, error) {
+	if len(s) < 2 {
+		return time.Time{}, fmt.Errorf("expected format: <n>d, <n>w, <n>m, or <n>y")
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("expected format: <n>d, <n>w, <n>m, or <n>y")
+	}
+
+	switch s[len(s)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("expected format: <n>d, <n>w, <n>m, or <n>y")
+	}
+}
@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var estimatesCmd = &cobra.Command{
+	Use:   "estimates",
+	Short: "Manage estimates",
+	Long: `Create estimates (quotes) for clients, record whether they were accepted,
+and convert accepted estimates into draft invoices.`,
+}
+
+var estimatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List estimates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var clientID *int64
+		if cmd.Flags().Changed("client") {
+			id, err := resolveClientID(ctx, mustGetString(cmd, "client"))
+			if err != nil {
+				return fmt.Errorf("failed to resolve client: %w", err)
+			}
+			clientID = &id
+		}
+
+		var status *domain.EstimateStatus
+		if cmd.Flags().Changed("status") {
+			s := domain.EstimateStatus(mustGetString(cmd, "status"))
+			status = &s
+		}
+
+		estimates, err := appInstance.EstimateService.ListEstimates(ctx, clientID, status)
+		if err != nil {
+			return fmt.Errorf("failed to list estimates: %w", err)
+		}
+
+		if len(estimates) == 0 {
+			fmt.Println("No estimates found")
+			return nil
+		}
+
+		fmt.Printf("%-5s %-15s %-20s %-14s %-12s %-10s %s\n", "ID", "Number", "Client", "Date", "Total", "Status", "Invoice")
+		fmt.Println("--------------------------------------------------------------------------------------------")
+
+		now := time.Now()
+		for _, estimate := range estimates {
+			client, _ := appInstance.ClientRepo.GetByID(ctx, estimate.ClientID)
+			clientName := fmt.Sprintf("Client #%d", estimate.ClientID)
+			if client != nil {
+				clientName = client.Name
+			}
+
+			status := string(estimate.Status)
+			if estimate.IsExpired(now) {
+				status += " (expired)"
+			}
+			invoice := ""
+			if estimate.InvoiceID != nil {
+				invoice = fmt.Sprintf("#%d", *estimate.InvoiceID)
+			}
+
+			fmt.Printf("%-5d %-15s %-20s %-14s %-12s %-10s %s\n",
+				estimate.ID,
+				estimate.EstimateNumber,
+				truncate(clientName, 20),
+				formatDate(estimate.IssueDate),
+				formatMoney(estimate.Total.Float()),
+				status,
+				invoice,
+			)
+		}
+
+		fmt.Printf("\nTotal: %d estimate(s)\n", len(estimates))
+		return nil
+	},
+}
+
+var estimatesCreateCmd = &cobra.Command{
+	Use:   "create [client_id_or_name]",
+	Short: "Create a new draft estimate",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		issueDate := time.Now()
+		if dateStr := mustGetString(cmd, "date"); dateStr != "" {
+			if issueDate, err = parseDate(dateStr); err != nil {
+				return fmt.Errorf("invalid date: %w", err)
+			}
+		}
+
+		validDays, _ := cmd.Flags().GetInt("valid-days")
+		if !cmd.Flags().Changed("valid-days") {
+			validDays = appInstance.Config.Invoice.EstimateValidDays
+		}
+
+		notes := mustGetString(cmd, "notes")
+		estimate, err := appInstance.EstimateService.CreateDraft(ctx, clientID, issueDate, validDays,
+			appInstance.Config.Invoice.EstimatePrefix, notes)
+		if err != nil {
+			return fmt.Errorf("failed to create estimate: %w", err)
+		}
+
+		fmt.Printf("✓ Draft estimate created: %s (#%d)\n", estimate.EstimateNumber, estimate.ID)
+		if estimate.ValidUntil != nil {
+			fmt.Printf("  Valid until: %s\n", formatDate(*estimate.ValidUntil))
+		}
+		fmt.Printf("  Add line items with `timesink estimates add-item %d <description> --hours <h>`\n", estimate.ID)
+		return nil
+	},
+}
+
+var estimatesAddItemCmd = &cobra.Command{
+	Use:   "add-item [estimate_id] [description]",
+	Short: "Add a line item to a draft estimate",
+	Long: `Add projected hours with --hours, priced at --rate or the client's rate on
+the estimate's date, or a fixed-price item with --amount.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid estimate ID: %w", err)
+		}
+
+		hasHours, hasAmount := cmd.Flags().Changed("hours"), cmd.Flags().Changed("amount")
+		if hasHours == hasAmount {
+			return fmt.Errorf("give either --hours or --amount")
+		}
+
+		var item *domain.EstimateLineItem
+		if hasHours {
+			hours, _ := cmd.Flags().GetFloat64("hours")
+			rate, _ := cmd.Flags().GetFloat64("rate")
+			if hours <= 0 {
+				return fmt.Errorf("hours must be positive")
+			}
+			item = domain.NewProjectedLineItem(args[1], hours, rate)
+		} else {
+			amount, _ := cmd.Flags().GetFloat64("amount")
+			item = domain.NewFixedLineItem(args[1], domain.MoneyFromFloat(amount))
+		}
+
+		if err := appInstance.EstimateService.AddLineItem(ctx, id, item); err != nil {
+			return fmt.Errorf("failed to add line item: %w", err)
+		}
+
+		fmt.Printf("✓ Added line item #%d: %s\n", item.ID, formatMoney(item.Amount.Float()))
+		if estimate, err := appInstance.EstimateService.GetEstimate(ctx, id); err == nil {
+			fmt.Printf("  Estimate total: %s\n", formatMoney(estimate.Total.Float()))
+		}
+		return nil
+	},
+}
+
+var estimatesRemoveItemCmd = &cobra.Command{
+	Use:   "remove-item [estimate_id] [item_id]",
+	Short: "Remove a line item from a draft estimate",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid estimate ID: %w", err)
+		}
+		itemID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid line item ID: %w", err)
+		}
+
+		if err := appInstance.EstimateService.RemoveLineItem(ctx, id, itemID); err != nil {
+			return fmt.Errorf("failed to remove line item: %w", err)
+		}
+
+		fmt.Printf("✓ Removed line item %d from estimate %d\n", itemID, id)
+		return nil
+	},
+}
+
+var estimatesShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show estimate details",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		estimate, err := loadEstimate(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		writeEstimate(os.Stdout, estimate)
+		return nil
+	},
+}
+
+var estimatesExportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Render an estimate as a document for the client",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		estimate, err := loadEstimate(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		opts := render.Options{
+			Locale:     cliLocale(),
+			HourFormat: appInstance.Config.Invoice.HourFormat,
+			From:       appInstance.Config.User,
+		}
+
+		output := mustGetString(cmd, "output")
+		if output == "" {
+			return render.Estimate(os.Stdout, estimate, estimate.LineItems, opts)
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+
+		if err := render.Estimate(f, estimate, estimate.LineItems, opts); err != nil {
+			return fmt.Errorf("failed to write estimate: %w", err)
+		}
+
+		fmt.Printf("✓ Estimate %s written to %s\n", estimate.EstimateNumber, output)
+		return nil
+	},
+}
+
+// estimateStatusCmd builds a command that moves an estimate to a status
+func estimateStatusCmd(use, short, done string, change func(ctx context.Context, id int64) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " [id]",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid estimate ID: %w", err)
+			}
+
+			if err := change(context.Background(), id); err != nil {
+				return fmt.Errorf("failed to update estimate: %w", err)
+			}
+
+			fmt.Printf("✓ Estimate #%d %s\n", id, done)
+			return nil
+		},
+	}
+}
+
+var estimatesConvertCmd = &cobra.Command{
+	Use:   "convert [id]",
+	Short: "Copy an accepted estimate's line items into a new draft invoice",
+	Long: `Convert creates a draft invoice with the accepted estimate's line items,
+the client's tax lines and the configured notes and payment instructions.
+Add time entries or finalize it like any other draft. Each estimate converts
+once.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid estimate ID: %w", err)
+		}
+
+		estimate, err := appInstance.EstimateService.GetEstimate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get estimate: %w", err)
+		}
+		client, err := appInstance.ClientRepo.GetByID(ctx, estimate.ClientID)
+		if err != nil {
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+
+		prefix := mustGetString(cmd, "prefix")
+		if prefix == "" {
+			prefix = appInstance.Config.Invoice.NumberPrefix
+		}
+
+		invoice, err := appInstance.EstimateService.ConvertToInvoice(ctx, id, prefix, invoiceTaxes(cmd, client), invoiceFooter(cmd))
+		if err != nil {
+			return fmt.Errorf("failed to convert estimate: %w", err)
+		}
+
+		fmt.Printf("✓ Estimate %s converted to draft invoice %s (#%d)\n", estimate.EstimateNumber, invoice.InvoiceNumber, invoice.ID)
+		fmt.Printf("  Total: %s\n", formatMoney(invoice.Total.Float()))
+		fmt.Printf("  Finalize it with `timesink invoices finalize %d`\n", invoice.ID)
+		return nil
+	},
+}
+
+// loadEstimate parses an estimate ID and loads it with its line items and client
+func loadEstimate(ctx context.Context, arg string) (*domain.Estimate, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid estimate ID: %w", err)
+	}
+
+	estimate, err := appInstance.EstimateService.GetEstimate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+	estimate.Client, _ = appInstance.ClientRepo.GetByID(ctx, estimate.ClientID)
+	return estimate, nil
+}
+
+// writeEstimate prints an estimate with its line item IDs
+func writeEstimate(w io.Writer, estimate *domain.Estimate) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Estimate: %s\n", estimate.EstimateNumber)
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	clientName := fmt.Sprintf("Client #%d", estimate.ClientID)
+	if estimate.Client != nil {
+		clientName = estimate.Client.Name
+	}
+	fmt.Fprintf(w, "Client: %s\n", clientName)
+	fmt.Fprintf(w, "Date:   %s\n", formatDate(estimate.IssueDate))
+	if estimate.ValidUntil != nil {
+		fmt.Fprintf(w, "Valid:  until %s\n", formatDate(*estimate.ValidUntil))
+	}
+	fmt.Fprintf(w, "Status: %s\n", estimate.Status)
+	if estimate.InvoiceID != nil {
+		fmt.Fprintf(w, "Invoice: #%d\n", *estimate.InvoiceID)
+	}
+	fmt.Fprintln(w)
+
+	if len(estimate.LineItems) > 0 {
+		fmt.Fprintln(w, "Line Items:")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		fmt.Fprintf(w, "%-5s %-40s %8s %9s %10s\n", "ID", "Description", "Hours", "Rate", "Amount")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+
+		for _, item := range estimate.LineItems {
+			hours, rate := "", ""
+			if !item.IsFixed() {
+				hours, rate = formatInvoiceHours(item.Hours), formatMoney(item.Rate)
+			}
+			fmt.Fprintf(w, "%-5d %-40s %8s %9s %10s\n",
+				item.ID,
+				truncate(item.Description, 40),
+				hours,
+				rate,
+				formatMoney(item.Amount.Float()),
+			)
+		}
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+	}
+
+	fmt.Fprintf(w, "\nTotal: %s (before tax)\n", formatMoney(estimate.Total.Float()))
+	if text := strings.TrimSpace(estimate.Notes); text != "" {
+		fmt.Fprintf(w, "\nNotes:\n%s\n", indentOrNone(text))
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+}
+
+// mustGetString returns a string flag; the flag is always defined
+func mustGetString(cmd *cobra.Command, name string) string {
+	s, _ := cmd.Flags().GetString(name)
+	return s
+}
+
+func init() {
+	estimatesCmd.AddCommand(estimatesListCmd)
+	estimatesCmd.AddCommand(estimatesCreateCmd)
+	estimatesCmd.AddCommand(estimatesAddItemCmd)
+	estimatesCmd.AddCommand(estimatesRemoveItemCmd)
+	estimatesCmd.AddCommand(estimatesShowCmd)
+	estimatesCmd.AddCommand(estimatesExportCmd)
+	estimatesCmd.AddCommand(estimateStatusCmd("mark-sent", "Mark a draft estimate as sent", "marked as sent",
+		func(ctx context.Context, id int64) error { return appInstance.EstimateService.MarkSent(ctx, id) }))
+	estimatesCmd.AddCommand(estimateStatusCmd("accept", "Record that the client accepted an estimate", "accepted",
+		func(ctx context.Context, id int64) error { return appInstance.EstimateService.Accept(ctx, id) }))
+	estimatesCmd.AddCommand(estimateStatusCmd("decline", "Record that the client declined an estimate", "declined",
+		func(ctx context.Context, id int64) error { return appInstance.EstimateService.Decline(ctx, id) }))
+	estimatesCmd.AddCommand(estimatesConvertCmd)
+
+	// List flags
+	estimatesListCmd.Flags().String("client", "", "Filter by client ID or name")
+	estimatesListCmd.Flags().String("status", "", "Filter by status (draft, sent, accepted, declined)")
+
+	// Create flags
+	estimatesCreateCmd.Flags().String("date", "", "Issue date (default: today)")
+	estimatesCreateCmd.Flags().Int("valid-days", 0, "Days the estimate is valid for (defaults to invoice.estimate_valid_days)")
+	estimatesCreateCmd.Flags().String("notes", "", "Notes printed at the bottom of the estimate")
+
+	// Line item flags
+	estimatesAddItemCmd.Flags().Float64("hours", 0, "Projected hours")
+	estimatesAddItemCmd.Flags().Float64("rate", 0, "Hourly rate for projected hours (defaults to the client's rate)")
+	estimatesAddItemCmd.Flags().Float64("amount", 0, "Fixed amount")
+
+	// Export flags
+	estimatesExportCmd.Flags().StringP("output", "o", "", "Write the estimate to a file instead of stdout")
+
+	// Convert flags
+	estimatesConvertCmd.Flags().String("prefix", "", "Invoice number prefix (defaults to invoice.number_prefix)")
+	estimatesConvertCmd.Flags().Float64("tax", 0, "Single tax rate (defaults to invoice.taxes or invoice.default_tax_rate)")
+	addFooterFlags(estimatesConvertCmd)
+}
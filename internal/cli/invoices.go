@@ -1,13 +1,22 @@
 package cli
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/andy/timesink/internal/config"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/render"
+	"github.com/andy/timesink/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -60,16 +69,16 @@ var invoicesListCmd = &cobra.Command{
 			}
 
 			period := fmt.Sprintf("%s - %s",
-				invoice.PeriodStart.Format("2006-01-02"),
-				invoice.PeriodEnd.Format("2006-01-02"),
+				cliLocale().FormatShortDate(invoice.PeriodStart),
+				formatDate(invoice.PeriodEnd),
 			)
 
-			fmt.Printf("%-5d %-15s %-20s %-20s $%-11.2f %-12s\n",
+			fmt.Printf("%-5d %-15s %-20s %-20s %-12s %-12s\n",
 				invoice.ID,
 				invoice.InvoiceNumber,
 				truncate(clientName, 20),
 				truncate(period, 20),
-				invoice.Total,
+				formatMoney(invoice.Total.Float()),
 				invoice.Status,
 			)
 		}
@@ -79,6 +88,66 @@ var invoicesListCmd = &cobra.Command{
 	},
 }
 
+var invoicesSuggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "List clients due an invoice",
+	Long: `List the clients with billable time not yet invoiced that is worth billing
+now: it adds up to invoice.remind_unbilled or more, or the client was last
+invoiced invoice.remind_after_days ago or longer (counted from their oldest
+unbilled entry if they never were). The dashboard shows the same list.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		cfg := appInstance.Config.Invoice
+		minUnbilled := cfg.RemindUnbilled
+		if cmd.Flags().Changed("min-unbilled") {
+			minUnbilled, _ = cmd.Flags().GetFloat64("min-unbilled")
+		}
+		days := cfg.RemindAfterDays
+		if cmd.Flags().Changed("days") {
+			days, _ = cmd.Flags().GetInt("days")
+		}
+		if minUnbilled < 0 || days < 0 {
+			return fmt.Errorf("--min-unbilled and --days must be 0 (off) or more")
+		}
+
+		reminders, err := appInstance.ReportService.GetBillingReminders(ctx, time.Now(), domain.MoneyFromFloat(minUnbilled), days)
+		if err != nil {
+			return fmt.Errorf("failed to find clients to invoice: %w", err)
+		}
+		if len(reminders) == 0 {
+			fmt.Println("No clients are due an invoice")
+			return nil
+		}
+
+		fmt.Printf("%-20s %12s  %-14s %6s  %s\n", "Client", "Unbilled", "Last Invoiced", "Days", "Why")
+		fmt.Println("--------------------------------------------------------------------------------")
+		for _, r := range reminders {
+			last := "never"
+			if r.LastInvoiced != nil {
+				last = formatDate(*r.LastInvoiced)
+			}
+			var why []string
+			if r.OverAmount {
+				why = append(why, "over "+formatMoney(minUnbilled))
+			}
+			if r.OverDays {
+				why = append(why, fmt.Sprintf("%d+ days", days))
+			}
+			fmt.Printf("%-20s %12s  %-14s %6d  %s\n",
+				truncate(clientName(ctx, r.ClientID), 20),
+				formatMoney(r.Unbilled.Float()),
+				last,
+				r.Days,
+				strings.Join(why, ", "),
+			)
+		}
+
+		fmt.Printf("\n%d client(s) to invoice\n", len(reminders))
+		return nil
+	},
+}
+
 var invoicesCreateCmd = &cobra.Command{
 	Use:   "create [client_id_or_name]",
 	Short: "Create a new draft invoice",
@@ -113,7 +182,7 @@ var invoicesCreateCmd = &cobra.Command{
 		}
 
 		// Create invoice
-		invoice, err := appInstance.InvoiceService.CreateDraft(ctx, clientID, start, end, prefix)
+		invoice, err := appInstance.InvoiceService.CreateDraft(ctx, clientID, start, end, prefix, invoiceFooter(cmd))
 		if err != nil {
 			return fmt.Errorf("failed to create invoice: %w", err)
 		}
@@ -124,17 +193,125 @@ var invoicesCreateCmd = &cobra.Command{
 			clientName = client.Name
 		}
 
+		due, err := dueDateFromFlags(cmd, invoice)
+		if err != nil {
+			return err
+		}
+		if due != nil {
+			if err := appInstance.InvoiceService.SetDueDate(ctx, invoice.ID, *due, ""); err != nil {
+				return fmt.Errorf("failed to set due date: %w", err)
+			}
+		}
+
 		fmt.Printf("✓ Draft invoice created: %s\n", invoice.InvoiceNumber)
 		fmt.Printf("  Client: %s\n", clientName)
 		fmt.Printf("  Period: %s to %s\n",
-			invoice.PeriodStart.Format("2006-01-02"),
-			invoice.PeriodEnd.Format("2006-01-02"),
+			formatDate(invoice.PeriodStart),
+			formatDate(invoice.PeriodEnd),
 		)
+		if due != nil {
+			fmt.Printf("  Due: %s\n", formatDate(*due))
+		}
+
+		// The draft starts empty; check the time it was created to bill
+		if appInstance.Config.Invoice.LintEntries {
+			entries, err := appInstance.EntryRepo.GetUnbilledByClient(ctx, clientID, start, end)
+			if err != nil {
+				return fmt.Errorf("failed to list unbilled entries: %w", err)
+			}
+			warnEntryLint(entries)
+		}
 
 		return nil
 	},
 }
 
+var invoicesGenerateAllCmd = &cobra.Command{
+	Use:   "generate-all",
+	Short: "Create a draft invoice for every client with unbilled time in a period",
+	Long: `Create a draft invoice for every active client with unbilled time in the
+period, then print what was drafted. Each client's draft is created on its
+own, so one failing does not stop the rest. Review the drafts, then finalize
+them one by one.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		periodStr, _ := cmd.Flags().GetString("period")
+		start, end, err := parsePeriod(periodStr, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid period: %w", err)
+		}
+
+		prefix, _ := cmd.Flags().GetString("prefix")
+		if prefix == "" {
+			prefix = appInstance.Config.Invoice.NumberPrefix
+		}
+
+		taxes := func(client *domain.Client) []*domain.InvoiceTax {
+			return invoiceTaxes(cmd, client)
+		}
+		results, err := appInstance.InvoiceService.DraftAll(ctx, start, end, prefix, taxes, invoiceFooter(cmd))
+		if err != nil {
+			return fmt.Errorf("failed to create invoices: %w", err)
+		}
+		if len(results) == 0 {
+			fmt.Printf("No unbilled time from %s to %s\n", formatDate(start), formatDate(end))
+			return nil
+		}
+
+		fmt.Printf("Period: %s to %s\n\n", formatDate(start), formatDate(end))
+		fmt.Printf("%-20s %-16s %8s %12s  %s\n", "Client", "Invoice", "Entries", "Total", "Notes")
+		fmt.Println("--------------------------------------------------------------------------------")
+
+		var drafted, failed int
+		var total domain.Money
+		for _, r := range results {
+			number, amount := "-", "-"
+			var notes []string
+			if r.Invoice != nil {
+				drafted++
+				total += r.Invoice.Total
+				number = r.Invoice.InvoiceNumber
+				amount = formatMoney(r.Invoice.Total.Float())
+			}
+			if r.Err != nil {
+				failed++
+				notes = append(notes, "failed: "+r.Err.Error())
+			}
+			if r.Held > 0 {
+				notes = append(notes, fmt.Sprintf("%d awaiting approval", r.Held))
+			}
+			if r.Invoice != nil && appInstance.Config.Invoice.LintEntries {
+				items, err := appInstance.InvoiceRepo.GetLineItems(ctx, r.Invoice.ID)
+				if err != nil {
+					return fmt.Errorf("failed to get line items: %w", err)
+				}
+				entries, err := lineItemEntries(ctx, items)
+				if err != nil {
+					return err
+				}
+				if lints := service.LintEntries(entries); len(lints) > 0 {
+					notes = append(notes, fmt.Sprintf("%d to lint", len(lints)))
+				}
+			}
+			fmt.Printf("%-20s %-16s %8d %12s  %s\n",
+				truncate(r.Client.Name, 20),
+				number,
+				r.Entries,
+				amount,
+				strings.Join(notes, "; "),
+			)
+		}
+
+		fmt.Printf("\n✓ %d draft invoice(s) created, totalling %s\n", drafted, formatMoney(total.Float()))
+		if failed > 0 {
+			return fmt.Errorf("%d client(s) could not be invoiced", failed)
+		}
+		return nil
+	},
+}
+
 var invoicesAddEntriesCmd = &cobra.Command{
 	Use:   "add-entries [invoice_id] [entry_ids...]",
 	Short: "Add time entries to a draft invoice",
@@ -163,19 +340,33 @@ var invoicesAddEntriesCmd = &cobra.Command{
 		}
 
 		// Recalculate totals
-		taxRate, _ := cmd.Flags().GetFloat64("tax")
-		if err := appInstance.InvoiceService.CalculateTotals(ctx, invoiceID, taxRate); err != nil {
+		taxes, err := invoiceTaxesForDraft(ctx, cmd, invoiceID)
+		if err != nil {
+			return err
+		}
+		if err := appInstance.InvoiceService.CalculateTotals(ctx, invoiceID, taxes); err != nil {
 			return fmt.Errorf("failed to calculate totals: %w", err)
 		}
 
 		fmt.Printf("✓ Added %d entries to invoice #%d\n", len(entryIDs), invoiceID)
+		if appInstance.Config.Invoice.LintEntries {
+			items, err := appInstance.InvoiceRepo.GetLineItems(ctx, invoiceID)
+			if err != nil {
+				return fmt.Errorf("failed to get line items: %w", err)
+			}
+			entries, err := lineItemEntries(ctx, items)
+			if err != nil {
+				return err
+			}
+			warnEntryLint(entries)
+		}
 
 		// Show updated invoice
 		invoice, _ := appInstance.InvoiceService.GetInvoice(ctx, invoiceID)
 		if invoice != nil {
-			fmt.Printf("  Subtotal: $%.2f\n", invoice.Subtotal)
-			fmt.Printf("  Tax: $%.2f\n", invoice.TaxAmount)
-			fmt.Printf("  Total: $%.2f\n", invoice.Total)
+			fmt.Printf("  Subtotal: %s\n", formatMoney(invoice.Subtotal.Float()))
+			fmt.Printf("  Tax: %s\n", formatMoney(invoice.TaxAmount.Float()))
+			fmt.Printf("  Total: %s\n", formatMoney(invoice.Total.Float()))
 		}
 
 		return nil
@@ -194,24 +385,181 @@ var invoicesFinalizeCmd = &cobra.Command{
 			return fmt.Errorf("invalid invoice ID: %w", err)
 		}
 
-		if err := appInstance.InvoiceService.Finalize(ctx, id); err != nil {
+		if cmd.Flags().Changed("due-days") || cmd.Flags().Changed("due-date") {
+			invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get invoice: %w", err)
+			}
+			if invoice == nil {
+				return fmt.Errorf("invoice not found")
+			}
+			if !invoice.CanEdit() {
+				return fmt.Errorf("failed to finalize invoice: %w", service.ErrInvoiceNotEditable)
+			}
+			due, err := dueDateFromFlags(cmd, invoice)
+			if err != nil {
+				return err
+			}
+			if err := appInstance.InvoiceService.SetDueDate(ctx, id, *due, ""); err != nil {
+				return fmt.Errorf("failed to set due date: %w", err)
+			}
+		}
+
+		if err := appInstance.InvoiceService.Finalize(ctx, id, appInstance.Config.Invoice.DefaultDueDays); err != nil {
 			return fmt.Errorf("failed to finalize invoice: %w", err)
 		}
 
 		invoice, _ := appInstance.InvoiceService.GetInvoice(ctx, id)
 		if invoice != nil {
 			fmt.Printf("✓ Invoice finalized: %s\n", invoice.InvoiceNumber)
-			fmt.Printf("  Total: $%.2f\n", invoice.Total)
+			fmt.Printf("  Total: %s\n", formatMoney(invoice.Total.Float()))
+			if invoice.DueDate != nil {
+				fmt.Printf("  Due: %s\n", formatDate(*invoice.DueDate))
+			}
+		}
+
+		return nil
+	},
+}
+
+var invoicesSetDueCmd = &cobra.Command{
+	Use:   "set-due [id]",
+	Short: "Change an unpaid invoice's due date",
+	Long: `Set-due moves the due date of an invoice that has not been paid, given as
+--due-date or as --due-days after the invoice date. Once the invoice has been
+sent the change needs a --note, which is kept with the invoice and listed by
+'invoices show'. An overdue invoice given a date still ahead is no longer
+overdue.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+
+		due, err := dueDateFromFlags(cmd, invoice)
+		if err != nil {
+			return err
+		}
+		if due == nil {
+			return fmt.Errorf("give the new due date with --due-date or --due-days")
+		}
+
+		note, _ := cmd.Flags().GetString("note")
+		if err := appInstance.InvoiceService.SetDueDate(ctx, id, *due, note); err != nil {
+			return fmt.Errorf("failed to set due date: %w", err)
+		}
+
+		fmt.Printf("✓ Invoice %s is now due %s\n", invoice.InvoiceNumber, formatDate(*due))
+		return nil
+	},
+}
+
+var invoicesReopenCmd = &cobra.Command{
+	Use:   "reopen [id]",
+	Short: "Move a finalized invoice back to draft (unlocks entries)",
+	Long: `Reopen moves a finalized invoice back to draft and releases the locks on
+its time entries so mistakes can be corrected. Invoices that have been sent
+or paid cannot be reopened.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+		if !invoice.CanReopen() {
+			return fmt.Errorf("cannot reopen invoice %s: it is %s", invoice.InvoiceNumber, invoice.Status)
+		}
+
+		msg := fmt.Sprintf("This will move invoice %s (%s) back to draft and unlock its time entries.",
+			invoice.InvoiceNumber, formatMoney(invoice.Total.Float()))
+		if !confirmTyped(msg, invoice.InvoiceNumber) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		if err := appInstance.InvoiceService.Reopen(ctx, id); err != nil {
+			return fmt.Errorf("failed to reopen invoice: %w", err)
+		}
+
+		fmt.Printf("✓ Invoice %s reopened as draft\n", invoice.InvoiceNumber)
+		return nil
+	},
+}
+
+var invoicesDeleteCmd = &cobra.Command{
+	Use:   "delete [id]",
+	Short: "Delete a draft invoice",
+	Long: `Delete voids a draft invoice and hides it from the invoice list. Its line
+items are removed, so the time entries and any estimate they billed can be
+invoiced again. The invoice number stays taken; list void invoices with
+--status void. Finalized invoices cannot be deleted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+		if !invoice.CanDelete() {
+			return fmt.Errorf("cannot delete invoice %s: it is %s", invoice.InvoiceNumber, invoice.Status)
+		}
+
+		msg := fmt.Sprintf("This will delete draft invoice %s (%s) and release its line items. Continue?",
+			invoice.InvoiceNumber, formatMoney(invoice.Total.Float()))
+		if !confirmPrompt(msg) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		if err := appInstance.InvoiceService.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete invoice: %w", err)
 		}
 
+		fmt.Printf("✓ Invoice %s deleted\n", invoice.InvoiceNumber)
 		return nil
 	},
 }
 
 var invoicesMarkSentCmd = &cobra.Command{
 	Use:   "mark-sent [id]",
-	Short: "Mark an invoice as sent",
-	Args:  cobra.ExactArgs(1),
+	Short: "Mark an invoice as sent and log the delivery",
+	Long: `Mark-sent records who the invoice was sent to and when in the invoice's
+delivery log, and marks a finalized invoice as sent. Run it again when
+resending or chasing; the status of sent and paid invoices is left alone.
+
+Pass the e-mail's message ID to match read receipts and provider webhooks to
+the delivery later with mark-opened.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
@@ -220,11 +568,160 @@ var invoicesMarkSentCmd = &cobra.Command{
 			return fmt.Errorf("invalid invoice ID: %w", err)
 		}
 
-		if err := appInstance.InvoiceService.MarkSent(ctx, id); err != nil {
+		sentAt := time.Now()
+		if value, _ := cmd.Flags().GetString("at"); value != "" {
+			if sentAt, err = parseDateTime(value); err != nil {
+				return fmt.Errorf("invalid sent time: %w", err)
+			}
+		}
+		recipient, _ := cmd.Flags().GetString("to")
+		messageID, _ := cmd.Flags().GetString("message-id")
+
+		delivery, err := appInstance.DeliveryService.Send(ctx, id, recipient, messageID, sentAt)
+		if err != nil {
 			return fmt.Errorf("failed to mark invoice as sent: %w", err)
 		}
 
 		fmt.Printf("✓ Invoice #%d marked as sent\n", id)
+		if delivery.Recipient != "" {
+			fmt.Printf("  Delivered to %s (delivery #%d)\n", delivery.Recipient, delivery.ID)
+		}
+		return nil
+	},
+}
+
+var invoicesMarkOpenedCmd = &cobra.Command{
+	Use:   "mark-opened [delivery_id]",
+	Short: "Record that the client opened a delivered invoice",
+	Long: `Mark-opened records a read receipt or a provider's open event against a
+delivery from the invoice's log, found by its ID or by --message-id. Only the
+first open is kept.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		openedAt := time.Now()
+		if value, _ := cmd.Flags().GetString("at"); value != "" {
+			var err error
+			if openedAt, err = parseDateTime(value); err != nil {
+				return fmt.Errorf("invalid opened time: %w", err)
+			}
+		}
+
+		messageID, _ := cmd.Flags().GetString("message-id")
+		var delivery *domain.InvoiceDelivery
+		var err error
+		switch {
+		case len(args) == 1 && messageID != "":
+			return fmt.Errorf("pass a delivery ID or --message-id, not both")
+		case len(args) == 1:
+			id, perr := strconv.ParseInt(args[0], 10, 64)
+			if perr != nil {
+				return fmt.Errorf("invalid delivery ID: %w", perr)
+			}
+			delivery, err = appInstance.DeliveryService.MarkOpened(ctx, id, openedAt)
+		case messageID != "":
+			delivery, err = appInstance.DeliveryService.MarkOpenedByMessageID(ctx, messageID, openedAt)
+		default:
+			return fmt.Errorf("pass a delivery ID or --message-id")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record open: %w", err)
+		}
+
+		fmt.Printf("✓ Invoice #%d opened %s\n", delivery.InvoiceID, delivery.OpenedAt.Format("2006-01-02 15:04"))
+		return nil
+	},
+}
+
+var invoicesInterestCmd = &cobra.Command{
+	Use:   "interest [id]",
+	Short: "Calculate late-payment interest on an invoice",
+	Long: `Interest calculates simple late-payment interest on an invoice's total from
+its due date until it was paid, or until today (or --as-of) while it is still
+outstanding. Rates are per year unless given per month, e.g. --rate 8%/year
+for statutory interest or --rate 1.5%/month for a contractual rate.
+
+Invoices without a due date are taken to be due invoice.default_due_days after
+they were created; --due overrides the due date.
+
+With --add-to the interest is added as a line item to a draft invoice for the
+same client; with --follow-up it goes on a new, untaxed draft invoice.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		rate, err := domain.ParseInterestRate(mustGetString(cmd, "rate"))
+		if err != nil {
+			return err
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+		if !invoice.IsFinalized() {
+			return fmt.Errorf("invoice %s is still a draft", invoice.InvoiceNumber)
+		}
+
+		due := invoice.DueOn(appInstance.Config.Invoice.DefaultDueDays)
+		if value := mustGetString(cmd, "due"); value != "" {
+			if due, err = parseDate(value); err != nil {
+				return fmt.Errorf("invalid due date: %w", err)
+			}
+		}
+		asOf := time.Now()
+		if value := mustGetString(cmd, "as-of"); value != "" {
+			if asOf, err = parseDate(value); err != nil {
+				return fmt.Errorf("invalid as-of date: %w", err)
+			}
+		}
+
+		charge := domain.NewInterestCharge(invoice, due, asOf, rate)
+		fmt.Printf("Invoice %s: %s due %s\n", invoice.InvoiceNumber, formatMoney(charge.Principal.Float()), formatDate(charge.DueDate))
+		if charge.Days == 0 {
+			fmt.Println("Not overdue, so no interest is owed")
+			return nil
+		}
+		until := "unpaid as of"
+		if invoice.PaidDate != nil {
+			until = "paid"
+		}
+		fmt.Printf("  %s %s, %d days late\n", until, formatDate(charge.Until), charge.Days)
+		fmt.Printf("  Interest at %s: %s\n", charge.Rate, formatMoney(charge.Amount.Float()))
+
+		addTo, _ := cmd.Flags().GetInt64("add-to")
+		followUp, _ := cmd.Flags().GetBool("follow-up")
+		switch {
+		case addTo != 0 && followUp:
+			return fmt.Errorf("pass --add-to or --follow-up, not both")
+		case addTo != 0:
+			if err := appInstance.InvoiceService.AddInterest(ctx, addTo, charge); err != nil {
+				return fmt.Errorf("failed to add interest: %w", err)
+			}
+			fmt.Printf("✓ Interest added to invoice #%d\n", addTo)
+		case followUp:
+			prefix := mustGetString(cmd, "prefix")
+			if prefix == "" {
+				prefix = appInstance.Config.Invoice.NumberPrefix
+			}
+			if prefix == "" {
+				prefix = "INV"
+			}
+			followUpInvoice, err := appInstance.InvoiceService.ChargeInterest(ctx, charge, prefix, invoiceFooter(cmd))
+			if err != nil {
+				return fmt.Errorf("failed to create follow-up invoice: %w", err)
+			}
+			fmt.Printf("✓ Draft invoice created: %s (ID: %d)\n", followUpInvoice.InvoiceNumber, followUpInvoice.ID)
+		}
 		return nil
 	},
 }
@@ -256,7 +753,7 @@ var invoicesMarkPaidCmd = &cobra.Command{
 			return fmt.Errorf("failed to mark invoice as paid: %w", err)
 		}
 
-		fmt.Printf("✓ Invoice #%d marked as paid on %s\n", id, paidDate.Format("2006-01-02"))
+		fmt.Printf("✓ Invoice #%d marked as paid on %s\n", id, formatDate(paidDate))
 		return nil
 	},
 }
@@ -287,6 +784,11 @@ var invoicesShowCmd = &cobra.Command{
 			return fmt.Errorf("failed to load line items: %w", err)
 		}
 
+		invoice.Taxes, err = appInstance.InvoiceRepo.GetTaxes(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load taxes: %w", err)
+		}
+
 		// Get client
 		client, _ := appInstance.ClientRepo.GetByID(ctx, invoice.ClientID)
 		clientName := fmt.Sprintf("Client #%d", invoice.ClientID)
@@ -294,76 +796,644 @@ var invoicesShowCmd = &cobra.Command{
 			clientName = client.Name
 		}
 
-		// Print invoice details
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("Invoice: %s\n", invoice.InvoiceNumber)
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("Client: %s\n", clientName)
-		fmt.Printf("Period: %s to %s\n",
-			invoice.PeriodStart.Format("2006-01-02"),
-			invoice.PeriodEnd.Format("2006-01-02"),
-		)
-		fmt.Printf("Status: %s\n", invoice.Status)
-		fmt.Println()
-
-		// Print line items
-		if len(lineItems) > 0 {
-			fmt.Println("Line Items:")
-			fmt.Println(strings.Repeat("-", 80))
-			fmt.Printf("%-12s %-40s %-8s %-8s %s\n", "Date", "Description", "Hours", "Rate", "Amount")
-			fmt.Println(strings.Repeat("-", 80))
-
-			for _, item := range lineItems {
-				fmt.Printf("%-12s %-40s %8.2f $%7.2f $%8.2f\n",
-					item.Date.Format("2006-01-02"),
-					truncate(item.Description, 40),
-					item.Hours,
-					item.Rate,
-					item.Amount,
-				)
-			}
-			fmt.Println(strings.Repeat("-", 80))
-		}
-
-		// Print totals
-		fmt.Printf("\n")
-		fmt.Printf("Subtotal: $%.2f\n", invoice.Subtotal)
-		fmt.Printf("Tax (%.1f%%): $%.2f\n", invoice.TaxRate*100, invoice.TaxAmount)
-		fmt.Printf("Total: $%.2f\n", invoice.Total)
-		fmt.Println(strings.Repeat("=", 80))
+		writeInvoice(os.Stdout, invoice, clientName, lineItems)
+
+		attachments, err := appInstance.AttachmentService.List(ctx, domain.AttachmentOwnerInvoice, id)
+		if err != nil {
+			return fmt.Errorf("failed to load attachments: %w", err)
+		}
+		if len(attachments) > 0 {
+			fmt.Println("\nAttachments:")
+			for _, a := range attachments {
+				fmt.Printf("  #%-4d %s (%s)\n", a.ID, a.Name, formatBytes(a.Size))
+			}
+		}
+
+		changes, err := appInstance.InvoiceRepo.GetDueDateChanges(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load due date changes: %w", err)
+		}
+		if len(changes) > 0 {
+			fmt.Println("\nDue Date Changes:")
+			for _, c := range changes {
+				from := "none"
+				if c.From != nil {
+					from = formatDate(*c.From)
+				}
+				fmt.Printf("  %s  %s -> %s  %s\n", c.ChangedAt.Format("2006-01-02 15:04"), from, formatDate(c.To), c.Note)
+			}
+		}
+
+		deliveries, err := appInstance.DeliveryService.List(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load deliveries: %w", err)
+		}
+		if len(deliveries) > 0 {
+			fmt.Println("\nDeliveries:")
+			for _, d := range deliveries {
+				fmt.Printf("  #%-4d %s  %s\n", d.ID, d.SentAt.Format("2006-01-02 15:04"), deliverySummary(d))
+			}
+		}
 
 		return nil
 	},
 }
 
-var invoicesRemoveEntryCmd = &cobra.Command{
-	Use:   "remove-entry [invoice_id] [entry_id]",
-	Short: "Remove a time entry from a draft invoice",
-	Args:  cobra.ExactArgs(2),
+var invoicesPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Preview the invoice unbilled entries would produce, without saving",
+	Long: `Preview renders the invoice that would be generated from a client's
+unbilled entries in the period. Nothing is written to the database: no draft
+is created, no invoice number is reserved and no entries are locked.
+
+Use --output to export the draft to a text file instead of printing it.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		invoiceID, err := strconv.ParseInt(args[0], 10, 64)
+		clientArg, _ := cmd.Flags().GetString("client")
+		clientID, err := resolveClientID(ctx, clientArg)
 		if err != nil {
-			return fmt.Errorf("invalid invoice ID: %w", err)
+			return fmt.Errorf("failed to resolve client: %w", err)
 		}
 
-		entryID, err := strconv.ParseInt(args[1], 10, 64)
+		startStr, _ := cmd.Flags().GetString("start")
+		endStr, _ := cmd.Flags().GetString("end")
+
+		start, err := parseDate(startStr)
 		if err != nil {
-			return fmt.Errorf("invalid entry ID: %w", err)
+			return fmt.Errorf("invalid start date: %w", err)
 		}
 
-		if err := appInstance.InvoiceService.RemoveEntryFromInvoice(ctx, invoiceID, entryID); err != nil {
-			return fmt.Errorf("failed to remove entry from invoice: %w", err)
+		end, err := parseDate(endStr)
+		if err != nil {
+			return fmt.Errorf("invalid end date: %w", err)
+		}
+		// Include entries started on the end date
+		end = end.AddDate(0, 0, 1).Add(-time.Second)
+
+		prefix, _ := cmd.Flags().GetString("prefix")
+		if prefix == "" {
+			prefix = appInstance.Config.Invoice.NumberPrefix
+		}
+		if prefix == "" {
+			prefix = "INV"
+		}
+
+		client, err := appInstance.ClientRepo.GetByID(ctx, clientID)
+		if err != nil {
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+
+		invoice, err := appInstance.InvoiceService.Preview(ctx, clientID, start, end, prefix, invoiceTaxes(cmd, client), invoiceFooter(cmd))
+		if err != nil {
+			return fmt.Errorf("failed to preview invoice: %w", err)
+		}
+		if len(invoice.LineItems) == 0 {
+			return fmt.Errorf("no unbilled entries for %s between %s and %s",
+				invoice.Client.Name, formatDate(start), formatDate(end))
+		}
+
+		if appInstance.Config.Invoice.LintEntries {
+			entries, err := lineItemEntries(ctx, invoice.LineItems)
+			if err != nil {
+				return err
+			}
+			defer warnEntryLint(entries)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			fmt.Println("PREVIEW - nothing has been saved")
+			writeInvoice(os.Stdout, invoice, invoice.Client.Name, invoice.LineItems)
+			return nil
+		}
+
+		labels, err := appInstance.InvoiceLabels(invoice.Client)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+
+		// Same layout as generated invoices, marked so it can't pass for one
+		fmt.Fprintln(f, "DRAFT PREVIEW - not a valid invoice")
+		opts := render.Options{
+			Locale:     cliLocale(),
+			HourFormat: appInstance.Config.Invoice.HourFormat,
+			From:       appInstance.Config.User,
+			Date:       time.Now(),
+			Labels:     labels,
+		}
+		if err := render.Invoice(f, invoice, invoice.LineItems, opts); err != nil {
+			return fmt.Errorf("failed to render invoice: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+
+		fmt.Printf("✓ Draft preview written to %s\n", output)
+		fmt.Printf("  %d line items, total %s\n", len(invoice.LineItems), formatMoney(invoice.Total.Float()))
+		return nil
+	},
+}
+
+var invoicesRenderCmd = &cobra.Command{
+	Use:   "render [id]",
+	Short: "Print an invoice to stdout, e.g. to pipe it to lpr or less",
+	Long: `Render writes an invoice to stdout and nothing else, so it can be piped
+to a printer, a pager or another tool:
+
+  timesink invoices render 12 | lpr
+  timesink invoices render 12 --format markdown | less
+
+Use invoices export to write it to a file instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		exporter, err := render.ExporterNamed(mustGetString(cmd, "format"))
+		if err != nil {
+			return err
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+		data, err := renderInvoice(ctx, invoice, exporter)
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(data)
+		return err
+	},
+}
+
+var invoicesExportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Render an invoice as a document for the client",
+	Long: `Render an invoice as a document for the client.
+
+The format is taken from --format, or else from the extension of --output,
+e.g. INV-2026-001.html. Without either the invoice is plain text. Invoices
+with no stored due date show one invoice.default_due_days after they were
+created.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		output := mustGetString(cmd, "output")
+		exporter, err := invoiceExporter(mustGetString(cmd, "format"), output)
+		if err != nil {
+			return err
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+		data, err := renderInvoice(ctx, invoice, exporter)
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			_, err := os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+
+		fmt.Printf("✓ Invoice %s written to %s (%s)\n", invoice.InvoiceNumber, output, exporter.Name())
+		return nil
+	},
+}
+
+var invoicesExportBatchCmd = &cobra.Command{
+	Use:   "export-batch",
+	Short: "Render every invoice dated in a period into one ZIP file",
+	Long: `Render every invoice dated in a period into one ZIP file, e.g. to hand an
+accountant the year's invoices at tax time. Each invoice is a file named
+after its number, in --format (default txt). Drafts and void invoices are
+left out unless --drafts is given.
+
+Choose the period with --year, or --start and --end:
+
+  timesink invoices export-batch --year 2025 --format pdf -o invoices-2025.zip`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		start, end, err := batchPeriod(cmd)
+		if err != nil {
+			return err
+		}
+		exporter, err := render.ExporterNamed(mustGetString(cmd, "format"))
+		if err != nil {
+			return err
+		}
+		output := mustGetString(cmd, "output")
+		if output == "" {
+			output = fmt.Sprintf("invoices-%s-to-%s.zip", start.Format("2006-01-02"), end.Format("2006-01-02"))
+			if cmd.Flags().Changed("year") {
+				output = fmt.Sprintf("invoices-%d.zip", start.Year())
+			}
+		}
+		drafts, _ := cmd.Flags().GetBool("drafts")
+
+		invoices, err := appInstance.InvoiceService.ListInvoices(ctx, nil, nil)
+		if err != nil {
+			return fmt.Errorf("failed to list invoices: %w", err)
+		}
+		var batch []*domain.Invoice
+		for _, inv := range invoices {
+			if inv.Status == domain.InvoiceStatusVoid || (inv.Status == domain.InvoiceStatusDraft && !drafts) {
+				continue
+			}
+			if inv.CreatedAt.Before(start) || !inv.CreatedAt.Before(end.AddDate(0, 0, 1)) {
+				continue
+			}
+			batch = append(batch, inv)
+		}
+		if len(batch) == 0 {
+			return fmt.Errorf("no invoices dated %s to %s", formatDate(start), formatDate(end))
+		}
+		sort.Slice(batch, func(i, j int) bool { return batch[i].InvoiceNumber < batch[j].InvoiceNumber })
+
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		var total domain.Money
+		for _, inv := range batch {
+			data, err := renderInvoice(ctx, inv, exporter)
+			if err != nil {
+				return fmt.Errorf("invoice %s: %w", inv.InvoiceNumber, err)
+			}
+			w, err := zw.CreateHeader(&zip.FileHeader{
+				Name:     inv.InvoiceNumber + exporter.Extensions()[0],
+				Method:   zip.Deflate,
+				Modified: inv.CreatedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add %s: %w", inv.InvoiceNumber, err)
+			}
+			if _, err := w.Write(data); err != nil {
+				return fmt.Errorf("failed to add %s: %w", inv.InvoiceNumber, err)
+			}
+			total += inv.Total
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to build archive: %w", err)
+		}
+
+		// Written in one go, so a failed run leaves no half-built archive
+		if err := os.WriteFile(output, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+
+		fmt.Printf("✓ %d invoice(s) totalling %s written to %s (%s)\n",
+			len(batch), formatMoney(total.Float()), output, exporter.Name())
+		return nil
+	},
+}
+
+// batchPeriod returns the first and last day of the invoices to export,
+// from --year or from --start and --end
+func batchPeriod(cmd *cobra.Command) (time.Time, time.Time, error) {
+	if cmd.Flags().Changed("year") {
+		if cmd.Flags().Changed("start") || cmd.Flags().Changed("end") {
+			return time.Time{}, time.Time{}, fmt.Errorf("use --year or --start and --end, not both")
+		}
+		year, _ := cmd.Flags().GetInt("year")
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(1, 0, -1), nil
+	}
+
+	startStr, endStr := mustGetString(cmd, "start"), mustGetString(cmd, "end")
+	if startStr == "" || endStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--year, or --start and --end, is required")
+	}
+	start, err := parseDate(startStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := parseDate(endStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date is before start date")
+	}
+	return start, end, nil
+}
+
+// renderInvoice loads what the document shows beyond the invoice itself and
+// renders it with exporter. Invoices with no stored due date show one
+// invoice.default_due_days after they were created.
+func renderInvoice(ctx context.Context, invoice *domain.Invoice, exporter render.Exporter) ([]byte, error) {
+	lineItems, err := appInstance.InvoiceRepo.GetLineItems(ctx, invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load line items: %w", err)
+	}
+	if invoice.Taxes, err = appInstance.InvoiceRepo.GetTaxes(ctx, invoice.ID); err != nil {
+		return nil, fmt.Errorf("failed to load taxes: %w", err)
+	}
+	if invoice.Client, err = appInstance.ClientRepo.GetByID(ctx, invoice.ClientID); err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	if invoice.DueDate == nil && invoice.Status != domain.InvoiceStatusDraft {
+		due := invoice.DueOn(appInstance.Config.Invoice.DefaultDueDays)
+		invoice.DueDate = &due
+	}
+	labels, err := appInstance.InvoiceLabels(invoice.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := render.Options{
+		Locale:     cliLocale(),
+		HourFormat: appInstance.Config.Invoice.HourFormat,
+		From:       appInstance.Config.User,
+		Date:       invoice.CreatedAt,
+		Labels:     labels,
+	}
+	data, err := exporter.Render(invoice, lineItems, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render invoice: %w", err)
+	}
+	return data, nil
+}
+
+// invoiceExporter picks the exporter named by --format, or else the one for
+// the output file's extension, or else plain text
+func invoiceExporter(format, output string) (render.Exporter, error) {
+	if format != "" {
+		return render.ExporterNamed(format)
+	}
+	if e, ok := render.ExporterFor(output); ok {
+		return e, nil
+	}
+	return render.ExporterNamed(render.DefaultExporter)
+}
+
+var invoicesNotesCmd = &cobra.Command{
+	Use:   "notes [id]",
+	Short: "Show or edit the notes and payment instructions of an invoice",
+	Long: `Notes and payment instructions are printed at the bottom of the invoice.
+New invoices start with invoice.notes and user.payment_instructions from
+config.yaml. Without flags the current text is shown; set either flag to
+change it while the invoice is a draft, or to "" to remove it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		invoice, err := appInstance.InvoiceService.GetInvoice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice: %w", err)
+		}
+		if invoice == nil {
+			return fmt.Errorf("invoice not found")
+		}
+
+		footer := invoice.Footer
+		changed := cmd.Flags().Changed("notes") || cmd.Flags().Changed("payment-instructions")
+		if cmd.Flags().Changed("notes") {
+			footer.Notes, _ = cmd.Flags().GetString("notes")
+		}
+		if cmd.Flags().Changed("payment-instructions") {
+			footer.PaymentInstructions, _ = cmd.Flags().GetString("payment-instructions")
+		}
+
+		if changed {
+			if err := appInstance.InvoiceService.SetFooter(ctx, id, footer); err != nil {
+				return fmt.Errorf("failed to update invoice: %w", err)
+			}
+			fmt.Printf("✓ Invoice %s updated\n", invoice.InvoiceNumber)
+		}
+
+		fmt.Printf("Notes:\n%s\n", indentOrNone(footer.Notes))
+		fmt.Printf("Payment Instructions:\n%s\n", indentOrNone(footer.PaymentInstructions))
+		return nil
+	},
+}
+
+var invoicesAttachmentsCmd = &cobra.Command{
+	Use:   "attachments [invoice_id]",
+	Short: "List the files attached to an invoice",
+	Long: `Files such as signed contracts, receipts, or the PDF that was sent are
+copied into database.attachments_dir and listed with the invoice. Use the
+subcommands to add, open, or remove them. Only invoices take attachments;
+expenses are not tracked, so a receipt goes on the invoice it was billed on.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		attachments, err := appInstance.AttachmentService.List(ctx, domain.AttachmentOwnerInvoice, id)
+		if err != nil {
+			return fmt.Errorf("failed to list attachments: %w", err)
+		}
+
+		if len(attachments) == 0 {
+			fmt.Println("No attachments")
+			return nil
+		}
+
+		fmt.Printf("%-5s %-30s %-10s %-12s\n", "ID", "Name", "Size", "Added")
+		fmt.Println("----------------------------------------------------------------")
+		for _, a := range attachments {
+			fmt.Printf("%-5d %-30s %-10s %-12s\n", a.ID, truncate(a.Name, 30), formatBytes(a.Size), formatDate(a.CreatedAt))
+		}
+
+		return nil
+	},
+}
+
+var invoicesAttachCmd = &cobra.Command{
+	Use:   "add [invoice_id] [file]...",
+	Short: "Attach files to an invoice",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		for _, path := range args[1:] {
+			attachment, err := appInstance.AttachmentService.Attach(ctx, domain.AttachmentOwnerInvoice, id, path)
+			if err != nil {
+				return fmt.Errorf("failed to attach %s: %w", path, err)
+			}
+			fmt.Printf("✓ Attached %s (#%d, %s)\n", attachment.Name, attachment.ID, formatBytes(attachment.Size))
+		}
+
+		return nil
+	},
+}
+
+var invoicesOpenAttachmentCmd = &cobra.Command{
+	Use:   "open [attachment_id]",
+	Short: "Open an attachment with the default application",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid attachment ID: %w", err)
+		}
+
+		attachment, err := appInstance.AttachmentService.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get attachment: %w", err)
+		}
+
+		if printPath, _ := cmd.Flags().GetBool("path"); printPath {
+			fmt.Println(appInstance.AttachmentService.Path(attachment))
+			return nil
+		}
+
+		return appInstance.OpenAttachment(attachment)
+	},
+}
+
+var invoicesRemoveAttachmentCmd = &cobra.Command{
+	Use:   "remove [attachment_id]",
+	Short: "Remove an attachment and its stored file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid attachment ID: %w", err)
+		}
+
+		if err := appInstance.AttachmentService.Remove(ctx, id); err != nil {
+			return fmt.Errorf("failed to remove attachment: %w", err)
+		}
+
+		fmt.Printf("✓ Attachment #%d removed\n", id)
+		return nil
+	},
+}
+
+var invoicesEditItemCmd = &cobra.Command{
+	Use:   "edit-item [invoice_id] [line] [description]",
+	Short: "Reword a line item on a draft invoice",
+	Long: `Replace the description of a line item on a draft invoice, e.g. to tidy
+shorthand copied from an entry. The line is its number in invoices show.
+The entry keeps its own description, and the line's original wording is
+kept and shown by invoices show; --revert restores it.`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		invoiceID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+		line, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid line number: %w", err)
+		}
+
+		revert, _ := cmd.Flags().GetBool("revert")
+		description := ""
+		switch {
+		case revert && len(args) == 3:
+			return fmt.Errorf("give a description or --revert, not both")
+		case !revert && len(args) < 3:
+			return fmt.Errorf("give the new description, or --revert to restore the original")
+		case !revert:
+			if description = strings.TrimSpace(args[2]); description == "" {
+				return fmt.Errorf("description cannot be empty; use --revert to restore the original")
+			}
+		}
+
+		lineItems, err := appInstance.InvoiceRepo.GetLineItems(ctx, invoiceID)
+		if err != nil {
+			return fmt.Errorf("failed to load line items: %w", err)
+		}
+		if line < 1 || line > len(lineItems) {
+			return fmt.Errorf("invoice %d has no line %d (it has %d)", invoiceID, line, len(lineItems))
+		}
+
+		item, err := appInstance.InvoiceService.RewordLineItem(ctx, invoiceID, lineItems[line-1].ID, description)
+		if err != nil {
+			return fmt.Errorf("failed to edit line item: %w", err)
+		}
+
+		if item.IsReworded() {
+			fmt.Printf("✓ Line %d reworded: %s\n", line, item.Description)
+			fmt.Printf("  Was: %s\n", item.OriginalDescription)
+		} else {
+			fmt.Printf("✓ Line %d restored: %s\n", line, item.Description)
+		}
+		return nil
+	},
+}
+
+var invoicesRemoveEntryCmd = &cobra.Command{
+	Use:   "remove-entry [invoice_id] [entry_id]",
+	Short: "Remove a time entry from a draft invoice",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		invoiceID, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invoice ID: %w", err)
+		}
+
+		entryID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid entry ID: %w", err)
+		}
+
+		if err := appInstance.InvoiceService.RemoveEntryFromInvoice(ctx, invoiceID, entryID); err != nil {
+			return fmt.Errorf("failed to remove entry from invoice: %w", err)
 		}
 
 		fmt.Printf("✓ Removed entry %d from invoice %d\n", entryID, invoiceID)
 		// Show updated invoice totals
 		invoice, _ := appInstance.InvoiceService.GetInvoice(ctx, invoiceID)
 		if invoice != nil {
-			fmt.Printf("  Subtotal: $%.2f\n", invoice.Subtotal)
-			fmt.Printf("  Tax: $%.2f\n", invoice.TaxAmount)
-			fmt.Printf("  Total: $%.2f\n", invoice.Total)
+			fmt.Printf("  Subtotal: %s\n", formatMoney(invoice.Subtotal.Float()))
+			fmt.Printf("  Tax: %s\n", formatMoney(invoice.TaxAmount.Float()))
+			fmt.Printf("  Total: %s\n", formatMoney(invoice.Total.Float()))
 		}
 
 		return nil
@@ -372,17 +1442,41 @@ var invoicesRemoveEntryCmd = &cobra.Command{
 
 func init() {
 	invoicesCmd.AddCommand(invoicesListCmd)
+	invoicesCmd.AddCommand(invoicesSuggestCmd)
 	invoicesCmd.AddCommand(invoicesCreateCmd)
+	invoicesCmd.AddCommand(invoicesGenerateAllCmd)
 	invoicesCmd.AddCommand(invoicesAddEntriesCmd)
 	invoicesCmd.AddCommand(invoicesFinalizeCmd)
+	invoicesCmd.AddCommand(invoicesSetDueCmd)
+	invoicesCmd.AddCommand(invoicesReopenCmd)
+	invoicesCmd.AddCommand(invoicesDeleteCmd)
 	invoicesCmd.AddCommand(invoicesMarkSentCmd)
+	invoicesCmd.AddCommand(invoicesMarkOpenedCmd)
 	invoicesCmd.AddCommand(invoicesMarkPaidCmd)
+	invoicesCmd.AddCommand(invoicesInterestCmd)
 	invoicesCmd.AddCommand(invoicesShowCmd)
+	invoicesCmd.AddCommand(invoicesPreviewCmd)
+	invoicesCmd.AddCommand(invoicesRenderCmd)
+	invoicesCmd.AddCommand(invoicesExportCmd)
+	invoicesCmd.AddCommand(invoicesExportBatchCmd)
 	invoicesCmd.AddCommand(invoicesRemoveEntryCmd)
+	invoicesCmd.AddCommand(invoicesEditItemCmd)
+	invoicesCmd.AddCommand(invoicesNotesCmd)
+	invoicesCmd.AddCommand(invoicesAttachmentsCmd)
+	invoicesAttachmentsCmd.AddCommand(invoicesAttachCmd)
+	invoicesAttachmentsCmd.AddCommand(invoicesOpenAttachmentCmd)
+	invoicesAttachmentsCmd.AddCommand(invoicesRemoveAttachmentCmd)
 
 	// List flags
 	invoicesListCmd.Flags().Int64("client", 0, "Filter by client ID")
-	invoicesListCmd.Flags().String("status", "", "Filter by status (draft, finalized, sent, paid, overdue)")
+	invoicesListCmd.Flags().String("status", "", "Filter by status (draft, finalized, sent, paid, overdue, void)")
+
+	// Suggest flags
+	invoicesSuggestCmd.Flags().Float64("min-unbilled", 0, "Unbilled amount worth invoicing, 0 for off (defaults to invoice.remind_unbilled)")
+	invoicesSuggestCmd.Flags().Int("days", 0, "Days since the last invoice, 0 for off (defaults to invoice.remind_after_days)")
+
+	// Attachment flags
+	invoicesOpenAttachmentCmd.Flags().Bool("path", false, "Print the stored file's path instead of opening it")
 
 	// Create flags
 	invoicesCreateCmd.Flags().String("start", "", "Period start date (required)")
@@ -390,10 +1484,275 @@ func init() {
 	invoicesCreateCmd.Flags().String("prefix", "INV", "Invoice number prefix")
 	invoicesCreateCmd.MarkFlagRequired("start")
 	invoicesCreateCmd.MarkFlagRequired("end")
+	addFooterFlags(invoicesCreateCmd)
+	addDueDateFlags(invoicesCreateCmd)
+
+	// Generate all flags
+	invoicesGenerateAllCmd.Flags().String("period", "last-month", "Month to invoice: last-month, this-month, or YYYY-MM")
+	invoicesGenerateAllCmd.Flags().String("prefix", "", "Invoice number prefix (defaults to invoice.number_prefix)")
+	invoicesGenerateAllCmd.Flags().Float64("tax", 0, "Single tax rate, 0.0 to 1.0 (defaults to invoice.taxes or invoice.default_tax_rate)")
+	addFooterFlags(invoicesGenerateAllCmd)
+
+	// Due date flags
+	addDueDateFlags(invoicesFinalizeCmd)
+	addDueDateFlags(invoicesSetDueCmd)
+	invoicesSetDueCmd.Flags().String("note", "", "Why the due date changed (required once the invoice has been sent)")
+
+	// Preview flags
+	invoicesPreviewCmd.Flags().String("client", "", "Client ID or name (required)")
+	invoicesPreviewCmd.Flags().String("start", "", "Period start date (required)")
+	invoicesPreviewCmd.Flags().String("end", "", "Period end date, inclusive (required)")
+	invoicesPreviewCmd.Flags().String("prefix", "", "Invoice number prefix (defaults to invoice.number_prefix)")
+	invoicesPreviewCmd.Flags().Float64("tax", 0, "Single tax rate (defaults to invoice.taxes or invoice.default_tax_rate)")
+	invoicesPreviewCmd.Flags().StringP("output", "o", "", "Write the draft to a file instead of stdout")
+	invoicesPreviewCmd.MarkFlagRequired("client")
+	invoicesPreviewCmd.MarkFlagRequired("start")
+	invoicesPreviewCmd.MarkFlagRequired("end")
+	addFooterFlags(invoicesPreviewCmd)
+
+	// Export flags
+	invoicesRenderCmd.Flags().String("format", render.DefaultExporter, "Document format: "+strings.Join(render.ExporterNames(), ", "))
+	invoicesExportCmd.Flags().String("format", "", "Document format: "+strings.Join(render.ExporterNames(), ", ")+" (default from --output, else txt)")
+	invoicesExportCmd.Flags().StringP("output", "o", "", "Write the invoice to a file instead of stdout")
+
+	// Export batch flags
+	invoicesExportBatchCmd.Flags().Int("year", 0, "Export the invoices dated in this year")
+	invoicesExportBatchCmd.Flags().String("start", "", "First invoice date to export (with --end)")
+	invoicesExportBatchCmd.Flags().String("end", "", "Last invoice date to export, inclusive (with --start)")
+	invoicesExportBatchCmd.Flags().String("format", render.DefaultExporter, "Document format: "+strings.Join(render.ExporterNames(), ", "))
+	invoicesExportBatchCmd.Flags().StringP("output", "o", "", "ZIP file to write (default invoices-<year>.zip)")
+	invoicesExportBatchCmd.Flags().Bool("drafts", false, "Include draft invoices")
+
+	// Notes flags
+	addFooterFlags(invoicesNotesCmd)
 
 	// Add entries flags
-	invoicesAddEntriesCmd.Flags().Float64("tax", 0, "Tax rate (0.0 to 1.0)")
+	invoicesAddEntriesCmd.Flags().Float64("tax", 0, "Single tax rate, 0.0 to 1.0 (defaults to invoice.taxes or invoice.default_tax_rate)")
+
+	// Delivery flags
+	invoicesMarkSentCmd.Flags().String("to", "", "Recipient (defaults to the client's email)")
+	invoicesMarkSentCmd.Flags().String("message-id", "", "Message ID from the mail provider, for matching receipts")
+	invoicesMarkSentCmd.Flags().String("at", "", "When it was sent (YYYY-MM-DD HH:MM, defaults to now)")
+	invoicesMarkOpenedCmd.Flags().String("message-id", "", "Find the delivery by the message ID it was sent with")
+	invoicesMarkOpenedCmd.Flags().String("at", "", "When it was opened (YYYY-MM-DD HH:MM, defaults to now)")
+
+	// Interest flags
+	invoicesInterestCmd.Flags().String("rate", "", "Interest rate, e.g. 8%/year or 1.5%/month (required)")
+	invoicesInterestCmd.Flags().String("as-of", "", "Charge interest up to this date if unpaid (defaults to today)")
+	invoicesInterestCmd.Flags().String("due", "", "Due date to charge from (defaults to the invoice's)")
+	invoicesInterestCmd.Flags().Int64("add-to", 0, "Add the interest to this draft invoice")
+	invoicesInterestCmd.Flags().Bool("follow-up", false, "Create a draft follow-up invoice for the interest")
+	invoicesInterestCmd.Flags().String("prefix", "", "Follow-up invoice number prefix (defaults to invoice.number_prefix)")
+	invoicesInterestCmd.MarkFlagRequired("rate")
+	addFooterFlags(invoicesInterestCmd)
 
 	// Mark paid flags
 	invoicesMarkPaidCmd.Flags().String("date", "", "Payment date (defaults to today)")
+
+	// Edit item flags
+	invoicesEditItemCmd.Flags().Bool("revert", false, "Restore the line's original description")
+}
+
+// writeInvoice prints an invoice with its line items and totals
+func writeInvoice(w io.Writer, invoice *domain.Invoice, clientName string, lineItems []*domain.InvoiceLineItem) {
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Invoice: %s\n", invoice.InvoiceNumber)
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+	fmt.Fprintf(w, "Client: %s\n", clientName)
+	fmt.Fprintf(w, "Period: %s to %s\n",
+		formatDate(invoice.PeriodStart),
+		formatDate(invoice.PeriodEnd),
+	)
+	fmt.Fprintf(w, "Status: %s\n", invoice.Status)
+	fmt.Fprintln(w)
+
+	// Print line items
+	if len(lineItems) > 0 {
+		fmt.Fprintln(w, "Line Items:")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+		fmt.Fprintf(w, "%-3s %-12s %-36s %-8s %-8s %s\n", "#", "Date", "Description", "Hours", "Rate", "Amount")
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+
+		for i, item := range lineItems {
+			fmt.Fprintf(w, "%-3d %-12s %-36s %8s %8s %9s\n",
+				i+1,
+				cliLocale().FormatShortDate(item.Date),
+				truncate(item.Description, 36),
+				formatInvoiceHours(item.Hours),
+				formatMoney(item.Rate),
+				formatMoney(item.Amount.Float()),
+			)
+			if item.Role != "" {
+				fmt.Fprintf(w, "%-16s + role: %s\n", "", item.Role)
+			}
+			if item.RateReason != "" {
+				fmt.Fprintf(w, "%-16s * rate: %s\n", "", item.RateReason)
+			}
+			if item.IsReworded() {
+				fmt.Fprintf(w, "%-16s ~ was: %s\n", "", item.OriginalDescription)
+			}
+		}
+		fmt.Fprintln(w, strings.Repeat("-", 80))
+	}
+
+	// Print totals
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "Subtotal: %s\n", formatMoney(invoice.Subtotal.Float()))
+	if len(invoice.Taxes) == 0 {
+		fmt.Fprintf(w, "Tax (%s%%): %s\n", cliLocale().Number(invoice.TaxRate*100, 1), formatMoney(invoice.TaxAmount.Float()))
+	}
+	for _, tax := range invoice.Taxes {
+		fmt.Fprintf(w, "%s (%s%%): %s\n", tax.Label, cliLocale().Number(tax.Rate*100, 1), formatMoney(tax.Amount.Float()))
+	}
+	fmt.Fprintf(w, "Total: %s\n", formatMoney(invoice.Total.Float()))
+	for _, tax := range invoice.Taxes {
+		if tax.Note != "" {
+			fmt.Fprintf(w, "\n%s\n", tax.Note)
+		}
+	}
+	if text := strings.TrimSpace(invoice.Footer.Notes); text != "" {
+		fmt.Fprintf(w, "\nNotes:\n%s\n", indentOrNone(text))
+	}
+	if text := strings.TrimSpace(invoice.Footer.PaymentInstructions); text != "" {
+		fmt.Fprintf(w, "\nPayment Instructions:\n%s\n", indentOrNone(text))
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 80))
+}
+
+// addDueDateFlags adds the flags for giving an invoice custom payment terms
+func addDueDateFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("due-days", 0, "Due this many days after the invoice date (defaults to invoice.default_due_days)")
+	cmd.Flags().String("due-date", "", "Due on this date")
+	cmd.MarkFlagsMutuallyExclusive("due-days", "due-date")
+}
+
+// dueDateFromFlags returns the due date the due date flags give for
+// invoice, or nil when neither was given
+func dueDateFromFlags(cmd *cobra.Command, invoice *domain.Invoice) (*time.Time, error) {
+	if cmd.Flags().Changed("due-date") {
+		due, err := parseDate(mustGetString(cmd, "due-date"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date: %w", err)
+		}
+		return &due, nil
+	}
+	if cmd.Flags().Changed("due-days") {
+		days, _ := cmd.Flags().GetInt("due-days")
+		if days < 0 {
+			return nil, fmt.Errorf("--due-days cannot be negative")
+		}
+		due := invoice.CreatedAt.AddDate(0, 0, days)
+		return &due, nil
+	}
+	return nil, nil
+}
+
+// addFooterFlags adds the flags for an invoice's notes and payment
+// instructions, printed at the bottom of the invoice
+func addFooterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("notes", "", "Notes printed at the bottom of the invoice (defaults to invoice.notes)")
+	cmd.Flags().String("payment-instructions", "", "Payment instructions such as bank details (defaults to user.payment_instructions)")
+}
+
+// invoiceFooter returns the configured notes and payment instructions,
+// overridden by any footer flags given
+func invoiceFooter(cmd *cobra.Command) domain.InvoiceFooter {
+	footer := appInstance.InvoiceFooter()
+	if cmd.Flags().Changed("notes") {
+		footer.Notes, _ = cmd.Flags().GetString("notes")
+	}
+	if cmd.Flags().Changed("payment-instructions") {
+		footer.PaymentInstructions, _ = cmd.Flags().GetString("payment-instructions")
+	}
+	return footer
+}
+
+// parsePeriod parses a month to invoice, returning its first day and its
+// last second in the local timezone
+func parsePeriod(s string, now time.Time) (time.Time, time.Time, error) {
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	var start time.Time
+	switch s {
+	case "this-month":
+		start = thisMonth
+	case "last-month":
+		start = thisMonth.AddDate(0, -1, 0)
+	default:
+		t, err := time.ParseInLocation("2006-01", s, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("expected format: YYYY-MM, 'this-month', or 'last-month'")
+		}
+		start = t
+	}
+	return start, start.AddDate(0, 1, 0).Add(-time.Second), nil
+}
+
+// indentOrNone indents each line of text, or shows (none) when empty
+func indentOrNone(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "  (none)"
+	}
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
+
+// invoiceTaxes returns the tax lines for an invoice to client: a single
+// line when --tax is given, otherwise the configured taxes
+func invoiceTaxes(cmd *cobra.Command, client *domain.Client) []*domain.InvoiceTax {
+	if !cmd.Flags().Changed("tax") {
+		return appInstance.InvoiceTaxes(client)
+	}
+	taxRate, _ := cmd.Flags().GetFloat64("tax")
+	if taxRate == 0 {
+		return []*domain.InvoiceTax{}
+	}
+	return []*domain.InvoiceTax{{Label: "Tax", Rate: taxRate}}
+}
+
+// invoiceTaxesForDraft returns the tax lines for a draft invoice's client
+func invoiceTaxesForDraft(ctx context.Context, cmd *cobra.Command, invoiceID int64) ([]*domain.InvoiceTax, error) {
+	invoice, err := appInstance.InvoiceService.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	if invoice == nil {
+		return nil, fmt.Errorf("invoice not found")
+	}
+	client, err := appInstance.ClientRepo.GetByID(ctx, invoice.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client: %w", err)
+	}
+	return invoiceTaxes(cmd, client), nil
+}
+
+// formatInvoiceHours formats line item hours using invoice.hour_format
+func formatInvoiceHours(hours float64) string {
+	if appInstance != nil && appInstance.Config != nil && appInstance.Config.Invoice.HourFormat == config.HourFormatDecimal {
+		return cliLocale().Number(hours, 2)
+	}
+	if hours < 0 {
+		return "-" + formatInvoiceHours(-hours)
+	}
+	h := int(hours)
+	m := int(math.Round((hours - float64(h)) * 60))
+	if m == 60 {
+		h, m = h+1, 0
+	}
+	return fmt.Sprintf("%dh %dm", h, m)
+}
+
+// deliverySummary describes who a delivery went to and whether it was opened
+func deliverySummary(d *domain.InvoiceDelivery) string {
+	recipient := d.Recipient
+	if recipient == "" {
+		recipient = "(no recipient)"
+	}
+	if d.MessageID != "" {
+		recipient += " [" + d.MessageID + "]"
+	}
+	if d.OpenedAt != nil {
+		return recipient + ", opened " + d.OpenedAt.Format("2006-01-02 15:04")
+	}
+	return recipient + ", not opened"
 }
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/service"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// lintReason is recorded in the history of entries changed by entries lint
+const lintReason = "fixed by entries lint"
+
+var entriesLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Find unbilled entries with data-quality issues",
+	Long: `Lint checks unbilled entries for problems that end up on invoices:
+entries without a description, entries with no duration, entries longer
+than 12 hours (usually a timer left running overnight), and billable
+entries at a rate of $0.
+
+Use --fix to go through the entries one by one and describe, shorten,
+delete or re-rate them. Every change is recorded in the entry's history.
+Lint exits with status 1 while issues remain, so it can gate a script.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var clientID *int64
+		if cmd.Flags().Changed("client") {
+			id, err := resolveClientID(ctx, mustGetString(cmd, "client"))
+			if err != nil {
+				return fmt.Errorf("failed to resolve client: %w", err)
+			}
+			clientID = &id
+		}
+
+		var start, end *time.Time
+		if cmd.Flags().Changed("start") {
+			t, err := parseDate(mustGetString(cmd, "start"))
+			if err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+			start = &t
+		}
+		if cmd.Flags().Changed("end") {
+			t, err := parseDate(mustGetString(cmd, "end"))
+			if err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+			// Include entries started on the end date
+			t = t.AddDate(0, 0, 1).Add(-time.Second)
+			end = &t
+		}
+
+		entries, err := appInstance.EntryRepo.List(ctx, clientID, start, end, false)
+		if err != nil {
+			return fmt.Errorf("failed to list entries: %w", err)
+		}
+		lints := service.LintEntries(entries)
+		if len(lints) == 0 {
+			fmt.Println("✓ No issues found")
+			return nil
+		}
+
+		printEntryLints(ctx, lints)
+
+		if fix, _ := cmd.Flags().GetBool("fix"); fix {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return fmt.Errorf("--fix asks about each entry and needs a terminal")
+			}
+			remaining := 0
+			for _, lint := range lints {
+				fixed, err := fixEntryLint(ctx, lint)
+				if err != nil {
+					return err
+				}
+				if !fixed {
+					remaining++
+				}
+			}
+			if remaining == 0 {
+				fmt.Println("\n✓ All issues fixed")
+				return nil
+			}
+			fmt.Printf("\n! %d entries still have issues\n", remaining)
+		}
+
+		exitCode = 1
+		return nil
+	},
+}
+
+// printEntryLints lists entries with their data-quality issues
+func printEntryLints(ctx context.Context, lints []*service.EntryLint) {
+	fmt.Printf("%-5s %-15s %-20s %-10s %s\n", "ID", "Client", "Date", "Duration", "Issues")
+	fmt.Println("--------------------------------------------------------------------------------")
+	for _, lint := range lints {
+		e := lint.Entry
+		fmt.Printf("%-5d %-15s %-20s %-10s %s\n",
+			e.ID,
+			truncate(clientName(ctx, e.ClientID), 15),
+			formatDate(e.StartTime)+e.StartTime.Format(" 15:04"),
+			formatDuration(e.Duration()),
+			joinLintIssues(lint.Issues),
+		)
+	}
+	fmt.Println("--------------------------------------------------------------------------------")
+	fmt.Printf("%d entries with issues\n", len(lints))
+}
+
+func joinLintIssues(issues []service.LintIssue) string {
+	names := make([]string, len(issues))
+	for i, issue := range issues {
+		names[i] = string(issue)
+	}
+	return strings.Join(names, ", ")
+}
+
+// fixEntryLint asks how to fix each issue on an entry and saves the answers.
+// It reports whether no issues are left.
+func fixEntryLint(ctx context.Context, lint *service.EntryLint) (bool, error) {
+	e := lint.Entry
+	fmt.Printf("\n#%d %s, %s, %s\n", e.ID, clientName(ctx, e.ClientID),
+		e.StartTime.Format("2006-01-02 15:04"), formatDuration(e.Duration()))
+	if e.Description != "" {
+		fmt.Printf("  %s\n", e.Description)
+	}
+
+	if lint.Has(service.LintZeroDuration) && confirmPrompt("  Delete this empty entry?") {
+		if err := appInstance.EntryRepo.SoftDelete(ctx, e.ID, lintReason); err != nil {
+			return false, fmt.Errorf("failed to delete entry: %w", err)
+		}
+		fmt.Printf("  ✓ Entry deleted (ID: %d)\n", e.ID)
+		return true, nil
+	}
+
+	changed := false
+	if lint.Has(service.LintNoDescription) {
+		if desc := promptLine("  Description (blank to skip):"); desc != "" {
+			e.Description = desc
+			changed = true
+		}
+	}
+	if lint.Has(service.LintZeroDuration) || lint.Has(service.LintLongDuration) {
+		for {
+			input := promptLine("  End time (HH:MM or YYYY-MM-DD HH:MM, blank to skip):")
+			if input == "" {
+				break
+			}
+			at, err := parseSplitTime(input, e.StartTime)
+			if err == nil && at.After(e.StartTime) {
+				e.Stop(at)
+				changed = true
+				break
+			}
+			fmt.Println("  ! End time must be after the start")
+		}
+	}
+	if lint.Has(service.LintZeroRate) {
+		clientRate, err := appInstance.ClientRepo.RateAt(ctx, e.ClientID, e.StartTime)
+		if err != nil {
+			return false, fmt.Errorf("failed to get client rate: %w", err)
+		}
+		if clientRate > 0 && confirmPrompt(fmt.Sprintf("  Bill at the client's rate of %s/h?", formatMoney(clientRate))) {
+			_ = e.SetRate(clientRate, clientRate, "")
+			changed = true
+		} else if confirmPrompt("  Mark as non-billable?") {
+			e.IsBillable = false
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := e.Validate(); err != nil {
+			return false, fmt.Errorf("invalid entry: %w", err)
+		}
+		if err := appInstance.EntryRepo.Update(ctx, e, lintReason); err != nil {
+			return false, fmt.Errorf("failed to update entry: %w", err)
+		}
+		fmt.Printf("  ✓ Entry updated (ID: %d)\n", e.ID)
+	}
+	return len(service.LintEntries([]*domain.TimeEntry{e})) == 0, nil
+}
+
+// promptLine asks for a line of input, returning it trimmed
+func promptLine(message string) string {
+	fmt.Printf("%s ", message)
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(input)
+}
+
+// warnEntryLint points out entries about to be invoiced with data-quality
+// issues, when invoice.lint_entries is set
+func warnEntryLint(entries []*domain.TimeEntry) {
+	if !appInstance.Config.Invoice.LintEntries {
+		return
+	}
+	lints := service.LintEntries(entries)
+	if len(lints) == 0 {
+		return
+	}
+	fmt.Printf("! %d entries have data-quality issues (fix with timesink entries lint --fix):\n", len(lints))
+	for _, lint := range lints {
+		fmt.Printf("  %-5d %s\n", lint.Entry.ID, joinLintIssues(lint.Issues))
+	}
+}
+
+// lineItemEntries returns the entries billed by items, in their order.
+// Drafts only lock their entries when finalized, so this is how to find them.
+func lineItemEntries(ctx context.Context, items []*domain.InvoiceLineItem) ([]*domain.TimeEntry, error) {
+	var ids []int64
+	for _, item := range items {
+		if item.EntryID != 0 {
+			ids = append(ids, item.EntryID)
+		}
+	}
+	byID, err := appInstance.EntryRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries: %w", err)
+	}
+	entries := make([]*domain.TimeEntry, 0, len(byID))
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func init() {
+	entriesCmd.AddCommand(entriesLintCmd)
+
+	entriesLintCmd.Flags().String("client", "", "Only this client's entries (ID or name)")
+	entriesLintCmd.Flags().String("start", "", "Only entries from this date (YYYY-MM-DD or 'today')")
+	entriesLintCmd.Flags().String("end", "", "Only entries up to this date (YYYY-MM-DD or 'today')")
+	entriesLintCmd.Flags().Bool("fix", false, "Go through the entries and fix them")
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock timesink until the passphrase is entered again",
+	Long: `Lock the current profile. Until the database passphrase is entered again,
+CLI commands ask for it before running and the TUI hides everything behind a
+lock screen, including any TUI that is already open.
+
+Set security.auto_lock_minutes to lock the TUI automatically after a period
+of inactivity.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := appInstance.Lock(); err != nil {
+			return err
+		}
+		fmt.Println("🔒 Locked")
+		return nil
+	},
+}
+
+// requireUnlocked asks for the passphrase before a command runs in a locked
+// session. The TUI shows its own lock screen instead.
+func requireUnlocked(cmd *cobra.Command, args []string) error {
+	if appInstance == nil || !appInstance.IsLocked() {
+		return nil
+	}
+	if !cmd.HasParent() || cmd == tuiCmd || cmd == lockCmd {
+		return nil
+	}
+	// A locked session is not a usage mistake
+	cmd.SilenceUsage = true
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return errors.New("timesink is locked: run a command from a terminal to enter the passphrase")
+	}
+
+	fmt.Fprint(os.Stderr, "🔒 timesink is locked. Passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	if err := appInstance.Unlock(string(passphrase)); err != nil {
+		if errors.Is(err, app.ErrWrongPassphrase) {
+			return errors.New("wrong passphrase; timesink is still locked")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// decimalValue replaces the value of a float64 flag so it accepts "1,5" as
+// well as "1.5", read with the configured locale. It keeps the float64 type,
+// so the flag is still read with GetFloat64.
+type decimalValue float64
+
+func (v *decimalValue) Set(s string) error {
+	f, err := cliLocale().ParseNumber(s)
+	if err != nil {
+		return err
+	}
+	*v = decimalValue(f)
+	return nil
+}
+
+func (v *decimalValue) String() string {
+	return strconv.FormatFloat(float64(*v), 'g', -1, 64)
+}
+
+func (v *decimalValue) Type() string {
+	return "float64"
+}
+
+// acceptDecimalCommas makes every float64 flag of cmd and its subcommands
+// accept a comma as the decimal separator
+func acceptDecimalCommas(cmd *cobra.Command) {
+	replace := func(f *pflag.Flag) {
+		if f.Value.Type() != "float64" {
+			return
+		}
+		if _, ok := f.Value.(*decimalValue); ok {
+			return
+		}
+		def, _ := strconv.ParseFloat(f.Value.String(), 64)
+		v := decimalValue(def)
+		f.Value = &v
+	}
+	cmd.Flags().VisitAll(replace)
+	cmd.PersistentFlags().VisitAll(replace)
+	for _, sub := range cmd.Commands() {
+		acceptDecimalCommas(sub)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/crypto"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage profiles",
+	Long: `Manage profiles: separate sets of config, database, and encryption key,
+e.g. one for an LLC and one for personal freelancing.
+
+The profile in use is chosen by --profile, then TIMESINK_PROFILE, then the
+profile selected with 'timesink profile switch'. The "default" profile uses
+the timesink config, data, and state directories directly; others use a
+profiles/<name> subdirectory of each.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := config.ListProfiles()
+		if err != nil {
+			return err
+		}
+
+		flag, _ := cmd.Flags().GetString("profile")
+		active := cmdProfile(cmd)
+
+		for _, name := range profiles {
+			marker := "  "
+			if name == active {
+				marker = "* "
+			}
+			fmt.Printf("%s%-20s %s\n", marker, name, config.ProfileDir(name))
+		}
+
+		if env := os.Getenv(config.ProfileEnv); flag == "" && env != "" {
+			fmt.Printf("\n%s=%s overrides the switched profile (%s)\n", config.ProfileEnv, env, config.CurrentProfile())
+		}
+		return nil
+	},
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.CreateProfile(name); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Created profile %q in %s\n", name, config.ProfileDir(name))
+		fmt.Printf("  Its database is encrypted with its own key (%s where no keyring is available).\n", crypto.KeyEnvFor(name))
+		fmt.Printf("  Use it with --profile %s, or make it the default with 'timesink profile switch %s'.\n", name, name)
+		return nil
+	},
+}
+
+var profileSwitchCmd = &cobra.Command{
+	Use:   "switch <name>",
+	Short: "Make a profile the default",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.SetCurrentProfile(name); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Switched to profile %q\n", name)
+		if env := os.Getenv(config.ProfileEnv); env != "" && env != name {
+			fmt.Printf("  Note: %s=%s is set and takes precedence in this shell\n", config.ProfileEnv, env)
+		}
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileSwitchCmd)
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var clientsRateCardCmd = &cobra.Command{
+	Use:   "rate-card [client_id_or_name]",
+	Short: "Show a client's rate card",
+	Long: `A rate card names the rates a client pays for different kinds of work,
+e.g. "development" at one rate and "consulting" at another. Start a timer or
+add an entry with --role to bill it at the role's rate instead of the
+client's hourly rate; the role is shown on the invoice line item.
+
+Changing a role's rate affects entries logged from then on. Entries already
+logged keep the rate they were billed at.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		card, err := appInstance.ClientRepo.ListRoleRates(ctx, clientID)
+		if err != nil {
+			return fmt.Errorf("failed to list rate card: %w", err)
+		}
+
+		if len(card) == 0 {
+			fmt.Println("No roles on the rate card")
+			return nil
+		}
+
+		fmt.Printf("%-20s %-15s\n", "Role", "Hourly Rate")
+		fmt.Println("----------------------------------------")
+		for _, role := range card {
+			fmt.Printf("%-20s %-15s\n", truncate(role.Role, 20), formatMoney(role.HourlyRate))
+		}
+
+		return nil
+	},
+}
+
+var clientsSetRoleCmd = &cobra.Command{
+	Use:   "set [client_id_or_name] [role] [rate]",
+	Short: "Add a role to a client's rate card, or change its rate",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		rate, err := cliLocale().ParseNumber(args[2])
+		if err != nil {
+			return fmt.Errorf("invalid rate: %w", err)
+		}
+
+		role := &domain.RoleRate{ClientID: clientID, Role: args[1], HourlyRate: rate}
+		if err := appInstance.ClientRepo.SetRoleRate(ctx, role); err != nil {
+			return fmt.Errorf("failed to set role rate: %w", err)
+		}
+
+		fmt.Printf("✓ %s: %s/h for %s\n", role.Role, formatMoney(role.HourlyRate), clientName(ctx, clientID))
+		return nil
+	},
+}
+
+var clientsRemoveRoleCmd = &cobra.Command{
+	Use:   "remove [client_id_or_name] [role]",
+	Short: "Take a role off a client's rate card",
+	Long: `Take a role off a client's rate card. Entries already logged for the
+role keep their rate; a running timer for it is billed at the client's rate.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		if err := appInstance.ClientRepo.DeleteRoleRate(ctx, clientID, args[1]); err != nil {
+			return fmt.Errorf("failed to remove role: %w", err)
+		}
+
+		fmt.Printf("✓ Role %s removed from %s's rate card\n", strings.TrimSpace(args[1]), clientName(ctx, clientID))
+		return nil
+	},
+}
+
+func init() {
+	clientsCmd.AddCommand(clientsRateCardCmd)
+	clientsRateCardCmd.AddCommand(clientsSetRoleCmd)
+	clientsRateCardCmd.AddCommand(clientsRemoveRoleCmd)
+}
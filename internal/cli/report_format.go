@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// reportFormat is how a report is written out
+type reportFormat string
+
+const (
+	formatTable reportFormat = "table"
+	formatCSV   reportFormat = "csv"
+	formatJSON  reportFormat = "json"
+)
+
+// tabularReport is a report that flattens to a single table for CSV: a
+// header row, then one row per line of the report. Totals are left out so
+// spreadsheets can sum the columns themselves.
+type tabularReport interface {
+	csvHeader() []string
+	csvRows() [][]string
+}
+
+// reportFormatFlag returns the format chosen with --format, with --json as
+// shorthand for --format json
+func reportFormatFlag(cmd *cobra.Command) (reportFormat, error) {
+	if v, _ := cmd.Flags().GetBool("json"); v {
+		return formatJSON, nil
+	}
+	value, _ := cmd.Flags().GetString("format")
+	switch format := reportFormat(value); format {
+	case formatTable, formatCSV, formatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q (expected table, csv, or json)", value)
+}
+
+// writeReport writes report in the format chosen on the command line. JSON
+// encodes the report as is and CSV writes its table; the text table is left
+// to printTable, since each report lays it out differently.
+func writeReport(cmd *cobra.Command, report tabularReport, printTable func()) error {
+	format, err := reportFormatFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		return printJSON(report)
+	case formatCSV:
+		return printCSV(report)
+	}
+	printTable()
+	return nil
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printCSV writes the report's table to stdout
+func printCSV(report tabularReport) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(report.csvHeader()); err != nil {
+		return err
+	}
+	if err := w.WriteAll(report.csvRows()); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// csvNumber formats hours and amounts for CSV: plain, unlocalized, two
+// decimal places
+func csvNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,999 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var reportsCmd = &cobra.Command{
+	Use:   "reports",
+	Short: "Show time and revenue reports",
+	Long:  `Show weekly, monthly, per-client, revenue, aging, and working-hours reports as tables, CSV, or JSON.`,
+}
+
+// clientHoursRow is one client's line in a week or month report
+type clientHoursRow struct {
+	ClientID      int64   `json:"client_id"`
+	Client        string  `json:"client"`
+	Hours         float64 `json:"hours"`
+	BillableHours float64 `json:"billable_hours"`
+	Value         float64 `json:"value"`
+}
+
+type dayHoursRow struct {
+	Date  string  `json:"date"`
+	Day   string  `json:"day"`
+	Hours float64 `json:"hours"`
+	Off   string  `json:"off,omitempty"` // vacation, sick, or holiday
+}
+
+type weekReport struct {
+	WeekStart     string           `json:"week_start"`
+	TotalHours    float64          `json:"total_hours"`
+	BillableHours float64          `json:"billable_hours"`
+	TotalValue    float64          `json:"total_value"`
+	Utilization   float64          `json:"utilization"` // billable share of hours on working days, in percent
+	Days          []dayHoursRow    `json:"days"`
+	Clients       []clientHoursRow `json:"clients"`
+}
+
+func (r weekReport) csvHeader() []string {
+	return []string{"date", "day", "hours", "off"}
+}
+
+func (r weekReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Days))
+	for _, d := range r.Days {
+		rows = append(rows, []string{d.Date, d.Day, csvNumber(d.Hours), d.Off})
+	}
+	return rows
+}
+
+type monthReport struct {
+	Month         string           `json:"month"`
+	TotalHours    float64          `json:"total_hours"`
+	BillableHours float64          `json:"billable_hours"`
+	TotalValue    float64          `json:"total_value"`
+	Unbilled      float64          `json:"unbilled"`
+	Outstanding   float64          `json:"outstanding"`
+	Clients       []clientHoursRow `json:"clients"`
+}
+
+func (r monthReport) csvHeader() []string {
+	return clientHoursHeader
+}
+
+func (r monthReport) csvRows() [][]string {
+	return clientHoursRecords(r.Clients)
+}
+
+// clientHoursHeader is the CSV header for a list of clientHoursRow
+var clientHoursHeader = []string{"client_id", "client", "hours", "billable_hours", "value"}
+
+// clientHoursRecords flattens client rows for CSV
+func clientHoursRecords(clients []clientHoursRow) [][]string {
+	rows := make([][]string, 0, len(clients))
+	for _, c := range clients {
+		rows = append(rows, []string{
+			strconv.FormatInt(c.ClientID, 10), c.Client,
+			csvNumber(c.Hours), csvNumber(c.BillableHours), csvNumber(c.Value),
+		})
+	}
+	return rows
+}
+
+type clientEntryRow struct {
+	ID          int64   `json:"id"`
+	Date        string  `json:"date"`
+	Hours       float64 `json:"hours"`
+	Value       float64 `json:"value"`
+	Billable    bool    `json:"billable"`
+	Invoiced    bool    `json:"invoiced"`
+	Description string  `json:"description"`
+}
+
+type clientReport struct {
+	ClientID      int64            `json:"client_id"`
+	Client        string           `json:"client"`
+	Start         string           `json:"start"`
+	End           string           `json:"end"`
+	TotalHours    float64          `json:"total_hours"`
+	BillableHours float64          `json:"billable_hours"`
+	TotalValue    float64          `json:"total_value"`
+	UnbilledValue float64          `json:"unbilled_value"`
+	Entries       []clientEntryRow `json:"entries"`
+}
+
+func (r clientReport) csvHeader() []string {
+	return []string{"id", "date", "hours", "value", "billable", "invoiced", "description"}
+}
+
+func (r clientReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Entries))
+	for _, e := range r.Entries {
+		rows = append(rows, []string{
+			strconv.FormatInt(e.ID, 10), e.Date, csvNumber(e.Hours), csvNumber(e.Value),
+			strconv.FormatBool(e.Billable), strconv.FormatBool(e.Invoiced), e.Description,
+		})
+	}
+	return rows
+}
+
+type monthRevenueRow struct {
+	Month   string  `json:"month"`
+	Revenue float64 `json:"revenue"`
+}
+
+type revenueReport struct {
+	Year   int               `json:"year"`
+	Basis  string            `json:"basis"`
+	Total  float64           `json:"total"`
+	Months []monthRevenueRow `json:"months"`
+}
+
+func (r revenueReport) csvHeader() []string {
+	return []string{"month", "revenue"}
+}
+
+func (r revenueReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Months))
+	for _, m := range r.Months {
+		rows = append(rows, []string{m.Month, csvNumber(m.Revenue)})
+	}
+	return rows
+}
+
+var reportsWeekCmd = &cobra.Command{
+	Use:   "week",
+	Short: "Show hours by day and client for a week",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		date := time.Now()
+		if cmd.Flags().Changed("date") {
+			dateStr, _ := cmd.Flags().GetString("date")
+			t, err := parseDate(dateStr)
+			if err != nil {
+				return fmt.Errorf("invalid date: %w", err)
+			}
+			date = t
+		}
+		weekStart := weekMonday(date)
+
+		summary, err := appInstance.ReportService.GetWeekSummary(ctx, weekStart)
+		if err != nil {
+			return fmt.Errorf("failed to get week summary: %w", err)
+		}
+
+		report := weekReport{
+			WeekStart:     weekStart.Format("2006-01-02"),
+			TotalHours:    summary.TotalHours,
+			BillableHours: summary.BillableHours,
+			TotalValue:    summary.TotalValue.Float(),
+			Utilization:   summary.Utilization(),
+			Clients:       []clientHoursRow{},
+		}
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			report.Days = append(report.Days, dayHoursRow{
+				Date:  day.Format("2006-01-02"),
+				Day:   day.Weekday().String()[:3],
+				Hours: summary.ByDay[day.Weekday()],
+				Off:   string(summary.DaysOff[day.Weekday()]),
+			})
+		}
+		for clientID, hours := range summary.ByClient {
+			report.Clients = append(report.Clients, clientHoursRow{
+				ClientID:      clientID,
+				Client:        clientName(ctx, clientID),
+				Hours:         hours,
+				BillableHours: summary.BillableByClient[clientID],
+				Value:         summary.ValueByClient[clientID].Float(),
+			})
+		}
+		sortClientRows(report.Clients)
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Week of %s\n\n", formatDate(weekStart))
+			fmt.Printf("%-5s %-12s %8s\n", "Day", "Date", "Hours")
+			fmt.Println("----------------------------")
+			for _, d := range report.Days {
+				day, _ := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+				fmt.Printf("%-5s %-12s %8s", d.Day, cliLocale().FormatShortDate(day), formatHours(d.Hours))
+				if d.Off != "" {
+					fmt.Printf("  %s", d.Off)
+				}
+				fmt.Println()
+			}
+			fmt.Println()
+			printClientRows(report.Clients)
+			fmt.Printf("Total: %s hours (%s billable), %s\n",
+				formatHours(report.TotalHours), formatHours(report.BillableHours), formatMoney(report.TotalValue))
+			fmt.Printf("Utilization: %.0f%% (days off left out)\n", report.Utilization)
+		})
+	},
+}
+
+var reportsMonthCmd = &cobra.Command{
+	Use:   "month",
+	Short: "Show hours and value by client for a month",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		now := time.Now()
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		if cmd.Flags().Changed("month") {
+			monthStr, _ := cmd.Flags().GetString("month")
+			t, err := time.ParseInLocation("2006-01", monthStr, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid month %q (use YYYY-MM)", monthStr)
+			}
+			start = t
+		}
+		end := start.AddDate(0, 1, 0)
+
+		clients, err := appInstance.ClientRepo.List(ctx, true)
+		if err != nil {
+			return fmt.Errorf("failed to list clients: %w", err)
+		}
+
+		report := monthReport{Month: start.Format("2006-01"), Clients: []clientHoursRow{}}
+		var totalValue domain.Money
+		for _, client := range clients {
+			summary, err := appInstance.ReportService.GetClientSummary(ctx, client.ID, start, end)
+			if err != nil {
+				return fmt.Errorf("failed to get summary for %s: %w", client.Name, err)
+			}
+			if len(summary.Entries) == 0 {
+				continue
+			}
+			report.Clients = append(report.Clients, clientHoursRow{
+				ClientID:      client.ID,
+				Client:        client.Name,
+				Hours:         summary.TotalHours,
+				BillableHours: summary.BillableHours,
+				Value:         summary.TotalValue.Float(),
+			})
+			report.TotalHours += summary.TotalHours
+			report.BillableHours += summary.BillableHours
+			totalValue += summary.TotalValue
+		}
+		sortClientRows(report.Clients)
+		report.TotalValue = totalValue.Float()
+
+		unbilled, err := appInstance.ReportService.GetUnbilledTotal(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get unbilled total: %w", err)
+		}
+		outstanding, err := appInstance.ReportService.GetOutstandingTotal(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get outstanding total: %w", err)
+		}
+		report.Unbilled, report.Outstanding = unbilled.Float(), outstanding.Float()
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("%s\n\n", start.Format("January 2006"))
+			printClientRows(report.Clients)
+			fmt.Printf("Total: %s hours (%s billable), %s\n",
+				formatHours(report.TotalHours), formatHours(report.BillableHours), formatMoney(report.TotalValue))
+			fmt.Printf("Unbilled (all time): %s\n", formatMoney(report.Unbilled))
+			fmt.Printf("Outstanding invoices: %s\n", formatMoney(report.Outstanding))
+		})
+	},
+}
+
+var reportsClientCmd = &cobra.Command{
+	Use:   "client <client_id_or_name>",
+	Short: "Show a client's hours, value, and entries for a date range",
+	Long: `Show a client's hours, value, and entries for a date range.
+
+--start defaults to the first of the current month and --end to today.
+Both dates are inclusive.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+
+		now := time.Now()
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		if cmd.Flags().Changed("start") {
+			startStr, _ := cmd.Flags().GetString("start")
+			if start, err = parseDate(startStr); err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+		}
+		if cmd.Flags().Changed("end") {
+			endStr, _ := cmd.Flags().GetString("end")
+			if end, err = parseDate(endStr); err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+		}
+		if end.Before(start) {
+			return fmt.Errorf("end date is before start date")
+		}
+
+		summary, err := appInstance.ReportService.GetClientSummary(ctx, clientID, start, end.AddDate(0, 0, 1))
+		if err != nil {
+			return fmt.Errorf("failed to get client summary: %w", err)
+		}
+
+		report := clientReport{
+			ClientID:      clientID,
+			Client:        clientName(ctx, clientID),
+			Start:         start.Format("2006-01-02"),
+			End:           end.Format("2006-01-02"),
+			TotalHours:    summary.TotalHours,
+			BillableHours: summary.BillableHours,
+			TotalValue:    summary.TotalValue.Float(),
+			UnbilledValue: summary.UnbilledValue.Float(),
+			Entries:       []clientEntryRow{},
+		}
+		for _, entry := range summary.Entries {
+			report.Entries = append(report.Entries, clientEntryRow{
+				ID:          entry.ID,
+				Date:        entry.StartTime.Format("2006-01-02"),
+				Hours:       entry.Duration().Hours(),
+				Value:       entry.Amount().Float(),
+				Billable:    entry.IsBillable,
+				Invoiced:    entry.InvoiceID != nil,
+				Description: entry.Description,
+			})
+		}
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("%s: %s - %s\n\n", report.Client, formatDate(start), formatDate(end))
+			if len(summary.Entries) == 0 {
+				fmt.Println("No entries found")
+				return
+			}
+
+			fmt.Printf("%-5s %-14s %8s %12s %-8s %s\n", "ID", "Date", "Hours", "Value", "Status", "Description")
+			fmt.Println("--------------------------------------------------------------------------------")
+			for _, entry := range summary.Entries {
+				status := "Unbilled"
+				if !entry.IsBillable {
+					status = "Non-bill"
+				} else if entry.InvoiceID != nil {
+					status = "Invoiced"
+				}
+				fmt.Printf("%-5d %-14s %8s %12s %-8s %s\n",
+					entry.ID,
+					formatDate(entry.StartTime),
+					formatHours(entry.Duration().Hours()),
+					formatMoney(entry.Amount().Float()),
+					status,
+					truncate(entry.Description, 30),
+				)
+			}
+			fmt.Println("--------------------------------------------------------------------------------")
+			fmt.Printf("Total: %s hours (%s billable), %s (%s unbilled)\n",
+				formatHours(report.TotalHours), formatHours(report.BillableHours),
+				formatMoney(report.TotalValue), formatMoney(report.UnbilledValue))
+		})
+	},
+}
+
+type agingRow struct {
+	ClientID int64   `json:"client_id,omitempty"`
+	Client   string  `json:"client"`
+	Days0    float64 `json:"days_0_30"`
+	Days31   float64 `json:"days_31_60"`
+	Days61   float64 `json:"days_61_90"`
+	Days90   float64 `json:"days_90_plus"`
+	Total    float64 `json:"total"`
+}
+
+type agingReport struct {
+	AsOf    string     `json:"as_of"`
+	Clients []agingRow `json:"clients"`
+	Total   agingRow   `json:"total"`
+}
+
+func (r agingReport) csvHeader() []string {
+	return []string{"client_id", "client", "days_0_30", "days_31_60", "days_61_90", "days_90_plus", "total"}
+}
+
+func (r agingReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Clients))
+	for _, c := range r.Clients {
+		rows = append(rows, []string{
+			strconv.FormatInt(c.ClientID, 10), c.Client,
+			csvNumber(c.Days0), csvNumber(c.Days31), csvNumber(c.Days61), csvNumber(c.Days90),
+			csvNumber(c.Total),
+		})
+	}
+	return rows
+}
+
+var reportsAgingCmd = &cobra.Command{
+	Use:   "aging",
+	Short: "Show receivables by client and days past due",
+	Long: `Aging groups sent and overdue invoices by client and by how many days
+past their due date they are: 0-30, 31-60, 61-90 and over 90. Invoices that
+are not yet due count as 0-30. Invoices without a due date are due
+invoice.default_due_days after they were created.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		asOf := time.Now()
+		if value, _ := cmd.Flags().GetString("as-of"); value != "" {
+			var err error
+			if asOf, err = parseDate(value); err != nil {
+				return fmt.Errorf("invalid as-of date: %w", err)
+			}
+		}
+
+		aging, err := appInstance.ReportService.GetAging(ctx, asOf, appInstance.Config.Invoice.DefaultDueDays)
+		if err != nil {
+			return fmt.Errorf("failed to get aging: %w", err)
+		}
+
+		report := agingReport{
+			AsOf:  asOf.Format("2006-01-02"),
+			Total: newAgingRow(0, "Total", aging.Buckets, aging.Total),
+		}
+		for _, row := range aging.Clients {
+			report.Clients = append(report.Clients,
+				newAgingRow(row.ClientID, clientName(ctx, row.ClientID), row.Buckets, row.Total))
+		}
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Receivables aging as of %s\n\n", formatDate(asOf))
+			if len(report.Clients) == 0 {
+				fmt.Println("No outstanding invoices")
+				return
+			}
+			fmt.Printf("%-20s %12s %12s %12s %12s %12s\n", "Client", "0-30", "31-60", "61-90", "90+", "Total")
+			fmt.Println("------------------------------------------------------------------------------------")
+			for _, row := range report.Clients {
+				printAgingRow(row)
+			}
+			fmt.Println("------------------------------------------------------------------------------------")
+			printAgingRow(report.Total)
+		})
+	},
+}
+
+// newAgingRow flattens a client's aging buckets for printing
+func newAgingRow(clientID int64, client string, buckets [4]domain.Money, total domain.Money) agingRow {
+	return agingRow{
+		ClientID: clientID,
+		Client:   client,
+		Days0:    buckets[0].Float(),
+		Days31:   buckets[1].Float(),
+		Days61:   buckets[2].Float(),
+		Days90:   buckets[3].Float(),
+		Total:    total.Float(),
+	}
+}
+
+// printAgingRow prints one line of the aging table
+func printAgingRow(row agingRow) {
+	fmt.Printf("%-20s %12s %12s %12s %12s %12s\n",
+		truncate(row.Client, 20),
+		formatMoney(row.Days0),
+		formatMoney(row.Days31),
+		formatMoney(row.Days61),
+		formatMoney(row.Days90),
+		formatMoney(row.Total),
+	)
+}
+
+type heatmapDay struct {
+	Day   string      `json:"day"`
+	Hours [24]float64 `json:"hours"`
+	Total float64     `json:"total"`
+}
+
+type heatmapReport struct {
+	Start string       `json:"start"`
+	End   string       `json:"end"`
+	Total float64      `json:"total"`
+	Days  []heatmapDay `json:"days"`
+}
+
+func (r heatmapReport) csvHeader() []string {
+	header := []string{"day"}
+	for hour := 0; hour < 24; hour++ {
+		header = append(header, fmt.Sprintf("%02d:00", hour))
+	}
+	return append(header, "total")
+}
+
+func (r heatmapReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Days))
+	for _, d := range r.Days {
+		row := []string{d.Day}
+		for _, hours := range d.Hours {
+			row = append(row, csvNumber(hours))
+		}
+		rows = append(rows, append(row, csvNumber(d.Total)))
+	}
+	return rows
+}
+
+// heatmapShades draws a heatmap cell for each level, from no time to the busiest
+var heatmapShades = [5]string{" ·", "░░", "▒▒", "▓▓", "██"}
+
+var reportsHeatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Show which hours of the day and days of the week you work",
+	Long: `Show a heatmap of time tracked by day of the week and hour of the day.
+
+Each entry is spread over the clock hours it ran, so a 9:30-11:15 entry adds
+half an hour at 9, an hour at 10 and a quarter hour at 11. Darker cells hold
+more time, relative to the busiest hour.
+
+--start defaults to four weeks ago and --end to today. Both dates are inclusive.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		now := time.Now()
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		start := end.AddDate(0, 0, -27)
+		var err error
+		if cmd.Flags().Changed("start") {
+			startStr, _ := cmd.Flags().GetString("start")
+			if start, err = parseDate(startStr); err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+		}
+		if cmd.Flags().Changed("end") {
+			endStr, _ := cmd.Flags().GetString("end")
+			if end, err = parseDate(endStr); err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+		}
+		if end.Before(start) {
+			return fmt.Errorf("end date is before start date")
+		}
+
+		heatmap, err := appInstance.ReportService.GetHoursHeatmap(ctx, start, end.AddDate(0, 0, 1))
+		if err != nil {
+			return fmt.Errorf("failed to get heatmap: %w", err)
+		}
+
+		report := heatmapReport{
+			Start: start.Format("2006-01-02"),
+			End:   end.Format("2006-01-02"),
+			Total: heatmap.Total,
+		}
+		for _, day := range mondayFirst {
+			row := heatmapDay{Day: day.String(), Hours: heatmap.Hours[day]}
+			for _, hours := range row.Hours {
+				row.Total += hours
+			}
+			report.Days = append(report.Days, row)
+		}
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Working hours: %s - %s\n\n", formatDate(start), formatDate(end))
+			if heatmap.Total == 0 {
+				fmt.Println("No time tracked")
+				return
+			}
+
+			fmt.Print("     ")
+			for hour := 0; hour < 24; hour += 3 {
+				fmt.Printf("%-6d", hour)
+			}
+			fmt.Printf("%8s\n", "Hours")
+			for _, day := range mondayFirst {
+				fmt.Printf("%-5s", day.String()[:3])
+				var total float64
+				for hour := 0; hour < 24; hour++ {
+					fmt.Print(heatmapShades[heatmap.Level(day, hour)])
+					total += heatmap.Hours[day][hour]
+				}
+				fmt.Printf("%8s\n", formatHours(total))
+			}
+
+			busiestDay, busiestHour := heatmap.Busiest()
+			fmt.Printf("\nTotal %s hours. Busiest hour: %s %02d:00 (%s hours)\n",
+				formatHours(heatmap.Total), busiestDay.String()[:3], busiestHour, formatHours(heatmap.Max))
+		})
+	},
+}
+
+// mondayFirst lists the days of the week in the order reports show them
+var mondayFirst = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday,
+	time.Friday, time.Saturday, time.Sunday,
+}
+
+var reportsRevenueCmd = &cobra.Command{
+	Use:   "revenue",
+	Short: "Show invoice revenue by month for a year",
+	Long: `Show invoice revenue by month for a year.
+
+--basis cash (the default) counts paid invoices in the month they were paid.
+--basis accrual counts every finalized invoice, paid or not, in the month its
+billing period ended.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		year, _ := cmd.Flags().GetInt("year")
+		if year == 0 {
+			year = time.Now().Year()
+		}
+
+		basisFlag, _ := cmd.Flags().GetString("basis")
+		basis, err := service.ParseRevenueBasis(basisFlag)
+		if err != nil {
+			return err
+		}
+
+		byMonth, err := appInstance.ReportService.GetRevenueByMonth(ctx, year, basis)
+		if err != nil {
+			return fmt.Errorf("failed to get revenue: %w", err)
+		}
+
+		report := revenueReport{Year: year, Basis: string(basis)}
+		var total domain.Money
+		for month := time.January; month <= time.December; month++ {
+			report.Months = append(report.Months, monthRevenueRow{
+				Month:   month.String(),
+				Revenue: byMonth[month].Float(),
+			})
+			total += byMonth[month]
+		}
+		report.Total = total.Float()
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Revenue %d (%s basis)\n\n", year, basis)
+			fmt.Printf("%-10s %12s\n", "Month", "Revenue")
+			fmt.Println("-----------------------")
+			for _, row := range report.Months {
+				fmt.Printf("%-10s %12s\n", row.Month[:3], formatMoney(row.Revenue))
+			}
+			fmt.Println("-----------------------")
+			fmt.Printf("%-10s %12s\n", "Total", formatMoney(report.Total))
+		})
+	},
+}
+
+type clientRevenueRow struct {
+	Rank     int     `json:"rank"`
+	ClientID int64   `json:"client_id"`
+	Client   string  `json:"client"`
+	Revenue  float64 `json:"revenue"`
+	Share    float64 `json:"share"` // percent of the total
+}
+
+type concentrationReport struct {
+	Start   string             `json:"start"`
+	End     string             `json:"end"`
+	Basis   string             `json:"basis"`
+	Total   float64            `json:"total"`
+	Limit   float64            `json:"limit"`             // percent; 0 when the check is off
+	Warning string             `json:"warning,omitempty"` // set when one client's share is over the limit
+	Clients []clientRevenueRow `json:"clients"`
+}
+
+func (r concentrationReport) csvHeader() []string {
+	return []string{"rank", "client_id", "client", "revenue", "share"}
+}
+
+func (r concentrationReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Clients))
+	for _, c := range r.Clients {
+		rows = append(rows, []string{
+			strconv.Itoa(c.Rank), strconv.FormatInt(c.ClientID, 10), c.Client,
+			csvNumber(c.Revenue), csvNumber(c.Share),
+		})
+	}
+	return rows
+}
+
+var reportsConcentrationCmd = &cobra.Command{
+	Use:   "concentration",
+	Short: "Rank clients by their share of revenue",
+	Long: `Rank clients by their share of revenue over a range, and warn when one
+client earns more than reports.concentration_limit of it, since losing them
+would cost that much of your income.
+
+The range is the current year unless --year or --start and --end are given.
+Revenue is counted as in the revenue report: --basis cash (the default)
+counts paid invoices on the day they were paid, --basis accrual counts every
+finalized invoice on the day its billing period ended.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		year, _ := cmd.Flags().GetInt("year")
+		if year == 0 {
+			year = time.Now().Year()
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		end := start.AddDate(1, 0, -1)
+		var err error
+		if cmd.Flags().Changed("start") {
+			if start, err = parseDate(mustGetString(cmd, "start")); err != nil {
+				return fmt.Errorf("invalid start date: %w", err)
+			}
+		}
+		if cmd.Flags().Changed("end") {
+			if end, err = parseDate(mustGetString(cmd, "end")); err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+		}
+		if end.Before(start) {
+			return fmt.Errorf("end date is before start date")
+		}
+
+		basis, err := service.ParseRevenueBasis(mustGetString(cmd, "basis"))
+		if err != nil {
+			return err
+		}
+		limit := appInstance.Config.Reports.ConcentrationLimit
+		if cmd.Flags().Changed("limit") {
+			limit, _ = cmd.Flags().GetFloat64("limit")
+			if limit < 0 || limit > 1 {
+				return fmt.Errorf("--limit must be a decimal between 0 (off) and 1, e.g. 0.5 for 50%%")
+			}
+		}
+
+		revenue, err := appInstance.ReportService.GetRevenueByClient(ctx, start, end.AddDate(0, 0, 1), basis)
+		if err != nil {
+			return fmt.Errorf("failed to get revenue: %w", err)
+		}
+
+		report := concentrationReport{
+			Start: start.Format("2006-01-02"),
+			End:   end.Format("2006-01-02"),
+			Basis: string(basis),
+			Total: revenue.Total.Float(),
+			Limit: limit * 100,
+		}
+		for i, row := range revenue.Clients {
+			report.Clients = append(report.Clients, clientRevenueRow{
+				Rank:     i + 1,
+				ClientID: row.ClientID,
+				Client:   clientName(ctx, row.ClientID),
+				Revenue:  row.Revenue.Float(),
+				Share:    row.Share * 100,
+			})
+		}
+		if top := revenue.Concentrated(limit); top != nil {
+			report.Warning = fmt.Sprintf("%s earns %.0f%% of revenue, over the %.0f%% limit",
+				clientName(ctx, top.ClientID), top.Share*100, limit*100)
+		}
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Revenue by client: %s - %s (%s basis)\n\n", formatDate(start), formatDate(end), basis)
+			if len(report.Clients) == 0 {
+				fmt.Println("No revenue recorded")
+				return
+			}
+			fmt.Printf("%4s  %-20s %12s %7s\n", "#", "Client", "Revenue", "Share")
+			fmt.Println("------------------------------------------------------------------")
+			for _, row := range report.Clients {
+				fmt.Printf("%4d  %-20s %12s %6.1f%%  %s\n",
+					row.Rank,
+					truncate(row.Client, 20),
+					formatMoney(row.Revenue),
+					row.Share,
+					strings.Repeat("█", int(math.Round(row.Share/5))),
+				)
+			}
+			fmt.Println("------------------------------------------------------------------")
+			fmt.Printf("%4s  %-20s %12s\n", "", "Total", formatMoney(report.Total))
+			if report.Warning != "" {
+				fmt.Printf("\n! %s\n", report.Warning)
+			}
+		})
+	},
+}
+
+type capacityWeekRow struct {
+	Week     string  `json:"week"` // Monday
+	DaysOff  int     `json:"days_off"`
+	Capacity float64 `json:"capacity"`
+	Demand   float64 `json:"demand"`
+	Balance  float64 `json:"balance"` // negative when overbooked
+}
+
+type clientDemandRow struct {
+	ClientID int64   `json:"client_id"`
+	Client   string  `json:"client"`
+	Hours    float64 `json:"hours"`
+}
+
+type capacityReport struct {
+	Target  float64           `json:"target"`
+	Clients []clientDemandRow `json:"clients"`
+	Weeks   []capacityWeekRow `json:"weeks"`
+}
+
+func (r capacityReport) csvHeader() []string {
+	return []string{"week", "days_off", "capacity", "demand", "balance"}
+}
+
+func (r capacityReport) csvRows() [][]string {
+	rows := make([][]string, 0, len(r.Weeks))
+	for _, w := range r.Weeks {
+		rows = append(rows, []string{
+			w.Week, strconv.Itoa(w.DaysOff),
+			csvNumber(w.Capacity), csvNumber(w.Demand), csvNumber(w.Balance),
+		})
+	}
+	return rows
+}
+
+var reportsCapacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "Plan coming weeks' billable target against client demand",
+	Long: `Compare the billable hours you aim to sell each week with the hours your
+clients are expected to need, for the coming weeks starting with this one.
+
+The target is workday.weekly_billable_hours unless --target is given, and is
+reduced by weekdays of time off. Demand is the total of each active client's
+expected weekly hours, set with clients edit --weekly-hours. A negative
+balance means the week is overbooked.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		weeks, _ := cmd.Flags().GetInt("weeks")
+		if weeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		target := appInstance.Config.Workday.WeeklyBillableHours
+		if cmd.Flags().Changed("target") {
+			target, _ = cmd.Flags().GetFloat64("target")
+			if target < 0 || target > 168 {
+				return fmt.Errorf("--target must be between 0 and 168 hours")
+			}
+		}
+		if target == 0 {
+			return fmt.Errorf("no weekly billable target: set workday.weekly_billable_hours or pass --target")
+		}
+
+		clients, err := appInstance.ClientRepo.List(ctx, false)
+		if err != nil {
+			return fmt.Errorf("failed to list clients: %w", err)
+		}
+		plan, err := appInstance.ReportService.GetCapacity(ctx, time.Now(), weeks, target, clients)
+		if err != nil {
+			return fmt.Errorf("failed to plan capacity: %w", err)
+		}
+
+		report := capacityReport{Target: target}
+		for _, c := range clients {
+			if c.WeeklyDemand > 0 {
+				report.Clients = append(report.Clients, clientDemandRow{ClientID: c.ID, Client: c.Name, Hours: c.WeeklyDemand})
+			}
+		}
+		sort.Slice(report.Clients, func(i, j int) bool { return report.Clients[i].Hours > report.Clients[j].Hours })
+		for _, w := range plan {
+			report.Weeks = append(report.Weeks, capacityWeekRow{
+				Week:     w.Start.Format("2006-01-02"),
+				DaysOff:  w.DaysOff,
+				Capacity: w.Capacity,
+				Demand:   w.Demand,
+				Balance:  w.Balance(),
+			})
+		}
+
+		return writeReport(cmd, report, func() {
+			fmt.Printf("Capacity: %s billable hours a week\n\n", formatHours(target))
+			if len(report.Clients) == 0 {
+				fmt.Println("No client has expected weekly hours; set them with clients edit --weekly-hours")
+				fmt.Println()
+			} else {
+				for _, c := range report.Clients {
+					fmt.Printf("  %-20s %8s\n", truncate(c.Client, 20), formatHours(c.Hours))
+				}
+				fmt.Println()
+			}
+			fmt.Printf("%-12s %8s %10s %10s %10s\n", "Week of", "Days off", "Capacity", "Demand", "Balance")
+			fmt.Println("-------------------------------------------------------------")
+			for i, w := range report.Weeks {
+				status := ""
+				switch {
+				case w.Balance < 0:
+					status = "overbooked"
+				case w.Balance > 0:
+					status = "underbooked"
+				}
+				fmt.Printf("%-12s %8d %10s %10s %10s  %s\n",
+					formatDate(plan[i].Start), w.DaysOff, formatHours(w.Capacity), formatHours(w.Demand), formatHours(w.Balance), status)
+			}
+		})
+	},
+}
+
+func init() {
+	reportsCmd.AddCommand(reportsWeekCmd)
+	reportsCmd.AddCommand(reportsMonthCmd)
+	reportsCmd.AddCommand(reportsClientCmd)
+	reportsCmd.AddCommand(reportsRevenueCmd)
+	reportsCmd.AddCommand(reportsConcentrationCmd)
+	reportsCmd.AddCommand(reportsCapacityCmd)
+	reportsCmd.AddCommand(reportsAgingCmd)
+	reportsCmd.AddCommand(reportsHeatmapCmd)
+
+	reportsCmd.PersistentFlags().String("format", string(formatTable), "Output format: table, csv, or json")
+	reportsCmd.PersistentFlags().Bool("json", false, "Print the report as JSON (same as --format json)")
+
+	reportsWeekCmd.Flags().String("date", "", "Any date in the week (YYYY-MM-DD, today, yesterday)")
+	reportsMonthCmd.Flags().String("month", "", "Month to report (YYYY-MM, default current month)")
+	reportsClientCmd.Flags().String("start", "", "Start date (YYYY-MM-DD)")
+	reportsClientCmd.Flags().String("end", "", "End date, inclusive (YYYY-MM-DD)")
+	reportsRevenueCmd.Flags().Int("year", 0, "Year to report (default current year)")
+	reportsRevenueCmd.Flags().String("basis", string(service.RevenueCash), "Revenue basis: cash or accrual")
+	reportsConcentrationCmd.Flags().Int("year", 0, "Year to report (default current year)")
+	reportsConcentrationCmd.Flags().String("start", "", "Start date, overriding --year (YYYY-MM-DD)")
+	reportsConcentrationCmd.Flags().String("end", "", "End date, inclusive, overriding --year (YYYY-MM-DD)")
+	reportsConcentrationCmd.Flags().String("basis", string(service.RevenueCash), "Revenue basis: cash or accrual")
+	reportsConcentrationCmd.Flags().Float64("limit", 0, "Largest healthy share, 0 for off (defaults to reports.concentration_limit)")
+	reportsCapacityCmd.Flags().Int("weeks", 8, "Number of weeks to plan, starting with this one")
+	reportsCapacityCmd.Flags().Float64("target", 0, "Billable hours a week (defaults to workday.weekly_billable_hours)")
+	reportsAgingCmd.Flags().String("as-of", "", "Date to age invoices to (YYYY-MM-DD, default today)")
+	reportsHeatmapCmd.Flags().String("start", "", "Start date (YYYY-MM-DD, default four weeks ago)")
+	reportsHeatmapCmd.Flags().String("end", "", "End date, inclusive (YYYY-MM-DD, default today)")
+}
+
+// printClientRows prints the per-client table shared by the week and month reports
+func printClientRows(rows []clientHoursRow) {
+	if len(rows) == 0 {
+		fmt.Println("No time tracked")
+		fmt.Println()
+		return
+	}
+	fmt.Printf("%-20s %8s %8s %12s\n", "Client", "Hours", "Billable", "Value")
+	fmt.Println("--------------------------------------------------")
+	for _, row := range rows {
+		fmt.Printf("%-20s %8s %8s %12s\n",
+			truncate(row.Client, 20),
+			formatHours(row.Hours),
+			formatHours(row.BillableHours),
+			formatMoney(row.Value),
+		)
+	}
+	fmt.Println("--------------------------------------------------")
+}
+
+// sortClientRows orders clients by hours, most first
+func sortClientRows(rows []clientHoursRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Hours != rows[j].Hours {
+			return rows[i].Hours > rows[j].Hours
+		}
+		return rows[i].Client < rows[j].Client
+	})
+}
+
+// clientName returns the client's name, or a placeholder if it can't be found
+func clientName(ctx context.Context, clientID int64) string {
+	client, _ := appInstance.ClientRepo.GetByID(ctx, clientID)
+	if client == nil {
+		return fmt.Sprintf("Client #%d", clientID)
+	}
+	return client.Name
+}
+
+// formatHours formats decimal hours using the configured locale, e.g. 7.50
+func formatHours(hours float64) string {
+	return cliLocale().Number(hours, 2)
+}
+
+// weekMonday returns midnight on the Monday of the week containing t
+func weekMonday(t time.Time) time.Time {
+	for t.Weekday() != time.Monday {
+		t = t.AddDate(0, 0, -1)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
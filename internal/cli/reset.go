@@ -2,10 +2,12 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/andy/timesink/internal/app"
 	"github.com/spf13/cobra"
 )
 
@@ -27,27 +29,8 @@ var resetEntriesCmd = &cobra.Command{
 			fmt.Println("Cancelled.")
 			return nil
 		}
-
-		db := appInstance.DB
-
-		// Clear invoice references from entries before deleting invoices
-		if _, err := db.Exec("UPDATE time_entries SET invoice_id = NULL WHERE invoice_id IS NOT NULL"); err != nil {
-			return fmt.Errorf("failed to unlock entries: %w", err)
-		}
-
-		// Order matters due to foreign keys
-		tables := []string{
-			"invoice_line_items",
-			"invoices",
-			"entry_history",
-			"time_entries",
-			"active_timer",
-		}
-
-		for _, table := range tables {
-			if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
-				return fmt.Errorf("failed to clear %s: %w", table, err)
-			}
+		if err := appInstance.Reset(context.Background(), app.ResetEntries); err != nil {
+			return err
 		}
 
 		fmt.Println("All time entries, invoices, and timer state have been deleted.")
@@ -63,23 +46,8 @@ var resetInvoicesCmd = &cobra.Command{
 			fmt.Println("Cancelled.")
 			return nil
 		}
-
-		db := appInstance.DB
-
-		// Unlock all entries that were locked to invoices
-		if _, err := db.Exec("UPDATE time_entries SET invoice_id = NULL WHERE invoice_id IS NOT NULL"); err != nil {
-			return fmt.Errorf("failed to unlock entries: %w", err)
-		}
-
-		tables := []string{
-			"invoice_line_items",
-			"invoices",
-		}
-
-		for _, table := range tables {
-			if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
-				return fmt.Errorf("failed to clear %s: %w", table, err)
-			}
+		if err := appInstance.Reset(context.Background(), app.ResetInvoices); err != nil {
+			return err
 		}
 
 		fmt.Println("All invoices have been deleted and time entries unlocked.")
@@ -95,28 +63,8 @@ var resetAllCmd = &cobra.Command{
 			fmt.Println("Cancelled.")
 			return nil
 		}
-
-		db := appInstance.DB
-
-		// Clear invoice references from entries before deleting invoices
-		if _, err := db.Exec("UPDATE time_entries SET invoice_id = NULL WHERE invoice_id IS NOT NULL"); err != nil {
-			return fmt.Errorf("failed to unlock entries: %w", err)
-		}
-
-		// Order matters due to foreign keys
-		tables := []string{
-			"invoice_line_items",
-			"invoices",
-			"entry_history",
-			"time_entries",
-			"active_timer",
-			"clients",
-		}
-
-		for _, table := range tables {
-			if _, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
-				return fmt.Errorf("failed to clear %s: %w", table, err)
-			}
+		if err := appInstance.Reset(context.Background(), app.ResetAll); err != nil {
+			return err
 		}
 
 		fmt.Println("All data has been deleted.")
@@ -135,6 +83,17 @@ func confirmPrompt(message string) bool {
 	return input == "y" || input == "yes"
 }
 
+// confirmTyped asks the user to type expected back before continuing
+func confirmTyped(message, expected string) bool {
+	fmt.Printf("%s\nType %q to confirm: ", message, expected)
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(input) == expected
+}
+
 func init() {
 	resetCmd.AddCommand(resetEntriesCmd)
 	resetCmd.AddCommand(resetInvoicesCmd)
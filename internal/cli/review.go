@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andy/timesink/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review last week's time before invoicing it",
+	Long: `Open the weekly review in the terminal UI. It walks through last week's
+days, Monday to Sunday, marking weekdays logged below workday.target_hours
+that no time off or day-off entry explains, and entries without a
+description. Press n to jump to the next thing to fix and enter to describe
+the selected entry.
+
+Use --week to review the week holding another day.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		day := time.Now().AddDate(0, 0, -7)
+		if cmd.Flags().Changed("week") {
+			var err error
+			if day, err = parseDate(mustGetString(cmd, "week")); err != nil {
+				return fmt.Errorf("invalid week: %w", err)
+			}
+		}
+
+		if err := tui.RunReview(appInstance, day); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+
+	reviewCmd.Flags().String("week", "", "Any day of the week to review (YYYY-MM-DD, 'today', or 'yesterday'; default: last week)")
+}
@@ -7,6 +7,10 @@ import (
 
 var appInstance *app.App
 
+// exitCode is the process status requested by a command that otherwise
+// succeeded, e.g. `timer status` reporting an idle timer
+var exitCode int
+
 var rootCmd = &cobra.Command{
 	Use:   "timesink",
 	Short: "A CLI time tracking tool for freelancers",
@@ -14,6 +18,7 @@ var rootCmd = &cobra.Command{
 
 By default, running timesink without arguments launches the interactive TUI.
 Use subcommands for CLI operations.`,
+	PersistentPreRunE: requireUnlocked,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default behavior: launch TUI
 		launchTUI(cmd, args)
@@ -22,20 +27,42 @@ Use subcommands for CLI operations.`,
 
 // Execute runs the root command
 func Execute() error {
+	acceptDecimalCommas(rootCmd)
 	return rootCmd.Execute()
 }
 
+// ExitCode returns the exit status requested by the command that ran
+func ExitCode() int {
+	return exitCode
+}
+
 // SetApp sets the app instance for commands to use
 func SetApp(a *app.App) {
 	appInstance = a
 }
 
 func init() {
+	// Read by main before the app starts; declared here for help and parsing
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log debug detail to stderr as well as the log file")
+	rootCmd.PersistentFlags().String("profile", "", "Profile to use (default: $TIMESINK_PROFILE, then the switched profile)")
+	rootCmd.PersistentFlags().String("config", "", "Config file to use instead of the profile's config.yaml (default: $TIMESINK_CONFIG)")
+
 	// Add all subcommands
 	rootCmd.AddCommand(timerCmd)
 	rootCmd.AddCommand(clientsCmd)
 	rootCmd.AddCommand(entriesCmd)
 	rootCmd.AddCommand(invoicesCmd)
+	rootCmd.AddCommand(estimatesCmd)
+	rootCmd.AddCommand(statementsCmd)
+	rootCmd.AddCommand(digestCmd)
+	rootCmd.AddCommand(timeOffCmd)
+	rootCmd.AddCommand(reportsCmd)
+	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(resetCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(lockCmd)
 }
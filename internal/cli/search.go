@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <words>...",
+	Short: "Search entry descriptions and notes, and client names and notes",
+	Long: `Search the full-text index of entry descriptions and notes and client names
+and notes. Results contain every word given, each also matching as the start
+of a longer word ("deplo" finds "deployment"). Matching clients are listed
+first, then entries newest first; deleted entries are left out.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 1 {
+			return fmt.Errorf("--limit must be at least 1")
+		}
+
+		results, err := appInstance.SearchRepo.Search(ctx, strings.Join(args, " "), limit)
+		if err != nil {
+			return fmt.Errorf("failed to search: %w", err)
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			out := make([]searchResultJSON, 0, len(results))
+			for _, r := range results {
+				out = append(out, searchResultJSON{
+					Kind:     r.Kind,
+					ID:       r.ID,
+					ClientID: r.ClientID,
+					Client:   r.ClientName,
+					Date:     r.Date,
+					Snippet:  domain.HighlightSnippet(r.Snippet, "", ""),
+				})
+			}
+			return printJSON(out)
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No matches found")
+			return nil
+		}
+
+		fmt.Printf("%-7s %-5s %-15s %-12s %s\n", "Kind", "ID", "Client", "Date", "Match")
+		fmt.Println("------------------------------------------------------------------------------------------")
+		for _, r := range results {
+			date := ""
+			if r.Date != nil {
+				date = formatDate(*r.Date)
+			}
+			fmt.Printf("%-7s %-5d %-15s %-12s %s\n",
+				r.Kind,
+				r.ID,
+				truncate(r.ClientName, 15),
+				date,
+				strings.ReplaceAll(domain.HighlightSnippet(r.Snippet, "[", "]"), "\n", " "),
+			)
+		}
+		if len(results) == limit {
+			fmt.Printf("\nShowing the first %d matches; use --limit to see more.\n", limit)
+		}
+		return nil
+	},
+}
+
+// searchResultJSON is a search result as written by --json
+type searchResultJSON struct {
+	Kind     domain.SearchKind `json:"kind"`
+	ID       int64             `json:"id"`
+	ClientID int64             `json:"client_id"`
+	Client   string            `json:"client"`
+	Date     *time.Time        `json:"date,omitempty"`
+	Snippet  string            `json:"snippet"`
+}
+
+func init() {
+	searchCmd.Flags().Int("limit", 20, "Maximum number of results")
+	searchCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/andy/timesink/internal/render"
+	"github.com/spf13/cobra"
+)
+
+var statementsCmd = &cobra.Command{
+	Use:   "statements",
+	Short: "Generate client statements",
+	Long: `Generate monthly statements of account: for one client and month, the
+balance carried forward, each invoice issued and payment received, and the
+balance due at the end of the month.`,
+}
+
+var statementsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a client's statement for a month",
+	Long: `Generate a client's statement for a month, last month by default.
+
+Invoices count as issued on their invoice date and as paid on their paid
+date. Drafts are left out, as they have not been sent.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		clientID, err := resolveClientID(ctx, mustGetString(cmd, "client"))
+		if err != nil {
+			return fmt.Errorf("failed to resolve client: %w", err)
+		}
+		client, err := appInstance.ClientRepo.GetByID(ctx, clientID)
+		if err != nil {
+			return fmt.Errorf("failed to get client: %w", err)
+		}
+
+		now := time.Now()
+		month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+		if cmd.Flags().Changed("month") {
+			monthStr := mustGetString(cmd, "month")
+			month, err = time.ParseInLocation("2006-01", monthStr, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid month %q (use YYYY-MM)", monthStr)
+			}
+		}
+
+		statement, err := appInstance.ReportService.GetStatement(ctx, client, month)
+		if err != nil {
+			return fmt.Errorf("failed to build statement: %w", err)
+		}
+
+		opts := render.Options{
+			Locale:     cliLocale(),
+			HourFormat: appInstance.Config.Invoice.HourFormat,
+			From:       appInstance.Config.User,
+			Date:       now,
+		}
+
+		output := mustGetString(cmd, "output")
+		if output == "" {
+			return render.Statement(os.Stdout, statement, opts)
+		}
+
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+
+		if err := render.Statement(f, statement, opts); err != nil {
+			return fmt.Errorf("failed to write statement: %w", err)
+		}
+
+		fmt.Printf("✓ Statement for %s, %s written to %s\n", client.Name, month.Format("January 2006"), output)
+		return nil
+	},
+}
+
+func init() {
+	statementsCmd.AddCommand(statementsGenerateCmd)
+
+	statementsGenerateCmd.Flags().String("client", "", "Client ID or name")
+	statementsGenerateCmd.Flags().String("month", "", "Month of the statement (YYYY-MM, default last month)")
+	statementsGenerateCmd.Flags().StringP("output", "o", "", "Write the statement to a file instead of stdout")
+	statementsGenerateCmd.MarkFlagRequired("client")
+}
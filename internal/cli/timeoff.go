@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+var timeOffCmd = &cobra.Command{
+	Use:   "timeoff",
+	Short: "Track vacation, sick days, and holidays",
+	Long: `Time off marks days you did not work. Reports flag them on the week view,
+leave them out of utilization, and do not count them as days short of
+workday.target_hours.`,
+}
+
+var timeOffAddCmd = &cobra.Command{
+	Use:   "add <start> [end]",
+	Short: "Add time off from start to end, inclusive",
+	Long: `Add time off from start to end, inclusive. Without an end date the time off
+is a single day. Dates are YYYY-MM-DD, 'today', or 'yesterday'.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start, err := parseDate(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid start date: %w", err)
+		}
+		end := start
+		if len(args) == 2 {
+			if end, err = parseDate(args[1]); err != nil {
+				return fmt.Errorf("invalid end date: %w", err)
+			}
+		}
+		timeOffType, err := domain.ParseTimeOffType(mustGetString(cmd, "type"))
+		if err != nil {
+			return err
+		}
+
+		timeOff := domain.NewTimeOff(start, end, timeOffType)
+		timeOff.Note = mustGetString(cmd, "note")
+
+		if err := appInstance.TimeOffRepo.Create(context.Background(), timeOff); err != nil {
+			return fmt.Errorf("failed to add time off: %w", err)
+		}
+
+		fmt.Printf("✓ Time off #%d added: %s, %s\n", timeOff.ID, timeOff.Type, formatTimeOffDates(timeOff))
+		return nil
+	},
+}
+
+var timeOffListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List time off in a year",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		year := time.Now().Year()
+		if cmd.Flags().Changed("year") {
+			year, _ = cmd.Flags().GetInt("year")
+		}
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		end := start.AddDate(1, 0, 0)
+
+		list, err := appInstance.TimeOffRepo.List(context.Background(), &start, &end)
+		if err != nil {
+			return fmt.Errorf("failed to list time off: %w", err)
+		}
+
+		if len(list) == 0 {
+			fmt.Printf("No time off in %d\n", year)
+			return nil
+		}
+
+		fmt.Printf("%-5s %-10s %-30s %5s  %s\n", "ID", "Type", "Dates", "Days", "Note")
+		fmt.Println("----------------------------------------------------------------------")
+
+		totals := make(map[domain.TimeOffType]int)
+		for _, t := range list {
+			fmt.Printf("%-5d %-10s %-30s %5d  %s\n",
+				t.ID,
+				t.Type,
+				formatTimeOffDates(t),
+				t.Days(),
+				truncate(t.Note, 30),
+			)
+			totals[t.Type] += t.Days()
+		}
+
+		fmt.Println()
+		for _, t := range []domain.TimeOffType{domain.TimeOffVacation, domain.TimeOffSick, domain.TimeOffHoliday} {
+			switch totals[t] {
+			case 0:
+			case 1:
+				fmt.Printf("%-10s 1 day\n", t)
+			default:
+				fmt.Printf("%-10s %d days\n", t, totals[t])
+			}
+		}
+		return nil
+	},
+}
+
+var timeOffRemoveCmd = &cobra.Command{
+	Use:   "remove [id]",
+	Short: "Delete time off",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid time off ID: %w", err)
+		}
+
+		if err := appInstance.TimeOffRepo.Delete(context.Background(), id); err != nil {
+			return fmt.Errorf("failed to remove time off: %w", err)
+		}
+
+		fmt.Printf("✓ Time off #%d removed\n", id)
+		return nil
+	},
+}
+
+// formatTimeOffDates describes the days off, e.g. "Aug 3, 2026 - Aug 14, 2026"
+func formatTimeOffDates(t *domain.TimeOff) string {
+	if t.StartDate.Equal(t.EndDate) {
+		return formatDate(t.StartDate)
+	}
+	return formatDate(t.StartDate) + " - " + formatDate(t.EndDate)
+}
+
+func init() {
+	timeOffCmd.AddCommand(timeOffAddCmd)
+	timeOffCmd.AddCommand(timeOffListCmd)
+	timeOffCmd.AddCommand(timeOffRemoveCmd)
+
+	timeOffAddCmd.Flags().String("type", string(domain.TimeOffVacation), "vacation, sick, or holiday")
+	timeOffAddCmd.Flags().String("note", "", "Note, e.g. where you went")
+	timeOffListCmd.Flags().Int("year", 0, "Year to list (default: this year)")
+}
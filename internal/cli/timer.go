@@ -2,10 +2,18 @@ package cli
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/locale"
+	"github.com/andy/timesink/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +26,14 @@ var timerCmd = &cobra.Command{
 var timerStartCmd = &cobra.Command{
 	Use:   "start [client_id_or_name] [description]",
 	Short: "Start a new timer",
-	Long:  `Start a new timer for a client with an optional description.`,
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Start a new timer for a client with an optional description.
+
+The timer records where the work is done from timer.location, e.g. the
+machine's hostname; --location sets it for this timer instead.
+
+--role bills the time at a rate from the client's rate card, set with
+"clients rate-card set", instead of the client's hourly rate.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
@@ -35,8 +49,16 @@ var timerStartCmd = &cobra.Command{
 			description = args[1]
 		}
 
+		nonBillable, _ := cmd.Flags().GetBool("non-billable")
+		location := appInstance.Location()
+		if cmd.Flags().Changed("location") {
+			location = strings.TrimSpace(mustGetString(cmd, "location"))
+		}
+
+		role := mustGetString(cmd, "role")
+
 		// Start timer
-		if err := appInstance.TimerService.Start(ctx, clientID, description); err != nil {
+		if err := appInstance.TimerService.Start(ctx, clientID, description, !nonBillable, location, role); err != nil {
 			return fmt.Errorf("failed to start timer: %w", err)
 		}
 
@@ -51,7 +73,74 @@ var timerStartCmd = &cobra.Command{
 		if description != "" {
 			fmt.Printf("  Description: %s\n", description)
 		}
+		if nonBillable {
+			fmt.Println("  Non-billable")
+		}
+		if location != "" {
+			fmt.Printf("  Location: %s\n", location)
+		}
+		if role != "" {
+			fmt.Printf("  Role: %s\n", strings.TrimSpace(role))
+		}
+
+		return nil
+	},
+}
+
+var timerAutoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Start a timer for the client whose directory or repository this is",
+	Long: `Start a timer from the timer.auto_start rules in config.yaml, matching
+the working directory or the origin remote of the git repository it is in.
+Run it from a shell hook when changing directory, or from cron, so timers
+start without remembering to.
+
+Nothing happens when no rule matches or a timer for the same client is
+already running. A timer running for another client is left alone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		dir := mustGetString(cmd, "dir")
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get working directory: %w", err)
+			}
+			dir = wd
+		}
+
+		rule, description := appInstance.AutoStart(dir)
+		if rule == nil {
+			return nil
+		}
+		clientID, err := resolveClientID(ctx, rule.Client)
+		if err != nil {
+			return fmt.Errorf("failed to resolve auto-start client %q: %w", rule.Client, err)
+		}
+
+		timer, err := appInstance.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active timer: %w", err)
+		}
+		if timer != nil {
+			if timer.ClientID != clientID {
+				fmt.Printf("! A timer is already running for %s; not starting one for %s\n",
+					clientName(ctx, timer.ClientID), clientName(ctx, clientID))
+			}
+			return nil
+		}
+
+		location := appInstance.Location()
+		if err := appInstance.TimerService.Start(ctx, clientID, description, true, location, ""); err != nil {
+			return fmt.Errorf("failed to start timer: %w", err)
+		}
 
+		fmt.Printf("✓ Timer started for %s\n", clientName(ctx, clientID))
+		fmt.Printf("  Description: %s\n", description)
+		if location != "" {
+			fmt.Printf("  Location: %s\n", location)
+		}
 		return nil
 	},
 }
@@ -59,26 +148,70 @@ var timerStartCmd = &cobra.Command{
 var timerStopCmd = &cobra.Command{
 	Use:   "stop",
 	Short: "Stop the active timer and save the time entry",
+	Long: `Stop the active timer and save the time entry.
+
+The entry can be corrected as it is saved, instead of edited afterwards:
+--description replaces the timer's description, --trim ends the entry
+earlier, e.g. --trim 10m when you stopped working ten minutes ago, and
+--non-billable saves it as non-billable time.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
-		entry, err := appInstance.TimerService.Stop(ctx)
+		var entries []*domain.TimeEntry
+		var err error
+		if cmd.Flags().Changed("description") || cmd.Flags().Changed("trim") || cmd.Flags().Changed("non-billable") {
+			timer, getErr := appInstance.TimerService.GetActiveTimer(ctx)
+			if getErr != nil {
+				return fmt.Errorf("failed to get active timer: %w", getErr)
+			}
+			if timer == nil {
+				return fmt.Errorf("failed to stop timer: %w", service.ErrNoActiveTimer)
+			}
+			edit := domain.TimerStop{End: time.Now(), Description: timer.DescriptionWithNotes(), IsBillable: timer.IsBillable}
+			if cmd.Flags().Changed("description") {
+				edit.Description = mustGetString(cmd, "description")
+			}
+			if trim, _ := cmd.Flags().GetDuration("trim"); trim != 0 {
+				if trim < 0 {
+					return fmt.Errorf("--trim must be positive (got %s)", trim)
+				}
+				edit.End = edit.End.Add(-trim)
+			}
+			if nonBillable, _ := cmd.Flags().GetBool("non-billable"); nonBillable {
+				edit.IsBillable = false
+			}
+			entries, err = appInstance.TimerService.StopEdited(ctx, edit)
+		} else {
+			entries, err = appInstance.TimerService.Stop(ctx)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to stop timer: %w", err)
 		}
 
 		// Get client for display
-		client, _ := appInstance.ClientRepo.GetByID(ctx, entry.ClientID)
-		clientName := fmt.Sprintf("Client #%d", entry.ClientID)
+		clientID := entries[0].ClientID
+		client, _ := appInstance.ClientRepo.GetByID(ctx, clientID)
+		clientName := fmt.Sprintf("Client #%d", clientID)
 		if client != nil {
 			clientName = client.Name
 		}
 
-		duration := entry.Duration()
+		var duration time.Duration
+		var amount domain.Money
+		for _, entry := range entries {
+			duration += entry.Duration()
+			amount += entry.Amount()
+		}
 		fmt.Printf("✓ Timer stopped\n")
 		fmt.Printf("  Client: %s\n", clientName)
 		fmt.Printf("  Duration: %s\n", formatDuration(duration))
-		fmt.Printf("  Amount: $%.2f\n", entry.Amount())
+		fmt.Printf("  Amount: %s\n", formatMoney(amount.Float()))
+		if len(entries) > 1 {
+			fmt.Printf("  Split at midnight into %d entries:\n", len(entries))
+			for _, entry := range entries {
+				fmt.Printf("    #%-4d %s  %s\n", entry.ID, formatDate(entry.StartTime), formatDuration(entry.Duration()))
+			}
+		}
 
 		return nil
 	},
@@ -114,6 +247,75 @@ var timerResumeCmd = &cobra.Command{
 	},
 }
 
+var timerNoteCmd = &cobra.Command{
+	Use:   "note <text>",
+	Short: "Add a timestamped note to the active timer",
+	Long: `Add a timestamped note to the active timer, e.g. what you just finished.
+
+When the timer stops, its notes are appended to the entry's description
+with their times, so invoice lines say what the time went on.
+
+Example:
+  timesink timer note "fixed the auth bug"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		if err := appInstance.TimerService.AddNote(ctx, strings.Join(args, " ")); err != nil {
+			return fmt.Errorf("failed to add note: %w", err)
+		}
+
+		timer, err := appInstance.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active timer: %w", err)
+		}
+		notes := timer.NoteLines()
+		fmt.Printf("✓ Note added: %s\n", notes[len(notes)-1])
+		return nil
+	},
+}
+
+var timerAdjustCmd = &cobra.Command{
+	Use:   "adjust",
+	Short: "Move the active timer's start time",
+	Long: `Move the active timer's start time, e.g. when you started work before
+starting the timer.
+
+--start takes a shift from the current start, such as -20m or +5m, or a time
+of day such as 09:40 on the day the timer started. The start must stay in
+the past and cannot be moved back over time already logged in an entry.
+
+Example:
+  timesink timer adjust --start -20m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		timer, err := appInstance.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active timer: %w", err)
+		}
+		if timer == nil {
+			return fmt.Errorf("failed to adjust timer: %w", service.ErrNoActiveTimer)
+		}
+
+		start, err := domain.ParseTimerStart(mustGetString(cmd, "start"), timer.StartTime)
+		if err != nil {
+			return err
+		}
+		if err := appInstance.TimerService.AdjustStart(ctx, start); err != nil {
+			return fmt.Errorf("failed to adjust timer: %w", err)
+		}
+
+		timer, err = appInstance.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active timer: %w", err)
+		}
+		fmt.Printf("✓ Timer now started at %s\n", start.Format("15:04"))
+		fmt.Printf("  Elapsed: %s\n", formatDuration(timer.Elapsed()))
+		return nil
+	},
+}
+
 var timerDiscardCmd = &cobra.Command{
 	Use:   "discard",
 	Short: "Discard the active timer without saving",
@@ -132,16 +334,63 @@ var timerDiscardCmd = &cobra.Command{
 var timerStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show the status of the active timer",
+	Long: `Show the status of the active timer.
+
+The exit code reports the timer state: 0 running, 2 paused, 3 idle
+(1 is reserved for errors). With --quiet nothing is printed and only the
+state is read, so status bars can poll it cheaply:
+  timesink timer status --quiet && echo running
+
+Use --format to print a Go template instead, e.g. for a shell prompt or
+tmux status bar. Nothing is printed when no timer is active. Fields:
+  .State           running or paused
+  .Client          client name
+  .ClientID        client ID
+  .Description     timer description
+  .Elapsed         elapsed time, e.g. 1h 5m 12s
+  .ElapsedMinutes  elapsed whole minutes
+  .ElapsedSeconds  elapsed whole seconds
+  .Hours           elapsed hours as a decimal number
+  .Value           accrued value, formatted as money (0 if non-billable)
+  .Billable        true if the timer is billable
+  .Location        where the work is done, if recorded
+  .Role            rate card role the time is billed at, if any
+
+Example:
+  timesink timer status --format '{{.Client}} {{.Elapsed}}'`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 
+		format, _ := cmd.Flags().GetString("format")
+		var tmpl *template.Template
+		if format != "" {
+			var err error
+			tmpl, err = template.New("status").Parse(format)
+			if err != nil {
+				return fmt.Errorf("invalid format: %w", err)
+			}
+		}
+
 		state, err := appInstance.TimerService.GetState(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get timer state: %w", err)
 		}
 
-		if state == "idle" {
-			fmt.Println("No active timer")
+		if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+			switch state {
+			case domain.TimerStateIdle:
+				exitCode = exitTimerIdle
+			case domain.TimerStatePaused:
+				exitCode = exitTimerPaused
+			}
+			return nil
+		}
+
+		if state == domain.TimerStateIdle {
+			exitCode = exitTimerIdle
+			if tmpl == nil {
+				fmt.Println("No active timer")
+			}
 			return nil
 		}
 
@@ -149,37 +398,136 @@ var timerStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get active timer: %w", err)
 		}
-
-		// Get client for display
-		client, _ := appInstance.ClientRepo.GetByID(ctx, timer.ClientID)
-		clientName := fmt.Sprintf("Client #%d", timer.ClientID)
-		if client != nil {
-			clientName = client.Name
+		if state == domain.TimerStatePaused {
+			exitCode = exitTimerPaused
 		}
 
-		elapsed := timer.Elapsed()
-		value := elapsed.Hours() * client.HourlyRate
+		status := newTimerStatus(ctx, timer)
+
+		if tmpl != nil {
+			if err := tmpl.Execute(os.Stdout, status); err != nil {
+				return fmt.Errorf("failed to render format: %w", err)
+			}
+			fmt.Println()
+			return nil
+		}
 
 		fmt.Printf("Timer Status: %s\n", state)
-		fmt.Printf("  Client: %s\n", clientName)
+		fmt.Printf("  Client: %s\n", status.Client)
 		if timer.Description != "" {
 			fmt.Printf("  Description: %s\n", timer.Description)
 		}
+		if timer.Location != "" {
+			fmt.Printf("  Location: %s\n", timer.Location)
+		}
+		if timer.Role != "" {
+			fmt.Printf("  Role: %s\n", timer.Role)
+		}
 		fmt.Printf("  Started: %s\n", timer.StartTime.Format("2006-01-02 15:04:05"))
-		fmt.Printf("  Elapsed: %s\n", formatDuration(elapsed))
-		fmt.Printf("  Current Value: $%.2f\n", value)
+		fmt.Printf("  Elapsed: %s\n", status.Elapsed)
+		fmt.Printf("  Current Value: %s\n", status.Value)
+		if notes := timer.NoteLines(); len(notes) > 0 {
+			fmt.Println("  Notes:")
+			for _, note := range notes {
+				fmt.Printf("    %s\n", note)
+			}
+		}
 
 		return nil
 	},
 }
 
+// Exit codes for `timer status`; 0 means running
+const (
+	exitTimerPaused = 2
+	exitTimerIdle   = 3
+)
+
+// timerStatus holds the fields available to `timer status --format`
+type timerStatus struct {
+	State          string
+	Client         string
+	ClientID       int64
+	Description    string
+	Elapsed        string
+	ElapsedMinutes int64
+	ElapsedSeconds int64
+	Hours          float64
+	Value          string
+	Billable       bool
+	Location       string
+	Role           string
+}
+
+// newTimerStatus snapshots the active timer for display
+func newTimerStatus(ctx context.Context, timer *domain.ActiveTimer) timerStatus {
+	elapsed := timer.Elapsed()
+	status := timerStatus{
+		State:          string(timer.State()),
+		Client:         fmt.Sprintf("Client #%d", timer.ClientID),
+		ClientID:       timer.ClientID,
+		Description:    timer.Description,
+		Elapsed:        formatDuration(elapsed),
+		ElapsedMinutes: int64(elapsed.Minutes()),
+		ElapsedSeconds: int64(elapsed.Seconds()),
+		Hours:          elapsed.Hours(),
+		Billable:       timer.IsBillable,
+		Location:       timer.Location,
+		Role:           timer.Role,
+	}
+
+	value := 0.0
+	client, _ := appInstance.ClientRepo.GetByID(ctx, timer.ClientID)
+	if client != nil {
+		status.Client = client.Name
+		if timer.IsBillable {
+			rate, err := appInstance.ClientRepo.RateAt(ctx, timer.ClientID, timer.StartTime)
+			if err != nil {
+				rate = client.HourlyRate
+			}
+			if timer.Role != "" {
+				if role, err := service.FindRoleRate(ctx, appInstance.ClientRepo, client, timer.Role); err == nil {
+					rate = role.HourlyRate
+				}
+			}
+			value = elapsed.Hours() * rate
+		}
+	}
+	status.Value = formatMoney(value)
+
+	return status
+}
+
 func init() {
 	timerCmd.AddCommand(timerStartCmd)
+	timerCmd.AddCommand(timerAutoCmd)
 	timerCmd.AddCommand(timerStopCmd)
 	timerCmd.AddCommand(timerPauseCmd)
 	timerCmd.AddCommand(timerResumeCmd)
+	timerCmd.AddCommand(timerNoteCmd)
+	timerCmd.AddCommand(timerAdjustCmd)
 	timerCmd.AddCommand(timerDiscardCmd)
 	timerCmd.AddCommand(timerStatusCmd)
+
+	// Start flags
+	timerStartCmd.Flags().Bool("non-billable", false, "Track this time as non-billable")
+	timerStartCmd.Flags().String("location", "", "Where the work is done, e.g. on-site (defaults to timer.location)")
+	timerStartCmd.Flags().String("role", "", "Bill at this role's rate from the client's rate card")
+
+	// Auto flags
+	timerAutoCmd.Flags().String("dir", "", "Directory to match instead of the working directory")
+
+	// Status flags
+	timerStopCmd.Flags().String("description", "", "Save the entry with this description instead of the timer's")
+	timerStopCmd.Flags().Duration("trim", 0, "End the entry this long before now, e.g. 10m")
+	timerStopCmd.Flags().Bool("non-billable", false, "Save the entry as non-billable")
+
+	timerAdjustCmd.Flags().String("start", "", "New start: a shift such as -20m, or HH:MM")
+	timerAdjustCmd.MarkFlagRequired("start")
+
+	timerStatusCmd.Flags().String("format", "", "Go template for the output, e.g. '{{.Client}} {{.Elapsed}}'")
+	timerStatusCmd.Flags().BoolP("quiet", "q", false, "Print nothing; report the state by exit code only")
+	timerStatusCmd.MarkFlagsMutuallyExclusive("format", "quiet")
 }
 
 // resolveClientID resolves a client by ID or name
@@ -199,16 +547,48 @@ func resolveClientID(ctx context.Context, idOrName string) (int64, error) {
 
 	// Try to find by name
 	client, err := appInstance.ClientRepo.GetByName(ctx, idOrName)
-	if err != nil {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return 0, err
 	}
 	if client == nil {
-		return 0, fmt.Errorf("client named '%s' not found", idOrName)
+		return 0, fmt.Errorf("client named '%s' not found%s", idOrName, suggestClients(ctx, idOrName))
 	}
 
 	return client.ID, nil
 }
 
+// suggestClients names the clients whose names resemble name, as a
+// "did you mean" hint for an error message
+func suggestClients(ctx context.Context, name string) string {
+	similar, err := appInstance.ClientService.Similar(ctx, name)
+	if err != nil || len(similar) == 0 {
+		return ""
+	}
+	names := make([]string, len(similar))
+	for i, c := range similar {
+		names[i] = fmt.Sprintf("%q (ID %d)", c.Name, c.ID)
+	}
+	return "; did you mean " + strings.Join(names, " or ") + "?"
+}
+
+// cliLocale returns the configured display locale
+func cliLocale() locale.Locale {
+	if appInstance == nil || appInstance.Config == nil {
+		return locale.Default()
+	}
+	return locale.FromConfig(appInstance.Config.Locale)
+}
+
+// formatMoney formats an amount using the configured locale
+func formatMoney(amount float64) string {
+	return cliLocale().Money(amount)
+}
+
+// formatDate formats a date (with year) using the configured locale
+func formatDate(t time.Time) string {
+	return cliLocale().FormatLongDate(t)
+}
+
 // formatDuration formats a duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	h := int(d.Hours())
@@ -11,12 +11,35 @@ import (
 var tuiCmd = &cobra.Command{
 	Use:   "tui",
 	Short: "Launch the terminal UI",
-	Long:  `Launch the interactive terminal user interface for timesink.`,
-	Run:   launchTUI,
+	Long: `Launch the interactive terminal user interface for timesink.
+
+Use --screen to open directly on a screen: dashboard, timer, entries,
+clients, invoices, estimates, reports, settings, or review.`,
+	Run: launchTUI,
+}
+
+func init() {
+	tuiCmd.Flags().String("screen", "dashboard", "Screen to open on start")
 }
 
 func launchTUI(cmd *cobra.Command, args []string) {
-	if err := tui.Run(appInstance); err != nil {
+	// The app is not initialized when only help was requested
+	if appInstance == nil {
+		cmd.Help()
+		return
+	}
+
+	start := tui.ScreenDashboard
+	if name, _ := cmd.Flags().GetString("screen"); name != "" {
+		screen, err := tui.ParseScreen(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		start = screen
+	}
+
+	if err := tui.Run(appInstance, start); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		os.Exit(1)
 	}
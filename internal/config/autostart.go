@@ -0,0 +1,60 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// AutoStartFor returns the first auto-start rule matching a directory or the
+// origin remote of the git repository it is in, or nil if none does. remote
+// is empty outside a repository.
+func (t *TimerConfig) AutoStartFor(dir, remote string) *AutoStartRule {
+	dir = filepath.Clean(dir)
+	remote = normalizeRemote(remote)
+	for i := range t.AutoStart {
+		rule := &t.AutoStart[i]
+		if rule.Path != "" && withinDir(dir, expandHome(rule.Path)) {
+			return rule
+		}
+		if repo := normalizeRemote(rule.Repo); repo != "" && remote != "" &&
+			(remote == repo || strings.HasPrefix(remote, repo+"/")) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// withinDir reports whether dir is root or below it
+func withinDir(dir, root string) bool {
+	rel, err := filepath.Rel(filepath.Clean(root), dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// expandHome replaces a leading "~" with the home directory
+func expandHome(path string) string {
+	if path == "~" {
+		return homeDir()
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(homeDir(), rest)
+	}
+	return path
+}
+
+// normalizeRemote reduces the forms of a git remote URL to host/path, so
+// "git@github.com:acme/site.git" and "https://github.com/acme/site" compare
+// equal, ignoring case
+func normalizeRemote(remote string) string {
+	remote = strings.ToLower(strings.TrimSpace(remote))
+	if _, rest, ok := strings.Cut(remote, "://"); ok {
+		remote = rest
+	} else if host, path, ok := strings.Cut(remote, ":"); ok && !strings.Contains(host, "/") {
+		// scp-like syntax, user@host:path
+		remote = host + "/" + path
+	}
+	if user, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(user, "/") {
+		remote = rest
+	}
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	return strings.TrimSuffix(remote, "/")
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestAutoStartFor_MatchesDirectoriesAndRemotes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	timer := TimerConfig{AutoStart: []AutoStartRule{
+		{Path: "~/work/acme", Client: "Acme"},
+		{Repo: "github.com/globex", Client: "Globex"},
+		{Repo: "https://gitlab.com/initech/tps.git", Client: "Initech"},
+	}}
+
+	tests := []struct {
+		name        string
+		dir, remote string
+		want        string
+	}{
+		{"the directory", filepath.Join(home, "work/acme"), "", "Acme"},
+		{"below the directory", filepath.Join(home, "work/acme/api/cmd"), "", "Acme"},
+		{"a sibling sharing the prefix", filepath.Join(home, "work/acme-old"), "", ""},
+		{"an owner's repository over ssh", "/src/site", "git@github.com:Globex/site.git", "Globex"},
+		{"another owner with the prefix", "/src/site", "git@github.com:globex-corp/site.git", ""},
+		{"the repository over ssh", "/src/tps", "ssh://git@gitlab.com/initech/tps", "Initech"},
+		{"no rule", "/tmp", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if rule := timer.AutoStartFor(tt.dir, tt.remote); rule != nil {
+				got = rule.Client
+			}
+			if got != tt.want {
+				t.Errorf("AutoStartFor(%q, %q) = %q, want %q", tt.dir, tt.remote, got, tt.want)
+			}
+		})
+	}
+}
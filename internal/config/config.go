@@ -16,10 +16,52 @@ type Config struct {
 
 	// User info for invoices
 	User UserConfig `yaml:"user"`
+
+	// Audit trail settings
+	Audit AuditConfig `yaml:"audit"`
+
+	// Display formatting for money, numbers, and dates
+	Locale LocaleConfig `yaml:"locale"`
+
+	// TUI colors
+	Theme ThemeConfig `yaml:"theme"`
+
+	// Structured log of changes, for diagnosing edits after the fact
+	Log LogConfig `yaml:"log"`
+
+	// Hours to work each weekday, marked in reports and checked on the
+	// dashboard
+	Workday WorkdayConfig `yaml:"workday"`
+
+	// Revenue reports
+	Reports ReportsConfig `yaml:"reports"`
+
+	// Timer display outside timesink
+	Timer TimerConfig `yaml:"timer"`
+
+	// Session locking
+	Security SecurityConfig `yaml:"security"`
+
+	// Executables run when timers stop, entries are created and invoices
+	// are finalized
+	Hooks HooksConfig `yaml:"hooks"`
+
+	// Outgoing mail server, used to send the weekly digest
+	SMTP SMTPConfig `yaml:"smtp"`
+
+	// Weekly summary of hours, unbilled time and unpaid invoices
+	Digest DigestConfig `yaml:"digest"`
+
+	// TUI key remapping: action name to the keys that trigger it
+	Keybindings map[string][]string `yaml:"keybindings,omitempty"`
+
+	// Values replaced by TIMESINK_* environment variables
+	overrides []envOverride
 }
 
 type DatabaseConfig struct {
-	Path string `yaml:"path"` // Path to SQLite database
+	Path           string `yaml:"path"`            // Path to SQLite database
+	AttachmentsDir string `yaml:"attachments_dir"` // Files attached to invoices, managed by timesink
 }
 
 type InvoiceConfig struct {
@@ -27,6 +69,172 @@ type InvoiceConfig struct {
 	DefaultTaxRate float64 `yaml:"default_tax_rate"` // Tax rate as decimal (0.0825 = 8.25%)
 	OutputDir      string  `yaml:"output_dir"`       // Directory for generated PDFs
 	NumberPrefix   string  `yaml:"number_prefix"`    // Invoice number prefix (e.g., "INV")
+	HourFormat     string  `yaml:"hour_format"`      // "hm" (7h 30m) or "decimal" (7.50)
+
+	// Tax lines added to each invoice, e.g. VAT plus a local surcharge.
+	// When set they replace default_tax_rate.
+	Taxes []TaxConfig `yaml:"taxes,omitempty"`
+
+	// Note printed on invoices to reverse-charge clients, whose tax is 0%
+	ReverseChargeNote string `yaml:"reverse_charge_note"`
+
+	// Notes printed at the bottom of new invoices, editable per invoice
+	Notes string `yaml:"notes"`
+
+	// Invoice labels by language code, e.g. de: {tax: "USt."}, overriding
+	// the built-in translations or adding a language
+	Translations map[string]map[string]string `yaml:"translations,omitempty"`
+
+	// Estimates are numbered apart from invoices, e.g. "EST-2026-001"
+	EstimatePrefix    string `yaml:"estimate_prefix"`
+	EstimateValidDays int    `yaml:"estimate_valid_days"` // Days an estimate's quote holds
+
+	// Clients are due an invoice once their unbilled time reaches this
+	// amount or they were last invoiced this many days ago; 0 turns either
+	// check off
+	RemindUnbilled  float64 `yaml:"remind_unbilled"`
+	RemindAfterDays int     `yaml:"remind_after_days"`
+
+	// Warn when invoicing entries that entries lint would flag
+	LintEntries bool `yaml:"lint_entries"`
+
+	// Open invoice files saved from the TUI with the default application
+	OpenAfterGenerate bool `yaml:"open_after_generate"`
+}
+
+// TaxConfig is one tax line on every invoice
+type TaxConfig struct {
+	Label string  `yaml:"label"` // e.g. "VAT"
+	Rate  float64 `yaml:"rate"`  // Rate as decimal (0.2 = 20%)
+}
+
+// DefaultReverseChargeNote is the reverse-charge note used unless configured
+const DefaultReverseChargeNote = "Reverse charge: VAT to be accounted for by the recipient"
+
+// Invoice hour formats
+const (
+	HourFormatHM      = "hm"
+	HourFormatDecimal = "decimal"
+)
+
+type AuditConfig struct {
+	RequireReason bool `yaml:"require_reason"` // Require a reason for TUI edits and deletes
+}
+
+type LogConfig struct {
+	Path  string `yaml:"path"`  // Log file; empty disables logging
+	Level string `yaml:"level"` // "debug", "info", "warn" or "error"
+}
+
+type WorkdayConfig struct {
+	TargetHours float64 `yaml:"target_hours"` // Hours to log each weekday; 0 turns the target off
+
+	// Time of day, HH:MM, that an entry logged as a duration starts at
+	// unless another start is given
+	Start string `yaml:"start"`
+
+	// Billable hours you aim to sell each week, planned against clients'
+	// expected demand; 0 turns capacity planning off
+	WeeklyBillableHours float64 `yaml:"weekly_billable_hours"`
+
+	// An entry whose description or notes contain one of these explains a
+	// weekday below target, e.g. a zero-length "day off" entry
+	DayOffWords []string `yaml:"day_off_words,omitempty"`
+}
+
+type ReportsConfig struct {
+	// Warn when one client earns more than this share of revenue, as a
+	// decimal (0.5 = 50%); 0 turns the warning off
+	ConcentrationLimit float64 `yaml:"concentration_limit"`
+}
+
+type TimerConfig struct {
+	WindowTitle bool `yaml:"window_title"` // Show the running timer in the terminal title while the TUI is open
+
+	// What the TUI does with a running timer when the computer wakes from
+	// sleep: "pause" it as of the sleep, "resume" it leaving the sleep out,
+	// or "off" to count the sleep
+	OnSleep string `yaml:"on_sleep"`
+
+	// Where new timers and entries are recorded as done: "hostname" for the
+	// machine's name, "directory" for the working directory, any other value
+	// as a fixed tag such as "remote", or empty to record nothing
+	Location string `yaml:"location,omitempty"`
+
+	// Timers `timer auto` starts for the directory or git repository it
+	// runs in; the first matching rule wins
+	AutoStart []AutoStartRule `yaml:"auto_start,omitempty"`
+}
+
+// AutoStartRule starts a timer for a client when `timer auto` runs inside a
+// directory or a clone of a git repository
+type AutoStartRule struct {
+	Path        string `yaml:"path,omitempty"`        // Directory, matching everything below it; "~" is the home directory
+	Repo        string `yaml:"repo,omitempty"`        // Origin remote, or an owner's remotes, e.g. "github.com/acme"
+	Client      string `yaml:"client"`                // Client ID or name
+	Description string `yaml:"description,omitempty"` // Timer description; defaults to the directory's name
+}
+
+// Timer sleep actions
+const (
+	OnSleepPause  = "pause"
+	OnSleepResume = "resume"
+	OnSleepOff    = "off"
+)
+
+// Timer locations looked up when a timer starts or an entry is created
+const (
+	LocationHostname  = "hostname"
+	LocationDirectory = "directory"
+)
+
+type SecurityConfig struct {
+	AutoLockMinutes int `yaml:"auto_lock_minutes"` // Lock the TUI after this much inactivity; 0 disables
+}
+
+type HooksConfig struct {
+	Dir            string `yaml:"dir"`             // Directory holding hooks named after their event
+	TimeoutSeconds int    `yaml:"timeout_seconds"` // Hooks still running after this long are killed
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`     // e.g. "smtp.fastmail.com"; empty disables sending
+	Port     int    `yaml:"port"`     // 587 for STARTTLS, 465 for TLS
+	Username string `yaml:"username"` // Empty sends without logging in
+	Password string `yaml:"password"` // Better set as TIMESINK_SMTP_PASSWORD
+	From     string `yaml:"from"`     // Sender address; defaults to user.email
+}
+
+type DigestConfig struct {
+	To string `yaml:"to"` // Recipient address; defaults to user.email
+}
+
+type LocaleConfig struct {
+	Name               string `yaml:"name"`                // Preset, e.g. "en-US", "en-GB", "de-DE"
+	CurrencySymbol     string `yaml:"currency_symbol"`     // Overrides the preset's symbol
+	DecimalSeparator   string `yaml:"decimal_separator"`   // Overrides the preset's decimal separator
+	ThousandsSeparator string `yaml:"thousands_separator"` // Overrides the preset's grouping separator
+	ShortDate          string `yaml:"short_date"`          // Go time layout without year, e.g. "Jan 2"
+	LongDate           string `yaml:"long_date"`           // Go time layout with year, e.g. "Jan 2, 2006"
+}
+
+type ThemeConfig struct {
+	Name   string      `yaml:"name"`   // "dark", "light", or "high-contrast"
+	Colors ThemeColors `yaml:"colors"` // Hex overrides applied on top of the named theme
+}
+
+type ThemeColors struct {
+	Primary      string `yaml:"primary,omitempty"`
+	Accent       string `yaml:"accent,omitempty"`
+	Muted        string `yaml:"muted,omitempty"`
+	Success      string `yaml:"success,omitempty"`
+	Warning      string `yaml:"warning,omitempty"`
+	Error        string `yaml:"error,omitempty"`
+	NonBillable  string `yaml:"non_billable,omitempty"`
+	Help         string `yaml:"help,omitempty"`
+	Border       string `yaml:"border,omitempty"`
+	Footer       string `yaml:"footer,omitempty"`
+	SelectedText string `yaml:"selected_text,omitempty"`
 }
 
 type UserConfig struct {
@@ -34,34 +242,71 @@ type UserConfig struct {
 	Email   string `yaml:"email"`
 	Address string `yaml:"address"`
 	Phone   string `yaml:"phone"`
+
+	// Bank details, PayPal address or terms printed at the bottom of new
+	// invoices, editable per invoice
+	PaymentInstructions string `yaml:"payment_instructions"`
 }
 
-// DefaultConfigPath returns ~/.config/timesink/config.yaml
+// DefaultConfigPath returns $XDG_CONFIG_HOME/timesink/config.yaml
 func DefaultConfigPath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		// Fallback to current directory if home dir unavailable
-		return filepath.Join(".", ".config", "timesink", "config.yaml")
-	}
-	return filepath.Join(homeDir, ".config", "timesink", "config.yaml")
+	return ProfileConfigPath(DefaultProfile)
 }
 
 // DefaultConfig returns sensible defaults
 func DefaultConfig() *Config {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		homeDir = "."
-	}
+	return defaultConfigFor(DefaultProfile)
+}
 
+// defaultConfigFor returns defaults with the database and invoices in the
+// profile's data directory and the log in its state directory
+func defaultConfigFor(profile string) *Config {
 	return &Config{
 		Database: DatabaseConfig{
-			Path: filepath.Join(homeDir, ".config", "timesink", "timesink.db"),
+			Path:           filepath.Join(ProfileDataDir(profile), "timesink.db"),
+			AttachmentsDir: filepath.Join(ProfileDataDir(profile), "attachments"),
 		},
 		Invoice: InvoiceConfig{
 			DefaultDueDays: 30,
 			DefaultTaxRate: 0.0,
-			OutputDir:      ".",
+			OutputDir:      filepath.Join(ProfileDataDir(profile), "invoices"),
 			NumberPrefix:   "INV",
+			HourFormat:     HourFormatHM,
+
+			ReverseChargeNote: DefaultReverseChargeNote,
+
+			EstimatePrefix:    "EST",
+			EstimateValidDays: 30,
+
+			RemindUnbilled:  500,
+			RemindAfterDays: 30,
+		},
+		Locale: LocaleConfig{
+			Name: "en-US",
+		},
+		Theme: ThemeConfig{
+			Name: "dark",
+		},
+		Workday: WorkdayConfig{
+			Start:       "09:00",
+			DayOffWords: []string{"day off", "holiday", "vacation", "sick"},
+		},
+		Reports: ReportsConfig{
+			ConcentrationLimit: 0.5,
+		},
+		Log: LogConfig{
+			Path:  filepath.Join(ProfileStateDir(profile), "timesink.log"),
+			Level: "info",
+		},
+		Hooks: HooksConfig{
+			Dir:            filepath.Join(ProfileDir(profile), "hooks"),
+			TimeoutSeconds: 10,
+		},
+		Timer: TimerConfig{
+			OnSleep: OnSleepPause,
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
 		},
 		User: UserConfig{
 			Name:    "",
@@ -74,24 +319,36 @@ func DefaultConfig() *Config {
 
 // Load loads config from the given path, or returns defaults if file doesn't exist
 func Load(path string) (*Config, error) {
-	// If file doesn't exist, return defaults
+	return load(path, DefaultConfig())
+}
+
+// load reads path over the given defaults, then applies environment
+// overrides
+func load(path string, cfg *Config) (*Config, error) {
+	if err := readFile(path, cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile parses path into cfg. A missing file leaves cfg unchanged.
+func readFile(path string, cfg *Config) error {
+	// If file doesn't exist, keep defaults
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+		return nil
 	}
 
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Parse YAML
-	cfg := DefaultConfig()
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, err
-	}
-
-	return cfg, nil
+	return yaml.Unmarshal(data, cfg)
 }
 
 // LoadDefault loads from the default config path
@@ -99,8 +356,11 @@ func LoadDefault() (*Config, error) {
 	return Load(DefaultConfigPath())
 }
 
-// Save writes the config to the given path
+// Save writes the config to the given path. Values set by environment
+// variables are not written.
 func (c *Config) Save(path string) error {
+	c = c.withoutOverrides()
+
 	// Create parent directories if they don't exist
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -130,5 +390,10 @@ func (c *Config) EnsureDirectories() error {
 		return err
 	}
 
+	// Attachments are private documents such as contracts and receipts
+	if err := os.MkdirAll(c.Database.AttachmentsDir, 0700); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts every variable that overrides a config value. The rest
+// of the name is the value's key in config.yaml, upper-cased with dots as
+// underscores: invoice.number_prefix is TIMESINK_INVOICE_NUMBER_PREFIX.
+const EnvPrefix = "TIMESINK_"
+
+// ConfigEnv selects the config file when --config is not given
+const ConfigEnv = "TIMESINK_CONFIG"
+
+// envOverride records a value taken from the environment and the value it
+// replaced, so that saving the config does not write the override to disk
+type envOverride struct {
+	name     string
+	index    []int
+	original reflect.Value
+	value    reflect.Value
+}
+
+// EnvVars lists every variable that can override a config value
+func EnvVars() []string {
+	var names []string
+	walkConfig(reflect.ValueOf(&Config{}).Elem(), nil, "", func(name string, _ []int, _ reflect.Value) error {
+		names = append(names, name)
+		return nil
+	})
+	return names
+}
+
+// Overrides lists the variables that replaced values in this config
+func (c *Config) Overrides() []string {
+	names := make([]string, len(c.overrides))
+	for i, o := range c.overrides {
+		names[i] = o.name
+	}
+	return names
+}
+
+// applyEnv replaces config values with any TIMESINK_* variables that are set
+func (c *Config) applyEnv() error {
+	root := reflect.ValueOf(c).Elem()
+	return walkConfig(root, nil, "", func(name string, index []int, field reflect.Value) error {
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			return nil
+		}
+
+		original := reflect.New(field.Type()).Elem()
+		original.Set(field)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a whole number", name, raw)
+			}
+			field.SetInt(int64(n))
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", name, raw)
+			}
+			field.SetFloat(f)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not true or false", name, raw)
+			}
+			field.SetBool(b)
+		}
+
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		c.overrides = append(c.overrides, envOverride{name: name, index: index, original: original, value: value})
+		return nil
+	})
+}
+
+// withoutOverrides returns the config as it was before applyEnv, keeping
+// any value that has since been changed, e.g. from the Settings screen
+func (c *Config) withoutOverrides() *Config {
+	if len(c.overrides) == 0 {
+		return c
+	}
+
+	out := *c
+	root := reflect.ValueOf(&out).Elem()
+	for _, o := range c.overrides {
+		field := root.FieldByIndex(o.index)
+		if field.Equal(o.value) {
+			field.Set(o.original)
+		}
+	}
+	return &out
+}
+
+// walkConfig calls fn for each string, number, and bool setting, with its
+// variable name and field index. Maps such as keybindings are skipped.
+func walkConfig(v reflect.Value, index []int, prefix string, fn func(name string, index []int, field reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if !sf.IsExported() || key == "" || key == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		name := prefix + strings.ToUpper(key)
+		field := v.Field(i)
+
+		switch field.Kind() {
+		case reflect.Struct:
+			if err := walkConfig(field, fieldIndex, name+"_", fn); err != nil {
+				return err
+			}
+		case reflect.String, reflect.Int, reflect.Float64, reflect.Bool:
+			if err := fn(EnvPrefix+name, fieldIndex, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
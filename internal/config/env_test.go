@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestApplyEnv_OverridesNestedValues(t *testing.T) {
+	t.Setenv("TIMESINK_DATABASE_PATH", "/data/timesink.db")
+	t.Setenv("TIMESINK_INVOICE_DEFAULT_DUE_DAYS", "14")
+	t.Setenv("TIMESINK_INVOICE_DEFAULT_TAX_RATE", "0.2")
+	t.Setenv("TIMESINK_AUDIT_REQUIRE_REASON", "true")
+	t.Setenv("TIMESINK_THEME_COLORS_PRIMARY", "#ff0000")
+
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Database.Path != "/data/timesink.db" || cfg.Invoice.DefaultDueDays != 14 ||
+		cfg.Invoice.DefaultTaxRate != 0.2 || !cfg.Audit.RequireReason || cfg.Theme.Colors.Primary != "#ff0000" {
+		t.Fatalf("overrides not applied: %+v", cfg)
+	}
+	if len(cfg.Overrides()) != 5 {
+		t.Errorf("expected 5 overrides, got %v", cfg.Overrides())
+	}
+}
+
+func TestApplyEnv_RejectsBadNumbers(t *testing.T) {
+	t.Setenv("TIMESINK_INVOICE_DEFAULT_DUE_DAYS", "two weeks")
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("expected an error for a non-numeric override")
+	}
+}
+
+func TestSave_DoesNotPersistOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := DefaultConfig().Save(path); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	t.Setenv("TIMESINK_INVOICE_NUMBER_PREFIX", "ENV")
+	t.Setenv("TIMESINK_INVOICE_OUTPUT_DIR", "/env/out")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A value changed after loading is saved; an untouched override is not
+	cfg.Invoice.OutputDir = "/chosen/out"
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	os.Unsetenv("TIMESINK_INVOICE_NUMBER_PREFIX")
+	os.Unsetenv("TIMESINK_INVOICE_OUTPUT_DIR")
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved.Invoice.NumberPrefix != "INV" {
+		t.Errorf("override was written to disk: prefix %q", saved.Invoice.NumberPrefix)
+	}
+	if saved.Invoice.OutputDir != "/chosen/out" {
+		t.Errorf("edited value was not saved: output dir %q", saved.Invoice.OutputDir)
+	}
+	if cfg.Invoice.NumberPrefix != "ENV" {
+		t.Errorf("saving changed the in-memory config")
+	}
+}
+
+func TestEnvVars_FollowYAMLKeys(t *testing.T) {
+	vars := EnvVars()
+	for _, want := range []string{"TIMESINK_DATABASE_PATH", "TIMESINK_INVOICE_NUMBER_PREFIX", "TIMESINK_LOCALE_SHORT_DATE"} {
+		if !slices.Contains(vars, want) {
+			t.Errorf("missing %s in %v", want, vars)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Move is a file relocated from the pre-XDG layout
+type Move struct {
+	From string
+	To   string
+}
+
+// MigrateLegacyLayout moves files from ~/.config/timesink, where everything
+// lived before the XDG split, to the XDG config, data, and state
+// directories. A file is only moved when nothing exists at its new path.
+// Config files that name the old default database or log path are updated
+// to the new one; other explicit paths are left alone.
+func MigrateLegacyLayout() ([]Move, error) {
+	legacy := legacyDir()
+	if _, err := os.Stat(legacy); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var moves []Move
+	move := func(from, to string) error {
+		moved, err := moveIfAbsent(from, to)
+		if moved {
+			moves = append(moves, Move{From: from, To: to})
+		}
+		return err
+	}
+
+	if err := move(filepath.Join(legacy, currentProfileFile), filepath.Join(BaseDir(), currentProfileFile)); err != nil {
+		return moves, err
+	}
+
+	profiles := []string{DefaultProfile}
+	if dirEntries, err := os.ReadDir(filepath.Join(legacy, "profiles")); err == nil {
+		for _, d := range dirEntries {
+			if d.IsDir() && ValidateProfileName(d.Name()) == nil && d.Name() != DefaultProfile {
+				profiles = append(profiles, d.Name())
+			}
+		}
+	}
+
+	for _, profile := range profiles {
+		from := profileSubdir(legacy, profile)
+		if err := move(filepath.Join(from, "config.yaml"), ProfileConfigPath(profile)); err != nil {
+			return moves, err
+		}
+		if err := migrateProfileFiles(profile, from, move); err != nil {
+			return moves, fmt.Errorf("failed to migrate profile %q: %w", profile, err)
+		}
+	}
+
+	return moves, nil
+}
+
+// migrateProfileFiles moves a profile's database and log out of its legacy
+// directory, if the profile's config still points at them
+func migrateProfileFiles(profile, legacy string, move func(from, to string) error) error {
+	cfgPath := ProfileConfigPath(profile)
+	cfg := defaultConfigFor(profile)
+	if err := readFile(cfgPath, cfg); err != nil {
+		return fmt.Errorf("failed to read %s: %w", cfgPath, err)
+	}
+	defaults := defaultConfigFor(profile)
+	rewrite := false
+
+	oldDB := filepath.Join(legacy, "timesink.db")
+	if cfg.Database.Path == oldDB || cfg.Database.Path == defaults.Database.Path {
+		// SQLite's write-ahead log and shared memory files travel with it
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := move(oldDB+suffix, defaults.Database.Path+suffix); err != nil {
+				return err
+			}
+		}
+		rewrite = rewrite || cfg.Database.Path == oldDB
+		cfg.Database.Path = defaults.Database.Path
+	}
+
+	oldLog := filepath.Join(legacy, "timesink.log")
+	if cfg.Log.Path == oldLog || cfg.Log.Path == defaults.Log.Path {
+		if err := move(oldLog, defaults.Log.Path); err != nil {
+			return err
+		}
+		rewrite = rewrite || cfg.Log.Path == oldLog
+		cfg.Log.Path = defaults.Log.Path
+	}
+
+	if rewrite {
+		return cfg.Save(cfgPath)
+	}
+	return nil
+}
+
+// moveIfAbsent renames from to to, copying across file systems, unless from
+// is missing, to already exists, or they are the same path
+func moveIfAbsent(from, to string) (bool, error) {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return false, nil
+	}
+	if _, err := os.Stat(from); err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(to); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+		return false, fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err == nil {
+		return true, nil
+	}
+
+	if err := copyFile(from, to); err != nil {
+		return false, fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+	}
+	return true, os.Remove(from)
+}
+
+// copyFile copies a file, keeping its permissions
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(to)
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeHome points HOME at a temp directory with no XDG overrides
+func fakeHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	return home
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXDGDirs(t *testing.T) {
+	home := fakeHome(t)
+	if got := DataDir(); got != filepath.Join(home, ".local", "share", "timesink") {
+		t.Errorf("default data dir: got %s", got)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "/var/state")
+	if got := StateDir(); got != "/var/state/timesink" {
+		t.Errorf("XDG_STATE_HOME not used: got %s", got)
+	}
+
+	// Relative paths are invalid per the spec
+	t.Setenv("XDG_CONFIG_HOME", "relative/config")
+	if got := BaseDir(); got != filepath.Join(home, ".config", "timesink") {
+		t.Errorf("relative XDG_CONFIG_HOME not ignored: got %s", got)
+	}
+}
+
+func TestMigrateLegacyLayout(t *testing.T) {
+	home := fakeHome(t)
+	legacy := filepath.Join(home, ".config", "timesink")
+
+	// Default profile: config saved with the old absolute paths
+	writeFile(t, filepath.Join(legacy, "config.yaml"),
+		"database:\n  path: "+filepath.Join(legacy, "timesink.db")+"\nlog:\n  path: "+filepath.Join(legacy, "timesink.log")+"\n")
+	writeFile(t, filepath.Join(legacy, "timesink.db"), "db")
+	writeFile(t, filepath.Join(legacy, "timesink.db-wal"), "wal")
+	writeFile(t, filepath.Join(legacy, "timesink.log"), "log")
+
+	// Named profile relying on defaults, with its config in a moved XDG_CONFIG_HOME
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	writeFile(t, filepath.Join(legacy, "profiles", "llc", "config.yaml"), "invoice:\n  number_prefix: LLC\n")
+	writeFile(t, filepath.Join(legacy, "profiles", "llc", "timesink.db"), "llc-db")
+
+	if _, err := MigrateLegacyLayout(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := filepath.Join(home, ".local", "share", "timesink")
+	for path, want := range map[string]string{
+		filepath.Join(data, "timesink.db"):                                 "db",
+		filepath.Join(data, "timesink.db-wal"):                             "wal",
+		filepath.Join(home, ".local", "state", "timesink", "timesink.log"): "log",
+		filepath.Join(data, "profiles", "llc", "timesink.db"):              "llc-db",
+	} {
+		if got, err := os.ReadFile(path); err != nil || string(got) != want {
+			t.Errorf("%s: got %q, %v", path, got, err)
+		}
+	}
+
+	cfg, err := LoadProfile(DefaultProfile)
+	if err != nil {
+		t.Fatalf("failed to load default profile: %v", err)
+	}
+	if cfg.Database.Path != filepath.Join(data, "timesink.db") {
+		t.Errorf("database path not rewritten: %s", cfg.Database.Path)
+	}
+
+	llc, err := LoadProfile("llc")
+	if err != nil {
+		t.Fatalf("failed to load llc profile: %v", err)
+	}
+	if llc.Invoice.NumberPrefix != "LLC" {
+		t.Errorf("llc config not moved: prefix %q", llc.Invoice.NumberPrefix)
+	}
+
+	moves, err := MigrateLegacyLayout()
+	if err != nil || len(moves) != 0 {
+		t.Errorf("second run should do nothing, got %v, %v", moves, err)
+	}
+}
+
+func TestMigrateLegacyLayout_KeepsExplicitPaths(t *testing.T) {
+	home := fakeHome(t)
+	legacy := filepath.Join(home, ".config", "timesink")
+	custom := filepath.Join(home, "Dropbox", "timesink.db")
+
+	writeFile(t, filepath.Join(legacy, "config.yaml"), "database:\n  path: "+custom+"\n")
+	writeFile(t, filepath.Join(legacy, "timesink.db"), "stale")
+
+	if _, err := MigrateLegacyLayout(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(legacy, "timesink.db")); err != nil {
+		t.Errorf("file not in use by the config should stay put: %v", err)
+	}
+	cfg, _ := LoadProfile(DefaultProfile)
+	if cfg.Database.Path != custom {
+		t.Errorf("explicit database path changed to %s", cfg.Database.Path)
+	}
+}
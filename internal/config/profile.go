@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile whose files live directly in the base
+// directories, as they did before profiles existed
+const DefaultProfile = "default"
+
+// ProfileEnv selects a profile when --profile is not given
+const ProfileEnv = "TIMESINK_PROFILE"
+
+// currentProfileFile records the profile chosen with `profile switch`
+const currentProfileFile = "current_profile"
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// profileSubdir returns a profile's directory under base. The default
+// profile uses base itself; named profiles use base/profiles/<name>.
+func profileSubdir(base, name string) string {
+	if name == DefaultProfile {
+		return base
+	}
+	return filepath.Join(base, "profiles", name)
+}
+
+// ProfileDir returns the directory holding a profile's config
+func ProfileDir(name string) string {
+	return profileSubdir(BaseDir(), name)
+}
+
+// ProfileDataDir returns the directory holding a profile's database and
+// invoices
+func ProfileDataDir(name string) string {
+	return profileSubdir(DataDir(), name)
+}
+
+// ProfileStateDir returns the directory holding a profile's log
+func ProfileStateDir(name string) string {
+	return profileSubdir(StateDir(), name)
+}
+
+// ProfileConfigPath returns the config file of a profile
+func ProfileConfigPath(name string) string {
+	return filepath.Join(ProfileDir(name), "config.yaml")
+}
+
+// LoadProfile loads a profile's config. Defaults place the database and log
+// in the profile's data and state directories.
+func LoadProfile(name string) (*Config, error) {
+	return LoadProfileFile(name, ProfileConfigPath(name))
+}
+
+// LoadProfileFile loads the config at path, with defaults from the profile
+func LoadProfileFile(name, path string) (*Config, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	if !ProfileExists(name) {
+		return nil, fmt.Errorf("profile %q does not exist (create it with `timesink profile create %s`)", name, name)
+	}
+	return load(path, defaultConfigFor(name))
+}
+
+// ValidateProfileName checks that a name is safe to use as a directory name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ProfileExists reports whether a profile has been created. The default
+// profile always exists.
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(ProfileDir(name))
+	return err == nil && info.IsDir()
+}
+
+// ListProfiles returns the default profile followed by named profiles in
+// alphabetical order
+func ListProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	dirEntries, err := os.ReadDir(filepath.Join(BaseDir(), "profiles"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var named []string
+	for _, d := range dirEntries {
+		if d.IsDir() && ValidateProfileName(d.Name()) == nil && d.Name() != DefaultProfile {
+			named = append(named, d.Name())
+		}
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
+
+// CreateProfile creates a named profile with a default config
+func CreateProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if ProfileExists(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	if err := os.MkdirAll(ProfileDir(name), 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	return defaultConfigFor(name).Save(ProfileConfigPath(name))
+}
+
+// CurrentProfile returns the profile chosen with `profile switch`, or the
+// default profile if none has been chosen
+func CurrentProfile() string {
+	data, err := os.ReadFile(filepath.Join(BaseDir(), currentProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil {
+		return DefaultProfile
+	}
+	return name
+}
+
+// SetCurrentProfile makes name the profile used when neither --profile nor
+// TIMESINK_PROFILE is given
+func SetCurrentProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	if err := os.MkdirAll(BaseDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(BaseDir(), currentProfileFile), []byte(name+"\n"), 0644)
+}
+
+// ResolveProfile picks the active profile: the --profile flag, then
+// TIMESINK_PROFILE, then the switched profile
+func ResolveProfile(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(ProfileEnv); env != "" {
+		return env
+	}
+	return CurrentProfile()
+}
+
+// ResolveConfigPath picks the config file: the --config flag, then
+// TIMESINK_CONFIG, then the profile's config.yaml
+func ResolveConfigPath(profile, flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(ConfigEnv); env != "" {
+		return env
+	}
+	return ProfileConfigPath(profile)
+}
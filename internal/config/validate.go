@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Validate reports settings that cannot work, naming each by its key in
+// config.yaml. All problems are returned together.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if strings.TrimSpace(c.Database.Path) == "" {
+		add("database.path is required")
+	}
+	if strings.TrimSpace(c.Database.AttachmentsDir) == "" {
+		add("database.attachments_dir is required")
+	}
+
+	if strings.TrimSpace(c.Invoice.OutputDir) == "" {
+		add(`invoice.output_dir is required (use "." for the current directory)`)
+	}
+	if strings.TrimSpace(c.Invoice.NumberPrefix) == "" {
+		add(`invoice.number_prefix is required (e.g. "INV")`)
+	}
+	if strings.TrimSpace(c.Invoice.EstimatePrefix) == "" {
+		add(`invoice.estimate_prefix is required (e.g. "EST")`)
+	} else if c.Invoice.EstimatePrefix == c.Invoice.NumberPrefix {
+		add("invoice.estimate_prefix must differ from invoice.number_prefix (both %q)", c.Invoice.EstimatePrefix)
+	}
+	if c.Invoice.EstimateValidDays <= 0 {
+		add("invoice.estimate_valid_days must be at least 1 (got %d)", c.Invoice.EstimateValidDays)
+	}
+	if c.Invoice.DefaultDueDays <= 0 {
+		add("invoice.default_due_days must be at least 1 (got %d)", c.Invoice.DefaultDueDays)
+	}
+	if c.Invoice.DefaultTaxRate < 0 || c.Invoice.DefaultTaxRate > 1 {
+		add("invoice.default_tax_rate must be a decimal between 0 and 1, e.g. 0.0825 for 8.25%% (got %g)",
+			c.Invoice.DefaultTaxRate)
+	}
+	for i, tax := range c.Invoice.Taxes {
+		if strings.TrimSpace(tax.Label) == "" {
+			add("invoice.taxes[%d].label is required (e.g. \"VAT\")", i)
+		}
+		if tax.Rate < 0 || tax.Rate > 1 {
+			add("invoice.taxes[%d].rate must be a decimal between 0 and 1, e.g. 0.2 for 20%% (got %g)", i, tax.Rate)
+		}
+	}
+	if c.Invoice.RemindUnbilled < 0 {
+		add("invoice.remind_unbilled must be 0 (off) or more (got %g)", c.Invoice.RemindUnbilled)
+	}
+	if c.Invoice.RemindAfterDays < 0 {
+		add("invoice.remind_after_days must be 0 (off) or more (got %d)", c.Invoice.RemindAfterDays)
+	}
+	switch c.Invoice.HourFormat {
+	case "", HourFormatHM, HourFormatDecimal:
+	default:
+		add("invoice.hour_format must be %q or %q (got %q)", HourFormatHM, HourFormatDecimal, c.Invoice.HourFormat)
+	}
+
+	if c.Workday.TargetHours < 0 || c.Workday.TargetHours > 24 {
+		add("workday.target_hours must be between 0 (off) and 24 (got %g)", c.Workday.TargetHours)
+	}
+	if c.Workday.Start != "" {
+		if _, err := time.Parse("15:04", c.Workday.Start); err != nil {
+			add("workday.start must be a time of day as HH:MM, e.g. 09:00 (got %q)", c.Workday.Start)
+		}
+	}
+	if c.Workday.WeeklyBillableHours < 0 || c.Workday.WeeklyBillableHours > 168 {
+		add("workday.weekly_billable_hours must be between 0 (off) and 168 (got %g)", c.Workday.WeeklyBillableHours)
+	}
+
+	if c.Reports.ConcentrationLimit < 0 || c.Reports.ConcentrationLimit > 1 {
+		add("reports.concentration_limit must be a decimal between 0 (off) and 1, e.g. 0.5 for 50%% (got %g)",
+			c.Reports.ConcentrationLimit)
+	}
+
+	switch c.Timer.OnSleep {
+	case "", OnSleepPause, OnSleepResume, OnSleepOff:
+	default:
+		add("timer.on_sleep must be %q, %q or %q (got %q)", OnSleepPause, OnSleepResume, OnSleepOff, c.Timer.OnSleep)
+	}
+	for i, rule := range c.Timer.AutoStart {
+		if strings.TrimSpace(rule.Client) == "" {
+			add("timer.auto_start[%d].client is required (a client ID or name)", i)
+		}
+		if strings.TrimSpace(rule.Path) == "" && strings.TrimSpace(rule.Repo) == "" {
+			add("timer.auto_start[%d] needs a path or a repo to match", i)
+		}
+	}
+
+	if c.Security.AutoLockMinutes < 0 {
+		add("security.auto_lock_minutes must be 0 (off) or more (got %d)", c.Security.AutoLockMinutes)
+	}
+
+	if c.Hooks.TimeoutSeconds < 1 {
+		add("hooks.timeout_seconds must be at least 1 (got %d)", c.Hooks.TimeoutSeconds)
+	}
+
+	if c.SMTP.Port < 1 || c.SMTP.Port > 65535 {
+		add("smtp.port must be between 1 and 65535 (got %d)", c.SMTP.Port)
+	}
+
+	if c.Log.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+			add("log.level must be debug, info, warn or error (got %q)", c.Log.Level)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("defaults should be valid, got %v", err)
+	}
+}
+
+func TestValidate_RejectsNonsense(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"negative due days", func(c *Config) { c.Invoice.DefaultDueDays = -3 }, "invoice.default_due_days"},
+		{"tax over 100%", func(c *Config) { c.Invoice.DefaultTaxRate = 8.25 }, "invoice.default_tax_rate"},
+		{"missing output dir", func(c *Config) { c.Invoice.OutputDir = " " }, "invoice.output_dir"},
+		{"missing prefix", func(c *Config) { c.Invoice.NumberPrefix = "" }, "invoice.number_prefix"},
+		{"estimate prefix shared", func(c *Config) { c.Invoice.EstimatePrefix = c.Invoice.NumberPrefix }, "invoice.estimate_prefix"},
+		{"zero estimate validity", func(c *Config) { c.Invoice.EstimateValidDays = 0 }, "invoice.estimate_valid_days"},
+		{"unlabeled tax", func(c *Config) { c.Invoice.Taxes = []TaxConfig{{Rate: 0.2}} }, "invoice.taxes[0].label"},
+		{"tax line over 100%", func(c *Config) { c.Invoice.Taxes = []TaxConfig{{Label: "VAT", Rate: 20}} }, "invoice.taxes[0].rate"},
+		{"negative reminder days", func(c *Config) { c.Invoice.RemindAfterDays = -1 }, "invoice.remind_after_days"},
+		{"unknown hour format", func(c *Config) { c.Invoice.HourFormat = "minutes" }, "invoice.hour_format"},
+		{"unknown log level", func(c *Config) { c.Log.Level = "loud" }, "log.level"},
+		{"target over a day", func(c *Config) { c.Workday.TargetHours = 25 }, "workday.target_hours"},
+		{"workday start not a time", func(c *Config) { c.Workday.Start = "9am" }, "workday.start"},
+		{"billable target over a week", func(c *Config) { c.Workday.WeeklyBillableHours = 200 }, "workday.weekly_billable_hours"},
+		{"concentration as a percent", func(c *Config) { c.Reports.ConcentrationLimit = 50 }, "reports.concentration_limit"},
+		{"unknown sleep action", func(c *Config) { c.Timer.OnSleep = "stop" }, "timer.on_sleep"},
+		{"auto-start rule without a client", func(c *Config) { c.Timer.AutoStart = []AutoStartRule{{Path: "~/work/acme"}} }, "timer.auto_start[0].client"},
+		{"auto-start rule matching nothing", func(c *Config) { c.Timer.AutoStart = []AutoStartRule{{Client: "Acme"}} }, "timer.auto_start[0] needs a path or a repo"},
+		{"negative auto-lock", func(c *Config) { c.Security.AutoLockMinutes = -1 }, "security.auto_lock_minutes"},
+		{"zero hook timeout", func(c *Config) { c.Hooks.TimeoutSeconds = 0 }, "hooks.timeout_seconds"},
+		{"smtp port out of range", func(c *Config) { c.SMTP.Port = 70000 }, "smtp.port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error mentioning %s, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Invoice.DefaultDueDays = 0
+	cfg.Invoice.OutputDir = ""
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 2 {
+		t.Fatalf("expected 2 problems, got %q", err)
+	}
+}
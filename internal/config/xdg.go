@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// appDir names timesink's subdirectory in each XDG base directory
+const appDir = "timesink"
+
+// xdgDir returns $env/timesink, or ~/<fallback>/timesink when the variable
+// is unset. The XDG spec says relative paths must be ignored.
+func xdgDir(env string, fallback ...string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appDir)
+	}
+	return filepath.Join(append(append([]string{homeDir()}, fallback...), appDir)...)
+}
+
+// homeDir returns the user's home directory, or "." if it is unknown
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
+
+// BaseDir returns the config directory, $XDG_CONFIG_HOME/timesink
+// (~/.config/timesink by default)
+func BaseDir() string {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// DataDir returns the directory for the database and invoices,
+// $XDG_DATA_HOME/timesink (~/.local/share/timesink by default)
+func DataDir() string {
+	return xdgDir("XDG_DATA_HOME", ".local", "share")
+}
+
+// StateDir returns the directory for logs, $XDG_STATE_HOME/timesink
+// (~/.local/state/timesink by default)
+func StateDir() string {
+	return xdgDir("XDG_STATE_HOME", ".local", "state")
+}
+
+// legacyDir is where every file lived before the XDG split
+func legacyDir() string {
+	return filepath.Join(homeDir(), ".config", appDir)
+}
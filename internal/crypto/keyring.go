@@ -1,5 +1,10 @@
 package crypto
 
+import (
+	"errors"
+	"strings"
+)
+
 // Keyring provides secure key storage abstraction
 type Keyring interface {
 	GetKey() (string, error)
@@ -11,9 +16,88 @@ type Keyring interface {
 const (
 	ServiceName = "timesink"
 	KeyName     = "db-encryption-key"
+	KeyEnv      = "TIMESINK_DB_KEY"
 )
 
-// NewKeyring returns the best available keyring implementation
-func NewKeyring() Keyring {
-	return newPlatformKeyring()
+// defaultProfile keeps the original key name and variable, so existing
+// databases open without changes
+const defaultProfile = "default"
+
+// NewKeyring returns the keyring for the given profile: the system keyring,
+// falling back to an environment variable when it is unreachable or has no
+// key. Each profile has its own key.
+func NewKeyring(profile string) Keyring {
+	return chainKeyring{newSystemKeyring(profile), newEnvKeyring(profile)}
+}
+
+// chainKeyring uses the first keyring in the chain that succeeds
+type chainKeyring []Keyring
+
+// GetKey returns the key from the first keyring that has one
+func (c chainKeyring) GetKey() (string, error) {
+	var errs []error
+	for _, k := range c {
+		key, err := k.GetKey()
+		if err == nil {
+			return key, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+// SetKey stores the key in the first keyring that accepts it. If none do,
+// the last keyring's error explains how to provide the key instead.
+func (c chainKeyring) SetKey(password string) error {
+	var err error
+	for _, k := range c {
+		if err = k.SetKey(password); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// DeleteKey removes the key from every keyring that holds it
+func (c chainKeyring) DeleteKey() error {
+	var errs []error
+	deleted := false
+	for _, k := range c {
+		if err := k.DeleteKey(); err != nil {
+			errs = append(errs, err)
+		} else {
+			deleted = true
+		}
+	}
+	if deleted {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// IsAvailable reports whether any keyring in the chain is usable
+func (c chainKeyring) IsAvailable() bool {
+	for _, k := range c {
+		if k.IsAvailable() {
+			return true
+		}
+	}
+	return false
+}
+
+// keyNameFor returns the keychain account holding a profile's key
+func keyNameFor(profile string) string {
+	if profile == "" || profile == defaultProfile {
+		return KeyName
+	}
+	return KeyName + ":" + profile
+}
+
+// KeyEnvFor returns the environment variable holding a profile's key, e.g.
+// TIMESINK_DB_KEY_MY_LLC for profile "my-llc"
+func KeyEnvFor(profile string) string {
+	if profile == "" || profile == defaultProfile {
+		return KeyEnv
+	}
+	return KeyEnv + "_" + strings.ToUpper(strings.ReplaceAll(profile, "-", "_"))
 }
@@ -0,0 +1,47 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// envKeyring reads the key from an environment variable. It is the last
+// resort when no system keyring is reachable, e.g. on a headless server.
+type envKeyring struct {
+	env string
+}
+
+func newEnvKeyring(profile string) Keyring {
+	return &envKeyring{env: KeyEnvFor(profile)}
+}
+
+// GetKey retrieves the encryption key from the profile's environment
+// variable (TIMESINK_DB_KEY for the default profile)
+func (k *envKeyring) GetKey() (string, error) {
+	key := os.Getenv(k.env)
+	if key == "" {
+		return "", fmt.Errorf("%s environment variable not set", k.env)
+	}
+
+	return key, nil
+}
+
+// SetKey returns an error suggesting to set the environment variable
+func (k *envKeyring) SetKey(password string) error {
+	if password == "" {
+		return errors.New("password cannot be empty")
+	}
+
+	return fmt.Errorf("no system keyring available: please set %s environment variable to '%s'", k.env, password)
+}
+
+// DeleteKey returns an error suggesting to unset the environment variable
+func (k *envKeyring) DeleteKey() error {
+	return fmt.Errorf("no system keyring available: please unset %s environment variable manually", k.env)
+}
+
+// IsAvailable checks if the profile's key environment variable is set
+func (k *envKeyring) IsAvailable() bool {
+	return os.Getenv(k.env) != ""
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeKeyring is an in-memory keyring; unavailable ones fail every call
+type fakeKeyring struct {
+	key       string
+	available bool
+}
+
+func (f *fakeKeyring) GetKey() (string, error) {
+	if !f.available || f.key == "" {
+		return "", errors.New("no key")
+	}
+	return f.key, nil
+}
+
+func (f *fakeKeyring) SetKey(password string) error {
+	if !f.available {
+		return errors.New("unavailable")
+	}
+	f.key = password
+	return nil
+}
+
+func (f *fakeKeyring) DeleteKey() error {
+	if !f.available || f.key == "" {
+		return errors.New("no key")
+	}
+	f.key = ""
+	return nil
+}
+
+func (f *fakeKeyring) IsAvailable() bool { return f.available }
+
+func TestChainKeyring_PrefersSystemKeyring(t *testing.T) {
+	system := &fakeKeyring{key: "system", available: true}
+	env := &fakeKeyring{key: "env", available: true}
+
+	key, err := chainKeyring{system, env}.GetKey()
+	if err != nil || key != "system" {
+		t.Fatalf("expected system key, got %q, %v", key, err)
+	}
+}
+
+func TestChainKeyring_FallsBackToEnv(t *testing.T) {
+	system := &fakeKeyring{available: false}
+	env := &fakeKeyring{key: "env", available: true}
+	chain := chainKeyring{system, env}
+
+	key, err := chain.GetKey()
+	if err != nil || key != "env" {
+		t.Fatalf("expected env key, got %q, %v", key, err)
+	}
+	if !chain.IsAvailable() {
+		t.Error("chain with a usable keyring should be available")
+	}
+}
+
+func TestChainKeyring_SetKeyStoresInFirstAvailable(t *testing.T) {
+	system := &fakeKeyring{available: true}
+	env := &fakeKeyring{available: true}
+
+	if err := (chainKeyring{system, env}).SetKey("secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if system.key != "secret" || env.key != "" {
+		t.Fatalf("expected key only in system keyring, got system=%q env=%q", system.key, env.key)
+	}
+}
+
+func TestChainKeyring_SetKeyReturnsLastError(t *testing.T) {
+	last := errors.New("set TIMESINK_DB_KEY instead")
+	chain := chainKeyring{&fakeKeyring{available: false}, failingSet{last}}
+
+	if err := chain.SetKey("secret"); !errors.Is(err, last) {
+		t.Fatalf("expected the fallback's instructions, got %v", err)
+	}
+}
+
+// failingSet is a keyring whose SetKey always fails with err
+type failingSet struct{ err error }
+
+func (f failingSet) GetKey() (string, error) { return "", f.err }
+func (f failingSet) SetKey(string) error     { return f.err }
+func (f failingSet) DeleteKey() error        { return f.err }
+func (f failingSet) IsAvailable() bool       { return false }
+
+func TestKeyEnvFor(t *testing.T) {
+	if got := KeyEnvFor("default"); got != "TIMESINK_DB_KEY" {
+		t.Errorf("default profile: got %s", got)
+	}
+	if got := KeyEnvFor("my-llc"); got != "TIMESINK_DB_KEY_MY_LLC" {
+		t.Errorf("named profile: got %s", got)
+	}
+}
@@ -1,16 +1,42 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/andy/timesink/internal/config"
 	_ "github.com/mutecomm/go-sqlcipher/v4"
 )
 
+// Connection settings. Every pooled connection applies the pragmas as it
+// opens, and opening one means deriving the SQLCipher key again, so the pool
+// is kept small and its connections are never closed for being idle.
+const (
+	// busyTimeout is how long a connection waits on another's write lock
+	// before failing with "database is locked"
+	busyTimeout = 5 * time.Second
+	// synchronous NORMAL is safe from corruption in WAL mode; a power cut
+	// can lose only the last commits
+	synchronous = "NORMAL"
+	maxConns    = 4
+)
+
 type DB struct {
 	*sql.DB
+	path string
+
+	// Prepared statements by query text, for Stmt
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+
+	// Connection kept aside for DataVersion, opened on first use
+	versionMu   sync.Mutex
+	versionConn *sql.Conn
 }
 
 // Open opens an encrypted SQLite database with the given password.
@@ -22,26 +48,22 @@ func Open(dbPath, password string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Build connection string with encryption key
-	connStr := fmt.Sprintf("%s?_key=%s", dbPath, password)
+	// Build connection string with encryption key. Pragmas go in the
+	// connection string rather than through Exec so that every connection
+	// in the pool gets them, not just the first. Transactions begin
+	// IMMEDIATE, taking the write lock up front: one that read first and
+	// wrote later would fail with "database is locked", without waiting,
+	// once another process had written in between.
+	connStr := fmt.Sprintf("%s?_key=%s&_foreign_keys=1&_journal_mode=WAL&_busy_timeout=%d&_synchronous=%s&_txlock=immediate",
+		dbPath, password, busyTimeout.Milliseconds(), synchronous)
 
 	// Open the database
 	sqlDB, err := sql.Open("sqlite3", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-
-	// Enable foreign keys
-	if _, err := sqlDB.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		sqlDB.Close()
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
-	}
-
-	// Enable WAL mode for better concurrent performance
-	if _, err := sqlDB.Exec("PRAGMA journal_mode = WAL"); err != nil {
-		sqlDB.Close()
-		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
-	}
+	sqlDB.SetMaxOpenConns(maxConns)
+	sqlDB.SetMaxIdleConns(maxConns)
 
 	// Ping to verify connection
 	if err := sqlDB.Ping(); err != nil {
@@ -49,22 +71,76 @@ func Open(dbPath, password string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &DB{DB: sqlDB}, nil
+	return &DB{DB: sqlDB, path: dbPath, stmts: make(map[string]*sql.Stmt)}, nil
 }
 
 // OpenWithDefaults opens the database at the default location
-// ~/.config/timesink/timesink.db
+// $XDG_DATA_HOME/timesink/timesink.db
 func OpenWithDefaults(password string) (*DB, error) {
-	homeDir, err := os.UserHomeDir()
+	return Open(config.DefaultConfig().Database.Path, password)
+}
+
+// Path returns the database file path
+func (db *DB) Path() string {
+	return db.path
+}
+
+// Stmt returns a prepared statement for query, preparing it the first time
+// the query is seen. Statements live until Close, so only queries with a
+// fixed text belong here, not ones built with a variable number of
+// placeholders.
+func (db *DB) Stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if stmt, ok := db.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, err
+	}
+	db.stmts[query] = stmt
+	return stmt, nil
+}
+
+// DataVersion returns a number that changes whenever a write is committed,
+// by this process or any other, so callers can tell whether data they read
+// earlier may be stale. It reads PRAGMA data_version on a connection of its
+// own, which reports commits made on every other connection.
+func (db *DB) DataVersion(ctx context.Context) (int64, error) {
+	db.versionMu.Lock()
+	defer db.versionMu.Unlock()
+
+	if db.versionConn == nil {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to open version connection: %w", err)
+		}
+		db.versionConn = conn
 	}
 
-	dbPath := filepath.Join(homeDir, ".config", "timesink", "timesink.db")
-	return Open(dbPath, password)
+	var version int64
+	if err := db.versionConn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read data version: %w", err)
+	}
+	return version, nil
 }
 
-// Close closes the database connection
+// Close closes the prepared statements and the database connection
 func (db *DB) Close() error {
+	db.versionMu.Lock()
+	if db.versionConn != nil {
+		db.versionConn.Close()
+		db.versionConn = nil
+	}
+	db.versionMu.Unlock()
+
+	db.mu.Lock()
+	for query, stmt := range db.stmts {
+		stmt.Close()
+		delete(db.stmts, query)
+	}
+	db.mu.Unlock()
 	return db.DB.Close()
 }
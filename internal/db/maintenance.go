@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// TableStats is the row count of a single table
+type TableStats struct {
+	Name string
+	Rows int64
+}
+
+// Stats summarizes the contents and on-disk size of the database
+type Stats struct {
+	Path           string
+	SizeBytes      int64 // main database file
+	WALBytes       int64 // write-ahead log not yet checkpointed
+	SchemaVersion  int
+	Tables         []TableStats
+	DeletedEntries int64      // soft-deleted entries still on disk
+	OldestEntry    *time.Time // nil when there are no live entries
+	NewestEntry    *time.Time
+}
+
+// Stats collects row counts, file sizes, and the entry date range
+func (db *DB) Stats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{Path: db.path}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	stats.SchemaVersion = version
+
+	tables, err := db.tableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		var rows int64
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&rows); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		stats.Tables = append(stats.Tables, TableStats{Name: table, Rows: rows})
+	}
+
+	var oldest, newest sql.NullString
+	err = db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(is_deleted), 0),
+		       MIN(CASE WHEN is_deleted = 0 THEN start_time END),
+		       MAX(CASE WHEN is_deleted = 0 THEN start_time END)
+		FROM time_entries
+	`).Scan(&stats.DeletedEntries, &oldest, &newest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query entry range: %w", err)
+	}
+	if stats.OldestEntry, err = parseNullTime(oldest); err != nil {
+		return nil, err
+	}
+	if stats.NewestEntry, err = parseNullTime(newest); err != nil {
+		return nil, err
+	}
+
+	stats.SizeBytes, stats.WALBytes = db.fileSizes()
+	return stats, nil
+}
+
+// tableNames lists the tables Stats reports, by name: every table in the
+// schema except SQLite's own and the migration record
+func (db *DB) tableNames(ctx context.Context) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND name != 'schema_version'
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// Vacuum checkpoints the write-ahead log and rebuilds the database file to
+// reclaim the space left by deleted rows
+func (db *DB) Vacuum(ctx context.Context) error {
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	// In WAL mode VACUUM writes the rebuilt pages through the log
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	return nil
+}
+
+// fileSizes returns the size of the database file and its WAL. Missing
+// files count as zero.
+func (db *DB) fileSizes() (size, wal int64) {
+	if info, err := os.Stat(db.path); err == nil {
+		size = info.Size()
+	}
+	if info, err := os.Stat(db.path + "-wal"); err == nil {
+		wal = info.Size()
+	}
+	return size, wal
+}
+
+// SizeBytes returns the combined size of the database file and its WAL
+func (db *DB) SizeBytes() int64 {
+	size, wal := db.fileSizes()
+	return size + wal
+}
+
+// parseNullTime parses an RFC3339 column that may be NULL
+func parseNullTime(s sql.NullString) (*time.Time, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse time %q: %w", s.String, err)
+	}
+	t = t.Local()
+	return &t, nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestStats_CountsEveryTable(t *testing.T) {
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"), "test")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.RunMigrations(); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, "INSERT INTO time_off (start_date, end_date, type, created_at) VALUES ('2026-03-02', '2026-03-06', 'vacation', '2026-03-01T09:00:00Z')"); err != nil {
+		t.Fatalf("failed to insert time off: %v", err)
+	}
+
+	stats, err := database.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	rows := make(map[string]int64)
+	for _, table := range stats.Tables {
+		rows[table.Name] = table.Rows
+	}
+	for _, name := range []string{"clients", "time_entries", "invoices", "time_off", "client_role_rates"} {
+		if _, ok := rows[name]; !ok {
+			t.Errorf("expected %s in the stats, got %v", name, stats.Tables)
+		}
+	}
+	if rows["time_off"] != 1 {
+		t.Errorf("expected 1 time off row, got %d", rows["time_off"])
+	}
+	for _, name := range []string{"schema_version", "sqlite_sequence"} {
+		if _, ok := rows[name]; ok {
+			t.Errorf("expected %s left out of the stats", name)
+		}
+	}
+}
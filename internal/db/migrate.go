@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// MigrationStep is one migration applied or reverted on the way to a target
+// schema version
+type MigrationStep struct {
+	Version     int
+	Down        bool   // reverts the migration rather than applying it
+	Description string // first sentence of the migration's leading comment
+}
+
+// MigrateResult reports what Migrate did
+type MigrateResult struct {
+	From, To int
+	Steps    []MigrationStep
+	Backup   string // copy of the database taken first; empty if none was needed
+}
+
+// LatestVersion returns the schema version this build migrates to
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// MigrationPlan lists the steps that take the schema from its current version
+// to target: pending migrations in order when moving up, and the applied ones
+// above target newest first when moving down. Moving down fails if one of
+// those migrations has no down script.
+func (db *DB) MigrationPlan(target int) ([]MigrationStep, error) {
+	current, plan, err := db.plan(target)
+	if err != nil {
+		return nil, err
+	}
+	return planSteps(plan, target < current), nil
+}
+
+// Migrate brings the schema to version target in a single transaction. An
+// existing database is backed up beside itself before anything changes. A
+// dry run applies the steps and rolls them back, which checks they succeed
+// without changing anything or taking a backup.
+//
+// Migrations run with foreign keys off, so rebuilding a table does not
+// cascade into the tables that reference it. Moving down, the foreign keys
+// are checked before committing instead; moving up they are not, since a
+// dangling reference already in the data is for doctor to report rather
+// than a reason to refuse the upgrade.
+func (db *DB) Migrate(ctx context.Context, target int, dryRun bool) (*MigrateResult, error) {
+	current, plan, err := db.plan(target)
+	if err != nil {
+		return nil, err
+	}
+	down := target < current
+	result := &MigrateResult{From: current, To: target, Steps: planSteps(plan, down)}
+	if len(plan) == 0 {
+		return result, nil
+	}
+
+	if !dryRun && current > 0 {
+		if result.Backup, err = db.backup(ctx, current); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Only takes effect outside a transaction
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return nil, fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, m := range plan {
+		if down {
+			if _, err := tx.ExecContext(ctx, m.down); err != nil {
+				return nil, fmt.Errorf("failed to revert migration %d: %w", m.version, err)
+			}
+			if _, err := tx.ExecContext(ctx, "DELETE FROM schema_version WHERE version = ?", m.version); err != nil {
+				return nil, fmt.Errorf("failed to record reverting migration %d: %w", m.version, err)
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+			return nil, fmt.Errorf("failed to apply migration %d: %w", m.version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES (?)", m.version); err != nil {
+			return nil, fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+	}
+
+	if down {
+		if err := checkForeignKeys(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit migrations: %w", err)
+	}
+	return result, nil
+}
+
+// plan returns the current schema version and the migrations that move it
+// to target
+func (db *DB) plan(target int) (int, []migration, error) {
+	if err := db.ensureVersionTable(); err != nil {
+		return 0, nil, err
+	}
+	current, err := db.SchemaVersion()
+	if err != nil {
+		return 0, nil, err
+	}
+	plan, err := planMigrations(current, target)
+	if err != nil {
+		return 0, nil, err
+	}
+	return current, plan, nil
+}
+
+// planSteps describes plan for callers outside the package
+func planSteps(plan []migration, down bool) []MigrationStep {
+	steps := make([]MigrationStep, len(plan))
+	for i, m := range plan {
+		steps[i] = MigrationStep{Version: m.version, Down: down, Description: m.description()}
+	}
+	return steps
+}
+
+// planMigrations returns the migrations to apply, or to revert newest first,
+// to move from version current to target
+func planMigrations(current, target int) ([]migration, error) {
+	if target < 0 || target > LatestVersion() {
+		return nil, fmt.Errorf("no schema version %d; this build knows versions 0 to %d", target, LatestVersion())
+	}
+
+	var plan []migration
+	if target >= current {
+		for _, m := range migrations {
+			if m.version > current && m.version <= target {
+				plan = append(plan, m)
+			}
+		}
+		return plan, nil
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.version <= target || m.version > current {
+			continue
+		}
+		if m.down == "" {
+			return nil, fmt.Errorf("migration %d cannot be reverted; restore the backup taken before it instead", m.version)
+		}
+		plan = append(plan, m)
+	}
+	return plan, nil
+}
+
+// description returns the first sentence of the comment the migration's SQL
+// opens with
+func (m migration) description() string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(m.sql), "\n") {
+		comment, ok := strings.CutPrefix(strings.TrimSpace(line), "--")
+		if !ok {
+			break
+		}
+		lines = append(lines, strings.TrimSpace(comment))
+	}
+	text := strings.Join(lines, " ")
+	if i := strings.Index(text, ". "); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSuffix(text, ".")
+}
+
+// checkForeignKeys fails if any row references one that does not exist
+func checkForeignKeys(ctx context.Context, tx *sql.Tx) error {
+	var table string
+	var rowid sql.NullInt64
+	var parent string
+	var fkid int
+	err := tx.QueryRowContext(ctx, "PRAGMA foreign_key_check").Scan(&table, &rowid, &parent, &fkid)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	return fmt.Errorf("migration left a row in %s referencing a missing %s", table, parent)
+}
+
+// backup copies the database file to one beside it named for the schema
+// version it holds, and returns its path. It holds the write lock while
+// copying so no new changes land in the write-ahead log; a log that is not
+// empty is copied alongside, where SQLite finds it when the copy is opened.
+// The copy keeps the original's encryption.
+func (db *DB) backup(ctx context.Context, version int) (string, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Folds the log into the main file when nothing else is reading it
+	if _, err := conn.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return "", fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return "", fmt.Errorf("failed to lock database for backup: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "ROLLBACK")
+
+	path := fmt.Sprintf("%s.v%d-%s.bak", db.path, version, time.Now().Format("20060102-150405"))
+	if err := copyFile(db.path, path); err != nil {
+		return "", fmt.Errorf("failed to back up database: %w", err)
+	}
+	if info, err := os.Stat(db.path + "-wal"); err == nil && info.Size() > 0 {
+		if err := copyFile(db.path+"-wal", path+"-wal"); err != nil {
+			os.Remove(path)
+			return "", fmt.Errorf("failed to back up write-ahead log: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// copyFile copies src to a new file at dst readable only by the user
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
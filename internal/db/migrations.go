@@ -1,12 +1,16 @@
 package db
 
 import (
+	"context"
 	"fmt"
 )
 
+// migration is one schema change. down reverts it and is optional; a
+// migration without one can only be undone by restoring a backup.
 type migration struct {
 	version int
 	sql     string
+	down    string
 }
 
 var migrations = []migration{
@@ -103,56 +107,814 @@ CREATE INDEX idx_entries_client ON time_entries(client_id);
 CREATE INDEX idx_entries_start ON time_entries(start_time);
 CREATE INDEX idx_entries_unbilled ON time_entries(client_id, invoice_id) WHERE invoice_id IS NULL;
 CREATE INDEX idx_invoices_status ON invoices(status);
+`,
+	},
+	{
+		version: 2,
+		sql: `
+-- Timestamped notes captured while a timer runs, carried onto the entry
+ALTER TABLE active_timer ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE time_entries ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 3,
+		sql: `
+-- Timers can be started as non-billable
+ALTER TABLE active_timer ADD COLUMN is_billable INTEGER NOT NULL DEFAULT 1;
+`,
+	},
+	{
+		version: 4,
+		sql: `
+-- Store all timestamps in UTC. strftime normalizes any stored offset (or the
+-- bare UTC of datetime('now') defaults) to RFC3339 with a Z suffix; values it
+-- cannot parse are left untouched.
+UPDATE clients SET
+    created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', created_at), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', updated_at), updated_at);
+UPDATE time_entries SET
+    start_time = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', start_time), start_time),
+    end_time = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', end_time), end_time),
+    created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', created_at), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', updated_at), updated_at);
+UPDATE entry_history SET
+    changed_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', changed_at), changed_at);
+UPDATE invoices SET
+    period_start = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', period_start), period_start),
+    period_end = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', period_end), period_end),
+    due_date = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', due_date), due_date),
+    paid_date = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', paid_date), paid_date),
+    created_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', created_at), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', updated_at), updated_at);
+UPDATE invoice_line_items SET
+    date = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', date), date);
+UPDATE active_timer SET
+    start_time = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', start_time), start_time),
+    paused_at = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', paused_at), paused_at);
+UPDATE entry_history SET
+    old_value = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', old_value), old_value),
+    new_value = COALESCE(strftime('%Y-%m-%dT%H:%M:%SZ', new_value), new_value)
+WHERE field_name IN ('start_time', 'end_time');
+`,
+	},
+	{
+		version: 5,
+		sql: `
+-- Per-client option to write a detailed timesheet alongside each invoice
+ALTER TABLE clients ADD COLUMN attach_timesheet INTEGER NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		version: 6,
+		sql: `
+-- Client hourly rates over time. clients.hourly_rate keeps the current rate;
+-- existing clients start with their current rate from when they were created.
+CREATE TABLE client_rate_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    hourly_rate REAL NOT NULL,
+    effective_from TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    UNIQUE (client_id, effective_from)
+);
+CREATE INDEX idx_client_rates ON client_rate_history(client_id, effective_from);
+INSERT INTO client_rate_history (client_id, hourly_rate, effective_from, created_at)
+SELECT id, hourly_rate, created_at, created_at FROM clients;
+`,
+	},
+	{
+		version: 7,
+		sql: `
+-- Per-client billing rules applied when invoice totals are calculated
+ALTER TABLE clients ADD COLUMN min_increment_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN daily_cap_hours REAL NOT NULL DEFAULT 0;
+ALTER TABLE clients ADD COLUMN overtime_multiplier REAL NOT NULL DEFAULT 0;
+
+-- Adjustment line items derived from those rules have no entry, so
+-- entry_id becomes nullable. SQLite cannot relax a column constraint in
+-- place, so the table is rebuilt.
+CREATE TABLE invoice_line_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id),
+    entry_id INTEGER REFERENCES time_entries(id),
+    date TEXT NOT NULL,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL,
+    rate REAL NOT NULL,
+    amount REAL NOT NULL
+);
+INSERT INTO invoice_line_items_new (id, invoice_id, entry_id, date, description, hours, rate, amount)
+SELECT id, invoice_id, entry_id, date, description, hours, rate, amount FROM invoice_line_items;
+DROP TABLE invoice_line_items;
+ALTER TABLE invoice_line_items_new RENAME TO invoice_line_items;
+`,
+	},
+	{
+		version: 8,
+		sql: `
+-- Tax lines per invoice (e.g. VAT plus a local surcharge). invoices.tax_rate
+-- and tax_amount keep their sums.
+CREATE TABLE invoice_taxes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    rate REAL NOT NULL,
+    amount REAL NOT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX idx_invoice_taxes_invoice ON invoice_taxes(invoice_id);
+
+-- Existing invoices keep their single tax as one line
+INSERT INTO invoice_taxes (invoice_id, position, label, rate, amount)
+SELECT id, 0, 'Tax', tax_rate, tax_amount FROM invoices WHERE tax_rate > 0;
+
+-- Reverse-charge clients are invoiced at 0% with a note
+ALTER TABLE clients ADD COLUMN reverse_charge INTEGER NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		version: 9,
+		sql: `
+-- Free text printed at the bottom of each invoice
+ALTER TABLE invoices ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE invoices ADD COLUMN payment_instructions TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 10,
+		sql: `
+-- Files attached to records, stored in the attachments directory. The
+-- owner is polymorphic, so there is no foreign key.
+CREATE TABLE attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner_type TEXT NOT NULL,
+    owner_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    path TEXT NOT NULL UNIQUE,
+    size INTEGER NOT NULL,
+    sha256 TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX idx_attachments_owner ON attachments(owner_type, owner_id);
+`,
+	},
+	{
+		version: 11,
+		sql: `
+-- Estimates (quotes) with their own numbering and line items, convertible
+-- into a draft invoice once accepted
+CREATE TABLE estimates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    issue_date TEXT NOT NULL,
+    valid_until TEXT,
+    status TEXT NOT NULL DEFAULT 'draft',
+    total REAL NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+CREATE INDEX idx_estimates_client ON estimates(client_id);
+
+CREATE TABLE estimate_line_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id INTEGER NOT NULL REFERENCES estimates(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL DEFAULT 0,
+    rate REAL NOT NULL DEFAULT 0,
+    amount REAL NOT NULL
+);
+CREATE INDEX idx_estimate_line_items_estimate ON estimate_line_items(estimate_id);
+
+-- Invoice line items copied from an estimate, kept apart from billing
+-- rule adjustments
+ALTER TABLE invoice_line_items ADD COLUMN estimate_id INTEGER REFERENCES estimates(id) ON DELETE SET NULL;
+`,
+	},
+	{
+		version: 12,
+		sql: `
+-- Engagements with clients, for end-date warnings. Dates are whole days and
+-- end_date is the last day of the contract.
+CREATE TABLE contracts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    rate REAL NOT NULL DEFAULT 0,
+    scope TEXT NOT NULL DEFAULT '',
+    renews INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+CREATE INDEX idx_contracts_client ON contracts(client_id, end_date);
+`,
+	},
+	{
+		version: 13,
+		sql: `
+-- Client sign-off on entries, and clients whose invoices only take
+-- approved entries
+ALTER TABLE time_entries ADD COLUMN approval TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE time_entries ADD COLUMN approval_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE clients ADD COLUMN require_approval INTEGER NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		version: 14,
+		sql: `
+-- Each time an invoice is sent, and when a read receipt or provider
+-- webhook reported it opened
+CREATE TABLE invoice_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    recipient TEXT NOT NULL DEFAULT '',
+    message_id TEXT NOT NULL DEFAULT '',
+    sent_at TEXT NOT NULL,
+    opened_at TEXT,
+    created_at TEXT NOT NULL
+);
+CREATE INDEX idx_invoice_deliveries_invoice ON invoice_deliveries(invoice_id, sent_at);
+CREATE INDEX idx_invoice_deliveries_message ON invoice_deliveries(message_id);
+`,
+	},
+	{
+		version: 15,
+		sql: `
+-- Late-payment interest charged on a follow-up invoice for an earlier one
+ALTER TABLE invoice_line_items ADD COLUMN interest_invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL;
+`,
+	},
+	{
+		version: 16,
+		sql: `
+-- Full-text indexes over entry descriptions and notes and client names and
+-- notes, keyed by the row's id and kept in step by triggers. FTS4 rather
+-- than FTS5, which SQLCipher leaves out of the default build.
+CREATE VIRTUAL TABLE entries_fts USING fts4(description, notes, tokenize=unicode61);
+CREATE VIRTUAL TABLE clients_fts USING fts4(name, notes, tokenize=unicode61);
+
+INSERT INTO entries_fts (docid, description, notes)
+    SELECT id, COALESCE(description, ''), notes FROM time_entries;
+INSERT INTO clients_fts (docid, name, notes)
+    SELECT id, name, COALESCE(notes, '') FROM clients;
+
+CREATE TRIGGER time_entries_fts_insert AFTER INSERT ON time_entries BEGIN
+    INSERT INTO entries_fts (docid, description, notes)
+        VALUES (new.id, COALESCE(new.description, ''), new.notes);
+END;
+CREATE TRIGGER time_entries_fts_update AFTER UPDATE OF description, notes ON time_entries BEGIN
+    DELETE FROM entries_fts WHERE docid = old.id;
+    INSERT INTO entries_fts (docid, description, notes)
+        VALUES (new.id, COALESCE(new.description, ''), new.notes);
+END;
+CREATE TRIGGER time_entries_fts_delete AFTER DELETE ON time_entries BEGIN
+    DELETE FROM entries_fts WHERE docid = old.id;
+END;
+
+CREATE TRIGGER clients_fts_insert AFTER INSERT ON clients BEGIN
+    INSERT INTO clients_fts (docid, name, notes)
+        VALUES (new.id, new.name, COALESCE(new.notes, ''));
+END;
+CREATE TRIGGER clients_fts_update AFTER UPDATE OF name, notes ON clients BEGIN
+    DELETE FROM clients_fts WHERE docid = old.id;
+    INSERT INTO clients_fts (docid, name, notes)
+        VALUES (new.id, new.name, COALESCE(new.notes, ''));
+END;
+CREATE TRIGGER clients_fts_delete AFTER DELETE ON clients BEGIN
+    DELETE FROM clients_fts WHERE docid = old.id;
+END;
+`,
+		down: `
+DROP TRIGGER time_entries_fts_insert;
+DROP TRIGGER time_entries_fts_update;
+DROP TRIGGER time_entries_fts_delete;
+DROP TRIGGER clients_fts_insert;
+DROP TRIGGER clients_fts_update;
+DROP TRIGGER clients_fts_delete;
+DROP TABLE entries_fts;
+DROP TABLE clients_fts;
+`,
+	},
+	{
+		version: 17,
+		sql: `
+-- Row versions for optimistic concurrency. Update methods only write a row
+-- whose version still matches the one they read and bump it; the triggers
+-- bump it for every other write, such as archiving a client, locking an
+-- entry to an invoice, or a status change made by another process.
+ALTER TABLE clients ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE time_entries ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE invoices ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TRIGGER clients_version AFTER UPDATE ON clients
+WHEN new.version = old.version BEGIN
+    UPDATE clients SET version = old.version + 1 WHERE id = old.id;
+END;
+CREATE TRIGGER time_entries_version AFTER UPDATE ON time_entries
+WHEN new.version = old.version BEGIN
+    UPDATE time_entries SET version = old.version + 1 WHERE id = old.id;
+END;
+CREATE TRIGGER invoices_version AFTER UPDATE ON invoices
+WHEN new.version = old.version BEGIN
+    UPDATE invoices SET version = old.version + 1 WHERE id = old.id;
+END;
+`,
+		down: `
+-- SQLite before 3.35 cannot drop a column, so the three tables are rebuilt
+-- without it, keeping their ids and AUTOINCREMENT counters. Their indexes
+-- and the search triggers go with the old tables and are recreated.
+DROP TRIGGER clients_version;
+DROP TRIGGER time_entries_version;
+DROP TRIGGER invoices_version;
+
+CREATE TABLE clients_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    email TEXT,
+    hourly_rate REAL NOT NULL DEFAULT 0,
+    notes TEXT,
+    is_archived INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    attach_timesheet INTEGER NOT NULL DEFAULT 0,
+    min_increment_minutes INTEGER NOT NULL DEFAULT 0,
+    daily_cap_hours REAL NOT NULL DEFAULT 0,
+    overtime_multiplier REAL NOT NULL DEFAULT 0,
+    reverse_charge INTEGER NOT NULL DEFAULT 0,
+    require_approval INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO clients_new
+SELECT id, name, email, hourly_rate, notes, is_archived, created_at, updated_at,
+       attach_timesheet, min_increment_minutes, daily_cap_hours, overtime_multiplier,
+       reverse_charge, require_approval
+FROM clients;
+
+CREATE TABLE time_entries_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    description TEXT,
+    start_time TEXT NOT NULL,
+    end_time TEXT,
+    duration_seconds INTEGER,
+    hourly_rate REAL NOT NULL,
+    is_billable INTEGER NOT NULL DEFAULT 1,
+    is_deleted INTEGER NOT NULL DEFAULT 0,
+    invoice_id INTEGER REFERENCES invoices(id),
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    notes TEXT NOT NULL DEFAULT '',
+    approval TEXT NOT NULL DEFAULT 'pending',
+    approval_note TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO time_entries_new
+SELECT id, client_id, description, start_time, end_time, duration_seconds, hourly_rate,
+       is_billable, is_deleted, invoice_id, created_at, updated_at, notes, approval, approval_note
+FROM time_entries;
+
+CREATE TABLE invoices_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    period_start TEXT NOT NULL,
+    period_end TEXT NOT NULL,
+    subtotal REAL NOT NULL DEFAULT 0,
+    tax_rate REAL NOT NULL DEFAULT 0,
+    tax_amount REAL NOT NULL DEFAULT 0,
+    total REAL NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'draft',
+    due_date TEXT,
+    paid_date TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    notes TEXT NOT NULL DEFAULT '',
+    payment_instructions TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO invoices_new
+SELECT id, invoice_number, client_id, period_start, period_end, subtotal, tax_rate,
+       tax_amount, total, status, due_date, paid_date, created_at, updated_at,
+       notes, payment_instructions
+FROM invoices;
+
+DELETE FROM sqlite_sequence WHERE name IN ('clients_new', 'time_entries_new', 'invoices_new');
+INSERT INTO sqlite_sequence (name, seq)
+SELECT name || '_new', seq FROM sqlite_sequence WHERE name IN ('clients', 'time_entries', 'invoices');
+
+DROP TABLE clients;
+DROP TABLE time_entries;
+DROP TABLE invoices;
+ALTER TABLE clients_new RENAME TO clients;
+ALTER TABLE time_entries_new RENAME TO time_entries;
+ALTER TABLE invoices_new RENAME TO invoices;
+
+CREATE INDEX idx_entries_client ON time_entries(client_id);
+CREATE INDEX idx_entries_start ON time_entries(start_time);
+CREATE INDEX idx_entries_unbilled ON time_entries(client_id, invoice_id) WHERE invoice_id IS NULL;
+CREATE INDEX idx_invoices_status ON invoices(status);
+
+CREATE TRIGGER time_entries_fts_insert AFTER INSERT ON time_entries BEGIN
+    INSERT INTO entries_fts (docid, description, notes)
+        VALUES (new.id, COALESCE(new.description, ''), new.notes);
+END;
+CREATE TRIGGER time_entries_fts_update AFTER UPDATE OF description, notes ON time_entries BEGIN
+    DELETE FROM entries_fts WHERE docid = old.id;
+    INSERT INTO entries_fts (docid, description, notes)
+        VALUES (new.id, COALESCE(new.description, ''), new.notes);
+END;
+CREATE TRIGGER time_entries_fts_delete AFTER DELETE ON time_entries BEGIN
+    DELETE FROM entries_fts WHERE docid = old.id;
+END;
+
+CREATE TRIGGER clients_fts_insert AFTER INSERT ON clients BEGIN
+    INSERT INTO clients_fts (docid, name, notes)
+        VALUES (new.id, new.name, COALESCE(new.notes, ''));
+END;
+CREATE TRIGGER clients_fts_update AFTER UPDATE OF name, notes ON clients BEGIN
+    DELETE FROM clients_fts WHERE docid = old.id;
+    INSERT INTO clients_fts (docid, name, notes)
+        VALUES (new.id, new.name, COALESCE(new.notes, ''));
+END;
+CREATE TRIGGER clients_fts_delete AFTER DELETE ON clients BEGIN
+    DELETE FROM clients_fts WHERE docid = old.id;
+END;
+`,
+	},
+	{
+		version: 18,
+		sql: `
+-- Due dates moved after an invoice was sent, with the note explaining why
+CREATE TABLE invoice_due_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    old_due_date TEXT,
+    new_due_date TEXT NOT NULL,
+    note TEXT NOT NULL,
+    changed_at TEXT NOT NULL
+);
+CREATE INDEX idx_invoice_due_changes_invoice ON invoice_due_changes(invoice_id, changed_at);
+`,
+		down: `
+DROP TABLE invoice_due_changes;
+`,
+	},
+	{
+		version: 19,
+		sql: `
+-- Days off, left out of utilization and short-day alerts. Dates are whole
+-- days and end_date is the last day off.
+CREATE TABLE time_off (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('vacation', 'sick', 'holiday')),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TEXT NOT NULL
+);
+CREATE INDEX idx_time_off_dates ON time_off(start_date, end_date);
+`,
+		down: `
+DROP TABLE time_off;
+`,
+	},
+	{
+		version: 20,
+		sql: `
+-- Language of the labels on a client's invoices; empty for English
+ALTER TABLE clients ADD COLUMN language TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 21,
+		sql: `
+-- Amounts on invoices and estimates in whole cents, so totals add up
+-- exactly. Rates stay REAL. SQLite cannot change a column's type in place,
+-- so the tables are rebuilt, keeping their ids and AUTOINCREMENT counters;
+-- their indexes and the version trigger are recreated.
+CREATE TABLE invoices_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    period_start TEXT NOT NULL,
+    period_end TEXT NOT NULL,
+    subtotal INTEGER NOT NULL DEFAULT 0,
+    tax_rate REAL NOT NULL DEFAULT 0,
+    tax_amount INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'draft',
+    due_date TEXT,
+    paid_date TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    notes TEXT NOT NULL DEFAULT '',
+    payment_instructions TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO invoices_new
+SELECT id, invoice_number, client_id, period_start, period_end, CAST(ROUND(subtotal * 100) AS INTEGER), tax_rate,
+       CAST(ROUND(tax_amount * 100) AS INTEGER), CAST(ROUND(total * 100) AS INTEGER), status, due_date, paid_date, created_at, updated_at,
+       notes, payment_instructions, version
+FROM invoices;
+
+CREATE TABLE invoice_line_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id),
+    entry_id INTEGER REFERENCES time_entries(id),
+    date TEXT NOT NULL,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL,
+    rate REAL NOT NULL,
+    amount INTEGER NOT NULL,
+    estimate_id INTEGER REFERENCES estimates(id) ON DELETE SET NULL,
+    interest_invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL
+);
+INSERT INTO invoice_line_items_new
+SELECT id, invoice_id, entry_id, date, description, hours, rate, CAST(ROUND(amount * 100) AS INTEGER),
+       estimate_id, interest_invoice_id
+FROM invoice_line_items;
+
+CREATE TABLE invoice_taxes_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    rate REAL NOT NULL,
+    amount INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO invoice_taxes_new
+SELECT id, invoice_id, position, label, rate, CAST(ROUND(amount * 100) AS INTEGER), note FROM invoice_taxes;
+
+CREATE TABLE estimates_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    issue_date TEXT NOT NULL,
+    valid_until TEXT,
+    status TEXT NOT NULL DEFAULT 'draft',
+    total INTEGER NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+INSERT INTO estimates_new
+SELECT id, estimate_number, client_id, issue_date, valid_until, status, CAST(ROUND(total * 100) AS INTEGER),
+       notes, invoice_id, created_at, updated_at
+FROM estimates;
+
+CREATE TABLE estimate_line_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id INTEGER NOT NULL REFERENCES estimates(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL DEFAULT 0,
+    rate REAL NOT NULL DEFAULT 0,
+    amount INTEGER NOT NULL
+);
+INSERT INTO estimate_line_items_new
+SELECT id, estimate_id, description, hours, rate, CAST(ROUND(amount * 100) AS INTEGER) FROM estimate_line_items;
+
+DELETE FROM sqlite_sequence WHERE name IN
+    ('invoices_new', 'invoice_line_items_new', 'invoice_taxes_new', 'estimates_new', 'estimate_line_items_new');
+INSERT INTO sqlite_sequence (name, seq)
+SELECT name || '_new', seq FROM sqlite_sequence WHERE name IN
+    ('invoices', 'invoice_line_items', 'invoice_taxes', 'estimates', 'estimate_line_items');
+
+DROP TABLE invoices;
+DROP TABLE invoice_line_items;
+DROP TABLE invoice_taxes;
+DROP TABLE estimates;
+DROP TABLE estimate_line_items;
+ALTER TABLE invoices_new RENAME TO invoices;
+ALTER TABLE invoice_line_items_new RENAME TO invoice_line_items;
+ALTER TABLE invoice_taxes_new RENAME TO invoice_taxes;
+ALTER TABLE estimates_new RENAME TO estimates;
+ALTER TABLE estimate_line_items_new RENAME TO estimate_line_items;
+
+CREATE INDEX idx_invoices_status ON invoices(status);
+CREATE INDEX idx_invoice_taxes_invoice ON invoice_taxes(invoice_id);
+CREATE INDEX idx_estimates_client ON estimates(client_id);
+CREATE INDEX idx_estimate_line_items_estimate ON estimate_line_items(estimate_id);
+
+CREATE TRIGGER invoices_version AFTER UPDATE ON invoices
+WHEN new.version = old.version BEGIN
+    UPDATE invoices SET version = old.version + 1 WHERE id = old.id;
+END;
+`,
+		down: `
+CREATE TABLE invoices_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    period_start TEXT NOT NULL,
+    period_end TEXT NOT NULL,
+    subtotal REAL NOT NULL DEFAULT 0,
+    tax_rate REAL NOT NULL DEFAULT 0,
+    tax_amount REAL NOT NULL DEFAULT 0,
+    total REAL NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'draft',
+    due_date TEXT,
+    paid_date TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    notes TEXT NOT NULL DEFAULT '',
+    payment_instructions TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1
+);
+INSERT INTO invoices_new
+SELECT id, invoice_number, client_id, period_start, period_end, subtotal / 100.0, tax_rate,
+       tax_amount / 100.0, total / 100.0, status, due_date, paid_date, created_at, updated_at,
+       notes, payment_instructions, version
+FROM invoices;
+
+CREATE TABLE invoice_line_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id),
+    entry_id INTEGER REFERENCES time_entries(id),
+    date TEXT NOT NULL,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL,
+    rate REAL NOT NULL,
+    amount REAL NOT NULL,
+    estimate_id INTEGER REFERENCES estimates(id) ON DELETE SET NULL,
+    interest_invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL
+);
+INSERT INTO invoice_line_items_new
+SELECT id, invoice_id, entry_id, date, description, hours, rate, amount / 100.0,
+       estimate_id, interest_invoice_id
+FROM invoice_line_items;
+
+CREATE TABLE invoice_taxes_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    invoice_id INTEGER NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    rate REAL NOT NULL,
+    amount REAL NOT NULL,
+    note TEXT NOT NULL DEFAULT ''
+);
+INSERT INTO invoice_taxes_new
+SELECT id, invoice_id, position, label, rate, amount / 100.0, note FROM invoice_taxes;
+
+CREATE TABLE estimates_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_number TEXT NOT NULL UNIQUE,
+    client_id INTEGER NOT NULL REFERENCES clients(id),
+    issue_date TEXT NOT NULL,
+    valid_until TEXT,
+    status TEXT NOT NULL DEFAULT 'draft',
+    total REAL NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+    invoice_id INTEGER REFERENCES invoices(id) ON DELETE SET NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL
+);
+INSERT INTO estimates_new
+SELECT id, estimate_number, client_id, issue_date, valid_until, status, total / 100.0,
+       notes, invoice_id, created_at, updated_at
+FROM estimates;
+
+CREATE TABLE estimate_line_items_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    estimate_id INTEGER NOT NULL REFERENCES estimates(id) ON DELETE CASCADE,
+    description TEXT NOT NULL,
+    hours REAL NOT NULL DEFAULT 0,
+    rate REAL NOT NULL DEFAULT 0,
+    amount REAL NOT NULL
+);
+INSERT INTO estimate_line_items_new
+SELECT id, estimate_id, description, hours, rate, amount / 100.0 FROM estimate_line_items;
+
+DELETE FROM sqlite_sequence WHERE name IN
+    ('invoices_new', 'invoice_line_items_new', 'invoice_taxes_new', 'estimates_new', 'estimate_line_items_new');
+INSERT INTO sqlite_sequence (name, seq)
+SELECT name || '_new', seq FROM sqlite_sequence WHERE name IN
+    ('invoices', 'invoice_line_items', 'invoice_taxes', 'estimates', 'estimate_line_items');
+
+DROP TABLE invoices;
+DROP TABLE invoice_line_items;
+DROP TABLE invoice_taxes;
+DROP TABLE estimates;
+DROP TABLE estimate_line_items;
+ALTER TABLE invoices_new RENAME TO invoices;
+ALTER TABLE invoice_line_items_new RENAME TO invoice_line_items;
+ALTER TABLE invoice_taxes_new RENAME TO invoice_taxes;
+ALTER TABLE estimates_new RENAME TO estimates;
+ALTER TABLE estimate_line_items_new RENAME TO estimate_line_items;
+
+CREATE INDEX idx_invoices_status ON invoices(status);
+CREATE INDEX idx_invoice_taxes_invoice ON invoice_taxes(invoice_id);
+CREATE INDEX idx_estimates_client ON estimates(client_id);
+CREATE INDEX idx_estimate_line_items_estimate ON estimate_line_items(estimate_id);
+
+CREATE TRIGGER invoices_version AFTER UPDATE ON invoices
+WHEN new.version = old.version BEGIN
+    UPDATE invoices SET version = old.version + 1 WHERE id = old.id;
+END;
+`,
+	},
+	{
+		version: 22,
+		sql: `
+-- Why an entry is billed at a rate other than the client's rate for its
+-- day, carried onto the entry's invoice line item
+ALTER TABLE time_entries ADD COLUMN rate_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE invoice_line_items ADD COLUMN rate_reason TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 23,
+		sql: `
+-- Hours of work each client is expected to need a week, for capacity
+-- planning
+ALTER TABLE clients ADD COLUMN weekly_demand REAL NOT NULL DEFAULT 0;
+`,
+	},
+	{
+		version: 24,
+		sql: `
+-- Where the work was done, e.g. "on-site" or the machine's hostname;
+-- empty if not recorded
+ALTER TABLE time_entries ADD COLUMN location TEXT NOT NULL DEFAULT '';
+ALTER TABLE active_timer ADD COLUMN location TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 25,
+		sql: `
+-- A line item's description as copied onto the invoice, kept when it is
+-- reworded on a draft; empty if it never was
+ALTER TABLE invoice_line_items ADD COLUMN original_description TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 26,
+		sql: `
+-- Rate cards: named rates per client, such as development and consulting,
+-- billed on entries for that role instead of the client's rate
+CREATE TABLE client_role_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    role TEXT NOT NULL COLLATE NOCASE,
+    hourly_rate REAL NOT NULL,
+    created_at TEXT NOT NULL,
+    UNIQUE (client_id, role)
+);
+ALTER TABLE time_entries ADD COLUMN role TEXT NOT NULL DEFAULT '';
+ALTER TABLE active_timer ADD COLUMN role TEXT NOT NULL DEFAULT '';
+ALTER TABLE invoice_line_items ADD COLUMN role TEXT NOT NULL DEFAULT '';
+`,
+	},
+	{
+		version: 27,
+		sql: `
+-- When a timer was paused, one start/end pair per line, so its entries keep
+-- their real end and leave the pauses out on the day they happened
+ALTER TABLE active_timer ADD COLUMN pauses TEXT NOT NULL DEFAULT '';
+ALTER TABLE time_entries ADD COLUMN pauses TEXT NOT NULL DEFAULT '';
 `,
 	},
 }
 
-// RunMigrations applies all pending database migrations
-func (db *DB) RunMigrations() error {
-	// Ensure schema_version table exists
-	_, err := db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_version (
-			version INTEGER PRIMARY KEY,
-			applied_at TEXT NOT NULL DEFAULT (datetime('now'))
-		)
-	`)
+// SchemaVersion returns the most recently applied migration
+func (db *DB) SchemaVersion() (int, error) {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
 	if err != nil {
-		return fmt.Errorf("failed to create schema_version table: %w", err)
+		return 0, fmt.Errorf("failed to get current schema version: %w", err)
 	}
+	return version, nil
+}
 
-	// Get current schema version
-	var currentVersion int
-	err = db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&currentVersion)
-	if err != nil {
-		return fmt.Errorf("failed to get current schema version: %w", err)
+// RunMigrations applies all pending database migrations, backing up an
+// existing database first. A schema newer than this build is left alone.
+func (db *DB) RunMigrations() (*MigrateResult, error) {
+	if err := db.ensureVersionTable(); err != nil {
+		return nil, err
 	}
-
-	// Apply pending migrations in a transaction
-	tx, err := db.Begin()
+	current, err := db.SchemaVersion()
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
-
-	for _, m := range migrations {
-		if m.version <= currentVersion {
-			continue
-		}
-
-		// Execute migration SQL
-		if _, err := tx.Exec(m.sql); err != nil {
-			return fmt.Errorf("failed to apply migration %d: %w", m.version, err)
-		}
-
-		// Record migration
-		if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES (?)", m.version); err != nil {
-			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
-		}
+	if current >= LatestVersion() {
+		return &MigrateResult{From: current, To: current}, nil
 	}
+	return db.Migrate(context.Background(), LatestVersion(), false)
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit migrations: %w", err)
+// ensureVersionTable creates the table recording applied migrations
+func (db *DB) ensureVersionTable() error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			applied_at TEXT NOT NULL DEFAULT (datetime('now'))
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
 	}
-
 	return nil
 }
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrNotReadOnly is returned by ReadOnlyQuery for statements that could
+// change the database
+var ErrNotReadOnly = errors.New("only SELECT, WITH, VALUES, and EXPLAIN statements can be run")
+
+// ErrMultipleStatements is returned by ReadOnlyQuery for input holding more
+// than one statement. The driver would run each of them, so a later one
+// could undo PRAGMA query_only and write.
+var ErrMultipleStatements = errors.New("only one statement can be run at a time")
+
+// readOnlyKeywords are the statements ReadOnlyQuery accepts
+var readOnlyKeywords = []string{"SELECT", "WITH", "VALUES", "EXPLAIN"}
+
+// QueryResult holds the columns and rows of a query. Values are as the
+// driver returns them: int64, float64, string, []byte, time.Time, or nil.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// ReadOnlyQuery runs a single statement for ad-hoc analysis. Statements
+// other than SELECT, WITH, VALUES, and EXPLAIN are refused, as is anything
+// after the first statement, and the query runs on a connection with PRAGMA
+// query_only set, which SQLite enforces for anything that slips past the
+// first check, such as a WITH ahead of a DELETE. The statement is prepared
+// before it runs, so the driver executes it alone even if a second one
+// escaped the check. The connection is closed afterwards rather than
+// returned to the pool.
+func (db *DB) ReadOnlyQuery(ctx context.Context, query string) (*QueryResult, error) {
+	if !isReadOnly(query) {
+		return nil, ErrNotReadOnly
+	}
+	if hasMoreStatements(query) {
+		return nil, ErrMultipleStatements
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+	defer conn.Raw(func(any) error { return driver.ErrBadConn })
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		return nil, fmt.Errorf("failed to make connection read-only: %w", err)
+	}
+
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &QueryResult{}
+	if result.Columns, err = rows.Columns(); err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	for rows.Next() {
+		row := make([]any, len(result.Columns))
+		ptrs := make([]any, len(row))
+		for i := range row {
+			ptrs[i] = &row[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// isReadOnly reports whether query starts, after any comments, with one of
+// readOnlyKeywords
+func isReadOnly(query string) bool {
+	rest := skipComments(query)
+	word := rest
+	if i := strings.IndexFunc(rest, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}); i >= 0 {
+		word = rest[:i]
+	}
+	for _, keyword := range readOnlyKeywords {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipComments drops leading white space and comments from s
+func skipComments(s string) string {
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(s, "--"):
+			_, s, _ = strings.Cut(s, "\n")
+		case strings.HasPrefix(s, "/*"):
+			_, s, _ = strings.Cut(s, "*/")
+		default:
+			return s
+		}
+	}
+}
+
+// hasMoreStatements reports whether anything but semicolons, white space
+// and comments follows the first statement in query. Semicolons inside
+// string literals, quoted identifiers and comments do not end a statement.
+func hasMoreStatements(query string) bool {
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; c {
+		case '\'', '"', '`':
+			// A doubled quote is an escaped one, which the next pass
+			// through here steps over as an empty literal
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+		case '[':
+			end := strings.IndexByte(query[i+1:], ']')
+			if end < 0 {
+				return false
+			}
+			i += end + 1
+		case '-', '/':
+			if rest := query[i:]; strings.HasPrefix(rest, "--") || strings.HasPrefix(rest, "/*") {
+				skipped := skipComments(rest)
+				if skipped == "" {
+					return false
+				}
+				i = len(query) - len(skipped) - 1
+			}
+		case ';':
+			for rest := query[i+1:]; ; {
+				rest = skipComments(rest)
+				if !strings.HasPrefix(rest, ";") {
+					return rest != ""
+				}
+				rest = rest[1:]
+			}
+		}
+	}
+	return false
+}
+
+// SchemaObject is a table, index, trigger, or view and the statement that
+// creates it
+type SchemaObject struct {
+	Type  string
+	Name  string
+	Table string // the table an index or trigger belongs to
+	SQL   string
+}
+
+// Schema returns the statements that create the database's tables, then
+// their indexes, triggers, and views, limited to those of table when it is
+// not empty. SQLite's own tables and the shadow tables full-text indexes
+// keep their data in are left out.
+func (db *DB) Schema(ctx context.Context, table string) ([]SchemaObject, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT type, name, tbl_name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%'
+		  AND (? = '' OR tbl_name = ?)
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 WHEN 'trigger' THEN 2 ELSE 3 END,
+		         tbl_name, name
+	`, table, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	var objects []SchemaObject
+	var virtual []string
+	for rows.Next() {
+		var o SchemaObject
+		if err := rows.Scan(&o.Type, &o.Name, &o.Table, &o.SQL); err != nil {
+			return nil, fmt.Errorf("failed to scan schema: %w", err)
+		}
+		if o.Type == "table" && strings.HasPrefix(strings.ToUpper(o.SQL), "CREATE VIRTUAL TABLE") {
+			virtual = append(virtual, o.Name)
+		}
+		objects = append(objects, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	kept := objects[:0]
+	for _, o := range objects {
+		if !isShadowTable(o, virtual) {
+			kept = append(kept, o)
+		}
+	}
+	if table != "" && len(kept) == 0 {
+		return nil, fmt.Errorf("no table named %q", table)
+	}
+	return kept, nil
+}
+
+// isShadowTable reports whether o is one of the tables a virtual table in
+// virtual stores its data in, which are named after it
+func isShadowTable(o SchemaObject, virtual []string) bool {
+	if o.Type != "table" {
+		return false
+	}
+	for _, name := range virtual {
+		if strings.HasPrefix(o.Name, name+"_") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestHasMoreStatements(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", false},
+		{"SELECT 1;", false},
+		{"SELECT 1; ;  -- done\n", false},
+		{"SELECT 1; /* trailing */", false},
+		{"SELECT ';' AS semi", false},
+		{"SELECT 'it''s; fine'", false},
+		{`SELECT "a;b" FROM [c;d]`, false},
+		{"SELECT 1 -- not; a statement\n", false},
+		{"SELECT 1 /* ; */ + 1", false},
+		{"SELECT 1; DELETE FROM clients", true},
+		{"SELECT 1;PRAGMA query_only=OFF", true},
+		{"SELECT 1; -- comment\nDELETE FROM clients", true},
+		{"SELECT 'a'; SELECT 'b'", true},
+	}
+	for _, tt := range tests {
+		if got := hasMoreStatements(tt.query); got != tt.want {
+			t.Errorf("hasMoreStatements(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestReadOnlyQuery_RefusesMultipleStatements(t *testing.T) {
+	ctx := context.Background()
+	database, err := Open(filepath.Join(t.TempDir(), "test.db"), "test")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer database.Close()
+	if _, err := database.RunMigrations(); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	if _, err := database.ExecContext(ctx, "INSERT INTO clients (name, hourly_rate) VALUES ('Acme', 100)"); err != nil {
+		t.Fatalf("failed to insert client: %v", err)
+	}
+
+	// Each later statement would run too, turning query_only back off
+	_, err = database.ReadOnlyQuery(ctx, "SELECT 1; PRAGMA query_only=OFF; DELETE FROM clients")
+	if !errors.Is(err, ErrMultipleStatements) {
+		t.Errorf("expected ErrMultipleStatements, got %v", err)
+	}
+
+	result, err := database.ReadOnlyQuery(ctx, "SELECT COUNT(*) FROM clients;")
+	if err != nil {
+		t.Fatalf("ReadOnlyQuery failed: %v", err)
+	}
+	if got := result.Rows[0][0]; got != int64(1) {
+		t.Errorf("expected the client to survive, got %v clients", got)
+	}
+
+	if _, err := database.ReadOnlyQuery(ctx, "WITH x AS (SELECT 1) DELETE FROM clients"); err == nil {
+		t.Error("expected query_only to refuse a DELETE behind a WITH")
+	}
+}
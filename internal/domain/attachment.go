@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// AttachmentOwner is the kind of record a file is attached to. Only invoices
+// take attachments: timesink has no expense records for receipts to belong
+// to, so expenses would first need a table of their own and an owner here.
+type AttachmentOwner string
+
+const (
+	AttachmentOwnerInvoice AttachmentOwner = "invoice"
+)
+
+// Attachment is a file such as a signed contract, receipt, or the PDF that
+// was sent, copied into the managed attachments directory
+type Attachment struct {
+	ID        int64
+	OwnerType AttachmentOwner
+	OwnerID   int64
+	Name      string // original file name
+	Path      string // relative to the attachments directory
+	Size      int64  // bytes
+	SHA256    string // hex digest of the stored file
+	CreatedAt time.Time
+}
+
+// Validate returns an error if the attachment is invalid
+func (a *Attachment) Validate() error {
+	if a.OwnerType != AttachmentOwnerInvoice {
+		return errors.New("unknown attachment owner: " + string(a.OwnerType))
+	}
+	if a.OwnerID <= 0 {
+		return errors.New("attachment owner ID is required")
+	}
+	if strings.TrimSpace(a.Name) == "" {
+		return errors.New("attachment name is required")
+	}
+	if strings.TrimSpace(a.Path) == "" {
+		return errors.New("attachment path is required")
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BillingRules adjust what a client is billed for the time logged. They are
+// applied when invoice totals are calculated and appear as separate
+// adjustment line items, so entries and their line items stay as logged.
+type BillingRules struct {
+	MinIncrementMinutes int     // bill each entry in whole increments, at least one; 0 = exact time
+	DailyCapHours       float64 // billable hours per day before overtime; 0 = no cap
+	OvertimeMultiplier  float64 // rate multiplier for hours over the cap; 0 = not billed
+}
+
+// IsZero returns true if no rule is set
+func (r BillingRules) IsZero() bool {
+	return r == BillingRules{}
+}
+
+// Validate returns an error if the rules are invalid
+func (r BillingRules) Validate() error {
+	if r.MinIncrementMinutes < 0 {
+		return errors.New("minimum increment cannot be negative")
+	}
+	if r.DailyCapHours < 0 || r.DailyCapHours > 24 {
+		return errors.New("daily cap must be between 0 and 24 hours")
+	}
+	if r.OvertimeMultiplier < 0 {
+		return errors.New("overtime multiplier cannot be negative")
+	}
+	if r.OvertimeMultiplier > 0 && r.DailyCapHours == 0 {
+		return errors.New("overtime multiplier requires a daily cap")
+	}
+	return nil
+}
+
+// Describe summarizes the rules, e.g. "min 1h, cap 8h/day, overtime 1.5x"
+func (r BillingRules) Describe() string {
+	if r.IsZero() {
+		return "none"
+	}
+	s := ""
+	add := func(part string) {
+		if s != "" {
+			s += ", "
+		}
+		s += part
+	}
+	if r.MinIncrementMinutes > 0 {
+		add("min " + formatIncrement(r.MinIncrementMinutes))
+	}
+	if r.DailyCapHours > 0 {
+		add("cap " + formatCap(r.DailyCapHours) + "/day")
+		if r.OvertimeMultiplier > 0 {
+			add("overtime " + strconv.FormatFloat(r.OvertimeMultiplier, 'f', -1, 64) + "x")
+		}
+	}
+	return s
+}
+
+// Adjustments returns the line items the rules add to an invoice's entry
+// line items: one per day for rounding up to the minimum increment, and one
+// per day for hours over the daily cap. Hours over the cap are billed at the
+// overtime multiplier, or written off when there is none. Adjustment items
+// have no entry. Non-billable items and items copied from an estimate are
+// left alone.
+func (r BillingRules) Adjustments(items []*InvoiceLineItem) []*InvoiceLineItem {
+	if r.IsZero() {
+		return nil
+	}
+
+	billable := make([]*InvoiceLineItem, 0, len(items))
+	for _, item := range items {
+		if item.EntryID != 0 && item.Amount != 0 && item.Hours > 0 {
+			billable = append(billable, item)
+		}
+	}
+	sort.SliceStable(billable, func(i, j int) bool {
+		return billable[i].Date.Before(billable[j].Date)
+	})
+
+	type dayTotals struct {
+		date                      time.Time
+		billed                    float64 // hours after rounding, for the cap
+		roundHours, roundAmount   float64
+		excessHours, excessAmount float64
+	}
+	var days []*dayTotals
+	for _, item := range billable {
+		date := RateDay(item.Date)
+		if len(days) == 0 || !days[len(days)-1].date.Equal(date) {
+			days = append(days, &dayTotals{date: date})
+		}
+		day := days[len(days)-1]
+
+		hours := r.billedHours(item.Hours)
+		day.roundHours += hours - item.Hours
+		day.roundAmount += (hours - item.Hours) * item.Rate
+
+		if r.DailyCapHours > 0 {
+			over := math.Min(hours, day.billed+hours-r.DailyCapHours)
+			if over > 0 {
+				day.excessHours += over
+				day.excessAmount += over * item.Rate
+			}
+		}
+		day.billed += hours
+	}
+
+	var adjustments []*InvoiceLineItem
+	for _, day := range days {
+		if day.roundHours > hourTolerance {
+			adjustments = append(adjustments, &InvoiceLineItem{
+				Date:        day.date,
+				Description: "Rounded up to " + formatIncrement(r.MinIncrementMinutes) + " increments",
+				Hours:       day.roundHours,
+				Rate:        day.roundAmount / day.roundHours,
+				Amount:      MoneyFromFloat(day.roundAmount),
+			})
+		}
+		if day.excessHours <= hourTolerance || r.OvertimeMultiplier == 1 {
+			continue
+		}
+		rate := day.excessAmount / day.excessHours
+		if r.OvertimeMultiplier == 0 {
+			adjustments = append(adjustments, &InvoiceLineItem{
+				Date:        day.date,
+				Description: "Over " + formatCap(r.DailyCapHours) + " daily cap, not billed",
+				Hours:       -day.excessHours,
+				Rate:        rate,
+				Amount:      -MoneyFromFloat(day.excessAmount),
+			})
+			continue
+		}
+		// The hours are already billed at the normal rate; add the premium
+		premium := r.OvertimeMultiplier - 1
+		adjustments = append(adjustments, &InvoiceLineItem{
+			Date: day.date,
+			Description: fmt.Sprintf("Overtime over %s at %sx",
+				formatCap(r.DailyCapHours), strconv.FormatFloat(r.OvertimeMultiplier, 'f', -1, 64)),
+			Hours:  day.excessHours,
+			Rate:   rate * premium,
+			Amount: MoneyFromFloat(day.excessAmount * premium),
+		})
+	}
+
+	return adjustments
+}
+
+// hourTolerance ignores rounding noise of well under a second
+const hourTolerance = 1e-6
+
+// billedHours rounds hours up to whole minimum increments
+func (r BillingRules) billedHours(hours float64) float64 {
+	if r.MinIncrementMinutes <= 0 {
+		return hours
+	}
+	increment := float64(r.MinIncrementMinutes)
+	n := math.Ceil(hours*60/increment - hourTolerance)
+	if n < 1 {
+		n = 1
+	}
+	return n * increment / 60
+}
+
+// formatIncrement formats an increment in minutes, e.g. "15m" or "1h"
+func formatIncrement(minutes int) string {
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// formatCap formats a daily cap in hours, e.g. "8h" or "7.5h"
+func formatCap(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+}
@@ -7,14 +7,21 @@ import (
 )
 
 type Client struct {
-	ID         int64
-	Name       string
-	Email      string
-	HourlyRate float64
-	Notes      string
-	IsArchived bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID              int64
+	Name            string
+	Email           string
+	HourlyRate      float64
+	Notes           string
+	IsArchived      bool
+	AttachTimesheet bool // write a timesheet of the invoiced entries next to each invoice
+	Billing         BillingRules
+	ReverseCharge   bool    // invoiced without tax; the recipient accounts for VAT
+	RequireApproval bool    // only approved entries can be invoiced
+	Language        string  // of the labels on invoices, e.g. "de"; empty for English
+	WeeklyDemand    float64 // hours of work the client is expected to need each week; 0 if unplanned
+	Version         int64   // bumped on every write, so a stale Update is refused
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // NewClient creates a new client with required fields
@@ -36,5 +43,126 @@ func (c *Client) Validate() error {
 	if c.HourlyRate < 0 {
 		return errors.New("hourly rate cannot be negative")
 	}
+	if c.WeeklyDemand < 0 || c.WeeklyDemand > 168 {
+		return errors.New("weekly demand must be between 0 and 168 hours")
+	}
+	return c.Billing.Validate()
+}
+
+// NormalizeClientName folds a client name for comparison: surrounding
+// space is dropped, runs of inner space become one, and case is ignored
+func NormalizeClientName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// HasName reports whether the client is called name, ignoring case and
+// spacing, so "ACME" and "Acme " are the same client
+func (c *Client) HasName(name string) bool {
+	return NormalizeClientName(c.Name) == NormalizeClientName(name)
+}
+
+// ResemblesName reports whether name is close to the client's name: the
+// same name, one a prefix of the other, or a few typos apart
+func (c *Client) ResemblesName(name string) bool {
+	a, b := NormalizeClientName(c.Name), NormalizeClientName(name)
+	if a == "" || b == "" {
+		return false
+	}
+	if strings.HasPrefix(a, b) || strings.HasPrefix(b, a) {
+		return true
+	}
+	// One typo per four characters of the shorter name, at least one
+	allowed := max(1, min(len([]rune(a)), len([]rune(b)))/4)
+	return editDistance(a, b) <= allowed
+}
+
+// editDistance is the Levenshtein distance between two strings in runes
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// CanInvoice reports whether an entry may go on an invoice to the client
+func (c *Client) CanInvoice(entry *TimeEntry) bool {
+	return !c.RequireApproval || entry.Approval == ApprovalApproved
+}
+
+// ClientRate is a client's hourly rate from a given day onwards. Entries
+// freeze the rate in effect on the day they start.
+type ClientRate struct {
+	ID            int64
+	ClientID      int64
+	HourlyRate    float64
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+// RateOn returns the rate in effect at a time from the client's rate
+// history, newest first. Times before the first recorded rate use that
+// first rate, and without a history it is the client's current rate.
+func (c *Client) RateOn(history []*ClientRate, at time.Time) float64 {
+	for _, rate := range history {
+		if !rate.EffectiveFrom.After(at) {
+			return rate.HourlyRate
+		}
+	}
+	if len(history) > 0 {
+		return history[len(history)-1].HourlyRate
+	}
+	return c.HourlyRate
+}
+
+// RoleRate is a named rate on a client's rate card, e.g. "development" at
+// one rate and "consulting" at another. An entry for a role is billed at
+// the role's rate instead of the client's.
+type RoleRate struct {
+	ID         int64
+	ClientID   int64
+	Role       string
+	HourlyRate float64
+	CreatedAt  time.Time
+}
+
+// Validate returns an error if the role rate is invalid
+func (r *RoleRate) Validate() error {
+	if strings.TrimSpace(r.Role) == "" {
+		return errors.New("role name is required")
+	}
+	if r.HourlyRate < 0 {
+		return errors.New("hourly rate cannot be negative")
+	}
+	return nil
+}
+
+// FindRole returns the rate for role on a rate card, ignoring case and
+// surrounding space, or nil if the card has no such role
+func FindRole(card []*RoleRate, role string) *RoleRate {
+	role = strings.TrimSpace(role)
+	for _, r := range card {
+		if strings.EqualFold(r.Role, role) {
+			return r
+		}
+	}
 	return nil
 }
+
+// RateDay returns the start of the day a rate set at t takes effect
+func RateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ContractWarningDays is how far ahead the dashboard warns about contracts
+// that are about to end
+const ContractWarningDays = 30
+
+// Contract records an engagement with a client. It is informational: entries
+// are still priced at the client's rate, and logging time outside a
+// contract is allowed but flagged.
+type Contract struct {
+	ID        int64
+	ClientID  int64
+	StartDate time.Time
+	EndDate   time.Time // last day of the contract
+	Rate      float64   // agreed hourly rate; 0 when the client's rate applies
+	Scope     string
+	Renews    bool // renews automatically, so it never lapses
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewContract creates a contract running from start to end, inclusive
+func NewContract(clientID int64, start, end time.Time) *Contract {
+	now := time.Now()
+	return &Contract{
+		ClientID:  clientID,
+		StartDate: RateDay(start),
+		EndDate:   RateDay(end),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate returns an error if the contract is invalid
+func (c *Contract) Validate() error {
+	if c.ClientID == 0 {
+		return errors.New("client is required")
+	}
+	if c.StartDate.IsZero() || c.EndDate.IsZero() {
+		return errors.New("start and end dates are required")
+	}
+	if c.EndDate.Before(c.StartDate) {
+		return errors.New("end date cannot be before start date")
+	}
+	if c.Rate < 0 {
+		return errors.New("rate cannot be negative")
+	}
+	return nil
+}
+
+// Covers reports whether t falls within the contract. A renewing contract
+// covers everything from its start date on.
+func (c *Contract) Covers(t time.Time) bool {
+	if t.Before(c.StartDate) {
+		return false
+	}
+	return c.Renews || t.Before(c.EndDate.AddDate(0, 0, 1))
+}
+
+// EndsWithin reports whether the contract's last day is between today and
+// the given number of days from now
+func (c *Contract) EndsWithin(now time.Time, days int) bool {
+	today := RateDay(now)
+	return !c.EndDate.Before(today) && !c.EndDate.After(today.AddDate(0, 0, days))
+}
+
+// DaysLeft returns the number of days from now until the contract's last day
+func (c *Contract) DaysLeft(now time.Time) int {
+	return int(c.EndDate.Sub(RateDay(now)).Hours() / 24)
+}
+
+// ContractLapsed reports whether work at t falls after a client's contracts
+// ended: the client has contracts, none of them covers t, and at least one
+// ended before it. Work before the first contract starts is not flagged.
+func ContractLapsed(contracts []*Contract, t time.Time) bool {
+	lapsed := false
+	for _, c := range contracts {
+		if c.Covers(t) {
+			return false
+		}
+		if !c.Renews && t.After(c.EndDate) {
+			lapsed = true
+		}
+	}
+	return lapsed
+}
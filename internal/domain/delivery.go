@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// InvoiceDelivery records one sending of an invoice to a recipient, and when
+// the recipient first opened it if a read receipt or provider webhook said so
+type InvoiceDelivery struct {
+	ID        int64
+	InvoiceID int64
+	Recipient string // e-mail address or name the invoice was sent to
+	MessageID string // provider or Message-ID header, for matching receipts
+	SentAt    time.Time
+	OpenedAt  *time.Time // nil until an open is recorded
+	CreatedAt time.Time
+}
+
+// IsOpened returns true if the recipient is known to have opened the invoice
+func (d *InvoiceDelivery) IsOpened() bool {
+	return d.OpenedAt != nil
+}
+
+// MarkOpened records the first time the delivery was opened. Later opens
+// are ignored so the log shows when the client first saw the invoice.
+func (d *InvoiceDelivery) MarkOpened(at time.Time) bool {
+	if d.OpenedAt != nil && !at.Before(*d.OpenedAt) {
+		return false
+	}
+	d.OpenedAt = &at
+	return true
+}
+
+// Validate returns an error if the delivery is invalid
+func (d *InvoiceDelivery) Validate() error {
+	if d.InvoiceID <= 0 {
+		return errors.New("invoice ID is required")
+	}
+	if d.SentAt.IsZero() {
+		return errors.New("sent time is required")
+	}
+	if d.OpenedAt != nil && d.OpenedAt.Before(d.SentAt) {
+		return errors.New("opened time must be after the sent time")
+	}
+	return nil
+}
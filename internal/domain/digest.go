@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Digest summarizes a week for the weekly e-mail: the hours logged for each
+// client against the week before, billable time not yet invoiced, and the
+// invoices still awaiting payment when the week ended
+type Digest struct {
+	Start      time.Time // Monday the week began
+	End        time.Time // the Monday after
+	Clients    []*DigestClient
+	Hours      float64
+	PriorHours float64 // logged the week before
+
+	Unbilled        Money // billable time not yet invoiced, from any week
+	AwaitingPayment []*DigestInvoice
+	Outstanding     Money // total of AwaitingPayment
+}
+
+// DigestClient is the time logged for a client in a digest's week and the
+// week before
+type DigestClient struct {
+	Client     *Client
+	Hours      float64
+	PriorHours float64
+}
+
+// Change returns the hours logged this week less those logged the week before
+func (c *DigestClient) Change() float64 {
+	return c.Hours - c.PriorHours
+}
+
+// DigestInvoice is a sent or overdue invoice awaiting payment
+type DigestInvoice struct {
+	Invoice     *Invoice
+	Due         time.Time
+	DaysPastDue int // at the end of the digest's week
+}
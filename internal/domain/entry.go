@@ -2,20 +2,48 @@ package domain
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// ApprovalStatus records whether the client signed off on an entry
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// ParseApprovalStatus looks up an approval status by name
+func ParseApprovalStatus(s string) (ApprovalStatus, error) {
+	switch status := ApprovalStatus(s); status {
+	case ApprovalPending, ApprovalApproved, ApprovalRejected:
+		return status, nil
+	}
+	return "", fmt.Errorf("unknown approval status %q (expected pending, approved, or rejected)", s)
+}
+
 type TimeEntry struct {
 	ID              int64
 	ClientID        int64
 	Description     string
+	Notes           string // notes carried over from the timer
 	StartTime       time.Time
 	EndTime         *time.Time // nil if still running
-	DurationSeconds *int64     // calculated, nil if still running
+	DurationSeconds *int64     // time worked, calculated, nil if still running
+	Pauses          []Pause    // when the timer the entry came from was paused, oldest first
 	HourlyRate      float64    // frozen at entry time
+	RateReason      string     // why the rate differs from the client's rate that day
+	Role            string     // rate card role billed, e.g. "consulting"; empty for the client's rate
+	Location        string     // where the work was done, e.g. "on-site"; empty if not recorded
 	IsBillable      bool
 	IsDeleted       bool   // soft delete
 	InvoiceID       *int64 // nil = unbilled, non-nil = locked
+	Approval        ApprovalStatus
+	ApprovalNote    string // e.g. why the client rejected the entry
+	Version         int64  // row version when read, checked by Update
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -29,26 +57,44 @@ func NewTimeEntry(clientID int64, description string, hourlyRate float64) *TimeE
 		StartTime:   now,
 		HourlyRate:  hourlyRate,
 		IsBillable:  true,
+		Approval:    ApprovalPending,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 }
 
-// Duration returns the duration of the entry
+// Duration returns the time worked on the entry: its span, less the time
+// the timer it came from was paused
 func (e *TimeEntry) Duration() time.Duration {
+	return e.Span() - e.pausedTime()
+}
+
+// Span returns the time from the entry's start to its end, or to now while
+// it runs, pauses included
+func (e *TimeEntry) Span() time.Duration {
 	if e.EndTime == nil {
 		return time.Since(e.StartTime)
 	}
 	return e.EndTime.Sub(e.StartTime)
 }
 
-// Amount returns the billable amount (hours * rate)
-func (e *TimeEntry) Amount() float64 {
+// Amount returns the billable amount (hours * rate), rounded to the cent
+func (e *TimeEntry) Amount() Money {
 	if !e.IsBillable {
 		return 0
 	}
-	hours := e.Duration().Hours()
-	return hours * e.HourlyRate
+	return AmountFor(e.Duration().Hours(), e.HourlyRate)
+}
+
+// AtLocation reports whether the entry was recorded at location, ignoring
+// case and surrounding spaces. An empty location matches entries without one.
+func (e *TimeEntry) AtLocation(location string) bool {
+	return strings.EqualFold(strings.TrimSpace(e.Location), strings.TrimSpace(location))
+}
+
+// Overlaps reports whether the entry shares any time with start to end
+func (e *TimeEntry) Overlaps(start, end time.Time) bool {
+	return e.StartTime.Before(end) && e.StartTime.Add(e.Span()).After(start)
 }
 
 // IsLocked returns true if the entry is attached to an invoice
@@ -61,14 +107,110 @@ func (e *TimeEntry) IsRunning() bool {
 	return e.EndTime == nil
 }
 
-// Stop sets the end time and calculates duration
+// pausedTime is how much of the entry's span it was paused
+func (e *TimeEntry) pausedTime() time.Duration {
+	var paused time.Duration
+	for _, p := range e.pausesWithin() {
+		paused += p.End.Sub(p.Start)
+	}
+	return paused
+}
+
+// pausesWithin returns the parts of the entry's pauses that fall between its
+// start and end, without overlaps
+func (e *TimeEntry) pausesWithin() []Pause {
+	end := e.StartTime.Add(e.Span())
+	var within []Pause
+	from := e.StartTime
+	for _, p := range e.Pauses {
+		if p.Start.After(from) {
+			from = p.Start
+		}
+		to := p.End
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			within = append(within, Pause{Start: from, End: to})
+			from = to
+		}
+	}
+	return within
+}
+
+// Stop sets the end time and calculates duration, keeping the pauses that
+// still fall within the entry
 func (e *TimeEntry) Stop(endTime time.Time) {
 	e.EndTime = &endTime
+	e.Pauses = e.pausesWithin()
 	durationSecs := int64(e.Duration().Seconds())
 	e.DurationSeconds = &durationSecs
 	e.UpdatedAt = time.Now()
 }
 
+// SplitAt shortens the entry to end at the given time and returns a new entry
+// covering the remainder, with the same client, rate, description, billable
+// flag and approval. Each part keeps the pauses that fell within it. The
+// returned entry has no ID until it is persisted.
+func (e *TimeEntry) SplitAt(at time.Time) (*TimeEntry, error) {
+	if e.IsLocked() {
+		return nil, errors.New("cannot split an entry locked by an invoice")
+	}
+	if e.EndTime == nil {
+		return nil, errors.New("cannot split a running entry")
+	}
+	if !at.After(e.StartTime) || !at.Before(*e.EndTime) {
+		return nil, errors.New("split time must be between the entry's start and end")
+	}
+
+	originalEnd := *e.EndTime
+	now := time.Now()
+
+	second := &TimeEntry{
+		ClientID:     e.ClientID,
+		Description:  e.Description,
+		Notes:        e.Notes,
+		StartTime:    at,
+		Pauses:       e.Pauses,
+		HourlyRate:   e.HourlyRate,
+		RateReason:   e.RateReason,
+		Role:         e.Role,
+		Location:     e.Location,
+		IsBillable:   e.IsBillable,
+		Approval:     e.Approval,
+		ApprovalNote: e.ApprovalNote,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	second.Stop(originalEnd)
+
+	e.Stop(at)
+	return second, nil
+}
+
+// SplitAtMidnight splits a stopped entry that runs past midnight so that
+// each part falls on one day: the entry is shortened to end at the first
+// midnight and the parts after it are returned, in order. An entry within a
+// single day is left alone and nothing is returned.
+func (e *TimeEntry) SplitAtMidnight() ([]*TimeEntry, error) {
+	var rest []*TimeEntry
+	last := e
+	for last.EndTime != nil {
+		start := last.StartTime
+		midnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+		if !midnight.Before(*last.EndTime) {
+			break
+		}
+		next, err := last.SplitAt(midnight)
+		if err != nil {
+			return nil, err
+		}
+		rest = append(rest, next)
+		last = next
+	}
+	return rest, nil
+}
+
 // Validate returns an error if the entry is invalid
 func (e *TimeEntry) Validate() error {
 	if e.ClientID <= 0 {
@@ -85,3 +227,25 @@ func (e *TimeEntry) Validate() error {
 	}
 	return nil
 }
+
+// RateDiffers reports whether the entry is billed at a rate other than the
+// client's rate on its day, to the cent
+func (e *TimeEntry) RateDiffers(clientRate float64) bool {
+	return MoneyFromFloat(e.HourlyRate) != MoneyFromFloat(clientRate)
+}
+
+// SetRate bills the entry at rate, where clientRate is the client's rate on
+// the entry's day. A rate other than the client's needs a reason, since rate
+// discrepancies are the usual start of a billing dispute; the reason is
+// dropped when the rates agree.
+func (e *TimeEntry) SetRate(rate, clientRate float64, reason string) error {
+	e.HourlyRate = rate
+	e.RateReason = ""
+	if !e.RateDiffers(clientRate) {
+		return nil
+	}
+	if e.RateReason = strings.TrimSpace(reason); e.RateReason == "" {
+		return fmt.Errorf("the rate %.2f differs from the client's rate of %.2f that day; give a reason for it", rate, clientRate)
+	}
+	return nil
+}
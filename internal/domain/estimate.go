@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type EstimateStatus string
+
+const (
+	EstimateStatusDraft    EstimateStatus = "draft"
+	EstimateStatusSent     EstimateStatus = "sent"
+	EstimateStatusAccepted EstimateStatus = "accepted"
+	EstimateStatusDeclined EstimateStatus = "declined"
+)
+
+// Estimate is a quote sent to a client before the work is done. Once
+// accepted, its line items can be copied into a draft invoice.
+type Estimate struct {
+	ID             int64
+	EstimateNumber string
+	ClientID       int64
+	IssueDate      time.Time
+	ValidUntil     *time.Time
+	Status         EstimateStatus
+	Total          Money // before tax; tax is added on the invoice
+	Notes          string
+	InvoiceID      *int64 // draft invoice the estimate was converted into
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	// Related data (populated by repository)
+	LineItems []*EstimateLineItem
+	Client    *Client
+}
+
+// EstimateLineItem is either projected hours at a rate or a fixed amount
+type EstimateLineItem struct {
+	ID          int64
+	EstimateID  int64
+	Description string
+	Hours       float64 // 0 for fixed-price items
+	Rate        float64
+	Amount      Money
+}
+
+// NewEstimate creates a new draft estimate
+func NewEstimate(estimateNumber string, clientID int64, issueDate time.Time) *Estimate {
+	now := time.Now()
+	return &Estimate{
+		EstimateNumber: estimateNumber,
+		ClientID:       clientID,
+		IssueDate:      issueDate,
+		Status:         EstimateStatusDraft,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		LineItems:      make([]*EstimateLineItem, 0),
+	}
+}
+
+// NewProjectedLineItem creates a line item for projected hours at a rate
+func NewProjectedLineItem(description string, hours, rate float64) *EstimateLineItem {
+	return &EstimateLineItem{
+		Description: description,
+		Hours:       hours,
+		Rate:        rate,
+		Amount:      AmountFor(hours, rate),
+	}
+}
+
+// NewFixedLineItem creates a line item for a fixed amount
+func NewFixedLineItem(description string, amount Money) *EstimateLineItem {
+	return &EstimateLineItem{
+		Description: description,
+		Amount:      amount,
+	}
+}
+
+// IsFixed returns true if the item is a fixed amount rather than hours
+func (li *EstimateLineItem) IsFixed() bool {
+	return li.Hours == 0
+}
+
+// Validate returns an error if the line item is invalid
+func (li *EstimateLineItem) Validate() error {
+	if strings.TrimSpace(li.Description) == "" {
+		return errors.New("line item description is required")
+	}
+	if li.Hours < 0 {
+		return errors.New("projected hours cannot be negative")
+	}
+	if li.Rate < 0 {
+		return errors.New("rate cannot be negative")
+	}
+	return nil
+}
+
+// CanEdit returns true if line items can still be changed
+func (e *Estimate) CanEdit() bool {
+	return e.Status == EstimateStatusDraft
+}
+
+// CanRespond returns true if the client's answer can be recorded
+func (e *Estimate) CanRespond() bool {
+	return e.Status == EstimateStatusDraft || e.Status == EstimateStatusSent
+}
+
+// CanConvert returns true if the estimate is accepted and has not been
+// converted into an invoice yet
+func (e *Estimate) CanConvert() bool {
+	return e.Status == EstimateStatusAccepted && e.InvoiceID == nil
+}
+
+// IsExpired returns true if the estimate is still awaiting an answer past
+// its validity date
+func (e *Estimate) IsExpired(now time.Time) bool {
+	return e.CanRespond() && e.ValidUntil != nil && now.After(*e.ValidUntil)
+}
+
+// CalculateTotal recalculates the total from line items
+func (e *Estimate) CalculateTotal() {
+	e.Total = 0
+	for _, item := range e.LineItems {
+		e.Total += item.Amount
+	}
+	e.UpdatedAt = time.Now()
+}
+
+// Validate returns an error if the estimate is invalid
+func (e *Estimate) Validate() error {
+	if e.EstimateNumber == "" {
+		return errors.New("estimate number is required")
+	}
+	if e.ClientID <= 0 {
+		return errors.New("client ID is required")
+	}
+	if e.IssueDate.IsZero() {
+		return errors.New("issue date is required")
+	}
+	if e.ValidUntil != nil && e.ValidUntil.Before(e.IssueDate) {
+		return errors.New("valid until must be after the issue date")
+	}
+	switch e.Status {
+	case EstimateStatusDraft, EstimateStatusSent, EstimateStatusAccepted, EstimateStatusDeclined:
+	default:
+		return errors.New("unknown estimate status: " + string(e.Status))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InterestRate is a simple late-payment interest rate, e.g. 1.5% a month
+// or a statutory 8% a year
+type InterestRate struct {
+	Percent  float64
+	PerMonth bool // false for a yearly rate
+}
+
+// ParseInterestRate parses rates such as "1.5%/month" or "8%/year". A rate
+// without a period is yearly.
+func ParseInterestRate(s string) (InterestRate, error) {
+	value, period, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "/")
+	var rate InterestRate
+	switch strings.TrimSpace(period) {
+	case "month", "mo", "m":
+		rate.PerMonth = true
+	case "", "year", "yr", "y", "annum", "a":
+	default:
+		return rate, fmt.Errorf("unknown interest period %q (expected month or year)", period)
+	}
+
+	// A percentage has no thousands, so a comma can only be a decimal comma
+	value = strings.ReplaceAll(strings.TrimSuffix(strings.TrimSpace(value), "%"), ",", ".")
+	percent, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return rate, fmt.Errorf("invalid interest rate %q (expected e.g. 1.5%%/month or 8%%/year)", s)
+	}
+	if percent <= 0 {
+		return rate, fmt.Errorf("interest rate must be positive")
+	}
+	rate.Percent = percent
+	return rate, nil
+}
+
+// String formats the rate the way ParseInterestRate reads it
+func (r InterestRate) String() string {
+	period := "year"
+	if r.PerMonth {
+		period = "month"
+	}
+	return strconv.FormatFloat(r.Percent, 'f', -1, 64) + "%/" + period
+}
+
+// Yearly returns the rate per year as a fraction, e.g. 0.18 for 1.5%/month
+func (r InterestRate) Yearly() float64 {
+	if r.PerMonth {
+		return r.Percent / 100 * 12
+	}
+	return r.Percent / 100
+}
+
+// InterestCharge is simple interest on an invoice's total from its due date
+// until it was paid, or until a given day if it is still outstanding
+type InterestCharge struct {
+	InvoiceID     int64
+	InvoiceNumber string
+	ClientID      int64
+	Principal     Money
+	DueDate       time.Time
+	Until         time.Time
+	Days          int // whole days late
+	Rate          InterestRate
+	Amount        Money
+}
+
+// NewInterestCharge calculates the interest owed on an invoice that was due
+// on the given day. Interest accrues daily on a 365-day year and stops on
+// the paid date. Invoices paid on time owe nothing.
+func NewInterestCharge(invoice *Invoice, due, asOf time.Time, rate InterestRate) *InterestCharge {
+	until := asOf
+	if invoice.PaidDate != nil {
+		until = *invoice.PaidDate
+	}
+
+	charge := &InterestCharge{
+		InvoiceID:     invoice.ID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		ClientID:      invoice.ClientID,
+		Principal:     invoice.Total,
+		DueDate:       due,
+		Until:         until,
+		Rate:          rate,
+	}
+	charge.Days = daysBetween(due, until)
+	if charge.Days <= 0 {
+		charge.Days = 0
+		return charge
+	}
+	charge.Amount = charge.Principal.Times(rate.Yearly() * float64(charge.Days) / 365)
+	return charge
+}
+
+// LineItem returns the charge as a line item for a follow-up invoice
+func (c *InterestCharge) LineItem() *InvoiceLineItem {
+	return &InvoiceLineItem{
+		InterestInvoiceID: c.InvoiceID,
+		Date:              c.Until,
+		Description:       fmt.Sprintf("Interest on %s, %d days late", c.InvoiceNumber, c.Days),
+		Amount:            c.Amount,
+	}
+}
+
+// daysBetween counts the calendar days from one day to another
+func daysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
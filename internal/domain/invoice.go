@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,7 @@ const (
 	InvoiceStatusSent      InvoiceStatus = "sent"
 	InvoiceStatusPaid      InvoiceStatus = "paid"
 	InvoiceStatusOverdue   InvoiceStatus = "overdue"
+	InvoiceStatusVoid      InvoiceStatus = "void" // a deleted draft, kept so its number is not reused
 )
 
 type Invoice struct {
@@ -21,30 +23,75 @@ type Invoice struct {
 	ClientID      int64
 	PeriodStart   time.Time
 	PeriodEnd     time.Time
-	Subtotal      float64
+	Subtotal      Money
 	TaxRate       float64
-	TaxAmount     float64
-	Total         float64
+	TaxAmount     Money
+	Total         Money
 	Status        InvoiceStatus
 	DueDate       *time.Time
 	PaidDate      *time.Time
+	Footer        InvoiceFooter
+	Version       int64 // row version when read
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 
 	// Related data (populated by repository)
 	LineItems []*InvoiceLineItem
+	Taxes     []*InvoiceTax
 	Client    *Client
 }
 
 type InvoiceLineItem struct {
-	ID          int64
-	InvoiceID   int64
-	EntryID     int64
-	Date        time.Time
-	Description string
-	Hours       float64
-	Rate        float64
-	Amount      float64
+	ID                int64
+	InvoiceID         int64
+	EntryID           int64 // 0 for adjustments derived from billing rules
+	EstimateID        int64 // set on items copied from an accepted estimate
+	InterestInvoiceID int64 // set on late-payment interest charged for another invoice
+	Date              time.Time
+	Description       string
+	Hours             float64
+	Rate              float64
+	Amount            Money
+	RateReason        string // copied from an entry billed at a rate other than the client's
+	Role              string // copied from an entry billed at a rate card role
+
+	// The description as it was copied onto the invoice, kept when it is
+	// reworded; empty if it never was
+	OriginalDescription string
+}
+
+// InvoiceFooter is the free text printed at the bottom of an invoice.
+// It can be edited until the invoice is finalized.
+type InvoiceFooter struct {
+	Notes               string // e.g. thanks or terms
+	PaymentInstructions string // e.g. bank details or a PayPal address
+}
+
+// IsZero returns true if there is nothing to print
+func (f InvoiceFooter) IsZero() bool {
+	return strings.TrimSpace(f.Notes) == "" && strings.TrimSpace(f.PaymentInstructions) == ""
+}
+
+// DueDateChange records a due date moved after the invoice was sent, when
+// the client already had the old one, and the reason given
+type DueDateChange struct {
+	ID        int64
+	InvoiceID int64
+	From      *time.Time // nil if the invoice had no due date
+	To        time.Time
+	Note      string
+	ChangedAt time.Time
+}
+
+// InvoiceTax is one tax line on an invoice, e.g. VAT or a local surcharge.
+// An invoice with tax lines has TaxRate and TaxAmount set to their sums.
+type InvoiceTax struct {
+	ID        int64
+	InvoiceID int64
+	Label     string
+	Rate      float64
+	Amount    Money
+	Note      string // e.g. a reverse-charge statement
 }
 
 // NewInvoice creates a new draft invoice
@@ -62,6 +109,46 @@ func NewInvoice(invoiceNumber string, clientID int64, periodStart, periodEnd tim
 	}
 }
 
+// IsAdjustment returns true if the item was derived from the client's
+// billing rules rather than billed from a time entry, an estimate or as
+// interest
+func (li *InvoiceLineItem) IsAdjustment() bool {
+	return li.EntryID == 0 && li.EstimateID == 0 && li.InterestInvoiceID == 0
+}
+
+// IsInterest returns true if the item charges interest on a late invoice
+func (li *InvoiceLineItem) IsInterest() bool {
+	return li.InterestInvoiceID != 0
+}
+
+// IsEstimated returns true if the item was copied from an estimate
+func (li *InvoiceLineItem) IsEstimated() bool {
+	return li.EstimateID != 0
+}
+
+// Reword replaces the item's description, keeping the one it was copied
+// with. An empty description restores that original.
+func (li *InvoiceLineItem) Reword(description string) {
+	description = strings.TrimSpace(description)
+	original := li.OriginalDescription
+	if original == "" {
+		original = li.Description
+	}
+	if description == "" {
+		description = original
+	}
+	if description == original {
+		original = ""
+	}
+	li.Description, li.OriginalDescription = description, original
+}
+
+// IsReworded returns true if the description differs from the one the
+// item was copied with
+func (li *InvoiceLineItem) IsReworded() bool {
+	return li.OriginalDescription != ""
+}
+
 // CanEdit returns true if the invoice can be modified
 func (i *Invoice) CanEdit() bool {
 	return i.Status == InvoiceStatusDraft
@@ -69,7 +156,13 @@ func (i *Invoice) CanEdit() bool {
 
 // IsFinalized returns true if the invoice is finalized or later
 func (i *Invoice) IsFinalized() bool {
-	return i.Status != InvoiceStatusDraft
+	return i.Status != InvoiceStatusDraft && i.Status != InvoiceStatusVoid
+}
+
+// CanDelete returns true if the invoice can be voided. Only drafts qualify;
+// once finalized an invoice is part of the books.
+func (i *Invoice) CanDelete() bool {
+	return i.Status == InvoiceStatusDraft
 }
 
 // Finalize locks the invoice and prevents further edits
@@ -80,13 +173,67 @@ func (i *Invoice) Finalize() {
 	}
 }
 
-// CalculateTotals recalculates subtotal, tax, and total from line items
+// DueOn returns the invoice's due date, or defaultDays after it was created
+// when none is stored
+func (i *Invoice) DueOn(defaultDays int) time.Time {
+	if i.DueDate != nil {
+		return *i.DueDate
+	}
+	return i.CreatedAt.AddDate(0, 0, defaultDays)
+}
+
+// DaysPastDue returns how many whole days past its due date the invoice is
+// on the given day, or 0 if it is not yet due
+func (i *Invoice) DaysPastDue(defaultDays int, asOf time.Time) int {
+	return max(daysBetween(i.DueOn(defaultDays), asOf), 0)
+}
+
+// CanChangeDueDate returns true if the due date can still be moved: until
+// the invoice is paid
+func (i *Invoice) CanChangeDueDate() bool {
+	return i.Status != InvoiceStatusPaid && i.Status != InvoiceStatusVoid
+}
+
+// DueDateChangeNeedsNote returns true if the client has been sent the
+// invoice, so moving its due date is recorded with a reason
+func (i *Invoice) DueDateChangeNeedsNote() bool {
+	return i.Status == InvoiceStatusSent || i.Status == InvoiceStatusOverdue
+}
+
+// CanReopen returns true if the invoice can be moved back to draft.
+// Only finalized invoices qualify; once sent the client has seen it.
+func (i *Invoice) CanReopen() bool {
+	return i.Status == InvoiceStatusFinalized
+}
+
+// Reopen moves a finalized invoice back to draft
+func (i *Invoice) Reopen() {
+	if i.CanReopen() {
+		i.Status = InvoiceStatusDraft
+		i.UpdatedAt = time.Now()
+	}
+}
+
+// CalculateTotals recalculates subtotal, tax, and total from line items.
+// Each tax line applies to the subtotal and is rounded to the cent on its
+// own; without tax lines TaxRate does. The total is always the subtotal
+// plus the tax amount.
 func (i *Invoice) CalculateTotals() {
 	i.Subtotal = 0
 	for _, item := range i.LineItems {
 		i.Subtotal += item.Amount
 	}
-	i.TaxAmount = i.Subtotal * i.TaxRate
+	if len(i.Taxes) > 0 {
+		i.TaxRate = 0
+		i.TaxAmount = 0
+		for _, tax := range i.Taxes {
+			tax.Amount = i.Subtotal.Times(tax.Rate)
+			i.TaxRate += tax.Rate
+			i.TaxAmount += tax.Amount
+		}
+	} else {
+		i.TaxAmount = i.Subtotal.Times(i.TaxRate)
+	}
 	i.Total = i.Subtotal + i.TaxAmount
 	i.UpdatedAt = time.Now()
 }
@@ -111,5 +258,13 @@ func (i *Invoice) Validate() error {
 	if i.TaxRate < 0 || i.TaxRate > 1 {
 		return errors.New("tax rate must be between 0 and 1")
 	}
+	for _, tax := range i.Taxes {
+		if tax.Label == "" {
+			return errors.New("tax label is required")
+		}
+		if tax.Rate < 0 || tax.Rate > 1 {
+			return errors.New("tax rate must be between 0 and 1")
+		}
+	}
 	return nil
 }
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"log/slog"
+	"math"
+)
+
+// Money is an amount in cents. Amounts are rounded to the cent where they
+// are calculated, such as an entry's hours at its rate or a tax on a
+// subtotal, so sums of them come out exact.
+type Money int64
+
+// MoneyFromFloat rounds an amount in currency units to the nearest cent,
+// halves away from zero
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(amount * 100))
+}
+
+// AmountFor returns hours at an hourly rate, rounded to the cent
+func AmountFor(hours, rate float64) Money {
+	return MoneyFromFloat(hours * rate)
+}
+
+// Times returns the amount multiplied by a rate, e.g. a tax rate or an
+// overtime premium, rounded to the cent
+func (m Money) Times(rate float64) Money {
+	return Money(math.Round(float64(m) * rate))
+}
+
+// Float returns the amount in currency units, for display and for ratios
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// LogValue logs the amount in currency units rather than cents
+func (m Money) LogValue() slog.Value {
+	return slog.Float64Value(m.Float())
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestMoneyFromFloat_RoundsToNearestCent(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   Money
+	}{
+		{0, 0},
+		{12.34, 1234},
+		{1234.9999999, 123500},
+		{0.125, 13},
+		{-0.125, -13},
+		{100.0 / 3, 3333},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromFloat(tt.amount); got != tt.want {
+			t.Errorf("MoneyFromFloat(%v) = %d, want %d", tt.amount, got, tt.want)
+		}
+	}
+}
+
+// quickConfig makes the property tests repeatable
+var quickConfig = &quick.Config{MaxCount: 2000, Rand: rand.New(rand.NewSource(1))}
+
+// lineSpec is a generated line item: minutes worked at a rate in cents
+type lineSpec struct {
+	Minutes   uint16
+	RateCents uint16
+}
+
+// invoiceFrom builds an invoice from generated line items and tax rates in
+// hundredths of a percent, capped at 30%
+func invoiceFrom(lines []lineSpec, taxBasisPoints []uint16) *Invoice {
+	invoice := NewInvoice("INV-2026-001", 1, time.Now(), time.Now())
+	for _, l := range lines {
+		hours, rate := float64(l.Minutes)/60, float64(l.RateCents)/100
+		invoice.LineItems = append(invoice.LineItems, &InvoiceLineItem{
+			Hours:  hours,
+			Rate:   rate,
+			Amount: AmountFor(hours, rate),
+		})
+	}
+	for _, bp := range taxBasisPoints {
+		invoice.Taxes = append(invoice.Taxes, &InvoiceTax{Label: "Tax", Rate: float64(bp%3000) / 10000})
+	}
+	return invoice
+}
+
+func TestCalculateTotals_SubtotalPlusTaxIsTotal(t *testing.T) {
+	property := func(lines []lineSpec, taxBasisPoints []uint16) bool {
+		invoice := invoiceFrom(lines, taxBasisPoints)
+		invoice.CalculateTotals()
+
+		var subtotal, tax Money
+		for _, item := range invoice.LineItems {
+			subtotal += item.Amount
+		}
+		for _, line := range invoice.Taxes {
+			tax += line.Amount
+		}
+		if len(invoice.Taxes) == 0 {
+			tax = invoice.TaxAmount
+		}
+		return invoice.Subtotal == subtotal &&
+			invoice.TaxAmount == tax &&
+			invoice.Total == invoice.Subtotal+invoice.TaxAmount
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCalculateTotals_AmountsWithinHalfACent(t *testing.T) {
+	property := func(lines []lineSpec, taxBasisPoints []uint16) bool {
+		invoice := invoiceFrom(lines, taxBasisPoints)
+		invoice.CalculateTotals()
+
+		for _, item := range invoice.LineItems {
+			if math.Abs(item.Amount.Float()-item.Hours*item.Rate) > 0.005+1e-9 {
+				return false
+			}
+		}
+		for _, line := range invoice.Taxes {
+			if math.Abs(line.Amount.Float()-invoice.Subtotal.Float()*line.Rate) > 0.005+1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCalculateTotals_SingleTaxRate(t *testing.T) {
+	property := func(lines []lineSpec, taxBasisPoints uint16) bool {
+		invoice := invoiceFrom(lines, nil)
+		invoice.TaxRate = float64(taxBasisPoints%3000) / 10000
+		invoice.CalculateTotals()
+		return invoice.TaxAmount == invoice.Subtotal.Times(invoice.TaxRate) &&
+			invoice.Total == invoice.Subtotal+invoice.TaxAmount
+	}
+	if err := quick.Check(property, quickConfig); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QuickEntry is a time entry typed on one line, e.g. "2h ACME code review"
+type QuickEntry struct {
+	Duration    time.Duration
+	Client      *Client
+	Description string
+}
+
+// ParseQuickEntry reads a duration, a client, and a description from text,
+// in that order. The duration is written like 2h, 1h30m, 45m, 1.5h, or 1:30.
+// The client is the active client whose whole name comes next, ignoring
+// case, or else the only one whose name starts with the next word, so
+// "2h acme fix login" finds "Acme Corp".
+func ParseQuickEntry(text string, clients []*Client) (*QuickEntry, error) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("type how long, the client, and what you did, e.g. 2h Acme code review")
+	}
+
+	duration, err := parseQuickDuration(words[0])
+	if err != nil {
+		return nil, err
+	}
+
+	client, n, err := matchQuickClient(words[1:], clients)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := strings.Join(words[1+n:], " ")
+	if desc == "" {
+		return nil, fmt.Errorf("add what you worked on for %s after the client's name", client.Name)
+	}
+	return &QuickEntry{Duration: duration, Client: client, Description: desc}, nil
+}
+
+// parseQuickDuration reads a duration such as 2h, 1h30m, 1.5h, or 1:30
+func parseQuickDuration(s string) (time.Duration, error) {
+	d, err := ParseDuration(s)
+	if errors.Is(err, errDurationFormat) {
+		return 0, fmt.Errorf("start with how long you worked, e.g. 2h, 45m, or 1:30 (got %q)", s)
+	}
+	return d, err
+}
+
+var errDurationFormat = errors.New("invalid duration")
+
+// ParseDuration reads how long an entry lasted, written like 2h, 1h30m,
+// 45m, 1:30, or as hours alone such as 3.5 or 3,5. It is rounded to the
+// minute and must be between a minute and a day.
+func ParseDuration(s string) (time.Duration, error) {
+	// Durations have no thousands, so a comma can only be a decimal comma
+	text := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), ",", ".")
+
+	d, err := time.ParseDuration(text)
+	if hours, perr := strconv.ParseFloat(text, 64); perr == nil {
+		d, err = time.Duration(hours*float64(time.Hour)), nil
+	}
+	if hours, minutes, ok := strings.Cut(text, ":"); ok {
+		h, herr := strconv.Atoi(hours)
+		m, merr := strconv.Atoi(minutes)
+		d, err = time.Duration(h)*time.Hour+time.Duration(m)*time.Minute, nil
+		if herr != nil || merr != nil || m < 0 || m >= 60 {
+			err = errDurationFormat
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w %q (e.g. 2h, 45m, 1:30, or 3.5)", errDurationFormat, s)
+	}
+
+	if d < time.Minute {
+		return 0, fmt.Errorf("duration must be at least a minute")
+	}
+	if d > 24*time.Hour {
+		return 0, fmt.Errorf("duration cannot be more than a day")
+	}
+	return d.Round(time.Minute), nil
+}
+
+// matchQuickClient finds the client words start with and returns it with
+// the number of words its name took up
+func matchQuickClient(words []string, clients []*Client) (*Client, int, error) {
+	if len(words) == 0 {
+		return nil, 0, fmt.Errorf("add the client's name after the duration")
+	}
+
+	// The longest whole name wins, so "Acme Labs" beats "Acme"
+	var best *Client
+	bestLen := 0
+	for _, c := range clients {
+		if c.IsArchived {
+			continue
+		}
+		name := strings.Fields(c.Name)
+		if len(name) <= bestLen || len(name) > len(words) {
+			continue
+		}
+		if strings.EqualFold(strings.Join(name, " "), strings.Join(words[:len(name)], " ")) {
+			best, bestLen = c, len(name)
+		}
+	}
+	if best != nil {
+		return best, bestLen, nil
+	}
+
+	var matches []*Client
+	for _, c := range clients {
+		if !c.IsArchived && strings.HasPrefix(strings.ToLower(c.Name), strings.ToLower(words[0])) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, 0, fmt.Errorf("no active client matches %q", words[0])
+	case 1:
+		return matches[0], 1, nil
+	}
+	names := make([]string, len(matches))
+	for i, c := range matches {
+		names[i] = c.Name
+	}
+	return nil, 0, fmt.Errorf("%q could be %s; type more of the name", words[0], strings.Join(names, " or "))
+}
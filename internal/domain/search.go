@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// SearchKind is the type of record a search result points at
+type SearchKind string
+
+const (
+	SearchEntry  SearchKind = "entry"
+	SearchClient SearchKind = "client"
+)
+
+// Markers around the matched words in a search snippet
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// SearchResult is one entry or client matching a full-text search
+type SearchResult struct {
+	Kind       SearchKind
+	ID         int64
+	ClientID   int64 // the client itself for client results
+	ClientName string
+	Snippet    string     // matching text, matched words between MatchStart and MatchEnd
+	Date       *time.Time // entry start time; nil for clients
+}
+
+// HighlightSnippet replaces the match markers in a snippet with the given
+// strings, e.g. "[" and "]" for plain text or "" and "" to strip them
+func HighlightSnippet(snippet, start, end string) string {
+	return strings.NewReplacer(MatchStart, start, MatchEnd, end).Replace(snippet)
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Statement is a client's account for one month: what they owed when it
+// began, each invoice issued and paid during it, and what they owed when it
+// ended. Clients reconcile against it, so it lists invoices rather than work.
+type Statement struct {
+	Client         *Client
+	Start          time.Time // first day of the month
+	End            time.Time // first day of the next month
+	OpeningBalance Money     // balance carried forward
+	Lines          []*StatementLine
+	Invoiced       Money
+	Received       Money
+	ClosingBalance Money
+}
+
+// StatementLine is an invoice issued or a payment received
+type StatementLine struct {
+	Date    time.Time
+	Invoice *Invoice
+	Payment bool  // the invoice was paid rather than issued
+	Amount  Money // negative for payments
+	Balance Money // after this line
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeOffType is why a day is off
+type TimeOffType string
+
+const (
+	TimeOffVacation TimeOffType = "vacation"
+	TimeOffSick     TimeOffType = "sick"
+	TimeOffHoliday  TimeOffType = "holiday"
+)
+
+// ParseTimeOffType looks up a time off type by name
+func ParseTimeOffType(s string) (TimeOffType, error) {
+	switch t := TimeOffType(s); t {
+	case TimeOffVacation, TimeOffSick, TimeOffHoliday:
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown time off type %q (expected vacation, sick, or holiday)", s)
+}
+
+// TimeOff is a run of days not worked. Reports leave them out of
+// utilization and do not count them as short days.
+type TimeOff struct {
+	ID        int64
+	StartDate time.Time
+	EndDate   time.Time // last day off
+	Type      TimeOffType
+	Note      string
+	CreatedAt time.Time
+}
+
+// NewTimeOff creates time off from start to end, inclusive
+func NewTimeOff(start, end time.Time, t TimeOffType) *TimeOff {
+	return &TimeOff{
+		StartDate: RateDay(start),
+		EndDate:   RateDay(end),
+		Type:      t,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate returns an error if the time off is invalid
+func (t *TimeOff) Validate() error {
+	if t.StartDate.IsZero() || t.EndDate.IsZero() {
+		return errors.New("start and end dates are required")
+	}
+	if t.EndDate.Before(t.StartDate) {
+		return errors.New("end date cannot be before start date")
+	}
+	if _, err := ParseTimeOffType(string(t.Type)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Covers reports whether the day containing d is off
+func (t *TimeOff) Covers(d time.Time) bool {
+	day := RateDay(d)
+	return !day.Before(t.StartDate) && !day.After(t.EndDate)
+}
+
+// Days returns the number of days off, including weekends
+func (t *TimeOff) Days() int {
+	return int(t.EndDate.Sub(t.StartDate).Hours()/24+0.5) + 1
+}
+
+// TimeOffOn returns the time off covering the day containing d, or nil if
+// it was a working day
+func TimeOffOn(timeOff []*TimeOff, d time.Time) *TimeOff {
+	for _, t := range timeOff {
+		if t.Covers(d) {
+			return t
+		}
+	}
+	return nil
+}
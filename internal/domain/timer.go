@@ -1,6 +1,11 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type TimerState string
 
@@ -10,12 +15,24 @@ const (
 	TimerStatePaused  TimerState = "paused"
 )
 
+// Pause is a stretch of time a timer was paused, which its entries do not
+// bill
+type Pause struct {
+	Start time.Time
+	End   time.Time
+}
+
 type ActiveTimer struct {
 	ClientID           int64
 	Description        string
 	StartTime          time.Time
 	PausedAt           *time.Time
 	TotalPausedSeconds int64
+	Pauses             []Pause // pauses resumed so far, oldest first
+	Notes              string  // timestamped notes, one per line
+	IsBillable         bool
+	Location           string // where the work is done, carried onto the entry
+	Role               string // rate card role the entry is billed at; empty for the client's rate
 }
 
 // NewActiveTimer creates a new running timer
@@ -24,6 +41,7 @@ func NewActiveTimer(clientID int64, description string) *ActiveTimer {
 		ClientID:    clientID,
 		Description: description,
 		StartTime:   time.Now(),
+		IsBillable:  true,
 	}
 }
 
@@ -50,40 +68,172 @@ func (t *ActiveTimer) Elapsed() time.Duration {
 
 // Pause pauses the timer
 func (t *ActiveTimer) Pause() {
-	if t.PausedAt == nil {
-		now := time.Now()
-		t.PausedAt = &now
+	t.PauseAt(time.Now())
+}
+
+// PauseAt pauses the timer as of an earlier time, e.g. when the computer
+// went to sleep, so the time since then is not counted. Times before the
+// start, or before the last pause ended, pause it from then.
+func (t *ActiveTimer) PauseAt(at time.Time) {
+	if t.PausedAt != nil {
+		return
+	}
+	if at.Before(t.StartTime) {
+		at = t.StartTime
 	}
+	if n := len(t.Pauses); n > 0 && at.Before(t.Pauses[n-1].End) {
+		at = t.Pauses[n-1].End
+	}
+	t.PausedAt = &at
 }
 
 // Resume resumes a paused timer
 func (t *ActiveTimer) Resume() {
 	if t.PausedAt != nil {
-		pauseDuration := time.Since(*t.PausedAt)
-		t.TotalPausedSeconds += int64(pauseDuration.Seconds())
+		now := time.Now()
+		t.TotalPausedSeconds += int64(now.Sub(*t.PausedAt).Seconds())
+		t.Pauses = append(t.Pauses, Pause{Start: *t.PausedAt, End: now})
 		t.PausedAt = nil
 	}
 }
 
-// ToTimeEntry converts the timer to a time entry when stopped
+// AddNote appends a timestamped note to the timer
+func (t *ActiveTimer) AddNote(note string) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return
+	}
+	line := "[" + time.Now().Format("15:04") + "] " + note
+	if t.Notes == "" {
+		t.Notes = line
+	} else {
+		t.Notes += "\n" + line
+	}
+}
+
+// DescriptionWithNotes returns the description followed by the timestamped
+// notes, e.g. "Deploy; [10:05] fixed the auth bug", on one line so it reads
+// well on an invoice
+func (t *ActiveTimer) DescriptionWithNotes() string {
+	parts := make([]string, 0, 1+len(t.NoteLines()))
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		parts = append(parts, desc)
+	}
+	parts = append(parts, t.NoteLines()...)
+	return strings.Join(parts, "; ")
+}
+
+// SetStart moves the timer's start, e.g. back to when work really began.
+// The start must stay before now, or before the pause of a paused timer,
+// with some time still counted after the pauses so far.
+func (t *ActiveTimer) SetStart(start time.Time) error {
+	end := time.Now()
+	if t.PausedAt != nil {
+		end = *t.PausedAt
+	}
+	if !start.Before(end) {
+		if t.PausedAt != nil {
+			return errors.New("start must be before the timer was paused")
+		}
+		return errors.New("start must be in the past")
+	}
+	paused := time.Duration(t.TotalPausedSeconds) * time.Second
+	if end.Sub(start) <= paused {
+		return fmt.Errorf("start leaves no time outside the %s the timer was paused", paused)
+	}
+	t.StartTime = start
+	return nil
+}
+
+// ParseTimerStart reads a new start for a timer that started at current:
+// a shift such as "-20m" or "+5m", or a time such as "09:40" on the day the
+// timer started
+func ParseTimerStart(s string, current time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		shift, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid shift %q (e.g. -20m or +1h5m)", s)
+		}
+		return current.Add(shift), nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start %q (use a shift such as -20m, or HH:MM)", s)
+	}
+	return time.Date(current.Year(), current.Month(), current.Day(), t.Hour(), t.Minute(), 0, 0, current.Location()), nil
+}
+
+// NoteLines returns the timer notes as individual lines
+func (t *ActiveTimer) NoteLines() []string {
+	if t.Notes == "" {
+		return nil
+	}
+	return strings.Split(t.Notes, "\n")
+}
+
+// TimerStop is what a timer's entry is saved as when it is reviewed on
+// stopping, rather than taken as it ran
+type TimerStop struct {
+	End         time.Time // when work stopped, no later than the timer
+	Description string
+	IsBillable  bool
+}
+
+// Apply sets the end, description and billable flag of the entry a timer
+// was converted to. The end can be moved earlier to trim the entry but not
+// past when the timer stopped. Pauses before the new end stay left out.
+func (s TimerStop) Apply(e *TimeEntry) error {
+	if !s.End.After(e.StartTime) {
+		return errors.New("the entry must end after it started")
+	}
+	if e.EndTime != nil && s.End.After(*e.EndTime) {
+		return errors.New("the entry cannot end after the timer stopped")
+	}
+	trimmed := *e
+	trimmed.Stop(s.End)
+	if trimmed.Duration() <= 0 {
+		return fmt.Errorf("the entry must end after some time worked, not %s into a pause", s.End.Sub(e.StartTime).Round(time.Second))
+	}
+	e.Description = strings.TrimSpace(s.Description)
+	e.IsBillable = s.IsBillable
+	e.Stop(s.End)
+	return nil
+}
+
+// ToTimeEntry converts the timer to a time entry when stopped, with the
+// notes taken while it ran appended to the description. The entry spans the
+// whole time the timer ran and keeps its pauses, so they are not billed.
 func (t *ActiveTimer) ToTimeEntry(hourlyRate float64) *TimeEntry {
 	// If paused, finalize the pause duration
 	if t.PausedAt != nil {
 		t.Resume()
 	}
 
+	// A timer paused before pauses were recorded one by one only has the
+	// total; take what the pauses do not cover as paused from the start
+	pauses := t.Pauses
+	var recorded time.Duration
+	for _, p := range pauses {
+		recorded += p.End.Sub(p.Start)
+	}
+	if rest := time.Duration(t.TotalPausedSeconds)*time.Second - recorded; rest >= time.Second {
+		pauses = append([]Pause{{Start: t.StartTime, End: t.StartTime.Add(rest)}}, pauses...)
+	}
+
 	now := time.Now()
-	durationSecs := int64(t.Elapsed().Seconds())
-
-	return &TimeEntry{
-		ClientID:        t.ClientID,
-		Description:     t.Description,
-		StartTime:       t.StartTime,
-		EndTime:         &now,
-		DurationSeconds: &durationSecs,
-		HourlyRate:      hourlyRate,
-		IsBillable:      true,
-		CreatedAt:       t.StartTime,
-		UpdatedAt:       now,
+	e := &TimeEntry{
+		ClientID:    t.ClientID,
+		Description: t.DescriptionWithNotes(),
+		StartTime:   t.StartTime,
+		Pauses:      pauses,
+		HourlyRate:  hourlyRate,
+		IsBillable:  t.IsBillable,
+		Location:    t.Location,
+		Role:        t.Role,
+		CreatedAt:   t.StartTime,
 	}
+	e.Stop(now)
+	e.UpdatedAt = now
+	return e
 }
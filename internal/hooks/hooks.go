@@ -0,0 +1,112 @@
+// Package hooks runs the user's own executables when things happen in
+// timesink, so they can extend it without waiting for built-in integrations.
+//
+// A hook is an executable file in the hooks directory named after its event,
+// e.g. hooks/timer-stopped. It receives the event as JSON on stdin.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Event names a hook and the file it runs from
+type Event string
+
+const (
+	TimerStopped     Event = "timer-stopped"
+	EntryCreated     Event = "entry-created"
+	InvoiceFinalized Event = "invoice-finalized"
+)
+
+// maxLoggedOutput caps how much of a failed hook's output goes to the log
+const maxLoggedOutput = 1024
+
+// Runner runs hooks from a directory
+type Runner struct {
+	dir     string
+	timeout time.Duration
+	profile string
+	log     *slog.Logger
+}
+
+// NewRunner creates a runner for the hooks in dir. Hooks still running after
+// timeout are killed.
+func NewRunner(dir string, timeout time.Duration, profile string, log *slog.Logger) *Runner {
+	return &Runner{dir: dir, timeout: timeout, profile: profile, log: log}
+}
+
+// message is what a hook reads from stdin
+type message struct {
+	Event   Event     `json:"event"`
+	Profile string    `json:"profile"`
+	Time    time.Time `json:"time"`
+	Data    any       `json:"data"`
+}
+
+// Fire runs the hook for event, if there is one, and waits for it to finish.
+// Failures are logged rather than returned: the change that triggered the
+// hook has already been saved, and a broken hook must not look like it wasn't.
+func (r *Runner) Fire(ctx context.Context, event Event, data any) {
+	path := filepath.Join(r.dir, string(event))
+	info, err := os.Stat(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			r.log.Warn("hook unreadable", "event", string(event), "path", path, "error", err)
+		}
+		return
+	}
+	if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		r.log.Warn("hook is not executable", "event", string(event), "path", path)
+		return
+	}
+
+	input, err := json.Marshal(message{Event: event, Profile: r.profile, Time: time.Now(), Data: data})
+	if err != nil {
+		r.log.Error("hook input failed", "event", string(event), "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.Env = append(os.Environ(), "TIMESINK_PROFILE="+r.profile)
+	// Don't wait on children the hook left holding its output open
+	cmd.WaitDelay = time.Second
+
+	started := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(started).Round(time.Millisecond).String()
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		r.log.Warn("hook timed out", "event", string(event), "path", path, "timeout", r.timeout.String(),
+			"output", truncateOutput(output.Bytes()))
+	case err != nil:
+		r.log.Warn("hook failed", "event", string(event), "path", path, "error", err, "duration", elapsed,
+			"output", truncateOutput(output.Bytes()))
+	default:
+		r.log.Debug("hook ran", "event", string(event), "path", path, "duration", elapsed)
+	}
+}
+
+// truncateOutput trims a hook's output for the log
+func truncateOutput(b []byte) string {
+	b = bytes.TrimSpace(b)
+	if len(b) > maxLoggedOutput {
+		return string(b[:maxLoggedOutput]) + "..."
+	}
+	return string(b)
+}
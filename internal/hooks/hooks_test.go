@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHook writes an executable shell script for event into dir
+func writeHook(t *testing.T, dir string, event Event, script string) {
+	t.Helper()
+	path := filepath.Join(dir, string(event))
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write hook: %v", err)
+	}
+}
+
+func newTestRunner(t *testing.T, timeout time.Duration) (*Runner, string, *bytes.Buffer) {
+	t.Helper()
+	dir := t.TempDir()
+	var logs bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return NewRunner(dir, timeout, "work", log), dir, &logs
+}
+
+func TestFire_PassesEventJSONOnStdin(t *testing.T) {
+	runner, dir, _ := newTestRunner(t, 5*time.Second)
+	out := filepath.Join(dir, "received.json")
+	writeHook(t, dir, EntryCreated, `cat > "`+out+`"; echo "$TIMESINK_PROFILE" > "`+out+`.profile"`)
+
+	runner.Fire(context.Background(), EntryCreated, Entry{ID: 7, ClientID: 2, Description: "design"})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	var msg struct {
+		Event   Event  `json:"event"`
+		Profile string `json:"profile"`
+		Data    Entry  `json:"data"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("hook received invalid JSON %q: %v", data, err)
+	}
+	if msg.Event != EntryCreated || msg.Profile != "work" || msg.Data.ID != 7 || msg.Data.Description != "design" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+
+	profile, _ := os.ReadFile(out + ".profile")
+	if strings.TrimSpace(string(profile)) != "work" {
+		t.Fatalf("expected TIMESINK_PROFILE=work, got %q", profile)
+	}
+}
+
+func TestFire_LogsFailuresAndTimeouts(t *testing.T) {
+	runner, dir, logs := newTestRunner(t, 200*time.Millisecond)
+	writeHook(t, dir, TimerStopped, `echo "no network" >&2; exit 3`)
+	writeHook(t, dir, InvoiceFinalized, `sleep 5`)
+	if err := os.WriteFile(filepath.Join(dir, string(EntryCreated)), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runner.Fire(context.Background(), TimerStopped, nil)
+
+	started := time.Now()
+	runner.Fire(context.Background(), InvoiceFinalized, nil)
+	if elapsed := time.Since(started); elapsed > 3*time.Second {
+		t.Fatalf("expected the hook to be killed at its timeout, took %s", elapsed)
+	}
+
+	runner.Fire(context.Background(), EntryCreated, nil)
+
+	for _, want := range []string{
+		`msg="hook failed" event=timer-stopped`,
+		`output="no network"`,
+		`msg="hook timed out" event=invoice-finalized`,
+		`msg="hook is not executable" event=entry-created`,
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("expected log to contain %s, got:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestFire_IgnoresMissingHooks(t *testing.T) {
+	runner, _, logs := newTestRunner(t, time.Second)
+
+	runner.Fire(context.Background(), TimerStopped, nil)
+
+	if logs.Len() != 0 {
+		t.Fatalf("expected nothing logged for a missing hook, got %s", logs.String())
+	}
+}
@@ -0,0 +1,183 @@
+package hooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+	"github.com/andy/timesink/internal/service"
+)
+
+// Entry is an entry as hooks see it
+type Entry struct {
+	ID              int64      `json:"id"`
+	ClientID        int64      `json:"client_id"`
+	Description     string     `json:"description"`
+	Notes           string     `json:"notes,omitempty"`
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	HourlyRate      float64    `json:"hourly_rate"`
+	RateReason      string     `json:"rate_reason,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	Amount          float64    `json:"amount"`
+	Billable        bool       `json:"billable"`
+}
+
+func newEntry(e *domain.TimeEntry) Entry {
+	return Entry{
+		ID:              e.ID,
+		ClientID:        e.ClientID,
+		Description:     e.Description,
+		Notes:           e.Notes,
+		Start:           e.StartTime,
+		End:             e.EndTime,
+		DurationSeconds: int64(e.Duration().Seconds()),
+		HourlyRate:      e.HourlyRate,
+		RateReason:      e.RateReason,
+		Role:            e.Role,
+		Amount:          e.Amount().Float(),
+		Billable:        e.IsBillable,
+	}
+}
+
+// Invoice is an invoice as hooks see it
+type Invoice struct {
+	ID          int64      `json:"id"`
+	Number      string     `json:"number"`
+	ClientID    int64      `json:"client_id"`
+	PeriodStart time.Time  `json:"period_start"`
+	PeriodEnd   time.Time  `json:"period_end"`
+	Subtotal    float64    `json:"subtotal"`
+	TaxAmount   float64    `json:"tax_amount"`
+	Total       float64    `json:"total"`
+	Status      string     `json:"status"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+}
+
+func newInvoice(i *domain.Invoice) Invoice {
+	return Invoice{
+		ID:          i.ID,
+		Number:      i.InvoiceNumber,
+		ClientID:    i.ClientID,
+		PeriodStart: i.PeriodStart,
+		PeriodEnd:   i.PeriodEnd,
+		Subtotal:    i.Subtotal.Float(),
+		TaxAmount:   i.TaxAmount.Float(),
+		Total:       i.Total.Float(),
+		Status:      string(i.Status),
+		DueDate:     i.DueDate,
+	}
+}
+
+// hookedEntryRepo fires entry-created for entries added from the CLI, the
+// TUI and splits. Stopped timers save their entries in a transaction, outside
+// this repo, so hookedTimerService fires it for them.
+type hookedEntryRepo struct {
+	repository.TimeEntryRepository
+	hooks *Runner
+}
+
+// WrapEntryRepo wraps repo so that new entries fire entry-created
+func WrapEntryRepo(repo repository.TimeEntryRepository, hooks *Runner) repository.TimeEntryRepository {
+	return &hookedEntryRepo{TimeEntryRepository: repo, hooks: hooks}
+}
+
+func (r *hookedEntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error {
+	if err := r.TimeEntryRepository.Create(ctx, entry); err != nil {
+		return err
+	}
+	r.hooks.Fire(ctx, EntryCreated, newEntry(entry))
+	return nil
+}
+
+func (r *hookedEntryRepo) Split(ctx context.Context, id int64, at time.Time, reason string) (*domain.TimeEntry, error) {
+	second, err := r.TimeEntryRepository.Split(ctx, id, at, reason)
+	if err != nil {
+		return nil, err
+	}
+	r.hooks.Fire(ctx, EntryCreated, newEntry(second))
+	return second, nil
+}
+
+type hookedTimerService struct {
+	service.TimerService
+	hooks *Runner
+}
+
+// WrapTimerService wraps svc so that stopping the timer fires entry-created
+// and timer-stopped with each entry it saved, once they have committed
+func WrapTimerService(svc service.TimerService, hooks *Runner) service.TimerService {
+	return &hookedTimerService{TimerService: svc, hooks: hooks}
+}
+
+func (s *hookedTimerService) Stop(ctx context.Context) ([]*domain.TimeEntry, error) {
+	entries, err := s.TimerService.Stop(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.fireStopped(ctx, entries)
+	return entries, nil
+}
+
+func (s *hookedTimerService) StopEdited(ctx context.Context, edit domain.TimerStop) ([]*domain.TimeEntry, error) {
+	entries, err := s.TimerService.StopEdited(ctx, edit)
+	if err != nil {
+		return nil, err
+	}
+	s.fireStopped(ctx, entries)
+	return entries, nil
+}
+
+func (s *hookedTimerService) fireStopped(ctx context.Context, entries []*domain.TimeEntry) {
+	for _, entry := range entries {
+		s.hooks.Fire(ctx, EntryCreated, newEntry(entry))
+		s.hooks.Fire(ctx, TimerStopped, newEntry(entry))
+	}
+}
+
+type hookedInvoiceService struct {
+	service.InvoiceService
+	invoices repository.InvoiceRepository
+	hooks    *Runner
+}
+
+// WrapInvoiceService wraps svc so that finalizing or generating an invoice
+// fires invoice-finalized. Finalize reads the invoice back from invoices once
+// it has committed.
+func WrapInvoiceService(svc service.InvoiceService, invoices repository.InvoiceRepository, hooks *Runner) service.InvoiceService {
+	return &hookedInvoiceService{InvoiceService: svc, invoices: invoices, hooks: hooks}
+}
+
+// Generate creates and finalizes an invoice in one step, as the TUI does
+func (s *hookedInvoiceService) Generate(
+	ctx context.Context,
+	clientID int64,
+	periodStart, periodEnd time.Time,
+	prefix string,
+	taxes []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+	entryIDs []int64,
+	defaultDueDays int,
+) (*domain.Invoice, error) {
+	invoice, err := s.InvoiceService.Generate(ctx, clientID, periodStart, periodEnd, prefix, taxes, footer, entryIDs, defaultDueDays)
+	if err != nil {
+		return nil, err
+	}
+	s.hooks.Fire(ctx, InvoiceFinalized, newInvoice(invoice))
+	return invoice, nil
+}
+
+func (s *hookedInvoiceService) Finalize(ctx context.Context, invoiceID int64, defaultDueDays int) error {
+	if err := s.InvoiceService.Finalize(ctx, invoiceID, defaultDueDays); err != nil {
+		return err
+	}
+	invoice, err := s.invoices.GetByID(ctx, invoiceID)
+	if err != nil {
+		s.hooks.log.Warn("hook skipped", "event", string(InvoiceFinalized), "invoice_id", invoiceID, "error", err)
+		return nil
+	}
+	s.hooks.Fire(ctx, InvoiceFinalized, newInvoice(invoice))
+	return nil
+}
@@ -0,0 +1,194 @@
+package locale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+)
+
+// Locale controls how money, numbers, and dates are displayed
+type Locale struct {
+	CurrencySymbol string
+	SymbolAfter    bool   // "1.234,56 €" rather than "€1,234.56"
+	SymbolSpace    bool   // space between symbol and amount
+	DecimalSep     string // "." or ","
+	ThousandsSep   string // ",", ".", " " or ""
+	ShortDate      string // Go layout, e.g. "Jan 2"
+	LongDate       string // Go layout, e.g. "Jan 2, 2006"
+}
+
+// presets are keyed by the locale name used in config.yaml
+var presets = map[string]Locale{
+	"en-US": {CurrencySymbol: "$", DecimalSep: ".", ThousandsSep: ",", ShortDate: "Jan 2", LongDate: "Jan 2, 2006"},
+	"en-GB": {CurrencySymbol: "£", DecimalSep: ".", ThousandsSep: ",", ShortDate: "2 Jan", LongDate: "2 Jan 2006"},
+	"en-IE": {CurrencySymbol: "€", DecimalSep: ".", ThousandsSep: ",", ShortDate: "2 Jan", LongDate: "2 Jan 2006"},
+	"de-DE": {CurrencySymbol: "€", SymbolAfter: true, SymbolSpace: true, DecimalSep: ",", ThousandsSep: ".", ShortDate: "2. Jan", LongDate: "2. Jan 2006"},
+	"fr-FR": {CurrencySymbol: "€", SymbolAfter: true, SymbolSpace: true, DecimalSep: ",", ThousandsSep: " ", ShortDate: "2 Jan", LongDate: "2 Jan 2006"},
+	"es-ES": {CurrencySymbol: "€", SymbolAfter: true, SymbolSpace: true, DecimalSep: ",", ThousandsSep: ".", ShortDate: "2 Jan", LongDate: "2 Jan 2006"},
+	"nl-NL": {CurrencySymbol: "€", SymbolSpace: true, DecimalSep: ",", ThousandsSep: ".", ShortDate: "2 Jan", LongDate: "2 Jan 2006"},
+}
+
+// DefaultName is the preset used when none is configured
+const DefaultName = "en-US"
+
+// Default returns the en-US locale
+func Default() Locale {
+	return presets[DefaultName]
+}
+
+// Names returns the supported preset names
+func Names() []string {
+	return []string{"en-US", "en-GB", "en-IE", "de-DE", "fr-FR", "es-ES", "nl-NL"}
+}
+
+// FromConfig builds a locale from a preset plus any explicit overrides.
+// Unknown presets fall back to en-US.
+func FromConfig(cfg config.LocaleConfig) Locale {
+	l, ok := presets[cfg.Name]
+	if !ok {
+		l = Default()
+	}
+
+	if cfg.CurrencySymbol != "" {
+		l.CurrencySymbol = cfg.CurrencySymbol
+	}
+	if cfg.DecimalSeparator != "" {
+		l.DecimalSep = cfg.DecimalSeparator
+	}
+	if cfg.ThousandsSeparator != "" {
+		l.ThousandsSep = cfg.ThousandsSeparator
+	}
+	if cfg.ShortDate != "" {
+		l.ShortDate = cfg.ShortDate
+	}
+	if cfg.LongDate != "" {
+		l.LongDate = cfg.LongDate
+	}
+
+	return l
+}
+
+// Number formats a value with the given number of decimals and grouping
+func (l Locale) Number(value float64, decimals int) string {
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	s := strconv.FormatFloat(value, 'f', decimals, 64)
+	intPart, decPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, decPart = s[:dot], s[dot+1:]
+	}
+
+	// Group the integer part in threes
+	var b strings.Builder
+	for i, c := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.ThousandsSep)
+		}
+		b.WriteRune(c)
+	}
+	if decPart != "" {
+		b.WriteString(l.DecimalSep)
+		b.WriteString(decPart)
+	}
+
+	result := b.String()
+	if negative && math.Round(value*math.Pow10(decimals)) != 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// ParseNumber reads a number typed with a comma or a dot as the decimal
+// separator and optional thousands separators, so "1,5", "150,00",
+// "1.234,50" and "1,234.50" all parse. A lone separator followed by exactly
+// three digits, as in "1,500", is taken as the locale's thousands separator
+// when it is one, and as the decimal separator otherwise.
+func (l Locale) ParseNumber(s string) (float64, error) {
+	// Spaces and apostrophes only ever group thousands
+	text := strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "", "'", "").Replace(strings.TrimSpace(s))
+
+	decimal := -1
+	comma, dot := strings.LastIndexByte(text, ','), strings.LastIndexByte(text, '.')
+	switch {
+	case comma >= 0 && dot >= 0:
+		decimal = max(comma, dot)
+	case comma >= 0 || dot >= 0:
+		sep, i := ",", comma
+		if dot >= 0 {
+			sep, i = ".", dot
+		}
+		if strings.Count(text, sep) == 1 && (len(text)-i-1 != 3 || l.ThousandsSep != sep) {
+			decimal = i
+		}
+	}
+
+	intPart, fracPart := text, ""
+	if decimal >= 0 {
+		intPart, fracPart = text[:decimal], text[decimal+1:]
+	}
+	if strings.Contains(intPart, ",") && strings.Contains(intPart, ".") {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	// Whatever separators are left group the integer part in threes
+	groups := strings.FieldsFunc(intPart, func(r rune) bool { return r == ',' || r == '.' })
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+	}
+
+	normalized := strings.Join(groups, "")
+	if decimal >= 0 {
+		normalized += "." + fracPart
+	}
+	value, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return value, nil
+}
+
+// Money formats an amount with two decimals and the currency symbol
+func (l Locale) Money(amount float64) string {
+	return l.withSymbol(amount, 2)
+}
+
+// MoneyWhole formats an amount rounded to whole currency units
+func (l Locale) MoneyWhole(amount float64) string {
+	return l.withSymbol(amount, 0)
+}
+
+func (l Locale) withSymbol(amount float64, decimals int) string {
+	num := l.Number(amount, decimals)
+	sign := ""
+	if strings.HasPrefix(num, "-") {
+		sign, num = "-", num[1:]
+	}
+
+	space := ""
+	if l.SymbolSpace {
+		space = " "
+	}
+	if l.SymbolAfter {
+		return sign + num + space + l.CurrencySymbol
+	}
+	return sign + l.CurrencySymbol + space + num
+}
+
+// FormatShortDate formats a date without the year
+func (l Locale) FormatShortDate(t time.Time) string {
+	return t.Format(l.ShortDate)
+}
+
+// FormatLongDate formats a date including the year
+func (l Locale) FormatLongDate(t time.Time) string {
+	return t.Format(l.LongDate)
+}
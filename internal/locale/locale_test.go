@@ -0,0 +1,107 @@
+package locale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+)
+
+func TestMoney_Presets(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		want   string
+	}{
+		{"en-US", 1234567.891, "$1,234,567.89"},
+		{"en-US", -42.5, "-$42.50"},
+		{"en-GB", 999.999, "£1,000.00"},
+		{"de-DE", 1234.5, "1.234,50 €"},
+		{"fr-FR", 1234.5, "1 234,50 €"},
+		{"nl-NL", -1234.5, "-€ 1.234,50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := FromConfig(config.LocaleConfig{Name: tt.name})
+			if got := l.Money(tt.amount); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMoney_NegativeZero(t *testing.T) {
+	if got := Default().Money(-0.001); got != "$0.00" {
+		t.Fatalf("expected $0.00, got %q", got)
+	}
+}
+
+func TestFromConfig_Overrides(t *testing.T) {
+	l := FromConfig(config.LocaleConfig{
+		Name:           "de-DE",
+		CurrencySymbol: "CHF",
+		ShortDate:      "02.01.",
+	})
+
+	if got := l.MoneyWhole(150); got != "150 CHF" {
+		t.Fatalf("expected 150 CHF, got %q", got)
+	}
+
+	d := time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)
+	if got := l.FormatShortDate(d); got != "05.03." {
+		t.Fatalf("expected 05.03., got %q", got)
+	}
+}
+
+func TestFromConfig_UnknownFallsBack(t *testing.T) {
+	l := FromConfig(config.LocaleConfig{Name: "xx-XX"})
+	if got := l.Money(1000); got != "$1,000.00" {
+		t.Fatalf("expected en-US formatting, got %q", got)
+	}
+}
+
+func TestParseNumber_CommaOrDotDecimals(t *testing.T) {
+	tests := []struct {
+		locale string
+		input  string
+		want   float64
+	}{
+		{"en-US", "1.5", 1.5},
+		{"en-US", "1,5", 1.5},
+		{"en-US", "150,00", 150},
+		{"en-US", "1,234.50", 1234.5},
+		{"en-US", "1.234,50", 1234.5},
+		{"en-US", "1,234,567", 1234567},
+		{"en-US", " -2,25 ", -2.25},
+		{"en-US", ",5", 0.5},
+		{"fr-FR", "1 234,5", 1234.5},
+		{"en-US", "1'234.5", 1234.5},
+
+		// A lone separator before three digits follows the locale
+		{"en-US", "1,500", 1500},
+		{"en-US", "1.500", 1.5},
+		{"de-DE", "1.500", 1500},
+		{"de-DE", "1,500", 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.input, func(t *testing.T) {
+			got, err := FromConfig(config.LocaleConfig{Name: tt.locale}).ParseNumber(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseNumber_RejectsMalformed(t *testing.T) {
+	for _, input := range []string{"", "abc", "1,2,3", "1.2,3", "1.234,567.8", "12,34,567.8", "1,5h"} {
+		if got, err := Default().ParseNumber(input); err == nil {
+			t.Errorf("%q: expected an error, got %v", input, got)
+		}
+	}
+}
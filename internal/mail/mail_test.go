@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage_RoundTrips(t *testing.T) {
+	from := &netmail.Address{Name: "Jane Doe", Address: "jane@example.test"}
+	to := &netmail.Address{Address: "me@example.test"}
+	body := "Client    This wk\nAcme Ünited   12h\n"
+	date := time.Date(2026, time.March, 16, 7, 0, 0, 0, time.UTC)
+
+	raw, err := message(from, to, "Week of Mar 9: 12h logged — €1.000", body, date)
+	if err != nil {
+		t.Fatalf("failed to build message: %v", err)
+	}
+	msg, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("message does not parse: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Week of Mar 9: 12h logged — €1.000" {
+		t.Errorf("Subject = %q (%v)", subject, err)
+	}
+	if got := msg.Header.Get("To"); got != "<me@example.test>" {
+		t.Errorf("To = %q", got)
+	}
+	got, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if strings.ReplaceAll(string(got), "\r\n", "\n") != body {
+		t.Errorf("body = %q, want %q", got, body)
+	}
+}
+
+func TestSend_RequiresServer(t *testing.T) {
+	err := NewSender("", 587, "", "", "me@example.test").Send("me@example.test", "hi", "body")
+	if err == nil || !strings.Contains(err.Error(), "smtp.host") {
+		t.Fatalf("expected missing smtp.host error, got %v", err)
+	}
+}
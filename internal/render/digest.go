@@ -0,0 +1,110 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/mattn/go-runewidth"
+)
+
+// Digest column widths share the invoice layout: a client or invoice
+// number and name, then hours for this week, last week and the change
+const (
+	digestClientCol  = invoiceWidth - 3*hoursCol - 3
+	digestInvoiceCol = invoiceWidth - dateCol - amountCol - 2
+)
+
+// DigestSubject returns the subject line of a digest e-mail
+func DigestSubject(d *domain.Digest, opts Options) string {
+	subject := fmt.Sprintf("Week of %s: %s logged", opts.Locale.FormatShortDate(d.Start), hours(opts, d.Hours))
+	if d.Outstanding > 0 {
+		subject += fmt.Sprintf(", %s awaiting payment", opts.Locale.Money(d.Outstanding.Float()))
+	}
+	return subject
+}
+
+// Digest writes the weekly digest as plain text: hours per client against
+// the week before, time not yet invoiced, and invoices awaiting payment,
+// earliest due first
+func Digest(w io.Writer, d *domain.Digest, opts Options) error {
+	var b strings.Builder
+
+	sep := strings.Repeat("=", invoiceWidth)
+	line := strings.Repeat("-", invoiceWidth)
+	last := d.End.AddDate(0, 0, -1)
+
+	b.WriteString("WEEKLY DIGEST\n")
+	b.WriteString(sep + "\n")
+	fmt.Fprintf(&b, "Week:       %s - %s\n", opts.Locale.FormatLongDate(d.Start), opts.Locale.FormatLongDate(last))
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(digestRow("Client", "This wk", "Last wk", "Change"))
+	b.WriteString(line + "\n")
+	if len(d.Clients) == 0 {
+		b.WriteString("No time logged either week\n")
+	}
+	for _, c := range d.Clients {
+		b.WriteString(digestRow(c.Client.Name, hours(opts, c.Hours), hours(opts, c.PriorHours), change(opts, c.Change())))
+	}
+	b.WriteString(line + "\n")
+	b.WriteString(digestRow("Total", hours(opts, d.Hours), hours(opts, d.PriorHours), change(opts, d.Hours-d.PriorHours)))
+
+	b.WriteString("\n")
+	b.WriteString(total("Unbilled time", opts.Locale.Money(d.Unbilled.Float())))
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(digestInvoiceRow("Invoice", "Awaiting payment", "Amount"))
+	b.WriteString(line + "\n")
+	if len(d.AwaitingPayment) == 0 {
+		b.WriteString("Nothing awaiting payment\n")
+	}
+	for _, inv := range d.AwaitingPayment {
+		name := ""
+		if inv.Invoice.Client != nil {
+			name = inv.Invoice.Client.Name
+		}
+		b.WriteString(digestInvoiceRow(inv.Invoice.InvoiceNumber, name, opts.Locale.Money(inv.Invoice.Total.Float())))
+		due := "due " + opts.Locale.FormatShortDate(inv.Due)
+		switch {
+		case inv.DaysPastDue == 1:
+			due += ", 1 day overdue"
+		case inv.DaysPastDue > 1:
+			due += fmt.Sprintf(", %d days overdue", inv.DaysPastDue)
+		}
+		b.WriteString(strings.TrimRight(digestInvoiceRow("", due, ""), " \n") + "\n")
+	}
+	b.WriteString(line + "\n")
+	b.WriteString(total("Outstanding", opts.Locale.Money(d.Outstanding.Float())))
+	b.WriteString(sep + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// change formats a difference in hours with its sign
+func change(opts Options, h float64) string {
+	if hours(opts, h) == hours(opts, 0) {
+		return "-"
+	}
+	if h > 0 {
+		return "+" + hours(opts, h)
+	}
+	return hours(opts, h)
+}
+
+// digestRow lays out one line of the digest's hours table
+func digestRow(name, this, last, change string) string {
+	return padRight(runewidth.Truncate(name, digestClientCol, "…"), digestClientCol) + " " +
+		padLeft(this, hoursCol) + " " +
+		padLeft(last, hoursCol) + " " +
+		padLeft(change, hoursCol) + "\n"
+}
+
+// digestInvoiceRow lays out one line of the digest's invoice table
+func digestInvoiceRow(number, desc, amount string) string {
+	return padRight(number, dateCol) + " " +
+		padRight(runewidth.Truncate(desc, digestInvoiceCol, "…"), digestInvoiceCol) + " " +
+		padLeft(amount, amountCol) + "\n"
+}
@@ -0,0 +1,42 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestDigest_Golden(t *testing.T) {
+	start := time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)
+	acme := &domain.Client{ID: 1, Name: "Acme Corp"}
+	globex := &domain.Client{ID: 2, Name: "Globex"}
+
+	d := &domain.Digest{
+		Start: start,
+		End:   start.AddDate(0, 0, 7),
+		Clients: []*domain.DigestClient{
+			{Client: acme, Hours: 12.5, PriorHours: 10},
+			{Client: globex, Hours: 3, PriorHours: 6.25},
+		},
+		Hours:      15.5,
+		PriorHours: 16.25,
+		Unbilled:   155000,
+		AwaitingPayment: []*domain.DigestInvoice{
+			{Invoice: &domain.Invoice{InvoiceNumber: "INV-2026-004", Client: globex, Total: 62500}, Due: time.Date(2026, time.March, 11, 0, 0, 0, 0, time.UTC), DaysPastDue: 5},
+			{Invoice: &domain.Invoice{InvoiceNumber: "INV-2026-007", Client: acme, Total: 121781}, Due: time.Date(2026, time.April, 2, 0, 0, 0, 0, time.UTC)},
+		},
+		Outstanding: 184281,
+	}
+
+	var b strings.Builder
+	if err := Digest(&b, d, fixtureOptions()); err != nil {
+		t.Fatalf("failed to render digest: %v", err)
+	}
+	assertGolden(t, "digest", b.String())
+
+	if got, want := DigestSubject(d, fixtureOptions()), "Week of Mar 9: 15h 30m logged, $1,842.81 awaiting payment"; got != want {
+		t.Errorf("DigestSubject() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// Estimate column widths share the invoice layout; the description takes
+// the invoice's date column as well
+const (
+	estimateDescCol = dateCol + 1 + descCol
+)
+
+// Estimate writes a formatted text estimate in the same layout as an
+// invoice. Projected items show their hours; fixed-price items only an
+// amount. opts.Date is ignored in favour of the estimate's issue date.
+func Estimate(w io.Writer, est *domain.Estimate, items []*domain.EstimateLineItem, opts Options) error {
+	var b strings.Builder
+
+	sep := strings.Repeat("=", invoiceWidth)
+	line := strings.Repeat("-", invoiceWidth)
+
+	b.WriteString("ESTIMATE\n")
+	b.WriteString(sep + "\n")
+	fmt.Fprintf(&b, "Estimate #: %s\n", est.EstimateNumber)
+	fmt.Fprintf(&b, "Date:       %s\n", opts.Locale.FormatLongDate(est.IssueDate))
+	if est.ValidUntil != nil {
+		fmt.Fprintf(&b, "Expires:    %s\n", opts.Locale.FormatLongDate(*est.ValidUntil))
+	}
+
+	from := opts.From
+	if from.Name != "" || from.Email != "" {
+		b.WriteString("\nFrom:\n")
+		for _, field := range []string{from.Name, from.Email, from.Address, from.Phone} {
+			if field != "" {
+				fmt.Fprintf(&b, "  %s\n", field)
+			}
+		}
+	}
+
+	b.WriteString("\nPrepared For:\n")
+	if est.Client != nil {
+		fmt.Fprintf(&b, "  %s\n", est.Client.Name)
+		if est.Client.Email != "" {
+			fmt.Fprintf(&b, "  %s\n", est.Client.Email)
+		}
+	}
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(estimateRow("Description", "Hours", "Amount"))
+	b.WriteString(line + "\n")
+
+	for _, item := range items {
+		desc := wrap(item.Description, estimateDescCol)
+		h := ""
+		if !item.IsFixed() {
+			h = hours(opts, item.Hours)
+		}
+		b.WriteString(estimateRow(desc[0], h, opts.Locale.Money(item.Amount.Float())))
+		for _, cont := range desc[1:] {
+			b.WriteString(strings.TrimRight(estimateRow(cont, "", ""), " \n") + "\n")
+		}
+	}
+
+	b.WriteString(line + "\n")
+	b.WriteString(total("TOTAL", opts.Locale.Money(est.Total.Float())))
+	b.WriteString("\nAmounts exclude tax, which is added on the invoice.\n")
+
+	writeBlock(&b, "Notes", est.Notes)
+	b.WriteString(sep + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// estimateRow lays out one line of the estimate's line item table
+func estimateRow(desc, hours, amount string) string {
+	return padRight(desc, estimateDescCol) + " " +
+		padLeft(hours, hoursCol) + " " +
+		padLeft(amount, amountCol) + "\n"
+}
@@ -0,0 +1,30 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestEstimate_Golden(t *testing.T) {
+	issued := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	est := domain.NewEstimate("EST-2026-003", 1, issued)
+	est.Client = &domain.Client{ID: 1, Name: "Acme Corp", Email: "ap@acme.test"}
+	validUntil := issued.AddDate(0, 0, 30)
+	est.ValidUntil = &validUntil
+	est.Notes = "Includes two rounds of revisions."
+	est.LineItems = []*domain.EstimateLineItem{
+		domain.NewProjectedLineItem("Discovery workshop and requirements write-up", 6, 150),
+		domain.NewProjectedLineItem("Design", 12.5, 150),
+		domain.NewFixedLineItem("Hosting setup", 40000),
+	}
+	est.CalculateTotal()
+
+	var b strings.Builder
+	if err := Estimate(&b, est, est.LineItems, fixtureOptions()); err != nil {
+		t.Fatalf("failed to render estimate: %v", err)
+	}
+	assertGolden(t, "estimate", b.String())
+}
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// Exporter renders an invoice in one file format. The CLI and TUI write
+// invoices only through exporters, so a format registered here is available
+// to both.
+type Exporter interface {
+	// Name selects the format on the command line, e.g. "html"
+	Name() string
+	// Extensions lists the file extensions the format is chosen for, the
+	// first being the one given to new files, e.g. ".html"
+	Extensions() []string
+	// Render returns the invoice document. The sender's details are in
+	// opts.From.
+	Render(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) ([]byte, error)
+}
+
+// DefaultExporter names the format used when none is chosen
+const DefaultExporter = "txt"
+
+var exporters = make(map[string]Exporter)
+
+// Register makes an exporter available by name and extension. It panics if
+// the name or an extension is already taken, since that can only be a
+// programming error.
+func Register(e Exporter) {
+	name := e.Name()
+	if _, ok := exporters[name]; ok {
+		panic(fmt.Sprintf("render: exporter %q registered twice", name))
+	}
+	for _, ext := range e.Extensions() {
+		if other, ok := ExporterFor("file" + ext); ok {
+			panic(fmt.Sprintf("render: extension %s of exporter %q already belongs to %q", ext, name, other.Name()))
+		}
+	}
+	exporters[name] = e
+}
+
+// Exporters returns every registered exporter, sorted by name
+func Exporters() []Exporter {
+	list := make([]Exporter, 0, len(exporters))
+	for _, e := range exporters {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+// ExporterNames returns the names of every registered exporter, sorted
+func ExporterNames() []string {
+	var names []string
+	for _, e := range Exporters() {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+// ExporterNamed looks up an exporter by name
+func ExporterNamed(name string) (Exporter, error) {
+	if e, ok := exporters[name]; ok {
+		return e, nil
+	}
+	return nil, fmt.Errorf("unknown invoice format %q (expected %s)", name, strings.Join(ExporterNames(), ", "))
+}
+
+// ExporterFor returns the exporter for a file name's extension
+func ExporterFor(path string) (Exporter, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return nil, false
+	}
+	for _, e := range exporters {
+		for _, candidate := range e.Extensions() {
+			if candidate == ext {
+				return e, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// textExporter is the plain-text invoice written by Invoice
+type textExporter struct{}
+
+func (textExporter) Name() string         { return "txt" }
+func (textExporter) Extensions() []string { return []string{".txt"} }
+
+func (textExporter) Render(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) ([]byte, error) {
+	var b bytes.Buffer
+	if err := Invoice(&b, inv, items, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func init() {
+	Register(textExporter{})
+	Register(markdownExporter{})
+	Register(htmlExporter{})
+	Register(pdfExporter{})
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// exportInvoice renders the footer and tax-lines fixture with the named exporter
+func exportInvoice(t *testing.T, name string) string {
+	t.Helper()
+	inv, items := fixtureInvoice("Design review", "Fix | pipe & <b>markup</b>")
+	inv.Taxes = []*domain.InvoiceTax{
+		{Label: "VAT", Rate: 0, Note: "Reverse charge: VAT to be accounted for by the recipient"},
+		{Label: "City surcharge", Rate: 0.015},
+	}
+	inv.Footer = domain.InvoiceFooter{
+		Notes:               "Thank you for your business.",
+		PaymentInstructions: "Bank: First Example Bank\nIBAN: DE89 3704 0044 0532 0130 00",
+	}
+	inv.CalculateTotals()
+
+	e, err := ExporterNamed(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := e.Render(inv, items, fixtureOptions())
+	if err != nil {
+		t.Fatalf("failed to render %s: %v", name, err)
+	}
+	return string(out)
+}
+
+func TestExporter_GoldenMarkdown(t *testing.T) {
+	assertGolden(t, "invoice_markdown", exportInvoice(t, "markdown"))
+}
+
+func TestExporter_GoldenHTML(t *testing.T) {
+	assertGolden(t, "invoice_html", exportInvoice(t, "html"))
+}
+
+func TestExporter_TextMatchesInvoice(t *testing.T) {
+	inv, items := fixtureInvoice("Design review", "Build")
+	e, err := ExporterNamed(DefaultExporter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := e.Render(inv, items, fixtureOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := renderInvoice(t, inv, items, fixtureOptions()); string(out) != want {
+		t.Fatalf("txt exporter differs from Invoice:\n%s", out)
+	}
+}
+
+func TestExporterFor_MatchesExtensions(t *testing.T) {
+	tests := map[string]string{
+		"INV-2026-001.txt":      "txt",
+		"out/INV-2026-001.HTML": "html",
+		"invoice.htm":           "html",
+		"invoice.md":            "markdown",
+		"invoice.PDF":           "pdf",
+	}
+	for path, want := range tests {
+		e, ok := ExporterFor(path)
+		if !ok || e.Name() != want {
+			t.Errorf("ExporterFor(%q) = %v, want %s", path, e, want)
+		}
+	}
+
+	for _, path := range []string{"invoices", "invoice.docx"} {
+		if e, ok := ExporterFor(path); ok {
+			t.Errorf("expected no exporter for %q, got %s", path, e.Name())
+		}
+	}
+	if _, err := ExporterNamed("docx"); err == nil {
+		t.Error("expected an error for an unregistered format")
+	}
+}
+
+func TestExporter_PDFSetsTheTextInvoice(t *testing.T) {
+	inv, items := fixtureInvoice("Design review (remote)", "Build")
+	e, err := ExporterNamed("pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf, err := e.Render(inv, items, fixtureOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(pdf)
+	if !strings.HasPrefix(out, "%PDF-1.4\n") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatalf("expected a PDF document, got %q...", out[:min(len(out), 40)])
+	}
+
+	// Every line of the text invoice is shown, with parentheses escaped
+	for _, line := range strings.Split(strings.TrimSpace(renderInvoice(t, inv, items, fixtureOptions())), "\n") {
+		if line != "" && !strings.Contains(out, pdfString(line)+" Tj") {
+			t.Errorf("expected line %q in the PDF", line)
+		}
+	}
+
+	// The cross-reference table points at each object
+	xref := out[strings.LastIndex(out, "startxref\n")+len("startxref\n"):]
+	start, err := strconv.Atoi(strings.TrimSuffix(xref, "\n%%EOF\n"))
+	if err != nil || !strings.HasPrefix(out[start:], "xref\n") {
+		t.Fatalf("startxref does not point at the xref table: %v", err)
+	}
+	for i, entry := range strings.Split(out[start:], "\n")[3:] {
+		if !strings.HasSuffix(entry, " n ") {
+			break
+		}
+		offset, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(out[offset:], want) {
+			t.Fatalf("xref entry %d points at %q", i+1, out[offset:offset+10])
+		}
+	}
+}
+
+func TestTextPDF_WrapsAndPaginates(t *testing.T) {
+	lines := make([]string, pdfPageLines+1)
+	lines[0] = strings.Repeat("x", pdfColumns+5)
+	out := string(textPDF(lines))
+	if !strings.Contains(out, "/Count 2") {
+		t.Errorf("expected a second page for a wrapped line past a full page")
+	}
+	if !strings.Contains(out, "("+strings.Repeat("x", pdfColumns)+") Tj\nT* (xxxxx) Tj") {
+		t.Errorf("expected the long line wrapped at %d columns", pdfColumns)
+	}
+}
+
+func TestPDFString_EncodesWinAnsi(t *testing.T) {
+	tests := map[string]string{
+		"(a) \\ b": `(\(a\) \\ b)`,
+		"Café €5":  `(Caf\351 \2005)`,
+		"日本":       `(??)`,
+	}
+	for in, want := range tests {
+		if got := pdfString(in); got != want {
+			t.Errorf("pdfString(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// htmlExporter writes the invoice as a standalone HTML page that prints
+// cleanly from a browser
+type htmlExporter struct{}
+
+func (htmlExporter) Name() string         { return "html" }
+func (htmlExporter) Extensions() []string { return []string{".html", ".htm"} }
+
+func (htmlExporter) Render(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) ([]byte, error) {
+	var b bytes.Buffer
+	if err := invoiceHTML.Execute(&b, newInvoiceView(inv, items, opts)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+var invoiceHTML = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="{{.Labels.Language}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Labels.Invoice}} {{.Number}}</title>
+<style>
+body { font-family: sans-serif; max-width: 48em; margin: 2em auto; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.parties { display: flex; gap: 4em; margin: 2em 0; }
+.parties h2 { font-size: 1em; margin: 0 0 0.3em; }
+table { width: 100%; border-collapse: collapse; }
+th, td { padding: 0.4em; text-align: left; border-bottom: 1px solid #ddd; }
+.num { text-align: right; white-space: nowrap; }
+tfoot td { border: none; }
+tfoot tr.total td { font-weight: bold; border-top: 2px solid #222; }
+.block { white-space: pre-line; }
+</style>
+</head>
+<body>
+<h1>{{.Labels.Invoice}} {{.Number}}</h1>
+<p>{{.Labels.Date}}: {{.Date}}{{if .Due}}<br>{{.Labels.Due}}: {{.Due}}{{end}}</p>
+<div class="parties">
+{{- if .From}}
+<div><h2>{{.Labels.From}}</h2>{{range $i, $line := .From}}{{if $i}}<br>{{end}}{{$line}}{{end}}</div>
+{{- end}}
+{{- if .BillTo}}
+<div><h2>{{.Labels.BillTo}}</h2>{{range $i, $line := .BillTo}}{{if $i}}<br>{{end}}{{$line}}{{end}}</div>
+{{- end}}
+</div>
+<table>
+<thead><tr><th>{{.Labels.Date}}</th><th>{{.Labels.Description}}</th><th class="num">{{.Labels.Hours}}</th><th class="num">{{.Labels.Amount}}</th></tr></thead>
+<tbody>
+{{- range .Items}}
+<tr><td>{{.Date}}</td><td>{{.Description}}{{if .RateNote}}<br><small>{{.RateNote}}</small>{{end}}</td><td class="num">{{.Hours}}</td><td class="num">{{.Amount}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><td colspan="3" class="num">{{.Labels.Subtotal}}</td><td class="num">{{.Subtotal}}</td></tr>
+{{- range .Taxes}}
+<tr><td colspan="3" class="num">{{.Label}}</td><td class="num">{{.Amount}}</td></tr>
+{{- end}}
+<tr class="total"><td colspan="3" class="num">{{.Labels.Total}}</td><td class="num">{{.Total}}</td></tr>
+</tfoot>
+</table>
+{{- range .TaxNotes}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Notes}}
+<h2>{{.Labels.Notes}}</h2>
+<p class="block">{{.Notes}}</p>
+{{- end}}
+{{- if .PaymentInstructions}}
+<h2>{{.Labels.PaymentInstructions}}</h2>
+<p class="block">{{.PaymentInstructions}}</p>
+{{- end}}
+</body>
+</html>
+`))
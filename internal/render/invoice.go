@@ -0,0 +1,251 @@
+// Package render produces the documents sent to clients: invoices in each
+// registered export format, their timesheet appendices, estimates, and
+// monthly statements.
+package render
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/locale"
+	"github.com/mattn/go-runewidth"
+)
+
+// Options controls how documents are formatted
+type Options struct {
+	Locale     locale.Locale
+	HourFormat string            // config.HourFormatHM or config.HourFormatDecimal
+	From       config.UserConfig // sender details, omitted when empty
+	Date       time.Time         // issue date printed on the invoice
+	Labels     Labels            // words on invoices; English when zero
+}
+
+// Invoice column widths in terminal cells
+const (
+	invoiceWidth = 56
+	dateCol      = 12
+	descCol      = 24
+	hoursCol     = 8
+	amountCol    = 10
+)
+
+// Invoice writes a formatted text invoice. Long descriptions wrap onto
+// continuation lines rather than being cut off.
+func Invoice(w io.Writer, inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) error {
+	var b strings.Builder
+	l := opts.labels()
+
+	sep := strings.Repeat("=", invoiceWidth)
+	line := strings.Repeat("-", invoiceWidth)
+
+	// Header values line up after the longest label
+	labelWidth := dateCol
+	for _, label := range []string{l.InvoiceNumber, l.Date, l.Due} {
+		labelWidth = max(labelWidth, runewidth.StringWidth(label)+3)
+	}
+	header := func(label, value string) {
+		fmt.Fprintf(&b, "%s%s\n", padRight(label+":", labelWidth), value)
+	}
+
+	b.WriteString(strings.ToUpper(l.Invoice) + "\n")
+	b.WriteString(sep + "\n")
+	header(l.InvoiceNumber, inv.InvoiceNumber)
+	header(l.Date, opts.Locale.FormatLongDate(opts.Date))
+	if inv.DueDate != nil {
+		header(l.Due, opts.Locale.FormatLongDate(*inv.DueDate))
+	}
+
+	// From section (user info)
+	from := opts.From
+	if from.Name != "" || from.Email != "" {
+		b.WriteString("\n" + l.From + ":\n")
+		for _, field := range []string{from.Name, from.Email, from.Address, from.Phone} {
+			if field != "" {
+				fmt.Fprintf(&b, "  %s\n", field)
+			}
+		}
+	}
+
+	// Bill To section
+	b.WriteString("\n" + l.BillTo + ":\n")
+	if inv.Client != nil {
+		fmt.Fprintf(&b, "  %s\n", inv.Client.Name)
+		if inv.Client.Email != "" {
+			fmt.Fprintf(&b, "  %s\n", inv.Client.Email)
+		}
+	}
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(row(l.Date, l.Description, l.Hours, l.Amount))
+	b.WriteString(line + "\n")
+
+	for _, item := range items {
+		desc := wrap(item.Description, descCol)
+		b.WriteString(row(
+			opts.Locale.FormatShortDate(item.Date),
+			desc[0],
+			hours(opts, item.Hours),
+			opts.Locale.Money(item.Amount.Float()),
+		))
+		for _, cont := range desc[1:] {
+			b.WriteString(strings.TrimRight(row("", cont, "", ""), " \n") + "\n")
+		}
+		if note := rateNote(item, l, opts); note != "" {
+			for _, cont := range wrap("* "+note, descCol) {
+				b.WriteString(strings.TrimRight(row("", cont, "", ""), " \n") + "\n")
+			}
+		}
+	}
+
+	b.WriteString(line + "\n")
+	b.WriteString(total(l.Subtotal, opts.Locale.Money(inv.Subtotal.Float())))
+	for _, tax := range taxRows(inv, opts) {
+		b.WriteString(total(tax.Label, tax.Amount))
+	}
+	b.WriteString(total(strings.ToUpper(l.Total), opts.Locale.Money(inv.Total.Float())))
+
+	for _, tax := range inv.Taxes {
+		if tax.Note != "" {
+			b.WriteString("\n" + tax.Note + "\n")
+		}
+	}
+	writeBlock(&b, l.Notes, inv.Footer.Notes)
+	writeBlock(&b, l.PaymentInstructions, inv.Footer.PaymentInstructions)
+	b.WriteString(sep + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labeledAmount is a label and a formatted amount, e.g. one tax line
+type labeledAmount struct {
+	Label  string
+	Amount string
+}
+
+// taxRows lists an invoice's tax lines as they appear under the subtotal:
+// each configured tax separately, or a single line for the flat rate
+func taxRows(inv *domain.Invoice, opts Options) []labeledAmount {
+	switch {
+	case len(inv.Taxes) > 0:
+		// Each tax line separately, e.g. VAT and a local surcharge
+		rows := make([]labeledAmount, 0, len(inv.Taxes))
+		for _, tax := range inv.Taxes {
+			label := fmt.Sprintf("%s (%s%%)", tax.Label, opts.Locale.Number(tax.Rate*100, 1))
+			rows = append(rows, labeledAmount{label, opts.Locale.Money(tax.Amount.Float())})
+		}
+		return rows
+	case inv.TaxRate > 0:
+		label := fmt.Sprintf("%s (%s%%)", opts.labels().Tax, opts.Locale.Number(inv.TaxRate*100, 1))
+		return []labeledAmount{{label, opts.Locale.Money(inv.TaxAmount.Float())}}
+	default:
+		return []labeledAmount{{opts.labels().Tax, opts.Locale.Money(inv.TaxAmount.Float())}}
+	}
+}
+
+// writeBlock writes a titled block of free text, keeping its line breaks
+// and wrapping long lines to the invoice width
+func writeBlock(b *strings.Builder, title, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	for _, para := range strings.Split(text, "\n") {
+		for _, l := range wrap(para, invoiceWidth-2) {
+			b.WriteString(strings.TrimRight("  "+l, " ") + "\n")
+		}
+	}
+}
+
+// row lays out one line of the line item table
+func row(date, desc, hours, amount string) string {
+	return padRight(date, dateCol) + " " +
+		padRight(desc, descCol) + " " +
+		padLeft(hours, hoursCol) + " " +
+		padLeft(amount, amountCol) + "\n"
+}
+
+// total right-aligns a totals label against the amount column
+func total(label, amount string) string {
+	return padLeft(label, dateCol+descCol+hoursCol+2) + " " + padLeft(amount, amountCol) + "\n"
+}
+
+// Timesheet writes every invoiced entry with its start and end times and
+// full description, as an appendix to the invoice
+func Timesheet(w io.Writer, inv *domain.Invoice, entries []*domain.TimeEntry, opts Options) error {
+	sorted := make([]*domain.TimeEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.Before(sorted[j].StartTime)
+	})
+
+	var b strings.Builder
+
+	sep := strings.Repeat("=", 72)
+	line := strings.Repeat("-", 72)
+
+	b.WriteString("TIMESHEET\n")
+	b.WriteString(sep + "\n")
+	fmt.Fprintf(&b, "Invoice #:  %s\n", inv.InvoiceNumber)
+	if inv.Client != nil {
+		fmt.Fprintf(&b, "Client:     %s\n", inv.Client.Name)
+	}
+	fmt.Fprintf(&b, "Period:     %s - %s\n",
+		opts.Locale.FormatLongDate(inv.PeriodStart), opts.Locale.FormatLongDate(inv.PeriodEnd))
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(padRight("Date", dateCol) + " Start End   " + padLeft("Hours", hoursCol) + "  Description\n")
+	b.WriteString(line + "\n")
+
+	var sum float64
+	for _, e := range sorted {
+		end := "     "
+		if e.EndTime != nil {
+			end = e.EndTime.Format("15:04")
+		}
+		h := e.Duration().Hours()
+		sum += h
+		b.WriteString(strings.TrimRight(padRight(opts.Locale.FormatShortDate(e.StartTime), dateCol)+" "+
+			e.StartTime.Format("15:04")+" "+end+" "+
+			padLeft(hours(opts, h), hoursCol)+"  "+e.Description, " ") + "\n")
+	}
+
+	b.WriteString(line + "\n")
+	label := fmt.Sprintf("Total (%d entries)", len(sorted))
+	b.WriteString(padRight(label, 24) + " " + padLeft(hours(opts, sum), hoursCol) + "\n")
+	b.WriteString(sep + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// hours formats a duration in hours using the configured hour format
+func hours(opts Options, h float64) string {
+	if opts.HourFormat == config.HourFormatDecimal {
+		return opts.Locale.Number(h, 2)
+	}
+	// Adjustments written off by billing rules have negative hours
+	if h < 0 {
+		return "-" + hours(opts, -h)
+	}
+	whole := int(h)
+	m := int(math.Round((h - float64(whole)) * 60))
+	if m == 60 {
+		whole++
+		m = 0
+	}
+	if whole == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	if m == 0 {
+		return fmt.Sprintf("%dh", whole)
+	}
+	return fmt.Sprintf("%dh %dm", whole, m)
+}
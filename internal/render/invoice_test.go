@@ -0,0 +1,244 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/locale"
+	"github.com/mattn/go-runewidth"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// assertGolden compares got with testdata/<name>.golden
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Fatalf("output does not match %s\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+func fixtureInvoice(descriptions ...string) (*domain.Invoice, []*domain.InvoiceLineItem) {
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	inv := domain.NewInvoice("INV-2026-007", 1, start, start.AddDate(0, 0, 27))
+	inv.Client = &domain.Client{ID: 1, Name: "Acme Corp", Email: "ap@acme.test"}
+	due := time.Date(2026, time.April, 30, 0, 0, 0, 0, time.UTC)
+	inv.DueDate = &due
+	inv.TaxRate = 0.0825
+
+	var items []*domain.InvoiceLineItem
+	for i, desc := range descriptions {
+		item := &domain.InvoiceLineItem{
+			Date:        start.AddDate(0, 0, i),
+			Description: desc,
+			Hours:       1.5 + float64(i),
+			Rate:        150,
+		}
+		item.Amount = domain.AmountFor(item.Hours, item.Rate)
+		items = append(items, item)
+		inv.LineItems = append(inv.LineItems, item)
+	}
+	inv.CalculateTotals()
+
+	return inv, items
+}
+
+func fixtureOptions() Options {
+	return Options{
+		Locale:     locale.Default(),
+		HourFormat: config.HourFormatHM,
+		From:       config.UserConfig{Name: "Jo Freelancer", Email: "jo@example.test", Address: "1 Main St"},
+		Date:       time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func renderInvoice(t *testing.T, inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) string {
+	t.Helper()
+	var b strings.Builder
+	if err := Invoice(&b, inv, items, opts); err != nil {
+		t.Fatalf("failed to render invoice: %v", err)
+	}
+	return b.String()
+}
+
+func TestInvoice_Golden(t *testing.T) {
+	inv, items := fixtureInvoice("Design review", "Build", "Deploy")
+	assertGolden(t, "invoice", renderInvoice(t, inv, items, fixtureOptions()))
+}
+
+func TestInvoice_GoldenDecimalHoursAndLocale(t *testing.T) {
+	inv, items := fixtureInvoice("Entwurf", "Umsetzung")
+	opts := fixtureOptions()
+	opts.Locale = locale.FromConfig(config.LocaleConfig{Name: "de-DE"})
+	opts.HourFormat = config.HourFormatDecimal
+	opts.From = config.UserConfig{}
+	assertGolden(t, "invoice_de_decimal", renderInvoice(t, inv, items, opts))
+}
+
+func TestInvoice_GoldenGermanLabels(t *testing.T) {
+	inv, items := fixtureInvoice("Entwurf", "Umsetzung")
+	inv.Footer = domain.InvoiceFooter{Notes: "Vielen Dank für Ihren Auftrag."}
+	opts := fixtureOptions()
+	opts.Locale = locale.FromConfig(config.LocaleConfig{Name: "de-DE"})
+	labels, err := LabelsFor("de", nil)
+	if err != nil {
+		t.Fatalf("failed to get labels: %v", err)
+	}
+	opts.Labels = labels
+	assertGolden(t, "invoice_de_labels", renderInvoice(t, inv, items, opts))
+}
+
+func TestLabelsFor_Translations(t *testing.T) {
+	translations := map[string]map[string]string{
+		"de": {"tax": "USt."},
+		"it": {"invoice": "Fattura", "total": "Totale"},
+	}
+
+	de, err := LabelsFor("de", translations)
+	if err != nil || de.Tax != "USt." || de.Invoice != "Rechnung" {
+		t.Fatalf("expected the override on top of German, got %+v (%v)", de, err)
+	}
+	it, err := LabelsFor("it", translations)
+	if err != nil || it.Invoice != "Fattura" || it.Subtotal != "Subtotal" || it.Language != "it" {
+		t.Fatalf("expected an added language to fall back to English, got %+v (%v)", it, err)
+	}
+	if en, err := LabelsFor("", nil); err != nil || en.Invoice != "Invoice" {
+		t.Fatalf("expected English by default, got %+v (%v)", en, err)
+	}
+
+	if _, err := LabelsFor("pt", translations); err == nil {
+		t.Fatal("expected an unknown language to be refused")
+	}
+	translations["de"]["vat"] = "MwSt."
+	if err := CheckTranslations(translations); err == nil || !strings.Contains(err.Error(), `"vat"`) {
+		t.Fatalf("expected the unknown label to be reported, got %v", err)
+	}
+}
+
+func TestInvoice_GoldenTaxLines(t *testing.T) {
+	inv, items := fixtureInvoice("Design review", "Build")
+	inv.Taxes = []*domain.InvoiceTax{
+		{Label: "VAT", Rate: 0, Note: "Reverse charge: VAT to be accounted for by the recipient"},
+		{Label: "City surcharge", Rate: 0.015},
+	}
+	inv.CalculateTotals()
+	assertGolden(t, "invoice_tax_lines", renderInvoice(t, inv, items, fixtureOptions()))
+}
+
+func TestInvoice_GoldenFooter(t *testing.T) {
+	inv, items := fixtureInvoice("Design review")
+	inv.Footer = domain.InvoiceFooter{
+		Notes:               "Thank you for your business. Payment is due within 30 days of the invoice date; late payments incur a 2% monthly fee.",
+		PaymentInstructions: "Bank: First Example Bank\nIBAN: DE89 3704 0044 0532 0130 00\n\nPayPal: jo@example.test",
+	}
+	assertGolden(t, "invoice_footer", renderInvoice(t, inv, items, fixtureOptions()))
+}
+
+func TestInvoice_GoldenRateReason(t *testing.T) {
+	inv, items := fixtureInvoice("Design review", "Production hotfix")
+	items[1].Rate = 225
+	items[1].Amount = domain.AmountFor(items[1].Hours, items[1].Rate)
+	items[1].RateReason = "Weekend call-out at the agreed emergency rate"
+	inv.CalculateTotals()
+	assertGolden(t, "invoice_rate_reason", renderInvoice(t, inv, items, fixtureOptions()))
+}
+
+func TestInvoice_GoldenRole(t *testing.T) {
+	inv, items := fixtureInvoice("Design review", "Architecture workshop")
+	items[1].Rate = 180
+	items[1].Amount = domain.AmountFor(items[1].Hours, items[1].Rate)
+	items[1].Role = "consulting"
+	inv.CalculateTotals()
+	assertGolden(t, "invoice_role", renderInvoice(t, inv, items, fixtureOptions()))
+}
+
+func TestInvoice_GoldenLongAndWideDescriptions(t *testing.T) {
+	inv, items := fixtureInvoice(
+		"Quarterly planning workshop with the product and engineering leads",
+		"Café résumé tweaks",
+		"日本語のドキュメント翻訳とレビュー",
+		"Supercalifragilisticexpialidocious-refactor",
+	)
+	got := renderInvoice(t, inv, items, fixtureOptions())
+	assertGolden(t, "invoice_wide", got)
+
+	// Every table row must end at the same display column
+	rules := 0
+	for _, line := range strings.Split(got, "\n") {
+		if strings.HasPrefix(line, "----") {
+			rules++
+			continue
+		}
+		// Line items sit between the second and third rules
+		if rules != 2 {
+			continue
+		}
+		if strings.TrimSpace(line[:dateCol]) == "" {
+			// Continuation lines only carry the description
+			if w := runewidth.StringWidth(line); w > dateCol+1+descCol {
+				t.Fatalf("continuation line overflows description column (%d cells): %q", w, line)
+			}
+			continue
+		}
+		if w := runewidth.StringWidth(line); w != dateCol+descCol+hoursCol+amountCol+3 {
+			t.Fatalf("misaligned row (%d cells): %q", w, line)
+		}
+	}
+}
+
+func TestTimesheet_Golden(t *testing.T) {
+	inv, _ := fixtureInvoice()
+	start := time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC)
+	var entries []*domain.TimeEntry
+	for i, desc := range []string{"Kickoff call", "Implementation — phase one", "Review"} {
+		e := domain.NewTimeEntry(1, desc, 150)
+		e.StartTime = start.AddDate(0, 0, 2-i)
+		e.Stop(e.StartTime.Add(time.Duration(i+1) * 45 * time.Minute))
+		entries = append(entries, e)
+	}
+
+	var b strings.Builder
+	if err := Timesheet(&b, inv, entries, fixtureOptions()); err != nil {
+		t.Fatalf("failed to render timesheet: %v", err)
+	}
+	assertGolden(t, "timesheet", b.String())
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"two words here", 9, []string{"two words", "here"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"日本語テキスト", 6, []string{"日本語", "テキス", "ト"}},
+	}
+	for _, tt := range tests {
+		got := wrap(tt.in, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Fatalf("wrap(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Labels are the words printed on an invoice around the client's data, in
+// the language the client is invoiced in. Money and dates keep the
+// configured locale either way.
+type Labels struct {
+	Language            string // code of the language, e.g. "de"
+	Invoice             string
+	InvoiceNumber       string
+	Date                string
+	Due                 string
+	From                string
+	BillTo              string
+	Description         string
+	Hours               string
+	Amount              string
+	Rate                string // before a rate other than the client's, with the reason
+	Subtotal            string
+	Tax                 string
+	Total               string
+	Notes               string
+	PaymentInstructions string
+}
+
+// DefaultLanguage is the language of invoices to clients without one
+const DefaultLanguage = "en"
+
+// languages are the built-in translations, keyed by the code set on clients
+var languages = map[string]Labels{
+	"en": {
+		Invoice: "Invoice", InvoiceNumber: "Invoice #", Date: "Date", Due: "Due",
+		From: "From", BillTo: "Bill To", Description: "Description", Hours: "Hours", Amount: "Amount",
+		Rate: "Rate", Subtotal: "Subtotal", Tax: "Tax", Total: "Total",
+		Notes: "Notes", PaymentInstructions: "Payment Instructions",
+	},
+	"de": {
+		Invoice: "Rechnung", InvoiceNumber: "Rechnungsnr.", Date: "Datum", Due: "Fällig",
+		From: "Von", BillTo: "Rechnung an", Description: "Beschreibung", Hours: "Stunden", Amount: "Betrag",
+		Rate: "Stundensatz", Subtotal: "Zwischensumme", Tax: "MwSt.", Total: "Gesamt",
+		Notes: "Anmerkungen", PaymentInstructions: "Zahlungshinweise",
+	},
+	"fr": {
+		Invoice: "Facture", InvoiceNumber: "N° de facture", Date: "Date", Due: "Échéance",
+		From: "De", BillTo: "Facturé à", Description: "Description", Hours: "Heures", Amount: "Montant",
+		Rate: "Taux", Subtotal: "Sous-total", Tax: "TVA", Total: "Total",
+		Notes: "Remarques", PaymentInstructions: "Modalités de paiement",
+	},
+	"es": {
+		Invoice: "Factura", InvoiceNumber: "N.º de factura", Date: "Fecha", Due: "Vencimiento",
+		From: "De", BillTo: "Facturar a", Description: "Descripción", Hours: "Horas", Amount: "Importe",
+		Rate: "Tarifa", Subtotal: "Subtotal", Tax: "IVA", Total: "Total",
+		Notes: "Notas", PaymentInstructions: "Instrucciones de pago",
+	},
+	"nl": {
+		Invoice: "Factuur", InvoiceNumber: "Factuurnummer", Date: "Datum", Due: "Vervaldatum",
+		From: "Van", BillTo: "Factuur aan", Description: "Omschrijving", Hours: "Uren", Amount: "Bedrag",
+		Rate: "Tarief", Subtotal: "Subtotaal", Tax: "Btw", Total: "Totaal",
+		Notes: "Opmerkingen", PaymentInstructions: "Betalingsinstructies",
+	},
+}
+
+// fields maps the keys used in invoice.translations to the labels they set
+func (l *Labels) fields() map[string]*string {
+	return map[string]*string{
+		"invoice":              &l.Invoice,
+		"invoice_number":       &l.InvoiceNumber,
+		"date":                 &l.Date,
+		"due":                  &l.Due,
+		"from":                 &l.From,
+		"bill_to":              &l.BillTo,
+		"description":          &l.Description,
+		"hours":                &l.Hours,
+		"amount":               &l.Amount,
+		"rate":                 &l.Rate,
+		"subtotal":             &l.Subtotal,
+		"tax":                  &l.Tax,
+		"total":                &l.Total,
+		"notes":                &l.Notes,
+		"payment_instructions": &l.PaymentInstructions,
+	}
+}
+
+// Languages returns the codes of the built-in languages and those added in
+// translations, sorted
+func Languages(translations map[string]map[string]string) []string {
+	var codes []string
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	for code := range translations {
+		if _, ok := languages[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// LabelsFor returns the labels for language, with any overrides for it in
+// translations, which map a language code to label keys and their text.
+// Translations may add a language; labels it leaves out stay in English.
+// An empty language is English.
+func LabelsFor(language string, translations map[string]map[string]string) (Labels, error) {
+	if language == "" {
+		language = DefaultLanguage
+	}
+	l, builtIn := languages[language]
+	overrides, added := translations[language]
+	if !builtIn && !added {
+		return Labels{}, fmt.Errorf("unknown invoice language %q (available: %s)",
+			language, strings.Join(Languages(translations), ", "))
+	}
+	if !builtIn {
+		l = languages[DefaultLanguage]
+	}
+	l.Language = language
+
+	fields := l.fields()
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			return Labels{}, fmt.Errorf("invoice.translations.%s: unknown label %q", language, key)
+		}
+		*field = overrides[key]
+	}
+	return l, nil
+}
+
+// CheckTranslations reports label keys in translations that are not used
+// on invoices
+func CheckTranslations(translations map[string]map[string]string) error {
+	var errs []error
+	for _, code := range Languages(translations) {
+		if _, err := LabelsFor(code, translations); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// labels returns the labels to print, English unless set
+func (o Options) labels() Labels {
+	if o.Labels.Invoice == "" {
+		l := languages[DefaultLanguage]
+		l.Language = DefaultLanguage
+		return l
+	}
+	return o.Labels
+}
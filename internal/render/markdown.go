@@ -0,0 +1,89 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// markdownExporter writes the invoice as Markdown, for pasting into wikis,
+// issue trackers and notes apps
+type markdownExporter struct{}
+
+func (markdownExporter) Name() string         { return "markdown" }
+func (markdownExporter) Extensions() []string { return []string{".md", ".markdown"} }
+
+func (markdownExporter) Render(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) ([]byte, error) {
+	v := newInvoiceView(inv, items, opts)
+	var b strings.Builder
+
+	l := v.Labels
+	fmt.Fprintf(&b, "# %s %s\n\n", mdEscape(l.Invoice), mdEscape(v.Number))
+	fmt.Fprintf(&b, "**%s:** %s  \n", mdEscape(l.Date), v.Date)
+	if v.Due != "" {
+		fmt.Fprintf(&b, "**%s:** %s  \n", mdEscape(l.Due), v.Due)
+	}
+	b.WriteString("\n")
+
+	writeMarkdownLines(&b, l.From, v.From)
+	writeMarkdownLines(&b, l.BillTo, v.BillTo)
+
+	fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+		mdEscape(l.Date), mdEscape(l.Description), mdEscape(l.Hours), mdEscape(l.Amount))
+	b.WriteString("|------|-------------|------:|-------:|\n")
+	for _, item := range v.Items {
+		desc := mdEscape(item.Description)
+		if item.RateNote != "" {
+			desc += "<br>*" + mdEscape(item.RateNote) + "*"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			item.Date, desc, item.Hours, item.Amount)
+	}
+	fmt.Fprintf(&b, "| | **%s** | | %s |\n", mdEscape(l.Subtotal), v.Subtotal)
+	for _, tax := range v.Taxes {
+		fmt.Fprintf(&b, "| | %s | | %s |\n", mdEscape(tax.Label), tax.Amount)
+	}
+	fmt.Fprintf(&b, "| | **%s** | | **%s** |\n", mdEscape(l.Total), v.Total)
+
+	for _, note := range v.TaxNotes {
+		fmt.Fprintf(&b, "\n%s\n", mdEscape(note))
+	}
+	writeMarkdownBlock(&b, l.Notes, v.Notes)
+	writeMarkdownBlock(&b, l.PaymentInstructions, v.PaymentInstructions)
+
+	return []byte(b.String()), nil
+}
+
+// writeMarkdownLines writes a titled address block, one field per line
+func writeMarkdownLines(b *strings.Builder, title string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "**%s:**  \n", title)
+	for i, line := range lines {
+		b.WriteString(mdEscape(line))
+		if i < len(lines)-1 {
+			b.WriteString("  ")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// writeMarkdownBlock writes a titled block of free text, keeping its line
+// breaks
+func writeMarkdownBlock(b *strings.Builder, title, text string) {
+	if text == "" {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	b.WriteString(strings.ReplaceAll(mdEscape(text), "\n", "  \n") + "\n")
+}
+
+var mdReplacer = strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "#", `\#`, "<", "&lt;")
+
+// mdEscape keeps user text from breaking table cells or turning into markup
+func mdEscape(s string) string {
+	return mdReplacer.Replace(s)
+}
@@ -0,0 +1,144 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// pdfExporter lays the plain-text invoice out on A4 pages in Courier. It is
+// one of the fonts every PDF reader provides, so none is embedded and the
+// columns line up as they do in the text. Characters Courier cannot show,
+// such as CJK, print as "?".
+type pdfExporter struct{}
+
+func (pdfExporter) Name() string         { return "pdf" }
+func (pdfExporter) Extensions() []string { return []string{".pdf"} }
+
+func (pdfExporter) Render(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) ([]byte, error) {
+	var text bytes.Buffer
+	if err := Invoice(&text, inv, items, opts); err != nil {
+		return nil, err
+	}
+	return textPDF(strings.Split(strings.TrimRight(text.String(), "\n"), "\n")), nil
+}
+
+// Page layout in points: A4 with 2cm margins, 9pt Courier whose characters
+// are 0.6em wide
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 56
+	pdfFontSize   = 9
+	pdfLeading    = 11
+	pdfColumns    = (pdfPageWidth - 2*pdfMargin) * 10 / (pdfFontSize * 6)
+	pdfPageLines  = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+)
+
+// textPDF writes lines of monospaced text as a PDF, wrapping lines wider
+// than the page and starting a new page when one is full
+func textPDF(lines []string) []byte {
+	var wrapped []string
+	for _, line := range lines {
+		runes := []rune(strings.ReplaceAll(line, "\t", "    "))
+		for len(runes) > pdfColumns {
+			wrapped = append(wrapped, string(runes[:pdfColumns]))
+			runes = runes[pdfColumns:]
+		}
+		wrapped = append(wrapped, string(runes))
+	}
+
+	var pages [][]string
+	for len(wrapped) > pdfPageLines {
+		pages = append(pages, wrapped[:pdfPageLines])
+		wrapped = wrapped[pdfPageLines:]
+	}
+	pages = append(pages, wrapped)
+
+	// Objects 1 to 3 are the catalog, page tree and font; each page then
+	// takes two, itself and its content
+	var objects []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range pages {
+		var content strings.Builder
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", pdfFontSize, pdfLeading, pdfMargin, pdfPageHeight-pdfMargin-pdfFontSize)
+		for j, line := range page {
+			if j > 0 {
+				content.WriteString("T* ")
+			}
+			content.WriteString(pdfString(line) + " Tj\n")
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+// pdfString quotes s as a PDF string in WinAnsiEncoding
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		c, ok := winAnsi(r)
+		switch {
+		case !ok:
+			b.WriteByte('?')
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// winAnsiExtras are the characters WinAnsiEncoding places at 0x80 to 0x9F
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// winAnsi returns r's byte in WinAnsiEncoding, if it has one
+func winAnsi(r rune) (byte, bool) {
+	switch {
+	case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+		return byte(r), true
+	}
+	c, ok := winAnsiExtras[r]
+	return c, ok
+}
@@ -0,0 +1,85 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// Statement column widths share the invoice layout, with a running balance
+// in place of hours
+const (
+	statementDescCol = invoiceWidth - dateCol - 2*amountCol - 3
+)
+
+// Statement writes a formatted text statement of account: the balance
+// carried forward, each invoice issued and payment received in the month
+// with the running balance, and the balance due at the end.
+func Statement(w io.Writer, st *domain.Statement, opts Options) error {
+	var b strings.Builder
+
+	sep := strings.Repeat("=", invoiceWidth)
+	line := strings.Repeat("-", invoiceWidth)
+	last := st.End.AddDate(0, 0, -1)
+
+	b.WriteString("STATEMENT\n")
+	b.WriteString(sep + "\n")
+	fmt.Fprintf(&b, "Period:     %s - %s\n", opts.Locale.FormatLongDate(st.Start), opts.Locale.FormatLongDate(last))
+	fmt.Fprintf(&b, "Date:       %s\n", opts.Locale.FormatLongDate(opts.Date))
+
+	from := opts.From
+	if from.Name != "" || from.Email != "" {
+		b.WriteString("\nFrom:\n")
+		for _, field := range []string{from.Name, from.Email, from.Address, from.Phone} {
+			if field != "" {
+				fmt.Fprintf(&b, "  %s\n", field)
+			}
+		}
+	}
+
+	b.WriteString("\nStatement For:\n")
+	if st.Client != nil {
+		fmt.Fprintf(&b, "  %s\n", st.Client.Name)
+		if st.Client.Email != "" {
+			fmt.Fprintf(&b, "  %s\n", st.Client.Email)
+		}
+	}
+
+	b.WriteString("\n" + line + "\n")
+	b.WriteString(statementRow("Date", "Description", "Amount", "Balance"))
+	b.WriteString(line + "\n")
+
+	b.WriteString(statementRow(opts.Locale.FormatShortDate(st.Start), "Balance forward", "",
+		opts.Locale.Money(st.OpeningBalance.Float())))
+	for _, l := range st.Lines {
+		desc := "Invoice " + l.Invoice.InvoiceNumber
+		if l.Payment {
+			desc = "Payment " + l.Invoice.InvoiceNumber
+		}
+		lines := wrap(desc, statementDescCol)
+		b.WriteString(statementRow(opts.Locale.FormatShortDate(l.Date), lines[0],
+			opts.Locale.Money(l.Amount.Float()), opts.Locale.Money(l.Balance.Float())))
+		for _, cont := range lines[1:] {
+			b.WriteString(strings.TrimRight(statementRow("", cont, "", ""), " \n") + "\n")
+		}
+	}
+
+	b.WriteString(line + "\n")
+	b.WriteString(total("Invoiced", opts.Locale.Money(st.Invoiced.Float())))
+	b.WriteString(total("Received", opts.Locale.Money(st.Received.Float())))
+	b.WriteString(total("BALANCE DUE", opts.Locale.Money(st.ClosingBalance.Float())))
+	b.WriteString(sep + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// statementRow lays out one line of the statement's table
+func statementRow(date, desc, amount, balance string) string {
+	return padRight(date, dateCol) + " " +
+		padRight(desc, statementDescCol) + " " +
+		padLeft(amount, amountCol) + " " +
+		padLeft(balance, amountCol) + "\n"
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestStatement_Golden(t *testing.T) {
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	paid := time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)
+	earlier := &domain.Invoice{InvoiceNumber: "INV-2026-004", Total: 120000}
+	issued := &domain.Invoice{InvoiceNumber: "INV-2026-007", Total: 121781}
+
+	st := &domain.Statement{
+		Client:         &domain.Client{ID: 1, Name: "Acme Corp", Email: "ap@acme.test"},
+		Start:          start,
+		End:            start.AddDate(0, 1, 0),
+		OpeningBalance: 120000,
+		Lines: []*domain.StatementLine{
+			{Date: paid, Invoice: earlier, Payment: true, Amount: -120000, Balance: 0},
+			{Date: time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), Invoice: issued, Amount: 121781, Balance: 121781},
+		},
+		Invoiced:       121781,
+		Received:       120000,
+		ClosingBalance: 121781,
+	}
+
+	var b strings.Builder
+	if err := Statement(&b, st, fixtureOptions()); err != nil {
+		t.Fatalf("failed to render statement: %v", err)
+	}
+	assertGolden(t, "statement", b.String())
+}
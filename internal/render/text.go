@@ -0,0 +1,56 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// padRight pads s with spaces to the given display width. Widths are
+// measured in terminal cells so accented and wide characters line up.
+func padRight(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
+
+// padLeft right-aligns s within the given display width
+func padLeft(s string, width int) string {
+	return runewidth.FillLeft(s, width)
+}
+
+// wrap breaks s into lines no wider than width, splitting on spaces where
+// possible and inside words that are too long on their own
+func wrap(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	var cur string
+	for _, word := range words {
+		for runewidth.StringWidth(word) > width {
+			if cur != "" {
+				lines = append(lines, cur)
+				cur = ""
+			}
+			head := runewidth.Truncate(word, width, "")
+			lines = append(lines, head)
+			word = word[len(head):]
+		}
+		switch {
+		case word == "":
+		case cur == "":
+			cur = word
+		case runewidth.StringWidth(cur)+1+runewidth.StringWidth(word) <= width:
+			cur += " " + word
+		default:
+			lines = append(lines, cur)
+			cur = word
+		}
+	}
+	if cur != "" {
+		lines = append(lines, cur)
+	}
+
+	return lines
+}
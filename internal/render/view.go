@@ -0,0 +1,100 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// invoiceView is an invoice with every value formatted for display, shared
+// by the exporters that produce markup
+type invoiceView struct {
+	Labels              Labels
+	Number              string
+	Date                string
+	Due                 string // empty when the invoice has no due date
+	From                []string
+	BillTo              []string
+	Items               []itemView
+	Subtotal            string
+	Taxes               []labeledAmount
+	Total               string
+	TaxNotes            []string
+	Notes               string
+	PaymentInstructions string
+}
+
+// itemView is one formatted line item
+type itemView struct {
+	Date        string
+	Description string
+	Hours       string
+	Amount      string
+	RateNote    string // why the item is billed at another rate; empty if it is not
+}
+
+func newInvoiceView(inv *domain.Invoice, items []*domain.InvoiceLineItem, opts Options) invoiceView {
+	v := invoiceView{
+		Labels:              opts.labels(),
+		Number:              inv.InvoiceNumber,
+		Date:                opts.Locale.FormatLongDate(opts.Date),
+		Subtotal:            opts.Locale.Money(inv.Subtotal.Float()),
+		Taxes:               taxRows(inv, opts),
+		Total:               opts.Locale.Money(inv.Total.Float()),
+		Notes:               strings.TrimSpace(inv.Footer.Notes),
+		PaymentInstructions: strings.TrimSpace(inv.Footer.PaymentInstructions),
+	}
+	if inv.DueDate != nil {
+		v.Due = opts.Locale.FormatLongDate(*inv.DueDate)
+	}
+
+	// Sender details are only shown with a name or email, as on the text invoice
+	from := opts.From
+	if from.Name != "" || from.Email != "" {
+		for _, field := range []string{from.Name, from.Email, from.Address, from.Phone} {
+			if field != "" {
+				v.From = append(v.From, field)
+			}
+		}
+	}
+	if inv.Client != nil {
+		v.BillTo = append(v.BillTo, inv.Client.Name)
+		if inv.Client.Email != "" {
+			v.BillTo = append(v.BillTo, inv.Client.Email)
+		}
+	}
+
+	for _, item := range items {
+		v.Items = append(v.Items, itemView{
+			Date:        opts.Locale.FormatShortDate(item.Date),
+			Description: item.Description,
+			Hours:       hours(opts, item.Hours),
+			Amount:      opts.Locale.Money(item.Amount.Float()),
+			RateNote:    rateNote(item, v.Labels, opts),
+		})
+	}
+	for _, tax := range inv.Taxes {
+		if tax.Note != "" {
+			v.TaxNotes = append(v.TaxNotes, tax.Note)
+		}
+	}
+	return v
+}
+
+// rateNote explains an item billed at a rate other than the client's, e.g.
+// "Rate $150.00/h: weekend release", or names the rate card role it was
+// billed at, e.g. "consulting $120.00/h", or is empty
+func rateNote(item *domain.InvoiceLineItem, l Labels, opts Options) string {
+	if item.RateReason == "" && item.Role == "" {
+		return ""
+	}
+	label := l.Rate
+	if item.Role != "" {
+		label = item.Role
+	}
+	note := label + " " + opts.Locale.Money(item.Rate) + "/h"
+	if item.RateReason != "" {
+		note += ": " + item.RateReason
+	}
+	return note
+}
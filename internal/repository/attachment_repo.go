@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// AttachmentRepo is a SQLite implementation of AttachmentRepository
+type AttachmentRepo struct {
+	db conn
+}
+
+// NewAttachmentRepo creates a new AttachmentRepo
+func NewAttachmentRepo(database *db.DB) *AttachmentRepo {
+	return &AttachmentRepo{db: database}
+}
+
+// Create records an attachment whose file is already stored
+func (r *AttachmentRepo) Create(ctx context.Context, attachment *domain.Attachment) error {
+	if err := attachment.Validate(); err != nil {
+		return fmt.Errorf("invalid attachment: %w", err)
+	}
+
+	query := `
+		INSERT INTO attachments (owner_type, owner_id, name, path, size, sha256, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(attachment.OwnerType),
+		attachment.OwnerID,
+		attachment.Name,
+		attachment.Path,
+		attachment.Size,
+		attachment.SHA256,
+		formatTimeValue(attachment.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get attachment ID: %w", err)
+	}
+
+	attachment.ID = id
+	return nil
+}
+
+// GetByID retrieves an attachment by ID
+func (r *AttachmentRepo) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	query := `
+		SELECT id, owner_type, owner_id, name, path, size, sha256, created_at
+		FROM attachments
+		WHERE id = ?
+	`
+
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("attachment not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// List retrieves the attachments of a record, oldest first
+func (r *AttachmentRepo) List(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) ([]*domain.Attachment, error) {
+	query := `
+		SELECT id, owner_type, owner_id, name, path, size, sha256, created_at
+		FROM attachments
+		WHERE owner_type = ? AND owner_id = ?
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(ownerType), ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make([]*domain.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Delete removes an attachment's record. The stored file is left to the
+// caller.
+func (r *AttachmentRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM attachments WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("attachment not found")
+	}
+
+	return nil
+}
+
+// scanAttachment reads one attachment row
+func scanAttachment(row interface{ Scan(...any) error }) (*domain.Attachment, error) {
+	attachment := &domain.Attachment{}
+	var ownerType, createdAt string
+
+	err := row.Scan(
+		&attachment.ID,
+		&ownerType,
+		&attachment.OwnerID,
+		&attachment.Name,
+		&attachment.Path,
+		&attachment.Size,
+		&attachment.SHA256,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.OwnerType = domain.AttachmentOwner(ownerType)
+	if attachment.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	return attachment, nil
+}
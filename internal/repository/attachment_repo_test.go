@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestAttachmentRepo_CreateListDelete(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001")
+	other := env.draftInvoice(client, "INV-2026-002")
+
+	contract := &domain.Attachment{
+		OwnerType: domain.AttachmentOwnerInvoice,
+		OwnerID:   invoice.ID,
+		Name:      "contract.pdf",
+		Path:      "invoice/1/contract.pdf",
+		Size:      1024,
+		SHA256:    "abc123",
+		CreatedAt: day(2),
+	}
+	receipt := &domain.Attachment{
+		OwnerType: domain.AttachmentOwnerInvoice,
+		OwnerID:   invoice.ID,
+		Name:      "receipt.png",
+		Path:      "invoice/1/receipt.png",
+		Size:      2048,
+		SHA256:    "def456",
+		CreatedAt: day(3),
+	}
+	unrelated := &domain.Attachment{
+		OwnerType: domain.AttachmentOwnerInvoice,
+		OwnerID:   other.ID,
+		Name:      "contract.pdf",
+		Path:      "invoice/2/contract.pdf",
+		Size:      10,
+		SHA256:    "fff",
+		CreatedAt: day(1),
+	}
+	for _, a := range []*domain.Attachment{receipt, contract, unrelated} {
+		if err := env.attachments.Create(env.ctx, a); err != nil {
+			t.Fatalf("failed to create attachment: %v", err)
+		}
+	}
+
+	got, err := env.attachments.GetByID(env.ctx, contract.ID)
+	if err != nil {
+		t.Fatalf("failed to get attachment: %v", err)
+	}
+	if got.Name != "contract.pdf" || got.Path != contract.Path || got.Size != 1024 || got.SHA256 != "abc123" {
+		t.Fatalf("unexpected attachment: %+v", got)
+	}
+	if !got.CreatedAt.Equal(day(2)) || got.OwnerType != domain.AttachmentOwnerInvoice {
+		t.Fatalf("expected owner and created_at to round-trip, got %+v", got)
+	}
+
+	list, err := env.attachments.List(env.ctx, domain.AttachmentOwnerInvoice, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to list attachments: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != contract.ID || list[1].ID != receipt.ID {
+		t.Fatalf("expected the invoice's two attachments oldest first, got %+v", list)
+	}
+
+	dup := *contract
+	dup.ID = 0
+	if err := env.attachments.Create(env.ctx, &dup); err == nil {
+		t.Fatalf("expected a duplicate path to be rejected")
+	}
+
+	if err := env.attachments.Delete(env.ctx, contract.ID); err != nil {
+		t.Fatalf("failed to delete attachment: %v", err)
+	}
+	if _, err := env.attachments.GetByID(env.ctx, contract.ID); err == nil {
+		t.Fatalf("expected deleted attachment to be gone")
+	}
+	if err := env.attachments.Delete(env.ctx, contract.ID); err == nil {
+		t.Fatalf("expected deleting a missing attachment to fail")
+	}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// Benchmarks for the hot read paths against a database the size of several
+// years of heavy use. Each runs through the prepared statement cache and,
+// for comparison, with the SQL parsed on every call:
+//
+//	go test ./internal/repository -run '^$' -bench . -benchmem
+
+const (
+	benchEntries = 50000
+	benchClients = 25
+)
+
+// uncachedConn hides the *db.DB from the statement cache, so queries are
+// prepared on every call as they were before it
+type uncachedConn struct {
+	*db.DB
+}
+
+// newBenchDB opens a migrated database holding benchEntries entries spread
+// over benchClients clients, one every half a working hour going back from
+// now
+func newBenchDB(b *testing.B) *db.DB {
+	b.Helper()
+
+	database, err := db.Open(filepath.Join(b.TempDir(), "bench.db"), "bench")
+	if err != nil {
+		b.Fatalf("failed to open database: %v", err)
+	}
+	b.Cleanup(func() { database.Close() })
+	if _, err := database.RunMigrations(); err != nil {
+		b.Fatalf("failed to run migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	clients := &ClientRepo{db: tx}
+	for i := 1; i <= benchClients; i++ {
+		if err := clients.Create(ctx, domain.NewClient(fmt.Sprintf("Client %02d", i), float64(100+i))); err != nil {
+			b.Fatalf("failed to seed client: %v", err)
+		}
+	}
+
+	insert, err := tx.PrepareContext(ctx, `
+		INSERT INTO time_entries (client_id, description, start_time, end_time, duration_seconds, hourly_rate, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 1800, 100, '', ?, ?)
+	`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer insert.Close()
+
+	start := time.Now().Add(-benchEntries * 30 * time.Minute).Truncate(time.Hour)
+	for i := 0; i < benchEntries; i++ {
+		from := start.Add(time.Duration(i) * 30 * time.Minute)
+		to := from.Add(30 * time.Minute)
+		if _, err := insert.ExecContext(ctx, i%benchClients+1, fmt.Sprintf("Task %d", i),
+			formatTimeValue(from), formatTimeValue(to), formatTimeValue(to), formatTimeValue(to)); err != nil {
+			b.Fatalf("failed to seed entry: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	return database
+}
+
+// benchConns runs fn as a sub-benchmark with and without the statement cache
+func benchConns(b *testing.B, database *db.DB, fn func(b *testing.B, c conn)) {
+	b.Run("prepared", func(b *testing.B) { fn(b, database) })
+	b.Run("unprepared", func(b *testing.B) { fn(b, uncachedConn{database}) })
+}
+
+func BenchmarkEntryRepo_ListMonth(b *testing.B) {
+	database := newBenchDB(b)
+	end := time.Now()
+	start := end.AddDate(0, -1, 0)
+
+	benchConns(b, database, func(b *testing.B, c conn) {
+		repo := &EntryRepo{db: c}
+		ctx := context.Background()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.List(ctx, nil, &start, &end, true); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkEntryRepo_GetByID(b *testing.B) {
+	database := newBenchDB(b)
+
+	benchConns(b, database, func(b *testing.B, c conn) {
+		repo := &EntryRepo{db: c}
+		ctx := context.Background()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetByID(ctx, int64(i%benchEntries+1)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkClientRepo_GetByID(b *testing.B) {
+	database := newBenchDB(b)
+
+	benchConns(b, database, func(b *testing.B, c conn) {
+		repo := &ClientRepo{db: c}
+		ctx := context.Background()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetByID(ctx, int64(i%benchClients+1)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -5,14 +5,21 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/andy/timesink/internal/db"
 	"github.com/andy/timesink/internal/domain"
 )
 
+// clientColumns are the clients columns read by scanClient
+const clientColumns = `id, name, email, hourly_rate, notes, is_archived, attach_timesheet,
+		       min_increment_minutes, daily_cap_hours, overtime_multiplier, reverse_charge, require_approval,
+		       language, weekly_demand, version, created_at, updated_at`
+
 // ClientRepo is a SQLite implementation of ClientRepository
 type ClientRepo struct {
-	db *db.DB
+	db conn
 }
 
 // NewClientRepo creates a new ClientRepo
@@ -27,18 +34,34 @@ func (r *ClientRepo) Create(ctx context.Context, client *domain.Client) error {
 	}
 
 	query := `
-		INSERT INTO clients (name, email, hourly_rate, notes, is_archived, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO clients (name, email, hourly_rate, notes, is_archived, attach_timesheet,
+		                     min_increment_minutes, daily_cap_hours, overtime_multiplier, reverse_charge, require_approval,
+		                     language, weekly_demand, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query,
 		client.Name,
 		client.Email,
 		client.HourlyRate,
 		client.Notes,
 		client.IsArchived,
-		client.CreatedAt.Format(timeLayout),
-		client.UpdatedAt.Format(timeLayout),
+		client.AttachTimesheet,
+		client.Billing.MinIncrementMinutes,
+		client.Billing.DailyCapHours,
+		client.Billing.OvertimeMultiplier,
+		client.ReverseCharge,
+		client.RequireApproval,
+		client.Language,
+		client.WeeklyDemand,
+		formatTimeValue(client.CreatedAt),
+		formatTimeValue(client.UpdatedAt),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
@@ -49,31 +72,29 @@ func (r *ClientRepo) Create(ctx context.Context, client *domain.Client) error {
 		return fmt.Errorf("failed to get client ID: %w", err)
 	}
 
+	// The starting rate applies from the day the client was added
+	if err := insertRate(ctx, tx, id, client.HourlyRate, domain.RateDay(client.CreatedAt)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	client.ID = id
+	client.Version = 1
 	return nil
 }
 
 // GetByID retrieves a client by ID
 func (r *ClientRepo) GetByID(ctx context.Context, id int64) (*domain.Client, error) {
 	query := `
-		SELECT id, name, email, hourly_rate, notes, is_archived, created_at, updated_at
+		SELECT ` + clientColumns + `
 		FROM clients
 		WHERE id = ?
 	`
 
-	client := &domain.Client{}
-	var createdAt, updatedAt string
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&client.ID,
-		&client.Name,
-		&client.Email,
-		&client.HourlyRate,
-		&client.Notes,
-		&client.IsArchived,
-		&createdAt,
-		&updatedAt,
-	)
+	client, err := scanClient(queryRowCached(ctx, r.db, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("client not found: %w", err)
@@ -81,64 +102,80 @@ func (r *ClientRepo) GetByID(ctx context.Context, id int64) (*domain.Client, err
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
 
-	if client.CreatedAt, err = parseTime(createdAt); err != nil {
-		return nil, fmt.Errorf("failed to parse created_at: %w", err)
-	}
-	if client.UpdatedAt, err = parseTime(updatedAt); err != nil {
-		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
-	}
-
 	return client, nil
 }
 
-// GetByName retrieves a client by name
+// GetByIDs retrieves the clients with the given IDs, keyed by ID. IDs with
+// no client are left out of the map.
+func (r *ClientRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.Client, error) {
+	clients := make(map[int64]*domain.Client, len(ids))
+	err := forIDChunks(ids, func(chunk []int64) error {
+		query := "SELECT " + clientColumns + " FROM clients WHERE id IN (" + placeholders(len(chunk)) + ")"
+
+		rows, err := r.db.QueryContext(ctx, query, int64Args(chunk)...)
+		if err != nil {
+			return fmt.Errorf("failed to get clients: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			client, err := scanClient(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan client: %w", err)
+			}
+			clients[client.ID] = client
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// GetByName retrieves a client by name, compared as
+// domain.NormalizeClientName folds it, the same way client names are kept
+// unique. Of clients created before names were kept unique, the oldest wins.
+// SQLite folds only ASCII case, so the names are compared here rather than
+// in the query.
 func (r *ClientRepo) GetByName(ctx context.Context, name string) (*domain.Client, error) {
 	query := `
-		SELECT id, name, email, hourly_rate, notes, is_archived, created_at, updated_at
+		SELECT ` + clientColumns + `
 		FROM clients
-		WHERE name = ?
+		ORDER BY id
 	`
 
-	client := &domain.Client{}
-	var createdAt, updatedAt string
-
-	err := r.db.QueryRowContext(ctx, query, name).Scan(
-		&client.ID,
-		&client.Name,
-		&client.Email,
-		&client.HourlyRate,
-		&client.Notes,
-		&client.IsArchived,
-		&createdAt,
-		&updatedAt,
-	)
+	rows, err := queryCached(ctx, r.db, query)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("client not found: %w", err)
-		}
 		return nil, fmt.Errorf("failed to get client: %w", err)
 	}
+	defer rows.Close()
 
-	if client.CreatedAt, err = parseTime(createdAt); err != nil {
-		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	for rows.Next() {
+		client, err := scanClient(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan client: %w", err)
+		}
+		if client.HasName(name) {
+			return client, nil
+		}
 	}
-	if client.UpdatedAt, err = parseTime(updatedAt); err != nil {
-		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating clients: %w", err)
 	}
-
-	return client, nil
+	return nil, fmt.Errorf("client not found: %w", sql.ErrNoRows)
 }
 
 // List retrieves all clients, optionally including archived ones
 func (r *ClientRepo) List(ctx context.Context, includeArchived bool) ([]*domain.Client, error) {
 	query := `
-		SELECT id, name, email, hourly_rate, notes, is_archived, created_at, updated_at
+		SELECT ` + clientColumns + `
 		FROM clients
 		WHERE is_archived = 0 OR ? = 1
 		ORDER BY name
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, includeArchived)
+	rows, err := queryCached(ctx, r.db, query, includeArchived)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list clients: %w", err)
 	}
@@ -146,30 +183,10 @@ func (r *ClientRepo) List(ctx context.Context, includeArchived bool) ([]*domain.
 
 	clients := make([]*domain.Client, 0)
 	for rows.Next() {
-		client := &domain.Client{}
-		var createdAt, updatedAt string
-
-		err := rows.Scan(
-			&client.ID,
-			&client.Name,
-			&client.Email,
-			&client.HourlyRate,
-			&client.Notes,
-			&client.IsArchived,
-			&createdAt,
-			&updatedAt,
-		)
+		client, err := scanClient(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan client: %w", err)
 		}
-
-		if client.CreatedAt, err = parseTime(createdAt); err != nil {
-			return nil, fmt.Errorf("failed to parse created_at: %w", err)
-		}
-		if client.UpdatedAt, err = parseTime(updatedAt); err != nil {
-			return nil, fmt.Errorf("failed to parse updated_at: %w", err)
-		}
-
 		clients = append(clients, client)
 	}
 
@@ -186,22 +203,48 @@ func (r *ClientRepo) Update(ctx context.Context, client *domain.Client) error {
 		return fmt.Errorf("invalid client: %w", err)
 	}
 
-	client.UpdatedAt = client.UpdatedAt // Keep the passed-in time or use time.Now()
+	client.UpdatedAt = time.Now()
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldRate float64
+	err = tx.QueryRowContext(ctx, "SELECT hourly_rate FROM clients WHERE id = ?", client.ID).Scan(&oldRate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("client not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get client rate: %w", err)
+	}
 
 	query := `
 		UPDATE clients
-		SET name = ?, email = ?, hourly_rate = ?, notes = ?, is_archived = ?, updated_at = ?
-		WHERE id = ?
+		SET name = ?, email = ?, hourly_rate = ?, notes = ?, is_archived = ?, attach_timesheet = ?,
+		    min_increment_minutes = ?, daily_cap_hours = ?, overtime_multiplier = ?, reverse_charge = ?, require_approval = ?,
+		    language = ?, weekly_demand = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		client.Name,
 		client.Email,
 		client.HourlyRate,
 		client.Notes,
 		client.IsArchived,
-		client.UpdatedAt.Format(timeLayout),
+		client.AttachTimesheet,
+		client.Billing.MinIncrementMinutes,
+		client.Billing.DailyCapHours,
+		client.Billing.OvertimeMultiplier,
+		client.ReverseCharge,
+		client.RequireApproval,
+		client.Language,
+		client.WeeklyDemand,
+		formatTimeValue(client.UpdatedAt),
 		client.ID,
+		client.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update client: %w", err)
@@ -212,9 +255,21 @@ func (r *ClientRepo) Update(ctx context.Context, client *domain.Client) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("client not found")
+		return missedUpdate(ctx, tx, "clients", "client", client.ID, client.Version, fmt.Errorf("client not found"))
+	}
+
+	// A changed rate applies from today; earlier entries keep the old one
+	if client.HourlyRate != oldRate {
+		if err := insertRate(ctx, tx, client.ID, client.HourlyRate, domain.RateDay(client.UpdatedAt)); err != nil {
+			return err
+		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	client.Version++
 	return nil
 }
 
@@ -265,3 +320,264 @@ func (r *ClientRepo) Unarchive(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// SetRate records a client's rate from the start of the effectiveFrom day
+// onwards, replacing any rate recorded for that same day. The client's
+// current rate is updated to match the history.
+func (r *ClientRepo) SetRate(ctx context.Context, clientID int64, rate float64, effectiveFrom time.Time) error {
+	if rate < 0 {
+		return fmt.Errorf("invalid rate: hourly rate cannot be negative")
+	}
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Recording the rate already in effect that day would change nothing
+	day := domain.RateDay(effectiveFrom)
+	existing, err := rateAt(ctx, tx, clientID, day)
+	if err != nil {
+		return err
+	}
+	if existing == rate {
+		return nil
+	}
+	if err := insertRate(ctx, tx, clientID, rate, day); err != nil {
+		return err
+	}
+
+	current, err := rateAt(ctx, tx, clientID, time.Now())
+	if err != nil {
+		return err
+	}
+	query := `
+		UPDATE clients
+		SET hourly_rate = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := tx.ExecContext(ctx, query, current, formatTime(), clientID)
+	if err != nil {
+		return fmt.Errorf("failed to update client rate: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("client not found")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RateAt returns the client's rate in effect at the given time. Times before
+// the first recorded rate use that first rate.
+func (r *ClientRepo) RateAt(ctx context.Context, clientID int64, at time.Time) (float64, error) {
+	return rateAt(ctx, r.db, clientID, at)
+}
+
+// ListRates returns the client's rate history, newest first
+func (r *ClientRepo) ListRates(ctx context.Context, clientID int64) ([]*domain.ClientRate, error) {
+	query := `
+		SELECT id, client_id, hourly_rate, effective_from, created_at
+		FROM client_rate_history
+		WHERE client_id = ?
+		ORDER BY effective_from DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := make([]*domain.ClientRate, 0)
+	for rows.Next() {
+		rate := &domain.ClientRate{}
+		var effectiveFrom, createdAt string
+
+		if err := rows.Scan(&rate.ID, &rate.ClientID, &rate.HourlyRate, &effectiveFrom, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan client rate: %w", err)
+		}
+
+		if rate.EffectiveFrom, err = parseTime(effectiveFrom); err != nil {
+			return nil, fmt.Errorf("failed to parse effective_from: %w", err)
+		}
+		if rate.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating client rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// SetRoleRate adds a role to the client's rate card, or changes its rate.
+// Entries already logged for the role keep the rate they were billed at.
+func (r *ClientRepo) SetRoleRate(ctx context.Context, role *domain.RoleRate) error {
+	if err := role.Validate(); err != nil {
+		return err
+	}
+	role.Role = strings.TrimSpace(role.Role)
+	role.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO client_role_rates (client_id, role, hourly_rate, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (client_id, role) DO UPDATE SET hourly_rate = excluded.hourly_rate
+	`
+	if _, err := r.db.ExecContext(ctx, query, role.ClientID, role.Role, role.HourlyRate, formatTimeValue(role.CreatedAt)); err != nil {
+		return fmt.Errorf("failed to set role rate: %w", err)
+	}
+
+	// The role may already be on the card under another case
+	err := r.db.QueryRowContext(ctx, `SELECT id, role FROM client_role_rates WHERE client_id = ? AND role = ?`,
+		role.ClientID, role.Role).Scan(&role.ID, &role.Role)
+	if err != nil {
+		return fmt.Errorf("failed to get role rate: %w", err)
+	}
+	return nil
+}
+
+// DeleteRoleRate removes a role from the client's rate card, ignoring case
+func (r *ClientRepo) DeleteRoleRate(ctx context.Context, clientID int64, role string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM client_role_rates WHERE client_id = ? AND role = ?`,
+		clientID, strings.TrimSpace(role))
+	if err != nil {
+		return fmt.Errorf("failed to delete role rate: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("role %q not found on the client's rate card", role)
+	}
+	return nil
+}
+
+// ListRoleRates returns the client's rate card, by role name
+func (r *ClientRepo) ListRoleRates(ctx context.Context, clientID int64) ([]*domain.RoleRate, error) {
+	query := `
+		SELECT id, client_id, role, hourly_rate, created_at
+		FROM client_role_rates
+		WHERE client_id = ?
+		ORDER BY role
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role rates: %w", err)
+	}
+	defer rows.Close()
+
+	card := make([]*domain.RoleRate, 0)
+	for rows.Next() {
+		role := &domain.RoleRate{}
+		var createdAt string
+		if err := rows.Scan(&role.ID, &role.ClientID, &role.Role, &role.HourlyRate, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role rate: %w", err)
+		}
+		if role.CreatedAt, err = parseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		card = append(card, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating role rates: %w", err)
+	}
+
+	return card, nil
+}
+
+// insertRate records a rate from effectiveFrom, replacing one from the same time
+func insertRate(ctx context.Context, c conn, clientID int64, rate float64, effectiveFrom time.Time) error {
+	query := `
+		INSERT INTO client_rate_history (client_id, hourly_rate, effective_from, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (client_id, effective_from) DO UPDATE SET hourly_rate = excluded.hourly_rate, created_at = excluded.created_at
+	`
+
+	if _, err := c.ExecContext(ctx, query, clientID, rate, formatTimeValue(effectiveFrom), formatTime()); err != nil {
+		return fmt.Errorf("failed to record client rate: %w", err)
+	}
+	return nil
+}
+
+// rateAt looks up the rate in effect at a time, falling back to the earliest
+// recorded rate and then to the client's current rate
+func rateAt(ctx context.Context, c conn, clientID int64, at time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(
+			(SELECT hourly_rate FROM client_rate_history
+			 WHERE client_id = ? AND effective_from <= ?
+			 ORDER BY effective_from DESC LIMIT 1),
+			(SELECT hourly_rate FROM client_rate_history
+			 WHERE client_id = ?
+			 ORDER BY effective_from LIMIT 1),
+			(SELECT hourly_rate FROM clients WHERE id = ?)
+		)
+	`
+
+	var rate sql.NullFloat64
+	err := c.QueryRowContext(ctx, query, clientID, formatTimeValue(at), clientID, clientID).Scan(&rate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get client rate: %w", err)
+	}
+	if !rate.Valid {
+		return 0, fmt.Errorf("client not found")
+	}
+	return rate.Float64, nil
+}
+
+// scanClient reads a row of clientColumns
+func scanClient(row rowScanner) (*domain.Client, error) {
+	client := &domain.Client{}
+	var createdAt, updatedAt string
+
+	err := row.Scan(
+		&client.ID,
+		&client.Name,
+		&client.Email,
+		&client.HourlyRate,
+		&client.Notes,
+		&client.IsArchived,
+		&client.AttachTimesheet,
+		&client.Billing.MinIncrementMinutes,
+		&client.Billing.DailyCapHours,
+		&client.Billing.OvertimeMultiplier,
+		&client.ReverseCharge,
+		&client.RequireApproval,
+		&client.Language,
+		&client.WeeklyDemand,
+		&client.Version,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if client.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestClientRepo_CreateAndGet(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 125)
+
+	byID, err := env.clients.GetByID(env.ctx, client.ID)
+	if err != nil {
+		t.Fatalf("failed to get client: %v", err)
+	}
+	if byID.Name != "Acme" || byID.HourlyRate != 125 {
+		t.Fatalf("unexpected client: %+v", byID)
+	}
+
+	byName, err := env.clients.GetByName(env.ctx, "Acme")
+	if err != nil {
+		t.Fatalf("failed to get client by name: %v", err)
+	}
+	if byName.ID != client.ID {
+		t.Fatalf("expected ID %d, got %d", client.ID, byName.ID)
+	}
+	byName, err = env.clients.GetByName(env.ctx, " ACME ")
+	if err != nil {
+		t.Fatalf("failed to get client by name ignoring case: %v", err)
+	}
+	if byName.ID != client.ID {
+		t.Fatalf("expected ID %d ignoring case, got %d", client.ID, byName.ID)
+	}
+
+	// Inner spacing and non-ASCII case fold as domain.NormalizeClientName does
+	united := env.client("Ünited  Works", 100)
+	for _, name := range []string{"ünited works", "ÜNITED   WORKS"} {
+		byName, err = env.clients.GetByName(env.ctx, name)
+		if err != nil {
+			t.Fatalf("failed to get client by name %q: %v", name, err)
+		}
+		if byName.ID != united.ID {
+			t.Fatalf("expected ID %d for %q, got %d", united.ID, name, byName.ID)
+		}
+	}
+	if _, err := env.clients.GetByName(env.ctx, "Acme Corp"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows for an unknown name, got %v", err)
+	}
+
+	if _, err := env.clients.GetByID(env.ctx, client.ID+100); err == nil {
+		t.Fatalf("expected error for missing client")
+	}
+}
+
+func TestClientRepo_GetByIDs(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	globex := env.client("Globex", 120)
+	env.client("Initech", 90)
+
+	got, err := env.clients.GetByIDs(env.ctx, []int64{globex.ID, acme.ID, 999})
+	if err != nil {
+		t.Fatalf("failed to get clients: %v", err)
+	}
+	if len(got) != 2 || got[acme.ID].Name != "Acme" || got[globex.ID].HourlyRate != 120 {
+		t.Fatalf("expected Acme and Globex only, got %+v", got)
+	}
+}
+
+func TestClientRepo_UpdatePersistsFields(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	client.Email = "billing@acme.test"
+	client.HourlyRate = 150
+	client.AttachTimesheet = true
+	if err := env.clients.Update(env.ctx, client); err != nil {
+		t.Fatalf("failed to update client: %v", err)
+	}
+
+	got, err := env.clients.GetByID(env.ctx, client.ID)
+	if err != nil {
+		t.Fatalf("failed to get client: %v", err)
+	}
+	if got.Email != "billing@acme.test" || got.HourlyRate != 150 || !got.AttachTimesheet {
+		t.Fatalf("update not persisted: %+v", got)
+	}
+}
+
+func TestClientRepo_UpdateRefusesStaleCopy(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	// Archived elsewhere after this copy was read
+	if err := env.clients.Archive(env.ctx, client.ID); err != nil {
+		t.Fatalf("failed to archive client: %v", err)
+	}
+	client.Email = "billing@acme.test"
+	if err := env.clients.Update(env.ctx, client); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	fresh, _ := env.clients.GetByID(env.ctx, client.ID)
+	fresh.Email = "billing@acme.test"
+	if err := env.clients.Update(env.ctx, fresh); err != nil {
+		t.Fatalf("failed to update a fresh copy: %v", err)
+	}
+	// The updated copy can be saved again
+	fresh.Notes = "Net 30"
+	if err := env.clients.Update(env.ctx, fresh); err != nil {
+		t.Fatalf("failed to update twice: %v", err)
+	}
+
+	got, _ := env.clients.GetByID(env.ctx, client.ID)
+	if !got.IsArchived || got.Email != "billing@acme.test" || got.Notes != "Net 30" {
+		t.Fatalf("expected the archive and both updates kept, got %+v", got)
+	}
+}
+
+func TestClientRepo_ListArchivedFilter(t *testing.T) {
+	env := newTestEnv(t)
+	env.client("Beta", 100)
+	archived := env.client("Alpha", 100)
+
+	if err := env.clients.Archive(env.ctx, archived.ID); err != nil {
+		t.Fatalf("failed to archive client: %v", err)
+	}
+
+	active, err := env.clients.List(env.ctx, false)
+	if err != nil {
+		t.Fatalf("failed to list clients: %v", err)
+	}
+	if len(active) != 1 || active[0].Name != "Beta" {
+		t.Fatalf("expected only Beta, got %d clients", len(active))
+	}
+
+	all, err := env.clients.List(env.ctx, true)
+	if err != nil {
+		t.Fatalf("failed to list clients: %v", err)
+	}
+	if len(all) != 2 || all[0].Name != "Alpha" {
+		t.Fatalf("expected both clients ordered by name, got %d", len(all))
+	}
+
+	if err := env.clients.Unarchive(env.ctx, archived.ID); err != nil {
+		t.Fatalf("failed to unarchive client: %v", err)
+	}
+	active, _ = env.clients.List(env.ctx, false)
+	if len(active) != 2 {
+		t.Fatalf("expected unarchived client to be listed, got %d", len(active))
+	}
+}
+
+func TestClientRepo_DuplicateNameRejected(t *testing.T) {
+	env := newTestEnv(t)
+	env.client("Acme", 100)
+
+	if err := env.clients.Create(env.ctx, domain.NewClient("Acme", 50)); err == nil {
+		t.Fatalf("expected duplicate client name to be rejected")
+	}
+}
+
+func TestClientRepo_RateHistory(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	// A past rate applies to its dates, and to anything before the first rate
+	if err := env.clients.SetRate(env.ctx, client.ID, 80, day(1)); err != nil {
+		t.Fatalf("failed to set past rate: %v", err)
+	}
+	// A scheduled rate leaves the current rate alone until it takes effect
+	next := time.Now().AddDate(0, 1, 0)
+	if err := env.clients.SetRate(env.ctx, client.ID, 150, next); err != nil {
+		t.Fatalf("failed to set future rate: %v", err)
+	}
+
+	for _, tc := range []struct {
+		at   time.Time
+		want float64
+	}{
+		{day(1).AddDate(-1, 0, 0), 80},
+		{day(10), 80},
+		{time.Now(), 100},
+		{next.AddDate(0, 0, 1), 150},
+	} {
+		got, err := env.clients.RateAt(env.ctx, client.ID, tc.at)
+		if err != nil {
+			t.Fatalf("failed to get rate: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("rate at %s = %.2f, want %.2f", tc.at.Format(time.DateOnly), got, tc.want)
+		}
+	}
+
+	current, _ := env.clients.GetByID(env.ctx, client.ID)
+	if current.HourlyRate != 100 {
+		t.Fatalf("expected current rate 100, got %.2f", current.HourlyRate)
+	}
+
+	// Repeating the rate already in effect records nothing
+	if err := env.clients.SetRate(env.ctx, client.ID, 80, day(5)); err != nil {
+		t.Fatalf("failed to set rate: %v", err)
+	}
+
+	rates, err := env.clients.ListRates(env.ctx, client.ID)
+	if err != nil {
+		t.Fatalf("failed to list rates: %v", err)
+	}
+	if len(rates) != 3 || rates[0].HourlyRate != 150 || rates[2].HourlyRate != 80 {
+		t.Fatalf("expected 3 rates newest first, got %d", len(rates))
+	}
+}
+
+func TestClientRepo_RateCard(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	other := env.client("Globex", 90)
+
+	for _, role := range []*domain.RoleRate{
+		{ClientID: client.ID, Role: "development", HourlyRate: 120},
+		{ClientID: client.ID, Role: "consulting", HourlyRate: 150},
+		{ClientID: other.ID, Role: "consulting", HourlyRate: 95},
+	} {
+		if err := env.clients.SetRoleRate(env.ctx, role); err != nil {
+			t.Fatalf("failed to set role rate: %v", err)
+		}
+	}
+
+	// Setting a role again, in any case, changes its rate and keeps its name
+	changed := &domain.RoleRate{ClientID: client.ID, Role: " Consulting ", HourlyRate: 160}
+	if err := env.clients.SetRoleRate(env.ctx, changed); err != nil {
+		t.Fatalf("failed to change role rate: %v", err)
+	}
+	if changed.Role != "consulting" || changed.ID == 0 {
+		t.Fatalf("expected the existing role back, got %+v", changed)
+	}
+
+	card, err := env.clients.ListRoleRates(env.ctx, client.ID)
+	if err != nil {
+		t.Fatalf("failed to list rate card: %v", err)
+	}
+	if len(card) != 2 || card[0].Role != "consulting" || card[0].HourlyRate != 160 || card[1].Role != "development" {
+		t.Fatalf("expected consulting at 160 and development, got %d roles", len(card))
+	}
+
+	if err := env.clients.DeleteRoleRate(env.ctx, client.ID, "DEVELOPMENT"); err != nil {
+		t.Fatalf("failed to delete role: %v", err)
+	}
+	if err := env.clients.DeleteRoleRate(env.ctx, client.ID, "development"); err == nil {
+		t.Fatalf("expected an error deleting a role not on the card")
+	}
+	if card, _ := env.clients.ListRoleRates(env.ctx, other.ID); len(card) != 1 || card[0].HourlyRate != 95 {
+		t.Fatalf("expected the other client's card untouched, got %d roles", len(card))
+	}
+}
+
+func TestClientRepo_UpdateRecordsRateChange(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	client.Email = "billing@acme.test"
+	if err := env.clients.Update(env.ctx, client); err != nil {
+		t.Fatalf("failed to update client: %v", err)
+	}
+	rates, _ := env.clients.ListRates(env.ctx, client.ID)
+	if len(rates) != 1 {
+		t.Fatalf("expected only the starting rate, got %d", len(rates))
+	}
+
+	client.HourlyRate = 120
+	if err := env.clients.Update(env.ctx, client); err != nil {
+		t.Fatalf("failed to update client: %v", err)
+	}
+	// Created and changed on the same day, so the new rate replaces the first
+	rates, _ = env.clients.ListRates(env.ctx, client.ID)
+	if len(rates) != 1 || rates[0].HourlyRate != 120 {
+		t.Fatalf("expected today's rate to be replaced, got %d rates", len(rates))
+	}
+	if rate, _ := env.clients.RateAt(env.ctx, client.ID, day(1)); rate != 120 {
+		t.Fatalf("expected earlier dates to fall back to the first rate, got %.2f", rate)
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrConflict is returned by Update when the row was written after it was
+// read, usually by another timesink process. Reading it again and reapplying
+// the change resolves it.
+var ErrConflict = errors.New("update conflict")
+
+// missedUpdate explains an Update of the row in table that matched nothing:
+// either its version moved on since it was read, or the row is gone, in
+// which case notFound is returned
+func missedUpdate(ctx context.Context, c conn, table, what string, id, readVersion int64, notFound error) error {
+	var version int64
+	err := c.QueryRowContext(ctx, "SELECT version FROM "+table+" WHERE id = ?", id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check %s version: %w", what, err)
+	}
+	if version == readVersion {
+		// Unchanged, so the update's other conditions ruled it out
+		return notFound
+	}
+	return fmt.Errorf("%w: %s %d was changed after it was read; reload it and try again", ErrConflict, what, id)
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// ContractRepo is a SQLite implementation of ContractRepository
+type ContractRepo struct {
+	db conn
+}
+
+// NewContractRepo creates a new ContractRepo
+func NewContractRepo(database *db.DB) *ContractRepo {
+	return &ContractRepo{db: database}
+}
+
+const contractColumns = `id, client_id, start_date, end_date, rate, scope, renews, created_at, updated_at`
+
+// Create inserts a new contract
+func (r *ContractRepo) Create(ctx context.Context, contract *domain.Contract) error {
+	if err := contract.Validate(); err != nil {
+		return fmt.Errorf("invalid contract: %w", err)
+	}
+
+	query := `
+		INSERT INTO contracts (client_id, start_date, end_date, rate, scope, renews, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		contract.ClientID,
+		formatTimeValue(contract.StartDate),
+		formatTimeValue(contract.EndDate),
+		contract.Rate,
+		contract.Scope,
+		contract.Renews,
+		formatTimeValue(contract.CreatedAt),
+		formatTimeValue(contract.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create contract: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get contract ID: %w", err)
+	}
+
+	contract.ID = id
+	return nil
+}
+
+// GetByID retrieves a contract by ID
+func (r *ContractRepo) GetByID(ctx context.Context, id int64) (*domain.Contract, error) {
+	query := `SELECT ` + contractColumns + ` FROM contracts WHERE id = ?`
+
+	contract, err := scanContract(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("contract not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	return contract, nil
+}
+
+// List retrieves contracts, optionally for a single client, ordered by end
+// date with the soonest first
+func (r *ContractRepo) List(ctx context.Context, clientID *int64) ([]*domain.Contract, error) {
+	query := `SELECT ` + contractColumns + ` FROM contracts`
+	args := []interface{}{}
+	if clientID != nil {
+		query += ` WHERE client_id = ?`
+		args = append(args, *clientID)
+	}
+	query += ` ORDER BY end_date, id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+	defer rows.Close()
+
+	contracts := make([]*domain.Contract, 0)
+	for rows.Next() {
+		contract, err := scanContract(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contract: %w", err)
+		}
+		contracts = append(contracts, contract)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating contracts: %w", err)
+	}
+
+	return contracts, nil
+}
+
+// Update saves changes to a contract
+func (r *ContractRepo) Update(ctx context.Context, contract *domain.Contract) error {
+	if err := contract.Validate(); err != nil {
+		return fmt.Errorf("invalid contract: %w", err)
+	}
+
+	query := `
+		UPDATE contracts
+		SET start_date = ?, end_date = ?, rate = ?, scope = ?, renews = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		formatTimeValue(contract.StartDate),
+		formatTimeValue(contract.EndDate),
+		contract.Rate,
+		contract.Scope,
+		contract.Renews,
+		formatTimeValue(contract.UpdatedAt),
+		contract.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update contract: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("contract not found")
+	}
+
+	return nil
+}
+
+// Delete removes a contract
+func (r *ContractRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM contracts WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete contract: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("contract not found")
+	}
+
+	return nil
+}
+
+// scanContract reads one contract row
+func scanContract(row interface{ Scan(...any) error }) (*domain.Contract, error) {
+	contract := &domain.Contract{}
+	var startDate, endDate, createdAt, updatedAt string
+
+	err := row.Scan(
+		&contract.ID,
+		&contract.ClientID,
+		&startDate,
+		&endDate,
+		&contract.Rate,
+		&contract.Scope,
+		&contract.Renews,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if contract.StartDate, err = parseTime(startDate); err != nil {
+		return nil, fmt.Errorf("failed to parse start_date: %w", err)
+	}
+	if contract.EndDate, err = parseTime(endDate); err != nil {
+		return nil, fmt.Errorf("failed to parse end_date: %w", err)
+	}
+	if contract.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if contract.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+
+	return contract, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestContractRepo_CreateListUpdateDelete(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	globex := env.client("Globex", 120)
+
+	later := domain.NewContract(acme.ID, day(1), day(30))
+	later.Rate = 110
+	later.Scope = "Phase 2"
+	sooner := domain.NewContract(acme.ID, day(1), day(10))
+	other := domain.NewContract(globex.ID, day(1), day(5))
+	for _, c := range []*domain.Contract{later, sooner, other} {
+		if err := env.contracts.Create(env.ctx, c); err != nil {
+			t.Fatalf("failed to create contract: %v", err)
+		}
+	}
+
+	got, err := env.contracts.GetByID(env.ctx, later.ID)
+	if err != nil {
+		t.Fatalf("failed to get contract: %v", err)
+	}
+	if got.Rate != 110 || got.Scope != "Phase 2" || got.Renews {
+		t.Fatalf("unexpected contract: %+v", got)
+	}
+	if !got.StartDate.Equal(domain.RateDay(day(1))) || !got.EndDate.Equal(domain.RateDay(day(30))) {
+		t.Fatalf("expected dates to round-trip as days, got %v to %v", got.StartDate, got.EndDate)
+	}
+
+	list, err := env.contracts.List(env.ctx, &acme.ID)
+	if err != nil {
+		t.Fatalf("failed to list contracts: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != sooner.ID || list[1].ID != later.ID {
+		t.Fatalf("expected Acme's contracts soonest end first, got %+v", list)
+	}
+	all, err := env.contracts.List(env.ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to list all contracts: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != other.ID {
+		t.Fatalf("expected all contracts soonest end first, got %+v", all)
+	}
+
+	got.Renews = true
+	got.EndDate = domain.RateDay(day(31))
+	if err := env.contracts.Update(env.ctx, got); err != nil {
+		t.Fatalf("failed to update contract: %v", err)
+	}
+	if got, _ = env.contracts.GetByID(env.ctx, later.ID); !got.Renews || !got.EndDate.Equal(domain.RateDay(day(31))) {
+		t.Fatalf("expected update to be saved, got %+v", got)
+	}
+
+	if err := env.contracts.Delete(env.ctx, sooner.ID); err != nil {
+		t.Fatalf("failed to delete contract: %v", err)
+	}
+	if err := env.contracts.Delete(env.ctx, sooner.ID); err == nil {
+		t.Fatal("expected deleting a missing contract to fail")
+	}
+}
+
+func TestContractRepo_RejectsEndBeforeStart(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	if err := env.contracts.Create(env.ctx, domain.NewContract(client.ID, day(10), day(9))); err == nil {
+		t.Fatal("expected a contract ending before it starts to be rejected")
+	}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// deliveryColumns are the invoice_deliveries columns read by scanDelivery
+const deliveryColumns = "id, invoice_id, recipient, message_id, sent_at, opened_at, created_at"
+
+// DeliveryRepo is a SQLite implementation of DeliveryRepository
+type DeliveryRepo struct {
+	db conn
+}
+
+// NewDeliveryRepo creates a new DeliveryRepo
+func NewDeliveryRepo(database *db.DB) *DeliveryRepo {
+	return &DeliveryRepo{db: database}
+}
+
+// Create records a delivery of an invoice
+func (r *DeliveryRepo) Create(ctx context.Context, delivery *domain.InvoiceDelivery) error {
+	if err := delivery.Validate(); err != nil {
+		return fmt.Errorf("invalid delivery: %w", err)
+	}
+
+	query := `
+		INSERT INTO invoice_deliveries (invoice_id, recipient, message_id, sent_at, opened_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		delivery.InvoiceID,
+		delivery.Recipient,
+		delivery.MessageID,
+		formatTimeValue(delivery.SentAt),
+		deliveryOpenedAt(delivery),
+		formatTimeValue(delivery.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get delivery ID: %w", err)
+	}
+
+	delivery.ID = id
+	return nil
+}
+
+// GetByID retrieves a delivery by ID
+func (r *DeliveryRepo) GetByID(ctx context.Context, id int64) (*domain.InvoiceDelivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM invoice_deliveries WHERE id = ?"
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("delivery not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// GetByMessageID retrieves the most recent delivery sent with a message ID
+func (r *DeliveryRepo) GetByMessageID(ctx context.Context, messageID string) (*domain.InvoiceDelivery, error) {
+	query := "SELECT " + deliveryColumns + ` FROM invoice_deliveries
+		WHERE message_id = ? AND message_id != ''
+		ORDER BY sent_at DESC, id DESC
+		LIMIT 1`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, messageID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("delivery not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// List retrieves the deliveries of an invoice, oldest first
+func (r *DeliveryRepo) List(ctx context.Context, invoiceID int64) ([]*domain.InvoiceDelivery, error) {
+	query := "SELECT " + deliveryColumns + ` FROM invoice_deliveries
+		WHERE invoice_id = ?
+		ORDER BY sent_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domain.InvoiceDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// SetOpened stores when a delivery was opened
+func (r *DeliveryRepo) SetOpened(ctx context.Context, delivery *domain.InvoiceDelivery) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE invoice_deliveries SET opened_at = ? WHERE id = ?",
+		deliveryOpenedAt(delivery), delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("delivery not found")
+	}
+
+	return nil
+}
+
+// deliveryOpenedAt returns opened_at as NULL when the delivery is unopened
+func deliveryOpenedAt(delivery *domain.InvoiceDelivery) interface{} {
+	if delivery.OpenedAt == nil {
+		return nil
+	}
+	return formatTimeValue(*delivery.OpenedAt)
+}
+
+// scanDelivery reads one delivery row selected with deliveryColumns
+func scanDelivery(row interface{ Scan(...any) error }) (*domain.InvoiceDelivery, error) {
+	delivery := &domain.InvoiceDelivery{}
+	var sentAt, createdAt string
+	var openedAt sql.NullString
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.InvoiceID,
+		&delivery.Recipient,
+		&delivery.MessageID,
+		&sentAt,
+		&openedAt,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.SentAt, err = parseTime(sentAt); err != nil {
+		return nil, fmt.Errorf("failed to parse sent_at: %w", err)
+	}
+	if openedAt.Valid {
+		t, err := parseTime(openedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse opened_at: %w", err)
+		}
+		delivery.OpenedAt = &t
+	}
+	if delivery.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestDeliveryRepo_LogAndOpen(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001")
+
+	first := &domain.InvoiceDelivery{InvoiceID: invoice.ID, Recipient: "ap@acme.test", MessageID: "msg-1", SentAt: day(2), CreatedAt: day(2)}
+	resent := &domain.InvoiceDelivery{InvoiceID: invoice.ID, Recipient: "ap@acme.test", MessageID: "msg-2", SentAt: day(9), CreatedAt: day(9)}
+	for _, d := range []*domain.InvoiceDelivery{resent, first} {
+		if err := env.deliveries.Create(env.ctx, d); err != nil {
+			t.Fatalf("failed to create delivery: %v", err)
+		}
+	}
+
+	list, err := env.deliveries.List(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != first.ID || list[0].OpenedAt != nil {
+		t.Fatalf("expected unopened deliveries oldest first, got %+v", list)
+	}
+
+	got, err := env.deliveries.GetByMessageID(env.ctx, "msg-2")
+	if err != nil {
+		t.Fatalf("failed to find delivery by message ID: %v", err)
+	}
+	if got.ID != resent.ID || got.Recipient != "ap@acme.test" || !got.SentAt.Equal(day(9)) {
+		t.Fatalf("unexpected delivery: %+v", got)
+	}
+	if _, err := env.deliveries.GetByMessageID(env.ctx, ""); err == nil {
+		t.Fatalf("expected an empty message ID not to match")
+	}
+
+	got.MarkOpened(day(10))
+	if err := env.deliveries.SetOpened(env.ctx, got); err != nil {
+		t.Fatalf("failed to set opened: %v", err)
+	}
+	got, _ = env.deliveries.GetByID(env.ctx, resent.ID)
+	if got.OpenedAt == nil || !got.OpenedAt.Equal(day(10)) {
+		t.Fatalf("expected opened_at to round-trip, got %v", got.OpenedAt)
+	}
+}
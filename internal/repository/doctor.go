@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// Consistency checks run by Doctor
+const (
+	CheckLineItemAmount   = "line_item_amount"
+	CheckLineItemEntry    = "line_item_entry"
+	CheckEntryLock        = "entry_lock"
+	CheckInvoiceTotals    = "invoice_totals"
+	CheckOrphanHistory    = "orphan_history"
+	CheckNegativeDuration = "negative_duration"
+)
+
+// Issue is a broken invariant found by Doctor
+type Issue struct {
+	Check   string
+	Subject string // what is broken, e.g. "entry 12"
+	Detail  string
+	Fixable bool
+
+	fix func(ctx context.Context, c conn) error
+}
+
+// Doctor verifies invariants that the schema alone does not enforce and
+// repairs the ones with an unambiguous fix
+type Doctor struct {
+	db *db.DB
+}
+
+// NewDoctor creates a new Doctor
+func NewDoctor(database *db.DB) *Doctor {
+	return &Doctor{db: database}
+}
+
+// Check runs every consistency check and returns the issues found
+func (d *Doctor) Check(ctx context.Context) ([]Issue, error) {
+	checks := []func(context.Context) ([]Issue, error){
+		d.checkLineItems,
+		d.checkEntryLocks,
+		d.checkInvoiceTotals,
+		d.checkOrphanHistory,
+		d.checkNegativeDurations,
+	}
+
+	var issues []Issue
+	for _, check := range checks {
+		found, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+
+	return issues, nil
+}
+
+// Fix repairs every fixable issue in a single transaction and returns the
+// number repaired
+func (d *Doctor) Fix(ctx context.Context, issues []Issue) (int, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	fixed := 0
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		if err := issue.fix(ctx, tx); err != nil {
+			return 0, fmt.Errorf("failed to fix %s (%s): %w", issue.Check, issue.Subject, err)
+		}
+		fixed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return fixed, nil
+}
+
+// checkLineItems compares each line item with the entry it was billed from.
+// Draft line items are refreshed from the entry; issued invoices are only
+// reported, since the client already has the stored figures. Adjustments
+// derived from billing rules have no entry and are skipped.
+func (d *Doctor) checkLineItems(ctx context.Context) ([]Issue, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT li.id, li.invoice_id, i.invoice_number, i.status, li.entry_id,
+		       li.hours, li.amount, e.id, e.start_time, e.end_time, e.pauses, e.hourly_rate, e.is_billable
+		FROM invoice_line_items li
+		JOIN invoices i ON i.id = li.invoice_id
+		LEFT JOIN time_entries e ON e.id = li.entry_id
+		WHERE li.entry_id IS NOT NULL
+		ORDER BY li.invoice_id, li.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check line items: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var itemID, invoiceID, entryID int64
+		var number, status string
+		var hours float64
+		var amount domain.Money
+		var foundID sql.NullInt64
+		var start, end, pauses sql.NullString
+		var rate sql.NullFloat64
+		var billable sql.NullBool
+
+		if err := rows.Scan(&itemID, &invoiceID, &number, &status, &entryID,
+			&hours, &amount, &foundID, &start, &end, &pauses, &rate, &billable); err != nil {
+			return nil, fmt.Errorf("failed to scan line item: %w", err)
+		}
+		subject := fmt.Sprintf("invoice %s, line item %d", number, itemID)
+
+		if !foundID.Valid {
+			issues = append(issues, Issue{
+				Check:   CheckLineItemEntry,
+				Subject: subject,
+				Detail:  fmt.Sprintf("entry %d no longer exists", entryID),
+			})
+			continue
+		}
+		if !start.Valid || !end.Valid {
+			continue
+		}
+
+		entry := &domain.TimeEntry{HourlyRate: rate.Float64, IsBillable: billable.Bool}
+		if entry.StartTime, err = parseTime(start.String); err != nil {
+			return nil, fmt.Errorf("failed to parse start_time: %w", err)
+		}
+		endTime, err := parseTime(end.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse end_time: %w", err)
+		}
+		entry.EndTime = &endTime
+		if entry.Pauses, err = parsePauses(pauses.String); err != nil {
+			return nil, fmt.Errorf("failed to parse pauses: %w", err)
+		}
+
+		want := entry.Amount()
+		if want == amount {
+			continue
+		}
+
+		draft := domain.InvoiceStatus(status) == domain.InvoiceStatusDraft
+		wantHours, wantRate := entry.Duration().Hours(), entry.HourlyRate
+		issues = append(issues, Issue{
+			Check:   CheckLineItemAmount,
+			Subject: subject,
+			Detail:  fmt.Sprintf("stored amount %.2f, entry %d now comes to %.2f", amount.Float(), entryID, want.Float()),
+			Fixable: draft,
+			fix: func(ctx context.Context, c conn) error {
+				_, err := c.ExecContext(ctx, `
+					UPDATE invoice_line_items SET hours = ?, rate = ?, amount = ? WHERE id = ?
+				`, wantHours, wantRate, want, itemID)
+				if err != nil {
+					return err
+				}
+				return recalcInvoiceTotals(ctx, c, invoiceID)
+			},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating line items: %w", err)
+	}
+
+	return issues, nil
+}
+
+// checkEntryLocks finds entries locked to invoices that do not exist or are
+// still drafts. Drafts never lock entries, so such locks are left over from
+// an interrupted finalize and can be released.
+func (d *Doctor) checkEntryLocks(ctx context.Context) ([]Issue, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT e.id, e.invoice_id, i.invoice_number, i.status
+		FROM time_entries e
+		LEFT JOIN invoices i ON i.id = e.invoice_id
+		WHERE e.invoice_id IS NOT NULL
+		  AND (i.id IS NULL OR i.status = ?)
+		ORDER BY e.id
+	`, string(domain.InvoiceStatusDraft))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check entry locks: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var entryID, invoiceID int64
+		var number, status sql.NullString
+		if err := rows.Scan(&entryID, &invoiceID, &number, &status); err != nil {
+			return nil, fmt.Errorf("failed to scan entry: %w", err)
+		}
+
+		detail := fmt.Sprintf("locked to invoice %d, which does not exist", invoiceID)
+		if number.Valid {
+			detail = fmt.Sprintf("locked to draft invoice %s", number.String)
+		}
+		issues = append(issues, Issue{
+			Check:   CheckEntryLock,
+			Subject: fmt.Sprintf("entry %d", entryID),
+			Detail:  detail,
+			Fixable: true,
+			fix: func(ctx context.Context, c conn) error {
+				_, err := c.ExecContext(ctx, `
+					UPDATE time_entries SET invoice_id = NULL, updated_at = ? WHERE id = ?
+				`, formatTime(), entryID)
+				return err
+			},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entries: %w", err)
+	}
+
+	return issues, nil
+}
+
+// checkInvoiceTotals recalculates each invoice from its line items
+func (d *Doctor) checkInvoiceTotals(ctx context.Context) ([]Issue, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT i.id, i.invoice_number, i.status, i.subtotal, i.tax_rate, i.tax_amount, i.total,
+		       COALESCE((SELECT SUM(amount) FROM invoice_line_items WHERE invoice_id = i.id), 0)
+		FROM invoices i
+		ORDER BY i.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check invoice totals: %w", err)
+	}
+	defer rows.Close()
+
+	type stored struct {
+		invoiceID                  int64
+		number, status             string
+		subtotal, taxAmount, total domain.Money
+		taxRate                    float64
+		sum                        domain.Money
+	}
+	var invoices []stored
+	for rows.Next() {
+		var inv stored
+		if err := rows.Scan(&inv.invoiceID, &inv.number, &inv.status, &inv.subtotal, &inv.taxRate,
+			&inv.taxAmount, &inv.total, &inv.sum); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invoices: %w", err)
+	}
+	rows.Close()
+
+	var issues []Issue
+	for _, inv := range invoices {
+		want, err := expectedTotals(ctx, d.db, inv.invoiceID, inv.sum, inv.taxRate)
+		if err != nil {
+			return nil, err
+		}
+		if inv.subtotal == want.Subtotal && inv.taxAmount == want.TaxAmount && inv.total == want.Total {
+			continue
+		}
+
+		invoiceID := inv.invoiceID
+		issues = append(issues, Issue{
+			Check:   CheckInvoiceTotals,
+			Subject: fmt.Sprintf("invoice %s", inv.number),
+			Detail:  fmt.Sprintf("stored total %.2f, line items come to %.2f", inv.total.Float(), want.Total.Float()),
+			Fixable: domain.InvoiceStatus(inv.status) == domain.InvoiceStatusDraft,
+			fix: func(ctx context.Context, c conn) error {
+				return recalcInvoiceTotals(ctx, c, invoiceID)
+			},
+		})
+	}
+
+	return issues, nil
+}
+
+// checkOrphanHistory finds audit records whose entry has been removed
+func (d *Doctor) checkOrphanHistory(ctx context.Context) ([]Issue, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT h.entry_id, COUNT(*)
+		FROM entry_history h
+		LEFT JOIN time_entries e ON e.id = h.entry_id
+		WHERE e.id IS NULL
+		GROUP BY h.entry_id
+		ORDER BY h.entry_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check entry history: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var entryID, count int64
+		if err := rows.Scan(&entryID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+
+		issues = append(issues, Issue{
+			Check:   CheckOrphanHistory,
+			Subject: fmt.Sprintf("entry %d", entryID),
+			Detail:  fmt.Sprintf("%d history records for an entry that no longer exists", count),
+			Fixable: true,
+			fix: func(ctx context.Context, c conn) error {
+				_, err := c.ExecContext(ctx, `DELETE FROM entry_history WHERE entry_id = ?`, entryID)
+				return err
+			},
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating history: %w", err)
+	}
+
+	return issues, nil
+}
+
+// checkNegativeDurations finds entries that end before they start. There is
+// no way to tell which time is wrong, so these are reported only.
+func (d *Doctor) checkNegativeDurations(ctx context.Context) ([]Issue, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		SELECT id, start_time, end_time
+		FROM time_entries
+		WHERE is_deleted = 0
+		  AND end_time IS NOT NULL
+		  AND (end_time < start_time OR duration_seconds < 0)
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check durations: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []Issue
+	for rows.Next() {
+		var entryID int64
+		var start, end string
+		if err := rows.Scan(&entryID, &start, &end); err != nil {
+			return nil, fmt.Errorf("failed to scan entry: %w", err)
+		}
+
+		issues = append(issues, Issue{
+			Check:   CheckNegativeDuration,
+			Subject: fmt.Sprintf("entry %d", entryID),
+			Detail:  fmt.Sprintf("ends at %s, before it starts at %s", end, start),
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entries: %w", err)
+	}
+
+	return issues, nil
+}
+
+// recalcInvoiceTotals recomputes an invoice's subtotal, tax, and total from
+// its line items
+func recalcInvoiceTotals(ctx context.Context, c conn, invoiceID int64) error {
+	var sum domain.Money
+	var taxRate float64
+	err := c.QueryRowContext(ctx, `
+		SELECT COALESCE((SELECT SUM(amount) FROM invoice_line_items WHERE invoice_id = ?), 0), tax_rate
+		FROM invoices WHERE id = ?
+	`, invoiceID, invoiceID).Scan(&sum, &taxRate)
+	if err != nil {
+		return fmt.Errorf("failed to sum line items: %w", err)
+	}
+
+	want, err := expectedTotals(ctx, c, invoiceID, sum, taxRate)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.ExecContext(ctx, `
+		UPDATE invoices SET subtotal = ?, tax_amount = ?, total = ?, updated_at = ? WHERE id = ?
+	`, want.Subtotal, want.TaxAmount, want.Total, formatTime(), invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice totals: %w", err)
+	}
+
+	for _, tax := range want.Taxes {
+		_, err = c.ExecContext(ctx, "UPDATE invoice_taxes SET amount = ? WHERE id = ?", tax.Amount, tax.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update invoice taxes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// expectedTotals calculates an invoice's totals from the sum of its line
+// items and its tax lines, the way the invoice itself does
+func expectedTotals(ctx context.Context, c conn, invoiceID int64, sum domain.Money, taxRate float64) (*domain.Invoice, error) {
+	rows, err := c.QueryContext(ctx, `
+		SELECT id, rate FROM invoice_taxes WHERE invoice_id = ? ORDER BY position
+	`, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice taxes: %w", err)
+	}
+	defer rows.Close()
+
+	invoice := &domain.Invoice{
+		TaxRate:   taxRate,
+		LineItems: []*domain.InvoiceLineItem{{Amount: sum}},
+	}
+	for rows.Next() {
+		tax := &domain.InvoiceTax{InvoiceID: invoiceID}
+		if err := rows.Scan(&tax.ID, &tax.Rate); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice tax: %w", err)
+		}
+		invoice.Taxes = append(invoice.Taxes, tax)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invoice taxes: %w", err)
+	}
+
+	invoice.CalculateTotals()
+	return invoice, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// withoutForeignKeys runs statements on a connection with foreign keys off,
+// to set up the kind of damage the schema would otherwise reject
+func (e *testEnv) withoutForeignKeys(stmts ...string) {
+	e.t.Helper()
+	c, err := e.db.Conn(e.ctx)
+	if err != nil {
+		e.t.Fatalf("failed to get connection: %v", err)
+	}
+	defer c.Close()
+	if _, err := c.ExecContext(e.ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		e.t.Fatalf("failed to disable foreign keys: %v", err)
+	}
+	defer c.ExecContext(e.ctx, "PRAGMA foreign_keys = ON")
+	for _, stmt := range stmts {
+		if _, err := c.ExecContext(e.ctx, stmt); err != nil {
+			e.t.Fatalf("failed to run %q: %v", stmt, err)
+		}
+	}
+}
+
+// issuesByCheck indexes issues by check name
+func issuesByCheck(issues []Issue) map[string][]Issue {
+	byCheck := make(map[string][]Issue)
+	for _, issue := range issues {
+		byCheck[issue.Check] = append(byCheck[issue.Check], issue)
+	}
+	return byCheck
+}
+
+func TestDoctor_CleanDatabase(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	env.entry(client, "design", day(2), time.Hour)
+
+	issues, err := NewDoctor(env.db).Check(env.ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestDoctor_FixesDraftInvoice(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+
+	// Entry edited after it was added to the draft, stale lock from an
+	// interrupted finalize, and totals never calculated
+	entry.Stop(entry.StartTime.Add(2 * time.Hour))
+	if err := env.entries.Update(env.ctx, entry, "extended"); err != nil {
+		t.Fatalf("failed to update entry: %v", err)
+	}
+	if _, err := env.db.Exec("UPDATE time_entries SET invoice_id = ? WHERE id = ?", invoice.ID, entry.ID); err != nil {
+		t.Fatalf("failed to lock entry: %v", err)
+	}
+
+	doctor := NewDoctor(env.db)
+	issues, err := doctor.Check(env.ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byCheck := issuesByCheck(issues)
+	for _, check := range []string{CheckLineItemAmount, CheckEntryLock, CheckInvoiceTotals} {
+		if len(byCheck[check]) != 1 || !byCheck[check][0].Fixable {
+			t.Errorf("expected one fixable %s issue, got %+v", check, byCheck[check])
+		}
+	}
+
+	fixed, err := doctor.Fix(env.ctx, issues)
+	if err != nil {
+		t.Fatalf("unexpected fix error: %v", err)
+	}
+	if fixed != 3 {
+		t.Errorf("expected 3 fixes, got %d", fixed)
+	}
+
+	if remaining, _ := doctor.Check(env.ctx); len(remaining) != 0 {
+		t.Fatalf("expected no issues after fix, got %+v", remaining)
+	}
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if got.Total != 20000 {
+		t.Errorf("expected total 200.00, got %.2f", got.Total.Float())
+	}
+}
+
+func TestDoctor_LeavesIssuedInvoicesAlone(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+	if _, err := env.db.Exec("UPDATE invoices SET status = 'finalized', total = 99900 WHERE id = ?", invoice.ID); err != nil {
+		t.Fatalf("failed to finalize invoice: %v", err)
+	}
+
+	doctor := NewDoctor(env.db)
+	issues, _ := doctor.Check(env.ctx)
+	byCheck := issuesByCheck(issues)
+	if len(byCheck[CheckInvoiceTotals]) != 1 || byCheck[CheckInvoiceTotals][0].Fixable {
+		t.Fatalf("expected an unfixable totals issue, got %+v", issues)
+	}
+
+	if fixed, _ := doctor.Fix(env.ctx, issues); fixed != 0 {
+		t.Errorf("expected nothing fixed, got %d", fixed)
+	}
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if got.Total != 99900 {
+		t.Errorf("issued invoice total changed to %.2f", got.Total.Float())
+	}
+}
+
+func TestDoctor_OrphansAndNegativeDurations(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	backwards := env.entry(client, "backwards", day(3), time.Hour)
+
+	env.withoutForeignKeys(
+		"INSERT INTO entry_history (entry_id, field_name, old_value, new_value) VALUES (9999, 'description', 'a', 'b')",
+		fmt.Sprintf("UPDATE time_entries SET invoice_id = 4242 WHERE id = %d", entry.ID),
+		fmt.Sprintf("UPDATE time_entries SET end_time = '2026-03-03T08:00:00Z', duration_seconds = -3600 WHERE id = %d", backwards.ID),
+	)
+
+	doctor := NewDoctor(env.db)
+	issues, err := doctor.Check(env.ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byCheck := issuesByCheck(issues)
+	if len(byCheck[CheckOrphanHistory]) != 1 || len(byCheck[CheckEntryLock]) != 1 {
+		t.Fatalf("expected orphan history and missing invoice lock, got %+v", issues)
+	}
+	if len(byCheck[CheckNegativeDuration]) != 1 || byCheck[CheckNegativeDuration][0].Fixable {
+		t.Fatalf("expected an unfixable negative duration, got %+v", issues)
+	}
+
+	if _, err := doctor.Fix(env.ctx, issues); err != nil {
+		t.Fatalf("unexpected fix error: %v", err)
+	}
+	remaining, _ := doctor.Check(env.ctx)
+	if len(remaining) != 1 || remaining[0].Check != CheckNegativeDuration {
+		t.Fatalf("expected only the negative duration to remain, got %+v", remaining)
+	}
+}
@@ -14,7 +14,7 @@ import (
 
 // EntryRepo is a SQLite implementation of TimeEntryRepository
 type EntryRepo struct {
-	db *db.DB
+	db conn
 }
 
 // NewEntryRepo creates a new EntryRepo
@@ -28,34 +28,50 @@ func (r *EntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error {
 		return fmt.Errorf("invalid time entry: %w", err)
 	}
 
+	return insertEntry(ctx, r.db, entry)
+}
+
+// insertEntry inserts a time entry using the given connection or transaction
+func insertEntry(ctx context.Context, exec execer, entry *domain.TimeEntry) error {
 	query := `
 		INSERT INTO time_entries (
 			client_id, description, start_time, end_time, duration_seconds,
-			hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at
+			hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at, notes,
+			approval, approval_note, rate_reason, location, role, pauses
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var endTime, durationSeconds interface{}
 	if entry.EndTime != nil {
-		endTime = entry.EndTime.Format(timeLayout)
+		endTime = formatTimeValue(*entry.EndTime)
 	}
 	if entry.DurationSeconds != nil {
 		durationSeconds = *entry.DurationSeconds
 	}
+	if entry.Approval == "" {
+		entry.Approval = domain.ApprovalPending
+	}
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		entry.ClientID,
 		entry.Description,
-		entry.StartTime.Format(timeLayout),
+		formatTimeValue(entry.StartTime),
 		endTime,
 		durationSeconds,
 		entry.HourlyRate,
 		entry.IsBillable,
 		entry.IsDeleted,
 		entry.InvoiceID,
-		entry.CreatedAt.Format(timeLayout),
-		entry.UpdatedAt.Format(timeLayout),
+		formatTimeValue(entry.CreatedAt),
+		formatTimeValue(entry.UpdatedAt),
+		entry.Notes,
+		string(entry.Approval),
+		entry.ApprovalNote,
+		entry.RateReason,
+		entry.Location,
+		entry.Role,
+		formatPauses(entry.Pauses),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create time entry: %w", err)
@@ -67,36 +83,19 @@ func (r *EntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error {
 	}
 
 	entry.ID = id
+	entry.Version = 1
 	return nil
 }
 
 // GetByID retrieves a time entry by ID
 func (r *EntryRepo) GetByID(ctx context.Context, id int64) (*domain.TimeEntry, error) {
 	query := `
-		SELECT id, client_id, description, start_time, end_time, duration_seconds,
-		       hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at
+		SELECT ` + entryColumns + `
 		FROM time_entries
 		WHERE id = ?
 	`
 
-	entry := &domain.TimeEntry{}
-	var startTime, createdAt, updatedAt sql.NullString
-	var endTime, durationSeconds, invoiceID sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&entry.ID,
-		&entry.ClientID,
-		&entry.Description,
-		&startTime,
-		&endTime,
-		&durationSeconds,
-		&entry.HourlyRate,
-		&entry.IsBillable,
-		&entry.IsDeleted,
-		&invoiceID,
-		&createdAt,
-		&updatedAt,
-	)
+	entry, err := scanEntryRow(queryRowCached(ctx, r.db, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("time entry not found: %w", err)
@@ -104,11 +103,36 @@ func (r *EntryRepo) GetByID(ctx context.Context, id int64) (*domain.TimeEntry, e
 		return nil, fmt.Errorf("failed to get time entry: %w", err)
 	}
 
-	if err := scanTimeEntry(entry, startTime, endTime, durationSeconds, invoiceID, createdAt, updatedAt); err != nil {
+	return entry, nil
+}
+
+// GetByIDs retrieves the time entries with the given IDs, keyed by ID,
+// including deleted ones as GetByID does. IDs with no entry are left out of
+// the map.
+func (r *EntryRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.TimeEntry, error) {
+	entries := make(map[int64]*domain.TimeEntry, len(ids))
+	err := forIDChunks(ids, func(chunk []int64) error {
+		query := "SELECT " + entryColumns + " FROM time_entries WHERE id IN (" + placeholders(len(chunk)) + ")"
+
+		rows, err := r.db.QueryContext(ctx, query, int64Args(chunk)...)
+		if err != nil {
+			return fmt.Errorf("failed to get time entries: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			entry, err := scanEntryRow(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan time entry: %w", err)
+			}
+			entries[entry.ID] = entry
+		}
+		return rows.Err()
+	})
+	if err != nil {
 		return nil, err
 	}
-
-	return entry, nil
+	return entries, nil
 }
 
 // Update updates an existing time entry and creates an audit record
@@ -133,7 +157,7 @@ func (r *EntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason
 	}
 
 	// Begin transaction
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -143,13 +167,13 @@ func (r *EntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason
 	query := `
 		UPDATE time_entries
 		SET client_id = ?, description = ?, start_time = ?, end_time = ?, duration_seconds = ?,
-		    hourly_rate = ?, is_billable = ?, updated_at = ?
-		WHERE id = ? AND is_deleted = 0
+		    hourly_rate = ?, rate_reason = ?, is_billable = ?, notes = ?, location = ?, role = ?, pauses = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND is_deleted = 0
 	`
 
 	var endTime, durationSeconds interface{}
 	if entry.EndTime != nil {
-		endTime = entry.EndTime.Format(timeLayout)
+		endTime = formatTimeValue(*entry.EndTime)
 	}
 	if entry.DurationSeconds != nil {
 		durationSeconds = *entry.DurationSeconds
@@ -160,13 +184,19 @@ func (r *EntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason
 	result, err := tx.ExecContext(ctx, query,
 		entry.ClientID,
 		entry.Description,
-		entry.StartTime.Format(timeLayout),
+		formatTimeValue(entry.StartTime),
 		endTime,
 		durationSeconds,
 		entry.HourlyRate,
+		entry.RateReason,
 		entry.IsBillable,
-		entry.UpdatedAt.Format(timeLayout),
+		entry.Notes,
+		entry.Location,
+		entry.Role,
+		formatPauses(entry.Pauses),
+		formatTimeValue(entry.UpdatedAt),
 		entry.ID,
+		entry.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update time entry: %w", err)
@@ -177,7 +207,7 @@ func (r *EntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("time entry not found or already deleted")
+		return missedUpdate(ctx, tx, "time_entries", "time entry", entry.ID, entry.Version, fmt.Errorf("time entry not found or already deleted"))
 	}
 
 	// Create audit records for changed fields
@@ -189,6 +219,7 @@ func (r *EntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	entry.Version++
 	return nil
 }
 
@@ -204,7 +235,7 @@ func (r *EntryRepo) SoftDelete(ctx context.Context, id int64, reason string) err
 	}
 
 	// Begin transaction
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -247,11 +278,173 @@ func (r *EntryRepo) SoftDelete(ctx context.Context, id int64, reason string) err
 	return nil
 }
 
+// Restore reverses a soft delete
+func (r *EntryRepo) Restore(ctx context.Context, id int64, reason string) error {
+	// Begin transaction
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE time_entries
+		SET is_deleted = 0, updated_at = ?
+		WHERE id = ? AND is_deleted = 1
+	`
+
+	result, err := tx.ExecContext(ctx, query, formatTime(), id)
+	if err != nil {
+		return fmt.Errorf("failed to restore time entry: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("deleted time entry not found")
+	}
+
+	// Create audit record
+	historyQuery := `
+		INSERT INTO entry_history (entry_id, field_name, old_value, new_value, change_reason, changed_at)
+		VALUES (?, 'is_deleted', '1', '0', ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, historyQuery, id, reason, formatTime())
+	if err != nil {
+		return fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeDeleted permanently removes soft-deleted entries (and their history)
+// that were deleted before the given time. Entries referenced by an invoice are
+// kept. Returns the number of entries removed.
+func (r *EntryRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	// Begin transaction
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	where := `
+		is_deleted = 1 AND updated_at < ? AND invoice_id IS NULL
+		AND id NOT IN (SELECT entry_id FROM invoice_line_items WHERE entry_id IS NOT NULL)
+	`
+	cutoff := formatTimeValue(before)
+
+	// History rows reference the entry, so remove them first
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM entry_history
+		WHERE entry_id IN (SELECT id FROM time_entries WHERE `+where+`)
+	`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge entry history: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM time_entries WHERE `+where, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge time entries: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Split divides an entry into two at the given time. The original entry is
+// shortened to end at the split point and a new entry covering the remainder is
+// created; both changes are recorded in the audit trail.
+func (r *EntryRepo) Split(ctx context.Context, id int64, at time.Time, reason string) (*domain.TimeEntry, error) {
+	entry, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.IsDeleted {
+		return nil, fmt.Errorf("cannot split time entry: entry is deleted")
+	}
+
+	second, err := entry.SplitAt(at)
+	if err != nil {
+		return nil, fmt.Errorf("cannot split time entry: %w", err)
+	}
+	oldEnd := *second.EndTime // the second half ends where the entry did
+
+	// Begin transaction
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Shorten the original, guarding against a concurrent lock
+	result, err := tx.ExecContext(ctx, `
+		UPDATE time_entries
+		SET end_time = ?, duration_seconds = ?, pauses = ?, updated_at = ?
+		WHERE id = ? AND invoice_id IS NULL AND is_deleted = 0
+	`, formatTimeValue(*entry.EndTime), *entry.DurationSeconds, formatPauses(entry.Pauses), formatTimeValue(entry.UpdatedAt), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("time entry not found, locked, or deleted")
+	}
+
+	if err := insertEntry(ctx, tx, second); err != nil {
+		return nil, err
+	}
+
+	// Audit both sides of the split
+	historyQuery := `
+		INSERT INTO entry_history (entry_id, field_name, old_value, new_value, change_reason, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	changedAt := formatTime()
+
+	_, err = tx.ExecContext(ctx, historyQuery, id, "end_time",
+		formatTimeValue(oldEnd), formatTimeValue(*entry.EndTime),
+		fmt.Sprintf("%s (split into entry %d)", reason, second.ID), changedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, historyQuery, second.ID, "split_from",
+		"", strconv.FormatInt(id, 10), reason, changedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit record: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return second, nil
+}
+
 // List retrieves time entries with optional filters
 func (r *EntryRepo) List(ctx context.Context, clientID *int64, start, end *time.Time, includeLocked bool) ([]*domain.TimeEntry, error) {
 	query := `
-		SELECT id, client_id, description, start_time, end_time, duration_seconds,
-		       hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at
+		SELECT ` + entryColumns + `
 		FROM time_entries
 		WHERE is_deleted = 0
 	`
@@ -264,12 +457,12 @@ func (r *EntryRepo) List(ctx context.Context, clientID *int64, start, end *time.
 
 	if start != nil {
 		query += " AND start_time >= ?"
-		args = append(args, start.Format(timeLayout))
+		args = append(args, formatTimeValue(*start))
 	}
 
 	if end != nil {
 		query += " AND start_time <= ?"
-		args = append(args, end.Format(timeLayout))
+		args = append(args, formatTimeValue(*end))
 	}
 
 	if !includeLocked {
@@ -278,7 +471,7 @@ func (r *EntryRepo) List(ctx context.Context, clientID *int64, start, end *time.
 
 	query += " ORDER BY start_time DESC"
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := queryCached(ctx, r.db, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list time entries: %w", err)
 	}
@@ -286,32 +479,80 @@ func (r *EntryRepo) List(ctx context.Context, clientID *int64, start, end *time.
 
 	entries := make([]*domain.TimeEntry, 0)
 	for rows.Next() {
-		entry := &domain.TimeEntry{}
-		var startTime, createdAt, updatedAt sql.NullString
-		var endTime, durationSeconds, invoiceID sql.NullString
-
-		err := rows.Scan(
-			&entry.ID,
-			&entry.ClientID,
-			&entry.Description,
-			&startTime,
-			&endTime,
-			&durationSeconds,
-			&entry.HourlyRate,
-			&entry.IsBillable,
-			&entry.IsDeleted,
-			&invoiceID,
-			&createdAt,
-			&updatedAt,
-		)
+		entry, err := scanEntryRow(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan time entry: %w", err)
 		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time entries: %w", err)
+	}
 
-		if err := scanTimeEntry(entry, startTime, endTime, durationSeconds, invoiceID, createdAt, updatedAt); err != nil {
-			return nil, err
+	return entries, nil
+}
+
+// ListDeleted retrieves soft-deleted time entries, optionally for a single client
+func (r *EntryRepo) ListDeleted(ctx context.Context, clientID *int64) ([]*domain.TimeEntry, error) {
+	query := `
+		SELECT ` + entryColumns + `
+		FROM time_entries
+		WHERE is_deleted = 1
+	`
+	args := make([]interface{}, 0)
+
+	if clientID != nil {
+		query += " AND client_id = ?"
+		args = append(args, *clientID)
+	}
+
+	query += " ORDER BY start_time DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.TimeEntry, 0)
+	for rows.Next() {
+		entry, err := scanEntryRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
 		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time entries: %w", err)
+	}
+
+	return entries, nil
+}
 
+// ListByInvoice retrieves the entries locked to an invoice, oldest first as
+// they are billed
+func (r *EntryRepo) ListByInvoice(ctx context.Context, invoiceID int64) ([]*domain.TimeEntry, error) {
+	query := `
+		SELECT ` + entryColumns + `
+		FROM time_entries
+		WHERE is_deleted = 0 AND invoice_id = ?
+		ORDER BY start_time ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.TimeEntry, 0)
+	for rows.Next() {
+		entry, err := scanEntryRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
 		entries = append(entries, entry)
 	}
 
@@ -322,11 +563,12 @@ func (r *EntryRepo) List(ctx context.Context, clientID *int64, start, end *time.
 	return entries, nil
 }
 
-// GetUnbilledByClient retrieves unbilled time entries for a client within a date range
+// GetUnbilledByClient retrieves unbilled time entries for a client within a
+// date range. Entries already on a draft are left out, as they are only
+// locked once the draft is finalized.
 func (r *EntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, start, end time.Time) ([]*domain.TimeEntry, error) {
 	query := `
-		SELECT id, client_id, description, start_time, end_time, duration_seconds,
-		       hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at
+		SELECT ` + entryColumns + `
 		FROM time_entries
 		WHERE client_id = ?
 		  AND invoice_id IS NULL
@@ -334,10 +576,15 @@ func (r *EntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, sta
 		  AND start_time >= ?
 		  AND start_time <= ?
 		  AND end_time IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM invoice_line_items li
+			JOIN invoices i ON i.id = li.invoice_id
+			WHERE li.entry_id = time_entries.id AND i.status = ?
+		  )
 		ORDER BY start_time
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, clientID, start.Format(timeLayout), end.Format(timeLayout))
+	rows, err := r.db.QueryContext(ctx, query, clientID, formatTimeValue(start), formatTimeValue(end), string(domain.InvoiceStatusDraft))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get unbilled entries: %w", err)
 	}
@@ -345,32 +592,50 @@ func (r *EntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, sta
 
 	entries := make([]*domain.TimeEntry, 0)
 	for rows.Next() {
-		entry := &domain.TimeEntry{}
-		var startTime, createdAt, updatedAt sql.NullString
-		var endTime, durationSeconds, invoiceID sql.NullString
-
-		err := rows.Scan(
-			&entry.ID,
-			&entry.ClientID,
-			&entry.Description,
-			&startTime,
-			&endTime,
-			&durationSeconds,
-			&entry.HourlyRate,
-			&entry.IsBillable,
-			&entry.IsDeleted,
-			&invoiceID,
-			&createdAt,
-			&updatedAt,
-		)
+		entry, err := scanEntryRow(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan time entry: %w", err)
 		}
+		entries = append(entries, entry)
+	}
 
-		if err := scanTimeEntry(entry, startTime, endTime, durationSeconds, invoiceID, createdAt, updatedAt); err != nil {
-			return nil, err
-		}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating unbilled entries: %w", err)
+	}
 
+	return entries, nil
+}
+
+// GetUnbilledSinceLastInvoice retrieves unbilled time entries for a client
+// that started after the end of their latest non-void invoice's period
+func (r *EntryRepo) GetUnbilledSinceLastInvoice(ctx context.Context, clientID int64, end time.Time) ([]*domain.TimeEntry, error) {
+	query := `
+		SELECT ` + entryColumns + `
+		FROM time_entries
+		WHERE client_id = ?
+		  AND invoice_id IS NULL
+		  AND is_deleted = 0
+		  AND start_time > COALESCE((
+			SELECT MAX(period_end) FROM invoices
+			WHERE client_id = ? AND status != 'void'
+		  ), '')
+		  AND start_time <= ?
+		  AND end_time IS NOT NULL
+		ORDER BY start_time
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, clientID, clientID, formatTimeValue(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unbilled entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*domain.TimeEntry, 0)
+	for rows.Next() {
+		entry, err := scanEntryRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
 		entries = append(entries, entry)
 	}
 
@@ -381,12 +646,46 @@ func (r *EntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, sta
 	return entries, nil
 }
 
+// RecentDescriptions lists the distinct descriptions of a client's entries,
+// most recently used first, for autocompleting new ones
+func (r *EntryRepo) RecentDescriptions(ctx context.Context, clientID int64, limit int) ([]string, error) {
+	query := `
+		SELECT description
+		FROM time_entries
+		WHERE client_id = ? AND is_deleted = 0 AND TRIM(description) != ''
+		GROUP BY description
+		ORDER BY MAX(start_time) DESC, COUNT(*) DESC
+		LIMIT ?
+	`
+
+	rows, err := queryCached(ctx, r.db, query, clientID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list descriptions: %w", err)
+	}
+	defer rows.Close()
+
+	descriptions := make([]string, 0)
+	for rows.Next() {
+		var desc string
+		if err := rows.Scan(&desc); err != nil {
+			return nil, fmt.Errorf("failed to scan description: %w", err)
+		}
+		descriptions = append(descriptions, desc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating descriptions: %w", err)
+	}
+
+	return descriptions, nil
+}
+
 // IsLocked checks if a time entry is locked (attached to an invoice)
 func (r *EntryRepo) IsLocked(ctx context.Context, id int64) (bool, error) {
 	var invoiceID sql.NullInt64
 	query := "SELECT invoice_id FROM time_entries WHERE id = ?"
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&invoiceID)
+	err := queryRowCached(ctx, r.db, query, id).Scan(&invoiceID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, fmt.Errorf("time entry not found")
@@ -404,7 +703,7 @@ func (r *EntryRepo) LockForInvoice(ctx context.Context, entryIDs []int64, invoic
 	}
 
 	// Begin transaction
-	tx, err := r.db.BeginTx(ctx, nil)
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -444,6 +743,85 @@ func (r *EntryRepo) LockForInvoice(ctx context.Context, entryIDs []int64, invoic
 	return nil
 }
 
+// UnlockForInvoice releases all time entries locked to an invoice
+func (r *EntryRepo) UnlockForInvoice(ctx context.Context, invoiceID int64) (int64, error) {
+	query := `
+		UPDATE time_entries
+		SET invoice_id = NULL, updated_at = ?
+		WHERE invoice_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, formatTime(), invoiceID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unlock entries: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows, nil
+}
+
+// SetApproval records the client's answer on unbilled entries in one
+// transaction. Each change is added to the entry's history with the note as
+// its reason.
+func (r *EntryRepo) SetApproval(ctx context.Context, entryIDs []int64, status domain.ApprovalStatus, note string) error {
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	changedAt := formatTime()
+	for _, entryID := range entryIDs {
+		var old string
+		var invoiceID sql.NullInt64
+		err := tx.QueryRowContext(ctx,
+			"SELECT approval, invoice_id FROM time_entries WHERE id = ? AND is_deleted = 0", entryID,
+		).Scan(&old, &invoiceID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("entry %d not found or deleted", entryID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get entry %d: %w", entryID, err)
+		}
+		if invoiceID.Valid {
+			return fmt.Errorf("cannot change approval of entry %d: locked by invoice", entryID)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE time_entries SET approval = ?, approval_note = ?, updated_at = ? WHERE id = ?",
+			string(status), note, changedAt, entryID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to set approval of entry %d: %w", entryID, err)
+		}
+
+		if old == string(status) {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO entry_history (entry_id, field_name, old_value, new_value, change_reason, changed_at)
+			VALUES (?, 'approval', ?, ?, ?, ?)
+		`, entryID, old, string(status), note, changedAt)
+		if err != nil {
+			return fmt.Errorf("failed to audit approval change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 // GetHistory retrieves the audit trail for a time entry
 func (r *EntryRepo) GetHistory(ctx context.Context, entryID int64) ([]*domain.EntryHistory, error) {
 	query := `
@@ -492,7 +870,7 @@ func (r *EntryRepo) GetHistory(ctx context.Context, entryID int64) ([]*domain.En
 }
 
 // createAuditRecords creates history records for changed fields
-func (r *EntryRepo) createAuditRecords(ctx context.Context, tx *sql.Tx, old, new *domain.TimeEntry, reason string) error {
+func (r *EntryRepo) createAuditRecords(ctx context.Context, tx execer, old, new *domain.TimeEntry, reason string) error {
 	changedAt := formatTime()
 
 	// Helper to insert audit record
@@ -521,8 +899,14 @@ func (r *EntryRepo) createAuditRecords(ctx context.Context, tx *sql.Tx, old, new
 		}
 	}
 
+	if old.Notes != new.Notes {
+		if err := insertHistory("notes", old.Notes, new.Notes); err != nil {
+			return fmt.Errorf("failed to audit notes change: %w", err)
+		}
+	}
+
 	if !old.StartTime.Equal(new.StartTime) {
-		if err := insertHistory("start_time", old.StartTime.Format(timeLayout), new.StartTime.Format(timeLayout)); err != nil {
+		if err := insertHistory("start_time", formatTimeValue(old.StartTime), formatTimeValue(new.StartTime)); err != nil {
 			return fmt.Errorf("failed to audit start_time change: %w", err)
 		}
 	}
@@ -530,10 +914,10 @@ func (r *EntryRepo) createAuditRecords(ctx context.Context, tx *sql.Tx, old, new
 	oldEnd := ""
 	newEnd := ""
 	if old.EndTime != nil {
-		oldEnd = old.EndTime.Format(timeLayout)
+		oldEnd = formatTimeValue(*old.EndTime)
 	}
 	if new.EndTime != nil {
-		newEnd = new.EndTime.Format(timeLayout)
+		newEnd = formatTimeValue(*new.EndTime)
 	}
 	if oldEnd != newEnd {
 		if err := insertHistory("end_time", oldEnd, newEnd); err != nil {
@@ -547,15 +931,92 @@ func (r *EntryRepo) createAuditRecords(ctx context.Context, tx *sql.Tx, old, new
 		}
 	}
 
+	if old.RateReason != new.RateReason {
+		if err := insertHistory("rate_reason", old.RateReason, new.RateReason); err != nil {
+			return fmt.Errorf("failed to audit rate_reason change: %w", err)
+		}
+	}
+
 	if old.IsBillable != new.IsBillable {
 		if err := insertHistory("is_billable", strconv.FormatBool(old.IsBillable), strconv.FormatBool(new.IsBillable)); err != nil {
 			return fmt.Errorf("failed to audit is_billable change: %w", err)
 		}
 	}
 
+	if old.Location != new.Location {
+		if err := insertHistory("location", old.Location, new.Location); err != nil {
+			return fmt.Errorf("failed to audit location change: %w", err)
+		}
+	}
+
+	if old.Role != new.Role {
+		if err := insertHistory("role", old.Role, new.Role); err != nil {
+			return fmt.Errorf("failed to audit role change: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// entryColumns is the column list shared by every time entry SELECT
+const entryColumns = `id, client_id, description, start_time, end_time, duration_seconds,
+		       hourly_rate, is_billable, is_deleted, invoice_id, created_at, updated_at, notes,
+		       approval, approval_note, version, rate_reason, location, role, pauses`
+
+// execer is satisfied by both *db.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanEntryRow scans a row selected with entryColumns into a time entry
+func scanEntryRow(row rowScanner) (*domain.TimeEntry, error) {
+	entry := &domain.TimeEntry{}
+	var startTime, createdAt, updatedAt sql.NullString
+	var endTime, durationSeconds, invoiceID sql.NullString
+	var approval, pauses string
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.ClientID,
+		&entry.Description,
+		&startTime,
+		&endTime,
+		&durationSeconds,
+		&entry.HourlyRate,
+		&entry.IsBillable,
+		&entry.IsDeleted,
+		&invoiceID,
+		&createdAt,
+		&updatedAt,
+		&entry.Notes,
+		&approval,
+		&entry.ApprovalNote,
+		&entry.Version,
+		&entry.RateReason,
+		&entry.Location,
+		&entry.Role,
+		&pauses,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entry.Approval = domain.ApprovalStatus(approval)
+	if entry.Pauses, err = parsePauses(pauses); err != nil {
+		return nil, fmt.Errorf("failed to parse pauses: %w", err)
+	}
+
+	if err := scanTimeEntry(entry, startTime, endTime, durationSeconds, invoiceID, createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
 // scanTimeEntry is a helper to parse time entry fields
 func scanTimeEntry(entry *domain.TimeEntry, startTime, endTime, durationSeconds, invoiceID, createdAt, updatedAt sql.NullString) error {
 	var err error
@@ -0,0 +1,457 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestEntryRepo_ListFilters(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	beta := env.client("Beta", 80)
+
+	first := env.entry(acme, "first", day(2), time.Hour)
+	env.entry(acme, "second", day(5), time.Hour)
+	env.entry(beta, "other client", day(3), time.Hour)
+	deleted := env.entry(acme, "deleted", day(4), time.Hour)
+	if err := env.entries.SoftDelete(env.ctx, deleted.ID, "mistake"); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+
+	all, err := env.entries.List(env.ctx, nil, nil, nil, true)
+	if err != nil {
+		t.Fatalf("failed to list entries: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 live entries, got %d", len(all))
+	}
+	if all[0].Description != "second" {
+		t.Fatalf("expected newest entry first, got %q", all[0].Description)
+	}
+
+	acmeOnly, _ := env.entries.List(env.ctx, &acme.ID, nil, nil, true)
+	if len(acmeOnly) != 2 {
+		t.Fatalf("expected 2 Acme entries, got %d", len(acmeOnly))
+	}
+
+	start, end := day(2), day(3)
+	ranged, _ := env.entries.List(env.ctx, nil, &start, &end, true)
+	if len(ranged) != 2 {
+		t.Fatalf("expected 2 entries in range, got %d", len(ranged))
+	}
+
+	if err := env.entries.LockForInvoice(env.ctx, []int64{first.ID}, env.draftInvoice(acme, "INV-2026-001").ID); err != nil {
+		t.Fatalf("failed to lock entry: %v", err)
+	}
+	unlocked, _ := env.entries.List(env.ctx, &acme.ID, nil, nil, false)
+	if len(unlocked) != 1 || unlocked[0].Description != "second" {
+		t.Fatalf("expected locked entry to be excluded, got %d entries", len(unlocked))
+	}
+
+	trash, _ := env.entries.ListDeleted(env.ctx, nil)
+	if len(trash) != 1 || trash[0].ID != deleted.ID {
+		t.Fatalf("expected the deleted entry in the trash, got %d", len(trash))
+	}
+}
+
+func TestEntryRepo_ListByInvoice(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	later := env.entry(acme, "Build", day(5), time.Hour)
+	earlier := env.entry(acme, "Design", day(3), time.Hour)
+	env.entry(acme, "Unbilled", day(4), time.Hour)
+	invoice := env.draftInvoice(acme, "INV-2026-001", later, earlier)
+	if err := env.entries.LockForInvoice(env.ctx, []int64{later.ID, earlier.ID}, invoice.ID); err != nil {
+		t.Fatalf("failed to lock entries: %v", err)
+	}
+
+	billed, err := env.entries.ListByInvoice(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("ListByInvoice failed: %v", err)
+	}
+	if len(billed) != 2 || billed[0].ID != earlier.ID || billed[1].ID != later.ID {
+		t.Fatalf("expected the two locked entries oldest first, got %d entries", len(billed))
+	}
+}
+
+func TestEntryRepo_GetByIDs(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	first := env.entry(acme, "Design", day(2), time.Hour)
+	second := env.entry(acme, "Build", day(3), 2*time.Hour)
+	deleted := env.entry(acme, "Mistake", day(4), time.Hour)
+	if err := env.entries.SoftDelete(env.ctx, deleted.ID, ""); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+
+	// More IDs than fit in one query, most of them missing
+	ids := []int64{second.ID, deleted.ID}
+	for id := int64(1000); len(ids) < 2*maxIDsPerQuery+10; id++ {
+		ids = append(ids, id)
+	}
+	ids = append(ids, first.ID)
+
+	got, err := env.entries.GetByIDs(env.ctx, ids)
+	if err != nil {
+		t.Fatalf("failed to get entries: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected the three existing entries, got %d", len(got))
+	}
+	if got[first.ID].Description != "Design" || got[second.ID].Duration() != 2*time.Hour || !got[deleted.ID].IsDeleted {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+
+	if got, err = env.entries.GetByIDs(env.ctx, nil); err != nil || len(got) != 0 {
+		t.Fatalf("expected no entries for no IDs, got %v, %v", got, err)
+	}
+}
+
+func TestEntryRepo_UpdateCreatesAuditRecords(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "draft", day(2), time.Hour)
+
+	entry.Description = "final"
+	entry.HourlyRate = 120
+	entry.Location = "on-site"
+	entry.Role = "consulting"
+	if err := env.entries.Update(env.ctx, entry, "client request"); err != nil {
+		t.Fatalf("failed to update entry: %v", err)
+	}
+
+	history, err := env.entries.GetHistory(env.ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	fields := map[string][2]string{}
+	for _, h := range history {
+		fields[h.FieldName] = [2]string{h.OldValue, h.NewValue}
+		if h.ChangeReason != "client request" {
+			t.Fatalf("expected reason to be recorded, got %q", h.ChangeReason)
+		}
+	}
+	if len(history) != 4 {
+		t.Fatalf("expected 4 audit records, got %d", len(history))
+	}
+	if fields["description"] != [2]string{"draft", "final"} {
+		t.Fatalf("unexpected description audit: %v", fields["description"])
+	}
+	if fields["hourly_rate"] != [2]string{"100.00", "120.00"} {
+		t.Fatalf("unexpected hourly_rate audit: %v", fields["hourly_rate"])
+	}
+	if fields["location"] != [2]string{"", "on-site"} {
+		t.Fatalf("unexpected location audit: %v", fields["location"])
+	}
+	if fields["role"] != [2]string{"", "consulting"} {
+		t.Fatalf("unexpected role audit: %v", fields["role"])
+	}
+	if saved, _ := env.entries.GetByID(env.ctx, entry.ID); saved.Location != "on-site" || saved.Role != "consulting" {
+		t.Fatalf("expected the location and role saved, got %q and %q", saved.Location, saved.Role)
+	}
+}
+
+func TestEntryRepo_UpdateRefusesStaleCopy(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	entry := env.entry(acme, "Design", day(2), time.Hour)
+
+	mine, _ := env.entries.GetByID(env.ctx, entry.ID)
+	theirs, _ := env.entries.GetByID(env.ctx, entry.ID)
+
+	theirs.Description = "Design review"
+	if err := env.entries.Update(env.ctx, theirs, ""); err != nil {
+		t.Fatalf("failed to update entry: %v", err)
+	}
+	mine.Description = "Design system"
+	if err := env.entries.Update(env.ctx, mine, ""); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	got, _ := env.entries.GetByID(env.ctx, entry.ID)
+	if got.Description != "Design review" {
+		t.Fatalf("expected the first update kept, got %q", got.Description)
+	}
+
+	// A deleted entry is still reported as such rather than as a conflict
+	if err := env.entries.SoftDelete(env.ctx, entry.ID, ""); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	deleted, _ := env.entries.GetByID(env.ctx, entry.ID)
+	deleted.Description = "Design"
+	if err := env.entries.Update(env.ctx, deleted, ""); err == nil || errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestEntryRepo_SoftDeleteRestoreAndPurge(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "oops", day(2), time.Hour)
+
+	if err := env.entries.SoftDelete(env.ctx, entry.ID, "duplicate"); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	if err := env.entries.Restore(env.ctx, entry.ID, "not a duplicate"); err != nil {
+		t.Fatalf("failed to restore entry: %v", err)
+	}
+	if err := env.entries.Restore(env.ctx, entry.ID, "again"); err == nil {
+		t.Fatalf("expected restoring a live entry to fail")
+	}
+
+	history, _ := env.entries.GetHistory(env.ctx, entry.ID)
+	if len(history) != 2 {
+		t.Fatalf("expected delete and restore audit records, got %d", len(history))
+	}
+
+	if err := env.entries.SoftDelete(env.ctx, entry.ID, "really a duplicate"); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	purged, err := env.entries.PurgeDeleted(env.ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("failed to purge: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 purged entry, got %d", purged)
+	}
+	if _, err := env.entries.GetByID(env.ctx, entry.ID); err == nil {
+		t.Fatalf("expected purged entry to be gone")
+	}
+	history, _ = env.entries.GetHistory(env.ctx, entry.ID)
+	if len(history) != 0 {
+		t.Fatalf("expected history to be purged, got %d records", len(history))
+	}
+}
+
+func TestEntryRepo_LockPreventsChanges(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "billed", day(2), 2*time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+
+	if err := env.entries.LockForInvoice(env.ctx, []int64{entry.ID}, invoice.ID); err != nil {
+		t.Fatalf("failed to lock entry: %v", err)
+	}
+	locked, err := env.entries.IsLocked(env.ctx, entry.ID)
+	if err != nil || !locked {
+		t.Fatalf("expected entry to be locked, got %v (%v)", locked, err)
+	}
+
+	if err := env.entries.LockForInvoice(env.ctx, []int64{entry.ID}, invoice.ID); err == nil {
+		t.Fatalf("expected locking twice to fail")
+	}
+	entry.Description = "changed"
+	if err := env.entries.Update(env.ctx, entry, ""); err == nil {
+		t.Fatalf("expected update of locked entry to fail")
+	}
+	if err := env.entries.SoftDelete(env.ctx, entry.ID, ""); err == nil {
+		t.Fatalf("expected delete of locked entry to fail")
+	}
+	if _, err := env.entries.Split(env.ctx, entry.ID, day(2).Add(time.Hour), ""); err == nil {
+		t.Fatalf("expected split of locked entry to fail")
+	}
+
+	unbilled, _ := env.entries.GetUnbilledByClient(env.ctx, client.ID, day(1), day(3))
+	if len(unbilled) != 0 {
+		t.Fatalf("expected locked entry to be excluded from unbilled, got %d", len(unbilled))
+	}
+
+	released, err := env.entries.UnlockForInvoice(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	if released != 1 {
+		t.Fatalf("expected 1 entry released, got %d", released)
+	}
+	unbilled, _ = env.entries.GetUnbilledByClient(env.ctx, client.ID, day(1), day(3))
+	if len(unbilled) != 0 {
+		t.Fatalf("expected unlocked entry to stay off unbilled while on the draft, got %d", len(unbilled))
+	}
+
+	if err := env.invoices.Void(env.ctx, invoice.ID); err != nil {
+		t.Fatalf("failed to void draft: %v", err)
+	}
+	unbilled, _ = env.entries.GetUnbilledByClient(env.ctx, client.ID, day(1), day(3))
+	if len(unbilled) != 1 {
+		t.Fatalf("expected entry to be unbilled again once its draft is void, got %d", len(unbilled))
+	}
+}
+
+func TestEntryRepo_SplitRefusesRunningEntry(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	running := domain.NewTimeEntry(client.ID, "still going", client.HourlyRate)
+	running.StartTime = day(2)
+	if err := env.entries.Create(env.ctx, running); err != nil {
+		t.Fatalf("failed to create entry: %v", err)
+	}
+
+	if _, err := env.entries.Split(env.ctx, running.ID, day(2).Add(time.Hour), ""); err == nil {
+		t.Fatalf("expected split of a running entry to fail")
+	}
+	history, _ := env.entries.GetHistory(env.ctx, running.ID)
+	if len(history) != 0 {
+		t.Fatalf("expected the running entry to be unchanged, got %d history records", len(history))
+	}
+}
+
+func TestEntryRepo_SplitAuditsBothHalves(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "long day", day(2), 4*time.Hour)
+
+	second, err := env.entries.Split(env.ctx, entry.ID, day(2).Add(time.Hour), "two tasks")
+	if err != nil {
+		t.Fatalf("failed to split entry: %v", err)
+	}
+
+	first, _ := env.entries.GetByID(env.ctx, entry.ID)
+	if first.Duration() != time.Hour || second.Duration() != 3*time.Hour {
+		t.Fatalf("unexpected split durations: %v and %v", first.Duration(), second.Duration())
+	}
+
+	firstHistory, _ := env.entries.GetHistory(env.ctx, entry.ID)
+	secondHistory, _ := env.entries.GetHistory(env.ctx, second.ID)
+	if len(firstHistory) != 1 || firstHistory[0].FieldName != "end_time" {
+		t.Fatalf("expected end_time audit on the original, got %d records", len(firstHistory))
+	}
+	if len(secondHistory) != 1 || secondHistory[0].FieldName != "split_from" {
+		t.Fatalf("expected split_from audit on the new entry, got %d records", len(secondHistory))
+	}
+}
+
+func TestEntryRepo_SplitKeepsPausesWhereTheyFell(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	// 09:00 to 13:00, paused from 12:00 to 12:30
+	entry := domain.NewTimeEntry(client.ID, "long day", client.HourlyRate)
+	entry.StartTime = day(2)
+	entry.Pauses = []domain.Pause{{Start: day(2).Add(3 * time.Hour), End: day(2).Add(210 * time.Minute)}}
+	entry.Stop(day(2).Add(4 * time.Hour))
+	if err := env.entries.Create(env.ctx, entry); err != nil {
+		t.Fatalf("failed to create entry: %v", err)
+	}
+
+	second, err := env.entries.Split(env.ctx, entry.ID, day(2).Add(time.Hour), "")
+	if err != nil {
+		t.Fatalf("failed to split entry: %v", err)
+	}
+	first, _ := env.entries.GetByID(env.ctx, entry.ID)
+	stored, _ := env.entries.GetByID(env.ctx, second.ID)
+	if first.Duration() != time.Hour || stored.Duration() != 150*time.Minute {
+		t.Fatalf("expected 1h and 2h30m worked, got %v and %v", first.Duration(), stored.Duration())
+	}
+	if !stored.EndTime.Equal(day(2).Add(4 * time.Hour)) {
+		t.Fatalf("expected the second part to keep the real end, got %v", stored.EndTime)
+	}
+	if *stored.DurationSeconds != int64((150 * time.Minute).Seconds()) {
+		t.Fatalf("expected 2h30m stored as worked, got %ds", *stored.DurationSeconds)
+	}
+}
+
+func TestEntryRepo_SetApprovalAuditsChanges(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	first := env.entry(client, "design", day(2), time.Hour)
+	second := env.entry(client, "build", day(3), time.Hour)
+
+	if err := env.entries.SetApproval(env.ctx, []int64{first.ID, second.ID}, domain.ApprovalRejected, "not in scope"); err != nil {
+		t.Fatalf("failed to set approval: %v", err)
+	}
+	got, err := env.entries.GetByID(env.ctx, first.ID)
+	if err != nil {
+		t.Fatalf("failed to get entry: %v", err)
+	}
+	if got.Approval != domain.ApprovalRejected || got.ApprovalNote != "not in scope" {
+		t.Fatalf("unexpected approval: %s %q", got.Approval, got.ApprovalNote)
+	}
+
+	history, _ := env.entries.GetHistory(env.ctx, second.ID)
+	if len(history) != 1 || history[0].FieldName != "approval" || history[0].NewValue != "rejected" {
+		t.Fatalf("expected an approval audit record, got %+v", history)
+	}
+
+	invoice := env.draftInvoice(client, "INV-2026-001", first)
+	if err := env.entries.LockForInvoice(env.ctx, []int64{first.ID}, invoice.ID); err != nil {
+		t.Fatalf("failed to lock entry: %v", err)
+	}
+	if err := env.entries.SetApproval(env.ctx, []int64{second.ID, first.ID}, domain.ApprovalApproved, ""); err == nil {
+		t.Fatalf("expected approving a locked entry to fail")
+	}
+	got, _ = env.entries.GetByID(env.ctx, second.ID)
+	if got.Approval != domain.ApprovalRejected {
+		t.Fatalf("expected failed batch to roll back, got %s", got.Approval)
+	}
+}
+
+func TestEntryRepo_GetUnbilledSinceLastInvoice(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+
+	env.entry(acme, "late addition", day(5), time.Hour)
+	env.entry(acme, "new work", day(15), time.Hour)
+
+	// Never invoiced: everything up to end
+	all, err := env.entries.GetUnbilledSinceLastInvoice(env.ctx, acme.ID, day(31))
+	if err != nil {
+		t.Fatalf("failed to get unbilled entries: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected both entries before the first invoice, got %d", len(all))
+	}
+	if last, _ := env.invoices.GetLastPeriodEnd(env.ctx, acme.ID); last != nil {
+		t.Fatalf("expected no period end before the first invoice, got %v", last)
+	}
+
+	invoice := domain.NewInvoice("INV-2026-001", acme.ID, day(1), day(10))
+	if err := env.invoices.Create(env.ctx, invoice); err != nil {
+		t.Fatalf("failed to create invoice: %v", err)
+	}
+	// A void invoice's period does not count
+	void := env.draftInvoice(acme, "INV-2026-002")
+	if err := env.invoices.Void(env.ctx, void.ID); err != nil {
+		t.Fatalf("failed to void invoice: %v", err)
+	}
+
+	last, err := env.invoices.GetLastPeriodEnd(env.ctx, acme.ID)
+	if err != nil || last == nil || !last.Equal(day(10)) {
+		t.Fatalf("expected the period to end on March 10, got %v (%v)", last, err)
+	}
+
+	since, _ := env.entries.GetUnbilledSinceLastInvoice(env.ctx, acme.ID, day(31))
+	if len(since) != 1 || since[0].Description != "new work" {
+		t.Fatalf("expected only the entry after the invoiced period, got %d", len(since))
+	}
+}
+
+func TestEntryRepo_RecentDescriptions(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	beta := env.client("Beta", 80)
+
+	env.entry(acme, "Code review", day(2), time.Hour)
+	env.entry(acme, "Standup", day(3), time.Hour)
+	env.entry(acme, "Code review", day(4), time.Hour)
+	env.entry(acme, "  ", day(5), time.Hour)
+	env.entry(beta, "Other client", day(6), time.Hour)
+	deleted := env.entry(acme, "Deleted", day(7), time.Hour)
+	if err := env.entries.SoftDelete(env.ctx, deleted.ID, "mistake"); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+
+	descs, err := env.entries.RecentDescriptions(env.ctx, acme.ID, 10)
+	if err != nil {
+		t.Fatalf("RecentDescriptions failed: %v", err)
+	}
+	if len(descs) != 2 || descs[0] != "Code review" || descs[1] != "Standup" {
+		t.Fatalf("expected each description once, most recent first, got %q", descs)
+	}
+
+	if descs, _ := env.entries.RecentDescriptions(env.ctx, acme.ID, 1); len(descs) != 1 {
+		t.Fatalf("expected the limit to apply, got %d", len(descs))
+	}
+}
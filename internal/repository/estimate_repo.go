@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// EstimateRepo is a SQLite implementation of EstimateRepository
+type EstimateRepo struct {
+	db conn
+}
+
+// NewEstimateRepo creates a new EstimateRepo
+func NewEstimateRepo(database *db.DB) *EstimateRepo {
+	return &EstimateRepo{db: database}
+}
+
+const estimateColumns = `
+	id, estimate_number, client_id, issue_date, valid_until, status,
+	total, notes, invoice_id, created_at, updated_at`
+
+// Create inserts a new estimate into the database
+func (r *EstimateRepo) Create(ctx context.Context, estimate *domain.Estimate) error {
+	if err := estimate.Validate(); err != nil {
+		return fmt.Errorf("invalid estimate: %w", err)
+	}
+
+	query := `
+		INSERT INTO estimates (
+			estimate_number, client_id, issue_date, valid_until, status,
+			total, notes, invoice_id, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	validUntil, invoiceID := estimateNullables(estimate)
+	result, err := r.db.ExecContext(ctx, query,
+		estimate.EstimateNumber,
+		estimate.ClientID,
+		formatTimeValue(estimate.IssueDate),
+		validUntil,
+		string(estimate.Status),
+		estimate.Total,
+		estimate.Notes,
+		invoiceID,
+		formatTimeValue(estimate.CreatedAt),
+		formatTimeValue(estimate.UpdatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create estimate: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get estimate ID: %w", err)
+	}
+
+	estimate.ID = id
+	return nil
+}
+
+// GetByID retrieves an estimate by ID
+func (r *EstimateRepo) GetByID(ctx context.Context, id int64) (*domain.Estimate, error) {
+	query := `SELECT ` + estimateColumns + ` FROM estimates WHERE id = ?`
+
+	estimate, err := scanEstimate(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("estimate not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get estimate: %w", err)
+	}
+
+	return estimate, nil
+}
+
+// List retrieves estimates with optional filters, newest first
+func (r *EstimateRepo) List(ctx context.Context, clientID *int64, status *domain.EstimateStatus) ([]*domain.Estimate, error) {
+	query := `SELECT ` + estimateColumns + ` FROM estimates WHERE 1=1`
+	args := make([]interface{}, 0)
+
+	if clientID != nil {
+		query += " AND client_id = ?"
+		args = append(args, *clientID)
+	}
+
+	if status != nil {
+		query += " AND status = ?"
+		args = append(args, string(*status))
+	}
+
+	query += " ORDER BY created_at DESC, id DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list estimates: %w", err)
+	}
+	defer rows.Close()
+
+	estimates := make([]*domain.Estimate, 0)
+	for rows.Next() {
+		estimate, err := scanEstimate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan estimate: %w", err)
+		}
+		estimates = append(estimates, estimate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating estimates: %w", err)
+	}
+
+	return estimates, nil
+}
+
+// Update updates an estimate's status, total, notes and invoice link
+func (r *EstimateRepo) Update(ctx context.Context, estimate *domain.Estimate) error {
+	if err := estimate.Validate(); err != nil {
+		return fmt.Errorf("invalid estimate: %w", err)
+	}
+
+	query := `
+		UPDATE estimates
+		SET issue_date = ?, valid_until = ?, status = ?, total = ?, notes = ?,
+		    invoice_id = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	validUntil, invoiceID := estimateNullables(estimate)
+	result, err := r.db.ExecContext(ctx, query,
+		formatTimeValue(estimate.IssueDate),
+		validUntil,
+		string(estimate.Status),
+		estimate.Total,
+		estimate.Notes,
+		invoiceID,
+		formatTimeValue(estimate.UpdatedAt),
+		estimate.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update estimate: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("estimate not found")
+	}
+
+	return nil
+}
+
+// AddLineItem adds a line item to an estimate
+func (r *EstimateRepo) AddLineItem(ctx context.Context, estimateID int64, item *domain.EstimateLineItem) error {
+	if err := item.Validate(); err != nil {
+		return fmt.Errorf("invalid line item: %w", err)
+	}
+
+	query := `
+		INSERT INTO estimate_line_items (estimate_id, description, hours, rate, amount)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, estimateID, item.Description, item.Hours, item.Rate, item.Amount)
+	if err != nil {
+		return fmt.Errorf("failed to add line item: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get line item ID: %w", err)
+	}
+
+	item.ID = id
+	item.EstimateID = estimateID
+	return nil
+}
+
+// DeleteLineItem removes a specific line item from an estimate
+func (r *EstimateRepo) DeleteLineItem(ctx context.Context, estimateID int64, lineItemID int64) error {
+	query := `
+		DELETE FROM estimate_line_items
+		WHERE id = ? AND estimate_id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, lineItemID, estimateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete line item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("line item not found")
+	}
+
+	return nil
+}
+
+// GetLineItems retrieves an estimate's line items in the order added
+func (r *EstimateRepo) GetLineItems(ctx context.Context, estimateID int64) ([]*domain.EstimateLineItem, error) {
+	query := `
+		SELECT id, estimate_id, description, hours, rate, amount
+		FROM estimate_line_items
+		WHERE estimate_id = ?
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, estimateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get line items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*domain.EstimateLineItem, 0)
+	for rows.Next() {
+		item := &domain.EstimateLineItem{}
+		if err := rows.Scan(&item.ID, &item.EstimateID, &item.Description, &item.Hours, &item.Rate, &item.Amount); err != nil {
+			return nil, fmt.Errorf("failed to scan line item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating line items: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetNextEstimateNumber generates the next estimate number in format "PREFIX-YEAR-SEQUENCE"
+func (r *EstimateRepo) GetNextEstimateNumber(ctx context.Context, prefix string, year int) (string, error) {
+	query := `
+		SELECT estimate_number
+		FROM estimates
+		WHERE estimate_number LIKE ?
+		ORDER BY estimate_number DESC
+		LIMIT 1
+	`
+
+	pattern := fmt.Sprintf("%s-%d-%%", prefix, year)
+	var lastNumber string
+
+	err := r.db.QueryRowContext(ctx, query, pattern).Scan(&lastNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Sprintf("%s-%d-001", prefix, year), nil
+		}
+		return "", fmt.Errorf("failed to get last estimate number: %w", err)
+	}
+
+	var lastSeq int
+	if _, err := fmt.Sscanf(lastNumber, prefix+"-%d-%d", &year, &lastSeq); err != nil {
+		return fmt.Sprintf("%s-%d-001", prefix, year), nil
+	}
+
+	return fmt.Sprintf("%s-%d-%03d", prefix, year, lastSeq+1), nil
+}
+
+// estimateNullables returns the optional columns as NULL when unset
+func estimateNullables(estimate *domain.Estimate) (validUntil, invoiceID interface{}) {
+	if estimate.ValidUntil != nil {
+		validUntil = formatTimeValue(*estimate.ValidUntil)
+	}
+	if estimate.InvoiceID != nil {
+		invoiceID = *estimate.InvoiceID
+	}
+	return validUntil, invoiceID
+}
+
+// scanEstimate reads one estimate row selected with estimateColumns
+func scanEstimate(row interface{ Scan(...any) error }) (*domain.Estimate, error) {
+	estimate := &domain.Estimate{}
+	var issueDate, status, createdAt, updatedAt string
+	var validUntil sql.NullString
+	var invoiceID sql.NullInt64
+
+	err := row.Scan(
+		&estimate.ID,
+		&estimate.EstimateNumber,
+		&estimate.ClientID,
+		&issueDate,
+		&validUntil,
+		&status,
+		&estimate.Total,
+		&estimate.Notes,
+		&invoiceID,
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate.Status = domain.EstimateStatus(status)
+	if estimate.IssueDate, err = parseTime(issueDate); err != nil {
+		return nil, fmt.Errorf("failed to parse issue_date: %w", err)
+	}
+	if validUntil.Valid {
+		t, err := parseTime(validUntil.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse valid_until: %w", err)
+		}
+		estimate.ValidUntil = &t
+	}
+	if invoiceID.Valid {
+		estimate.InvoiceID = &invoiceID.Int64
+	}
+	if estimate.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if estimate.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+
+	return estimate, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestEstimateRepo_CreateWithLineItems(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	estimate := domain.NewEstimate("EST-2026-001", client.ID, day(2))
+	validUntil := day(20)
+	estimate.ValidUntil = &validUntil
+	estimate.Notes = "Fixed scope"
+	if err := env.estimates.Create(env.ctx, estimate); err != nil {
+		t.Fatalf("failed to create estimate: %v", err)
+	}
+
+	items := []*domain.EstimateLineItem{
+		domain.NewProjectedLineItem("Design", 10, 100),
+		domain.NewFixedLineItem("Hosting setup", 25000),
+	}
+	for _, item := range items {
+		if err := env.estimates.AddLineItem(env.ctx, estimate.ID, item); err != nil {
+			t.Fatalf("failed to add line item: %v", err)
+		}
+	}
+	if err := env.estimates.AddLineItem(env.ctx, estimate.ID, domain.NewFixedLineItem(" ", 1000)); err == nil {
+		t.Fatalf("expected a line item without description to be rejected")
+	}
+
+	got, err := env.estimates.GetByID(env.ctx, estimate.ID)
+	if err != nil {
+		t.Fatalf("failed to get estimate: %v", err)
+	}
+	if got.EstimateNumber != "EST-2026-001" || got.Status != domain.EstimateStatusDraft || got.Notes != "Fixed scope" {
+		t.Fatalf("unexpected estimate: %+v", got)
+	}
+	if got.ValidUntil == nil || !got.ValidUntil.Equal(validUntil) || got.InvoiceID != nil {
+		t.Fatalf("expected valid_until to round-trip and no invoice, got %+v", got)
+	}
+
+	lineItems, err := env.estimates.GetLineItems(env.ctx, estimate.ID)
+	if err != nil {
+		t.Fatalf("failed to get line items: %v", err)
+	}
+	if len(lineItems) != 2 || lineItems[0].Amount != 100000 || !lineItems[1].IsFixed() {
+		t.Fatalf("unexpected line items: %+v", lineItems)
+	}
+
+	if err := env.estimates.DeleteLineItem(env.ctx, estimate.ID, lineItems[1].ID); err != nil {
+		t.Fatalf("failed to delete line item: %v", err)
+	}
+	lineItems, _ = env.estimates.GetLineItems(env.ctx, estimate.ID)
+	if len(lineItems) != 1 {
+		t.Fatalf("expected 1 line item after delete, got %d", len(lineItems))
+	}
+}
+
+func TestEstimateRepo_UpdateLinksInvoice(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	estimate := domain.NewEstimate("EST-2026-001", client.ID, day(2))
+	if err := env.estimates.Create(env.ctx, estimate); err != nil {
+		t.Fatalf("failed to create estimate: %v", err)
+	}
+	invoice := env.draftInvoice(client, "INV-2026-001")
+
+	estimate.Status = domain.EstimateStatusAccepted
+	estimate.Total = 125000
+	estimate.InvoiceID = &invoice.ID
+	if err := env.estimates.Update(env.ctx, estimate); err != nil {
+		t.Fatalf("failed to update estimate: %v", err)
+	}
+
+	accepted := domain.EstimateStatusAccepted
+	list, err := env.estimates.List(env.ctx, &client.ID, &accepted)
+	if err != nil {
+		t.Fatalf("failed to list estimates: %v", err)
+	}
+	if len(list) != 1 || list[0].Total != 125000 || list[0].InvoiceID == nil || *list[0].InvoiceID != invoice.ID {
+		t.Fatalf("unexpected estimates: %+v", list)
+	}
+
+	// Items copied from the estimate are kept apart from adjustments
+	item := &domain.InvoiceLineItem{EstimateID: estimate.ID, Date: day(3), Description: "Design", Hours: 10, Rate: 100, Amount: 100000}
+	if err := env.invoices.AddLineItem(env.ctx, invoice.ID, item); err != nil {
+		t.Fatalf("failed to add line item: %v", err)
+	}
+	items, err := env.invoices.GetLineItems(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get line items: %v", err)
+	}
+	if len(items) != 1 || items[0].EstimateID != estimate.ID || items[0].IsAdjustment() {
+		t.Fatalf("expected an estimated line item, got %+v", items)
+	}
+}
+
+func TestEstimateRepo_GetNextEstimateNumber(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	next, err := env.estimates.GetNextEstimateNumber(env.ctx, "EST", 2026)
+	if err != nil || next != "EST-2026-001" {
+		t.Fatalf("expected EST-2026-001, got %q (%v)", next, err)
+	}
+
+	for _, number := range []string{"EST-2026-001", "EST-2026-002"} {
+		if err := env.estimates.Create(env.ctx, domain.NewEstimate(number, client.ID, day(2))); err != nil {
+			t.Fatalf("failed to create estimate: %v", err)
+		}
+	}
+	// Invoice numbers do not affect the estimate sequence
+	env.draftInvoice(client, "INV-2026-007")
+
+	next, err = env.estimates.GetNextEstimateNumber(env.ctx, "EST", 2026)
+	if err != nil || next != "EST-2026-003" {
+		t.Fatalf("expected EST-2026-003, got %q (%v)", next, err)
+	}
+}
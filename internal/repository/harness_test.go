@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// testEnv is a migrated, throwaway SQLCipher database with a repository of
+// each kind bound to it
+type testEnv struct {
+	t   *testing.T
+	ctx context.Context
+	db  *db.DB
+
+	clients     *ClientRepo
+	entries     *EntryRepo
+	invoices    *InvoiceRepo
+	timer       *TimerRepo
+	attachments *AttachmentRepo
+	estimates   *EstimateRepo
+	contracts   *ContractRepo
+	timeOff     *TimeOffRepo
+	deliveries  *DeliveryRepo
+	search      *SearchRepo
+}
+
+// newTestEnv opens a fresh database in the test's temp directory. A file is
+// used rather than :memory: because each pooled connection would otherwise
+// see its own empty database.
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"), "test")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if _, err := database.RunMigrations(); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	return &testEnv{
+		t:           t,
+		ctx:         context.Background(),
+		db:          database,
+		clients:     NewClientRepo(database),
+		entries:     NewEntryRepo(database),
+		invoices:    NewInvoiceRepo(database),
+		timer:       NewTimerRepo(database),
+		attachments: NewAttachmentRepo(database),
+		estimates:   NewEstimateRepo(database),
+		contracts:   NewContractRepo(database),
+		timeOff:     NewTimeOffRepo(database),
+		deliveries:  NewDeliveryRepo(database),
+		search:      NewSearchRepo(database),
+	}
+}
+
+// client creates a client fixture
+func (e *testEnv) client(name string, rate float64) *domain.Client {
+	e.t.Helper()
+	client := domain.NewClient(name, rate)
+	if err := e.clients.Create(e.ctx, client); err != nil {
+		e.t.Fatalf("failed to create client %q: %v", name, err)
+	}
+	return client
+}
+
+// entry creates a completed time entry fixture at the client's rate
+func (e *testEnv) entry(client *domain.Client, description string, start time.Time, d time.Duration) *domain.TimeEntry {
+	e.t.Helper()
+	entry := domain.NewTimeEntry(client.ID, description, client.HourlyRate)
+	entry.StartTime = start
+	entry.Stop(start.Add(d))
+	if err := e.entries.Create(e.ctx, entry); err != nil {
+		e.t.Fatalf("failed to create entry %q: %v", description, err)
+	}
+	return entry
+}
+
+// draftInvoice creates a draft invoice fixture with a line item per entry
+func (e *testEnv) draftInvoice(client *domain.Client, number string, entries ...*domain.TimeEntry) *domain.Invoice {
+	e.t.Helper()
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	invoice := domain.NewInvoice(number, client.ID, start, start.AddDate(0, 1, -1))
+	if err := e.invoices.Create(e.ctx, invoice); err != nil {
+		e.t.Fatalf("failed to create invoice %s: %v", number, err)
+	}
+	for _, entry := range entries {
+		item := &domain.InvoiceLineItem{
+			InvoiceID:   invoice.ID,
+			EntryID:     entry.ID,
+			Date:        entry.StartTime,
+			Description: entry.Description,
+			Hours:       entry.Duration().Hours(),
+			Rate:        entry.HourlyRate,
+			Amount:      entry.Amount(),
+		}
+		if err := e.invoices.AddLineItem(e.ctx, invoice.ID, item); err != nil {
+			e.t.Fatalf("failed to add line item: %v", err)
+		}
+	}
+	return invoice
+}
+
+// day returns 09:00 UTC on the given day of March 2026
+func day(d int) time.Time {
+	return time.Date(2026, time.March, d, 9, 0, 0, 0, time.UTC)
+}
@@ -1,18 +1,97 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/andy/timesink/internal/domain"
 )
 
-// timeLayout is the RFC3339 format for storing times in SQLite
+// timeLayout is the RFC3339 format for storing times in SQLite. Times are
+// always stored in UTC so that string comparison matches chronological order
+// and durations survive DST changes and travel.
 const timeLayout = time.RFC3339
 
-// parseTime parses a time string in RFC3339 format
+// parseTime parses a stored RFC3339 time and converts it to local time for display
 func parseTime(s string) (time.Time, error) {
-	return time.Parse(timeLayout, s)
+	t, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Local(), nil
+}
+
+// formatTimeValue formats a time as RFC3339 in UTC for storage
+func formatTimeValue(t time.Time) string {
+	return t.UTC().Format(timeLayout)
 }
 
-// formatTime returns the current time formatted as RFC3339
+// formatTime returns the current time formatted for storage
 func formatTime() string {
-	return time.Now().Format(timeLayout)
+	return formatTimeValue(time.Now())
+}
+
+// formatPauses stores pauses one per line as start/end, empty if there are
+// none
+func formatPauses(pauses []domain.Pause) string {
+	lines := make([]string, len(pauses))
+	for i, p := range pauses {
+		lines[i] = formatTimeValue(p.Start) + "/" + formatTimeValue(p.End)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parsePauses reads pauses stored by formatPauses
+func parsePauses(s string) ([]domain.Pause, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var pauses []domain.Pause
+	for _, line := range strings.Split(s, "\n") {
+		from, to, ok := strings.Cut(line, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid pause %q", line)
+		}
+		start, err := parseTime(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTime(to)
+		if err != nil {
+			return nil, err
+		}
+		pauses = append(pauses, domain.Pause{Start: start, End: end})
+	}
+	return pauses, nil
+}
+
+// maxIDsPerQuery caps the placeholders in one IN (...) list, well inside
+// SQLite's limit on bound parameters
+const maxIDsPerQuery = 500
+
+// forIDChunks calls fn with ids split into slices of at most maxIDsPerQuery
+func forIDChunks(ids []int64, fn func(chunk []int64) error) error {
+	for len(ids) > 0 {
+		n := min(len(ids), maxIDsPerQuery)
+		if err := fn(ids[:n]); err != nil {
+			return err
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
+
+// placeholders returns n comma-separated bind parameters, e.g. "?, ?, ?"
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// int64Args converts ids to query arguments
+func int64Args(ids []int64) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
 }
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func loadNewYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	return loc
+}
+
+func TestFormatTimeValue_StoresUTC(t *testing.T) {
+	loc := loadNewYork(t)
+	local := time.Date(2026, time.July, 1, 9, 30, 0, 0, loc)
+
+	got := formatTimeValue(local)
+	if got != "2026-07-01T13:30:00Z" {
+		t.Fatalf("expected UTC timestamp, got %s", got)
+	}
+
+	parsed, err := parseTime(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !parsed.Equal(local) {
+		t.Fatalf("expected %v, got %v", local, parsed)
+	}
+	if parsed.Location() != time.Local {
+		t.Fatalf("expected parsed time in local zone, got %v", parsed.Location())
+	}
+}
+
+func TestFormatTimeValue_DSTBoundaries(t *testing.T) {
+	loc := loadNewYork(t)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  time.Duration
+	}{
+		{
+			// Clocks fall back 02:00 EDT -> 01:00 EST
+			name:  "fall back",
+			start: time.Date(2026, time.November, 1, 0, 30, 0, 0, loc),
+			end:   time.Date(2026, time.November, 1, 3, 30, 0, 0, loc),
+			want:  4 * time.Hour,
+		},
+		{
+			// Clocks spring forward 02:00 EST -> 03:00 EDT
+			name:  "spring forward",
+			start: time.Date(2026, time.March, 8, 0, 30, 0, 0, loc),
+			end:   time.Date(2026, time.March, 8, 3, 30, 0, 0, loc),
+			want:  2 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := parseTime(formatTimeValue(tt.start))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			end, err := parseTime(formatTimeValue(tt.end))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := end.Sub(start); got != tt.want {
+				t.Fatalf("expected duration %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFormatTimeValue_SortsChronologically(t *testing.T) {
+	loc := loadNewYork(t)
+
+	// 01:45 EDT happens before 01:15 EST on the fall-back night; the stored
+	// strings must sort the same way or date-range queries break.
+	edt := time.Date(2026, time.November, 1, 1, 45, 0, 0, loc)
+	est := edt.Add(30 * time.Minute)
+	if est.Hour() != 1 || est.Minute() != 15 {
+		t.Fatalf("expected 01:15 after fall back, got %s", est.Format("15:04"))
+	}
+
+	stored := []string{formatTimeValue(est), formatTimeValue(edt)}
+	sort.Strings(stored)
+	if stored[0] != formatTimeValue(edt) {
+		t.Fatalf("expected EDT time to sort first, got %v", stored)
+	}
+}
+
+func TestEntryRepo_ListAcrossDST(t *testing.T) {
+	loc := loadNewYork(t)
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	entries := env.entries
+	entry := domain.NewTimeEntry(client.ID, "overnight", 100)
+	entry.StartTime = time.Date(2026, time.November, 1, 0, 30, 0, 0, loc)
+	entry.Stop(time.Date(2026, time.November, 1, 3, 30, 0, 0, loc))
+	if err := entries.Create(env.ctx, entry); err != nil {
+		t.Fatalf("failed to create entry: %v", err)
+	}
+
+	got, err := entries.GetByID(env.ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("failed to get entry: %v", err)
+	}
+	if got.Duration() != 4*time.Hour {
+		t.Fatalf("expected 4h duration, got %v", got.Duration())
+	}
+
+	// The entry starts at 04:30Z; ranges expressed in UTC must agree
+	start := time.Date(2026, time.November, 1, 4, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.November, 1, 5, 0, 0, 0, time.UTC)
+	list, err := entries.List(env.ctx, nil, &start, &end, true)
+	if err != nil {
+		t.Fatalf("failed to list entries: %v", err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected 1 entry in range, got %d", len(list))
+	}
+
+	start = time.Date(2026, time.November, 1, 4, 31, 0, 0, time.UTC)
+	list, err = entries.List(env.ctx, nil, &start, &end, true)
+	if err != nil {
+		t.Fatalf("failed to list entries: %v", err)
+	}
+	if len(list) != 0 {
+		t.Fatalf("expected no entries in range, got %d", len(list))
+	}
+}
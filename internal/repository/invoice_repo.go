@@ -5,15 +5,23 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/db"
 	"github.com/andy/timesink/internal/domain"
+	sqlite3 "github.com/mutecomm/go-sqlcipher/v4"
 )
 
+// ErrDuplicateNumber is returned by Create when another invoice already has
+// the number, usually one created by another timesink process between
+// GetNextInvoiceNumber and Create. Asking for the next number again and
+// retrying resolves it.
+var ErrDuplicateNumber = errors.New("invoice number already taken")
+
 // InvoiceRepo is a SQLite implementation of InvoiceRepository
 type InvoiceRepo struct {
-	db *db.DB
+	db conn
 }
 
 // NewInvoiceRepo creates a new InvoiceRepo
@@ -31,24 +39,24 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error
 		INSERT INTO invoices (
 			invoice_number, client_id, period_start, period_end,
 			subtotal, tax_rate, tax_amount, total, status,
-			due_date, paid_date, created_at, updated_at
+			due_date, paid_date, notes, payment_instructions, created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var dueDate, paidDate interface{}
 	if invoice.DueDate != nil {
-		dueDate = invoice.DueDate.Format(timeLayout)
+		dueDate = formatTimeValue(*invoice.DueDate)
 	}
 	if invoice.PaidDate != nil {
-		paidDate = invoice.PaidDate.Format(timeLayout)
+		paidDate = formatTimeValue(*invoice.PaidDate)
 	}
 
 	result, err := r.db.ExecContext(ctx, query,
 		invoice.InvoiceNumber,
 		invoice.ClientID,
-		invoice.PeriodStart.Format(timeLayout),
-		invoice.PeriodEnd.Format(timeLayout),
+		formatTimeValue(invoice.PeriodStart),
+		formatTimeValue(invoice.PeriodEnd),
 		invoice.Subtotal,
 		invoice.TaxRate,
 		invoice.TaxAmount,
@@ -56,9 +64,14 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error
 		string(invoice.Status),
 		dueDate,
 		paidDate,
-		invoice.CreatedAt.Format(timeLayout),
-		invoice.UpdatedAt.Format(timeLayout),
+		invoice.Footer.Notes,
+		invoice.Footer.PaymentInstructions,
+		formatTimeValue(invoice.CreatedAt),
+		formatTimeValue(invoice.UpdatedAt),
 	)
+	if isDuplicateNumber(err) {
+		return fmt.Errorf("%w: %s", ErrDuplicateNumber, invoice.InvoiceNumber)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create invoice: %w", err)
 	}
@@ -69,6 +82,7 @@ func (r *InvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error
 	}
 
 	invoice.ID = id
+	invoice.Version = 1
 	return nil
 }
 
@@ -77,7 +91,7 @@ func (r *InvoiceRepo) GetByID(ctx context.Context, id int64) (*domain.Invoice, e
 	query := `
 		SELECT id, invoice_number, client_id, period_start, period_end,
 		       subtotal, tax_rate, tax_amount, total, status,
-		       due_date, paid_date, created_at, updated_at
+		       due_date, paid_date, notes, payment_instructions, version, created_at, updated_at
 		FROM invoices
 		WHERE id = ?
 	`
@@ -99,6 +113,9 @@ func (r *InvoiceRepo) GetByID(ctx context.Context, id int64) (*domain.Invoice, e
 		&status,
 		&dueDate,
 		&paidDate,
+		&invoice.Footer.Notes,
+		&invoice.Footer.PaymentInstructions,
+		&invoice.Version,
 		&createdAt,
 		&updatedAt,
 	)
@@ -121,7 +138,7 @@ func (r *InvoiceRepo) GetByNumber(ctx context.Context, number string) (*domain.I
 	query := `
 		SELECT id, invoice_number, client_id, period_start, period_end,
 		       subtotal, tax_rate, tax_amount, total, status,
-		       due_date, paid_date, created_at, updated_at
+		       due_date, paid_date, notes, payment_instructions, version, created_at, updated_at
 		FROM invoices
 		WHERE invoice_number = ?
 	`
@@ -143,6 +160,9 @@ func (r *InvoiceRepo) GetByNumber(ctx context.Context, number string) (*domain.I
 		&status,
 		&dueDate,
 		&paidDate,
+		&invoice.Footer.Notes,
+		&invoice.Footer.PaymentInstructions,
+		&invoice.Version,
 		&createdAt,
 		&updatedAt,
 	)
@@ -160,12 +180,13 @@ func (r *InvoiceRepo) GetByNumber(ctx context.Context, number string) (*domain.I
 	return invoice, nil
 }
 
-// List retrieves invoices with optional filters
+// List retrieves invoices with optional filters. Void invoices are only
+// listed when asked for by status.
 func (r *InvoiceRepo) List(ctx context.Context, clientID *int64, status *domain.InvoiceStatus) ([]*domain.Invoice, error) {
 	query := `
 		SELECT id, invoice_number, client_id, period_start, period_end,
 		       subtotal, tax_rate, tax_amount, total, status,
-		       due_date, paid_date, created_at, updated_at
+		       due_date, paid_date, notes, payment_instructions, version, created_at, updated_at
 		FROM invoices
 		WHERE 1=1
 	`
@@ -179,6 +200,9 @@ func (r *InvoiceRepo) List(ctx context.Context, clientID *int64, status *domain.
 	if status != nil {
 		query += " AND status = ?"
 		args = append(args, string(*status))
+	} else {
+		query += " AND status != ?"
+		args = append(args, string(domain.InvoiceStatusVoid))
 	}
 
 	query += " ORDER BY created_at DESC"
@@ -208,6 +232,9 @@ func (r *InvoiceRepo) List(ctx context.Context, clientID *int64, status *domain.
 			&statusStr,
 			&dueDate,
 			&paidDate,
+			&invoice.Footer.Notes,
+			&invoice.Footer.PaymentInstructions,
+			&invoice.Version,
 			&createdAt,
 			&updatedAt,
 		)
@@ -239,16 +266,17 @@ func (r *InvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error
 		UPDATE invoices
 		SET invoice_number = ?, client_id = ?, period_start = ?, period_end = ?,
 		    subtotal = ?, tax_rate = ?, tax_amount = ?, total = ?, status = ?,
-		    due_date = ?, paid_date = ?, updated_at = ?
-		WHERE id = ?
+		    due_date = ?, paid_date = ?, notes = ?, payment_instructions = ?, updated_at = ?,
+		    version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
 	var dueDate, paidDate interface{}
 	if invoice.DueDate != nil {
-		dueDate = invoice.DueDate.Format(timeLayout)
+		dueDate = formatTimeValue(*invoice.DueDate)
 	}
 	if invoice.PaidDate != nil {
-		paidDate = invoice.PaidDate.Format(timeLayout)
+		paidDate = formatTimeValue(*invoice.PaidDate)
 	}
 
 	invoice.UpdatedAt = time.Now()
@@ -256,8 +284,8 @@ func (r *InvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error
 	result, err := r.db.ExecContext(ctx, query,
 		invoice.InvoiceNumber,
 		invoice.ClientID,
-		invoice.PeriodStart.Format(timeLayout),
-		invoice.PeriodEnd.Format(timeLayout),
+		formatTimeValue(invoice.PeriodStart),
+		formatTimeValue(invoice.PeriodEnd),
 		invoice.Subtotal,
 		invoice.TaxRate,
 		invoice.TaxAmount,
@@ -265,8 +293,11 @@ func (r *InvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error
 		string(invoice.Status),
 		dueDate,
 		paidDate,
-		invoice.UpdatedAt.Format(timeLayout),
+		invoice.Footer.Notes,
+		invoice.Footer.PaymentInstructions,
+		formatTimeValue(invoice.UpdatedAt),
 		invoice.ID,
+		invoice.Version,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update invoice: %w", err)
@@ -277,7 +308,54 @@ func (r *InvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("invoice not found")
+		return missedUpdate(ctx, r.db, "invoices", "invoice", invoice.ID, invoice.Version, fmt.Errorf("invoice not found"))
+	}
+
+	invoice.Version++
+	return nil
+}
+
+// Void deletes a draft invoice. Its line items are removed, releasing the
+// entries, estimate and interest charge they billed, and the invoice is kept
+// as void with nothing owed so its number is not reused.
+func (r *InvoiceRepo) Void(ctx context.Context, id int64) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := formatTime()
+
+	// Guard against the draft being finalized meanwhile
+	result, err := tx.ExecContext(ctx, `
+		UPDATE invoices
+		SET status = ?, subtotal = 0, tax_amount = 0, total = 0, updated_at = ?
+		WHERE id = ? AND status = ?
+	`, string(domain.InvoiceStatusVoid), now, id, string(domain.InvoiceStatusDraft))
+	if err != nil {
+		return fmt.Errorf("failed to void invoice: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("invoice not found or not a draft")
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM invoice_line_items WHERE invoice_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete line items: %w", err)
+	}
+
+	// An estimate converted into the draft can be converted again
+	if _, err := tx.ExecContext(ctx, "UPDATE estimates SET invoice_id = NULL, updated_at = ? WHERE invoice_id = ?", now, id); err != nil {
+		return fmt.Errorf("failed to release estimate: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
@@ -286,18 +364,36 @@ func (r *InvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error
 // AddLineItem adds a line item to an invoice
 func (r *InvoiceRepo) AddLineItem(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error {
 	query := `
-		INSERT INTO invoice_line_items (invoice_id, entry_id, date, description, hours, rate, amount)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO invoice_line_items (invoice_id, entry_id, estimate_id, interest_invoice_id, date, description, hours, rate, amount, rate_reason, original_description, role)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	// Adjustments derived from billing rules, items copied from an estimate
+	// and interest charges have no entry
+	var entryID, estimateID, interestInvoiceID interface{}
+	if item.EntryID != 0 {
+		entryID = item.EntryID
+	}
+	if item.EstimateID != 0 {
+		estimateID = item.EstimateID
+	}
+	if item.InterestInvoiceID != 0 {
+		interestInvoiceID = item.InterestInvoiceID
+	}
+
 	result, err := r.db.ExecContext(ctx, query,
 		invoiceID,
-		item.EntryID,
-		item.Date.Format(timeLayout),
+		entryID,
+		estimateID,
+		interestInvoiceID,
+		formatTimeValue(item.Date),
 		item.Description,
 		item.Hours,
 		item.Rate,
 		item.Amount,
+		item.RateReason,
+		item.OriginalDescription,
+		item.Role,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to add line item: %w", err)
@@ -337,13 +433,35 @@ func (r *InvoiceRepo) DeleteLineItem(ctx context.Context, invoiceID int64, lineI
 	return nil
 }
 
+// SetLineItemDescription saves a line item's description and the original
+// it was reworded from
+func (r *InvoiceRepo) SetLineItemDescription(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE invoice_line_items
+		SET description = ?, original_description = ?
+		WHERE id = ? AND invoice_id = ?
+	`, item.Description, item.OriginalDescription, item.ID, invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to update line item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("line item not found")
+	}
+	return nil
+}
+
 // GetLineItems retrieves all line items for an invoice
 func (r *InvoiceRepo) GetLineItems(ctx context.Context, invoiceID int64) ([]*domain.InvoiceLineItem, error) {
 	query := `
-		SELECT id, invoice_id, entry_id, date, description, hours, rate, amount
+		SELECT id, invoice_id, entry_id, estimate_id, interest_invoice_id, date, description, hours, rate, amount, rate_reason, original_description, role
 		FROM invoice_line_items
 		WHERE invoice_id = ?
-		ORDER BY date
+		ORDER BY entry_id IS NULL, estimate_id IS NULL, interest_invoice_id IS NOT NULL, date, id
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, invoiceID)
@@ -356,16 +474,22 @@ func (r *InvoiceRepo) GetLineItems(ctx context.Context, invoiceID int64) ([]*dom
 	for rows.Next() {
 		item := &domain.InvoiceLineItem{}
 		var date string
+		var entryID, estimateID, interestInvoiceID sql.NullInt64
 
 		err := rows.Scan(
 			&item.ID,
 			&item.InvoiceID,
-			&item.EntryID,
+			&entryID,
+			&estimateID,
+			&interestInvoiceID,
 			&date,
 			&item.Description,
 			&item.Hours,
 			&item.Rate,
 			&item.Amount,
+			&item.RateReason,
+			&item.OriginalDescription,
+			&item.Role,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan line item: %w", err)
@@ -374,6 +498,9 @@ func (r *InvoiceRepo) GetLineItems(ctx context.Context, invoiceID int64) ([]*dom
 		if item.Date, err = parseTime(date); err != nil {
 			return nil, fmt.Errorf("failed to parse date: %w", err)
 		}
+		item.EntryID = entryID.Int64
+		item.EstimateID = estimateID.Int64
+		item.InterestInvoiceID = interestInvoiceID.Int64
 
 		items = append(items, item)
 	}
@@ -385,41 +512,195 @@ func (r *InvoiceRepo) GetLineItems(ctx context.Context, invoiceID int64) ([]*dom
 	return items, nil
 }
 
-// GetNextInvoiceNumber generates the next invoice number in format "PREFIX-YEAR-SEQUENCE"
-func (r *InvoiceRepo) GetNextInvoiceNumber(ctx context.Context, prefix string, year int) (string, error) {
-	// Find the highest sequence number for the given prefix and year
+// SetTaxes replaces an invoice's tax lines, keeping their order
+func (r *InvoiceRepo) SetTaxes(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM invoice_taxes WHERE invoice_id = ?", invoiceID); err != nil {
+		return fmt.Errorf("failed to clear invoice taxes: %w", err)
+	}
+
 	query := `
-		SELECT invoice_number
-		FROM invoices
-		WHERE invoice_number LIKE ?
-		ORDER BY invoice_number DESC
-		LIMIT 1
+		INSERT INTO invoice_taxes (invoice_id, position, label, rate, amount, note)
+		VALUES (?, ?, ?, ?, ?, ?)
 	`
+	for i, tax := range taxes {
+		result, err := tx.ExecContext(ctx, query, invoiceID, i, tax.Label, tax.Rate, tax.Amount, tax.Note)
+		if err != nil {
+			return fmt.Errorf("failed to add invoice tax: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get invoice tax ID: %w", err)
+		}
+		tax.ID = id
+		tax.InvoiceID = invoiceID
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit invoice taxes: %w", err)
+	}
+	return nil
+}
 
-	pattern := fmt.Sprintf("%s-%d-%%", prefix, year)
-	var lastNumber string
+// GetTaxes retrieves an invoice's tax lines in order
+func (r *InvoiceRepo) GetTaxes(ctx context.Context, invoiceID int64) ([]*domain.InvoiceTax, error) {
+	query := `
+		SELECT id, invoice_id, label, rate, amount, note
+		FROM invoice_taxes
+		WHERE invoice_id = ?
+		ORDER BY position, id
+	`
 
-	err := r.db.QueryRowContext(ctx, query, pattern).Scan(&lastNumber)
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			// No existing invoices for this year, start at 001
-			return fmt.Sprintf("%s-%d-001", prefix, year), nil
+		return nil, fmt.Errorf("failed to get invoice taxes: %w", err)
+	}
+	defer rows.Close()
+
+	taxes := make([]*domain.InvoiceTax, 0)
+	for rows.Next() {
+		tax := &domain.InvoiceTax{}
+		if err := rows.Scan(&tax.ID, &tax.InvoiceID, &tax.Label, &tax.Rate, &tax.Amount, &tax.Note); err != nil {
+			return nil, fmt.Errorf("failed to scan invoice tax: %w", err)
 		}
-		return "", fmt.Errorf("failed to get last invoice number: %w", err)
+		taxes = append(taxes, tax)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invoice taxes: %w", err)
+	}
+
+	return taxes, nil
+}
+
+// AddDueDateChange records a change to an invoice's due date
+func (r *InvoiceRepo) AddDueDateChange(ctx context.Context, change *domain.DueDateChange) error {
+	var from interface{}
+	if change.From != nil {
+		from = formatTimeValue(*change.From)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO invoice_due_changes (invoice_id, old_due_date, new_due_date, note, changed_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, change.InvoiceID, from, formatTimeValue(change.To), change.Note, formatTimeValue(change.ChangedAt))
+	if err != nil {
+		return fmt.Errorf("failed to record due date change: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get due date change ID: %w", err)
+	}
+	change.ID = id
+	return nil
+}
+
+// GetDueDateChanges retrieves the recorded due date changes of an invoice,
+// oldest first
+func (r *InvoiceRepo) GetDueDateChanges(ctx context.Context, invoiceID int64) ([]*domain.DueDateChange, error) {
+	query := `
+		SELECT id, invoice_id, old_due_date, new_due_date, note, changed_at
+		FROM invoice_due_changes
+		WHERE invoice_id = ?
+		ORDER BY changed_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due date changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.DueDateChange, 0)
+	for rows.Next() {
+		change := &domain.DueDateChange{}
+		var from sql.NullString
+		var to, changedAt string
+		if err := rows.Scan(&change.ID, &change.InvoiceID, &from, &to, &change.Note, &changedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan due date change: %w", err)
+		}
+		if from.Valid {
+			t, err := parseTime(from.String)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse old_due_date: %w", err)
+			}
+			change.From = &t
+		}
+		if change.To, err = parseTime(to); err != nil {
+			return nil, fmt.Errorf("failed to parse new_due_date: %w", err)
+		}
+		if change.ChangedAt, err = parseTime(changedAt); err != nil {
+			return nil, fmt.Errorf("failed to parse changed_at: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due date changes: %w", err)
 	}
 
-	// Parse the sequence number from the last invoice
-	// Format: PREFIX-YEAR-SEQUENCE (e.g., "INV-2026-005")
+	return changes, nil
+}
+
+// GetNextInvoiceNumber generates the next invoice number in format "PREFIX-YEAR-SEQUENCE"
+func (r *InvoiceRepo) GetNextInvoiceNumber(ctx context.Context, prefix string, year int) (string, error) {
+	// Find the highest sequence number for the given prefix and year. The
+	// sequence is compared as a number, since "INV-2026-999" sorts after
+	// "INV-2026-1000" as text.
+	query := `
+		SELECT COALESCE(MAX(CAST(substr(invoice_number, length(?1) + 1) AS INTEGER)), 0)
+		FROM invoices
+		WHERE substr(invoice_number, 1, length(?1)) = ?1
+	`
+
+	head := fmt.Sprintf("%s-%d-", prefix, year)
 	var lastSeq int
-	_, err = fmt.Sscanf(lastNumber, prefix+"-%d-%d", &year, &lastSeq)
+	err := r.db.QueryRowContext(ctx, query, head).Scan(&lastSeq)
 	if err != nil {
-		// Fallback: start at 001 if we can't parse
-		return fmt.Sprintf("%s-%d-001", prefix, year), nil
+		return "", fmt.Errorf("failed to get last invoice number: %w", err)
+	}
+
+	// Increment and format, e.g. "INV-2026-005"
+	return fmt.Sprintf("%s%03d", head, lastSeq+1), nil
+}
+
+// isDuplicateNumber reports whether err is the unique index on invoice
+// numbers rejecting an insert
+func isDuplicateNumber(err error) bool {
+	var sqliteErr sqlite3.Error
+	return errors.As(err, &sqliteErr) &&
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique &&
+		strings.Contains(sqliteErr.Error(), "invoices.invoice_number")
+}
+
+// GetLastPeriodEnd returns the latest period end of the client's non-void
+// invoices, or nil if there are none
+func (r *InvoiceRepo) GetLastPeriodEnd(ctx context.Context, clientID int64) (*time.Time, error) {
+	query := `
+		SELECT MAX(period_end)
+		FROM invoices
+		WHERE client_id = ? AND status != ?
+	`
+
+	var periodEnd sql.NullString
+	if err := r.db.QueryRowContext(ctx, query, clientID, string(domain.InvoiceStatusVoid)).Scan(&periodEnd); err != nil {
+		return nil, fmt.Errorf("failed to get last period end: %w", err)
+	}
+	if !periodEnd.Valid {
+		return nil, nil
 	}
 
-	// Increment and format
-	nextSeq := lastSeq + 1
-	return fmt.Sprintf("%s-%d-%03d", prefix, year, nextSeq), nil
+	t, err := parseTime(periodEnd.String)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse period_end: %w", err)
+	}
+	return &t, nil
 }
 
 // scanInvoice is a helper to parse invoice fields
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestInvoiceRepo_CreateWithLineItems(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	a := env.entry(client, "design", day(2), 2*time.Hour)
+	b := env.entry(client, "build", day(3), 90*time.Minute)
+	invoice := env.draftInvoice(client, "INV-2026-001", a, b)
+
+	got, err := env.invoices.GetByID(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get invoice: %v", err)
+	}
+	if got.InvoiceNumber != "INV-2026-001" || got.Status != domain.InvoiceStatusDraft {
+		t.Fatalf("unexpected invoice: %+v", got)
+	}
+
+	byNumber, err := env.invoices.GetByNumber(env.ctx, "INV-2026-001")
+	if err != nil || byNumber.ID != invoice.ID {
+		t.Fatalf("expected lookup by number to find invoice %d, got %v", invoice.ID, err)
+	}
+
+	items, err := env.invoices.GetLineItems(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get line items: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(items))
+	}
+	if items[0].EntryID != a.ID || items[0].Amount != 20000 || items[1].Hours != 1.5 {
+		t.Fatalf("unexpected line items: %+v, %+v", items[0], items[1])
+	}
+
+	if err := env.invoices.DeleteLineItem(env.ctx, invoice.ID, items[0].ID); err != nil {
+		t.Fatalf("failed to delete line item: %v", err)
+	}
+	items, _ = env.invoices.GetLineItems(env.ctx, invoice.ID)
+	if len(items) != 1 || items[0].EntryID != b.ID {
+		t.Fatalf("expected only the second line item to remain, got %d", len(items))
+	}
+}
+
+func TestInvoiceRepo_AdjustmentLineItems(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	a := env.entry(client, "design", day(3), 20*time.Minute)
+	invoice := env.draftInvoice(client, "INV-2026-001", a)
+
+	// Adjustments have no entry and list after the entries
+	adjustment := &domain.InvoiceLineItem{Date: day(2), Description: "Rounded up", Hours: 40.0 / 60, Rate: 100, Amount: 6667}
+	if err := env.invoices.AddLineItem(env.ctx, invoice.ID, adjustment); err != nil {
+		t.Fatalf("failed to add adjustment: %v", err)
+	}
+
+	items, err := env.invoices.GetLineItems(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get line items: %v", err)
+	}
+	if len(items) != 2 || items[0].EntryID != a.ID || !items[1].IsAdjustment() {
+		t.Fatalf("expected the entry then the adjustment, got %+v, %+v", items[0], items[1])
+	}
+}
+
+func TestInvoiceRepo_RewordedLineItemKeepsEntry(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	a := env.entry(client, "fixd login w/ jwt", day(3), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", a)
+
+	items, _ := env.invoices.GetLineItems(env.ctx, invoice.ID)
+	items[0].Reword("Fixed sign-in errors")
+	if err := env.invoices.SetLineItemDescription(env.ctx, invoice.ID, items[0]); err != nil {
+		t.Fatalf("failed to reword line item: %v", err)
+	}
+
+	items, _ = env.invoices.GetLineItems(env.ctx, invoice.ID)
+	if items[0].Description != "Fixed sign-in errors" || items[0].OriginalDescription != "fixd login w/ jwt" {
+		t.Fatalf("expected the new wording with the original kept, got %+v", items[0])
+	}
+	if entry, _ := env.entries.GetByID(env.ctx, a.ID); entry.Description != "fixd login w/ jwt" {
+		t.Fatalf("expected the entry left as it was, got %q", entry.Description)
+	}
+	if err := env.invoices.SetLineItemDescription(env.ctx, invoice.ID+1, items[0]); err == nil {
+		t.Fatalf("expected an item on another invoice to be refused")
+	}
+}
+
+func TestInvoiceRepo_TaxLines(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001", env.entry(client, "design", day(3), time.Hour))
+
+	taxes := []*domain.InvoiceTax{
+		{Label: "VAT", Rate: 0.2, Amount: 2000},
+		{Label: "City surcharge", Rate: 0.01, Amount: 100},
+	}
+	if err := env.invoices.SetTaxes(env.ctx, invoice.ID, taxes); err != nil {
+		t.Fatalf("failed to set taxes: %v", err)
+	}
+	// Setting again replaces rather than appends
+	reverse := []*domain.InvoiceTax{{Label: "VAT", Rate: 0, Note: "Reverse charge"}}
+	if err := env.invoices.SetTaxes(env.ctx, invoice.ID, append(reverse, taxes[1])); err != nil {
+		t.Fatalf("failed to replace taxes: %v", err)
+	}
+
+	got, err := env.invoices.GetTaxes(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get taxes: %v", err)
+	}
+	if len(got) != 2 || got[0].Note != "Reverse charge" || got[1].Label != "City surcharge" || got[1].Amount != 100 {
+		t.Fatalf("unexpected tax lines: %+v", got)
+	}
+}
+
+func TestInvoiceRepo_UpdatePersistsTotalsAndStatus(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001")
+
+	paid := day(20)
+	invoice.Subtotal = 10000
+	invoice.TaxRate = 0.1
+	invoice.TaxAmount = 1000
+	invoice.Total = 11000
+	invoice.Status = domain.InvoiceStatusPaid
+	invoice.PaidDate = &paid
+	invoice.Footer = domain.InvoiceFooter{Notes: "Thanks!", PaymentInstructions: "IBAN DE00 1234\nBIC ABCDEF"}
+	if err := env.invoices.Update(env.ctx, invoice); err != nil {
+		t.Fatalf("failed to update invoice: %v", err)
+	}
+
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if got.Total != 11000 || got.TaxRate != 0.1 || got.Status != domain.InvoiceStatusPaid {
+		t.Fatalf("update not persisted: %+v", got)
+	}
+	if got.PaidDate == nil || !got.PaidDate.Equal(paid) {
+		t.Fatalf("expected paid date %v, got %v", paid, got.PaidDate)
+	}
+	if got.Footer != invoice.Footer {
+		t.Fatalf("expected footer %+v, got %+v", invoice.Footer, got.Footer)
+	}
+}
+
+func TestInvoiceRepo_DueDateChanges(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001")
+	other := env.draftInvoice(client, "INV-2026-002")
+
+	first := day(20)
+	changes := []*domain.DueDateChange{
+		{InvoiceID: invoice.ID, To: first, Note: "Net 14 agreed", ChangedAt: day(5)},
+		{InvoiceID: invoice.ID, From: &first, To: day(27), Note: "Extension", ChangedAt: day(18)},
+		{InvoiceID: other.ID, To: day(30), Note: "Unrelated", ChangedAt: day(6)},
+	}
+	for _, c := range changes {
+		if err := env.invoices.AddDueDateChange(env.ctx, c); err != nil {
+			t.Fatalf("failed to record due date change: %v", err)
+		}
+	}
+
+	got, err := env.invoices.GetDueDateChanges(env.ctx, invoice.ID)
+	if err != nil {
+		t.Fatalf("failed to get due date changes: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != changes[0].ID || got[1].Note != "Extension" {
+		t.Fatalf("expected the invoice's two changes oldest first, got %+v", got)
+	}
+	if got[0].From != nil || got[1].From == nil || !got[1].From.Equal(first) || !got[1].To.Equal(day(27)) {
+		t.Fatalf("unexpected dates: %+v, %+v", got[0], got[1])
+	}
+}
+
+func TestInvoiceRepo_UpdateRefusesStaleCopy(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	invoice := env.draftInvoice(client, "INV-2026-001")
+
+	stale, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+
+	invoice.Status = domain.InvoiceStatusPaid
+	if err := env.invoices.Update(env.ctx, invoice); err != nil {
+		t.Fatalf("failed to update invoice: %v", err)
+	}
+	stale.Footer.Notes = "Thanks!"
+	if err := env.invoices.Update(env.ctx, stale); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if got.Status != domain.InvoiceStatusPaid || got.Footer.Notes != "" {
+		t.Fatalf("expected only the first update, got %+v", got)
+	}
+}
+
+func TestInvoiceRepo_VoidReleasesDraft(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "Design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+	kept := env.draftInvoice(client, "INV-2026-002")
+
+	estimate := domain.NewEstimate("EST-2026-001", client.ID, day(1))
+	if err := env.estimates.Create(env.ctx, estimate); err != nil {
+		t.Fatalf("failed to create estimate: %v", err)
+	}
+	estimate.Status = domain.EstimateStatusAccepted
+	estimate.InvoiceID = &invoice.ID
+	if err := env.estimates.Update(env.ctx, estimate); err != nil {
+		t.Fatalf("failed to update estimate: %v", err)
+	}
+
+	if err := env.invoices.Void(env.ctx, invoice.ID); err != nil {
+		t.Fatalf("failed to void invoice: %v", err)
+	}
+
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if got.Status != domain.InvoiceStatusVoid || got.Total != 0 {
+		t.Fatalf("expected a void invoice with nothing owed, got %+v", got)
+	}
+	if items, _ := env.invoices.GetLineItems(env.ctx, invoice.ID); len(items) != 0 {
+		t.Fatalf("expected the line items removed, got %d", len(items))
+	}
+	if released, _ := env.estimates.GetByID(env.ctx, estimate.ID); !released.CanConvert() {
+		t.Fatalf("expected the estimate to be convertible again, got %+v", released)
+	}
+
+	// Hidden from the list unless asked for
+	list, _ := env.invoices.List(env.ctx, nil, nil)
+	if len(list) != 1 || list[0].ID != kept.ID {
+		t.Fatalf("expected only the other draft listed, got %+v", list)
+	}
+	void := domain.InvoiceStatusVoid
+	if list, _ = env.invoices.List(env.ctx, nil, &void); len(list) != 1 || list[0].ID != invoice.ID {
+		t.Fatalf("expected the void invoice listed by status, got %+v", list)
+	}
+
+	// Only drafts can be voided
+	if err := env.invoices.Void(env.ctx, invoice.ID); err == nil {
+		t.Fatal("expected voiding twice to fail")
+	}
+	kept.Finalize()
+	if err := env.invoices.Update(env.ctx, kept); err != nil {
+		t.Fatalf("failed to finalize invoice: %v", err)
+	}
+	if err := env.invoices.Void(env.ctx, kept.ID); err == nil {
+		t.Fatal("expected voiding a finalized invoice to fail")
+	}
+}
+
+func TestInvoiceRepo_ListFilters(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	beta := env.client("Beta", 100)
+
+	env.draftInvoice(acme, "INV-2026-001")
+	sent := env.draftInvoice(acme, "INV-2026-002")
+	env.draftInvoice(beta, "INV-2026-003")
+
+	sent.Status = domain.InvoiceStatusSent
+	if err := env.invoices.Update(env.ctx, sent); err != nil {
+		t.Fatalf("failed to update invoice: %v", err)
+	}
+
+	all, err := env.invoices.List(env.ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to list invoices: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 invoices, got %d", len(all))
+	}
+
+	acmeOnly, _ := env.invoices.List(env.ctx, &acme.ID, nil)
+	if len(acmeOnly) != 2 {
+		t.Fatalf("expected 2 Acme invoices, got %d", len(acmeOnly))
+	}
+
+	status := domain.InvoiceStatusSent
+	sentOnly, _ := env.invoices.List(env.ctx, nil, &status)
+	if len(sentOnly) != 1 || sentOnly[0].ID != sent.ID {
+		t.Fatalf("expected only the sent invoice, got %d", len(sentOnly))
+	}
+}
+
+func TestInvoiceRepo_GetNextInvoiceNumber(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	next, err := env.invoices.GetNextInvoiceNumber(env.ctx, "INV", 2026)
+	if err != nil {
+		t.Fatalf("failed to get next number: %v", err)
+	}
+	if next != "INV-2026-001" {
+		t.Fatalf("expected first number INV-2026-001, got %s", next)
+	}
+
+	env.draftInvoice(client, "INV-2026-001")
+	env.draftInvoice(client, "INV-2026-002")
+	env.draftInvoice(client, "INV-2025-009")
+
+	next, _ = env.invoices.GetNextInvoiceNumber(env.ctx, "INV", 2026)
+	if next != "INV-2026-003" {
+		t.Fatalf("expected INV-2026-003, got %s", next)
+	}
+
+	next, _ = env.invoices.GetNextInvoiceNumber(env.ctx, "ACME", 2026)
+	if next != "ACME-2026-001" {
+		t.Fatalf("expected prefixes to be numbered separately, got %s", next)
+	}
+}
+
+func TestInvoiceRepo_NumbersPastNineHundredNinetyNine(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	env.draftInvoice(client, "INV-2026-999")
+	env.draftInvoice(client, "INV-2026-1000")
+
+	next, _ := env.invoices.GetNextInvoiceNumber(env.ctx, "INV", 2026)
+	if next != "INV-2026-1001" {
+		t.Fatalf("expected INV-2026-1001, got %s", next)
+	}
+}
+
+func TestInvoiceRepo_CreateRejectsTakenNumber(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	env.draftInvoice(client, "INV-2026-001")
+
+	dup := domain.NewInvoice("INV-2026-001", client.ID, day(1), day(31))
+	if err := env.invoices.Create(env.ctx, dup); !errors.Is(err, ErrDuplicateNumber) {
+		t.Fatalf("expected ErrDuplicateNumber, got %v", err)
+	}
+}
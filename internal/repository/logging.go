@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+// loggedEntryRepo records every change made through a TimeEntryRepository.
+// Entries are edited directly from the CLI and TUI rather than through a
+// service, so this is where those edits get logged. Descriptions and notes
+// stay out of the log since the database they come from is encrypted.
+type loggedEntryRepo struct {
+	TimeEntryRepository
+	log *slog.Logger
+}
+
+// WithEntryLogging wraps repo so that its mutations are logged
+func WithEntryLogging(repo TimeEntryRepository, log *slog.Logger) TimeEntryRepository {
+	return &loggedEntryRepo{TimeEntryRepository: repo, log: log}
+}
+
+func (r *loggedEntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error {
+	if err := r.TimeEntryRepository.Create(ctx, entry); err != nil {
+		return err
+	}
+	r.log.Info("entry created", entryAttrs(entry)...)
+	return nil
+}
+
+func (r *loggedEntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason string) error {
+	if err := r.TimeEntryRepository.Update(ctx, entry, reason); err != nil {
+		return err
+	}
+	r.log.Info("entry updated", append(entryAttrs(entry), "reason", reason)...)
+	return nil
+}
+
+func (r *loggedEntryRepo) SoftDelete(ctx context.Context, id int64, reason string) error {
+	if err := r.TimeEntryRepository.SoftDelete(ctx, id, reason); err != nil {
+		return err
+	}
+	r.log.Info("entry deleted", "entry_id", id, "reason", reason)
+	return nil
+}
+
+func (r *loggedEntryRepo) Restore(ctx context.Context, id int64, reason string) error {
+	if err := r.TimeEntryRepository.Restore(ctx, id, reason); err != nil {
+		return err
+	}
+	r.log.Info("entry restored", "entry_id", id, "reason", reason)
+	return nil
+}
+
+func (r *loggedEntryRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	n, err := r.TimeEntryRepository.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	r.log.Info("deleted entries purged", "count", n, "before", before.Format(time.RFC3339))
+	return n, nil
+}
+
+func (r *loggedEntryRepo) Split(ctx context.Context, id int64, at time.Time, reason string) (*domain.TimeEntry, error) {
+	second, err := r.TimeEntryRepository.Split(ctx, id, at, reason)
+	if err != nil {
+		return nil, err
+	}
+	r.log.Info("entry split", "entry_id", id, "new_entry_id", second.ID, "at", at.Format(time.RFC3339), "reason", reason)
+	return second, nil
+}
+
+func (r *loggedEntryRepo) LockForInvoice(ctx context.Context, entryIDs []int64, invoiceID int64) error {
+	if err := r.TimeEntryRepository.LockForInvoice(ctx, entryIDs, invoiceID); err != nil {
+		return err
+	}
+	r.log.Debug("entries locked", "invoice_id", invoiceID, "entry_ids", entryIDs)
+	return nil
+}
+
+func (r *loggedEntryRepo) UnlockForInvoice(ctx context.Context, invoiceID int64) (int64, error) {
+	n, err := r.TimeEntryRepository.UnlockForInvoice(ctx, invoiceID)
+	if err != nil {
+		return 0, err
+	}
+	r.log.Debug("entries unlocked", "invoice_id", invoiceID, "count", n)
+	return n, nil
+}
+
+func (r *loggedEntryRepo) SetApproval(ctx context.Context, entryIDs []int64, status domain.ApprovalStatus, note string) error {
+	if err := r.TimeEntryRepository.SetApproval(ctx, entryIDs, status, note); err != nil {
+		return err
+	}
+	r.log.Info("entry approval set", "entry_ids", entryIDs, "approval", string(status))
+	return nil
+}
+
+// entryAttrs are the log attributes describing an entry's billable shape
+func entryAttrs(entry *domain.TimeEntry) []any {
+	return []any{
+		"entry_id", entry.ID,
+		"client_id", entry.ClientID,
+		"start", entry.StartTime.Format(time.RFC3339),
+		"duration", entry.Duration().Round(time.Second).String(),
+		"rate", entry.HourlyRate,
+		"billable", entry.IsBillable,
+	}
+}
@@ -11,38 +11,135 @@ import (
 type ClientRepository interface {
 	Create(ctx context.Context, client *domain.Client) error
 	GetByID(ctx context.Context, id int64) (*domain.Client, error)
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.Client, error) // Missing IDs are left out
 	GetByName(ctx context.Context, name string) (*domain.Client, error)
 	List(ctx context.Context, includeArchived bool) ([]*domain.Client, error)
 	Update(ctx context.Context, client *domain.Client) error
 	Archive(ctx context.Context, id int64) error
 	Unarchive(ctx context.Context, id int64) error
+	SetRate(ctx context.Context, clientID int64, rate float64, effectiveFrom time.Time) error // Records a rate from a day onwards
+	RateAt(ctx context.Context, clientID int64, at time.Time) (float64, error)                // Rate in effect at a time
+	ListRates(ctx context.Context, clientID int64) ([]*domain.ClientRate, error)              // Newest first
+	SetRoleRate(ctx context.Context, role *domain.RoleRate) error                             // Adds or reprices a role on the rate card
+	DeleteRoleRate(ctx context.Context, clientID int64, role string) error                    // Role matched ignoring case
+	ListRoleRates(ctx context.Context, clientID int64) ([]*domain.RoleRate, error)            // By role name
 }
 
 // TimeEntryRepository manages time entry persistence with audit trail
 type TimeEntryRepository interface {
 	Create(ctx context.Context, entry *domain.TimeEntry) error
 	GetByID(ctx context.Context, id int64) (*domain.TimeEntry, error)
-	Update(ctx context.Context, entry *domain.TimeEntry, reason string) error // Creates audit record
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.TimeEntry, error) // Missing IDs are left out
+	Update(ctx context.Context, entry *domain.TimeEntry, reason string) error       // Creates audit record
 	SoftDelete(ctx context.Context, id int64, reason string) error
+	Restore(ctx context.Context, id int64, reason string) error                                  // Reverses SoftDelete
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)                           // Permanently removes soft-deleted entries
+	Split(ctx context.Context, id int64, at time.Time, reason string) (*domain.TimeEntry, error) // Returns the new second half
 	List(ctx context.Context, clientID *int64, start, end *time.Time, includeLocked bool) ([]*domain.TimeEntry, error)
+	ListDeleted(ctx context.Context, clientID *int64) ([]*domain.TimeEntry, error)
+	ListByInvoice(ctx context.Context, invoiceID int64) ([]*domain.TimeEntry, error) // Entries locked to the invoice
+	// GetUnbilledByClient retrieves a client's unbilled entries that started
+	// between start and end, leaving out those already on a draft
 	GetUnbilledByClient(ctx context.Context, clientID int64, start, end time.Time) ([]*domain.TimeEntry, error)
+	// GetUnbilledSinceLastInvoice retrieves a client's unbilled entries that
+	// started after their last invoice's period ended, or all of them if
+	// they were never invoiced, up to end
+	GetUnbilledSinceLastInvoice(ctx context.Context, clientID int64, end time.Time) ([]*domain.TimeEntry, error)
+	// RecentDescriptions lists the distinct descriptions of a client's
+	// entries, most recently used first, for autocompleting new ones
+	RecentDescriptions(ctx context.Context, clientID int64, limit int) ([]string, error)
 	IsLocked(ctx context.Context, id int64) (bool, error)
 	LockForInvoice(ctx context.Context, entryIDs []int64, invoiceID int64) error
+	UnlockForInvoice(ctx context.Context, invoiceID int64) (int64, error) // Returns the number of entries released
+	// SetApproval records the client's answer on unbilled entries, with an audit record
+	SetApproval(ctx context.Context, entryIDs []int64, status domain.ApprovalStatus, note string) error
 	GetHistory(ctx context.Context, entryID int64) ([]*domain.EntryHistory, error)
 }
 
 // InvoiceRepository manages invoice persistence
 type InvoiceRepository interface {
+	// Create returns ErrDuplicateNumber if the invoice number is taken
 	Create(ctx context.Context, invoice *domain.Invoice) error
 	GetByID(ctx context.Context, id int64) (*domain.Invoice, error)
 	GetByNumber(ctx context.Context, number string) (*domain.Invoice, error)
 	List(ctx context.Context, clientID *int64, status *domain.InvoiceStatus) ([]*domain.Invoice, error)
 	Update(ctx context.Context, invoice *domain.Invoice) error
+	// Void deletes a draft invoice, keeping it as void and releasing what its
+	// line items billed
+	Void(ctx context.Context, id int64) error
 	AddLineItem(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error
 	// DeleteLineItem removes a specific line item from an invoice
 	DeleteLineItem(ctx context.Context, invoiceID int64, lineItemID int64) error
 	GetLineItems(ctx context.Context, invoiceID int64) ([]*domain.InvoiceLineItem, error)
+	// SetLineItemDescription saves a reworded line item's description and
+	// original description
+	SetLineItemDescription(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error
+	// SetTaxes replaces an invoice's tax lines
+	SetTaxes(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error
+	GetTaxes(ctx context.Context, invoiceID int64) ([]*domain.InvoiceTax, error)
+	// AddDueDateChange records a due date moved after the invoice was sent
+	AddDueDateChange(ctx context.Context, change *domain.DueDateChange) error
+	GetDueDateChanges(ctx context.Context, invoiceID int64) ([]*domain.DueDateChange, error)
+	// GetNextInvoiceNumber returns the number after the highest one taken
+	// for prefix and year. Nothing is reserved: Create returns
+	// ErrDuplicateNumber if another invoice took it in the meantime.
 	GetNextInvoiceNumber(ctx context.Context, prefix string, year int) (string, error)
+	// GetLastPeriodEnd returns when the latest period invoiced to the client
+	// ended, ignoring void invoices, or nil if they were never invoiced
+	GetLastPeriodEnd(ctx context.Context, clientID int64) (*time.Time, error)
+}
+
+// EstimateRepository manages estimate persistence
+type EstimateRepository interface {
+	Create(ctx context.Context, estimate *domain.Estimate) error
+	GetByID(ctx context.Context, id int64) (*domain.Estimate, error)
+	List(ctx context.Context, clientID *int64, status *domain.EstimateStatus) ([]*domain.Estimate, error)
+	Update(ctx context.Context, estimate *domain.Estimate) error
+	AddLineItem(ctx context.Context, estimateID int64, item *domain.EstimateLineItem) error
+	DeleteLineItem(ctx context.Context, estimateID int64, lineItemID int64) error
+	GetLineItems(ctx context.Context, estimateID int64) ([]*domain.EstimateLineItem, error)
+	GetNextEstimateNumber(ctx context.Context, prefix string, year int) (string, error)
+}
+
+// ContractRepository manages client contracts
+type ContractRepository interface {
+	Create(ctx context.Context, contract *domain.Contract) error
+	GetByID(ctx context.Context, id int64) (*domain.Contract, error)
+	List(ctx context.Context, clientID *int64) ([]*domain.Contract, error) // Soonest end date first
+	Update(ctx context.Context, contract *domain.Contract) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// TimeOffRepository manages days off
+type TimeOffRepository interface {
+	Create(ctx context.Context, timeOff *domain.TimeOff) error
+	List(ctx context.Context, start, end *time.Time) ([]*domain.TimeOff, error) // Overlapping the range, earliest first
+	Delete(ctx context.Context, id int64) error
+}
+
+// AttachmentRepository manages the records of attached files. The files
+// themselves are stored by the attachment service.
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *domain.Attachment) error
+	GetByID(ctx context.Context, id int64) (*domain.Attachment, error)
+	List(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) ([]*domain.Attachment, error) // Oldest first
+	Delete(ctx context.Context, id int64) error
+}
+
+// DeliveryRepository manages the log of invoices sent to clients
+type DeliveryRepository interface {
+	Create(ctx context.Context, delivery *domain.InvoiceDelivery) error
+	GetByID(ctx context.Context, id int64) (*domain.InvoiceDelivery, error)
+	GetByMessageID(ctx context.Context, messageID string) (*domain.InvoiceDelivery, error) // Most recent send
+	List(ctx context.Context, invoiceID int64) ([]*domain.InvoiceDelivery, error)          // Oldest first
+	SetOpened(ctx context.Context, delivery *domain.InvoiceDelivery) error
+}
+
+// SearchRepository finds entries and clients by the words in them
+type SearchRepository interface {
+	// Search returns matching clients, then entries newest first, up to
+	// limit results in all
+	Search(ctx context.Context, query string, limit int) ([]*domain.SearchResult, error)
 }
 
 // TimerRepository manages the active timer state (singleton)
@@ -51,3 +148,19 @@ type TimerRepository interface {
 	Save(ctx context.Context, timer *domain.ActiveTimer) error
 	Delete(ctx context.Context) error
 }
+
+// Repositories groups the repositories that take part in a unit of work
+type Repositories struct {
+	Clients    ClientRepository
+	Entries    TimeEntryRepository
+	Invoices   InvoiceRepository
+	Estimates  EstimateRepository
+	Deliveries DeliveryRepository
+	Timer      TimerRepository
+}
+
+// UnitOfWork runs a function against repositories that share a single
+// transaction. Changes commit only if the function returns nil.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+}
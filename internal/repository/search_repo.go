@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// snippetWords is how many words of context a search snippet shows
+const snippetWords = 12
+
+// SearchRepo is a SQLite implementation of SearchRepository, reading the
+// entries_fts and clients_fts indexes the schema keeps up to date
+type SearchRepo struct {
+	db conn
+}
+
+// NewSearchRepo creates a new SearchRepo
+func NewSearchRepo(database *db.DB) *SearchRepo {
+	return &SearchRepo{db: database}
+}
+
+// Search finds clients and live entries containing every word of query.
+// Each word also matches as a prefix, so "deplo" finds "deployment".
+func (r *SearchRepo) Search(ctx context.Context, query string, limit int) ([]*domain.SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	results, err := r.searchClients(ctx, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) >= limit {
+		return results, nil
+	}
+
+	entries, err := r.searchEntries(ctx, match, limit-len(results))
+	if err != nil {
+		return nil, err
+	}
+	return append(results, entries...), nil
+}
+
+func (r *SearchRepo) searchClients(ctx context.Context, match string, limit int) ([]*domain.SearchResult, error) {
+	query := fmt.Sprintf(`
+		SELECT c.id, c.name, snippet(clients_fts, char(2), char(3), '…', -1, %d)
+		FROM clients_fts
+		JOIN clients c ON c.id = clients_fts.docid
+		WHERE clients_fts MATCH ?
+		ORDER BY c.is_archived, c.name COLLATE NOCASE
+		LIMIT ?
+	`, snippetWords)
+
+	rows, err := r.db.QueryContext(ctx, query, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search clients: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.SearchResult
+	for rows.Next() {
+		result := &domain.SearchResult{Kind: domain.SearchClient}
+		if err := rows.Scan(&result.ID, &result.ClientName, &result.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan client result: %w", err)
+		}
+		result.ClientID = result.ID
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating client results: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *SearchRepo) searchEntries(ctx context.Context, match string, limit int) ([]*domain.SearchResult, error) {
+	query := fmt.Sprintf(`
+		SELECT e.id, e.client_id, c.name, e.start_time, snippet(entries_fts, char(2), char(3), '…', -1, %d)
+		FROM entries_fts
+		JOIN time_entries e ON e.id = entries_fts.docid
+		JOIN clients c ON c.id = e.client_id
+		WHERE entries_fts MATCH ? AND e.is_deleted = 0
+		ORDER BY e.start_time DESC
+		LIMIT ?
+	`, snippetWords)
+
+	rows, err := r.db.QueryContext(ctx, query, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search entries: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*domain.SearchResult
+	for rows.Next() {
+		result := &domain.SearchResult{Kind: domain.SearchEntry}
+		var startTime string
+		if err := rows.Scan(&result.ID, &result.ClientID, &result.ClientName, &startTime, &result.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan entry result: %w", err)
+		}
+		start, err := parseTime(startTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse start_time: %w", err)
+		}
+		result.Date = &start
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating entry results: %w", err)
+	}
+
+	return results, nil
+}
+
+// ftsQuery turns what the user typed into an FTS MATCH expression: every
+// word, lowercased so it can't be read as AND/OR/NOT, as a prefix search.
+// Punctuation separates words as the unicode61 tokenizer does, so quotes
+// and stray operators can't make the expression invalid.
+func ftsQuery(input string) string {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, w := range words {
+		words[i] = w + "*"
+	}
+	return strings.Join(words, " ")
+}
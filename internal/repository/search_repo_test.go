@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestSearchRepo_FindsEntriesAndClientNotes(t *testing.T) {
+	env := newTestEnv(t)
+	acme := env.client("Acme", 100)
+	globex := env.client("Globex", 120)
+	globex.Notes = "Prefers deployments on Fridays"
+	if err := env.clients.Update(env.ctx, globex); err != nil {
+		t.Fatalf("failed to update client: %v", err)
+	}
+
+	older := env.entry(acme, "Deploy billing service", day(2), time.Hour)
+	newer := env.entry(globex, "Code review", day(5), time.Hour)
+	newer.Notes = "Blocked on the staging deploy"
+	if err := env.entries.Update(env.ctx, newer, ""); err != nil {
+		t.Fatalf("failed to update entry: %v", err)
+	}
+	deleted := env.entry(acme, "Deploy hotfix", day(6), time.Hour)
+	if err := env.entries.SoftDelete(env.ctx, deleted.ID, ""); err != nil {
+		t.Fatalf("failed to delete entry: %v", err)
+	}
+	env.entry(acme, "Planning", day(7), time.Hour)
+
+	results, err := env.search.Search(env.ctx, `"deplo`, 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected a client and two live entries, got %+v", results)
+	}
+	if results[0].Kind != domain.SearchClient || results[0].ID != globex.ID {
+		t.Fatalf("expected Globex first, got %+v", results[0])
+	}
+	if results[1].Kind != domain.SearchEntry || results[1].ID != newer.ID || results[2].ID != older.ID {
+		t.Fatalf("expected entries newest first, got %+v, %+v", results[1], results[2])
+	}
+	if results[1].ClientName != "Globex" || results[1].Date == nil || !results[1].Date.Equal(day(5)) {
+		t.Fatalf("unexpected entry result: %+v", results[1])
+	}
+	if got := domain.HighlightSnippet(results[1].Snippet, "[", "]"); got != "Blocked on the staging [deploy]" {
+		t.Fatalf("unexpected snippet %q", got)
+	}
+
+	// Edits and restores are picked up by the index
+	older.Description = "Migrate billing database"
+	if err := env.entries.Update(env.ctx, older, ""); err != nil {
+		t.Fatalf("failed to update entry: %v", err)
+	}
+	if err := env.entries.Restore(env.ctx, deleted.ID, ""); err != nil {
+		t.Fatalf("failed to restore entry: %v", err)
+	}
+	results, err = env.search.Search(env.ctx, "deploy", 10)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(results) != 3 || results[1].ID != deleted.ID || results[2].ID != newer.ID {
+		t.Fatalf("expected the restored entry and not the edited one, got %+v", results)
+	}
+
+	// Every word must match, and the limit covers both kinds
+	if results, _ = env.search.Search(env.ctx, "billing migrate", 10); len(results) != 1 || results[0].ID != older.ID {
+		t.Fatalf("expected only the entry with both words, got %+v", results)
+	}
+	if results, _ = env.search.Search(env.ctx, "deploy", 2); len(results) != 2 {
+		t.Fatalf("expected the limit to apply, got %d results", len(results))
+	}
+	if results, _ = env.search.Search(env.ctx, " -- ", 10); len(results) != 0 {
+		t.Fatalf("expected nothing for a query without words, got %+v", results)
+	}
+}
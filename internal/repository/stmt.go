@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/andy/timesink/internal/db"
+)
+
+// The helpers below run a query through the database's prepared statement
+// cache, so hot paths such as listing entries and looking up clients skip
+// parsing and planning the SQL on every call. Inside a transaction they run
+// the query directly: statements prepared for a transaction end with it.
+// Only use them for queries whose text comes from a fixed set.
+
+// errRow is a row whose statement failed to prepare
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...interface{}) error {
+	return r.err
+}
+
+// queryCached runs a query that returns rows on c
+func queryCached(ctx context.Context, c conn, query string, args ...interface{}) (*sql.Rows, error) {
+	database, ok := c.(*db.DB)
+	if !ok {
+		return c.QueryContext(ctx, query, args...)
+	}
+	stmt, err := database.Stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// queryRowCached runs a query that returns at most one row on c
+func queryRowCached(ctx context.Context, c conn, query string, args ...interface{}) rowScanner {
+	database, ok := c.(*db.DB)
+	if !ok {
+		return c.QueryRowContext(ctx, query, args...)
+	}
+	stmt, err := database.Stmt(ctx, query)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// TimeOffRepo is a SQLite implementation of TimeOffRepository
+type TimeOffRepo struct {
+	db conn
+}
+
+// NewTimeOffRepo creates a new TimeOffRepo
+func NewTimeOffRepo(database *db.DB) *TimeOffRepo {
+	return &TimeOffRepo{db: database}
+}
+
+const timeOffColumns = `id, start_date, end_date, type, note, created_at`
+
+// Create inserts new time off
+func (r *TimeOffRepo) Create(ctx context.Context, timeOff *domain.TimeOff) error {
+	if err := timeOff.Validate(); err != nil {
+		return fmt.Errorf("invalid time off: %w", err)
+	}
+
+	query := `
+		INSERT INTO time_off (start_date, end_date, type, note, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		formatTimeValue(timeOff.StartDate),
+		formatTimeValue(timeOff.EndDate),
+		string(timeOff.Type),
+		timeOff.Note,
+		formatTimeValue(timeOff.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create time off: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get time off ID: %w", err)
+	}
+
+	timeOff.ID = id
+	return nil
+}
+
+// List retrieves the time off overlapping the days from start up to end,
+// earliest first. Nil bounds leave the range open.
+func (r *TimeOffRepo) List(ctx context.Context, start, end *time.Time) ([]*domain.TimeOff, error) {
+	query := `SELECT ` + timeOffColumns + ` FROM time_off WHERE 1=1`
+	args := []interface{}{}
+	if start != nil {
+		query += ` AND end_date >= ?`
+		args = append(args, formatTimeValue(domain.RateDay(*start)))
+	}
+	if end != nil {
+		query += ` AND start_date < ?`
+		args = append(args, formatTimeValue(*end))
+	}
+	query += ` ORDER BY start_date, id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time off: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]*domain.TimeOff, 0)
+	for rows.Next() {
+		timeOff, err := scanTimeOff(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time off: %w", err)
+		}
+		list = append(list, timeOff)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time off: %w", err)
+	}
+
+	return list, nil
+}
+
+// Delete removes time off
+func (r *TimeOffRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM time_off WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete time off: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("time off not found")
+	}
+
+	return nil
+}
+
+// scanTimeOff reads one time off row
+func scanTimeOff(row interface{ Scan(...any) error }) (*domain.TimeOff, error) {
+	timeOff := &domain.TimeOff{}
+	var startDate, endDate, timeOffType, createdAt string
+
+	err := row.Scan(
+		&timeOff.ID,
+		&startDate,
+		&endDate,
+		&timeOffType,
+		&timeOff.Note,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	timeOff.Type = domain.TimeOffType(timeOffType)
+	if timeOff.StartDate, err = parseTime(startDate); err != nil {
+		return nil, fmt.Errorf("failed to parse start_date: %w", err)
+	}
+	if timeOff.EndDate, err = parseTime(endDate); err != nil {
+		return nil, fmt.Errorf("failed to parse end_date: %w", err)
+	}
+	if timeOff.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+
+	return timeOff, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestTimeOffRepo_ListOverlapping(t *testing.T) {
+	env := newTestEnv(t)
+
+	vacation := domain.NewTimeOff(day(9), day(13), domain.TimeOffVacation)
+	vacation.Note = "Lisbon"
+	holiday := domain.NewTimeOff(day(17), day(17), domain.TimeOffHoliday)
+	for _, to := range []*domain.TimeOff{holiday, vacation} {
+		if err := env.timeOff.Create(env.ctx, to); err != nil {
+			t.Fatalf("failed to create time off: %v", err)
+		}
+	}
+
+	all, err := env.timeOff.List(env.ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to list time off: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != vacation.ID || all[0].Note != "Lisbon" || all[0].Type != domain.TimeOffVacation {
+		t.Fatalf("expected both, earliest first, got %+v", all)
+	}
+	if !all[0].StartDate.Equal(domain.RateDay(day(9))) || !all[0].EndDate.Equal(domain.RateDay(day(13))) {
+		t.Fatalf("expected dates to round-trip as days, got %v to %v", all[0].StartDate, all[0].EndDate)
+	}
+
+	// A range starting on the vacation's last day and ending before the
+	// holiday's only overlaps the vacation
+	start, end := domain.RateDay(day(13)), domain.RateDay(day(17))
+	got, err := env.timeOff.List(env.ctx, &start, &end)
+	if err != nil {
+		t.Fatalf("failed to list time off: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != vacation.ID {
+		t.Fatalf("expected only the vacation, got %+v", got)
+	}
+
+	if err := env.timeOff.Delete(env.ctx, vacation.ID); err != nil {
+		t.Fatalf("failed to delete time off: %v", err)
+	}
+	if err := env.timeOff.Delete(env.ctx, vacation.ID); err == nil {
+		t.Fatal("expected deleting missing time off to fail")
+	}
+}
+
+func TestTimeOffRepo_RejectsUnknownType(t *testing.T) {
+	env := newTestEnv(t)
+
+	if err := env.timeOff.Create(env.ctx, domain.NewTimeOff(day(9), day(9), "sabbatical")); err == nil {
+		t.Fatal("expected an unknown time off type to be rejected")
+	}
+}
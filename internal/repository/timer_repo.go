@@ -12,7 +12,7 @@ import (
 
 // TimerRepo is a SQLite implementation of TimerRepository
 type TimerRepo struct {
-	db *db.DB
+	db conn
 }
 
 // NewTimerRepo creates a new TimerRepo
@@ -23,21 +23,26 @@ func NewTimerRepo(database *db.DB) *TimerRepo {
 // Get retrieves the active timer, or returns nil if no timer is running
 func (r *TimerRepo) Get(ctx context.Context) (*domain.ActiveTimer, error) {
 	query := `
-		SELECT client_id, description, start_time, paused_at, total_paused_seconds
+		SELECT client_id, description, start_time, paused_at, total_paused_seconds, pauses, notes, is_billable, location, role
 		FROM active_timer
 		WHERE id = 1
 	`
 
 	timer := &domain.ActiveTimer{}
-	var startTime string
+	var startTime, pauses string
 	var pausedAt sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query).Scan(
+	err := queryRowCached(ctx, r.db, query).Scan(
 		&timer.ClientID,
 		&timer.Description,
 		&startTime,
 		&pausedAt,
 		&timer.TotalPausedSeconds,
+		&pauses,
+		&timer.Notes,
+		&timer.IsBillable,
+		&timer.Location,
+		&timer.Role,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -58,27 +63,36 @@ func (r *TimerRepo) Get(ctx context.Context) (*domain.ActiveTimer, error) {
 		timer.PausedAt = &t
 	}
 
+	if timer.Pauses, err = parsePauses(pauses); err != nil {
+		return nil, fmt.Errorf("failed to parse pauses: %w", err)
+	}
+
 	return timer, nil
 }
 
 // Save saves the active timer (insert or replace)
 func (r *TimerRepo) Save(ctx context.Context, timer *domain.ActiveTimer) error {
 	query := `
-		INSERT OR REPLACE INTO active_timer (id, client_id, description, start_time, paused_at, total_paused_seconds)
-		VALUES (1, ?, ?, ?, ?, ?)
+		INSERT OR REPLACE INTO active_timer (id, client_id, description, start_time, paused_at, total_paused_seconds, pauses, notes, is_billable, location, role)
+		VALUES (1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var pausedAt interface{}
 	if timer.PausedAt != nil {
-		pausedAt = timer.PausedAt.Format(timeLayout)
+		pausedAt = formatTimeValue(*timer.PausedAt)
 	}
 
 	_, err := r.db.ExecContext(ctx, query,
 		timer.ClientID,
 		timer.Description,
-		timer.StartTime.Format(timeLayout),
+		formatTimeValue(timer.StartTime),
 		pausedAt,
 		timer.TotalPausedSeconds,
+		formatPauses(timer.Pauses),
+		timer.Notes,
+		timer.IsBillable,
+		timer.Location,
+		timer.Role,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save active timer: %w", err)
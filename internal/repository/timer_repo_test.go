@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestTimerRepo_SaveGetDelete(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	got, err := env.timer.Get(env.ctx)
+	if err != nil {
+		t.Fatalf("failed to get timer: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected no timer on a fresh database")
+	}
+
+	timer := domain.NewActiveTimer(client.ID, "design")
+	timer.StartTime = day(2)
+	timer.AddNote("kickoff")
+	timer.Location = "on-site"
+	timer.Role = "consulting"
+	if err := env.timer.Save(env.ctx, timer); err != nil {
+		t.Fatalf("failed to save timer: %v", err)
+	}
+
+	got, err = env.timer.Get(env.ctx)
+	if err != nil {
+		t.Fatalf("failed to get timer: %v", err)
+	}
+	if got == nil || got.ClientID != client.ID || got.Description != "design" {
+		t.Fatalf("unexpected timer: %+v", got)
+	}
+	if !got.StartTime.Equal(timer.StartTime) || got.State() != domain.TimerStateRunning {
+		t.Fatalf("expected running timer started at %v, got %+v", timer.StartTime, got)
+	}
+	if got.Notes != timer.Notes || !got.IsBillable || got.Location != "on-site" || got.Role != "consulting" {
+		t.Fatalf("expected notes, billable flag, location and role to round-trip, got %+v", got)
+	}
+
+	if err := env.timer.Delete(env.ctx); err != nil {
+		t.Fatalf("failed to delete timer: %v", err)
+	}
+	got, _ = env.timer.Get(env.ctx)
+	if got != nil {
+		t.Fatalf("expected timer to be deleted")
+	}
+}
+
+func TestTimerRepo_SaveReplacesSingleton(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+
+	first := domain.NewActiveTimer(client.ID, "first")
+	if err := env.timer.Save(env.ctx, first); err != nil {
+		t.Fatalf("failed to save timer: %v", err)
+	}
+
+	paused := time.Now().Add(-time.Minute).Truncate(time.Second)
+	second := domain.NewActiveTimer(client.ID, "second")
+	second.PausedAt = &paused
+	second.TotalPausedSeconds = 30
+	second.Pauses = []domain.Pause{{Start: paused.Add(-2 * time.Minute), End: paused.Add(-90 * time.Second)}}
+	if err := env.timer.Save(env.ctx, second); err != nil {
+		t.Fatalf("failed to save timer: %v", err)
+	}
+
+	got, err := env.timer.Get(env.ctx)
+	if err != nil {
+		t.Fatalf("failed to get timer: %v", err)
+	}
+	if got.Description != "second" || got.State() != domain.TimerStatePaused {
+		t.Fatalf("expected paused second timer, got %+v", got)
+	}
+	if got.PausedAt == nil || !got.PausedAt.Equal(paused) || got.TotalPausedSeconds != 30 {
+		t.Fatalf("pause state not persisted: %+v", got)
+	}
+	if len(got.Pauses) != 1 || !got.Pauses[0].Start.Equal(second.Pauses[0].Start) || !got.Pauses[0].End.Equal(second.Pauses[0].End) {
+		t.Fatalf("pauses not persisted: %+v", got.Pauses)
+	}
+}
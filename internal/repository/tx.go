@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/andy/timesink/internal/db"
+)
+
+// conn is satisfied by both *db.DB and *sql.Tx, so a repository can run
+// against the database directly or inside a unit of work
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// txConn is a transaction started by a repository method
+type txConn interface {
+	conn
+	Commit() error
+	Rollback() error
+}
+
+// nestedTx joins a transaction owned by a unit of work. Commit and Rollback
+// are left to the owner, which rolls back if any step returns an error.
+type nestedTx struct {
+	*sql.Tx
+}
+
+func (nestedTx) Commit() error   { return nil }
+func (nestedTx) Rollback() error { return nil }
+
+// begin starts a transaction on c, or joins the one c already belongs to
+func begin(ctx context.Context, c conn) (txConn, error) {
+	if tx, ok := c.(*sql.Tx); ok {
+		return nestedTx{tx}, nil
+	}
+	database, ok := c.(*db.DB)
+	if !ok {
+		return nil, fmt.Errorf("unsupported connection type %T", c)
+	}
+	return database.BeginTx(ctx, nil)
+}
+
+// SQLUnitOfWork is a SQLite implementation of UnitOfWork
+type SQLUnitOfWork struct {
+	db  *db.DB
+	log *slog.Logger
+}
+
+// NewUnitOfWork creates a new SQLUnitOfWork. Changes to entries made inside
+// it are logged to log like those made through WithEntryLogging, as they are
+// made, so a transaction that rolls back may still have logged some.
+func NewUnitOfWork(database *db.DB, log *slog.Logger) *SQLUnitOfWork {
+	return &SQLUnitOfWork{db: database, log: log}
+}
+
+// Do runs fn inside a transaction, committing if it returns nil
+func (u *SQLUnitOfWork) Do(ctx context.Context, fn func(repos Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	repos := Repositories{
+		Clients:    &ClientRepo{db: tx},
+		Entries:    WithEntryLogging(&EntryRepo{db: tx}, u.log),
+		Invoices:   &InvoiceRepo{db: tx},
+		Estimates:  &EstimateRepo{db: tx},
+		Deliveries: &DeliveryRepo{db: tx},
+		Timer:      &TimerRepo{db: tx},
+	}
+	if err := fn(repos); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitOfWork_CommitsOnSuccess(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+
+	err := NewUnitOfWork(env.db, slog.New(slog.DiscardHandler)).Do(env.ctx, func(repos Repositories) error {
+		// LockForInvoice opens its own transaction, which must join this one
+		if err := repos.Entries.LockForInvoice(env.ctx, []int64{entry.ID}, invoice.ID); err != nil {
+			return err
+		}
+		invoice.Finalize()
+		return repos.Invoices.Update(env.ctx, invoice)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	locked, _ := env.entries.IsLocked(env.ctx, entry.ID)
+	got, _ := env.invoices.GetByID(env.ctx, invoice.ID)
+	if !locked || !got.IsFinalized() {
+		t.Fatalf("expected lock and status change to be committed")
+	}
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+
+	boom := errors.New("crash after locking")
+	err := NewUnitOfWork(env.db, slog.New(slog.DiscardHandler)).Do(env.ctx, func(repos Repositories) error {
+		if err := repos.Entries.LockForInvoice(env.ctx, []int64{entry.ID}, invoice.ID); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected fn error to be returned, got %v", err)
+	}
+
+	locked, _ := env.entries.IsLocked(env.ctx, entry.ID)
+	if locked {
+		t.Fatalf("expected entry lock to be rolled back")
+	}
+}
+
+func TestUnitOfWork_LogsEntryChanges(t *testing.T) {
+	env := newTestEnv(t)
+	client := env.client("Acme", 100)
+	entry := env.entry(client, "design", day(2), time.Hour)
+	invoice := env.draftInvoice(client, "INV-2026-001", entry)
+
+	var logged bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	err := NewUnitOfWork(env.db, log).Do(env.ctx, func(repos Repositories) error {
+		return repos.Entries.LockForInvoice(env.ctx, []int64{entry.ID}, invoice.ID)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(logged.String(), "entries locked") {
+		t.Fatalf("expected the lock to be logged, got %q", logged.String())
+	}
+}
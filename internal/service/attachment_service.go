@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+)
+
+var ErrNotAFile = errors.New("only regular files can be attached")
+
+// AttachmentService stores files in the attachments directory and keeps
+// their records
+type AttachmentService interface {
+	// Attach copies a file into the attachments directory and records it
+	// against an invoice or other owner
+	Attach(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64, srcPath string) (*domain.Attachment, error)
+
+	// List returns an owner's attachments, oldest first
+	List(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) ([]*domain.Attachment, error)
+
+	// Get retrieves an attachment by ID
+	Get(ctx context.Context, id int64) (*domain.Attachment, error)
+
+	// Path returns where an attachment's file is stored
+	Path(attachment *domain.Attachment) string
+
+	// Remove deletes an attachment's record and its stored file
+	Remove(ctx context.Context, id int64) error
+}
+
+type attachmentService struct {
+	repo        repository.AttachmentRepository
+	invoiceRepo repository.InvoiceRepository
+	dir         string
+	log         *slog.Logger
+}
+
+// NewAttachmentService creates an AttachmentService that stores files
+// under dir
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	invoiceRepo repository.InvoiceRepository,
+	dir string,
+	log *slog.Logger,
+) AttachmentService {
+	return &attachmentService{
+		repo:        repo,
+		invoiceRepo: invoiceRepo,
+		dir:         dir,
+		log:         log,
+	}
+}
+
+func (s *attachmentService) Attach(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64, srcPath string) (*domain.Attachment, error) {
+	if err := s.checkOwner(ctx, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, ErrNotAFile
+	}
+
+	name := filepath.Base(srcPath)
+	rel, dst, err := s.createUnique(ownerType, ownerID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(dst, io.TeeReader(src, hash))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(filepath.Join(s.dir, rel))
+		return nil, fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	attachment := &domain.Attachment{
+		OwnerType: ownerType,
+		OwnerID:   ownerID,
+		Name:      name,
+		Path:      rel,
+		Size:      size,
+		SHA256:    hex.EncodeToString(hash.Sum(nil)),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		os.Remove(filepath.Join(s.dir, rel))
+		return nil, err
+	}
+
+	s.log.Info("file attached",
+		"attachment_id", attachment.ID,
+		"owner_type", ownerType,
+		"owner_id", ownerID,
+		"size", size,
+	)
+	return attachment, nil
+}
+
+// checkOwner ensures the record being attached to exists
+func (s *attachmentService) checkOwner(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) error {
+	switch ownerType {
+	case domain.AttachmentOwnerInvoice:
+		if _, err := s.invoiceRepo.GetByID(ctx, ownerID); err != nil {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown attachment owner: %s", ownerType)
+	}
+}
+
+// createUnique creates the file an attachment is copied into, under a
+// directory per owner. A numeric suffix is added when the name is already
+// taken so earlier attachments are never overwritten.
+func (s *attachmentService) createUnique(ownerType domain.AttachmentOwner, ownerID int64, name string) (string, *os.File, error) {
+	ownerDir := filepath.Join(string(ownerType), strconv.FormatInt(ownerID, 10))
+	if err := os.MkdirAll(filepath.Join(s.dir, ownerDir), 0700); err != nil {
+		return "", nil, fmt.Errorf("failed to create attachments directory: %w", err)
+	}
+
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d%s", stem, i, ext)
+		}
+		rel := filepath.Join(ownerDir, candidate)
+		f, err := os.OpenFile(filepath.Join(s.dir, rel), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			return filepath.ToSlash(rel), f, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", nil, fmt.Errorf("failed to store file: %w", err)
+		}
+	}
+}
+
+func (s *attachmentService) List(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) ([]*domain.Attachment, error) {
+	return s.repo.List(ctx, ownerType, ownerID)
+}
+
+func (s *attachmentService) Get(ctx context.Context, id int64) (*domain.Attachment, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *attachmentService) Path(attachment *domain.Attachment) string {
+	return filepath.Join(s.dir, filepath.FromSlash(attachment.Path))
+}
+
+func (s *attachmentService) Remove(ctx context.Context, id int64) error {
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// The record is gone either way; a file removed by hand is not an error
+	if err := os.Remove(s.Path(attachment)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.log.Warn("attachment file not removed", "attachment_id", id, "error", err)
+	}
+
+	s.log.Info("attachment removed", "attachment_id", id, "owner_type", attachment.OwnerType, "owner_id", attachment.OwnerID)
+	return nil
+}
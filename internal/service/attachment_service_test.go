@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+type mockAttachmentRepo struct {
+	attachments map[int64]*domain.Attachment
+	nextID      int64
+}
+
+func (m *mockAttachmentRepo) Create(ctx context.Context, attachment *domain.Attachment) error {
+	if err := attachment.Validate(); err != nil {
+		return err
+	}
+	m.nextID++
+	attachment.ID = m.nextID
+	m.attachments[attachment.ID] = attachment
+	return nil
+}
+func (m *mockAttachmentRepo) GetByID(ctx context.Context, id int64) (*domain.Attachment, error) {
+	if a, ok := m.attachments[id]; ok {
+		return a, nil
+	}
+	return nil, errors.New("attachment not found")
+}
+func (m *mockAttachmentRepo) List(ctx context.Context, ownerType domain.AttachmentOwner, ownerID int64) ([]*domain.Attachment, error) {
+	var out []*domain.Attachment
+	for id := int64(1); id <= m.nextID; id++ {
+		if a, ok := m.attachments[id]; ok && a.OwnerType == ownerType && a.OwnerID == ownerID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+func (m *mockAttachmentRepo) Delete(ctx context.Context, id int64) error {
+	delete(m.attachments, id)
+	return nil
+}
+
+func TestAttach_CopiesAndKeepsNamesUnique(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockAttachmentRepo{attachments: map[int64]*domain.Attachment{}}
+	invRepo := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{1: {ID: 1}}}
+	dir := t.TempDir()
+	svc := NewAttachmentService(repo, invRepo, dir, discardLog)
+
+	src := filepath.Join(t.TempDir(), "contract.pdf")
+	if err := os.WriteFile(src, []byte("signed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := svc.Attach(ctx, domain.AttachmentOwnerInvoice, 1, src)
+	if err != nil {
+		t.Fatalf("Attach error: %v", err)
+	}
+	second, err := svc.Attach(ctx, domain.AttachmentOwnerInvoice, 1, src)
+	if err != nil {
+		t.Fatalf("Attach error: %v", err)
+	}
+
+	if first.Path != "invoice/1/contract.pdf" || second.Path != "invoice/1/contract-2.pdf" {
+		t.Fatalf("expected unique stored paths, got %q and %q", first.Path, second.Path)
+	}
+	if first.Name != "contract.pdf" || first.Size != 6 || len(first.SHA256) != 64 {
+		t.Fatalf("unexpected attachment metadata: %+v", first)
+	}
+	data, err := os.ReadFile(svc.Path(second))
+	if err != nil || string(data) != "signed" {
+		t.Fatalf("expected stored copy, got %q (%v)", data, err)
+	}
+
+	if err := svc.Remove(ctx, first.ID); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+	if _, err := os.Stat(svc.Path(first)); !os.IsNotExist(err) {
+		t.Fatalf("expected stored file to be removed, got %v", err)
+	}
+	list, _ := svc.List(ctx, domain.AttachmentOwnerInvoice, 1)
+	if len(list) != 1 || list[0].ID != second.ID {
+		t.Fatalf("expected one attachment left, got %+v", list)
+	}
+}
+
+func TestAttach_RejectsDirectories(t *testing.T) {
+	repo := &mockAttachmentRepo{attachments: map[int64]*domain.Attachment{}}
+	invRepo := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{1: {ID: 1}}}
+	svc := NewAttachmentService(repo, invRepo, t.TempDir(), discardLog)
+
+	_, err := svc.Attach(context.Background(), domain.AttachmentOwnerInvoice, 1, t.TempDir())
+	if !errors.Is(err, ErrNotAFile) {
+		t.Fatalf("expected ErrNotAFile, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+)
+
+// ErrDuplicateClient is returned when a client is created or renamed to the
+// name of another client, ignoring case and spacing
+var ErrDuplicateClient = errors.New("a client with that name already exists")
+
+// ClientService creates and renames clients, keeping their names unique
+type ClientService interface {
+	// Create validates and stores a new client. A client whose name only
+	// differs from an existing one's in case or spacing is refused with
+	// ErrDuplicateClient, naming the existing client.
+	Create(ctx context.Context, client *domain.Client) error
+
+	// Update validates and stores changes to a client, refusing a rename
+	// onto another client's name like Create
+	Update(ctx context.Context, client *domain.Client) error
+
+	// Similar returns the clients, archived ones included, whose names
+	// resemble name, for suggesting an existing client before adding one
+	Similar(ctx context.Context, name string) ([]*domain.Client, error)
+}
+
+type clientService struct {
+	clientRepo repository.ClientRepository
+	log        *slog.Logger
+}
+
+// NewClientService creates a new ClientService
+func NewClientService(clientRepo repository.ClientRepository, log *slog.Logger) ClientService {
+	return &clientService{clientRepo: clientRepo, log: log}
+}
+
+func (s *clientService) Create(ctx context.Context, client *domain.Client) error {
+	client.Name = strings.TrimSpace(client.Name)
+	if err := client.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkUnique(ctx, client); err != nil {
+		return err
+	}
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return err
+	}
+	s.log.Info("client created", "client_id", client.ID, "name", client.Name)
+	return nil
+}
+
+func (s *clientService) Update(ctx context.Context, client *domain.Client) error {
+	client.Name = strings.TrimSpace(client.Name)
+	if err := client.Validate(); err != nil {
+		return err
+	}
+	if err := s.checkUnique(ctx, client); err != nil {
+		return err
+	}
+	return s.clientRepo.Update(ctx, client)
+}
+
+func (s *clientService) Similar(ctx context.Context, name string) ([]*domain.Client, error) {
+	clients, err := s.clientRepo.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+	var similar []*domain.Client
+	for _, c := range clients {
+		if c.ResemblesName(name) {
+			similar = append(similar, c)
+		}
+	}
+	return similar, nil
+}
+
+// checkUnique refuses a name another client already has. Archived clients
+// count, since unarchiving one would bring the duplicate back.
+func (s *clientService) checkUnique(ctx context.Context, client *domain.Client) error {
+	clients, err := s.clientRepo.List(ctx, true)
+	if err != nil {
+		return fmt.Errorf("failed to list clients: %w", err)
+	}
+	for _, c := range clients {
+		if c.ID == client.ID || !c.HasName(client.Name) {
+			continue
+		}
+		existing := fmt.Sprintf("%q (ID %d)", c.Name, c.ID)
+		if c.IsArchived {
+			existing += ", archived"
+		}
+		return fmt.Errorf("%w: %s; use that client or choose a different name", ErrDuplicateClient, existing)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+func TestClientService_CreateRefusesDuplicateName(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockClientRepo{clients: []*domain.Client{
+		{ID: 1, Name: "Globex"},
+		{ID: 2, Name: "ACME", IsArchived: true},
+	}}
+	svc := NewClientService(repo, discardLog)
+
+	for _, name := range []string{"Acme ", "  acme", "A C M E"} {
+		err := svc.Create(ctx, domain.NewClient(name, 100))
+		if name == "A C M E" {
+			if err != nil {
+				t.Errorf("Create(%q) = %v, want a new client", name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrDuplicateClient) {
+			t.Fatalf("Create(%q) = %v, want ErrDuplicateClient", name, err)
+		}
+		if !strings.Contains(err.Error(), `"ACME" (ID 2), archived`) {
+			t.Errorf("error should name the existing client, got %q", err)
+		}
+	}
+}
+
+func TestClientService_UpdateAllowsKeepingItsOwnName(t *testing.T) {
+	ctx := context.Background()
+	acme := &domain.Client{ID: 2, Name: "ACME"}
+	repo := &mockClientRepo{clients: []*domain.Client{{ID: 1, Name: "Globex"}, acme}}
+	svc := NewClientService(repo, discardLog)
+
+	renamed := *acme
+	renamed.Name = " Acme"
+	if err := svc.Update(ctx, &renamed); err != nil {
+		t.Fatalf("renaming a client's own name should succeed, got %v", err)
+	}
+	if renamed.Name != "Acme" {
+		t.Errorf("name should be trimmed, got %q", renamed.Name)
+	}
+
+	renamed.Name = "globex"
+	if err := svc.Update(ctx, &renamed); !errors.Is(err, ErrDuplicateClient) {
+		t.Fatalf("renaming onto another client should fail, got %v", err)
+	}
+}
+
+func TestClientService_Similar(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockClientRepo{clients: []*domain.Client{
+		{ID: 1, Name: "Globex Corporation"},
+		{ID: 2, Name: "Acme"},
+		{ID: 3, Name: "Initech"},
+	}}
+	svc := NewClientService(repo, discardLog)
+
+	tests := []struct {
+		name string
+		want []int64
+	}{
+		{"acme", []int64{2}},
+		{"Acne", []int64{2}},
+		{"Globex", []int64{1}},
+		{"Inittech", []int64{3}},
+		{"Umbrella", nil},
+	}
+	for _, tt := range tests {
+		similar, err := svc.Similar(ctx, tt.name)
+		if err != nil {
+			t.Fatalf("Similar(%q): %v", tt.name, err)
+		}
+		var ids []int64
+		for _, c := range similar {
+			ids = append(ids, c.ID)
+		}
+		if len(ids) != len(tt.want) || (len(ids) > 0 && ids[0] != tt.want[0]) {
+			t.Errorf("Similar(%q) = %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
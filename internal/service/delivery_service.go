@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+)
+
+// DeliveryService keeps the log of invoices sent to clients and whether
+// they were opened
+type DeliveryService interface {
+	// Send records that an invoice was sent to a recipient, by default the
+	// client's e-mail address, and marks a finalized invoice as sent.
+	// Sending a sent or paid invoice again only adds to the log.
+	Send(ctx context.Context, invoiceID int64, recipient, messageID string, sentAt time.Time) (*domain.InvoiceDelivery, error)
+
+	// MarkOpened records when a delivery was opened. Only the first open
+	// is kept.
+	MarkOpened(ctx context.Context, deliveryID int64, at time.Time) (*domain.InvoiceDelivery, error)
+
+	// MarkOpenedByMessageID records an open reported for the most recent
+	// delivery sent with a message ID, e.g. by a provider webhook
+	MarkOpenedByMessageID(ctx context.Context, messageID string, at time.Time) (*domain.InvoiceDelivery, error)
+
+	// List returns an invoice's deliveries, oldest first
+	List(ctx context.Context, invoiceID int64) ([]*domain.InvoiceDelivery, error)
+}
+
+type deliveryService struct {
+	deliveryRepo repository.DeliveryRepository
+	invoiceRepo  repository.InvoiceRepository
+	clientRepo   repository.ClientRepository
+	uow          repository.UnitOfWork
+	log          *slog.Logger
+}
+
+// NewDeliveryService creates a new DeliveryService. The unit of work records
+// a delivery and the invoice's status change together.
+func NewDeliveryService(
+	deliveryRepo repository.DeliveryRepository,
+	invoiceRepo repository.InvoiceRepository,
+	clientRepo repository.ClientRepository,
+	uow repository.UnitOfWork,
+	log *slog.Logger,
+) DeliveryService {
+	return &deliveryService{
+		deliveryRepo: deliveryRepo,
+		invoiceRepo:  invoiceRepo,
+		clientRepo:   clientRepo,
+		uow:          uow,
+		log:          log,
+	}
+}
+
+// inTx runs fn with a copy of the service whose repositories share one
+// transaction. Without a unit of work fn runs against the service itself.
+func (s *deliveryService) inTx(ctx context.Context, fn func(tx *deliveryService) error) error {
+	if s.uow == nil {
+		return fn(s)
+	}
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		return fn(&deliveryService{
+			deliveryRepo: repos.Deliveries,
+			invoiceRepo:  repos.Invoices,
+			clientRepo:   repos.Clients,
+			log:          s.log,
+		})
+	})
+}
+
+func (s *deliveryService) Send(ctx context.Context, invoiceID int64, recipient, messageID string, sentAt time.Time) (*domain.InvoiceDelivery, error) {
+	var delivery *domain.InvoiceDelivery
+	err := s.inTx(ctx, func(tx *deliveryService) error {
+		invoice, err := tx.invoiceRepo.GetByID(ctx, invoiceID)
+		if err != nil {
+			return err
+		}
+		if invoice == nil {
+			return errors.New("invoice not found")
+		}
+		if invoice.Status == domain.InvoiceStatusVoid {
+			return ErrInvoiceVoid
+		}
+		if invoice.Status == domain.InvoiceStatusDraft {
+			return errors.New("cannot mark draft invoice as sent - finalize first")
+		}
+
+		if recipient == "" {
+			client, err := tx.clientRepo.GetByID(ctx, invoice.ClientID)
+			if err != nil {
+				return err
+			}
+			recipient = client.Email
+		}
+
+		delivery = &domain.InvoiceDelivery{
+			InvoiceID: invoiceID,
+			Recipient: recipient,
+			MessageID: messageID,
+			SentAt:    sentAt,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.deliveryRepo.Create(ctx, delivery); err != nil {
+			return err
+		}
+
+		if invoice.Status != domain.InvoiceStatusFinalized {
+			return nil
+		}
+		invoice.Status = domain.InvoiceStatusSent
+		invoice.UpdatedAt = time.Now()
+		if err := tx.invoiceRepo.Update(ctx, invoice); err != nil {
+			return err
+		}
+		s.log.Info("invoice status changed", "invoice_id", invoiceID,
+			"from", string(domain.InvoiceStatusFinalized), "to", string(invoice.Status))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("invoice delivered", "invoice_id", invoiceID, "delivery_id", delivery.ID, "message_id", messageID)
+	return delivery, nil
+}
+
+func (s *deliveryService) MarkOpened(ctx context.Context, deliveryID int64, at time.Time) (*domain.InvoiceDelivery, error) {
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, s.markOpened(ctx, delivery, at)
+}
+
+func (s *deliveryService) MarkOpenedByMessageID(ctx context.Context, messageID string, at time.Time) (*domain.InvoiceDelivery, error) {
+	delivery, err := s.deliveryRepo.GetByMessageID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, s.markOpened(ctx, delivery, at)
+}
+
+// markOpened stores an open unless an earlier one was already recorded
+func (s *deliveryService) markOpened(ctx context.Context, delivery *domain.InvoiceDelivery, at time.Time) error {
+	if at.Before(delivery.SentAt) {
+		return errors.New("opened time must be after the sent time")
+	}
+	if !delivery.MarkOpened(at) {
+		return nil
+	}
+	if err := s.deliveryRepo.SetOpened(ctx, delivery); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice opened", "invoice_id", delivery.InvoiceID, "delivery_id", delivery.ID)
+	return nil
+}
+
+func (s *deliveryService) List(ctx context.Context, invoiceID int64) ([]*domain.InvoiceDelivery, error) {
+	return s.deliveryRepo.List(ctx, invoiceID)
+}
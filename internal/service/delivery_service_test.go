@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+type mockDeliveryRepo struct {
+	deliveries map[int64]*domain.InvoiceDelivery
+	nextID     int64
+	opened     int
+}
+
+func (m *mockDeliveryRepo) Create(ctx context.Context, delivery *domain.InvoiceDelivery) error {
+	if err := delivery.Validate(); err != nil {
+		return err
+	}
+	m.nextID++
+	delivery.ID = m.nextID
+	m.deliveries[delivery.ID] = delivery
+	return nil
+}
+func (m *mockDeliveryRepo) GetByID(ctx context.Context, id int64) (*domain.InvoiceDelivery, error) {
+	if d, ok := m.deliveries[id]; ok {
+		return d, nil
+	}
+	return nil, errors.New("delivery not found")
+}
+func (m *mockDeliveryRepo) GetByMessageID(ctx context.Context, messageID string) (*domain.InvoiceDelivery, error) {
+	for _, d := range m.deliveries {
+		if d.MessageID == messageID {
+			return d, nil
+		}
+	}
+	return nil, errors.New("delivery not found")
+}
+func (m *mockDeliveryRepo) List(ctx context.Context, invoiceID int64) ([]*domain.InvoiceDelivery, error) {
+	return nil, nil
+}
+func (m *mockDeliveryRepo) SetOpened(ctx context.Context, delivery *domain.InvoiceDelivery) error {
+	m.opened++
+	return nil
+}
+
+func TestSend_LogsDeliveryAndMarksSent(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockDeliveryRepo{deliveries: map[int64]*domain.InvoiceDelivery{}}
+	invRepo := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		1: {ID: 1, ClientID: 1, Status: domain.InvoiceStatusFinalized},
+		2: {ID: 2, ClientID: 1, Status: domain.InvoiceStatusDraft},
+		3: {ID: 3, ClientID: 1, Status: domain.InvoiceStatusPaid},
+	}}
+	svc := NewDeliveryService(repo, invRepo, &mockClientRepo{}, nil, discardLog)
+	sentAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	delivery, err := svc.Send(ctx, 1, "ap@acme.test", "msg-1", sentAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if delivery.ID == 0 || delivery.Recipient != "ap@acme.test" || !delivery.SentAt.Equal(sentAt) {
+		t.Fatalf("unexpected delivery: %+v", delivery)
+	}
+	if invRepo.invoices[1].Status != domain.InvoiceStatusSent {
+		t.Fatalf("expected finalized invoice to be marked sent, got %s", invRepo.invoices[1].Status)
+	}
+
+	if _, err := svc.Send(ctx, 2, "ap@acme.test", "", sentAt); err == nil {
+		t.Fatalf("expected sending a draft to fail")
+	}
+
+	invRepo.updated = nil
+	if _, err := svc.Send(ctx, 3, "ap@acme.test", "", sentAt); err != nil {
+		t.Fatalf("unexpected error resending a paid invoice: %v", err)
+	}
+	if invRepo.updated != nil || invRepo.invoices[3].Status != domain.InvoiceStatusPaid {
+		t.Fatalf("expected a paid invoice to stay paid")
+	}
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", len(repo.deliveries))
+	}
+}
+
+func TestMarkOpened_KeepsFirstOpen(t *testing.T) {
+	ctx := context.Background()
+	sentAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := &mockDeliveryRepo{deliveries: map[int64]*domain.InvoiceDelivery{
+		1: {ID: 1, InvoiceID: 1, MessageID: "msg-1", SentAt: sentAt},
+	}}
+	svc := NewDeliveryService(repo, &mockInvoiceRepo{}, &mockClientRepo{}, nil, discardLog)
+
+	if _, err := svc.MarkOpened(ctx, 1, sentAt.Add(-time.Hour)); err == nil {
+		t.Fatalf("expected an open before sending to fail")
+	}
+
+	first := sentAt.Add(2 * time.Hour)
+	if _, err := svc.MarkOpenedByMessageID(ctx, "msg-1", first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delivery, err := svc.MarkOpened(ctx, 1, sentAt.Add(5*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !delivery.OpenedAt.Equal(first) || repo.opened != 1 {
+		t.Fatalf("expected the first open to be kept, got %v after %d writes", delivery.OpenedAt, repo.opened)
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+)
+
+var (
+	ErrEstimateNotEditable    = errors.New("estimate line items can only be changed while it is a draft")
+	ErrEstimateAnswered       = errors.New("estimate has already been accepted or declined")
+	ErrEstimateNotConvertible = errors.New("only accepted estimates that have not been converted can become invoices")
+)
+
+// EstimateService manages estimates and their conversion into invoices
+type EstimateService interface {
+	// CreateDraft creates a new draft estimate with an auto-generated number,
+	// valid for validDays from the issue date
+	CreateDraft(ctx context.Context, clientID int64, issueDate time.Time, validDays int, prefix, notes string) (*domain.Estimate, error)
+
+	// AddLineItem adds a line item to a draft estimate. Projected hours
+	// without a rate are priced at the client's rate on the issue date.
+	AddLineItem(ctx context.Context, estimateID int64, item *domain.EstimateLineItem) error
+
+	// RemoveLineItem removes a line item from a draft estimate
+	RemoveLineItem(ctx context.Context, estimateID int64, lineItemID int64) error
+
+	// MarkSent records that a draft estimate was sent to the client
+	MarkSent(ctx context.Context, estimateID int64) error
+
+	// Accept records the client's acceptance of an estimate
+	Accept(ctx context.Context, estimateID int64) error
+
+	// Decline records that the client declined an estimate
+	Decline(ctx context.Context, estimateID int64) error
+
+	// ConvertToInvoice copies an accepted estimate's line items into a new
+	// draft invoice as a single transaction
+	ConvertToInvoice(ctx context.Context, estimateID int64, prefix string, taxes []*domain.InvoiceTax, footer domain.InvoiceFooter) (*domain.Invoice, error)
+
+	// GetEstimate retrieves an estimate with its line items
+	GetEstimate(ctx context.Context, id int64) (*domain.Estimate, error)
+
+	// ListEstimates lists estimates with optional filters
+	ListEstimates(ctx context.Context, clientID *int64, status *domain.EstimateStatus) ([]*domain.Estimate, error)
+}
+
+type estimateService struct {
+	estimateRepo repository.EstimateRepository
+	invoiceRepo  repository.InvoiceRepository
+	clientRepo   repository.ClientRepository
+	uow          repository.UnitOfWork
+	log          *slog.Logger
+}
+
+// NewEstimateService creates a new EstimateService. The unit of work makes
+// conversion atomic; without one each repository call commits on its own.
+func NewEstimateService(
+	estimateRepo repository.EstimateRepository,
+	invoiceRepo repository.InvoiceRepository,
+	clientRepo repository.ClientRepository,
+	uow repository.UnitOfWork,
+	log *slog.Logger,
+) EstimateService {
+	return &estimateService{
+		estimateRepo: estimateRepo,
+		invoiceRepo:  invoiceRepo,
+		clientRepo:   clientRepo,
+		uow:          uow,
+		log:          log,
+	}
+}
+
+// inTx runs fn with a copy of the service whose repositories share one
+// transaction. Without a unit of work fn runs against the service itself.
+func (s *estimateService) inTx(ctx context.Context, fn func(tx *estimateService) error) error {
+	if s.uow == nil {
+		return fn(s)
+	}
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		return fn(&estimateService{
+			estimateRepo: repos.Estimates,
+			invoiceRepo:  repos.Invoices,
+			clientRepo:   repos.Clients,
+			log:          s.log,
+		})
+	})
+}
+
+func (s *estimateService) CreateDraft(
+	ctx context.Context,
+	clientID int64,
+	issueDate time.Time,
+	validDays int,
+	prefix, notes string,
+) (*domain.Estimate, error) {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+
+	number, err := s.estimateRepo.GetNextEstimateNumber(ctx, prefix, issueDate.Year())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate estimate number: %w", err)
+	}
+
+	estimate := domain.NewEstimate(number, clientID, issueDate)
+	estimate.Notes = notes
+	if validDays > 0 {
+		validUntil := issueDate.AddDate(0, 0, validDays)
+		estimate.ValidUntil = &validUntil
+	}
+
+	if err := s.estimateRepo.Create(ctx, estimate); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("estimate draft created", "estimate_id", estimate.ID, "number", number, "client_id", clientID)
+	return estimate, nil
+}
+
+func (s *estimateService) AddLineItem(ctx context.Context, estimateID int64, item *domain.EstimateLineItem) error {
+	return s.inTx(ctx, func(tx *estimateService) error {
+		estimate, err := tx.editableEstimate(ctx, estimateID)
+		if err != nil {
+			return err
+		}
+
+		if !item.IsFixed() && item.Rate == 0 {
+			rate, err := tx.clientRepo.RateAt(ctx, estimate.ClientID, estimate.IssueDate)
+			if err != nil {
+				return fmt.Errorf("failed to look up client rate: %w", err)
+			}
+			item.Rate = rate
+		}
+		if !item.IsFixed() {
+			item.Amount = domain.AmountFor(item.Hours, item.Rate)
+		}
+
+		if err := tx.estimateRepo.AddLineItem(ctx, estimateID, item); err != nil {
+			return err
+		}
+		return tx.updateTotal(ctx, estimate)
+	})
+}
+
+func (s *estimateService) RemoveLineItem(ctx context.Context, estimateID int64, lineItemID int64) error {
+	return s.inTx(ctx, func(tx *estimateService) error {
+		estimate, err := tx.editableEstimate(ctx, estimateID)
+		if err != nil {
+			return err
+		}
+		if err := tx.estimateRepo.DeleteLineItem(ctx, estimateID, lineItemID); err != nil {
+			return err
+		}
+		return tx.updateTotal(ctx, estimate)
+	})
+}
+
+// editableEstimate loads a draft estimate
+func (s *estimateService) editableEstimate(ctx context.Context, estimateID int64) (*domain.Estimate, error) {
+	estimate, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return nil, err
+	}
+	if !estimate.CanEdit() {
+		return nil, ErrEstimateNotEditable
+	}
+	return estimate, nil
+}
+
+// updateTotal recalculates and saves an estimate's total from its line items
+func (s *estimateService) updateTotal(ctx context.Context, estimate *domain.Estimate) error {
+	items, err := s.estimateRepo.GetLineItems(ctx, estimate.ID)
+	if err != nil {
+		return err
+	}
+	estimate.LineItems = items
+	estimate.CalculateTotal()
+
+	if err := s.estimateRepo.Update(ctx, estimate); err != nil {
+		return err
+	}
+
+	s.log.Info("estimate total calculated", "estimate_id", estimate.ID, "line_items", len(items), "total", estimate.Total)
+	return nil
+}
+
+func (s *estimateService) MarkSent(ctx context.Context, estimateID int64) error {
+	estimate, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return err
+	}
+	if estimate.Status != domain.EstimateStatusDraft {
+		return errors.New("only draft estimates can be marked as sent")
+	}
+	return s.setStatus(ctx, estimate, domain.EstimateStatusSent)
+}
+
+func (s *estimateService) Accept(ctx context.Context, estimateID int64) error {
+	return s.respond(ctx, estimateID, domain.EstimateStatusAccepted)
+}
+
+func (s *estimateService) Decline(ctx context.Context, estimateID int64) error {
+	return s.respond(ctx, estimateID, domain.EstimateStatusDeclined)
+}
+
+// respond records the client's answer to a draft or sent estimate
+func (s *estimateService) respond(ctx context.Context, estimateID int64, status domain.EstimateStatus) error {
+	estimate, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return err
+	}
+	if !estimate.CanRespond() {
+		return ErrEstimateAnswered
+	}
+	return s.setStatus(ctx, estimate, status)
+}
+
+func (s *estimateService) setStatus(ctx context.Context, estimate *domain.Estimate, status domain.EstimateStatus) error {
+	from := estimate.Status
+	estimate.Status = status
+	estimate.UpdatedAt = time.Now()
+
+	if err := s.estimateRepo.Update(ctx, estimate); err != nil {
+		return err
+	}
+
+	s.log.Info("estimate status changed", "estimate_id", estimate.ID, "from", string(from), "to", string(status))
+	return nil
+}
+
+func (s *estimateService) ConvertToInvoice(
+	ctx context.Context,
+	estimateID int64,
+	prefix string,
+	taxes []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+) (*domain.Invoice, error) {
+	var invoice *domain.Invoice
+	err := s.inTx(ctx, func(tx *estimateService) error {
+		var err error
+		invoice, err = tx.convertToInvoice(ctx, estimateID, prefix, taxes, footer)
+		return err
+	})
+	if err != nil {
+		s.log.Warn("estimate conversion failed", "estimate_id", estimateID, "error", err)
+		return nil, err
+	}
+
+	s.log.Info("estimate converted",
+		"estimate_id", estimateID,
+		"invoice_id", invoice.ID,
+		"number", invoice.InvoiceNumber,
+		"total", invoice.Total,
+	)
+	return invoice, nil
+}
+
+func (s *estimateService) convertToInvoice(
+	ctx context.Context,
+	estimateID int64,
+	prefix string,
+	taxes []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+) (*domain.Invoice, error) {
+	estimate, err := s.estimateRepo.GetByID(ctx, estimateID)
+	if err != nil {
+		return nil, err
+	}
+	if !estimate.CanConvert() {
+		return nil, ErrEstimateNotConvertible
+	}
+
+	items, err := s.estimateRepo.GetLineItems(ctx, estimateID)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("cannot convert an estimate with no line items")
+	}
+
+	// The invoice covers the time from the quote until today
+	now := time.Now()
+	periodEnd := now
+	if periodEnd.Before(estimate.IssueDate) {
+		periodEnd = estimate.IssueDate
+	}
+
+	invoice := domain.NewInvoice("", estimate.ClientID, estimate.IssueDate, periodEnd)
+	invoice.Footer = footer
+	if err := createNumbered(ctx, s.invoiceRepo, invoice, prefix); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		lineItem := &domain.InvoiceLineItem{
+			EstimateID:  estimate.ID,
+			Date:        now,
+			Description: item.Description,
+			Hours:       item.Hours,
+			Rate:        item.Rate,
+			Amount:      item.Amount,
+		}
+		if err := s.invoiceRepo.AddLineItem(ctx, invoice.ID, lineItem); err != nil {
+			return nil, err
+		}
+		invoice.LineItems = append(invoice.LineItems, lineItem)
+	}
+
+	invoice.Taxes = taxes
+	invoice.CalculateTotals()
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return nil, err
+	}
+	if err := s.invoiceRepo.SetTaxes(ctx, invoice.ID, taxes); err != nil {
+		return nil, err
+	}
+
+	estimate.InvoiceID = &invoice.ID
+	estimate.UpdatedAt = now
+	if err := s.estimateRepo.Update(ctx, estimate); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *estimateService) GetEstimate(ctx context.Context, id int64) (*domain.Estimate, error) {
+	estimate, err := s.estimateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if estimate.LineItems, err = s.estimateRepo.GetLineItems(ctx, id); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
+
+func (s *estimateService) ListEstimates(ctx context.Context, clientID *int64, status *domain.EstimateStatus) ([]*domain.Estimate, error) {
+	return s.estimateRepo.List(ctx, clientID, status)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+type mockEstimateRepo struct {
+	estimates map[int64]*domain.Estimate
+	lineItems map[int64][]*domain.EstimateLineItem
+	nextID    int64
+}
+
+func (m *mockEstimateRepo) Create(ctx context.Context, estimate *domain.Estimate) error {
+	m.nextID++
+	estimate.ID = m.nextID
+	m.estimates[estimate.ID] = estimate
+	return nil
+}
+func (m *mockEstimateRepo) GetByID(ctx context.Context, id int64) (*domain.Estimate, error) {
+	if e, ok := m.estimates[id]; ok {
+		copied := *e
+		return &copied, nil
+	}
+	return nil, errors.New("estimate not found")
+}
+func (m *mockEstimateRepo) List(ctx context.Context, clientID *int64, status *domain.EstimateStatus) ([]*domain.Estimate, error) {
+	return nil, nil
+}
+func (m *mockEstimateRepo) Update(ctx context.Context, estimate *domain.Estimate) error {
+	copied := *estimate
+	m.estimates[estimate.ID] = &copied
+	return nil
+}
+func (m *mockEstimateRepo) AddLineItem(ctx context.Context, estimateID int64, item *domain.EstimateLineItem) error {
+	m.nextID++
+	item.ID = m.nextID
+	item.EstimateID = estimateID
+	m.lineItems[estimateID] = append(m.lineItems[estimateID], item)
+	return nil
+}
+func (m *mockEstimateRepo) DeleteLineItem(ctx context.Context, estimateID int64, lineItemID int64) error {
+	return nil
+}
+func (m *mockEstimateRepo) GetLineItems(ctx context.Context, estimateID int64) ([]*domain.EstimateLineItem, error) {
+	return m.lineItems[estimateID], nil
+}
+func (m *mockEstimateRepo) GetNextEstimateNumber(ctx context.Context, prefix string, year int) (string, error) {
+	return "EST-2026-001", nil
+}
+
+func newMockEstimateRepo(estimates ...*domain.Estimate) *mockEstimateRepo {
+	m := &mockEstimateRepo{
+		estimates: make(map[int64]*domain.Estimate),
+		lineItems: make(map[int64][]*domain.EstimateLineItem),
+		nextID:    100,
+	}
+	for _, e := range estimates {
+		m.estimates[e.ID] = e
+	}
+	return m
+}
+
+func TestEstimateAddLineItem_PricesProjectedHours(t *testing.T) {
+	ctx := context.Background()
+
+	est := domain.NewEstimate("EST-2026-001", 1, time.Now())
+	est.ID = 1
+	repo := newMockEstimateRepo(est)
+	svc := NewEstimateService(repo, &mockInvoiceRepo{}, &mockClientRepo{rate: 120}, nil, discardLog)
+
+	if err := svc.AddLineItem(ctx, 1, domain.NewProjectedLineItem("Design", 10, 0)); err != nil {
+		t.Fatalf("AddLineItem error: %v", err)
+	}
+	if err := svc.AddLineItem(ctx, 1, domain.NewFixedLineItem("Hosting setup", 30000)); err != nil {
+		t.Fatalf("AddLineItem error: %v", err)
+	}
+
+	items := repo.lineItems[1]
+	if items[0].Rate != 120 || items[0].Amount != 120000 {
+		t.Fatalf("expected projected hours at the client's rate, got %+v", items[0])
+	}
+	if got := repo.estimates[1].Total; got != 150000 {
+		t.Fatalf("expected total 1500.00, got %.2f", got.Float())
+	}
+
+	if err := svc.MarkSent(ctx, 1); err != nil {
+		t.Fatalf("MarkSent error: %v", err)
+	}
+	err := svc.AddLineItem(ctx, 1, domain.NewFixedLineItem("Extra", 5000))
+	if !errors.Is(err, ErrEstimateNotEditable) {
+		t.Fatalf("expected ErrEstimateNotEditable once sent, got %v", err)
+	}
+}
+
+func TestConvertToInvoice_CopiesAcceptedLineItems(t *testing.T) {
+	ctx := context.Background()
+
+	est := domain.NewEstimate("EST-2026-001", 1, time.Now().AddDate(0, 0, -7))
+	est.ID = 1
+	est.Status = domain.EstimateStatusAccepted
+	declined := domain.NewEstimate("EST-2026-002", 1, time.Now())
+	declined.ID = 2
+	declined.Status = domain.EstimateStatusDeclined
+
+	repo := newMockEstimateRepo(est, declined)
+	repo.lineItems[1] = []*domain.EstimateLineItem{
+		domain.NewProjectedLineItem("Design", 10, 100),
+		domain.NewFixedLineItem("Hosting setup", 50000),
+	}
+	invRepo := &mockInvoiceRepo{lineItems: make(map[int64][]*domain.InvoiceLineItem)}
+	svc := NewEstimateService(repo, invRepo, &mockClientRepo{}, nil, discardLog)
+
+	taxes := []*domain.InvoiceTax{{Label: "VAT", Rate: 0.2}}
+	footer := domain.InvoiceFooter{Notes: "Thanks"}
+	inv, err := svc.ConvertToInvoice(ctx, 1, "INV", taxes, footer)
+	if err != nil {
+		t.Fatalf("ConvertToInvoice error: %v", err)
+	}
+
+	if inv.Status != domain.InvoiceStatusDraft || inv.Footer != footer {
+		t.Fatalf("expected a draft invoice with the footer, got %+v", inv)
+	}
+	items := invRepo.lineItems[inv.ID]
+	if len(items) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(items))
+	}
+	for _, item := range items {
+		if !item.IsEstimated() || item.IsAdjustment() {
+			t.Fatalf("expected items copied from the estimate, got %+v", item)
+		}
+	}
+	if inv.Subtotal != 150000 || inv.Total != 180000 {
+		t.Fatalf("expected subtotal 1500.00 and total 1800.00, got %.2f and %.2f", inv.Subtotal.Float(), inv.Total.Float())
+	}
+	if len(invRepo.taxes[inv.ID]) != 1 {
+		t.Fatalf("expected the tax lines to be saved")
+	}
+	if repo.estimates[1].InvoiceID == nil {
+		t.Fatalf("expected the estimate to link to its invoice")
+	}
+
+	if _, err := svc.ConvertToInvoice(ctx, 1, "INV", taxes, footer); !errors.Is(err, ErrEstimateNotConvertible) {
+		t.Fatalf("expected a second conversion to fail, got %v", err)
+	}
+	if _, err := svc.ConvertToInvoice(ctx, 2, "INV", taxes, footer); !errors.Is(err, ErrEstimateNotConvertible) {
+		t.Fatalf("expected a declined estimate not to convert, got %v", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/domain"
@@ -11,27 +13,82 @@ import (
 )
 
 var (
-	ErrInvoiceNotEditable = errors.New("invoice cannot be edited after finalization")
-	ErrEntryAlreadyLocked = errors.New("entry is already locked to an invoice")
-	ErrEntryNotFound      = errors.New("time entry not found")
+	ErrInvoiceNotEditable    = errors.New("invoice cannot be edited after finalization")
+	ErrEntryAlreadyLocked    = errors.New("entry is already locked to an invoice")
+	ErrEntryNotFound         = errors.New("time entry not found")
+	ErrInvoiceNotReopenable  = errors.New("only finalized invoices that have not been sent can be reopened")
+	ErrEntryNotApproved      = errors.New("entry has not been approved by the client")
+	ErrNoInterestOwed        = errors.New("invoice was not paid late, so no interest is owed")
+	ErrInvoiceNotDeletable   = errors.New("only draft invoices can be deleted; finalized invoices are kept as issued")
+	ErrInvoiceVoid           = errors.New("invoice has been deleted")
+	ErrDueDateLocked         = errors.New("the invoice has been paid, so its due date can no longer change")
+	ErrDueDateNoteRequired   = errors.New("the client has been sent this invoice; give a note explaining the new due date")
+	ErrAdjustmentNotEditable = errors.New("adjustments are recalculated from the client's billing rules, so they cannot be reworded")
 )
 
 // InvoiceService manages invoice lifecycle and entry locking
 type InvoiceService interface {
 	// CreateDraft creates a new draft invoice with auto-generated number
-	CreateDraft(ctx context.Context, clientID int64, periodStart, periodEnd time.Time, prefix string) (*domain.Invoice, error)
-
-	// AddEntriesToInvoice adds time entries to a draft invoice
+	CreateDraft(ctx context.Context, clientID int64, periodStart, periodEnd time.Time, prefix string, footer domain.InvoiceFooter) (*domain.Invoice, error)
+
+	// Preview builds the invoice that would be generated from a client's
+	// unbilled entries in the period, without writing anything. Clients that
+	// require approval only get their approved entries.
+	Preview(ctx context.Context, clientID int64, periodStart, periodEnd time.Time, prefix string, taxes []*domain.InvoiceTax, footer domain.InvoiceFooter) (*domain.Invoice, error)
+
+	// Generate creates, fills, totals and finalizes an invoice for the given
+	// entries as a single transaction. It is due defaultDueDays after its
+	// invoice date.
+	Generate(ctx context.Context, clientID int64, periodStart, periodEnd time.Time, prefix string, taxes []*domain.InvoiceTax, footer domain.InvoiceFooter, entryIDs []int64, defaultDueDays int) (*domain.Invoice, error)
+
+	// DraftAll creates a draft invoice, with totals, for each active client
+	// with unbilled entries in the period they can be invoiced for. Each
+	// client's draft is its own transaction, so one failing leaves the rest.
+	// taxes gives the tax lines for a client's draft.
+	DraftAll(ctx context.Context, periodStart, periodEnd time.Time, prefix string, taxes func(*domain.Client) []*domain.InvoiceTax, footer domain.InvoiceFooter) ([]*GroupDraft, error)
+
+	// AddEntriesToInvoice adds time entries to a draft invoice. Clients that
+	// require approval only take approved entries.
 	AddEntriesToInvoice(ctx context.Context, invoiceID int64, entryIDs []int64) error
 
+	// AddInterest adds a late-payment interest charge to a draft invoice for
+	// the same client and recalculates its totals with its current tax lines
+	AddInterest(ctx context.Context, draftID int64, charge *domain.InterestCharge) error
+
+	// ChargeInterest creates an untaxed draft follow-up invoice for a
+	// late-payment interest charge as a single transaction
+	ChargeInterest(ctx context.Context, charge *domain.InterestCharge, prefix string, footer domain.InvoiceFooter) (*domain.Invoice, error)
+
+	// SetFooter replaces the notes and payment instructions of a draft invoice
+	SetFooter(ctx context.Context, invoiceID int64, footer domain.InvoiceFooter) error
+
+	// RewordLineItem replaces the description of a line item on a draft
+	// invoice, keeping the original and leaving its entry as it was. An
+	// empty description restores the original.
+	RewordLineItem(ctx context.Context, invoiceID, lineItemID int64, description string) (*domain.InvoiceLineItem, error)
+
 	// RemoveEntryFromInvoice removes an entry from a draft invoice
 	RemoveEntryFromInvoice(ctx context.Context, invoiceID int64, entryID int64) error
 
-	// CalculateTotals recalculates invoice totals with tax
-	CalculateTotals(ctx context.Context, invoiceID int64, taxRate float64) error
+	// CalculateTotals recalculates invoice totals with the given tax lines.
+	// Nil taxes keep the invoice's current tax lines.
+	CalculateTotals(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error
+
+	// Finalize locks the invoice and all associated entries. An invoice
+	// without a due date is given one defaultDueDays after its invoice date.
+	Finalize(ctx context.Context, invoiceID int64, defaultDueDays int) error
 
-	// Finalize locks the invoice and all associated entries
-	Finalize(ctx context.Context, invoiceID int64) error
+	// SetDueDate moves the due date of an unpaid invoice. Once the invoice
+	// has been sent the change needs a note and is recorded with it, and an
+	// overdue invoice whose new date is still ahead is sent again.
+	SetDueDate(ctx context.Context, invoiceID int64, due time.Time, note string) error
+
+	// Reopen moves a finalized invoice back to draft and releases its entry locks
+	Reopen(ctx context.Context, invoiceID int64) error
+
+	// Delete voids a draft invoice, hiding it from lists and releasing its
+	// line items so their entries and estimate can be invoiced again
+	Delete(ctx context.Context, invoiceID int64) error
 
 	// MarkSent updates invoice status to sent
 	MarkSent(ctx context.Context, invoiceID int64) error
@@ -49,10 +106,21 @@ type InvoiceService interface {
 	ListInvoices(ctx context.Context, clientID *int64, status *domain.InvoiceStatus) ([]*domain.Invoice, error)
 }
 
+// GroupDraft is what DraftAll did for one client
+type GroupDraft struct {
+	Client  *domain.Client
+	Invoice *domain.Invoice // nil if nothing could be invoiced or drafting failed
+	Entries int             // entries on the draft
+	Held    int             // entries in the period awaiting the client's approval
+	Err     error
+}
+
 type invoiceService struct {
 	invoiceRepo repository.InvoiceRepository
 	entryRepo   repository.TimeEntryRepository
 	clientRepo  repository.ClientRepository
+	uow         repository.UnitOfWork
+	log         *slog.Logger
 }
 
 // NewInvoiceService creates a new invoice service
@@ -60,12 +128,226 @@ func NewInvoiceService(
 	invoiceRepo repository.InvoiceRepository,
 	entryRepo repository.TimeEntryRepository,
 	clientRepo repository.ClientRepository,
+	uow repository.UnitOfWork,
+	log *slog.Logger,
 ) InvoiceService {
 	return &invoiceService{
 		invoiceRepo: invoiceRepo,
 		entryRepo:   entryRepo,
 		clientRepo:  clientRepo,
+		uow:         uow,
+		log:         log,
+	}
+}
+
+// inTx runs fn with a copy of the service whose repositories share one
+// transaction. Without a unit of work fn runs against the service itself.
+func (s *invoiceService) inTx(ctx context.Context, fn func(tx *invoiceService) error) error {
+	if s.uow == nil {
+		return fn(s)
+	}
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		return fn(&invoiceService{
+			invoiceRepo: repos.Invoices,
+			entryRepo:   repos.Entries,
+			clientRepo:  repos.Clients,
+			log:         s.log,
+		})
+	})
+}
+
+func (s *invoiceService) Generate(
+	ctx context.Context,
+	clientID int64,
+	periodStart, periodEnd time.Time,
+	prefix string,
+	taxes []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+	entryIDs []int64,
+	defaultDueDays int,
+) (*domain.Invoice, error) {
+	var invoice *domain.Invoice
+	err := s.inTx(ctx, func(tx *invoiceService) error {
+		draft, err := tx.CreateDraft(ctx, clientID, periodStart, periodEnd, prefix, footer)
+		if err != nil {
+			return fmt.Errorf("create draft: %w", err)
+		}
+		if err := tx.addEntriesToInvoice(ctx, draft.ID, entryIDs); err != nil {
+			return fmt.Errorf("add entries: %w", err)
+		}
+		if err := tx.calculateTotals(ctx, draft.ID, taxes); err != nil {
+			return fmt.Errorf("calculate totals: %w", err)
+		}
+		if err := tx.finalize(ctx, draft.ID, defaultDueDays); err != nil {
+			return fmt.Errorf("finalize: %w", err)
+		}
+
+		if invoice, err = tx.invoiceRepo.GetByID(ctx, draft.ID); err != nil {
+			return err
+		}
+		invoice.Taxes, err = tx.invoiceRepo.GetTaxes(ctx, draft.ID)
+		return err
+	})
+	if err != nil {
+		// Everything above was rolled back
+		s.log.Warn("invoice generation failed", "client_id", clientID, "error", err)
+		return nil, err
 	}
+
+	s.log.Info("invoice generated",
+		"invoice_id", invoice.ID,
+		"number", invoice.InvoiceNumber,
+		"entries", len(entryIDs),
+		"total", invoice.Total,
+	)
+	return invoice, nil
+}
+
+func (s *invoiceService) DraftAll(
+	ctx context.Context,
+	periodStart, periodEnd time.Time,
+	prefix string,
+	taxes func(*domain.Client) []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+) ([]*GroupDraft, error) {
+	clients, err := s.clientRepo.List(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*GroupDraft
+	for _, client := range clients {
+		entries, err := s.entryRepo.GetUnbilledByClient(ctx, client.ID, periodStart, periodEnd)
+		if err != nil {
+			return nil, err
+		}
+		result := &GroupDraft{Client: client}
+		var entryIDs []int64
+		for _, entry := range entries {
+			if client.CanInvoice(entry) {
+				entryIDs = append(entryIDs, entry.ID)
+			} else {
+				result.Held++
+			}
+		}
+		if len(entryIDs) == 0 && result.Held == 0 {
+			continue
+		}
+		results = append(results, result)
+		if len(entryIDs) == 0 {
+			continue
+		}
+
+		result.Err = s.inTx(ctx, func(tx *invoiceService) error {
+			draft, err := tx.CreateDraft(ctx, client.ID, periodStart, periodEnd, prefix, footer)
+			if err != nil {
+				return fmt.Errorf("create draft: %w", err)
+			}
+			if err := tx.addEntriesToInvoice(ctx, draft.ID, entryIDs); err != nil {
+				return fmt.Errorf("add entries: %w", err)
+			}
+			if err := tx.calculateTotals(ctx, draft.ID, taxes(client)); err != nil {
+				return fmt.Errorf("calculate totals: %w", err)
+			}
+			result.Invoice, err = tx.invoiceRepo.GetByID(ctx, draft.ID)
+			return err
+		})
+		if result.Err != nil {
+			// This client's draft was rolled back
+			result.Invoice = nil
+			s.log.Warn("group invoice draft failed", "client_id", client.ID, "error", result.Err)
+			continue
+		}
+		result.Entries = len(entryIDs)
+	}
+
+	s.log.Info("group invoice drafts created", "clients", len(results))
+	return results, nil
+}
+
+func (s *invoiceService) AddInterest(ctx context.Context, draftID int64, charge *domain.InterestCharge) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		if err := tx.addInterest(ctx, draftID, charge); err != nil {
+			return err
+		}
+		return tx.calculateTotals(ctx, draftID, nil)
+	})
+}
+
+func (s *invoiceService) ChargeInterest(
+	ctx context.Context,
+	charge *domain.InterestCharge,
+	prefix string,
+	footer domain.InvoiceFooter,
+) (*domain.Invoice, error) {
+	if charge.Amount <= 0 {
+		return nil, ErrNoInterestOwed
+	}
+
+	var invoice *domain.Invoice
+	err := s.inTx(ctx, func(tx *invoiceService) error {
+		draft, err := tx.CreateDraft(ctx, charge.ClientID, charge.DueDate, charge.Until, prefix, footer)
+		if err != nil {
+			return fmt.Errorf("create draft: %w", err)
+		}
+		if err := tx.addInterest(ctx, draft.ID, charge); err != nil {
+			return err
+		}
+		if err := tx.calculateTotals(ctx, draft.ID, nil); err != nil {
+			return fmt.Errorf("calculate totals: %w", err)
+		}
+		invoice, err = tx.invoiceRepo.GetByID(ctx, draft.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// addInterest adds the charge as a line item unless the draft already
+// charges interest on the same invoice
+func (s *invoiceService) addInterest(ctx context.Context, draftID int64, charge *domain.InterestCharge) error {
+	if charge.Amount <= 0 {
+		return ErrNoInterestOwed
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, draftID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+	if !invoice.CanEdit() {
+		return ErrInvoiceNotEditable
+	}
+	if invoice.ClientID != charge.ClientID {
+		return fmt.Errorf("invoice %s is for another client", invoice.InvoiceNumber)
+	}
+
+	lineItems, err := s.invoiceRepo.GetLineItems(ctx, draftID)
+	if err != nil {
+		return err
+	}
+	for _, item := range lineItems {
+		if item.InterestInvoiceID == charge.InvoiceID {
+			return fmt.Errorf("invoice %s already charges interest on %s", invoice.InvoiceNumber, charge.InvoiceNumber)
+		}
+	}
+
+	if err := s.invoiceRepo.AddLineItem(ctx, draftID, charge.LineItem()); err != nil {
+		return err
+	}
+
+	s.log.Info("interest charged",
+		"invoice_id", draftID,
+		"late_invoice_id", charge.InvoiceID,
+		"days", charge.Days,
+		"rate", charge.Rate.String(),
+		"amount", charge.Amount,
+	)
+	return nil
 }
 
 func (s *invoiceService) CreateDraft(
@@ -73,6 +355,7 @@ func (s *invoiceService) CreateDraft(
 	clientID int64,
 	periodStart, periodEnd time.Time,
 	prefix string,
+	footer domain.InvoiceFooter,
 ) (*domain.Invoice, error) {
 	// Verify client exists
 	client, err := s.clientRepo.GetByID(ctx, clientID)
@@ -83,27 +366,114 @@ func (s *invoiceService) CreateDraft(
 		return nil, errors.New("client not found")
 	}
 
-	// Generate invoice number
-	year := periodEnd.Year()
-	invoiceNumber, err := s.invoiceRepo.GetNextInvoiceNumber(ctx, prefix, year)
+	// Create invoice under the next free number
+	invoice := domain.NewInvoice("", clientID, periodStart, periodEnd)
+	invoice.Footer = footer
+	if err := createNumbered(ctx, s.invoiceRepo, invoice, prefix); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("invoice draft created", "invoice_id", invoice.ID, "number", invoice.InvoiceNumber, "client_id", clientID)
+	return invoice, nil
+}
+
+// numberAttempts is how many times createNumbered takes the next number
+// before giving up on invoices created alongside it
+const numberAttempts = 5
+
+// createNumbered saves invoice under the next number for prefix in its
+// period's year. A number taken between reading it and saving, by another
+// process generating invoices at the same time, is skipped for the next.
+// Inside a unit of work the transaction holds the write lock from the
+// start, so no other process can take it there.
+func createNumbered(ctx context.Context, repo repository.InvoiceRepository, invoice *domain.Invoice, prefix string) error {
+	year := invoice.PeriodEnd.Year()
+	for attempt := 1; ; attempt++ {
+		number, err := repo.GetNextInvoiceNumber(ctx, prefix, year)
+		if err != nil {
+			return fmt.Errorf("failed to generate invoice number: %w", err)
+		}
+		invoice.InvoiceNumber = number
+		if err := invoice.Validate(); err != nil {
+			return err
+		}
+
+		err = repo.Create(ctx, invoice)
+		if errors.Is(err, repository.ErrDuplicateNumber) && attempt < numberAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+func (s *invoiceService) Preview(
+	ctx context.Context,
+	clientID int64,
+	periodStart, periodEnd time.Time,
+	prefix string,
+	taxes []*domain.InvoiceTax,
+	footer domain.InvoiceFooter,
+) (*domain.Invoice, error) {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+
+	entries, err := s.entryRepo.GetUnbilledByClient(ctx, clientID, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The number is what the next draft would get; nothing is reserved
+	invoiceNumber, err := s.invoiceRepo.GetNextInvoiceNumber(ctx, prefix, periodEnd.Year())
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate invoice number: %w", err)
 	}
 
-	// Create invoice
 	invoice := domain.NewInvoice(invoiceNumber, clientID, periodStart, periodEnd)
+	invoice.Client = client
+	invoice.Taxes = taxes
+	invoice.Footer = footer
 	if err := invoice.Validate(); err != nil {
 		return nil, err
 	}
 
-	if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
-		return nil, err
+	for _, entry := range entries {
+		if client.CanInvoice(entry) {
+			invoice.LineItems = append(invoice.LineItems, newLineItem(0, entry))
+		}
 	}
+	invoice.LineItems = append(invoice.LineItems, client.Billing.Adjustments(invoice.LineItems)...)
+	invoice.CalculateTotals()
 
 	return invoice, nil
 }
 
+// newLineItem builds the invoice line item for a time entry
+func newLineItem(invoiceID int64, entry *domain.TimeEntry) *domain.InvoiceLineItem {
+	return &domain.InvoiceLineItem{
+		InvoiceID:   invoiceID,
+		EntryID:     entry.ID,
+		Date:        entry.StartTime,
+		Description: entry.Description,
+		Hours:       entry.Duration().Hours(),
+		Rate:        entry.HourlyRate,
+		Amount:      entry.Amount(),
+		RateReason:  entry.RateReason,
+		Role:        entry.Role,
+	}
+}
+
 func (s *invoiceService) AddEntriesToInvoice(ctx context.Context, invoiceID int64, entryIDs []int64) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.addEntriesToInvoice(ctx, invoiceID, entryIDs)
+	})
+}
+
+func (s *invoiceService) addEntriesToInvoice(ctx context.Context, invoiceID int64, entryIDs []int64) error {
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
 	if err != nil {
@@ -118,54 +488,106 @@ func (s *invoiceService) AddEntriesToInvoice(ctx context.Context, invoiceID int6
 		return ErrInvoiceNotEditable
 	}
 
-	// Verify all entries are unlocked
+	client, err := s.clientRepo.GetByID(ctx, invoice.ClientID)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.entryRepo.GetByIDs(ctx, entryIDs)
+	if err != nil {
+		return err
+	}
+
+	// Verify all entries exist, are unlocked, and belong to the invoice client
 	for _, entryID := range entryIDs {
-		locked, err := s.entryRepo.IsLocked(ctx, entryID)
-		if err != nil {
-			return err
+		entry, ok := entries[entryID]
+		if !ok {
+			return fmt.Errorf("%w: entry %d", ErrEntryNotFound, entryID)
 		}
-		if locked {
+		if entry.IsLocked() {
 			return fmt.Errorf("%w: entry %d", ErrEntryAlreadyLocked, entryID)
 		}
-
-		// Verify entry exists and belongs to invoice client
-		entry, err := s.entryRepo.GetByID(ctx, entryID)
-		if err != nil {
-			return err
-		}
-		if entry == nil {
-			return fmt.Errorf("%w: entry %d", ErrEntryNotFound, entryID)
-		}
 		if entry.ClientID != invoice.ClientID {
 			return fmt.Errorf("entry %d does not belong to invoice client", entryID)
 		}
+		if !client.CanInvoice(entry) {
+			return fmt.Errorf("%w: entry %d is %s", ErrEntryNotApproved, entryID, entry.Approval)
+		}
 	}
 
 	// Create line items for each entry
 	for _, entryID := range entryIDs {
-		entry, err := s.entryRepo.GetByID(ctx, entryID)
-		if err != nil {
+		if err := s.invoiceRepo.AddLineItem(ctx, invoiceID, newLineItem(invoiceID, entries[entryID])); err != nil {
 			return err
 		}
+	}
 
-		lineItem := &domain.InvoiceLineItem{
-			InvoiceID:   invoiceID,
-			EntryID:     entryID,
-			Date:        entry.StartTime,
-			Description: entry.Description,
-			Hours:       entry.Duration().Hours(),
-			Rate:        entry.HourlyRate,
-			Amount:      entry.Amount(),
-		}
+	s.log.Info("entries added to invoice", "invoice_id", invoiceID, "entry_ids", entryIDs)
+	return nil
+}
 
-		if err := s.invoiceRepo.AddLineItem(ctx, invoiceID, lineItem); err != nil {
-			return err
-		}
+func (s *invoiceService) SetFooter(ctx context.Context, invoiceID int64, footer domain.InvoiceFooter) error {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return errors.New("invoice not found")
 	}
 
+	// The footer is part of what the client receives
+	if !invoice.CanEdit() {
+		return ErrInvoiceNotEditable
+	}
+
+	invoice.Footer = footer
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice footer updated", "invoice_id", invoiceID)
 	return nil
 }
 
+func (s *invoiceService) RewordLineItem(ctx context.Context, invoiceID, lineItemID int64, description string) (*domain.InvoiceLineItem, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+	if !invoice.CanEdit() {
+		return nil, ErrInvoiceNotEditable
+	}
+
+	lineItems, err := s.invoiceRepo.GetLineItems(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	var item *domain.InvoiceLineItem
+	for _, li := range lineItems {
+		if li.ID == lineItemID {
+			item = li
+			break
+		}
+	}
+	if item == nil {
+		return nil, errors.New("line item not found on invoice")
+	}
+	if item.IsAdjustment() {
+		return nil, ErrAdjustmentNotEditable
+	}
+
+	item.Reword(description)
+	if err := s.invoiceRepo.SetLineItemDescription(ctx, invoiceID, item); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("line item reworded", "invoice_id", invoiceID, "line_item_id", lineItemID, "restored", !item.IsReworded())
+	return item, nil
+}
+
 func (s *invoiceService) RemoveEntryFromInvoice(ctx context.Context, invoiceID int64, entryID int64) error {
 	// Get invoice
 	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
@@ -203,12 +625,21 @@ func (s *invoiceService) RemoveEntryFromInvoice(ctx context.Context, invoiceID i
 	if err := s.invoiceRepo.DeleteLineItem(ctx, invoiceID, target.ID); err != nil {
 		return err
 	}
+	s.log.Info("entry removed from invoice", "invoice_id", invoiceID, "entry_id", entryID, "amount", target.Amount)
+
+	// Recalculate totals keeping the invoice's tax lines
+	return s.CalculateTotals(ctx, invoiceID, nil)
+}
 
-	// Recalculate totals using the invoice's tax rate
-	return s.CalculateTotals(ctx, invoiceID, invoice.TaxRate)
+// CalculateTotals runs in a single transaction since refreshing a draft's
+// adjustments replaces line items
+func (s *invoiceService) CalculateTotals(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.calculateTotals(ctx, invoiceID, taxes)
+	})
 }
 
-func (s *invoiceService) CalculateTotals(ctx context.Context, invoiceID int64, taxRate float64) error {
+func (s *invoiceService) calculateTotals(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error {
 	// Get invoice with line items
 	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
 	if err != nil {
@@ -223,17 +654,94 @@ func (s *invoiceService) CalculateTotals(ctx context.Context, invoiceID int64, t
 	if err != nil {
 		return err
 	}
+
+	// Drafts follow the client's current billing rules
+	if invoice.CanEdit() {
+		if lineItems, err = s.applyBillingRules(ctx, invoice, lineItems); err != nil {
+			return err
+		}
+	}
 	invoice.LineItems = lineItems
 
-	// Set tax rate and calculate
-	invoice.TaxRate = taxRate
+	// Replace the tax lines, or keep the current ones
+	if taxes == nil {
+		if taxes, err = s.invoiceRepo.GetTaxes(ctx, invoiceID); err != nil {
+			return err
+		}
+	}
+	invoice.Taxes = taxes
+	if len(taxes) == 0 {
+		invoice.TaxRate = 0
+	}
+
+	oldTotal := invoice.Total
 	invoice.CalculateTotals()
 
-	// Save updated invoice
-	return s.invoiceRepo.Update(ctx, invoice)
+	// Save updated invoice and its tax lines
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+	if err := s.invoiceRepo.SetTaxes(ctx, invoiceID, taxes); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice totals calculated",
+		"invoice_id", invoiceID,
+		"line_items", len(lineItems),
+		"subtotal", invoice.Subtotal,
+		"tax_rate", invoice.TaxRate,
+		"old_total", oldTotal,
+		"total", invoice.Total,
+	)
+	return nil
 }
 
-func (s *invoiceService) Finalize(ctx context.Context, invoiceID int64) error {
+// applyBillingRules replaces a draft's adjustment line items with those
+// derived from the client's billing rules and returns the new line items
+func (s *invoiceService) applyBillingRules(ctx context.Context, invoice *domain.Invoice, lineItems []*domain.InvoiceLineItem) ([]*domain.InvoiceLineItem, error) {
+	client, err := s.clientRepo.GetByID(ctx, invoice.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+
+	items := make([]*domain.InvoiceLineItem, 0, len(lineItems))
+	removed := 0
+	for _, item := range lineItems {
+		if !item.IsAdjustment() {
+			items = append(items, item)
+			continue
+		}
+		if err := s.invoiceRepo.DeleteLineItem(ctx, invoice.ID, item.ID); err != nil {
+			return nil, err
+		}
+		removed++
+	}
+
+	adjustments := client.Billing.Adjustments(items)
+	for _, item := range adjustments {
+		if err := s.invoiceRepo.AddLineItem(ctx, invoice.ID, item); err != nil {
+			return nil, err
+		}
+	}
+
+	if removed > 0 || len(adjustments) > 0 {
+		s.log.Info("billing rules applied", "invoice_id", invoice.ID, "adjustments", len(adjustments), "replaced", removed)
+	}
+	return append(items, adjustments...), nil
+}
+
+// Finalize runs in a single transaction so a failure part way through
+// never leaves entries locked to a draft
+func (s *invoiceService) Finalize(ctx context.Context, invoiceID int64, defaultDueDays int) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.finalize(ctx, invoiceID, defaultDueDays)
+	})
+}
+
+func (s *invoiceService) finalize(ctx context.Context, invoiceID int64, defaultDueDays int) error {
 	// Get invoice with line items
 	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
 	if err != nil {
@@ -254,14 +762,21 @@ func (s *invoiceService) Finalize(ctx context.Context, invoiceID int64) error {
 		return err
 	}
 
-	if len(lineItems) == 0 {
-		return errors.New("cannot finalize invoice with no line items")
+	// Extract entry IDs; adjustments and estimated items have none
+	var entryIDs []int64
+	billed := 0
+	for _, item := range lineItems {
+		if item.IsAdjustment() {
+			continue
+		}
+		billed++
+		if item.EntryID != 0 {
+			entryIDs = append(entryIDs, item.EntryID)
+		}
 	}
 
-	// Extract entry IDs
-	entryIDs := make([]int64, len(lineItems))
-	for i, item := range lineItems {
-		entryIDs[i] = item.EntryID
+	if billed == 0 {
+		return errors.New("cannot finalize invoice with no line items")
 	}
 
 	// Lock all entries to this invoice
@@ -269,12 +784,131 @@ func (s *invoiceService) Finalize(ctx context.Context, invoiceID int64) error {
 		return fmt.Errorf("failed to lock entries: %w", err)
 	}
 
-	// Update invoice status
+	// Update invoice status, fixing the due date it was implied to have
 	invoice.Finalize()
+	if invoice.DueDate == nil {
+		due := invoice.DueOn(defaultDueDays)
+		invoice.DueDate = &due
+	}
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice finalized", "invoice_id", invoiceID, "number", invoice.InvoiceNumber, "locked_entries", len(entryIDs))
+	return nil
+}
+
+func (s *invoiceService) SetDueDate(ctx context.Context, invoiceID int64, due time.Time, note string) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.setDueDate(ctx, invoiceID, due, note)
+	})
+}
+
+func (s *invoiceService) setDueDate(ctx context.Context, invoiceID int64, due time.Time, note string) error {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	if invoice.Status == domain.InvoiceStatusVoid {
+		return ErrInvoiceVoid
+	}
+	if !invoice.CanChangeDueDate() {
+		return ErrDueDateLocked
+	}
+	note = strings.TrimSpace(note)
+	if invoice.DueDateChangeNeedsNote() && note == "" {
+		return ErrDueDateNoteRequired
+	}
+
+	now := time.Now()
+	from := invoice.DueDate
+	if invoice.DueDateChangeNeedsNote() {
+		change := &domain.DueDateChange{InvoiceID: invoiceID, From: from, To: due, Note: note, ChangedAt: now}
+		if err := s.invoiceRepo.AddDueDateChange(ctx, change); err != nil {
+			return err
+		}
+	}
+
+	invoice.DueDate = &due
+	if invoice.Status == domain.InvoiceStatusOverdue && !now.After(due) {
+		invoice.Status = domain.InvoiceStatusSent
+	}
+	invoice.UpdatedAt = now
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice due date changed",
+		"invoice_id", invoiceID,
+		"due_date", due.Format(time.DateOnly),
+		"status", string(invoice.Status),
+		"recorded", invoice.DueDateChangeNeedsNote(),
+	)
+	return nil
+}
+
+func (s *invoiceService) Reopen(ctx context.Context, invoiceID int64) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.reopen(ctx, invoiceID)
+	})
+}
+
+func (s *invoiceService) reopen(ctx context.Context, invoiceID int64) error {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	if !invoice.CanReopen() {
+		return fmt.Errorf("%w (invoice is %s)", ErrInvoiceNotReopenable, invoice.Status)
+	}
+
+	// Line items stay on the draft; only the locks are released
+	released, err := s.entryRepo.UnlockForInvoice(ctx, invoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to unlock entries: %w", err)
+	}
+
+	invoice.Reopen()
 	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
 		return err
 	}
 
+	s.log.Info("invoice reopened", "invoice_id", invoiceID, "number", invoice.InvoiceNumber, "released_entries", released)
+	return nil
+}
+
+func (s *invoiceService) Delete(ctx context.Context, invoiceID int64) error {
+	return s.inTx(ctx, func(tx *invoiceService) error {
+		return tx.delete(ctx, invoiceID)
+	})
+}
+
+func (s *invoiceService) delete(ctx context.Context, invoiceID int64) error {
+	invoice, err := s.invoiceRepo.GetByID(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	if !invoice.CanDelete() {
+		return fmt.Errorf("%w (invoice is %s)", ErrInvoiceNotDeletable, invoice.Status)
+	}
+
+	if err := s.invoiceRepo.Void(ctx, invoiceID); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice deleted", "invoice_id", invoiceID, "number", invoice.InvoiceNumber)
 	return nil
 }
 
@@ -287,14 +921,23 @@ func (s *invoiceService) MarkSent(ctx context.Context, invoiceID int64) error {
 		return errors.New("invoice not found")
 	}
 
+	if invoice.Status == domain.InvoiceStatusVoid {
+		return ErrInvoiceVoid
+	}
 	if invoice.Status == domain.InvoiceStatusDraft {
 		return errors.New("cannot mark draft invoice as sent - finalize first")
 	}
 
+	from := invoice.Status
 	invoice.Status = domain.InvoiceStatusSent
 	invoice.UpdatedAt = time.Now()
 
-	return s.invoiceRepo.Update(ctx, invoice)
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice status changed", "invoice_id", invoiceID, "from", string(from), "to", string(invoice.Status))
+	return nil
 }
 
 func (s *invoiceService) MarkPaid(ctx context.Context, invoiceID int64, paidDate time.Time) error {
@@ -306,11 +949,26 @@ func (s *invoiceService) MarkPaid(ctx context.Context, invoiceID int64, paidDate
 		return errors.New("invoice not found")
 	}
 
+	if invoice.Status == domain.InvoiceStatusVoid {
+		return ErrInvoiceVoid
+	}
+
+	from := invoice.Status
 	invoice.Status = domain.InvoiceStatusPaid
 	invoice.PaidDate = &paidDate
 	invoice.UpdatedAt = time.Now()
 
-	return s.invoiceRepo.Update(ctx, invoice)
+	if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
+		return err
+	}
+
+	s.log.Info("invoice status changed",
+		"invoice_id", invoiceID,
+		"from", string(from),
+		"to", string(invoice.Status),
+		"paid_date", paidDate.Format(time.DateOnly),
+	)
+	return nil
 }
 
 func (s *invoiceService) CheckOverdue(ctx context.Context) error {
@@ -329,6 +987,7 @@ func (s *invoiceService) CheckOverdue(ctx context.Context) error {
 			if err := s.invoiceRepo.Update(ctx, invoice); err != nil {
 				return err
 			}
+			s.log.Info("invoice status changed", "invoice_id", invoice.ID, "from", string(domain.InvoiceStatusSent), "to", string(invoice.Status))
 		}
 	}
 
@@ -3,20 +3,43 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/andy/timesink/internal/db"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
 )
 
+// discardLog swallows service logging in tests
+var discardLog = slog.New(slog.DiscardHandler)
+
 // mock implementations
 type mockInvoiceRepo struct {
 	invoices  map[int64]*domain.Invoice
 	lineItems map[int64][]*domain.InvoiceLineItem
+	taxes     map[int64][]*domain.InvoiceTax
 	updated   *domain.Invoice
+	voided    int64
+	dueDates  []*domain.DueDateChange
 }
 
-func (m *mockInvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error { return nil }
+func (m *mockInvoiceRepo) Create(ctx context.Context, invoice *domain.Invoice) error {
+	if m.invoices == nil {
+		return nil
+	}
+	for id := range m.invoices {
+		invoice.ID = max(invoice.ID, id)
+	}
+	invoice.ID++
+	m.invoices[invoice.ID] = invoice
+	return nil
+}
 func (m *mockInvoiceRepo) GetByID(ctx context.Context, id int64) (*domain.Invoice, error) {
 	if inv, ok := m.invoices[id]; ok {
 		return inv, nil
@@ -27,12 +50,23 @@ func (m *mockInvoiceRepo) GetByNumber(ctx context.Context, number string) (*doma
 	return nil, nil
 }
 func (m *mockInvoiceRepo) List(ctx context.Context, clientID *int64, status *domain.InvoiceStatus) ([]*domain.Invoice, error) {
-	return nil, nil
+	var out []*domain.Invoice
+	for _, inv := range m.invoices {
+		if (clientID == nil || inv.ClientID == *clientID) && (status == nil || inv.Status == *status) {
+			out = append(out, inv)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
 }
 func (m *mockInvoiceRepo) Update(ctx context.Context, invoice *domain.Invoice) error {
 	m.updated = invoice
 	return nil
 }
+func (m *mockInvoiceRepo) Void(ctx context.Context, id int64) error {
+	m.voided = id
+	return nil
+}
 func (m *mockInvoiceRepo) AddLineItem(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error {
 	m.lineItems[invoiceID] = append(m.lineItems[invoiceID], item)
 	return nil
@@ -44,9 +78,32 @@ func (m *mockInvoiceRepo) GetLineItems(ctx context.Context, invoiceID int64) ([]
 	copy(out, items)
 	return out, nil
 }
+func (m *mockInvoiceRepo) SetLineItemDescription(ctx context.Context, invoiceID int64, item *domain.InvoiceLineItem) error {
+	return nil
+}
+func (m *mockInvoiceRepo) SetTaxes(ctx context.Context, invoiceID int64, taxes []*domain.InvoiceTax) error {
+	if m.taxes == nil {
+		m.taxes = make(map[int64][]*domain.InvoiceTax)
+	}
+	m.taxes[invoiceID] = taxes
+	return nil
+}
+func (m *mockInvoiceRepo) GetTaxes(ctx context.Context, invoiceID int64) ([]*domain.InvoiceTax, error) {
+	return m.taxes[invoiceID], nil
+}
+func (m *mockInvoiceRepo) AddDueDateChange(ctx context.Context, change *domain.DueDateChange) error {
+	m.dueDates = append(m.dueDates, change)
+	return nil
+}
+func (m *mockInvoiceRepo) GetDueDateChanges(ctx context.Context, invoiceID int64) ([]*domain.DueDateChange, error) {
+	return m.dueDates, nil
+}
 func (m *mockInvoiceRepo) GetNextInvoiceNumber(ctx context.Context, prefix string, year int) (string, error) {
 	return "INV-2026-001", nil
 }
+func (m *mockInvoiceRepo) GetLastPeriodEnd(ctx context.Context, clientID int64) (*time.Time, error) {
+	return nil, nil
+}
 func (m *mockInvoiceRepo) DeleteLineItem(ctx context.Context, invoiceID int64, lineItemID int64) error {
 	items := m.lineItems[invoiceID]
 	for i, it := range items {
@@ -59,45 +116,119 @@ func (m *mockInvoiceRepo) DeleteLineItem(ctx context.Context, invoiceID int64, l
 	return errors.New("not found")
 }
 
-type mockEntryRepo struct{}
+type mockEntryRepo struct {
+	entries        []*domain.TimeEntry // returned by List, unfiltered, and looked up by GetByIDs
+	unbilled       []*domain.TimeEntry
+	unlockedForInv int64
+	created        []*domain.TimeEntry
+}
 
-func (m *mockEntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error { return nil }
+func (m *mockEntryRepo) Create(ctx context.Context, entry *domain.TimeEntry) error {
+	m.created = append(m.created, entry)
+	return nil
+}
 func (m *mockEntryRepo) GetByID(ctx context.Context, id int64) (*domain.TimeEntry, error) {
 	return nil, nil
 }
+func (m *mockEntryRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.TimeEntry, error) {
+	found := make(map[int64]*domain.TimeEntry)
+	for _, e := range m.entries {
+		for _, id := range ids {
+			if e.ID == id {
+				found[id] = e
+			}
+		}
+	}
+	return found, nil
+}
 func (m *mockEntryRepo) Update(ctx context.Context, entry *domain.TimeEntry, reason string) error {
 	return nil
 }
 func (m *mockEntryRepo) SoftDelete(ctx context.Context, id int64, reason string) error { return nil }
-func (m *mockEntryRepo) List(ctx context.Context, clientID *int64, start, end *time.Time, includeLocked bool) ([]*domain.TimeEntry, error) {
+func (m *mockEntryRepo) Restore(ctx context.Context, id int64, reason string) error    { return nil }
+func (m *mockEntryRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockEntryRepo) ListDeleted(ctx context.Context, clientID *int64) ([]*domain.TimeEntry, error) {
 	return nil, nil
 }
-func (m *mockEntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, start, end time.Time) ([]*domain.TimeEntry, error) {
+func (m *mockEntryRepo) ListByInvoice(ctx context.Context, invoiceID int64) ([]*domain.TimeEntry, error) {
+	return nil, nil
+}
+func (m *mockEntryRepo) Split(ctx context.Context, id int64, at time.Time, reason string) (*domain.TimeEntry, error) {
 	return nil, nil
 }
+func (m *mockEntryRepo) List(ctx context.Context, clientID *int64, start, end *time.Time, includeLocked bool) ([]*domain.TimeEntry, error) {
+	return m.entries, nil
+}
+func (m *mockEntryRepo) GetUnbilledByClient(ctx context.Context, clientID int64, start, end time.Time) ([]*domain.TimeEntry, error) {
+	return m.unbilled, nil
+}
+func (m *mockEntryRepo) GetUnbilledSinceLastInvoice(ctx context.Context, clientID int64, end time.Time) ([]*domain.TimeEntry, error) {
+	return m.unbilled, nil
+}
 func (m *mockEntryRepo) IsLocked(ctx context.Context, id int64) (bool, error) { return false, nil }
 func (m *mockEntryRepo) LockForInvoice(ctx context.Context, entryIDs []int64, invoiceID int64) error {
 	return nil
 }
+func (m *mockEntryRepo) UnlockForInvoice(ctx context.Context, invoiceID int64) (int64, error) {
+	m.unlockedForInv = invoiceID
+	return 0, nil
+}
+func (m *mockEntryRepo) SetApproval(ctx context.Context, entryIDs []int64, status domain.ApprovalStatus, note string) error {
+	return nil
+}
 func (m *mockEntryRepo) GetHistory(ctx context.Context, entryID int64) ([]*domain.EntryHistory, error) {
 	return nil, nil
 }
+func (m *mockEntryRepo) RecentDescriptions(ctx context.Context, clientID int64, limit int) ([]string, error) {
+	return nil, nil
+}
 
-type mockClientRepo struct{}
+type mockClientRepo struct {
+	billing         domain.BillingRules
+	rate            float64
+	requireApproval bool
+	clients         []*domain.Client   // Returned by List
+	roles           []*domain.RoleRate // Returned by ListRoleRates
+}
 
 func (m *mockClientRepo) Create(ctx context.Context, client *domain.Client) error { return nil }
 func (m *mockClientRepo) GetByID(ctx context.Context, id int64) (*domain.Client, error) {
-	return &domain.Client{ID: id, Name: "ACME"}, nil
+	return &domain.Client{ID: id, Name: "ACME", Billing: m.billing, RequireApproval: m.requireApproval}, nil
+}
+func (m *mockClientRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*domain.Client, error) {
+	clients := make(map[int64]*domain.Client, len(ids))
+	for _, id := range ids {
+		clients[id], _ = m.GetByID(ctx, id)
+	}
+	return clients, nil
 }
 func (m *mockClientRepo) GetByName(ctx context.Context, name string) (*domain.Client, error) {
 	return nil, nil
 }
 func (m *mockClientRepo) List(ctx context.Context, includeArchived bool) ([]*domain.Client, error) {
-	return nil, nil
+	return m.clients, nil
 }
 func (m *mockClientRepo) Update(ctx context.Context, client *domain.Client) error { return nil }
 func (m *mockClientRepo) Archive(ctx context.Context, id int64) error             { return nil }
 func (m *mockClientRepo) Unarchive(ctx context.Context, id int64) error           { return nil }
+func (m *mockClientRepo) SetRate(ctx context.Context, clientID int64, rate float64, effectiveFrom time.Time) error {
+	return nil
+}
+func (m *mockClientRepo) RateAt(ctx context.Context, clientID int64, at time.Time) (float64, error) {
+	return m.rate, nil
+}
+func (m *mockClientRepo) ListRates(ctx context.Context, clientID int64) ([]*domain.ClientRate, error) {
+	return nil, nil
+}
+func (m *mockClientRepo) SetRoleRate(ctx context.Context, role *domain.RoleRate) error { return nil }
+func (m *mockClientRepo) DeleteRoleRate(ctx context.Context, clientID int64, role string) error {
+	return nil
+}
+func (m *mockClientRepo) ListRoleRates(ctx context.Context, clientID int64) ([]*domain.RoleRate, error) {
+	return m.roles, nil
+}
 
 func TestRemoveEntryFromInvoice_Success(t *testing.T) {
 	ctx := context.Background()
@@ -107,8 +238,8 @@ func TestRemoveEntryFromInvoice_Success(t *testing.T) {
 	inv.ID = 10
 	inv.TaxRate = 0.10
 
-	li1 := &domain.InvoiceLineItem{ID: 1, InvoiceID: inv.ID, EntryID: 100, Hours: 2, Rate: 50, Amount: 100}
-	li2 := &domain.InvoiceLineItem{ID: 2, InvoiceID: inv.ID, EntryID: 101, Hours: 1, Rate: 75, Amount: 75}
+	li1 := &domain.InvoiceLineItem{ID: 1, InvoiceID: inv.ID, EntryID: 100, Hours: 2, Rate: 50, Amount: 10000}
+	li2 := &domain.InvoiceLineItem{ID: 2, InvoiceID: inv.ID, EntryID: 101, Hours: 1, Rate: 75, Amount: 7500}
 
 	mockInv := &mockInvoiceRepo{
 		invoices:  map[int64]*domain.Invoice{inv.ID: inv},
@@ -119,6 +250,7 @@ func TestRemoveEntryFromInvoice_Success(t *testing.T) {
 		invoiceRepo: mockInv,
 		entryRepo:   &mockEntryRepo{},
 		clientRepo:  &mockClientRepo{},
+		log:         discardLog,
 	}
 
 	// Remove entry 100 (li1)
@@ -138,8 +270,46 @@ func TestRemoveEntryFromInvoice_Success(t *testing.T) {
 	}
 
 	// Updated invoice should have subtotal equal to remaining amount (75)
-	if mockInv.updated.Subtotal != 75 {
-		t.Fatalf("expected subtotal 75, got %v", mockInv.updated.Subtotal)
+	if mockInv.updated.Subtotal != 7500 {
+		t.Fatalf("expected subtotal 75.00, got %.2f", mockInv.updated.Subtotal.Float())
+	}
+}
+
+func TestRewordLineItem_KeepsTheOriginal(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+	item := &domain.InvoiceLineItem{ID: 1, InvoiceID: inv.ID, EntryID: 100, Description: "fixd login bug w/ jwt"}
+	adjustment := &domain.InvoiceLineItem{ID: 2, InvoiceID: inv.ID, Description: "Minimum increment"}
+	mockInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{inv.ID: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{inv.ID: {item, adjustment}},
+	}
+	svc := &invoiceService{invoiceRepo: mockInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, log: discardLog}
+
+	for _, wording := range []string{"Fixed sign-in", "Fixed sign-in errors"} {
+		if _, err := svc.RewordLineItem(ctx, inv.ID, item.ID, wording); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if item.Description != "Fixed sign-in errors" || item.OriginalDescription != "fixd login bug w/ jwt" {
+		t.Fatalf("expected the first wording kept as the original, got %+v", item)
+	}
+
+	if _, err := svc.RewordLineItem(ctx, inv.ID, item.ID, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item.Description != "fixd login bug w/ jwt" || item.IsReworded() {
+		t.Fatalf("expected an empty description to restore the original, got %+v", item)
+	}
+
+	if _, err := svc.RewordLineItem(ctx, inv.ID, adjustment.ID, "Rounding"); !errors.Is(err, ErrAdjustmentNotEditable) {
+		t.Fatalf("expected adjustments to be refused, got %v", err)
+	}
+	inv.Status = domain.InvoiceStatusFinalized
+	if _, err := svc.RewordLineItem(ctx, inv.ID, item.ID, "Fixed sign-in"); !errors.Is(err, ErrInvoiceNotEditable) {
+		t.Fatalf("expected a finalized invoice to be refused, got %v", err)
 	}
 }
 
@@ -158,6 +328,7 @@ func TestRemoveEntryFromInvoice_NotFound(t *testing.T) {
 		invoiceRepo: mockInv,
 		entryRepo:   &mockEntryRepo{},
 		clientRepo:  &mockClientRepo{},
+		log:         discardLog,
 	}
 
 	err := svc.RemoveEntryFromInvoice(ctx, inv.ID, 999)
@@ -165,3 +336,707 @@ func TestRemoveEntryFromInvoice_NotFound(t *testing.T) {
 		t.Fatalf("expected error for missing entry")
 	}
 }
+
+func TestAddEntriesToInvoice_ChecksEveryEntryBeforeAdding(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	lockedTo := int64(5)
+	entries := []*domain.TimeEntry{
+		{ID: 100, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true},
+		{ID: 101, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, InvoiceID: &lockedTo},
+		{ID: 102, ClientID: 2, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true},
+	}
+
+	inv := domain.NewInvoice("INV-2026-001", 1, start, start.AddDate(0, 1, 0))
+	inv.ID = 12
+	mockInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{inv.ID: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{},
+	}
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{entries: entries},
+		clientRepo:  &mockClientRepo{},
+		log:         discardLog,
+	}
+
+	// Locked, missing, and another client's entries are all rejected
+	if err := svc.AddEntriesToInvoice(ctx, inv.ID, []int64{100, 101}); !errors.Is(err, ErrEntryAlreadyLocked) {
+		t.Fatalf("expected ErrEntryAlreadyLocked, got %v", err)
+	}
+	if err := svc.AddEntriesToInvoice(ctx, inv.ID, []int64{100, 999}); !errors.Is(err, ErrEntryNotFound) {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+	if err := svc.AddEntriesToInvoice(ctx, inv.ID, []int64{100, 102}); err == nil {
+		t.Fatalf("expected an error for another client's entry")
+	}
+	if len(mockInv.lineItems[inv.ID]) != 0 {
+		t.Fatalf("expected nothing added when any entry is rejected, got %d line items", len(mockInv.lineItems[inv.ID]))
+	}
+
+	if err := svc.AddEntriesToInvoice(ctx, inv.ID, []int64{100}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items := mockInv.lineItems[inv.ID]; len(items) != 1 || items[0].EntryID != 100 || items[0].Amount != 10000 {
+		t.Fatalf("expected a line item for entry 100, got %+v", items)
+	}
+}
+
+func TestDraftAll_DraftsApprovedTimeAndCountsHeld(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	entries := []*domain.TimeEntry{
+		{ID: 100, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, Approval: domain.ApprovalApproved},
+		{ID: 101, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, Approval: domain.ApprovalPending},
+	}
+
+	mockInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{},
+		lineItems: map[int64][]*domain.InvoiceLineItem{},
+	}
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{entries: entries, unbilled: entries},
+		clientRepo: &mockClientRepo{
+			requireApproval: true,
+			clients:         []*domain.Client{{ID: 1, Name: "ACME", RequireApproval: true}},
+		},
+		log: discardLog,
+	}
+
+	periodStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0).Add(-time.Second)
+	noTaxes := func(*domain.Client) []*domain.InvoiceTax { return nil }
+	results, err := svc.DraftAll(ctx, periodStart, periodEnd, "INV", noTaxes, domain.InvoiceFooter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected one client's result, got %d", len(results))
+	}
+	got := results[0]
+	if got.Err != nil || got.Invoice == nil {
+		t.Fatalf("expected a draft, got err %v", got.Err)
+	}
+	if got.Entries != 1 || got.Held != 1 {
+		t.Fatalf("expected 1 entry drafted and 1 held, got %d and %d", got.Entries, got.Held)
+	}
+	if got.Invoice.Status != domain.InvoiceStatusDraft || got.Invoice.Total != 10000 {
+		t.Fatalf("expected a $100.00 draft, got %s for %d", got.Invoice.Status, got.Invoice.Total)
+	}
+}
+
+func TestDraftAll_TwiceDraftsEachEntryOnce(t *testing.T) {
+	ctx := context.Background()
+
+	database := openTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	clients := repository.NewClientRepo(database)
+	entries := repository.NewEntryRepo(database)
+	invoices := repository.NewInvoiceRepo(database)
+	client := domain.NewClient("Acme", 100)
+	if err := clients.Create(ctx, client); err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	for d := 2; d <= 3; d++ {
+		entry := domain.NewTimeEntry(client.ID, "design", client.HourlyRate)
+		entry.StartTime = time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC)
+		entry.Stop(entry.StartTime.Add(time.Hour))
+		if err := entries.Create(ctx, entry); err != nil {
+			t.Fatalf("failed to create entry: %v", err)
+		}
+	}
+	svc := NewInvoiceService(invoices, entries, clients, repository.NewUnitOfWork(database, discardLog), discardLog)
+
+	periodStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0).Add(-time.Second)
+	noTaxes := func(*domain.Client) []*domain.InvoiceTax { return nil }
+	for run := 1; run <= 2; run++ {
+		if _, err := svc.DraftAll(ctx, periodStart, periodEnd, "INV", noTaxes, domain.InvoiceFooter{}); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+	}
+
+	drafts, err := invoices.List(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to list invoices: %v", err)
+	}
+	drafted := map[int64]int{}
+	for _, draft := range drafts {
+		items, err := invoices.GetLineItems(ctx, draft.ID)
+		if err != nil {
+			t.Fatalf("failed to get line items: %v", err)
+		}
+		for _, item := range items {
+			drafted[item.EntryID]++
+		}
+	}
+	if len(drafts) != 1 || len(drafted) != 2 {
+		t.Fatalf("expected both entries on one draft, got %d drafts covering %d entries", len(drafts), len(drafted))
+	}
+	for id, n := range drafted {
+		if n != 1 {
+			t.Fatalf("expected entry %d drafted once, got %d", id, n)
+		}
+	}
+}
+
+// openTestDB opens the database at path, migrating it if it is new
+func openTestDB(t *testing.T, path string) *db.DB {
+	t.Helper()
+	database, err := db.Open(path, "test")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if _, err := database.RunMigrations(); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	return database
+}
+
+// newSQLInvoiceService builds the invoice service over database, as the app
+// does
+func newSQLInvoiceService(database *db.DB) InvoiceService {
+	return NewInvoiceService(
+		repository.NewInvoiceRepo(database),
+		repository.NewEntryRepo(database),
+		repository.NewClientRepo(database),
+		repository.NewUnitOfWork(database, discardLog),
+		discardLog,
+	)
+}
+
+func TestGenerate_RacesAnotherProcessForNumbers(t *testing.T) {
+	ctx := context.Background()
+
+	// Two processes with their own connections to the same file, each
+	// generating invoices for its own client at the same moment
+	path := filepath.Join(t.TempDir(), "test.db")
+	databases := []*db.DB{openTestDB(t, path), openTestDB(t, path)}
+	clients := repository.NewClientRepo(databases[0])
+	entries := repository.NewEntryRepo(databases[0])
+
+	var ids []int64
+	for _, name := range []string{"Acme", "Globex"} {
+		client := domain.NewClient(name, 100)
+		if err := clients.Create(ctx, client); err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+		ids = append(ids, client.ID)
+	}
+
+	periodStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0).Add(-time.Second)
+	numbers := map[string]bool{}
+	for round := 0; round < 5; round++ {
+		var entryIDs []int64
+		for _, id := range ids {
+			entry := domain.NewTimeEntry(id, "design", 100)
+			entry.StartTime = periodStart.AddDate(0, 0, round).Add(9 * time.Hour)
+			entry.Stop(entry.StartTime.Add(time.Hour))
+			if err := entries.Create(ctx, entry); err != nil {
+				t.Fatalf("failed to create entry: %v", err)
+			}
+			entryIDs = append(entryIDs, entry.ID)
+		}
+
+		var wg sync.WaitGroup
+		ready := make(chan struct{})
+		invoices := make([]*domain.Invoice, len(ids))
+		errs := make([]error, len(ids))
+		for i := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				svc := newSQLInvoiceService(databases[i])
+				<-ready
+				invoices[i], errs[i] = svc.Generate(ctx, ids[i], periodStart, periodEnd, "INV", nil, domain.InvoiceFooter{}, entryIDs[i:i+1], 30)
+			}()
+		}
+		close(ready)
+		wg.Wait()
+
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("round %d: generate for client %d failed: %v", round, ids[i], err)
+			}
+			if numbers[invoices[i].InvoiceNumber] {
+				t.Fatalf("round %d: number %s given out twice", round, invoices[i].InvoiceNumber)
+			}
+			numbers[invoices[i].InvoiceNumber] = true
+		}
+	}
+}
+
+func TestPreview_BuildsInvoiceWithoutWriting(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end1 := start.Add(2 * time.Hour)
+	end2 := start.Add(26 * time.Hour)
+	entries := []*domain.TimeEntry{
+		{ID: 100, ClientID: 1, StartTime: start, EndTime: &end1, HourlyRate: 50, IsBillable: true, Description: "Design"},
+		{ID: 101, ClientID: 1, StartTime: start.Add(24 * time.Hour), EndTime: &end2, HourlyRate: 50, IsBillable: true},
+	}
+
+	// nil maps make any AddLineItem call panic
+	mockInv := &mockInvoiceRepo{}
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{unbilled: entries},
+		clientRepo:  &mockClientRepo{},
+		log:         discardLog,
+	}
+
+	taxes := []*domain.InvoiceTax{{Label: "Tax", Rate: 0.10}}
+	inv, err := svc.Preview(ctx, 1, start, start.AddDate(0, 0, 7), "INV", taxes, domain.InvoiceFooter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockInv.updated != nil {
+		t.Fatalf("preview must not update invoices")
+	}
+	if inv.ID != 0 || inv.Status != domain.InvoiceStatusDraft {
+		t.Fatalf("expected unsaved draft, got id %d status %s", inv.ID, inv.Status)
+	}
+	if inv.InvoiceNumber != "INV-2026-001" {
+		t.Fatalf("expected next invoice number, got %s", inv.InvoiceNumber)
+	}
+	if inv.Client == nil || inv.Client.Name != "ACME" {
+		t.Fatalf("expected client to be populated")
+	}
+	if len(inv.LineItems) != 2 {
+		t.Fatalf("expected 2 line items, got %d", len(inv.LineItems))
+	}
+	if inv.Subtotal != 20000 || inv.TaxAmount != 2000 || inv.Total != 22000 {
+		t.Fatalf("unexpected totals: subtotal %v tax %v total %v", inv.Subtotal, inv.TaxAmount, inv.Total)
+	}
+}
+
+func TestPreview_SkipsUnapprovedEntriesWhenRequired(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	entries := []*domain.TimeEntry{
+		{ID: 100, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, Approval: domain.ApprovalApproved},
+		{ID: 101, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, Approval: domain.ApprovalPending},
+		{ID: 102, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true, Approval: domain.ApprovalRejected},
+	}
+
+	svc := &invoiceService{
+		invoiceRepo: &mockInvoiceRepo{},
+		entryRepo:   &mockEntryRepo{unbilled: entries},
+		clientRepo:  &mockClientRepo{requireApproval: true},
+		log:         discardLog,
+	}
+
+	inv, err := svc.Preview(ctx, 1, start, start.AddDate(0, 0, 7), "INV", nil, domain.InvoiceFooter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inv.LineItems) != 1 || inv.LineItems[0].EntryID != 100 {
+		t.Fatalf("expected only the approved entry, got %d line items", len(inv.LineItems))
+	}
+}
+
+func TestCalculateTotals_AppliesBillingRules(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+
+	day1 := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	mockInv := &mockInvoiceRepo{
+		invoices: map[int64]*domain.Invoice{inv.ID: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{inv.ID: {
+			{ID: 1, InvoiceID: inv.ID, EntryID: 100, Date: day1, Hours: 1.0 / 3, Rate: 100, Amount: 3333},
+			{ID: 2, InvoiceID: inv.ID, EntryID: 101, Date: day1.Add(time.Hour), Hours: 9, Rate: 100, Amount: 90000},
+			{ID: 3, InvoiceID: inv.ID, EntryID: 102, Date: day2, Hours: 2, Rate: 100, Amount: 20000},
+		}},
+	}
+
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{},
+		clientRepo:  &mockClientRepo{billing: domain.BillingRules{MinIncrementMinutes: 60, DailyCapHours: 8, OvertimeMultiplier: 1.5}},
+		log:         discardLog,
+	}
+
+	// A second run replaces the adjustments rather than adding more
+	for i := 0; i < 2; i++ {
+		if err := svc.CalculateTotals(ctx, inv.ID, []*domain.InvoiceTax{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var adjustments []*domain.InvoiceLineItem
+	for _, item := range mockInv.lineItems[inv.ID] {
+		if item.IsAdjustment() {
+			adjustments = append(adjustments, item)
+		}
+	}
+	if len(adjustments) != 2 {
+		t.Fatalf("expected rounding and overtime adjustments, got %d", len(adjustments))
+	}
+	// 20m rounds up to 1h; the day then bills 10h, 2h over the cap
+	rounding, overtime := adjustments[0], adjustments[1]
+	if math.Abs(rounding.Hours-2.0/3) > 1e-9 || rounding.Amount != 6667 {
+		t.Fatalf("unexpected rounding adjustment: %+v", rounding)
+	}
+	if overtime.Hours != 2 || overtime.Amount != 10000 {
+		t.Fatalf("unexpected overtime adjustment: %+v", overtime)
+	}
+	if mockInv.updated.Subtotal != 130000 {
+		t.Fatalf("expected subtotal 1300.00, got %.2f", mockInv.updated.Subtotal.Float())
+	}
+}
+
+func TestCalculateTotals_TaxLines(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+
+	mockInv := &mockInvoiceRepo{
+		invoices: map[int64]*domain.Invoice{inv.ID: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{inv.ID: {
+			{ID: 1, InvoiceID: inv.ID, EntryID: 100, Hours: 10, Rate: 100, Amount: 100000},
+		}},
+	}
+
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{},
+		clientRepo:  &mockClientRepo{},
+		log:         discardLog,
+	}
+
+	taxes := []*domain.InvoiceTax{
+		{Label: "VAT", Rate: 0.20},
+		{Label: "City surcharge", Rate: 0.015},
+	}
+	if err := svc.CalculateTotals(ctx, inv.ID, taxes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved := mockInv.taxes[inv.ID]
+	if len(saved) != 2 || saved[0].Amount != 20000 || saved[1].Amount != 1500 {
+		t.Fatalf("unexpected tax lines: %+v", saved)
+	}
+	if got := mockInv.updated; got.TaxAmount != 21500 || got.Total != 121500 || math.Abs(got.TaxRate-0.215) > 1e-9 {
+		t.Fatalf("unexpected totals: rate %v tax %v total %v", got.TaxRate, got.TaxAmount, got.Total)
+	}
+
+	// Nil keeps the saved lines when totals are recalculated
+	mockInv.lineItems[inv.ID] = append(mockInv.lineItems[inv.ID],
+		&domain.InvoiceLineItem{ID: 2, InvoiceID: inv.ID, EntryID: 101, Hours: 1, Rate: 100, Amount: 10000})
+	if err := svc.CalculateTotals(ctx, inv.ID, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mockInv.updated; got.TaxAmount != 23650 || len(mockInv.taxes[inv.ID]) != 2 {
+		t.Fatalf("expected tax lines kept, got tax %v lines %d", got.TaxAmount, len(mockInv.taxes[inv.ID]))
+	}
+}
+
+func TestPreview_WritesOffHoursOverDailyCap(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.Local)
+	end := start.Add(10 * time.Hour)
+	entries := []*domain.TimeEntry{
+		{ID: 100, ClientID: 1, StartTime: start, EndTime: &end, HourlyRate: 50, IsBillable: true},
+	}
+
+	svc := &invoiceService{
+		invoiceRepo: &mockInvoiceRepo{},
+		entryRepo:   &mockEntryRepo{unbilled: entries},
+		clientRepo:  &mockClientRepo{billing: domain.BillingRules{DailyCapHours: 8}},
+		log:         discardLog,
+	}
+
+	inv, err := svc.Preview(ctx, 1, start, start.AddDate(0, 0, 7), "INV", nil, domain.InvoiceFooter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inv.LineItems) != 2 {
+		t.Fatalf("expected entry and write-off line items, got %d", len(inv.LineItems))
+	}
+	if writeOff := inv.LineItems[1]; !writeOff.IsAdjustment() || writeOff.Hours != -2 || writeOff.Amount != -10000 {
+		t.Fatalf("unexpected write-off: %+v", writeOff)
+	}
+	if inv.Subtotal != 40000 {
+		t.Fatalf("expected subtotal capped at 8h, got %.2f", inv.Subtotal.Float())
+	}
+}
+
+func TestReopen_FinalizedBecomesDraft(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+	inv.Finalize()
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{10: inv}}
+	mockEntries := &mockEntryRepo{}
+	svc := &invoiceService{invoiceRepo: mockInv, entryRepo: mockEntries, clientRepo: &mockClientRepo{}, log: discardLog}
+
+	if err := svc.Reopen(ctx, 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockEntries.unlockedForInv != 10 {
+		t.Fatalf("expected entries of invoice 10 to be unlocked, got %d", mockEntries.unlockedForInv)
+	}
+	if mockInv.updated == nil || mockInv.updated.Status != domain.InvoiceStatusDraft {
+		t.Fatalf("expected invoice to be saved as draft")
+	}
+}
+
+func TestReopen_BlockedAfterSent(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []domain.InvoiceStatus{
+		domain.InvoiceStatusDraft,
+		domain.InvoiceStatusSent,
+		domain.InvoiceStatusPaid,
+		domain.InvoiceStatusOverdue,
+	} {
+		inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+		inv.ID = 10
+		inv.Status = status
+
+		mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{10: inv}}
+		mockEntries := &mockEntryRepo{}
+		svc := &invoiceService{invoiceRepo: mockInv, entryRepo: mockEntries, clientRepo: &mockClientRepo{}, log: discardLog}
+
+		err := svc.Reopen(ctx, 10)
+		if !errors.Is(err, ErrInvoiceNotReopenable) {
+			t.Fatalf("%s: expected ErrInvoiceNotReopenable, got %v", status, err)
+		}
+		if mockEntries.unlockedForInv != 0 || mockInv.updated != nil {
+			t.Fatalf("%s: expected no changes", status)
+		}
+	}
+}
+
+func TestDelete_OnlyDrafts(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []domain.InvoiceStatus{
+		domain.InvoiceStatusDraft,
+		domain.InvoiceStatusFinalized,
+		domain.InvoiceStatusSent,
+		domain.InvoiceStatusPaid,
+		domain.InvoiceStatusOverdue,
+		domain.InvoiceStatusVoid,
+	} {
+		inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+		inv.ID = 10
+		inv.Status = status
+
+		mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{10: inv}}
+		svc := &invoiceService{invoiceRepo: mockInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, log: discardLog}
+
+		err := svc.Delete(ctx, 10)
+		if status == domain.InvoiceStatusDraft {
+			if err != nil || mockInv.voided != 10 {
+				t.Fatalf("expected the draft to be voided, got %v", err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvoiceNotDeletable) {
+			t.Fatalf("%s: expected ErrInvoiceNotDeletable, got %v", status, err)
+		}
+		if mockInv.voided != 0 {
+			t.Fatalf("%s: expected no changes", status)
+		}
+	}
+}
+
+type mockUnitOfWork struct {
+	repos repository.Repositories
+	calls int
+}
+
+func (m *mockUnitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	m.calls++
+	return fn(m.repos)
+}
+
+func TestSetFooter_OnlyOnDrafts(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{10: inv}}
+	svc := &invoiceService{invoiceRepo: mockInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, log: discardLog}
+
+	footer := domain.InvoiceFooter{Notes: "Thanks!", PaymentInstructions: "IBAN DE00 1234"}
+	if err := svc.SetFooter(ctx, 10, footer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockInv.updated == nil || mockInv.updated.Footer != footer {
+		t.Fatalf("expected footer to be saved")
+	}
+
+	inv.Finalize()
+	mockInv.updated = nil
+	if err := svc.SetFooter(ctx, 10, domain.InvoiceFooter{}); !errors.Is(err, ErrInvoiceNotEditable) {
+		t.Fatalf("expected ErrInvoiceNotEditable, got %v", err)
+	}
+	if mockInv.updated != nil {
+		t.Fatalf("expected finalized invoice to be unchanged")
+	}
+}
+
+func TestAddInterest_KeepsChargeThroughBillingRules(t *testing.T) {
+	ctx := context.Background()
+
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	late := &domain.Invoice{ID: 1, InvoiceNumber: "INV-2026-001", ClientID: 1, Total: 100000, Status: domain.InvoiceStatusSent}
+	rate, err := domain.ParseInterestRate("1%/month")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	charge := domain.NewInterestCharge(late, due, due.AddDate(0, 0, 73), rate)
+	if charge.Days != 73 || charge.Amount != 2400 {
+		t.Fatalf("expected 73 days and 24.00 interest, got %d days %.2f", charge.Days, charge.Amount.Float())
+	}
+
+	draft := domain.NewInvoice("INV-2026-002", 1, due, due.AddDate(0, 1, 0))
+	draft.ID = 2
+	mockInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{2: draft},
+		lineItems: map[int64][]*domain.InvoiceLineItem{},
+	}
+	svc := &invoiceService{
+		invoiceRepo: mockInv,
+		entryRepo:   &mockEntryRepo{},
+		clientRepo:  &mockClientRepo{billing: domain.BillingRules{MinIncrementMinutes: 60}},
+		log:         discardLog,
+	}
+
+	if err := svc.AddInterest(ctx, 2, charge); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items := mockInv.lineItems[2]
+	if len(items) != 1 || !items[0].IsInterest() || items[0].IsAdjustment() {
+		t.Fatalf("expected the interest line to survive billing rules, got %+v", items)
+	}
+	if mockInv.updated == nil || mockInv.updated.Total != 2400 {
+		t.Fatalf("expected totals to include the interest")
+	}
+
+	if err := svc.AddInterest(ctx, 2, charge); err == nil {
+		t.Fatalf("expected charging the same invoice twice to fail")
+	}
+	onTime := domain.NewInterestCharge(late, due, due, rate)
+	if err := svc.AddInterest(ctx, 2, onTime); !errors.Is(err, ErrNoInterestOwed) {
+		t.Fatalf("expected ErrNoInterestOwed, got %v", err)
+	}
+}
+
+func TestFinalize_RunsInUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+	txInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{10: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{10: {{ID: 1, InvoiceID: 10, EntryID: 100, Amount: 5000}}},
+	}
+	uow := &mockUnitOfWork{repos: repository.Repositories{
+		Invoices: txInv,
+		Entries:  &mockEntryRepo{},
+		Clients:  &mockClientRepo{},
+	}}
+
+	// The service's own repositories know nothing about the invoice, so the
+	// call only succeeds if it goes through the unit of work
+	outerInv := &mockInvoiceRepo{}
+	svc := &invoiceService{invoiceRepo: outerInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, uow: uow, log: discardLog}
+
+	if err := svc.Finalize(ctx, 10, 30); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if uow.calls != 1 {
+		t.Fatalf("expected one transaction, got %d", uow.calls)
+	}
+	if outerInv.updated != nil {
+		t.Fatalf("expected no writes outside the transaction")
+	}
+	if txInv.updated == nil || txInv.updated.Status != domain.InvoiceStatusFinalized {
+		t.Fatalf("expected invoice to be finalized inside the transaction")
+	}
+}
+
+func TestFinalize_StoresDefaultDueDate(t *testing.T) {
+	ctx := context.Background()
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().Add(-24*time.Hour), time.Now())
+	inv.ID = 10
+	mockInv := &mockInvoiceRepo{
+		invoices:  map[int64]*domain.Invoice{10: inv},
+		lineItems: map[int64][]*domain.InvoiceLineItem{10: {{ID: 1, InvoiceID: 10, EntryID: 100, Amount: 5000}}},
+	}
+	svc := &invoiceService{invoiceRepo: mockInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, log: discardLog}
+
+	if err := svc.Finalize(ctx, 10, 14); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := inv.CreatedAt.AddDate(0, 0, 14)
+	if mockInv.updated.DueDate == nil || !mockInv.updated.DueDate.Equal(want) {
+		t.Fatalf("expected due date %v, got %v", want, mockInv.updated.DueDate)
+	}
+}
+
+func TestSetDueDate_RecordsChangesOnceSent(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().AddDate(0, 0, -5)
+	ahead := time.Now().AddDate(0, 0, 10)
+
+	inv := domain.NewInvoice("INV-2026-001", 1, time.Now().AddDate(0, -1, 0), time.Now())
+	inv.ID = 10
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{10: inv}}
+	svc := &invoiceService{invoiceRepo: mockInv, entryRepo: &mockEntryRepo{}, clientRepo: &mockClientRepo{}, log: discardLog}
+
+	// Drafts change freely
+	if err := svc.SetDueDate(ctx, 10, past, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockInv.dueDates) != 0 {
+		t.Fatalf("expected draft changes to go unrecorded")
+	}
+
+	inv.Status = domain.InvoiceStatusOverdue
+	if err := svc.SetDueDate(ctx, 10, ahead, " "); !errors.Is(err, ErrDueDateNoteRequired) {
+		t.Fatalf("expected ErrDueDateNoteRequired, got %v", err)
+	}
+	if err := svc.SetDueDate(ctx, 10, ahead, "Agreed extension"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockInv.dueDates) != 1 {
+		t.Fatalf("expected one recorded change, got %d", len(mockInv.dueDates))
+	}
+	change := mockInv.dueDates[0]
+	if change.From == nil || !change.From.Equal(past) || !change.To.Equal(ahead) || change.Note != "Agreed extension" {
+		t.Fatalf("unexpected change recorded: %+v", change)
+	}
+	if inv.Status != domain.InvoiceStatusSent {
+		t.Fatalf("expected overdue invoice with a later due date to be sent again, got %s", inv.Status)
+	}
+
+	inv.Status = domain.InvoiceStatusPaid
+	if err := svc.SetDueDate(ctx, 10, ahead, "Too late"); !errors.Is(err, ErrDueDateLocked) {
+		t.Fatalf("expected ErrDueDateLocked, got %v", err)
+	}
+}
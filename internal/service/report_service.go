@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/domain"
@@ -10,11 +14,28 @@ import (
 
 // WeekSummary provides weekly time tracking analytics
 type WeekSummary struct {
-	TotalHours    float64
-	BillableHours float64
-	TotalValue    float64
-	ByClient      map[int64]float64 // Hours by client ID
-	ByDay         map[time.Weekday]float64
+	TotalHours       float64
+	BillableHours    float64
+	TotalValue       domain.Money
+	ByClient         map[int64]float64      // Hours by client ID
+	BillableByClient map[int64]float64      // Billable hours by client ID
+	ValueByClient    map[int64]domain.Money // Billable value by client ID
+	ByDay            map[time.Weekday]float64
+	DaysOff          map[time.Weekday]domain.TimeOffType
+
+	// Time logged on days off, which utilization leaves out
+	OffHours         float64
+	OffBillableHours float64
+}
+
+// Utilization returns the share of hours worked on working days that were
+// billable, as a percentage, or 0 if none were worked
+func (w *WeekSummary) Utilization() float64 {
+	hours := w.TotalHours - w.OffHours
+	if hours <= 0 {
+		return 0
+	}
+	return (w.BillableHours - w.OffBillableHours) / hours * 100
 }
 
 // ClientSummary provides client-specific time and revenue analytics
@@ -22,8 +43,8 @@ type ClientSummary struct {
 	ClientID      int64
 	TotalHours    float64
 	BillableHours float64
-	TotalValue    float64
-	UnbilledValue float64
+	TotalValue    domain.Money
+	UnbilledValue domain.Money
 	Entries       []*domain.TimeEntry
 }
 
@@ -32,10 +53,223 @@ type DailySummary struct {
 	Date          time.Time
 	TotalHours    float64
 	BillableHours float64
-	TotalValue    float64
+	TotalValue    domain.Money
 	Entries       []*domain.TimeEntry
 }
 
+// HoursHeatmap is the time worked in each hour of the day on each day of the
+// week, summed over a date range
+type HoursHeatmap struct {
+	Start time.Time
+	End   time.Time
+	Hours [7][24]float64 // indexed by time.Weekday, then hour of day
+	Total float64
+	Max   float64 // the busiest single cell
+}
+
+// Level grades a cell from 0 (no time) to 4 (as busy as the busiest cell)
+func (h *HoursHeatmap) Level(day time.Weekday, hour int) int {
+	hours := h.Hours[day][hour]
+	if hours <= 0 || h.Max <= 0 {
+		return 0
+	}
+	return int(math.Ceil(hours / h.Max * 4))
+}
+
+// Busiest returns the day and hour with the most time
+func (h *HoursHeatmap) Busiest() (time.Weekday, int) {
+	var day time.Weekday
+	var hour int
+	for d := range h.Hours {
+		for hr, hours := range h.Hours[d] {
+			if hours > h.Hours[day][hour] {
+				day, hour = time.Weekday(d), hr
+			}
+		}
+	}
+	return day, hour
+}
+
+// ShortDay is a weekday that ended with fewer hours logged than the daily
+// target
+type ShortDay struct {
+	Date  time.Time
+	Hours float64
+}
+
+// ReviewDay is a day of the weekly review, with the entries started that
+// day, earliest first
+type ReviewDay struct {
+	Date    time.Time
+	Entries []*domain.TimeEntry
+	Hours   float64
+	Short   bool // a weekday below the daily target that nothing explains
+}
+
+// Undescribed returns the day's entries without a description
+func (d *ReviewDay) Undescribed() []*domain.TimeEntry {
+	var undescribed []*domain.TimeEntry
+	for _, e := range d.Entries {
+		if strings.TrimSpace(e.Description) == "" {
+			undescribed = append(undescribed, e)
+		}
+	}
+	return undescribed
+}
+
+// NeedsReview reports whether the day is short or has entries to describe
+func (d *ReviewDay) NeedsReview() bool {
+	return d.Short || len(d.Undescribed()) > 0
+}
+
+// LintIssue is a data-quality problem found on a time entry
+type LintIssue string
+
+const (
+	LintNoDescription LintIssue = "no description"
+	LintZeroDuration  LintIssue = "zero duration"
+	LintLongDuration  LintIssue = "over 12h"
+	LintZeroRate      LintIssue = "billable at $0"
+)
+
+// LintLongHours is the length past which an entry is flagged, usually a
+// timer left running overnight
+const LintLongHours = 12
+
+// EntryLint is an entry with the data-quality issues found on it
+type EntryLint struct {
+	Entry  *domain.TimeEntry
+	Issues []LintIssue
+}
+
+// Has reports whether issue was found on the entry
+func (l *EntryLint) Has(issue LintIssue) bool {
+	for _, i := range l.Issues {
+		if i == issue {
+			return true
+		}
+	}
+	return false
+}
+
+// LintEntries returns the entries with data-quality issues, in the order
+// given. Running entries are left out.
+func LintEntries(entries []*domain.TimeEntry) []*EntryLint {
+	var lints []*EntryLint
+	for _, e := range entries {
+		if e.IsRunning() {
+			continue
+		}
+		var issues []LintIssue
+		if strings.TrimSpace(e.Description) == "" {
+			issues = append(issues, LintNoDescription)
+		}
+		if e.Duration() <= 0 {
+			issues = append(issues, LintZeroDuration)
+		} else if e.Duration().Hours() > LintLongHours {
+			issues = append(issues, LintLongDuration)
+		}
+		if e.IsBillable && e.HourlyRate <= 0 {
+			issues = append(issues, LintZeroRate)
+		}
+		if len(issues) > 0 {
+			lints = append(lints, &EntryLint{Entry: e, Issues: issues})
+		}
+	}
+	return lints
+}
+
+// CapacityWeek compares a coming week's billable target with the work
+// clients are expected to need
+type CapacityWeek struct {
+	Start    time.Time // Monday
+	DaysOff  int       // weekdays of time off
+	Capacity float64   // target hours, less the days off
+	Demand   float64   // hours active clients expect
+}
+
+// Balance returns the hours left unbooked, negative when overbooked
+func (w *CapacityWeek) Balance() float64 {
+	return w.Capacity - w.Demand
+}
+
+// RevenueBasis selects when an invoice counts as revenue
+type RevenueBasis string
+
+const (
+	// RevenueCash counts paid invoices in the month they were paid
+	RevenueCash RevenueBasis = "cash"
+	// RevenueAccrual counts every finalized invoice in the month its billing
+	// period ended, whether or not it has been paid
+	RevenueAccrual RevenueBasis = "accrual"
+)
+
+// ParseRevenueBasis looks up a revenue basis by name
+func ParseRevenueBasis(s string) (RevenueBasis, error) {
+	switch basis := RevenueBasis(s); basis {
+	case RevenueCash, RevenueAccrual:
+		return basis, nil
+	}
+	return "", fmt.Errorf("unknown revenue basis %q (expected cash or accrual)", s)
+}
+
+// AgingBuckets labels the days-past-due ranges of an aging report
+var AgingBuckets = [4]string{"0-30", "31-60", "61-90", "90+"}
+
+// AgingRow is one client's receivables by days past due
+type AgingRow struct {
+	ClientID int64
+	Buckets  [4]domain.Money // indexed like AgingBuckets
+	Total    domain.Money
+}
+
+// AgingReport groups unpaid sent and overdue invoices by how many days past
+// due they are. Invoices not yet due count in the first bucket.
+type AgingReport struct {
+	AsOf    time.Time
+	Clients []*AgingRow // Largest total first
+	Buckets [4]domain.Money
+	Total   domain.Money
+}
+
+// BillingReminder is a client whose billable time not yet invoiced is worth
+// billing now: it adds up to the reminder threshold, or the client was last
+// invoiced too long ago
+type BillingReminder struct {
+	ClientID     int64
+	Unbilled     domain.Money
+	Oldest       time.Time  // start of the earliest unbilled entry
+	LastInvoiced *time.Time // when the latest finalized invoice was issued; nil if never
+	Days         int        // days since LastInvoiced, or since Oldest if never invoiced
+	OverAmount   bool       // Unbilled reached the threshold
+	OverDays     bool       // Days reached the limit
+}
+
+// ClientRevenue is one client's revenue over a range
+type ClientRevenue struct {
+	ClientID int64
+	Revenue  domain.Money
+	Share    float64 // fraction of the range's total, 0 to 1
+}
+
+// RevenueByClient ranks clients by their revenue over a range
+type RevenueByClient struct {
+	Start   time.Time
+	End     time.Time // exclusive
+	Basis   RevenueBasis
+	Clients []*ClientRevenue // Largest revenue first
+	Total   domain.Money
+}
+
+// Concentrated returns the largest client if their share of revenue is over
+// limit, or nil if not or limit is 0
+func (r *RevenueByClient) Concentrated(limit float64) *ClientRevenue {
+	if limit <= 0 || len(r.Clients) == 0 || r.Clients[0].Share <= limit {
+		return nil
+	}
+	return r.Clients[0]
+}
+
 // ReportService provides aggregations and analytics
 type ReportService interface {
 	// Time tracking summaries
@@ -43,25 +277,68 @@ type ReportService interface {
 	GetClientSummary(ctx context.Context, clientID int64, start, end time.Time) (*ClientSummary, error)
 	GetDailySummary(ctx context.Context, date time.Time) (*DailySummary, error)
 
+	// GetHoursHeatmap spreads the time worked between start and end over the
+	// hours of the day and days of the week it fell in, in start's time zone
+	GetHoursHeatmap(ctx context.Context, start, end time.Time) (*HoursHeatmap, error)
+
+	// GetShortDays returns the weekdays from start up to end with fewer than
+	// target hours logged, unless the day is time off or an entry that day
+	// mentions one of dayOffWords to explain it. A target of 0 finds none.
+	GetShortDays(ctx context.Context, start, end time.Time, target float64, dayOffWords []string) ([]ShortDay, error)
+
+	// GetWeekReview returns the seven days from the Monday on or before
+	// weekStart with their entries, marking the days GetShortDays finds
+	// short, for reviewing a week before invoicing it
+	GetWeekReview(ctx context.Context, weekStart time.Time, target float64, dayOffWords []string) ([]*ReviewDay, error)
+
+	// GetCapacity plans weeks weeks from the Monday on or before weekStart,
+	// comparing target billable hours a week, spread over the weekdays, with
+	// the weekly demand of the clients that are not archived
+	GetCapacity(ctx context.Context, weekStart time.Time, weeks int, target float64, clients []*domain.Client) ([]*CapacityWeek, error)
+
 	// Financial summaries
-	GetOutstandingTotal(ctx context.Context) (float64, error) // Unpaid invoices
-	GetUnbilledTotal(ctx context.Context) (float64, error)    // Time not yet invoiced
-	GetRevenueByMonth(ctx context.Context, year int) (map[time.Month]float64, error)
+	GetOutstandingTotal(ctx context.Context) (domain.Money, error) // Unpaid invoices
+	GetUnbilledTotal(ctx context.Context) (domain.Money, error)    // Time not yet invoiced
+	GetRevenueByMonth(ctx context.Context, year int, basis RevenueBasis) (map[time.Month]domain.Money, error)
+
+	// GetRevenueByClient totals each client's revenue from start up to end,
+	// counted on basis, with their share of the whole
+	GetRevenueByClient(ctx context.Context, start, end time.Time, basis RevenueBasis) (*RevenueByClient, error)
+
+	// GetAging buckets receivables by days past due on asOf. Invoices
+	// without a due date are due defaultDueDays after they were created.
+	GetAging(ctx context.Context, asOf time.Time, defaultDueDays int) (*AgingReport, error)
+
+	// GetBillingReminders returns the clients with unbilled time of at least
+	// minUnbilled, or not invoiced for at least maxDays on asOf, largest
+	// amount first. A zero minUnbilled or maxDays turns that check off.
+	GetBillingReminders(ctx context.Context, asOf time.Time, minUnbilled domain.Money, maxDays int) ([]*BillingReminder, error)
+
+	// GetStatement builds client's statement for the month containing month
+	GetStatement(ctx context.Context, client *domain.Client, month time.Time) (*domain.Statement, error)
+
+	// GetDigest summarizes the week starting on the Monday on or before
+	// weekStart, naming clients from clients. Invoices without a due date
+	// are due defaultDueDays after they were created.
+	GetDigest(ctx context.Context, weekStart time.Time, clients map[int64]*domain.Client, defaultDueDays int) (*domain.Digest, error)
 }
 
 type reportService struct {
 	entryRepo   repository.TimeEntryRepository
 	invoiceRepo repository.InvoiceRepository
+	timeOffRepo repository.TimeOffRepository
 }
 
 // NewReportService creates a new report service
 func NewReportService(
 	entryRepo repository.TimeEntryRepository,
 	invoiceRepo repository.InvoiceRepository,
+	timeOffRepo repository.TimeOffRepository,
 ) ReportService {
 	return &reportService{
 		entryRepo:   entryRepo,
 		invoiceRepo: invoiceRepo,
+		timeOffRepo: timeOffRepo,
 	}
 }
 
@@ -80,9 +357,22 @@ func (s *reportService) GetWeekSummary(ctx context.Context, weekStart time.Time)
 		return nil, err
 	}
 
+	timeOff, err := s.timeOffRepo.List(ctx, &weekStart, &weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
 	summary := &WeekSummary{
-		ByClient: make(map[int64]float64),
-		ByDay:    make(map[time.Weekday]float64),
+		ByClient:         make(map[int64]float64),
+		BillableByClient: make(map[int64]float64),
+		ValueByClient:    make(map[int64]domain.Money),
+		ByDay:            make(map[time.Weekday]float64),
+		DaysOff:          make(map[time.Weekday]domain.TimeOffType),
+	}
+	for d := weekStart; d.Before(weekEnd); d = d.AddDate(0, 0, 1) {
+		if off := domain.TimeOffOn(timeOff, d); off != nil {
+			summary.DaysOff[d.Weekday()] = off.Type
+		}
 	}
 
 	for _, entry := range entries {
@@ -97,10 +387,20 @@ func (s *reportService) GetWeekSummary(ctx context.Context, weekStart time.Time)
 
 		// Aggregate by client
 		summary.ByClient[entry.ClientID] += hours
+		if entry.IsBillable {
+			summary.BillableByClient[entry.ClientID] += hours
+		}
+		summary.ValueByClient[entry.ClientID] += value
 
 		// Aggregate by day of week
 		weekday := entry.StartTime.Weekday()
 		summary.ByDay[weekday] += hours
+		if _, off := summary.DaysOff[weekday]; off {
+			summary.OffHours += hours
+			if entry.IsBillable {
+				summary.OffBillableHours += hours
+			}
+		}
 	}
 
 	return summary, nil
@@ -171,7 +471,197 @@ func (s *reportService) GetDailySummary(ctx context.Context, date time.Time) (*D
 	return summary, nil
 }
 
-func (s *reportService) GetOutstandingTotal(ctx context.Context) (float64, error) {
+func (s *reportService) GetHoursHeatmap(ctx context.Context, start, end time.Time) (*HoursHeatmap, error) {
+	// Entries are listed by start time, so reach back a day for ones that
+	// started before the range and ran into it
+	from := start.AddDate(0, 0, -1)
+	entries, err := s.entryRepo.List(ctx, nil, &from, &end, true)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &HoursHeatmap{Start: start, End: end}
+	loc := start.Location()
+	now := time.Now()
+
+	for _, entry := range entries {
+		t := entry.StartTime.In(loc)
+		stop := now.In(loc)
+		if entry.EndTime != nil {
+			stop = entry.EndTime.In(loc)
+		}
+		if t.Before(start) {
+			t = start
+		}
+		if stop.After(end) {
+			stop = end
+		}
+
+		// Walk the entry an hour of the clock at a time
+		for t.Before(stop) {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if next.After(stop) {
+				next = stop
+			}
+			hours := next.Sub(t).Hours()
+			heatmap.Hours[t.Weekday()][t.Hour()] += hours
+			heatmap.Total += hours
+			t = next
+		}
+	}
+
+	for _, day := range heatmap.Hours {
+		for _, hours := range day {
+			if hours > heatmap.Max {
+				heatmap.Max = hours
+			}
+		}
+	}
+
+	return heatmap, nil
+}
+
+func (s *reportService) GetShortDays(
+	ctx context.Context,
+	start, end time.Time,
+	target float64,
+	dayOffWords []string,
+) ([]ShortDay, error) {
+	if target <= 0 {
+		return nil, nil
+	}
+
+	entries, err := s.entryRepo.List(ctx, nil, &start, &end, true)
+	if err != nil {
+		return nil, err
+	}
+	timeOff, err := s.timeOffRepo.List(ctx, &start, &end)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := start.Location()
+	hours := make(map[string]float64)
+	explained := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.StartTime.Before(start) || !entry.StartTime.Before(end) {
+			continue
+		}
+		day := entry.StartTime.In(loc).Format(time.DateOnly)
+		hours[day] += entry.Duration().Hours()
+		if mentionsAny(entry.Description+"\n"+entry.Notes, dayOffWords) {
+			explained[day] = true
+		}
+	}
+
+	var short []ShortDay
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday || domain.TimeOffOn(timeOff, d) != nil {
+			continue
+		}
+		day := d.Format(time.DateOnly)
+		if hours[day] < target && !explained[day] {
+			short = append(short, ShortDay{Date: d, Hours: hours[day]})
+		}
+	}
+	return short, nil
+}
+
+func (s *reportService) GetWeekReview(
+	ctx context.Context,
+	weekStart time.Time,
+	target float64,
+	dayOffWords []string,
+) ([]*ReviewDay, error) {
+	start := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+	for start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, -1)
+	}
+	end := start.AddDate(0, 0, 7)
+
+	entries, err := s.entryRepo.List(ctx, nil, &start, &end, true)
+	if err != nil {
+		return nil, err
+	}
+	short, err := s.GetShortDays(ctx, start, end, target, dayOffWords)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]*ReviewDay, 7)
+	byDate := make(map[string]*ReviewDay, len(days))
+	for i := range days {
+		days[i] = &ReviewDay{Date: start.AddDate(0, 0, i)}
+		byDate[days[i].Date.Format(time.DateOnly)] = days[i]
+	}
+	for _, entry := range entries {
+		day, ok := byDate[entry.StartTime.In(start.Location()).Format(time.DateOnly)]
+		if !ok {
+			continue
+		}
+		day.Entries = append(day.Entries, entry)
+		day.Hours += entry.Duration().Hours()
+	}
+	for _, day := range days {
+		sort.Slice(day.Entries, func(i, j int) bool { return day.Entries[i].StartTime.Before(day.Entries[j].StartTime) })
+	}
+	for _, sd := range short {
+		if day, ok := byDate[sd.Date.Format(time.DateOnly)]; ok {
+			day.Short = true
+		}
+	}
+	return days, nil
+}
+
+func (s *reportService) GetCapacity(
+	ctx context.Context,
+	weekStart time.Time,
+	weeks int,
+	target float64,
+	clients []*domain.Client,
+) ([]*CapacityWeek, error) {
+	for weekStart.Weekday() != time.Monday {
+		weekStart = weekStart.AddDate(0, 0, -1)
+	}
+	end := weekStart.AddDate(0, 0, 7*weeks)
+	timeOff, err := s.timeOffRepo.List(ctx, &weekStart, &end)
+	if err != nil {
+		return nil, err
+	}
+
+	var demand float64
+	for _, c := range clients {
+		if !c.IsArchived {
+			demand += c.WeeklyDemand
+		}
+	}
+
+	plan := make([]*CapacityWeek, 0, weeks)
+	for i := 0; i < weeks; i++ {
+		week := &CapacityWeek{Start: weekStart.AddDate(0, 0, 7*i), Demand: demand}
+		for d := 0; d < 5; d++ {
+			if domain.TimeOffOn(timeOff, week.Start.AddDate(0, 0, d)) != nil {
+				week.DaysOff++
+			}
+		}
+		week.Capacity = target * float64(5-week.DaysOff) / 5
+		plan = append(plan, week)
+	}
+	return plan, nil
+}
+
+// mentionsAny reports whether text contains one of words, ignoring case
+func mentionsAny(text string, words []string) bool {
+	text = strings.ToLower(text)
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" && strings.Contains(text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *reportService) GetOutstandingTotal(ctx context.Context) (domain.Money, error) {
 	// Get invoices with status sent or overdue
 	sentStatus := domain.InvoiceStatusSent
 	overdueStatus := domain.InvoiceStatusOverdue
@@ -186,7 +676,7 @@ func (s *reportService) GetOutstandingTotal(ctx context.Context) (float64, error
 		return 0, err
 	}
 
-	total := 0.0
+	var total domain.Money
 	for _, invoice := range sentInvoices {
 		total += invoice.Total
 	}
@@ -197,14 +687,14 @@ func (s *reportService) GetOutstandingTotal(ctx context.Context) (float64, error
 	return total, nil
 }
 
-func (s *reportService) GetUnbilledTotal(ctx context.Context) (float64, error) {
+func (s *reportService) GetUnbilledTotal(ctx context.Context) (domain.Money, error) {
 	// Get all unbilled entries (no invoice_id)
 	entries, err := s.entryRepo.List(ctx, nil, nil, nil, false)
 	if err != nil {
 		return 0, err
 	}
 
-	total := 0.0
+	var total domain.Money
 	for _, entry := range entries {
 		if entry.InvoiceID == nil && entry.IsBillable {
 			total += entry.Amount()
@@ -214,7 +704,11 @@ func (s *reportService) GetUnbilledTotal(ctx context.Context) (float64, error) {
 	return total, nil
 }
 
-func (s *reportService) GetRevenueByMonth(ctx context.Context, year int) (map[time.Month]float64, error) {
+func (s *reportService) GetRevenueByMonth(ctx context.Context, year int, basis RevenueBasis) (map[time.Month]domain.Money, error) {
+	if basis == RevenueAccrual {
+		return s.getAccrualRevenueByMonth(ctx, year)
+	}
+
 	// Get all paid invoices for the year
 	paidStatus := domain.InvoiceStatusPaid
 	invoices, err := s.invoiceRepo.List(ctx, nil, &paidStatus)
@@ -222,28 +716,367 @@ func (s *reportService) GetRevenueByMonth(ctx context.Context, year int) (map[ti
 		return nil, err
 	}
 
-	revenue := make(map[time.Month]float64)
+	revenue := emptyMonths()
+
+	for _, invoice := range invoices {
+		// Only include invoices paid in the requested year
+		if paymentDate := paidOn(invoice); paymentDate.Year() == year {
+			month := paymentDate.Month()
+			revenue[month] += invoice.Total
+		}
+	}
+
+	return revenue, nil
+}
+
+// paidOn returns when a paid invoice was paid: its paid date if available,
+// otherwise when it was last updated
+func paidOn(invoice *domain.Invoice) time.Time {
+	if invoice.PaidDate != nil {
+		return *invoice.PaidDate
+	}
+	return invoice.UpdatedAt
+}
+
+// getAccrualRevenueByMonth counts every invoice past draft in the month its
+// billing period ended, which is when the work was earned. Invoices don't
+// record when they were finalized, so the period end stands in for it.
+func (s *reportService) getAccrualRevenueByMonth(ctx context.Context, year int) (map[time.Month]domain.Money, error) {
+	invoices, err := s.invoiceRepo.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	revenue := emptyMonths()
+
+	for _, invoice := range invoices {
+		if invoice.Status == domain.InvoiceStatusDraft {
+			continue
+		}
+		if invoice.PeriodEnd.Year() == year {
+			revenue[invoice.PeriodEnd.Month()] += invoice.Total
+		}
+	}
+
+	return revenue, nil
+}
+
+func (s *reportService) GetRevenueByClient(ctx context.Context, start, end time.Time, basis RevenueBasis) (*RevenueByClient, error) {
+	invoices, err := s.invoiceRepo.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RevenueByClient{Start: start, End: end, Basis: basis}
+	byClient := make(map[int64]*ClientRevenue)
+	for _, invoice := range invoices {
+		// Cash counts payments; accrual counts everything issued, on the
+		// same dates as GetRevenueByMonth
+		var on time.Time
+		switch {
+		case basis == RevenueCash && invoice.Status == domain.InvoiceStatusPaid:
+			on = paidOn(invoice)
+		case basis == RevenueAccrual && invoice.Status != domain.InvoiceStatusDraft && invoice.Status != domain.InvoiceStatusVoid:
+			on = invoice.PeriodEnd
+		default:
+			continue
+		}
+		if on.Before(start) || !on.Before(end) {
+			continue
+		}
 
-	// Initialize all months to 0
+		row, ok := byClient[invoice.ClientID]
+		if !ok {
+			row = &ClientRevenue{ClientID: invoice.ClientID}
+			byClient[invoice.ClientID] = row
+			report.Clients = append(report.Clients, row)
+		}
+		row.Revenue += invoice.Total
+		report.Total += invoice.Total
+	}
+
+	for _, row := range report.Clients {
+		if report.Total > 0 {
+			row.Share = float64(row.Revenue) / float64(report.Total)
+		}
+	}
+	sort.Slice(report.Clients, func(i, j int) bool {
+		a, b := report.Clients[i], report.Clients[j]
+		if a.Revenue != b.Revenue {
+			return a.Revenue > b.Revenue
+		}
+		return a.ClientID < b.ClientID
+	})
+
+	return report, nil
+}
+
+// emptyMonths returns a revenue map with every month set to 0
+func emptyMonths() map[time.Month]domain.Money {
+	revenue := make(map[time.Month]domain.Money)
 	for m := time.January; m <= time.December; m++ {
 		revenue[m] = 0
 	}
+	return revenue
+}
+
+func (s *reportService) GetAging(ctx context.Context, asOf time.Time, defaultDueDays int) (*AgingReport, error) {
+	report := &AgingReport{AsOf: asOf}
+	byClient := make(map[int64]*AgingRow)
+
+	for _, status := range []domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusOverdue} {
+		invoices, err := s.invoiceRepo.List(ctx, nil, &status)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, invoice := range invoices {
+			row := byClient[invoice.ClientID]
+			if row == nil {
+				row = &AgingRow{ClientID: invoice.ClientID}
+				byClient[invoice.ClientID] = row
+				report.Clients = append(report.Clients, row)
+			}
+
+			bucket := agingBucket(invoice.DaysPastDue(defaultDueDays, asOf))
+			row.Buckets[bucket] += invoice.Total
+			row.Total += invoice.Total
+			report.Buckets[bucket] += invoice.Total
+			report.Total += invoice.Total
+		}
+	}
+
+	sort.Slice(report.Clients, func(i, j int) bool {
+		if report.Clients[i].Total != report.Clients[j].Total {
+			return report.Clients[i].Total > report.Clients[j].Total
+		}
+		return report.Clients[i].ClientID < report.Clients[j].ClientID
+	})
+	return report, nil
+}
+
+// agingBucket returns the AgingBuckets index for a number of days past due
+func agingBucket(days int) int {
+	switch {
+	case days <= 30:
+		return 0
+	case days <= 60:
+		return 1
+	case days <= 90:
+		return 2
+	default:
+		return 3
+	}
+}
 
+func (s *reportService) GetBillingReminders(
+	ctx context.Context,
+	asOf time.Time,
+	minUnbilled domain.Money,
+	maxDays int,
+) ([]*BillingReminder, error) {
+	if minUnbilled <= 0 && maxDays <= 0 {
+		return nil, nil
+	}
+
+	entries, err := s.entryRepo.List(ctx, nil, nil, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	byClient := make(map[int64]*BillingReminder)
+	for _, entry := range entries {
+		if entry.InvoiceID != nil || !entry.IsBillable {
+			continue
+		}
+		r := byClient[entry.ClientID]
+		if r == nil {
+			r = &BillingReminder{ClientID: entry.ClientID, Oldest: entry.StartTime}
+			byClient[entry.ClientID] = r
+		}
+		r.Unbilled += entry.Amount()
+		if entry.StartTime.Before(r.Oldest) {
+			r.Oldest = entry.StartTime
+		}
+	}
+
+	invoices, err := s.invoiceRepo.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
 	for _, invoice := range invoices {
-		// Use paid date if available, otherwise use updated date
-		var paymentDate time.Time
-		if invoice.PaidDate != nil {
-			paymentDate = *invoice.PaidDate
-		} else {
-			paymentDate = invoice.UpdatedAt
+		r := byClient[invoice.ClientID]
+		if r == nil || !invoice.IsFinalized() {
+			continue
+		}
+		if r.LastInvoiced == nil || invoice.CreatedAt.After(*r.LastInvoiced) {
+			issued := invoice.CreatedAt
+			r.LastInvoiced = &issued
 		}
+	}
 
-		// Only include invoices paid in the requested year
-		if paymentDate.Year() == year {
-			month := paymentDate.Month()
-			revenue[month] += invoice.Total
+	var reminders []*BillingReminder
+	for _, r := range byClient {
+		if r.Unbilled <= 0 {
+			continue
+		}
+		since := r.Oldest
+		if r.LastInvoiced != nil {
+			since = *r.LastInvoiced
+		}
+		r.Days = max(int(asOf.Sub(since).Hours()/24), 0)
+		r.OverAmount = minUnbilled > 0 && r.Unbilled >= minUnbilled
+		r.OverDays = maxDays > 0 && r.Days >= maxDays
+		if r.OverAmount || r.OverDays {
+			reminders = append(reminders, r)
 		}
 	}
+	sort.Slice(reminders, func(i, j int) bool {
+		if reminders[i].Unbilled != reminders[j].Unbilled {
+			return reminders[i].Unbilled > reminders[j].Unbilled
+		}
+		return reminders[i].ClientID < reminders[j].ClientID
+	})
+	return reminders, nil
+}
 
-	return revenue, nil
+// GetStatement counts an invoice as issued when it was created, the date
+// printed on it, and as paid on its paid date. Drafts have not been issued,
+// so they are left out.
+func (s *reportService) GetStatement(ctx context.Context, client *domain.Client, month time.Time) (*domain.Statement, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	st := &domain.Statement{Client: client, Start: start, End: start.AddDate(0, 1, 0)}
+	inMonth := func(t time.Time) bool { return !t.Before(st.Start) && t.Before(st.End) }
+
+	invoices, err := s.invoiceRepo.List(ctx, &client.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, invoice := range invoices {
+		if !invoice.IsFinalized() {
+			continue
+		}
+		paid := invoice.Status == domain.InvoiceStatusPaid
+		paidDate := paidOn(invoice)
+
+		if invoice.CreatedAt.Before(st.Start) && !(paid && paidDate.Before(st.Start)) {
+			st.OpeningBalance += invoice.Total
+		}
+		if inMonth(invoice.CreatedAt) {
+			st.Lines = append(st.Lines, &domain.StatementLine{Date: invoice.CreatedAt, Invoice: invoice, Amount: invoice.Total})
+			st.Invoiced += invoice.Total
+		}
+		if paid && inMonth(paidDate) {
+			st.Lines = append(st.Lines, &domain.StatementLine{Date: paidDate, Invoice: invoice, Payment: true, Amount: -invoice.Total})
+			st.Received += invoice.Total
+		}
+	}
+
+	// By day, with the day's invoices before its payments: paid dates have
+	// no time of day, so an invoice paid the day it was issued would
+	// otherwise come after its payment
+	day := func(t time.Time) string { return t.In(start.Location()).Format("2006-01-02") }
+	sort.SliceStable(st.Lines, func(i, j int) bool {
+		a, b := st.Lines[i], st.Lines[j]
+		if day(a.Date) != day(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		if a.Payment != b.Payment {
+			return !a.Payment
+		}
+		return a.Date.Before(b.Date)
+	})
+
+	balance := st.OpeningBalance
+	for _, line := range st.Lines {
+		balance += line.Amount
+		line.Balance = balance
+	}
+	st.ClosingBalance = balance
+	return st, nil
+}
+
+func (s *reportService) GetDigest(
+	ctx context.Context,
+	weekStart time.Time,
+	clients map[int64]*domain.Client,
+	defaultDueDays int,
+) (*domain.Digest, error) {
+	start := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+	for start.Weekday() != time.Monday {
+		start = start.AddDate(0, 0, -1)
+	}
+	digest := &domain.Digest{Start: start, End: start.AddDate(0, 0, 7)}
+
+	week, err := s.GetWeekSummary(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	prior, err := s.GetWeekSummary(ctx, start.AddDate(0, 0, -7))
+	if err != nil {
+		return nil, err
+	}
+	digest.Hours, digest.PriorHours = week.TotalHours, prior.TotalHours
+
+	// A client missing from clients is still listed, by ID
+	client := func(id int64) *domain.Client {
+		if c, ok := clients[id]; ok {
+			return c
+		}
+		return &domain.Client{ID: id, Name: fmt.Sprintf("Client #%d", id)}
+	}
+
+	byClient := make(map[int64]*domain.DigestClient)
+	row := func(id int64) *domain.DigestClient {
+		if byClient[id] == nil {
+			byClient[id] = &domain.DigestClient{Client: client(id)}
+			digest.Clients = append(digest.Clients, byClient[id])
+		}
+		return byClient[id]
+	}
+	for id, hours := range week.ByClient {
+		row(id).Hours = hours
+	}
+	for id, hours := range prior.ByClient {
+		row(id).PriorHours = hours
+	}
+	sort.Slice(digest.Clients, func(i, j int) bool {
+		a, b := digest.Clients[i], digest.Clients[j]
+		if a.Hours != b.Hours {
+			return a.Hours > b.Hours
+		}
+		if a.PriorHours != b.PriorHours {
+			return a.PriorHours > b.PriorHours
+		}
+		return a.Client.Name < b.Client.Name
+	})
+
+	if digest.Unbilled, err = s.GetUnbilledTotal(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, status := range []domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusOverdue} {
+		invoices, err := s.invoiceRepo.List(ctx, nil, &status)
+		if err != nil {
+			return nil, err
+		}
+		for _, invoice := range invoices {
+			invoice.Client = client(invoice.ClientID)
+			digest.AwaitingPayment = append(digest.AwaitingPayment, &domain.DigestInvoice{
+				Invoice:     invoice,
+				Due:         invoice.DueOn(defaultDueDays),
+				DaysPastDue: invoice.DaysPastDue(defaultDueDays, digest.End),
+			})
+			digest.Outstanding += invoice.Total
+		}
+	}
+	sort.Slice(digest.AwaitingPayment, func(i, j int) bool {
+		a, b := digest.AwaitingPayment[i], digest.AwaitingPayment[j]
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		return a.Invoice.InvoiceNumber < b.Invoice.InvoiceNumber
+	})
+
+	return digest, nil
 }
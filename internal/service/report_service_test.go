@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+)
+
+type mockTimeOffRepo struct {
+	timeOff []*domain.TimeOff // returned by List, unfiltered
+}
+
+func (m *mockTimeOffRepo) Create(ctx context.Context, timeOff *domain.TimeOff) error { return nil }
+func (m *mockTimeOffRepo) List(ctx context.Context, start, end *time.Time) ([]*domain.TimeOff, error) {
+	return m.timeOff, nil
+}
+func (m *mockTimeOffRepo) Delete(ctx context.Context, id int64) error { return nil }
+
+func TestGetAging_BucketsByDaysPastDue(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	due := func(daysAgo int) *time.Time {
+		d := asOf.AddDate(0, 0, -daysAgo)
+		return &d
+	}
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		1: {ID: 1, ClientID: 1, Total: 10000, Status: domain.InvoiceStatusSent, DueDate: due(-5)},
+		2: {ID: 2, ClientID: 1, Total: 20000, Status: domain.InvoiceStatusOverdue, DueDate: due(31)},
+		3: {ID: 3, ClientID: 2, Total: 40000, Status: domain.InvoiceStatusOverdue, DueDate: due(91)},
+		4: {ID: 4, ClientID: 2, Total: 80000, Status: domain.InvoiceStatusPaid, DueDate: due(120)},
+		// No due date: due 30 days after it was created, so 60 days late
+		5: {ID: 5, ClientID: 2, Total: 5000, Status: domain.InvoiceStatusSent, CreatedAt: asOf.AddDate(0, 0, -90)},
+	}}
+	svc := NewReportService(&mockEntryRepo{}, mockInv, &mockTimeOffRepo{})
+
+	report, err := svc.GetAging(ctx, asOf, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Clients) != 2 || report.Clients[0].ClientID != 2 {
+		t.Fatalf("expected two clients, largest first, got %+v", report.Clients)
+	}
+	if want := [4]domain.Money{0, 5000, 0, 40000}; report.Clients[0].Buckets != want {
+		t.Fatalf("unexpected buckets for client 2: %v", report.Clients[0].Buckets)
+	}
+	if want := [4]domain.Money{10000, 20000, 0, 0}; report.Clients[1].Buckets != want {
+		t.Fatalf("unexpected buckets for client 1: %v", report.Clients[1].Buckets)
+	}
+	if report.Total != 75000 || report.Buckets != [4]domain.Money{10000, 25000, 0, 40000} {
+		t.Fatalf("unexpected totals: %v %v", report.Total, report.Buckets)
+	}
+}
+
+func TestGetRevenueByMonth_CashAndAccrual(t *testing.T) {
+	ctx := context.Background()
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 12, 0, 0, 0, time.UTC) }
+	paid := day(time.March, 10)
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		// January's work, paid in March
+		1: {ID: 1, Total: 10000, Status: domain.InvoiceStatusPaid, PeriodEnd: day(time.January, 31), PaidDate: &paid},
+		// February's work, billed but unpaid
+		2: {ID: 2, Total: 20000, Status: domain.InvoiceStatusSent, PeriodEnd: day(time.February, 28)},
+		// Drafts are not revenue on either basis
+		3: {ID: 3, Total: 40000, Status: domain.InvoiceStatusDraft, PeriodEnd: day(time.February, 28)},
+	}}
+	svc := NewReportService(&mockEntryRepo{}, mockInv, &mockTimeOffRepo{})
+
+	cash, err := svc.GetRevenueByMonth(ctx, 2026, RevenueCash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cash[time.January] != 0 || cash[time.February] != 0 || cash[time.March] != 10000 {
+		t.Fatalf("unexpected cash revenue: %v", cash)
+	}
+
+	accrual, err := svc.GetRevenueByMonth(ctx, 2026, RevenueAccrual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accrual[time.January] != 10000 || accrual[time.February] != 20000 || accrual[time.March] != 0 {
+		t.Fatalf("unexpected accrual revenue: %v", accrual)
+	}
+}
+
+func TestGetRevenueByClient_RanksSharesAndFlagsConcentration(t *testing.T) {
+	ctx := context.Background()
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 12, 0, 0, 0, time.UTC) }
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		1: {ID: 1, ClientID: 1, Total: 30000, Status: domain.InvoiceStatusSent, PeriodEnd: day(time.January, 31)},
+		2: {ID: 2, ClientID: 2, Total: 70000, Status: domain.InvoiceStatusPaid, PeriodEnd: day(time.February, 28)},
+		// Drafts, void invoices and other years are left out
+		3: {ID: 3, ClientID: 1, Total: 90000, Status: domain.InvoiceStatusDraft, PeriodEnd: day(time.March, 31)},
+		4: {ID: 4, ClientID: 1, Total: 90000, Status: domain.InvoiceStatusVoid, PeriodEnd: day(time.March, 31)},
+		5: {ID: 5, ClientID: 1, Total: 90000, Status: domain.InvoiceStatusSent, PeriodEnd: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+	}}
+	svc := NewReportService(&mockEntryRepo{}, mockInv, &mockTimeOffRepo{})
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	report, err := svc.GetRevenueByClient(ctx, start, start.AddDate(1, 0, 0), RevenueAccrual)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 100000 || len(report.Clients) != 2 {
+		t.Fatalf("expected $1,000.00 from two clients, got %d from %d", report.Total, len(report.Clients))
+	}
+	if top := report.Clients[0]; top.ClientID != 2 || math.Abs(top.Share-0.7) > 1e-9 {
+		t.Fatalf("expected client 2 first with 70%%, got %+v", top)
+	}
+
+	if got := report.Concentrated(0.5); got == nil || got.ClientID != 2 {
+		t.Fatalf("expected client 2 over a 50%% limit, got %+v", got)
+	}
+	if got := report.Concentrated(0.75); got != nil {
+		t.Fatalf("expected nothing over a 75%% limit, got %+v", got)
+	}
+	if got := report.Concentrated(0); got != nil {
+		t.Fatalf("expected a 0 limit to turn the check off, got %+v", got)
+	}
+}
+
+func TestGetHoursHeatmap_SplitsEntriesAcrossHours(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 6, day, hour, min, 0, 0, time.UTC) }
+	entry := func(start, end time.Time) *domain.TimeEntry {
+		e := &domain.TimeEntry{ClientID: 1, StartTime: start}
+		e.Stop(end)
+		return e
+	}
+
+	mockEntries := &mockEntryRepo{entries: []*domain.TimeEntry{
+		// Monday 9:30-11:15
+		entry(at(1, 9, 30), at(1, 11, 15)),
+		// Tuesday 23:00 to Wednesday 01:00, across midnight
+		entry(at(2, 23, 0), at(3, 1, 0)),
+		// Started before the range, running into it by half an hour
+		entry(time.Date(2026, 5, 31, 23, 30, 0, 0, time.UTC), at(1, 0, 30)),
+		// Sunday night, running past the end of the range
+		entry(at(7, 23, 0), at(8, 0, 30)),
+	}}
+	svc := NewReportService(mockEntries, &mockInvoiceRepo{}, &mockTimeOffRepo{})
+
+	heatmap, err := svc.GetHoursHeatmap(ctx, at(1, 0, 0), at(8, 0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	monday := heatmap.Hours[time.Monday]
+	if monday[9] != 0.5 || monday[10] != 1 || monday[11] != 0.25 {
+		t.Fatalf("unexpected Monday hours: %v", monday[9:12])
+	}
+	if heatmap.Hours[time.Tuesday][23] != 1 || heatmap.Hours[time.Wednesday][0] != 1 {
+		t.Fatalf("expected the overnight entry split across days, got %v and %v",
+			heatmap.Hours[time.Tuesday][23], heatmap.Hours[time.Wednesday][0])
+	}
+	if monday[0] != 0.5 || heatmap.Hours[time.Sunday][23] != 1 {
+		t.Fatalf("expected entries clipped to the range, got %v and %v", monday[0], heatmap.Hours[time.Sunday][23])
+	}
+	if heatmap.Total != 5.25 || heatmap.Max != 1 {
+		t.Fatalf("unexpected total %v or max %v", heatmap.Total, heatmap.Max)
+	}
+}
+
+func TestGetShortDays_SkipsWeekendsAndExplainedDays(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour int) time.Time { return time.Date(2026, 6, day, hour, 0, 0, 0, time.UTC) }
+	entry := func(start time.Time, hours int, desc string) *domain.TimeEntry {
+		e := &domain.TimeEntry{ClientID: 1, StartTime: start, Description: desc}
+		e.Stop(start.Add(time.Duration(hours) * time.Hour))
+		return e
+	}
+
+	// June 1 2026 is a Monday
+	mockEntries := &mockEntryRepo{entries: []*domain.TimeEntry{
+		entry(at(1, 9), 8, "Build"),            // Monday on target
+		entry(at(2, 9), 3, "Build"),            // Tuesday short
+		entry(at(2, 14), 2, "Review"),          // still short at 5h
+		entry(at(3, 9), 0, "Day off: dentist"), // Wednesday explained
+		entry(at(6, 10), 1, "Emails"),          // Saturday is not a workday
+	}}
+	svc := NewReportService(mockEntries, &mockInvoiceRepo{}, &mockTimeOffRepo{})
+
+	short, err := svc.GetShortDays(ctx, at(1, 0), at(6, 0), 8, []string{"day off"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Tuesday, then Thursday and Friday with nothing logged
+	if len(short) != 3 || !short[0].Date.Equal(at(2, 0)) || short[0].Hours != 5 {
+		t.Fatalf("unexpected short days: %+v", short)
+	}
+	if !short[1].Date.Equal(at(4, 0)) || !short[2].Date.Equal(at(5, 0)) || short[2].Hours != 0 {
+		t.Fatalf("expected Thursday and Friday short, got %+v", short[1:])
+	}
+
+	if short, _ := svc.GetShortDays(ctx, at(1, 0), at(6, 0), 0, nil); short != nil {
+		t.Fatalf("expected no short days without a target, got %+v", short)
+	}
+}
+
+func TestLintEntries_FlagsDataQualityIssues(t *testing.T) {
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	entry := func(desc string, d time.Duration, rate float64, billable bool) *domain.TimeEntry {
+		e := &domain.TimeEntry{ClientID: 1, StartTime: start, Description: desc, HourlyRate: rate, IsBillable: billable}
+		e.Stop(start.Add(d))
+		return e
+	}
+
+	clean := entry("Build", 2*time.Hour, 150, true)
+	undescribed := entry("  ", 2*time.Hour, 150, true)
+	empty := entry("Call", 0, 150, true)
+	overnight := entry("Build", 15*time.Hour, 150, true)
+	free := entry("Build", time.Hour, 0, true)
+	nonBillable := entry("Admin", time.Hour, 0, false)
+	running := &domain.TimeEntry{ClientID: 1, StartTime: start}
+
+	lints := LintEntries([]*domain.TimeEntry{clean, undescribed, empty, overnight, free, nonBillable, running})
+	if len(lints) != 4 {
+		t.Fatalf("expected 4 entries flagged, got %d", len(lints))
+	}
+
+	want := []struct {
+		entry *domain.TimeEntry
+		issue LintIssue
+	}{
+		{undescribed, LintNoDescription},
+		{empty, LintZeroDuration},
+		{overnight, LintLongDuration},
+		{free, LintZeroRate},
+	}
+	for i, w := range want {
+		if lints[i].Entry != w.entry || len(lints[i].Issues) != 1 || !lints[i].Has(w.issue) {
+			t.Errorf("lint %d: expected only %q, got %v", i, w.issue, lints[i].Issues)
+		}
+	}
+}
+
+func TestGetWeekReview_GroupsTheWeekAndFlagsDaysToFix(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour int) time.Time { return time.Date(2026, 6, day, hour, 0, 0, 0, time.UTC) }
+	entry := func(start time.Time, hours int, desc string) *domain.TimeEntry {
+		e := &domain.TimeEntry{ClientID: 1, StartTime: start, Description: desc}
+		e.Stop(start.Add(time.Duration(hours) * time.Hour))
+		return e
+	}
+
+	// June 1 2026 is a Monday; the repository lists the newest first
+	mockEntries := &mockEntryRepo{entries: []*domain.TimeEntry{
+		entry(at(8, 9), 4, "Next week"),
+		entry(at(5, 9), 8, "Build"),
+		entry(at(2, 14), 5, "  "),
+		entry(at(2, 9), 3, "Build"),
+		entry(at(1, 9), 8, "Build"),
+	}}
+	svc := NewReportService(mockEntries, &mockInvoiceRepo{}, &mockTimeOffRepo{})
+
+	// Any day of the week reviews it from Monday
+	days, err := svc.GetWeekReview(ctx, at(3, 15), 8, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(days) != 7 || !days[0].Date.Equal(at(1, 0)) || !days[6].Date.Equal(at(7, 0)) {
+		t.Fatalf("expected Monday to Sunday, got %d days from %v", len(days), days[0].Date)
+	}
+
+	tuesday := days[1]
+	if len(tuesday.Entries) != 2 || tuesday.Entries[0].Description != "Build" || tuesday.Hours != 8 {
+		t.Fatalf("expected Tuesday's two entries earliest first, 8h, got %+v", tuesday)
+	}
+	if tuesday.Short || len(tuesday.Undescribed()) != 1 || !tuesday.NeedsReview() {
+		t.Fatalf("expected Tuesday on target with one entry to describe")
+	}
+	if days[0].NeedsReview() || !days[2].Short || !days[3].Short || days[4].NeedsReview() {
+		t.Fatalf("expected Wednesday and Thursday short only")
+	}
+	if days[5].NeedsReview() || days[6].NeedsReview() {
+		t.Fatalf("expected the weekend left alone")
+	}
+}
+
+func TestTimeOff_LeftOutOfUtilizationAndShortDays(t *testing.T) {
+	ctx := context.Background()
+	at := func(day, hour int) time.Time { return time.Date(2026, 6, day, hour, 0, 0, 0, time.Local) }
+	entry := func(start time.Time, hours int, billable bool) *domain.TimeEntry {
+		e := &domain.TimeEntry{ClientID: 1, StartTime: start, IsBillable: billable}
+		e.Stop(start.Add(time.Duration(hours) * time.Hour))
+		return e
+	}
+
+	// June 1 2026 is a Monday; Wednesday and Thursday are off
+	mockEntries := &mockEntryRepo{entries: []*domain.TimeEntry{
+		entry(at(1, 9), 8, true),   // Monday
+		entry(at(2, 9), 6, true),   // Tuesday
+		entry(at(2, 15), 2, false), // Tuesday admin
+		entry(at(3, 9), 2, false),  // answering email on vacation
+		entry(at(5, 9), 8, true),   // Friday
+	}}
+	mockTimeOff := &mockTimeOffRepo{timeOff: []*domain.TimeOff{
+		domain.NewTimeOff(at(3, 0), at(4, 0), domain.TimeOffVacation),
+	}}
+	svc := NewReportService(mockEntries, &mockInvoiceRepo{}, mockTimeOff)
+
+	week, err := svc.GetWeekSummary(ctx, at(1, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if week.DaysOff[time.Wednesday] != domain.TimeOffVacation || week.DaysOff[time.Thursday] != domain.TimeOffVacation || len(week.DaysOff) != 2 {
+		t.Fatalf("expected Wednesday and Thursday off, got %v", week.DaysOff)
+	}
+	if week.TotalHours != 26 || week.OffHours != 2 {
+		t.Fatalf("expected all hours totalled and 2 on days off, got %v and %v", week.TotalHours, week.OffHours)
+	}
+	// 22 billable of 24 worked on working days
+	if u := week.Utilization(); u < 91.66 || u > 91.67 {
+		t.Fatalf("expected utilization to leave days off out, got %v", u)
+	}
+
+	short, err := svc.GetShortDays(ctx, at(1, 0), at(6, 0), 8, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(short) != 0 {
+		t.Fatalf("expected days off not to be short, got %+v", short)
+	}
+}
+
+func TestGetCapacity_TakesDaysOffFromTheTarget(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, time.June, d, 0, 0, 0, 0, time.UTC) }
+
+	// Wednesday June 10 through the weekend after is vacation
+	mockTimeOff := &mockTimeOffRepo{timeOff: []*domain.TimeOff{
+		domain.NewTimeOff(day(10), day(14), domain.TimeOffVacation),
+	}}
+	svc := NewReportService(&mockEntryRepo{}, &mockInvoiceRepo{}, mockTimeOff)
+	clients := []*domain.Client{
+		{ID: 1, WeeklyDemand: 20},
+		{ID: 2, WeeklyDemand: 12},
+		{ID: 3, WeeklyDemand: 10, IsArchived: true},
+	}
+
+	// Starting mid-week plans from that week's Monday
+	plan, err := svc.GetCapacity(ctx, day(3), 2, 30, clients)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 2 || !plan[0].Start.Equal(day(1)) || !plan[1].Start.Equal(day(8)) {
+		t.Fatalf("expected the weeks of June 1 and 8, got %+v", plan)
+	}
+	if plan[0].Capacity != 30 || plan[0].Demand != 32 || plan[0].Balance() != -2 {
+		t.Fatalf("expected the first week 2 hours overbooked, got %+v", plan[0])
+	}
+	if plan[1].DaysOff != 3 || plan[1].Capacity != 12 || plan[1].Balance() != -20 {
+		t.Fatalf("expected three weekdays off to leave 12 hours, got %+v", plan[1])
+	}
+}
+
+func TestGetStatement_CarriesBalanceForward(t *testing.T) {
+	ctx := context.Background()
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 12, 0, 0, 0, time.UTC) }
+	paidFeb, paidMar := day(time.February, 20), day(time.March, 5)
+	issuedDec, paidDec := time.Date(2025, time.December, 2, 12, 0, 0, 0, time.UTC), time.Date(2025, time.December, 15, 0, 0, 0, 0, time.UTC)
+
+	mockInv := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		// Paid before February: settled, so not carried forward
+		1: {ID: 1, ClientID: 1, Total: 5000, Status: domain.InvoiceStatusPaid, CreatedAt: issuedDec, PaidDate: &paidDec},
+		// Issued in January, paid in February
+		2: {ID: 2, ClientID: 1, Total: 10000, Status: domain.InvoiceStatusPaid, CreatedAt: day(time.January, 10), PaidDate: &paidFeb},
+		// Issued in January, still owed
+		3: {ID: 3, ClientID: 1, Total: 20000, Status: domain.InvoiceStatusOverdue, CreatedAt: day(time.January, 25)},
+		// Issued in February, paid in March
+		4: {ID: 4, ClientID: 1, Total: 40000, Status: domain.InvoiceStatusPaid, CreatedAt: day(time.February, 3), PaidDate: &paidMar},
+		// Drafts have not been issued, and other clients' invoices are not listed
+		5: {ID: 5, ClientID: 1, Total: 80000, Status: domain.InvoiceStatusDraft, CreatedAt: day(time.February, 4)},
+		6: {ID: 6, ClientID: 2, Total: 160000, Status: domain.InvoiceStatusSent, CreatedAt: day(time.February, 5)},
+	}}
+	svc := NewReportService(&mockEntryRepo{}, mockInv, &mockTimeOffRepo{})
+
+	st, err := svc.GetStatement(ctx, &domain.Client{ID: 1}, day(time.February, 14))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !st.Start.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start: %v", st.Start)
+	}
+	if st.OpeningBalance != 30000 || st.Invoiced != 40000 || st.Received != 10000 || st.ClosingBalance != 60000 {
+		t.Fatalf("unexpected totals: opening %v, invoiced %v, received %v, closing %v",
+			st.OpeningBalance, st.Invoiced, st.Received, st.ClosingBalance)
+	}
+	if len(st.Lines) != 2 {
+		t.Fatalf("expected two lines, got %d", len(st.Lines))
+	}
+	if l := st.Lines[0]; l.Invoice.ID != 4 || l.Payment || l.Balance != 70000 {
+		t.Fatalf("unexpected first line: %+v", l)
+	}
+	if l := st.Lines[1]; l.Invoice.ID != 2 || !l.Payment || l.Amount != -10000 || l.Balance != 60000 {
+		t.Fatalf("unexpected second line: %+v", l)
+	}
+}
+
+// rangedEntryRepo lists only the entries starting within the range asked for
+type rangedEntryRepo struct {
+	mockEntryRepo
+}
+
+func (m *rangedEntryRepo) List(ctx context.Context, clientID *int64, start, end *time.Time, includeLocked bool) ([]*domain.TimeEntry, error) {
+	var out []*domain.TimeEntry
+	for _, e := range m.entries {
+		if (start == nil || !e.StartTime.Before(*start)) && (end == nil || e.StartTime.Before(*end)) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func TestGetDigest_ComparesWeeksAndListsUnpaidInvoices(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	entry := func(clientID int64, day, hours int, invoiced bool) *domain.TimeEntry {
+		start := monday.AddDate(0, 0, day).Add(9 * time.Hour)
+		e := domain.NewTimeEntry(clientID, "Work", 100)
+		e.StartTime = start
+		e.Stop(start.Add(time.Duration(hours) * time.Hour))
+		if invoiced {
+			id := int64(1)
+			e.InvoiceID = &id
+		}
+		return e
+	}
+
+	entries := &rangedEntryRepo{mockEntryRepo{entries: []*domain.TimeEntry{
+		entry(1, 0, 4, false),
+		entry(1, 2, 2, false),
+		entry(2, 1, 1, false),
+		// The week before
+		entry(1, -7, 3, true),
+		entry(3, -5, 5, true),
+	}}}
+	due := monday.AddDate(0, 0, 2)
+	invoices := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		1: {ID: 1, InvoiceNumber: "INV-2026-002", ClientID: 3, Total: 50000, Status: domain.InvoiceStatusOverdue, DueDate: &due},
+		2: {ID: 2, InvoiceNumber: "INV-2026-003", ClientID: 1, Total: 30000, Status: domain.InvoiceStatusSent, CreatedAt: monday},
+		3: {ID: 3, InvoiceNumber: "INV-2026-001", ClientID: 1, Total: 90000, Status: domain.InvoiceStatusPaid},
+	}}
+	clients := map[int64]*domain.Client{1: {ID: 1, Name: "Acme"}, 2: {ID: 2, Name: "Globex"}}
+	svc := NewReportService(entries, invoices, &mockTimeOffRepo{})
+
+	// Any day of the week selects it
+	digest, err := svc.GetDigest(ctx, monday.AddDate(0, 0, 3).Add(15*time.Hour), clients, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !digest.Start.Equal(monday) || !digest.End.Equal(monday.AddDate(0, 0, 7)) {
+		t.Fatalf("unexpected week %v to %v", digest.Start, digest.End)
+	}
+	if digest.Hours != 7 || digest.PriorHours != 8 {
+		t.Fatalf("expected 7h against 8h, got %v against %v", digest.Hours, digest.PriorHours)
+	}
+
+	var got []string
+	for _, c := range digest.Clients {
+		got = append(got, fmt.Sprintf("%s %g/%g", c.Client.Name, c.Hours, c.PriorHours))
+	}
+	if want := "Acme 6/3, Globex 1/0, Client #3 0/5"; strings.Join(got, ", ") != want {
+		t.Fatalf("clients = %s, want %s", strings.Join(got, ", "), want)
+	}
+
+	// Only this week's entries are unbilled
+	if digest.Unbilled != 70000 {
+		t.Fatalf("expected 700.00 unbilled, got %v", digest.Unbilled)
+	}
+
+	if len(digest.AwaitingPayment) != 2 || digest.Outstanding != 80000 {
+		t.Fatalf("expected two unpaid invoices totalling 800.00, got %d totalling %v", len(digest.AwaitingPayment), digest.Outstanding)
+	}
+	first := digest.AwaitingPayment[0]
+	if first.Invoice.InvoiceNumber != "INV-2026-002" || first.DaysPastDue != 5 || first.Invoice.Client.Name != "Client #3" {
+		t.Fatalf("expected the overdue invoice first, 5 days late, got %s %d days", first.Invoice.InvoiceNumber, first.DaysPastDue)
+	}
+	if second := digest.AwaitingPayment[1]; !second.Due.Equal(monday.AddDate(0, 0, 30)) || second.DaysPastDue != 0 {
+		t.Fatalf("expected the sent invoice due in 30 days, got %v", second.Due)
+	}
+}
+
+func TestGetBillingReminders_ByAmountOrDaysSinceInvoiced(t *testing.T) {
+	ctx := context.Background()
+	asOf := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	entry := func(clientID int64, daysAgo, hours int, billable bool) *domain.TimeEntry {
+		start := asOf.AddDate(0, 0, -daysAgo)
+		e := domain.NewTimeEntry(clientID, "Work", 100)
+		e.StartTime = start
+		e.IsBillable = billable
+		e.Stop(start.Add(time.Duration(hours) * time.Hour))
+		return e
+	}
+
+	entries := &mockEntryRepo{entries: []*domain.TimeEntry{
+		// Over the amount, invoiced recently
+		entry(1, 3, 6, true),
+		// Small, last invoiced 90 days ago
+		entry(2, 10, 1, true),
+		// Small, never invoiced, first worked 5 days ago
+		entry(3, 5, 1, true),
+		// Only non-billable time
+		entry(4, 100, 8, false),
+	}}
+	invoices := &mockInvoiceRepo{invoices: map[int64]*domain.Invoice{
+		1: {ID: 1, ClientID: 1, Status: domain.InvoiceStatusSent, CreatedAt: asOf.AddDate(0, 0, -7)},
+		2: {ID: 2, ClientID: 2, Status: domain.InvoiceStatusPaid, CreatedAt: asOf.AddDate(0, 0, -90)},
+		// Drafts have not been issued
+		3: {ID: 3, ClientID: 2, Status: domain.InvoiceStatusDraft, CreatedAt: asOf.AddDate(0, 0, -1)},
+	}}
+	svc := NewReportService(entries, invoices, &mockTimeOffRepo{})
+
+	reminders, err := svc.GetBillingReminders(ctx, asOf, 50000, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reminders) != 2 {
+		t.Fatalf("expected two reminders, got %d", len(reminders))
+	}
+	if r := reminders[0]; r.ClientID != 1 || r.Unbilled != 60000 || !r.OverAmount || r.OverDays || r.Days != 7 {
+		t.Fatalf("unexpected first reminder: %+v", r)
+	}
+	if r := reminders[1]; r.ClientID != 2 || r.OverAmount || !r.OverDays || r.Days != 90 {
+		t.Fatalf("unexpected second reminder: %+v", r)
+	}
+
+	// Both checks off
+	if reminders, _ := svc.GetBillingReminders(ctx, asOf, 0, 0); len(reminders) != 0 {
+		t.Fatalf("expected no reminders when both checks are off, got %d", len(reminders))
+	}
+}
@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/domain"
@@ -14,6 +17,7 @@ var (
 	ErrTimerNotRunning     = errors.New("timer is not running")
 	ErrTimerNotPaused      = errors.New("timer is not paused")
 	ErrNoActiveTimer       = errors.New("no active timer")
+	ErrTimerOverlap        = errors.New("timer would overlap an existing entry")
 )
 
 // TimerService manages the timer state machine
@@ -24,17 +28,29 @@ type TimerService interface {
 	// GetActiveTimer returns the current active timer, or nil if idle
 	GetActiveTimer(ctx context.Context) (*domain.ActiveTimer, error)
 
-	// Start creates a new timer (only from Idle state)
-	Start(ctx context.Context, clientID int64, description string) error
+	// Start creates a new timer (only from Idle state), recording location
+	// as where the work is done and billing role from the client's rate
+	// card; either may be empty
+	Start(ctx context.Context, clientID int64, description string, billable bool, location, role string) error
 
 	// Pause pauses the running timer (only from Running state)
 	Pause(ctx context.Context) error
 
+	// PauseAt pauses the running timer as of an earlier time, e.g. when the
+	// computer went to sleep, leaving out the time since
+	PauseAt(ctx context.Context, at time.Time) error
+
 	// Resume resumes a paused timer (only from Paused state)
 	Resume(ctx context.Context) error
 
-	// Stop stops the timer and creates a time entry (from Running or Paused)
-	Stop(ctx context.Context) (*domain.TimeEntry, error)
+	// Stop stops the timer and saves it as a time entry (from Running or
+	// Paused). A timer that ran past midnight is saved as one entry per day,
+	// returned in order, so daily totals stay correct.
+	Stop(ctx context.Context) ([]*domain.TimeEntry, error)
+
+	// StopEdited stops the timer like Stop, saving it with the end,
+	// description and billable flag reviewed by the user
+	StopEdited(ctx context.Context, edit domain.TimerStop) ([]*domain.TimeEntry, error)
 
 	// Discard discards the active timer without creating an entry
 	Discard(ctx context.Context) error
@@ -48,6 +64,13 @@ type TimerService interface {
 	// UpdateDescription updates the description of the active timer
 	UpdateDescription(ctx context.Context, description string) error
 
+	// AdjustStart moves the active timer's start, e.g. back to when work
+	// began. Moving it earlier must not cover time already logged.
+	AdjustStart(ctx context.Context, start time.Time) error
+
+	// AddNote appends a timestamped note to the active timer
+	AddNote(ctx context.Context, note string) error
+
 	// RecoverFromCrash checks for an existing timer on startup
 	RecoverFromCrash(ctx context.Context) error
 }
@@ -56,19 +79,43 @@ type timerService struct {
 	timerRepo  repository.TimerRepository
 	entryRepo  repository.TimeEntryRepository
 	clientRepo repository.ClientRepository
+	uow        repository.UnitOfWork
+	log        *slog.Logger
 }
 
-// NewTimerService creates a new timer service
+// NewTimerService creates a new timer service. Stopping the timer saves its
+// entries and deletes it through uow, so it is never both saved and left
+// running; uow may be nil.
 func NewTimerService(
 	timerRepo repository.TimerRepository,
 	entryRepo repository.TimeEntryRepository,
 	clientRepo repository.ClientRepository,
+	uow repository.UnitOfWork,
+	log *slog.Logger,
 ) TimerService {
 	return &timerService{
 		timerRepo:  timerRepo,
 		entryRepo:  entryRepo,
 		clientRepo: clientRepo,
+		uow:        uow,
+		log:        log,
+	}
+}
+
+// inTx runs fn with a copy of the service whose repositories share one
+// transaction. Without a unit of work fn runs against the service itself.
+func (s *timerService) inTx(ctx context.Context, fn func(tx *timerService) error) error {
+	if s.uow == nil {
+		return fn(s)
 	}
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		return fn(&timerService{
+			timerRepo:  repos.Timer,
+			entryRepo:  repos.Entries,
+			clientRepo: repos.Clients,
+			log:        s.log,
+		})
+	})
 }
 
 func (s *timerService) GetState(ctx context.Context) (domain.TimerState, error) {
@@ -86,7 +133,7 @@ func (s *timerService) GetActiveTimer(ctx context.Context) (*domain.ActiveTimer,
 	return s.timerRepo.Get(ctx)
 }
 
-func (s *timerService) Start(ctx context.Context, clientID int64, description string) error {
+func (s *timerService) Start(ctx context.Context, clientID int64, description string, billable bool, location, role string) error {
 	// Verify client exists
 	client, err := s.clientRepo.GetByID(ctx, clientID)
 	if err != nil {
@@ -107,10 +154,41 @@ func (s *timerService) Start(ctx context.Context, clientID int64, description st
 
 	// Create and save new timer
 	timer := domain.NewActiveTimer(clientID, description)
-	return s.timerRepo.Save(ctx, timer)
+	timer.IsBillable = billable
+	timer.Location = location
+	if role != "" {
+		roleRate, err := FindRoleRate(ctx, s.clientRepo, client, role)
+		if err != nil {
+			return err
+		}
+		timer.Role = roleRate.Role
+	}
+	if err := s.timerRepo.Save(ctx, timer); err != nil {
+		return err
+	}
+
+	s.log.Info("timer started", "client_id", clientID, "billable", billable, "role", timer.Role)
+	return nil
+}
+
+// FindRoleRate looks up role on the client's rate card, ignoring case
+func FindRoleRate(ctx context.Context, clients repository.ClientRepository, client *domain.Client, role string) (*domain.RoleRate, error) {
+	card, err := clients.ListRoleRates(ctx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	found := domain.FindRole(card, role)
+	if found == nil {
+		return nil, fmt.Errorf("%s has no %q role on its rate card", client.Name, strings.TrimSpace(role))
+	}
+	return found, nil
 }
 
 func (s *timerService) Pause(ctx context.Context) error {
+	return s.PauseAt(ctx, time.Now())
+}
+
+func (s *timerService) PauseAt(ctx context.Context, at time.Time) error {
 	timer, err := s.timerRepo.Get(ctx)
 	if err != nil {
 		return err
@@ -124,8 +202,14 @@ func (s *timerService) Pause(ctx context.Context) error {
 		return ErrTimerNotRunning
 	}
 
-	timer.Pause()
-	return s.timerRepo.Save(ctx, timer)
+	timer.PauseAt(at)
+	if err := s.timerRepo.Save(ctx, timer); err != nil {
+		return err
+	}
+
+	s.log.Info("timer paused", "client_id", timer.ClientID, "at", timer.PausedAt.Format(time.RFC3339),
+		"elapsed", timer.Elapsed().Round(time.Second).String())
+	return nil
 }
 
 func (s *timerService) Resume(ctx context.Context) error {
@@ -143,10 +227,49 @@ func (s *timerService) Resume(ctx context.Context) error {
 	}
 
 	timer.Resume()
-	return s.timerRepo.Save(ctx, timer)
+	if err := s.timerRepo.Save(ctx, timer); err != nil {
+		return err
+	}
+
+	s.log.Info("timer resumed", "client_id", timer.ClientID, "paused_seconds", timer.TotalPausedSeconds)
+	return nil
+}
+
+func (s *timerService) Stop(ctx context.Context) ([]*domain.TimeEntry, error) {
+	return s.stop(ctx, nil)
+}
+
+func (s *timerService) StopEdited(ctx context.Context, edit domain.TimerStop) ([]*domain.TimeEntry, error) {
+	return s.stop(ctx, &edit)
+}
+
+// stop saves the timer as entries, reviewed by edit if it is not nil
+func (s *timerService) stop(ctx context.Context, edit *domain.TimerStop) ([]*domain.TimeEntry, error) {
+	var entries []*domain.TimeEntry
+	err := s.inTx(ctx, func(tx *timerService) error {
+		var err error
+		entries, err = tx.save(ctx, edit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		s.log.Info("timer stopped",
+			"client_id", e.ClientID,
+			"entry_id", e.ID,
+			"duration", e.Duration().Round(time.Second).String(),
+			"rate", e.HourlyRate,
+			"amount", e.Amount(),
+			"parts", len(entries),
+		)
+	}
+	return entries, nil
 }
 
-func (s *timerService) Stop(ctx context.Context) (*domain.TimeEntry, error) {
+// save converts the timer to entries, creates them and deletes the timer
+func (s *timerService) save(ctx context.Context, edit *domain.TimerStop) ([]*domain.TimeEntry, error) {
 	timer, err := s.timerRepo.Get(ctx)
 	if err != nil {
 		return nil, err
@@ -155,7 +278,7 @@ func (s *timerService) Stop(ctx context.Context) (*domain.TimeEntry, error) {
 		return nil, ErrNoActiveTimer
 	}
 
-	// Get client to retrieve hourly rate
+	// Verify client exists
 	client, err := s.clientRepo.GetByID(ctx, timer.ClientID)
 	if err != nil {
 		return nil, err
@@ -164,20 +287,48 @@ func (s *timerService) Stop(ctx context.Context) (*domain.TimeEntry, error) {
 		return nil, errors.New("client not found")
 	}
 
-	// Convert timer to time entry
-	entry := timer.ToTimeEntry(client.HourlyRate)
-
-	// Save entry
-	if err := s.entryRepo.Create(ctx, entry); err != nil {
+	// Convert timer to time entry at its role's rate, or else the rate in
+	// effect when it started, split so each entry falls on one day. A role
+	// taken off the rate card since the start falls back to the client's rate.
+	rate, err := s.clientRepo.RateAt(ctx, timer.ClientID, timer.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	if timer.Role != "" {
+		card, err := s.clientRepo.ListRoleRates(ctx, timer.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		if role := domain.FindRole(card, timer.Role); role != nil {
+			rate = role.HourlyRate
+		} else {
+			timer.Role = ""
+		}
+	}
+	entry := timer.ToTimeEntry(rate)
+	if edit != nil {
+		if err := edit.Apply(entry); err != nil {
+			return nil, err
+		}
+	}
+	rest, err := entry.SplitAtMidnight()
+	if err != nil {
 		return nil, err
 	}
+	entries := append([]*domain.TimeEntry{entry}, rest...)
+
+	// Save entries
+	for _, e := range entries {
+		if err := s.entryRepo.Create(ctx, e); err != nil {
+			return nil, err
+		}
+	}
 
 	// Delete active timer
 	if err := s.timerRepo.Delete(ctx); err != nil {
 		return nil, err
 	}
-
-	return entry, nil
+	return entries, nil
 }
 
 func (s *timerService) Discard(ctx context.Context) error {
@@ -189,7 +340,12 @@ func (s *timerService) Discard(ctx context.Context) error {
 		return ErrNoActiveTimer
 	}
 
-	return s.timerRepo.Delete(ctx)
+	if err := s.timerRepo.Delete(ctx); err != nil {
+		return err
+	}
+
+	s.log.Info("timer discarded", "client_id", timer.ClientID, "elapsed", timer.Elapsed().Round(time.Second).String())
+	return nil
 }
 
 func (s *timerService) ElapsedDuration(ctx context.Context) (time.Duration, error) {
@@ -224,7 +380,74 @@ func (s *timerService) UpdateDescription(ctx context.Context, description string
 	}
 
 	timer.Description = description
-	return s.timerRepo.Save(ctx, timer)
+	if err := s.timerRepo.Save(ctx, timer); err != nil {
+		return err
+	}
+
+	s.log.Debug("timer description updated", "client_id", timer.ClientID)
+	return nil
+}
+
+func (s *timerService) AdjustStart(ctx context.Context, start time.Time) error {
+	timer, err := s.timerRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if timer == nil {
+		return ErrNoActiveTimer
+	}
+
+	previous := timer.StartTime
+	adjusted := *timer
+	if err := adjusted.SetStart(start); err != nil {
+		return err
+	}
+
+	// Only the time the timer now also covers can clash. Entries are looked
+	// up from a day before it, which finds all but hand-made entries over a
+	// day long; timer entries are split at midnight.
+	if start.Before(previous) {
+		from := start.AddDate(0, 0, -1)
+		entries, err := s.entryRepo.List(ctx, nil, &from, &previous, true)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.Overlaps(start, previous) {
+				return fmt.Errorf("%w: #%d from %s to %s", ErrTimerOverlap, entry.ID,
+					entry.StartTime.Format("2006-01-02 15:04"), entry.StartTime.Add(entry.Span()).Format("15:04"))
+			}
+		}
+	}
+
+	if err := s.timerRepo.Save(ctx, &adjusted); err != nil {
+		return err
+	}
+
+	s.log.Info("timer start adjusted", "client_id", timer.ClientID, "from", previous, "to", start)
+	return nil
+}
+
+func (s *timerService) AddNote(ctx context.Context, note string) error {
+	if strings.TrimSpace(note) == "" {
+		return errors.New("note cannot be empty")
+	}
+
+	timer, err := s.timerRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if timer == nil {
+		return ErrNoActiveTimer
+	}
+
+	timer.AddNote(note)
+	if err := s.timerRepo.Save(ctx, timer); err != nil {
+		return err
+	}
+
+	s.log.Debug("timer note added", "client_id", timer.ClientID)
+	return nil
 }
 
 func (s *timerService) RecoverFromCrash(ctx context.Context) error {
@@ -236,7 +459,7 @@ func (s *timerService) RecoverFromCrash(ctx context.Context) error {
 	// If timer exists, it was running before crash - no action needed
 	// The timer repository persists the state, so it will continue
 	if timer != nil {
-		// Could log a message here about recovered timer
+		s.log.Debug("timer recovered", "client_id", timer.ClientID, "state", string(timer.State()))
 		return nil
 	}
 
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/repository"
+)
+
+type mockTimerRepo struct {
+	timer *domain.ActiveTimer
+}
+
+func (m *mockTimerRepo) Get(ctx context.Context) (*domain.ActiveTimer, error) { return m.timer, nil }
+func (m *mockTimerRepo) Save(ctx context.Context, timer *domain.ActiveTimer) error {
+	m.timer = timer
+	return nil
+}
+func (m *mockTimerRepo) Delete(ctx context.Context) error {
+	m.timer = nil
+	return nil
+}
+
+func TestStop_SplitsAtMidnight(t *testing.T) {
+	ctx := context.Background()
+
+	// Started at 22:00 two days ago, so it ran through two midnights
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()-2, 22, 0, 0, 0, time.Local)
+	timers := &mockTimerRepo{timer: &domain.ActiveTimer{ClientID: 1, Description: "Deploy", StartTime: start, IsBillable: true, Location: "on-site"}}
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(timers, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 || len(entryRepo.created) != 3 {
+		t.Fatalf("expected three entries saved, got %d entries, %d saved", len(entries), len(entryRepo.created))
+	}
+	if entries[0].Duration() != 2*time.Hour || entries[1].StartTime.Hour() != 0 || entries[1].EndTime.Hour() != 0 {
+		t.Fatalf("expected 2h then a full day, got %v and %v to %v", entries[0].Duration(), entries[1].StartTime, entries[1].EndTime)
+	}
+	for i, e := range entries {
+		if e.StartTime.Day() != e.EndTime.Add(-time.Nanosecond).Day() {
+			t.Fatalf("entry %d spans days: %v to %v", i, e.StartTime, e.EndTime)
+		}
+		if e.HourlyRate != 100 || e.Description != "Deploy" || !e.IsBillable || e.Location != "on-site" {
+			t.Fatalf("entry %d lost the timer's details: %+v", i, e)
+		}
+	}
+	if timers.timer != nil {
+		t.Fatalf("expected the timer to be cleared")
+	}
+}
+
+func TestStop_SameDayIsOneEntry(t *testing.T) {
+	ctx := context.Background()
+
+	timers := &mockTimerRepo{timer: domain.NewActiveTimer(1, "Review")}
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(timers, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || len(entryRepo.created) != 1 {
+		t.Fatalf("expected one entry saved, got %d entries", len(entries))
+	}
+}
+
+func TestAdjustStart_RefusesToCoverLoggedTime(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Review")
+	timer.StartTime = now.Add(-10 * time.Minute)
+	timers := &mockTimerRepo{timer: timer}
+
+	// An entry that ended half an hour ago
+	end := now.Add(-30 * time.Minute)
+	logged := &domain.TimeEntry{ID: 7, StartTime: now.Add(-time.Hour), EndTime: &end}
+	svc := NewTimerService(timers, &mockEntryRepo{entries: []*domain.TimeEntry{logged}}, &mockClientRepo{}, nil, discardLog)
+
+	if err := svc.AdjustStart(ctx, now.Add(-40*time.Minute)); !errors.Is(err, ErrTimerOverlap) {
+		t.Fatalf("expected ErrTimerOverlap, got %v", err)
+	}
+	if !timers.timer.StartTime.Equal(now.Add(-10 * time.Minute)) {
+		t.Fatalf("a refused adjustment should leave the start alone, got %v", timers.timer.StartTime)
+	}
+
+	start := now.Add(-30 * time.Minute)
+	if err := svc.AdjustStart(ctx, start); err != nil {
+		t.Fatalf("starting where the entry ended should succeed, got %v", err)
+	}
+	if !timers.timer.StartTime.Equal(start) {
+		t.Fatalf("expected start %v, got %v", start, timers.timer.StartTime)
+	}
+
+	if err := svc.AdjustStart(ctx, now.Add(time.Minute)); err == nil {
+		t.Fatalf("expected a start in the future to be refused")
+	}
+}
+
+func TestParseTimerStart(t *testing.T) {
+	current := time.Date(2026, time.March, 9, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"-20m", current.Add(-20 * time.Minute)},
+		{"+1h5m", current.Add(65 * time.Minute)},
+		{"09:40", time.Date(2026, time.March, 9, 9, 40, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := domain.ParseTimerStart(tt.in, current)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseTimerStart(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"20m", "-20", "9.40"} {
+		if _, err := domain.ParseTimerStart(in, current); err == nil {
+			t.Errorf("ParseTimerStart(%q) should fail", in)
+		}
+	}
+}
+
+func TestStopEdited_SavesTheReviewedEntry(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Reveiw")
+	timer.StartTime = now.Add(-time.Hour)
+	timers := &mockTimerRepo{timer: timer}
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(timers, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	if _, err := svc.StopEdited(ctx, domain.TimerStop{End: now.Add(time.Hour), Description: "Review"}); err == nil {
+		t.Fatalf("expected an end after the timer stopped to be refused")
+	}
+	if timers.timer == nil || len(entryRepo.created) != 0 {
+		t.Fatalf("a refused stop should keep the timer and save nothing")
+	}
+
+	end := now.Add(-15 * time.Minute)
+	entries, err := svc.StopEdited(ctx, domain.TimerStop{End: end, Description: " Review ", IsBillable: false})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || len(entryRepo.created) != 1 {
+		t.Fatalf("expected one entry saved, got %d", len(entryRepo.created))
+	}
+	e := entries[0]
+	if !e.EndTime.Equal(end) || e.Duration() != 45*time.Minute || *e.DurationSeconds != 45*60 {
+		t.Fatalf("expected the entry trimmed to 45m, got %v ending %v", e.Duration(), e.EndTime)
+	}
+	if e.Description != "Review" || e.IsBillable || e.HourlyRate != 100 {
+		t.Fatalf("entry should carry the reviewed details: %+v", e)
+	}
+	if timers.timer != nil {
+		t.Fatalf("expected the timer to be cleared")
+	}
+}
+
+func TestStop_AppendsNotesToDescription(t *testing.T) {
+	ctx := context.Background()
+
+	timer := domain.NewActiveTimer(1, "Auth service")
+	timer.StartTime = time.Now().Add(-time.Hour)
+	timer.Notes = "[09:05] fixed the token refresh bug\n[09:40] reviewed the PR"
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(&mockTimerRepo{timer: timer}, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Auth service; [09:05] fixed the token refresh bug; [09:40] reviewed the PR"
+	if entries[0].Description != want {
+		t.Fatalf("Description = %q, want %q", entries[0].Description, want)
+	}
+	if entries[0].Notes != "" {
+		t.Fatalf("notes should move into the description, not stay in Notes: %q", entries[0].Notes)
+	}
+}
+
+func TestPauseAt_LeavesOutTheTimeSince(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Review")
+	timer.StartTime = now.Add(-8 * time.Hour)
+	timers := &mockTimerRepo{timer: timer}
+	svc := NewTimerService(timers, &mockEntryRepo{}, &mockClientRepo{}, nil, discardLog)
+
+	// Asleep an hour after starting
+	if err := svc.PauseAt(ctx, now.Add(-7*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := timers.timer.Elapsed().Round(time.Minute); got != time.Hour {
+		t.Fatalf("expected 1h counted, got %v", got)
+	}
+	if err := svc.Resume(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := timers.timer.Elapsed().Round(time.Minute); got != time.Hour {
+		t.Fatalf("resuming should keep the sleep left out, got %v", got)
+	}
+}
+
+func TestStop_DoesNotBillASleep(t *testing.T) {
+	ctx := context.Background()
+
+	// A 3h timer the computer slept through for 2h of, resumed on waking
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Review")
+	timer.StartTime = now.Add(-3 * time.Hour)
+	timers := &mockTimerRepo{timer: timer}
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(timers, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	if err := svc.PauseAt(ctx, now.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.Resume(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := entries[0]
+	if got := e.Duration().Round(time.Minute); got != time.Hour {
+		t.Fatalf("expected 1h worked, got %v", got)
+	}
+	if got := e.Amount(); got != domain.MoneyFromFloat(100) {
+		t.Fatalf("expected 1h billed at 100, got %v", got)
+	}
+	if *e.DurationSeconds != int64(e.Duration().Seconds()) {
+		t.Fatalf("DurationSeconds %d disagrees with the time worked %v", *e.DurationSeconds, e.Duration())
+	}
+	if got := e.Span().Round(time.Minute); got != 3*time.Hour {
+		t.Fatalf("expected the entry to keep its real end, 3h after the start, got %v", got)
+	}
+}
+
+func TestStopEdited_KeepsPausesLeftOut(t *testing.T) {
+	ctx := context.Background()
+
+	// 4h timer paused for 1h, trimmed by 30m on stopping
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Review")
+	timer.StartTime = now.Add(-4 * time.Hour)
+	timer.Pauses = []domain.Pause{{Start: now.Add(-3 * time.Hour), End: now.Add(-2 * time.Hour)}}
+	timer.TotalPausedSeconds = int64(time.Hour.Seconds())
+	entryRepo := &mockEntryRepo{}
+	svc := NewTimerService(&mockTimerRepo{timer: timer}, entryRepo, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	end := now.Add(-30 * time.Minute)
+	entries, err := svc.StopEdited(ctx, domain.TimerStop{End: end, Description: "Review", IsBillable: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	e := entries[0]
+	if !e.EndTime.Equal(end) {
+		t.Fatalf("expected the entry to end when it was trimmed to, %v, got %v", end, e.EndTime)
+	}
+	if got := e.Duration().Round(time.Minute); got != 150*time.Minute {
+		t.Fatalf("expected 2h30m, got %v", got)
+	}
+	if got := e.Amount(); got != domain.MoneyFromFloat(250) {
+		t.Fatalf("expected 2.5h billed at 100, got %v", got)
+	}
+}
+
+func TestStopEdited_DropsPausesAfterTheEnd(t *testing.T) {
+	ctx := context.Background()
+
+	// 4h timer paused for its last hour, trimmed to end as the pause began
+	now := time.Now()
+	timer := domain.NewActiveTimer(1, "Review")
+	timer.StartTime = now.Add(-4 * time.Hour)
+	timer.Pauses = []domain.Pause{{Start: now.Add(-time.Hour), End: now}}
+	timer.TotalPausedSeconds = int64(time.Hour.Seconds())
+	svc := NewTimerService(&mockTimerRepo{timer: timer}, &mockEntryRepo{}, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	entries, err := svc.StopEdited(ctx, domain.TimerStop{End: now.Add(-time.Hour), Description: "Review", IsBillable: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := entries[0].Duration().Round(time.Minute); got != 3*time.Hour {
+		t.Fatalf("expected 3h, got %v", got)
+	}
+}
+
+func TestStop_LeavesPausesOutOnTheirDay(t *testing.T) {
+	ctx := context.Background()
+
+	// Started at 22:00 yesterday and paused from 22:30 to 23:30
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day()-1, 22, 0, 0, 0, time.Local)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	timer := domain.NewActiveTimer(1, "Deploy")
+	timer.StartTime = start
+	timer.Pauses = []domain.Pause{{Start: start.Add(30 * time.Minute), End: start.Add(90 * time.Minute)}}
+	timer.TotalPausedSeconds = int64(time.Hour.Seconds())
+	svc := NewTimerService(&mockTimerRepo{timer: timer}, &mockEntryRepo{}, &mockClientRepo{rate: 100}, nil, discardLog)
+
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected two entries, got %d", len(entries))
+	}
+	first, second := entries[0], entries[1]
+	if !first.EndTime.Equal(midnight) {
+		t.Fatalf("expected the first day to end at midnight, got %v", first.EndTime)
+	}
+	if got := first.Duration(); got != time.Hour {
+		t.Fatalf("expected the pause left out of the first day, got %v worked", got)
+	}
+	if got, want := second.Duration(), second.Span(); got != want {
+		t.Fatalf("expected nothing left out of the second day, got %v worked of %v", got, want)
+	}
+	if len(second.Pauses) != 0 {
+		t.Fatalf("expected no pauses on the second day, got %v", second.Pauses)
+	}
+}
+
+func TestStop_RunsInUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+
+	// Only the transaction's repositories know about the timer, so the stop
+	// only succeeds if it goes through the unit of work
+	txTimers := &mockTimerRepo{timer: domain.NewActiveTimer(1, "Review")}
+	txEntries := &mockEntryRepo{}
+	uow := &mockUnitOfWork{repos: repository.Repositories{
+		Timer:   txTimers,
+		Entries: txEntries,
+		Clients: &mockClientRepo{rate: 100},
+	}}
+	outerEntries := &mockEntryRepo{}
+	svc := NewTimerService(&mockTimerRepo{}, outerEntries, &mockClientRepo{rate: 100}, uow, discardLog)
+
+	if _, err := svc.Stop(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uow.calls != 1 {
+		t.Fatalf("expected one transaction, got %d", uow.calls)
+	}
+	if len(outerEntries.created) != 0 || len(txEntries.created) != 1 {
+		t.Fatalf("expected the entry saved inside the transaction, got %d outside and %d inside", len(outerEntries.created), len(txEntries.created))
+	}
+	if txTimers.timer != nil {
+		t.Fatalf("expected the timer deleted inside the transaction")
+	}
+}
+
+func TestStart_BillsTheRoleRate(t *testing.T) {
+	ctx := context.Background()
+
+	timers := &mockTimerRepo{}
+	entryRepo := &mockEntryRepo{}
+	clients := &mockClientRepo{rate: 100, roles: []*domain.RoleRate{{ClientID: 1, Role: "consulting", HourlyRate: 150}}}
+	svc := NewTimerService(timers, entryRepo, clients, nil, discardLog)
+
+	if err := svc.Start(ctx, 1, "Workshop", true, "", "design"); err == nil {
+		t.Fatal("expected a role missing from the rate card to be refused")
+	}
+	if err := svc.Start(ctx, 1, "Workshop", true, "", " Consulting "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timers.timer.Role != "consulting" {
+		t.Fatalf("expected the rate card's role name, got %q", timers.timer.Role)
+	}
+
+	entries, err := svc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := entries[0]; e.HourlyRate != 150 || e.Role != "consulting" {
+		t.Fatalf("expected the consulting rate, got %.2f for %q", e.HourlyRate, e.Role)
+	}
+}
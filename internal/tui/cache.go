@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/db"
+	"github.com/andy/timesink/internal/domain"
+)
+
+// screenData holds what the screens loaded, so switching back to a screen
+// shows it without querying again. It is set when the TUI starts.
+var screenData *dataCache
+
+// dataCache keeps the result of each screen load until the database changes.
+// Every write bumps SQLite's data version, whether it came from this TUI, the
+// CLI in another terminal, or a hook, and the first load after that starts
+// from an empty cache. Loads of the same key share one query while it runs.
+type dataCache struct {
+	db      *db.DB
+	mu      sync.Mutex
+	version int64
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry is one load, finished once done is closed
+type cacheEntry struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func newDataCache(database *db.DB) *dataCache {
+	return &dataCache{db: database, entries: make(map[string]*cacheEntry)}
+}
+
+// entry returns the load for key and whether the caller should run it
+func (c *dataCache) entry(key string) (*cacheEntry, bool) {
+	version, err := c.db.DataVersion(context.Background())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil || version != c.version {
+		c.entries = make(map[string]*cacheEntry)
+		c.version = version
+	}
+	if e, ok := c.entries[key]; ok {
+		return e, false
+	}
+	e := &cacheEntry{done: make(chan struct{})}
+	if err == nil {
+		c.entries[key] = e
+	}
+	return e, true
+}
+
+// forget drops a failed load so the next caller tries again
+func (c *dataCache) forget(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == e {
+		delete(c.entries, key)
+	}
+}
+
+// cached returns the value load produces for key, running it only if no
+// other caller has since the database last changed. Values are shared
+// between callers, so they must not be modified.
+func cached[T any](c *dataCache, key string, load func() (T, error)) (T, error) {
+	if c == nil || c.db == nil {
+		return load()
+	}
+
+	e, run := c.entry(key)
+	if !run {
+		<-e.done
+		value, _ := e.value.(T)
+		return value, e.err
+	}
+
+	value, err := load()
+	e.value, e.err = value, err
+	close(e.done)
+	if err != nil {
+		c.forget(key, e)
+	}
+	return value, err
+}
+
+// today is part of the key of loads that depend on the date, so they are
+// redone when the day changes
+func today() string {
+	return time.Now().Format("2006-01-02")
+}
+
+// clientsByID returns every client, archived ones included, by ID, for the
+// screens that show client names
+func clientsByID(a *app.App) (map[int64]*domain.Client, error) {
+	return cached(screenData, "clients-by-id", func() (map[int64]*domain.Client, error) {
+		clients, err := a.ClientRepo.List(context.Background(), true)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[int64]*domain.Client, len(clients))
+		for _, client := range clients {
+			byID[client.ID] = client
+		}
+		return byID, nil
+	})
+}
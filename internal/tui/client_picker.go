@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/andy/timesink/internal/domain"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerRows is how many clients a picker lists at once
+const pickerRows = 12
+
+// Letters filter the list, so a picker moves with the arrow keys and
+// ctrl+p/ctrl+n rather than j/k
+var (
+	pickerUpKey   = key.NewBinding(key.WithKeys("up", "ctrl+p"))
+	pickerDownKey = key.NewBinding(key.WithKeys("down", "ctrl+n"))
+)
+
+// clientPicker is the client list shared by every screen that asks for a
+// client. Typing filters it with fuzzy matching on the name, so "acl" finds
+// "Acme Labs"; the arrow keys move and enter picks. Screens show it with
+// View, pass it keys with Update, and treat esc as going back.
+type clientPicker struct {
+	clients []*domain.Client
+	list    listView[*domain.Client] // the matching clients
+	filter  textinput.Model
+}
+
+// newClientPicker lists clients in the order given until something is typed
+func newClientPicker(clients []*domain.Client) clientPicker {
+	ti := textinput.New()
+	ti.Prompt = "Find: "
+	ti.Placeholder = "type to filter"
+	ti.CharLimit = 50
+	ti.Width = 30
+	p := clientPicker{clients: clients, list: newListView(renderPickerClient), filter: ti}
+	p.list.SetHeight(pickerRows)
+	p.list.SetEmpty("No clients match")
+	p.refilter()
+	return p
+}
+
+// Focus starts taking typed text as the filter
+func (p *clientPicker) Focus() tea.Cmd {
+	return p.filter.Focus()
+}
+
+// Len is the number of clients the picker was given, matching or not
+func (p *clientPicker) Len() int {
+	return len(p.clients)
+}
+
+// Selected is the highlighted client, or nil when nothing matches
+func (p *clientPicker) Selected() *domain.Client {
+	client, _ := p.list.Selected()
+	return client
+}
+
+// Update moves the highlight or edits the filter. It returns the highlighted
+// client when enter is pressed on one.
+func (p *clientPicker) Update(msg tea.Msg) (*domain.Client, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, pickerUpKey):
+			p.list.MoveUp()
+			return nil, nil
+		case key.Matches(msg, pickerDownKey):
+			p.list.MoveDown()
+			return nil, nil
+		case key.Matches(msg, DefaultKeyMap.Select):
+			return p.Selected(), nil
+		}
+	}
+
+	before := p.filter.Value()
+	var cmd tea.Cmd
+	p.filter, cmd = p.filter.Update(msg)
+	if p.filter.Value() != before {
+		p.refilter()
+	}
+	return nil, cmd
+}
+
+// refilter lists the clients matching the filter, best match first
+func (p *clientPicker) refilter() {
+	p.list.Reset()
+	pattern := p.filter.Value()
+	if strings.TrimSpace(pattern) == "" {
+		p.list.SetItems(p.clients)
+		return
+	}
+
+	type scored struct {
+		client *domain.Client
+		score  int
+	}
+	var found []scored
+	for _, c := range p.clients {
+		if score, ok := fuzzyScore(pattern, c.Name); ok {
+			found = append(found, scored{c, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	matches := make([]*domain.Client, len(found))
+	for i, f := range found {
+		matches[i] = f.client
+	}
+	p.list.SetItems(matches)
+}
+
+// fuzzyScore reports whether the letters of pattern appear in s in order,
+// ignoring case and spaces, and how well they match: letters that follow
+// each other in s or start one of its words score higher.
+func fuzzyScore(pattern, s string) (int, bool) {
+	want := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	runes := []rune(strings.ToLower(s))
+
+	score, next, last := 0, 0, -2
+	for i, r := range runes {
+		if next == len(want) {
+			break
+		}
+		if r != want[next] {
+			continue
+		}
+		score++
+		if i == last+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		last = i
+		next++
+	}
+	return score, next == len(want)
+}
+
+// View renders the filter above the matching clients
+func (p *clientPicker) View() string {
+	return "  " + p.filter.View() + "\n\n" + p.list.View()
+}
+
+// pickerKeys are the keys shown while a picker is open
+func pickerKeys(pick string) []key.Binding {
+	nav := key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "navigate"))
+	return []key.Binding{nav, withHelp(DefaultKeyMap.Select, pick), DefaultKeyMap.Cancel}
+}
+
+// renderPickerClient draws a client as a picker row with its name and rate
+func renderPickerClient(c *domain.Client, selected bool) string {
+	line := fmt.Sprintf("  %-25s  %s", c.Name, formatRate(c.HourlyRate))
+	if selected {
+		return lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("> " + line[2:])
+	}
+	return line
+}
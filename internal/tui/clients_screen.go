@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/app"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/render"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,31 +31,49 @@ const (
 	fieldRate
 	fieldEmail
 	fieldNotes
+	fieldTimesheet
+	fieldMinIncrement
+	fieldDailyCap
+	fieldOvertime
+	fieldReverseCharge
+	fieldRequireApproval
+	fieldLanguage
+	fieldWeeklyDemand
 	fieldCount
 )
 
 // ClientsModel displays a navigable list of clients with create/edit forms
 type ClientsModel struct {
 	app          *app.App
-	clients      []*domain.Client
-	cursor       int
+	list         listView[*domain.Client]
 	showArchived bool
 	monthlyStats map[int64]*clientMonthStats
 	loading      bool
 	err          error
-	statusMsg    string
+
+	// Asks before archiving a client with open work
+	confirm confirmDialog
 
 	// Form state
-	mode           clientMode
-	fields         []textinput.Model
-	fieldFocus     int
-	editingID      int64 // 0 for new client
-	autoNewClient  bool  // open new client form after data loads
+	mode          clientMode
+	fields        []textinput.Model
+	fieldFocus    int
+	editingID     int64                // 0 for new client
+	editingVer    int64                // version of the client when the form opened
+	autoNewClient bool                 // open new client form after data loads
+	rates         []*domain.ClientRate // rate history of the client being edited
 }
 
+// clientsListChrome is the number of lines in the list view that are not
+// client rows: title, scroll indicators and help
+const clientsListChrome = 6
+
+// clientRowLines is the most lines a client's row takes
+const clientRowLines = 3
+
 type clientMonthStats struct {
 	hours float64
-	value float64
+	value domain.Money
 }
 
 type clientsDataMsg struct {
@@ -62,23 +82,59 @@ type clientsDataMsg struct {
 	err          error
 }
 
+type clientRatesMsg struct {
+	clientID int64
+	rates    []*domain.ClientRate
+	err      error
+}
+
 type clientSavedMsg struct {
 	name string
 	err  error
 }
 
+// openWorkMsg carries what is still open for a client about to be archived
+type openWorkMsg struct {
+	client *domain.Client
+	work   []string // one line per kind of open work; empty when there is none
+	err    error
+}
+
 // NewClientsModel creates a new clients screen model
 func NewClientsModel(a *app.App) tea.Model {
-	return &ClientsModel{
+	m := &ClientsModel{
 		app:          a,
 		monthlyStats: make(map[int64]*clientMonthStats),
 		loading:      true,
 	}
+	m.list = newListView(m.renderClient)
+	m.list.SetEmpty("No clients yet. Press 'n' to add one.")
+	return m
 }
 
-// IsCapturingInput returns true when the form is active
+// IsCapturingInput returns true when the form or a confirmation is active
 func (m *ClientsModel) IsCapturingInput() bool {
-	return m.mode == clientModeNew || m.mode == clientModeEdit
+	return m.mode == clientModeNew || m.mode == clientModeEdit || m.confirm.Active()
+}
+
+// OverridesKey claims 't' in list mode to start a timer for the selected client
+func (m *ClientsModel) OverridesKey(msg tea.KeyMsg) bool {
+	return m.mode == clientModeList && key.Matches(msg, DefaultKeyMap.StartTimer) && m.list.Len() > 0
+}
+
+// KeyHelp lists the keys for the list or the form
+func (m *ClientsModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.confirm.Active() {
+		return m.confirm.KeyHelp()
+	}
+	if m.IsCapturingInput() {
+		return formKeys()
+	}
+	if m.list.Len() == 0 {
+		return []key.Binding{k.New, k.ShowArchived}
+	}
+	return []key.Binding{navigateKeys(), k.New, withHelp(k.Select, "edit"), k.StartTimer, k.Archive, k.ShowArchived}
 }
 
 func (m *ClientsModel) Init() tea.Cmd {
@@ -86,38 +142,47 @@ func (m *ClientsModel) Init() tea.Cmd {
 }
 
 func (m *ClientsModel) loadClients() tea.Cmd {
+	key := fmt.Sprintf("clients:%t:%s", m.showArchived, today())
 	return func() tea.Msg {
-		ctx := context.Background()
+		msg, _ := cached(screenData, key, func() (clientsDataMsg, error) {
+			msg := m.fetchClients()
+			return msg, msg.err
+		})
+		return msg
+	}
+}
 
-		clients, err := m.app.ClientRepo.List(ctx, m.showArchived)
-		if err != nil {
-			return clientsDataMsg{err: err}
-		}
+func (m *ClientsModel) fetchClients() clientsDataMsg {
+	ctx := context.Background()
 
-		// Calculate monthly stats per client
-		now := time.Now()
-		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-		monthEnd := monthStart.AddDate(0, 1, 0)
+	clients, err := m.app.ClientRepo.List(ctx, m.showArchived)
+	if err != nil {
+		return clientsDataMsg{err: err}
+	}
 
-		stats := make(map[int64]*clientMonthStats)
-		for _, client := range clients {
-			cid := client.ID
-			entries, err := m.app.EntryRepo.List(ctx, &cid, &monthStart, &monthEnd, true)
-			if err != nil {
-				continue
-			}
-			cs := &clientMonthStats{}
-			for _, entry := range entries {
-				cs.hours += entry.Duration().Hours()
-				cs.value += entry.Amount()
-			}
-			stats[client.ID] = cs
-		}
+	// Calculate monthly stats per client
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
 
-		return clientsDataMsg{
-			clients:      clients,
-			monthlyStats: stats,
+	stats := make(map[int64]*clientMonthStats)
+	for _, client := range clients {
+		cid := client.ID
+		entries, err := m.app.EntryRepo.List(ctx, &cid, &monthStart, &monthEnd, true)
+		if err != nil {
+			continue
 		}
+		cs := &clientMonthStats{}
+		for _, entry := range entries {
+			cs.hours += entry.Duration().Hours()
+			cs.value += entry.Amount()
+		}
+		stats[client.ID] = cs
+	}
+
+	return clientsDataMsg{
+		clients:      clients,
+		monthlyStats: stats,
 	}
 }
 
@@ -148,21 +213,98 @@ func (m *ClientsModel) initForm(editing *domain.Client) {
 	m.fields[fieldNotes].CharLimit = 200
 	m.fields[fieldNotes].Width = 50
 
+	// Timesheet attachment field
+	m.fields[fieldTimesheet] = textinput.New()
+	m.fields[fieldTimesheet].Placeholder = "n"
+	m.fields[fieldTimesheet].CharLimit = 3
+	m.fields[fieldTimesheet].Width = 5
+
+	// Billing rule fields, blank for none
+	m.fields[fieldMinIncrement] = textinput.New()
+	m.fields[fieldMinIncrement].Placeholder = "0"
+	m.fields[fieldMinIncrement].CharLimit = 4
+	m.fields[fieldMinIncrement].Width = 5
+
+	m.fields[fieldDailyCap] = textinput.New()
+	m.fields[fieldDailyCap].Placeholder = "0"
+	m.fields[fieldDailyCap].CharLimit = 5
+	m.fields[fieldDailyCap].Width = 5
+
+	m.fields[fieldOvertime] = textinput.New()
+	m.fields[fieldOvertime].Placeholder = "0"
+	m.fields[fieldOvertime].CharLimit = 5
+	m.fields[fieldOvertime].Width = 5
+
+	// Reverse charge field
+	m.fields[fieldReverseCharge] = textinput.New()
+	m.fields[fieldReverseCharge].Placeholder = "n"
+	m.fields[fieldReverseCharge].CharLimit = 3
+	m.fields[fieldReverseCharge].Width = 5
+
+	// Approval field
+	m.fields[fieldRequireApproval] = textinput.New()
+	m.fields[fieldRequireApproval].Placeholder = "n"
+	m.fields[fieldRequireApproval].CharLimit = 3
+	m.fields[fieldRequireApproval].Width = 5
+
+	// Invoice language field, blank for English
+	m.fields[fieldLanguage] = textinput.New()
+	m.fields[fieldLanguage].Placeholder = render.DefaultLanguage
+	m.fields[fieldLanguage].CharLimit = 10
+	m.fields[fieldLanguage].Width = 5
+
+	// Expected weekly hours, blank for none
+	m.fields[fieldWeeklyDemand] = textinput.New()
+	m.fields[fieldWeeklyDemand].Placeholder = "0"
+	m.fields[fieldWeeklyDemand].CharLimit = 5
+	m.fields[fieldWeeklyDemand].Width = 5
+
 	// Pre-fill for editing
 	if editing != nil {
 		m.fields[fieldName].SetValue(editing.Name)
 		m.fields[fieldRate].SetValue(fmt.Sprintf("%.2f", editing.HourlyRate))
 		m.fields[fieldEmail].SetValue(editing.Email)
 		m.fields[fieldNotes].SetValue(editing.Notes)
+		if editing.AttachTimesheet {
+			m.fields[fieldTimesheet].SetValue("y")
+		}
+		if b := editing.Billing; b.MinIncrementMinutes > 0 {
+			m.fields[fieldMinIncrement].SetValue(strconv.Itoa(b.MinIncrementMinutes))
+		}
+		if b := editing.Billing; b.DailyCapHours > 0 {
+			m.fields[fieldDailyCap].SetValue(strconv.FormatFloat(b.DailyCapHours, 'f', -1, 64))
+		}
+		if b := editing.Billing; b.OvertimeMultiplier > 0 {
+			m.fields[fieldOvertime].SetValue(strconv.FormatFloat(b.OvertimeMultiplier, 'f', -1, 64))
+		}
+		if editing.ReverseCharge {
+			m.fields[fieldReverseCharge].SetValue("y")
+		}
+		if editing.RequireApproval {
+			m.fields[fieldRequireApproval].SetValue("y")
+		}
+		m.fields[fieldLanguage].SetValue(editing.Language)
+		if editing.WeeklyDemand > 0 {
+			m.fields[fieldWeeklyDemand].SetValue(strconv.FormatFloat(editing.WeeklyDemand, 'f', -1, 64))
+		}
 		m.editingID = editing.ID
+		m.editingVer = editing.Version
 	} else {
 		m.editingID = 0
 	}
+	m.rates = nil
 
 	m.fieldFocus = fieldName
 	m.fields[fieldName].Focus()
 }
 
+func (m *ClientsModel) loadRates(clientID int64) tea.Cmd {
+	return func() tea.Msg {
+		rates, err := m.app.ClientRepo.ListRates(context.Background(), clientID)
+		return clientRatesMsg{clientID: clientID, rates: rates, err: err}
+	}
+}
+
 func (m *ClientsModel) saveClient() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -171,29 +313,94 @@ func (m *ClientsModel) saveClient() tea.Cmd {
 		rateStr := m.fields[fieldRate].Value()
 		email := m.fields[fieldEmail].Value()
 		notes := m.fields[fieldNotes].Value()
+		timesheetStr := strings.ToLower(strings.TrimSpace(m.fields[fieldTimesheet].Value()))
 
 		if name == "" {
 			return clientSavedMsg{err: fmt.Errorf("name is required")}
 		}
 
-		rate, err := strconv.ParseFloat(rateStr, 64)
+		rate, err := activeLocale.ParseNumber(rateStr)
 		if err != nil && rateStr != "" {
 			return clientSavedMsg{err: fmt.Errorf("invalid rate: %s", rateStr)}
 		}
 
+		var attachTimesheet bool
+		switch timesheetStr {
+		case "y", "yes":
+			attachTimesheet = true
+		case "", "n", "no":
+		default:
+			return clientSavedMsg{err: fmt.Errorf("timesheet must be y or n")}
+		}
+
+		var reverseCharge bool
+		switch strings.ToLower(strings.TrimSpace(m.fields[fieldReverseCharge].Value())) {
+		case "y", "yes":
+			reverseCharge = true
+		case "", "n", "no":
+		default:
+			return clientSavedMsg{err: fmt.Errorf("reverse charge must be y or n")}
+		}
+
+		var requireApproval bool
+		switch strings.ToLower(strings.TrimSpace(m.fields[fieldRequireApproval].Value())) {
+		case "y", "yes":
+			requireApproval = true
+		case "", "n", "no":
+		default:
+			return clientSavedMsg{err: fmt.Errorf("require approval must be y or n")}
+		}
+
+		language := strings.TrimSpace(m.fields[fieldLanguage].Value())
+		if _, err := render.LabelsFor(language, m.app.Config.Invoice.Translations); err != nil {
+			return clientSavedMsg{err: err}
+		}
+
+		var billing domain.BillingRules
+		if v := strings.TrimSpace(m.fields[fieldMinIncrement].Value()); v != "" {
+			if billing.MinIncrementMinutes, err = strconv.Atoi(v); err != nil {
+				return clientSavedMsg{err: fmt.Errorf("invalid minimum increment: %s", v)}
+			}
+		}
+		if v := strings.TrimSpace(m.fields[fieldDailyCap].Value()); v != "" {
+			if billing.DailyCapHours, err = activeLocale.ParseNumber(v); err != nil {
+				return clientSavedMsg{err: fmt.Errorf("invalid daily cap: %s", v)}
+			}
+		}
+		if v := strings.TrimSpace(m.fields[fieldOvertime].Value()); v != "" {
+			if billing.OvertimeMultiplier, err = activeLocale.ParseNumber(v); err != nil {
+				return clientSavedMsg{err: fmt.Errorf("invalid overtime multiplier: %s", v)}
+			}
+		}
+
+		var weeklyDemand float64
+		if v := strings.TrimSpace(m.fields[fieldWeeklyDemand].Value()); v != "" {
+			if weeklyDemand, err = activeLocale.ParseNumber(v); err != nil {
+				return clientSavedMsg{err: fmt.Errorf("invalid weekly hours: %s", v)}
+			}
+		}
+
 		if m.editingID > 0 {
 			// Update existing
 			client, err := m.app.ClientRepo.GetByID(ctx, m.editingID)
 			if err != nil {
 				return clientSavedMsg{err: err}
 			}
+			// Refuse to overwrite changes made while the form was open
+			client.Version = m.editingVer
 			client.Name = name
 			client.HourlyRate = rate
 			client.Email = email
 			client.Notes = notes
+			client.AttachTimesheet = attachTimesheet
+			client.Billing = billing
+			client.ReverseCharge = reverseCharge
+			client.RequireApproval = requireApproval
+			client.Language = language
+			client.WeeklyDemand = weeklyDemand
 			client.UpdatedAt = time.Now()
 
-			if err := m.app.ClientRepo.Update(ctx, client); err != nil {
+			if err := m.app.ClientService.Update(ctx, client); err != nil {
 				return clientSavedMsg{err: err}
 			}
 			return clientSavedMsg{name: name}
@@ -203,8 +410,14 @@ func (m *ClientsModel) saveClient() tea.Cmd {
 		client := domain.NewClient(name, rate)
 		client.Email = email
 		client.Notes = notes
-
-		if err := m.app.ClientRepo.Create(ctx, client); err != nil {
+		client.AttachTimesheet = attachTimesheet
+		client.Billing = billing
+		client.ReverseCharge = reverseCharge
+		client.RequireApproval = requireApproval
+		client.Language = language
+		client.WeeklyDemand = weeklyDemand
+
+		if err := m.app.ClientService.Create(ctx, client); err != nil {
 			return clientSavedMsg{err: err}
 		}
 		return clientSavedMsg{name: name}
@@ -224,6 +437,12 @@ func (m *ClientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.fields[fieldName].Focus()
 	}
 
+	// Fit the list to the space the root model gives us
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.list.SetHeight((msg.Height - clientsListChrome) / clientRowLines)
+		return m, nil
+	}
+
 	// Handle form mode
 	if m.mode == clientModeNew || m.mode == clientModeEdit {
 		return m.updateForm(msg)
@@ -238,11 +457,8 @@ func (m *ClientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		m.err = msg.err
 		if msg.err == nil {
-			m.clients = msg.clients
 			m.monthlyStats = msg.monthlyStats
-			if m.cursor >= len(m.clients) {
-				m.cursor = max(0, len(m.clients)-1)
-			}
+			m.list.SetItems(msg.clients)
 		}
 		// Auto-open new client form on first run
 		if m.autoNewClient {
@@ -259,45 +475,68 @@ func (m *ClientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.mode = clientModeList
-		m.statusMsg = fmt.Sprintf("Saved: %s", msg.name)
 		m.loading = true
-		return m, m.loadClients()
+		return m, tea.Batch(m.loadClients(), notify(NotifySuccess, fmt.Sprintf("Saved: %s", msg.name)))
+
+	case timerStartedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		return m, switchToTimerCmd()
+
+	case openWorkMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		if len(msg.work) == 0 {
+			return m, m.toggleArchive(msg.client)
+		}
+		client := msg.client
+		m.confirm.Ask("Archive Client", fmt.Sprintf("Archive %s anyway?", client.Name), msg.work,
+			func() tea.Cmd { return m.toggleArchive(client) })
+		return m, nil
 
 	case tea.KeyMsg:
 		if m.loading {
 			return m, nil
 		}
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
+		}
 
-		m.statusMsg = ""
 		m.err = nil
 
 		switch {
-		case key.Matches(msg, DefaultKeyMap.Up):
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case key.Matches(msg, DefaultKeyMap.Down):
-			if m.cursor < len(m.clients)-1 {
-				m.cursor++
-			}
-		case msg.String() == "n":
+		case m.list.Update(msg):
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.New):
 			m.mode = clientModeNew
 			m.initForm(nil)
 			return m, m.fields[fieldName].Focus()
 		case key.Matches(msg, DefaultKeyMap.Select):
 			// Enter key opens edit form for selected client
-			if len(m.clients) > 0 && m.cursor < len(m.clients) {
+			if client, ok := m.list.Selected(); ok {
 				m.mode = clientModeEdit
-				m.initForm(m.clients[m.cursor])
-				return m, m.fields[fieldName].Focus()
+				m.initForm(client)
+				return m, tea.Batch(m.fields[fieldName].Focus(), m.loadRates(m.editingID))
+			}
+		case key.Matches(msg, DefaultKeyMap.Archive):
+			if client, ok := m.list.Selected(); ok {
+				if client.IsArchived {
+					return m, m.toggleArchive(client)
+				}
+				return m, m.loadOpenWork(client)
 			}
-		case msg.String() == "a":
-			if len(m.clients) > 0 && m.cursor < len(m.clients) {
-				return m, m.toggleArchive()
+		case key.Matches(msg, DefaultKeyMap.StartTimer):
+			if client, ok := m.list.Selected(); ok {
+				if client.IsArchived {
+					return m, notify(NotifyWarning, fmt.Sprintf("Cannot start timer: %s is archived", client.Name))
+				}
+				return m, startTimerCmd(m.app, client.ID, "", true)
 			}
-		case msg.String() == "h":
+		case key.Matches(msg, DefaultKeyMap.ShowArchived):
 			m.showArchived = !m.showArchived
-			m.cursor = 0
+			m.list.Reset()
 			m.loading = true
 			return m, m.loadClients()
 		}
@@ -308,37 +547,46 @@ func (m *ClientsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *ClientsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case clientRatesMsg:
+		// Ignore history for a client that is no longer being edited
+		if msg.clientID == m.editingID {
+			m.rates = msg.rates
+			if msg.err != nil {
+				m.err = msg.err
+			}
+		}
+		return m, nil
+
 	case clientSavedMsg:
 		if msg.err != nil {
 			m.err = msg.err
 			return m, nil
 		}
 		m.mode = clientModeList
-		m.statusMsg = fmt.Sprintf("Saved: %s", msg.name)
 		m.loading = true
-		return m, m.loadClients()
+		return m, tea.Batch(m.loadClients(), notify(NotifySuccess, fmt.Sprintf("Saved: %s", msg.name)))
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			// Cancel form
 			m.mode = clientModeList
 			m.err = nil
 			return m, nil
 
-		case "tab", "down":
+		case key.Matches(msg, DefaultKeyMap.NextField):
 			// Next field
 			m.fields[m.fieldFocus].Blur()
 			m.fieldFocus = (m.fieldFocus + 1) % fieldCount
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "shift+tab", "up":
+		case key.Matches(msg, DefaultKeyMap.PrevField):
 			// Previous field
 			m.fields[m.fieldFocus].Blur()
 			m.fieldFocus = (m.fieldFocus - 1 + fieldCount) % fieldCount
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "enter":
+		case key.Matches(msg, DefaultKeyMap.Select):
 			// If on last field or explicit submit, save
 			if m.fieldFocus == fieldCount-1 {
 				return m, m.saveClient()
@@ -348,7 +596,7 @@ func (m *ClientsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.fieldFocus++
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "ctrl+s":
+		case key.Matches(msg, DefaultKeyMap.Save):
 			// Save from any field
 			return m, m.saveClient()
 		}
@@ -360,10 +608,9 @@ func (m *ClientsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *ClientsModel) toggleArchive() tea.Cmd {
+func (m *ClientsModel) toggleArchive(client *domain.Client) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		client := m.clients[m.cursor]
 
 		if client.IsArchived {
 			m.app.ClientRepo.Unarchive(ctx, client.ID)
@@ -376,7 +623,62 @@ func (m *ClientsModel) toggleArchive() tea.Cmd {
 	}
 }
 
+// loadOpenWork looks for work on a client that archiving would hide:
+// unbilled time, invoices not yet paid and a running timer
+func (m *ClientsModel) loadOpenWork(client *domain.Client) tea.Cmd {
+	a := m.app
+	return func() tea.Msg {
+		ctx := context.Background()
+		var work []string
+
+		entries, err := a.EntryRepo.List(ctx, &client.ID, nil, nil, false)
+		if err != nil {
+			return openWorkMsg{err: err}
+		}
+		var unbilled int
+		var hours float64
+		for _, e := range entries {
+			if e.IsBillable && e.EndTime != nil {
+				unbilled++
+				hours += e.Duration().Hours()
+			}
+		}
+		if unbilled > 0 {
+			work = append(work, fmt.Sprintf("%d unbilled entries (%s)", unbilled, formatHours(hours)))
+		}
+
+		invoices, err := a.InvoiceService.ListInvoices(ctx, &client.ID, nil)
+		if err != nil {
+			return openWorkMsg{err: err}
+		}
+		var unpaid int
+		var owed domain.Money
+		for _, inv := range invoices {
+			if inv.Status != domain.InvoiceStatusPaid && inv.Status != domain.InvoiceStatusVoid {
+				unpaid++
+				owed += inv.Total
+			}
+		}
+		if unpaid > 0 {
+			work = append(work, fmt.Sprintf("%d invoices not yet paid (%s)", unpaid, formatMoney(owed.Float())))
+		}
+
+		timer, err := a.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return openWorkMsg{err: err}
+		}
+		if timer != nil && timer.ClientID == client.ID {
+			work = append(work, "A timer is running for this client")
+		}
+
+		return openWorkMsg{client: client, work: work}
+	}
+}
+
 func (m *ClientsModel) View() string {
+	if m.confirm.Active() {
+		return m.confirm.View()
+	}
 	if m.mode == clientModeNew || m.mode == clientModeEdit {
 		return m.viewForm()
 	}
@@ -387,7 +689,7 @@ func (m *ClientsModel) viewForm() string {
 	var s string
 
 	if m.mode == clientModeNew {
-		if len(m.clients) == 0 {
+		if m.list.Len() == 0 {
 			s += titleStyle.Render("Welcome to timesink!") + "\n"
 			s += subtitleStyle.Render("  Let's set up your first client to get started.") + "\n\n"
 		} else {
@@ -397,7 +699,12 @@ func (m *ClientsModel) viewForm() string {
 		s += titleStyle.Render("Edit Client") + "\n\n"
 	}
 
-	labels := []string{"Name:", "Rate ($/hr):", "Email:", "Notes:"}
+	labels := []string{"Name:", "Rate (" + activeLocale.CurrencySymbol + "/hr):", "Email:", "Notes:", "Attach timesheet to invoices (y/n):",
+		"Minimum increment (minutes, blank for exact time):", "Daily cap (hours, blank for none):",
+		"Overtime multiplier over the cap (blank to not bill):", "Reverse charge, invoice without tax (y/n):",
+		"Only invoice approved entries (y/n):",
+		"Invoice language (" + strings.Join(render.Languages(m.app.Config.Invoice.Translations), "/") + "):",
+		"Expected hours per week (blank for none):"}
 	for i, label := range labels {
 		indicator := "  "
 		if i == m.fieldFocus {
@@ -410,12 +717,38 @@ func (m *ClientsModel) viewForm() string {
 		s += fmt.Sprintf("%s%s\n  %s\n\n", indicator, labelStyle.Render(label), m.fields[i].View())
 	}
 
+	if m.mode == clientModeEdit && len(m.rates) > 0 {
+		s += m.viewRates() + "\n"
+	}
+
 	if m.err != nil {
 		s += lipgloss.NewStyle().Foreground(errorColor).
 			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
 	}
 
-	s += helpStyle.Render("  tab/shift+tab: navigate fields  ctrl+s: save  enter: next/save  esc: cancel")
+	s += renderKeyHelp(m.KeyHelp()...)
+
+	return s
+}
+
+// viewRates lists the edited client's rate history, newest first
+func (m *ClientsModel) viewRates() string {
+	s := lipgloss.NewStyle().Bold(true).Render("  Rate History") + "\n"
+
+	now := time.Now()
+	current := true
+	for _, rate := range m.rates {
+		note := ""
+		if rate.EffectiveFrom.After(now) {
+			note = "  (scheduled)"
+		} else if current {
+			note = "  (current)"
+			current = false
+		}
+		s += subtitleStyle.Render(fmt.Sprintf("    from %-14s %s%s",
+			formatLongDate(rate.EffectiveFrom), formatRate(rate.HourlyRate), note)) + "\n"
+	}
+	s += subtitleStyle.Render("  A new rate applies from today; entries keep the rate they were logged at.") + "\n"
 
 	return s
 }
@@ -439,29 +772,14 @@ func (m *ClientsModel) viewList() string {
 	}
 	s += titleStyle.Render(header) + "\n\n"
 
-	// Status message
-	if m.statusMsg != "" {
-		s += lipgloss.NewStyle().Foreground(successColor).
-			Render("  "+m.statusMsg) + "\n\n"
-	}
+	s += m.list.View()
 
-	if len(m.clients) == 0 {
-		s += subtitleStyle.Render("  No clients yet. Press 'n' to add one.") + "\n"
-		s += subtitleStyle.Render("  Press 'h' to toggle archived clients") + "\n"
-		return s
-	}
-
-	for i, client := range m.clients {
-		s += m.renderClient(i, client) + "\n"
-	}
-
-	s += "\n" + helpStyle.Render("  j/k: navigate  n: new  enter: edit  a: archive/unarchive  h: toggle archived")
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
 
-func (m *ClientsModel) renderClient(index int, client *domain.Client) string {
-	selected := index == m.cursor
+func (m *ClientsModel) renderClient(client *domain.Client, selected bool) string {
 
 	// Name
 	name := client.Name
@@ -469,18 +787,30 @@ func (m *ClientsModel) renderClient(index int, client *domain.Client) string {
 		name += " (archived)"
 	}
 
-	// Rate
-	rate := fmt.Sprintf("$%.0f/hr", client.HourlyRate)
+	// Rate and any billing rules
+	rate := formatRate(client.HourlyRate)
+	if !client.Billing.IsZero() {
+		rate += " (" + client.Billing.Describe() + ")"
+	}
+	if client.ReverseCharge {
+		rate += " (reverse charge)"
+	}
+	if client.RequireApproval {
+		rate += " (approval required)"
+	}
+	if client.Language != "" {
+		rate += " (invoices in " + client.Language + ")"
+	}
 
 	// Monthly stats
 	stats := m.monthlyStats[client.ID]
 	hours := 0.0
-	value := 0.0
+	var value domain.Money
 	if stats != nil {
 		hours = stats.hours
 		value = stats.value
 	}
-	monthly := fmt.Sprintf("This month: %s  %s", formatHours(hours), formatMoney(value))
+	monthly := fmt.Sprintf("This month: %s  %s", formatHours(hours), formatMoney(value.Float()))
 
 	// Contact
 	contact := client.Email
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// confirmDialog asks before an action that is hard to undo, the same way on
+// every screen: the confirm key (y) goes ahead, n or cancel (esc) backs out,
+// and other keys are ignored so a stray keypress neither confirms nor loses
+// the question. A screen opens it with Ask, passes it keys while Active, and
+// shows its View in place of the screen.
+type confirmDialog struct {
+	active   bool
+	title    string
+	details  []string // what the action affects, one line each
+	question string
+	onYes    func() tea.Cmd
+}
+
+// Ask opens the dialog. onYes runs when the action is confirmed, and the
+// command it returns is run in turn.
+func (d *confirmDialog) Ask(title, question string, details []string, onYes func() tea.Cmd) {
+	*d = confirmDialog{active: true, title: title, details: details, question: question, onYes: onYes}
+}
+
+// Active reports whether the dialog is waiting for an answer
+func (d *confirmDialog) Active() bool {
+	return d.active
+}
+
+// Close dismisses the dialog without an answer, e.g. when the screen is
+// sent elsewhere
+func (d *confirmDialog) Close() {
+	d.active = false
+}
+
+// Update answers the dialog
+func (d *confirmDialog) Update(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Confirm):
+		d.active = false
+		return d.onYes()
+	case key.Matches(msg, denyKey()):
+		d.active = false
+	}
+	return nil
+}
+
+// View renders the question with what it affects
+func (d *confirmDialog) View() string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(d.title) + "\n\n")
+	for _, line := range d.details {
+		s.WriteString("  " + line + "\n")
+	}
+	if len(d.details) > 0 {
+		s.WriteString("\n")
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(warningColor).Render("  "+d.question+" (y/n)") + "\n\n")
+	s.WriteString(renderKeyHelp(d.KeyHelp()...) + "\n")
+	return s.String()
+}
+
+// KeyHelp lists the answers
+func (d *confirmDialog) KeyHelp() []key.Binding {
+	return []key.Binding{DefaultKeyMap.Confirm, denyKey()}
+}
+
+// denyKey backs out of a confirmation: n, or the cancel key
+func denyKey() key.Binding {
+	cancel := DefaultKeyMap.Cancel
+	keys := append([]string{"n"}, cancel.Keys()...)
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp("n/"+cancel.Help().Key, "cancel"))
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/andy/timesink/internal/app"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/service"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -19,16 +22,28 @@ type DashboardModel struct {
 	// Data
 	weekTotalHours    float64
 	weekBillableHours float64
-	weekTotalValue    float64
+	weekTotalValue    domain.Money
 	todayTotalHours   float64
-	todayTotalValue   float64
-	outstanding       float64
-	unbilled          float64
+	todayTotalValue   domain.Money
+	outstanding       domain.Money
+	unbilled          domain.Money
 	activeTimer       *domain.ActiveTimer
 	activeClient      *domain.Client
 	recentEntries     []*domain.TimeEntry
+	endingContracts   []*domain.Contract
+	shortDays         []service.ShortDay
+	toInvoice         []*service.BillingReminder
 	clientCache       map[int64]*domain.Client
 
+	// Selected row: the outstanding amount, then each recent entry
+	cursor int
+
+	// Quick log line, e.g. "2h Acme code review", and why the last one
+	// typed could not be saved
+	quickLogging bool
+	quickInput   textinput.Model
+	quickErr     error
+
 	loading bool
 	err     error
 }
@@ -36,18 +51,28 @@ type DashboardModel struct {
 type dashboardDataMsg struct {
 	weekTotalHours    float64
 	weekBillableHours float64
-	weekTotalValue    float64
+	weekTotalValue    domain.Money
 	todayTotalHours   float64
-	todayTotalValue   float64
-	outstanding       float64
-	unbilled          float64
+	todayTotalValue   domain.Money
+	outstanding       domain.Money
+	unbilled          domain.Money
 	activeTimer       *domain.ActiveTimer
 	activeClient      *domain.Client
 	recentEntries     []*domain.TimeEntry
+	endingContracts   []*domain.Contract
+	shortDays         []service.ShortDay
+	toInvoice         []*service.BillingReminder
 	clientCache       map[int64]*domain.Client
 	err               error
 }
 
+// quickLoggedMsg reports the entry saved from the quick log line
+type quickLoggedMsg struct {
+	entry  *domain.TimeEntry
+	client *domain.Client
+	err    error
+}
+
 // NewDashboardModel creates a new dashboard model
 func NewDashboardModel(a *app.App) tea.Model {
 	return &DashboardModel{
@@ -57,77 +82,142 @@ func NewDashboardModel(a *app.App) tea.Model {
 	}
 }
 
+// IsCapturingInput returns true while a quick log line is being typed
+func (m *DashboardModel) IsCapturingInput() bool {
+	return m.quickLogging
+}
+
+// OverridesKey claims the quick log key, which is quit on other screens
+func (m *DashboardModel) OverridesKey(msg tea.KeyMsg) bool {
+	return key.Matches(msg, DefaultKeyMap.QuickLog)
+}
+
+// KeyHelp lists the dashboard's keys
+func (m *DashboardModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.quickLogging {
+		return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+	}
+	if m.cursor == 0 {
+		return []key.Binding{navigateKeys(), withHelp(k.Select, "unpaid invoices"), k.QuickLog}
+	}
+	return []key.Binding{navigateKeys(), withHelp(k.Select, "show entry"), k.QuickLog}
+}
+
+// recentLimit is the number of recent entries listed
+const recentLimit = 8
+
+// recent returns the entries listed under Recent Entries, most recent first
+func (m *DashboardModel) recent() []*domain.TimeEntry {
+	sorted := make([]*domain.TimeEntry, len(m.recentEntries))
+	copy(sorted, m.recentEntries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartTime.After(sorted[j].StartTime)
+	})
+	return sorted[:min(len(sorted), recentLimit)]
+}
+
 func (m *DashboardModel) Init() tea.Cmd {
 	return m.loadData()
 }
 
 func (m *DashboardModel) loadData() tea.Cmd {
+	key := "dashboard:" + today()
 	return func() tea.Msg {
-		ctx := context.Background()
-		msg := dashboardDataMsg{
-			clientCache: make(map[int64]*domain.Client),
-		}
+		msg, _ := cached(screenData, key, func() (dashboardDataMsg, error) {
+			msg := m.fetchData()
+			return msg, msg.err
+		})
+		return msg
+	}
+}
 
-		now := time.Now()
+func (m *DashboardModel) fetchData() dashboardDataMsg {
+	ctx := context.Background()
+	msg := dashboardDataMsg{}
 
-		// Week start (Monday)
-		weekStart := now
-		for weekStart.Weekday() != time.Monday {
-			weekStart = weekStart.AddDate(0, 0, -1)
-		}
-		weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		msg.err = fmt.Errorf("clients: %w", err)
+		return msg
+	}
+	msg.clientCache = clients
 
-		// Load week summary
-		weekSummary, err := m.app.ReportService.GetWeekSummary(ctx, weekStart)
-		if err != nil {
-			msg.err = fmt.Errorf("week summary: %w", err)
-			return msg
-		}
-		msg.weekTotalHours = weekSummary.TotalHours
-		msg.weekBillableHours = weekSummary.BillableHours
-		msg.weekTotalValue = weekSummary.TotalValue
+	now := time.Now()
 
-		// Load today summary
-		dailySummary, err := m.app.ReportService.GetDailySummary(ctx, now)
-		if err != nil {
-			msg.err = fmt.Errorf("daily summary: %w", err)
-			return msg
-		}
-		msg.todayTotalHours = dailySummary.TotalHours
-		msg.todayTotalValue = dailySummary.TotalValue
-
-		// Financial totals
-		msg.outstanding, _ = m.app.ReportService.GetOutstandingTotal(ctx)
-		msg.unbilled, _ = m.app.ReportService.GetUnbilledTotal(ctx)
-
-		// Active timer
-		activeTimer, err := m.app.TimerService.GetActiveTimer(ctx)
-		if err == nil && activeTimer != nil {
-			msg.activeTimer = activeTimer
-			client, err := m.app.ClientRepo.GetByID(ctx, activeTimer.ClientID)
-			if err == nil {
-				msg.activeClient = client
-				msg.clientCache[client.ID] = client
-			}
-		}
+	// Week start (Monday)
+	weekStart := now
+	for weekStart.Weekday() != time.Monday {
+		weekStart = weekStart.AddDate(0, 0, -1)
+	}
+	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+
+	// Load week summary
+	weekSummary, err := m.app.ReportService.GetWeekSummary(ctx, weekStart)
+	if err != nil {
+		msg.err = fmt.Errorf("week summary: %w", err)
+		return msg
+	}
+	msg.weekTotalHours = weekSummary.TotalHours
+	msg.weekBillableHours = weekSummary.BillableHours
+	msg.weekTotalValue = weekSummary.TotalValue
+
+	// Load today summary
+	dailySummary, err := m.app.ReportService.GetDailySummary(ctx, now)
+	if err != nil {
+		msg.err = fmt.Errorf("daily summary: %w", err)
+		return msg
+	}
+	msg.todayTotalHours = dailySummary.TotalHours
+	msg.todayTotalValue = dailySummary.TotalValue
+
+	// Financial totals
+	msg.outstanding, _ = m.app.ReportService.GetOutstandingTotal(ctx)
+	msg.unbilled, _ = m.app.ReportService.GetUnbilledTotal(ctx)
+
+	// Active timer
+	activeTimer, err := m.app.TimerService.GetActiveTimer(ctx)
+	if err == nil && activeTimer != nil {
+		msg.activeTimer = activeTimer
+		msg.activeClient = clients[activeTimer.ClientID]
+	}
 
-		// Recent entries (last 7 days)
-		sevenDaysAgo := now.AddDate(0, 0, -7)
-		entries, err := m.app.EntryRepo.List(ctx, nil, &sevenDaysAgo, &now, true)
-		if err == nil {
-			msg.recentEntries = entries
-			for _, entry := range entries {
-				if _, ok := msg.clientCache[entry.ClientID]; !ok {
-					client, err := m.app.ClientRepo.GetByID(ctx, entry.ClientID)
-					if err == nil {
-						msg.clientCache[entry.ClientID] = client
-					}
-				}
+	// Contracts ending soon
+	contracts, err := m.app.ContractRepo.List(ctx, nil)
+	if err == nil {
+		for _, c := range contracts {
+			if !c.EndsWithin(now, domain.ContractWarningDays) {
+				continue
 			}
+			msg.endingContracts = append(msg.endingContracts, c)
 		}
+	}
 
-		return msg
+	// Weekdays in the last week that ended below the daily target
+	workday := m.app.Config.Workday
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	shortDays, err := m.app.ReportService.GetShortDays(ctx, today.AddDate(0, 0, -7), today,
+		workday.TargetHours, workday.DayOffWords)
+	if err == nil {
+		msg.shortDays = shortDays
+	}
+
+	// Clients with unbilled time worth invoicing
+	invoiceCfg := m.app.Config.Invoice
+	toInvoice, err := m.app.ReportService.GetBillingReminders(ctx, now,
+		domain.MoneyFromFloat(invoiceCfg.RemindUnbilled), invoiceCfg.RemindAfterDays)
+	if err == nil {
+		msg.toInvoice = toInvoice
+	}
+
+	// Recent entries (last 7 days)
+	sevenDaysAgo := now.AddDate(0, 0, -7)
+	entries, err := m.app.EntryRepo.List(ctx, nil, &sevenDaysAgo, &now, true)
+	if err == nil {
+		msg.recentEntries = entries
 	}
+
+	return msg
 }
 
 func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -145,7 +235,11 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.activeTimer = msg.activeTimer
 		m.activeClient = msg.activeClient
 		m.recentEntries = msg.recentEntries
+		m.endingContracts = msg.endingContracts
+		m.shortDays = msg.shortDays
+		m.toInvoice = msg.toInvoice
 		m.clientCache = msg.clientCache
+		m.cursor = min(m.cursor, len(m.recent()))
 		if m.activeTimer != nil {
 			return m, tickTimer()
 		}
@@ -166,11 +260,117 @@ func (m *DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case RefreshDataMsg:
 		m.loading = true
 		return m, m.loadData()
+
+	case quickLoggedMsg:
+		if msg.err != nil {
+			m.quickErr = msg.err
+			return m, nil
+		}
+		m.quickLogging = false
+		m.quickErr = nil
+		return m, tea.Batch(m.loadData(), notify(NotifySuccess, fmt.Sprintf("Logged %s for %s",
+			formatHours(msg.entry.Duration().Hours()), msg.client.Name)))
+
+	case tea.KeyMsg:
+		// The quick log line intercepts all keys
+		if m.quickLogging {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
+				return m, m.quickLog(m.quickInput.Value())
+			case key.Matches(msg, DefaultKeyMap.Cancel):
+				m.quickLogging = false
+				m.quickErr = nil
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.quickInput, cmd = m.quickInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		if key.Matches(msg, DefaultKeyMap.QuickLog) {
+			ti := textinput.New()
+			ti.Placeholder = "2h Acme code review"
+			ti.CharLimit = 200
+			ti.Width = 50
+			m.quickInput = ti
+			m.quickLogging = true
+			m.quickErr = nil
+			return m, m.quickInput.Focus()
+		}
+
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, DefaultKeyMap.Down):
+			if m.cursor < len(m.recent()) {
+				m.cursor++
+			}
+		case key.Matches(msg, DefaultKeyMap.Select):
+			return m, m.jump()
+		}
 	}
 
 	return m, nil
 }
 
+// quickLog saves the entry described by line, ending now, at the client's
+// current rate
+func (m *DashboardModel) quickLog(line string) tea.Cmd {
+	clients := make([]*domain.Client, 0, len(m.clientCache))
+	for _, c := range m.clientCache {
+		clients = append(clients, c)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Name < clients[j].Name })
+
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		quick, err := domain.ParseQuickEntry(line, clients)
+		if err != nil {
+			return quickLoggedMsg{err: err}
+		}
+
+		end := time.Now().Truncate(time.Minute)
+		start := end.Add(-quick.Duration)
+		rate, err := m.app.ClientRepo.RateAt(ctx, quick.Client.ID, start)
+		if err != nil {
+			return quickLoggedMsg{err: err}
+		}
+
+		entry := domain.NewTimeEntry(quick.Client.ID, quick.Description, rate)
+		entry.StartTime = start
+		entry.Location = m.app.Location()
+		entry.Stop(end)
+		if err := m.app.EntryRepo.Create(ctx, entry); err != nil {
+			return quickLoggedMsg{err: err}
+		}
+		return quickLoggedMsg{entry: entry, client: quick.Client}
+	}
+}
+
+// jump opens the screen behind the selected row: unpaid invoices for the
+// outstanding amount, or the entries screen at the selected entry
+func (m *DashboardModel) jump() tea.Cmd {
+	if m.cursor == 0 {
+		return func() tea.Msg {
+			return SwitchScreenMsg{Screen: ScreenInvoices, Select: filterInvoicesMsg{
+				statuses: []domain.InvoiceStatus{domain.InvoiceStatusSent, domain.InvoiceStatusOverdue},
+			}}
+		}
+	}
+	recent := m.recent()
+	if m.cursor > len(recent) {
+		return nil
+	}
+	entry := recent[m.cursor-1]
+	return func() tea.Msg {
+		return SwitchScreenMsg{Screen: ScreenEntries, Select: selectEntryMsg{id: entry.ID}}
+	}
+}
+
 func (m *DashboardModel) View() string {
 	if m.loading {
 		return "Loading dashboard..."
@@ -184,12 +384,16 @@ func (m *DashboardModel) View() string {
 	var s string
 
 	// Summary boxes
+	outstanding := "Outstanding:  " + formatMoney(m.outstanding.Float())
+	if m.cursor == 0 {
+		outstanding = selectedStyle.Render(outstanding)
+	}
 	summaryLeft := fmt.Sprintf(
-		"  This Week:  %-12s  Billable:     %s\n  Today:      %-12s  Outstanding:  %s",
+		"  This Week:  %-12s  Billable:     %s\n  Today:      %-12s  %s",
 		formatHours(m.weekTotalHours),
-		formatMoney(m.weekTotalValue),
+		formatMoney(m.weekTotalValue.Float()),
 		formatHours(m.todayTotalHours),
-		formatMoney(m.outstanding),
+		outstanding,
 	)
 	s += summaryLeft + "\n"
 
@@ -201,9 +405,32 @@ func (m *DashboardModel) View() string {
 		s += subtitleStyle.Render("  No active timer") + "\n"
 	}
 
+	// Contract warnings
+	if len(m.endingContracts) > 0 {
+		s += "\n" + m.renderEndingContracts()
+	}
+
+	// Days below target
+	if len(m.shortDays) > 0 {
+		s += "\n" + m.renderShortDays()
+	}
+
+	// Clients due an invoice
+	if len(m.toInvoice) > 0 {
+		s += "\n" + m.renderToInvoice()
+	}
+
 	// Recent entries
 	s += "\n" + m.renderRecentEntries()
 
+	if m.quickLogging {
+		s += fmt.Sprintf("\n  Log: %s\n", m.quickInput.View())
+		if m.quickErr != nil {
+			s += lipgloss.NewStyle().Foreground(errorColor).Render(fmt.Sprintf("  %v", m.quickErr)) + "\n"
+		}
+	}
+	s += "\n" + renderKeyHelp(m.KeyHelp()...) + "\n"
+
 	return s
 }
 
@@ -240,21 +467,8 @@ func (m *DashboardModel) renderRecentEntries() string {
 		return header + subtitleStyle.Render("  No recent entries") + "\n"
 	}
 
-	// Sort most recent first
-	sorted := make([]*domain.TimeEntry, len(m.recentEntries))
-	copy(sorted, m.recentEntries)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i].StartTime.After(sorted[j].StartTime)
-	})
-
 	s := header
-	limit := 8
-	if len(sorted) < limit {
-		limit = len(sorted)
-	}
-
-	for i := 0; i < limit; i++ {
-		entry := sorted[i]
+	for i, entry := range m.recent() {
 		clientName := fmt.Sprintf("Client #%d", entry.ClientID)
 		if c, ok := m.clientCache[entry.ClientID]; ok {
 			clientName = c.Name
@@ -263,13 +477,83 @@ func (m *DashboardModel) renderRecentEntries() string {
 		hours := entry.Duration().Hours()
 		desc := truncateStr(entry.Description, 30)
 
-		s += fmt.Sprintf("  %-7s %-20s %6s  %s\n",
-			entry.StartTime.Format("Jan 2"),
+		line := fmt.Sprintf("  %-7s %-20s %6s  %s",
+			formatShortDate(entry.StartTime),
 			truncateStr(clientName, 20),
 			formatHours(hours),
 			desc,
 		)
+		if i+1 == m.cursor {
+			s += selectedStyle.Render(line) + "\n"
+		} else {
+			s += line + "\n"
+		}
 	}
 
 	return s
 }
+
+// renderEndingContracts warns about contracts whose last day is near, soonest
+// first. Contracts that renew automatically are listed but not highlighted.
+func (m *DashboardModel) renderEndingContracts() string {
+	s := lapsedStyle.Render(fmt.Sprintf("  Contracts Ending Within %d Days", domain.ContractWarningDays)) + "\n"
+
+	now := time.Now()
+	for _, c := range m.endingContracts {
+		clientName := fmt.Sprintf("Client #%d", c.ClientID)
+		if client, ok := m.clientCache[c.ClientID]; ok {
+			clientName = client.Name
+		}
+
+		when := "today"
+		if days := c.DaysLeft(now); days == 1 {
+			when = "tomorrow"
+		} else if days > 1 {
+			when = fmt.Sprintf("in %d days", days)
+		}
+
+		line := fmt.Sprintf("  %-20s ends %-12s %s", truncateStr(clientName, 20), formatShortDate(c.EndDate), when)
+		if c.Renews {
+			s += subtitleStyle.Render(line+" (renews)") + "\n"
+		} else {
+			s += line + "\n"
+		}
+	}
+
+	return s
+}
+
+// renderShortDays warns about recent weekdays logged below the daily target
+// that no entry explains
+func (m *DashboardModel) renderShortDays() string {
+	target := m.app.Config.Workday.TargetHours
+	s := lapsedStyle.Render(fmt.Sprintf("  Below Target (%s/day)", formatHours(target))) + "\n"
+	for _, d := range m.shortDays {
+		s += fmt.Sprintf("  %s %-7s %6s  %s short\n", d.Date.Weekday().String()[:3], formatShortDate(d.Date),
+			formatHours(d.Hours), formatHours(target-d.Hours))
+	}
+	if words := m.app.Config.Workday.DayOffWords; len(words) > 0 {
+		s += subtitleStyle.Render(fmt.Sprintf("  Log an entry mentioning %q to explain a day", words[0])) + "\n"
+	}
+	return s
+}
+
+// renderToInvoice nags about clients whose unbilled time is large or has
+// gone uninvoiced for too long, largest amount first
+func (m *DashboardModel) renderToInvoice() string {
+	s := lapsedStyle.Render("  Due an Invoice") + "\n"
+	for _, r := range m.toInvoice {
+		clientName := fmt.Sprintf("Client #%d", r.ClientID)
+		if c, ok := m.clientCache[r.ClientID]; ok {
+			clientName = c.Name
+		}
+
+		since := fmt.Sprintf("never invoiced, first unbilled %d days ago", r.Days)
+		if r.LastInvoiced != nil {
+			since = fmt.Sprintf("last invoiced %d days ago", r.Days)
+		}
+		s += fmt.Sprintf("  %-20s %12s  %s\n", truncateStr(clientName, 20), formatMoney(r.Unbilled.Float()), since)
+	}
+	s += subtitleStyle.Render(fmt.Sprintf("  Press '%s' to generate invoices", DefaultKeyMap.Invoices.Help().Key)) + "\n"
+	return s
+}
@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dateRange is a span of whole days, both ends included
+type dateRange struct {
+	start, end time.Time // midnight of the first and last day
+	name       string    // the preset it came from; "" when picked on the calendar
+}
+
+// until is the midnight after the last day, for queries that take an
+// exclusive end
+func (r dateRange) until() time.Time {
+	return r.end.AddDate(0, 0, 1)
+}
+
+// String names the range by its preset, or by its first and last day
+func (r dateRange) String() string {
+	if r.name != "" {
+		return r.name
+	}
+	return formatShortDate(r.start) + " - " + formatLongDate(r.end)
+}
+
+// asOf moves a preset's range to today, so a screen left open past midnight
+// still shows e.g. the last four weeks. Days picked on the calendar stay put.
+func (r dateRange) asOf(today time.Time) dateRange {
+	for _, p := range dateRangePresets {
+		if p.name == r.name {
+			return p.rangeOn(today)
+		}
+	}
+	return r
+}
+
+// dateRangePreset is a range relative to today, e.g. last month
+type dateRangePreset struct {
+	name string
+	span func(today time.Time) (start, end time.Time)
+}
+
+// rangeOn is the preset's range as of today
+func (p dateRangePreset) rangeOn(today time.Time) dateRange {
+	start, end := p.span(today)
+	return dateRange{start: start, end: end, name: p.name}
+}
+
+// lastWeeks is the n weeks up to and including today
+func lastWeeks(n int) dateRangePreset {
+	return dateRangePreset{
+		name: fmt.Sprintf("Last %d weeks", n),
+		span: func(today time.Time) (time.Time, time.Time) { return today.AddDate(0, 0, 1-7*n), today },
+	}
+}
+
+// dateRangePresets are the ranges a picker offers before its calendar
+var dateRangePresets = []dateRangePreset{
+	lastWeeks(4),
+	lastWeeks(12),
+	lastWeeks(26),
+	lastWeeks(52),
+	{"This week", func(today time.Time) (time.Time, time.Time) {
+		return weekMonday(today), weekMonday(today).AddDate(0, 0, 6)
+	}},
+	{"Last week", func(today time.Time) (time.Time, time.Time) {
+		return weekMonday(today).AddDate(0, 0, -7), weekMonday(today).AddDate(0, 0, -1)
+	}},
+	{"This month", func(today time.Time) (time.Time, time.Time) {
+		first := firstOfMonth(today)
+		return first, first.AddDate(0, 1, -1)
+	}},
+	{"Last month", func(today time.Time) (time.Time, time.Time) {
+		first := firstOfMonth(today)
+		return first.AddDate(0, -1, 0), first.AddDate(0, 0, -1)
+	}},
+	{"Year to date", func(today time.Time) (time.Time, time.Time) {
+		return time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location()), today
+	}},
+	{"Last year", func(today time.Time) (time.Time, time.Time) {
+		first := time.Date(today.Year()-1, time.January, 1, 0, 0, 0, 0, today.Location())
+		return first, first.AddDate(1, 0, -1)
+	}},
+}
+
+// Arrow keys move within a pane, so tab alone switches between the presets
+// and the calendar
+var rangePaneKey = key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("tab", "presets/calendar"))
+
+// dateRangePicker picks a range of days from the keyboard, for the screens
+// that report or filter by date. The presets list common ranges, such as
+// last month, and enter takes one. On the calendar the arrow keys or h/j/k/l
+// move a day or a week and [ ] a month; enter marks the first day, then the
+// last. Screens show it with View, pass it keys with Update, and treat esc
+// as going back.
+type dateRangePicker struct {
+	title   string
+	current dateRange // the range when the picker opened
+
+	onCalendar bool
+	preset     int       // highlighted preset
+	cursor     time.Time // highlighted day
+	anchor     time.Time // first day once marked; zero before
+}
+
+// newDateRangePicker opens on the preset current came from, or on the
+// calendar at its last day
+func newDateRangePicker(title string, current dateRange) dateRangePicker {
+	p := dateRangePicker{title: title, current: current, cursor: current.end, onCalendar: true}
+	for i, preset := range dateRangePresets {
+		if preset.name == current.name {
+			p.preset, p.onCalendar = i, false
+		}
+	}
+	return p
+}
+
+// Update moves the highlight or marks a day. It returns the picked range and
+// true once a preset is taken or the last day is marked.
+func (p *dateRangePicker) Update(msg tea.KeyMsg) (dateRange, bool) {
+	k := DefaultKeyMap
+	if key.Matches(msg, rangePaneKey) {
+		p.onCalendar = !p.onCalendar
+		return dateRange{}, false
+	}
+
+	if !p.onCalendar {
+		switch {
+		case key.Matches(msg, k.Up):
+			p.preset = max(p.preset-1, 0)
+		case key.Matches(msg, k.Down):
+			p.preset = min(p.preset+1, len(dateRangePresets)-1)
+		case key.Matches(msg, k.Select):
+			return dateRangePresets[p.preset].rangeOn(midnight(time.Now())), true
+		}
+		return dateRange{}, false
+	}
+
+	switch {
+	case key.Matches(msg, k.Left):
+		p.cursor = p.cursor.AddDate(0, 0, -1)
+	case key.Matches(msg, k.Right):
+		p.cursor = p.cursor.AddDate(0, 0, 1)
+	case key.Matches(msg, k.Up):
+		p.cursor = p.cursor.AddDate(0, 0, -7)
+	case key.Matches(msg, k.Down):
+		p.cursor = p.cursor.AddDate(0, 0, 7)
+	case key.Matches(msg, k.PrevMonth):
+		p.cursor = p.cursor.AddDate(0, -1, 0)
+	case key.Matches(msg, k.NextMonth):
+		p.cursor = p.cursor.AddDate(0, 1, 0)
+	case key.Matches(msg, k.Select):
+		if p.anchor.IsZero() {
+			p.anchor = p.cursor
+			return dateRange{}, false
+		}
+		start, end := p.anchor, p.cursor
+		if end.Before(start) {
+			start, end = end, start
+		}
+		return dateRange{start: start, end: end}, true
+	}
+	return dateRange{}, false
+}
+
+// shown is the range highlighted on the calendar: from the marked day to the
+// cursor while picking, otherwise the preset or current range
+func (p *dateRangePicker) shown() dateRange {
+	switch {
+	case !p.onCalendar:
+		return dateRangePresets[p.preset].rangeOn(midnight(time.Now()))
+	case p.anchor.IsZero():
+		return p.current
+	case p.cursor.Before(p.anchor):
+		return dateRange{start: p.cursor, end: p.anchor}
+	}
+	return dateRange{start: p.anchor, end: p.cursor}
+}
+
+// View renders the presets beside the month of the highlighted day, with the
+// range that would be picked below
+func (p *dateRangePicker) View() string {
+	r := p.shown()
+
+	var presets strings.Builder
+	for i, preset := range dateRangePresets {
+		line := "  " + preset.name
+		if !p.onCalendar && i == p.preset {
+			line = selectedStyle.Render("> " + preset.name)
+		}
+		presets.WriteString(line + "\n")
+	}
+
+	month := p.cursor
+	if !p.onCalendar {
+		month = r.end
+	}
+
+	var s string
+	s += titleStyle.Render(p.title) + "\n\n"
+	s += lipgloss.JoinHorizontal(lipgloss.Top, strings.TrimSuffix(presets.String(), "\n"), "    ", p.viewMonth(month, r)) + "\n"
+	s += fmt.Sprintf("  %s to %s (%d days)\n", formatLongDate(r.start), formatLongDate(r.end), int(r.until().Sub(r.start).Hours()/24+0.5))
+	if p.onCalendar {
+		step := "mark the first day"
+		if !p.anchor.IsZero() {
+			step = "mark the last day"
+		}
+		s += subtitleStyle.Render("  enter: "+step) + "\n"
+	}
+	s += "\n" + renderKeyHelp(p.KeyHelp()...) + "\n"
+	return s
+}
+
+// viewMonth draws the month containing day, Monday first, with the days of r
+// highlighted and the cursor marked while on the calendar
+func (p *dateRangePicker) viewMonth(day time.Time, r dateRange) string {
+	first := firstOfMonth(day)
+	inRange := lipgloss.NewStyle().Foreground(primaryColor)
+	cursor := lipgloss.NewStyle().Reverse(true)
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Render(first.Format("January 2006")) + "\n")
+	s.WriteString(subtitleStyle.Render("Mo Tu We Th Fr Sa Su") + "\n")
+	s.WriteString(strings.Repeat("   ", int(first.Weekday()+6)%7))
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", d.Day())
+		switch {
+		case p.onCalendar && d.Equal(p.cursor):
+			cell = cursor.Render(cell)
+		case !d.Before(r.start) && !d.After(r.end):
+			cell = inRange.Render(cell)
+		}
+		s.WriteString(cell)
+		if d.Weekday() == time.Sunday {
+			s.WriteString("\n")
+		} else {
+			s.WriteString(" ")
+		}
+	}
+	return strings.TrimRight(s.String(), " \n")
+}
+
+// KeyHelp lists the keys for the focused pane
+func (p *dateRangePicker) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if !p.onCalendar {
+		return []key.Binding{navigateKeys(), withHelp(k.Select, "pick"), rangePaneKey, k.Cancel}
+	}
+	move := key.NewBinding(key.WithKeys("left", "right", "up", "down"), key.WithHelp("←↑↓→", "day/week"))
+	return []key.Binding{move, k.PrevMonth, k.NextMonth, withHelp(k.Select, "mark day"), rangePaneKey, k.Cancel}
+}
+
+// midnight is the start of t's day
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// firstOfMonth is midnight on the first day of t's month
+func firstOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
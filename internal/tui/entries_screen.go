@@ -3,7 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/app"
@@ -18,10 +18,12 @@ type entryMode int
 
 const (
 	entryModeList          entryMode = iota
-	entryModePickClient              // cursor-based client selection
+	entryModePickClient              // type-to-filter client selection
 	entryModeNew                     // text input form for entry details
-	entryModeConfirmDelete           // y/n confirmation before delete
+	entryModeConfirmDelete           // deleting, as the action a reason is asked for
 	entryModeEditDesc                // inline description editing
+	entryModeSplit                   // split time input
+	entryModeReason                  // reason prompt before saving an edit or delete
 )
 
 // entry form field indices (after client is selected)
@@ -31,36 +33,66 @@ const (
 	entryFieldEndTime
 	entryFieldDescription
 	entryFieldRate
+	entryFieldRateReason
+	entryFieldLocation
+	entryFieldBillable
 	entryFieldCount
 )
 
+// entriesListChrome is the number of lines in the list view that are not
+// entry rows: title, status, summary, column header, scroll indicators,
+// totals, the selected entry's rate note, and help.
+const entriesListChrome = 13
+
 // EntriesModel displays a scrollable list of time entries
 type EntriesModel struct {
 	app         *app.App
-	entries     []*domain.TimeEntry
+	list        listView[*domain.TimeEntry]
 	clientNames map[int64]string
-	cursor      int
-	offset      int
-	maxVisible  int
+	contracts   map[int64][]*domain.Contract // by client, for flagging lapsed contracts
+	clientRates map[int64]float64            // by entry, the client's rate on the entry's day
 	loading     bool
 	err         error
-	statusMsg   string
 
 	// Form state
-	mode        entryMode
-	fields      []textinput.Model
-	fieldFocus  int
-	formClients []*domain.Client
-	formClient  *domain.Client // selected client
-	clientCursor int
+	mode          entryMode
+	fields        []textinput.Model
+	fieldFocus    int
+	picker        clientPicker
+	formClient    *domain.Client // selected client
+	durationInput bool           // the end time field takes how long instead
 
 	// Inline description editing
 	descInput textinput.Model
+
+	// Split time input
+	splitInput textinput.Model
+
+	// Most recently deleted entry, for undo
+	lastDeletedID int64
+
+	// Entry to select once the list has loaded, when opened from the dashboard
+	selectID int64
+
+	// Invoice whose entries are listed instead of the last 30 days, when
+	// opened from an invoice
+	invoice *domain.Invoice
+
+	// Confirmation before deleting an entry
+	confirm confirmDialog
+
+	// Reason prompt for audited changes
+	reasonInput textinput.Model
+	reasonFor   entryMode // entryModeEditDesc or entryModeConfirmDelete
+	pendingDesc string
 }
 
 type entriesDataMsg struct {
+	invoiceID   int64 // the invoice the entries were listed for, if any
 	entries     []*domain.TimeEntry
 	clientNames map[int64]string
+	contracts   map[int64][]*domain.Contract
+	clientRates map[int64]float64
 	err         error
 }
 
@@ -74,6 +106,7 @@ type entryClientsMsg struct {
 }
 
 type entryDeletedMsg struct {
+	id  int64
 	err error
 }
 
@@ -81,19 +114,80 @@ type entryDescUpdatedMsg struct {
 	err error
 }
 
-// IsCapturingInput returns true when the text form or delete confirmation is active
+type entrySplitMsg struct {
+	err error
+}
+
+type entryRestoredMsg struct {
+	err error
+}
+
+// IsCapturingInput returns true when the client picker, text form or delete
+// confirmation is active
 func (m *EntriesModel) IsCapturingInput() bool {
-	return m.mode == entryModeNew || m.mode == entryModeConfirmDelete || m.mode == entryModeEditDesc
+	return m.confirm.Active() || m.mode == entryModePickClient || m.mode == entryModeNew || m.mode == entryModeEditDesc ||
+		m.mode == entryModeSplit || m.mode == entryModeReason
+}
+
+// OverridesKey claims 't' in list mode to restart a timer from the selected entry
+func (m *EntriesModel) OverridesKey(msg tea.KeyMsg) bool {
+	return m.mode == entryModeList && key.Matches(msg, DefaultKeyMap.StartTimer) && m.list.Len() > 0
+}
+
+// KeyHelp lists the keys for the current mode
+func (m *EntriesModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.confirm.Active() {
+		return m.confirm.KeyHelp()
+	}
+	switch m.mode {
+	case entryModePickClient:
+		return pickerKeys("select")
+	case entryModeNew:
+		keys := formKeys()
+		keys[len(keys)-1] = withHelp(k.Cancel, "back")
+		if hasCompletion(m.fields[m.fieldFocus]) {
+			keys = append([]key.Binding{completionKey()}, keys...)
+		}
+		if m.durationInput {
+			return append(keys, withHelp(k.EntryDuration, "enter end time"))
+		}
+		return append(keys, k.EntryDuration)
+	case entryModeEditDesc:
+		if hasCompletion(m.descInput) {
+			return []key.Binding{completionKey(), withHelp(k.Select, "save"), k.Cancel}
+		}
+		return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+	case entryModeReason:
+		return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+	case entryModeSplit:
+		return []key.Binding{withHelp(k.Select, "split"), k.Cancel}
+	}
+	keys := []key.Binding{withHelp(k.New, "new entry")}
+	if m.list.Len() > 0 {
+		keys = []key.Binding{
+			navigateKeys(), withHelp(k.New, "new entry"), withHelp(k.Select, "edit desc"),
+			withHelp(k.StartTimer, "restart timer"), k.Split, k.Delete, k.Undo,
+		}
+	}
+	if m.invoice != nil {
+		keys = append(keys, withHelp(k.Back, "all entries"))
+	}
+	return keys
 }
 
 // NewEntriesModel creates a new entries screen model
 func NewEntriesModel(a *app.App) tea.Model {
-	return &EntriesModel{
+	m := &EntriesModel{
 		app:         a,
 		clientNames: make(map[int64]string),
-		maxVisible:  15,
 		loading:     true,
 	}
+	m.list = newListView(m.renderEntry)
+	m.list.SetHeader(fmt.Sprintf("     %-7s  %-20s  %6s  %10s  %s",
+		"Date", "Client", "Hours", "Amount", "Description"))
+	m.list.SetEmpty("No time entries yet. Press 'n' to add one.")
+	return m
 }
 
 func (m *EntriesModel) Init() tea.Cmd {
@@ -101,34 +195,75 @@ func (m *EntriesModel) Init() tea.Cmd {
 }
 
 func (m *EntriesModel) loadEntries() tea.Cmd {
+	key := "entries:" + today()
+	invoiceID := m.invoiceID()
+	if invoiceID != 0 {
+		key = fmt.Sprintf("entries:invoice:%d", invoiceID)
+	}
 	return func() tea.Msg {
-		ctx := context.Background()
+		msg, _ := cached(screenData, key, func() (entriesDataMsg, error) {
+			msg := m.fetchEntries(invoiceID)
+			return msg, msg.err
+		})
+		return msg
+	}
+}
 
+// invoiceID returns the ID of the invoice the list is filtered to, or 0
+func (m *EntriesModel) invoiceID() int64 {
+	if m.invoice == nil {
+		return 0
+	}
+	return m.invoice.ID
+}
+
+// fetchEntries lists the entries of an invoice, or of the last 30 days when
+// invoiceID is 0
+func (m *EntriesModel) fetchEntries(invoiceID int64) entriesDataMsg {
+	ctx := context.Background()
+
+	var entries []*domain.TimeEntry
+	var err error
+	if invoiceID != 0 {
+		entries, err = m.app.EntryRepo.ListByInvoice(ctx, invoiceID)
+	} else {
 		end := time.Now()
 		start := end.AddDate(0, 0, -30)
+		entries, err = m.app.EntryRepo.List(ctx, nil, &start, &end, true)
+	}
+	if err != nil {
+		return entriesDataMsg{invoiceID: invoiceID, err: err}
+	}
 
-		entries, err := m.app.EntryRepo.List(ctx, nil, &start, &end, true)
-		if err != nil {
-			return entriesDataMsg{err: err}
-		}
-
-		// Resolve client names
-		clientNames := make(map[int64]string)
-		for _, entry := range entries {
-			if _, ok := clientNames[entry.ClientID]; !ok {
-				client, err := m.app.ClientRepo.GetByID(ctx, entry.ClientID)
-				if err == nil && client != nil {
-					clientNames[entry.ClientID] = client.Name
-				} else {
-					clientNames[entry.ClientID] = fmt.Sprintf("Client #%d", entry.ClientID)
-				}
-			}
+	// Resolve client names
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		return entriesDataMsg{err: err}
+	}
+	clientNames := make(map[int64]string)
+	for _, entry := range entries {
+		if client, ok := clients[entry.ClientID]; ok {
+			clientNames[entry.ClientID] = client.Name
+		} else {
+			clientNames[entry.ClientID] = fmt.Sprintf("Client #%d", entry.ClientID)
 		}
+	}
 
-		return entriesDataMsg{
-			entries:     entries,
-			clientNames: clientNames,
-		}
+	contracts, err := m.app.ContractsByClient(ctx)
+	if err != nil {
+		return entriesDataMsg{err: err}
+	}
+	clientRates, err := m.app.ClientRatesOn(ctx, entries, clients)
+	if err != nil {
+		return entriesDataMsg{err: err}
+	}
+
+	return entriesDataMsg{
+		invoiceID:   invoiceID,
+		entries:     entries,
+		clientNames: clientNames,
+		contracts:   contracts,
+		clientRates: clientRates,
 	}
 }
 
@@ -176,20 +311,98 @@ func (m *EntriesModel) initForm() {
 	m.fields[entryFieldDescription].Placeholder = "What did you work on?"
 	m.fields[entryFieldDescription].CharLimit = 200
 	m.fields[entryFieldDescription].Width = 50
+	if m.formClient != nil {
+		suggestDescriptions(m.app, &m.fields[entryFieldDescription], m.formClient.ID)
+	}
 
-	// Hourly rate — pre-fill from selected client
+	// Hourly rate — left blank to use the client's rate on the entry's date
 	m.fields[entryFieldRate] = textinput.New()
-	m.fields[entryFieldRate].Placeholder = "150.00"
+	m.fields[entryFieldRate].Placeholder = "client rate"
 	m.fields[entryFieldRate].CharLimit = 10
 	m.fields[entryFieldRate].Width = 15
-	if m.formClient != nil {
-		m.fields[entryFieldRate].SetValue(fmt.Sprintf("%.2f", m.formClient.HourlyRate))
-	}
+
+	// Why the rate differs from the client's, required when it does
+	m.fields[entryFieldRateReason] = textinput.New()
+	m.fields[entryFieldRateReason].Placeholder = "e.g. weekend rush work"
+	m.fields[entryFieldRateReason].CharLimit = 200
+	m.fields[entryFieldRateReason].Width = 50
+
+	// Where the work was done, from timer.location unless changed
+	m.fields[entryFieldLocation] = textinput.New()
+	m.fields[entryFieldLocation].Placeholder = "e.g. on-site"
+	m.fields[entryFieldLocation].CharLimit = 100
+	m.fields[entryFieldLocation].Width = 30
+	m.fields[entryFieldLocation].SetValue(m.app.Location())
+
+	// Billable
+	m.fields[entryFieldBillable] = textinput.New()
+	m.fields[entryFieldBillable].Placeholder = "y"
+	m.fields[entryFieldBillable].CharLimit = 3
+	m.fields[entryFieldBillable].Width = 5
+	m.fields[entryFieldBillable].SetValue("y")
+
+	m.setDurationInput(m.durationInput)
 
 	m.fieldFocus = entryFieldDate
 	m.fields[entryFieldDate].Focus()
 }
 
+// setDurationInput makes the end time field take a clock time, or how long
+// the entry lasted with the start defaulting to workday.start
+func (m *EntriesModel) setDurationInput(on bool) {
+	m.durationInput = on
+	start, end := &m.fields[entryFieldStartTime], &m.fields[entryFieldEndTime]
+	if on {
+		start.Placeholder = m.workdayStart().Format("15:04")
+		end.Placeholder = "3.5h, 1h30m or 1:30"
+		end.CharLimit = 10
+		end.Width = 20
+		return
+	}
+	start.Placeholder = "09:00"
+	end.Placeholder = "17:00"
+	end.CharLimit = 5
+	end.Width = 10
+}
+
+// toggleDurationInput switches the end time field between a clock time and
+// a duration, converting what was typed when it can
+func (m *EntriesModel) toggleDurationInput() {
+	startField, endField := &m.fields[entryFieldStartTime], &m.fields[entryFieldEndTime]
+	start, startErr := time.Parse("15:04", strings.TrimSpace(startField.Value()))
+	value := strings.TrimSpace(endField.Value())
+
+	converted := ""
+	if m.durationInput {
+		if startErr != nil && strings.TrimSpace(startField.Value()) == "" {
+			start, startErr = m.workdayStart(), nil
+			startField.SetValue(start.Format("15:04"))
+		}
+		if d, err := domain.ParseDuration(value); err == nil && startErr == nil {
+			converted = start.Add(d).Format("15:04")
+		}
+	} else if end, err := time.Parse("15:04", value); err == nil && startErr == nil && end.After(start) {
+		d := end.Sub(start)
+		converted = fmt.Sprintf("%dh", int(d.Hours()))
+		if minutes := int(d.Minutes()) % 60; minutes > 0 {
+			converted += fmt.Sprintf("%dm", minutes)
+		}
+	}
+
+	m.setDurationInput(!m.durationInput)
+	endField.SetValue(converted)
+}
+
+// workdayStart is the clock time entries logged as a duration start at,
+// from workday.start
+func (m *EntriesModel) workdayStart() time.Time {
+	start, err := time.Parse("15:04", m.app.Config.Workday.Start)
+	if err != nil {
+		start, _ = time.Parse("15:04", "09:00")
+	}
+	return start
+}
+
 func (m *EntriesModel) saveEntry() tea.Cmd {
 	client := m.formClient
 	dateStr := m.fields[entryFieldDate].Value()
@@ -197,6 +410,13 @@ func (m *EntriesModel) saveEntry() tea.Cmd {
 	endStr := m.fields[entryFieldEndTime].Value()
 	desc := m.fields[entryFieldDescription].Value()
 	rateStr := m.fields[entryFieldRate].Value()
+	rateReason := m.fields[entryFieldRateReason].Value()
+	location := strings.TrimSpace(m.fields[entryFieldLocation].Value())
+	billableStr := strings.ToLower(strings.TrimSpace(m.fields[entryFieldBillable].Value()))
+	durationInput := m.durationInput
+	if durationInput && strings.TrimSpace(startStr) == "" {
+		startStr = m.workdayStart().Format("15:04")
+	}
 
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -215,22 +435,49 @@ func (m *EntriesModel) saveEntry() tea.Cmd {
 		startTime := time.Date(date.Year(), date.Month(), date.Day(),
 			startParts.Hour(), startParts.Minute(), 0, 0, time.Local)
 
-		// Parse end time
-		endParts, err := time.Parse("15:04", endStr)
-		if err != nil {
-			return entrySavedMsg{err: fmt.Errorf("invalid end time (use HH:MM): %s", endStr)}
+		// Parse end time, or work it out from how long the entry lasted
+		var endTime time.Time
+		if durationInput {
+			d, err := domain.ParseDuration(endStr)
+			if err != nil {
+				return entrySavedMsg{err: err}
+			}
+			endTime = startTime.Add(d)
+		} else {
+			endParts, err := time.Parse("15:04", endStr)
+			if err != nil {
+				return entrySavedMsg{err: fmt.Errorf("invalid end time (use HH:MM): %s", endStr)}
+			}
+			endTime = time.Date(date.Year(), date.Month(), date.Day(),
+				endParts.Hour(), endParts.Minute(), 0, 0, time.Local)
 		}
-		endTime := time.Date(date.Year(), date.Month(), date.Day(),
-			endParts.Hour(), endParts.Minute(), 0, 0, time.Local)
 
 		if !endTime.After(startTime) {
 			return entrySavedMsg{err: fmt.Errorf("end time must be after start time")}
 		}
 
-		// Parse rate
-		rate, err := strconv.ParseFloat(rateStr, 64)
-		if err != nil || rate < 0 {
-			return entrySavedMsg{err: fmt.Errorf("invalid hourly rate: %s", rateStr)}
+		// Parse rate, or use the client's rate in effect on that date
+		clientRate, err := m.app.ClientRepo.RateAt(ctx, client.ID, startTime)
+		if err != nil {
+			return entrySavedMsg{err: err}
+		}
+		rate := clientRate
+		if strings.TrimSpace(rateStr) != "" {
+			rate, err = activeLocale.ParseNumber(rateStr)
+			if err != nil || rate < 0 {
+				return entrySavedMsg{err: fmt.Errorf("invalid hourly rate: %s", rateStr)}
+			}
+		}
+
+		// Parse billable
+		var billable bool
+		switch billableStr {
+		case "y", "yes", "":
+			billable = true
+		case "n", "no":
+			billable = false
+		default:
+			return entrySavedMsg{err: fmt.Errorf("billable must be y or n: %s", billableStr)}
 		}
 
 		// Create entry
@@ -238,11 +485,14 @@ func (m *EntriesModel) saveEntry() tea.Cmd {
 			ClientID:    client.ID,
 			Description: desc,
 			StartTime:   startTime,
-			HourlyRate:  rate,
-			IsBillable:  true,
+			IsBillable:  billable,
+			Location:    location,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
+		if err := entry.SetRate(rate, clientRate, rateReason); err != nil {
+			return entrySavedMsg{err: err}
+		}
 		entry.Stop(endTime)
 
 		if err := m.app.EntryRepo.Create(ctx, entry); err != nil {
@@ -253,14 +503,53 @@ func (m *EntriesModel) saveEntry() tea.Cmd {
 	}
 }
 
-func (m *EntriesModel) deleteEntry(id int64) tea.Cmd {
+func (m *EntriesModel) deleteEntry(id int64, reason string) tea.Cmd {
 	return func() tea.Msg {
-		err := m.app.EntryRepo.SoftDelete(context.Background(), id, "deleted by user")
-		return entryDeletedMsg{err: err}
+		err := m.app.EntryRepo.SoftDelete(context.Background(), id, reason)
+		return entryDeletedMsg{id: id, err: err}
+	}
+}
+
+func (m *EntriesModel) updateDescription(entry *domain.TimeEntry, desc, reason string) tea.Cmd {
+	return func() tea.Msg {
+		// Edit a copy: the loaded entries are shared with the screen cache
+		updated := *entry
+		updated.Description = desc
+		updated.UpdatedAt = time.Now()
+		err := m.app.EntryRepo.Update(context.Background(), &updated, reason)
+		return entryDescUpdatedMsg{err: err}
+	}
+}
+
+// promptReason switches to the reason step for the given action
+func (m *EntriesModel) promptReason(action entryMode) tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = "Reason for change..."
+	if !m.app.Config.Audit.RequireReason {
+		ti.Placeholder = "Reason for change (optional)..."
+	}
+	ti.CharLimit = 200
+	ti.Width = 50
+	m.reasonInput = ti
+	m.reasonFor = action
+	m.mode = entryModeReason
+	return m.reasonInput.Focus()
+}
+
+func (m *EntriesModel) restoreEntry(id int64) tea.Cmd {
+	return func() tea.Msg {
+		err := m.app.EntryRepo.Restore(context.Background(), id, "undo delete")
+		return entryRestoredMsg{err: err}
 	}
 }
 
 func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Fit the list to the space the root model gives us
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.list.SetHeight(msg.Height - entriesListChrome)
+		return m, nil
+	}
+
 	// Handle client loading result — arrives while still in list mode
 	if msg, ok := msg.(entryClientsMsg); ok {
 		m.loading = false
@@ -272,15 +561,37 @@ func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = fmt.Errorf("no clients found — add a client first")
 			return m, nil
 		}
-		m.formClients = msg.clients
-		m.clientCursor = 0
+		m.picker = newClientPicker(msg.clients)
 		// Skip picker if only one client
 		if len(msg.clients) == 1 {
 			m.selectClient(msg.clients[0])
 			return m, m.fields[m.fieldFocus].Focus()
 		}
 		m.mode = entryModePickClient
+		return m, m.picker.Focus()
+	}
+
+	// A jump from another screen leaves any unfinished step
+	switch msg := msg.(type) {
+	case selectEntryMsg:
+		m.mode = entryModeList
+		m.confirm.Close()
+		m.selectID = msg.id
+		if m.invoice != nil {
+			return m, m.filterByInvoice(nil)
+		}
+		if !m.loading {
+			m.selectEntry()
+		}
 		return m, nil
+	case filterEntriesMsg:
+		m.mode = entryModeList
+		m.confirm.Close()
+		return m, m.filterByInvoice(msg.invoice)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && m.confirm.Active() {
+		return m, m.confirm.Update(msg)
 	}
 
 	// Route messages based on mode
@@ -289,10 +600,12 @@ func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updatePickClient(msg)
 	case entryModeNew:
 		return m.updateForm(msg)
-	case entryModeConfirmDelete:
-		return m.updateConfirmDelete(msg)
 	case entryModeEditDesc:
 		return m.updateEditDesc(msg)
+	case entryModeSplit:
+		return m.updateSplit(msg)
+	case entryModeReason:
+		return m.updateReason(msg)
 	}
 
 	switch msg := msg.(type) {
@@ -301,43 +614,59 @@ func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.loadEntries()
 
 	case entriesDataMsg:
+		// A list loaded before the invoice filter changed is stale
+		if msg.invoiceID != m.invoiceID() {
+			return m, nil
+		}
 		m.loading = false
 		m.err = msg.err
 		if msg.err == nil {
-			m.entries = msg.entries
 			m.clientNames = msg.clientNames
+			m.contracts = msg.contracts
+			m.clientRates = msg.clientRates
+			m.list.SetItems(msg.entries)
+			m.selectEntry()
 		}
+		m.selectID = 0
 		return m, nil
 
+	case timerStartedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		return m, switchToTimerCmd()
+
+	case entryRestoredMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		m.lastDeletedID = 0
+		m.loading = true
+		return m, tea.Batch(m.loadEntries(), notify(NotifySuccess, "Entry restored"))
+
 	case tea.KeyMsg:
 		if m.loading {
 			return m, nil
 		}
 
-		m.statusMsg = ""
 		m.err = nil
 
+		// Undo is only offered on the keypress right after a delete
+		lastDeletedID := m.lastDeletedID
+		m.lastDeletedID = 0
+
 		switch {
-		case key.Matches(msg, DefaultKeyMap.Up):
-			if m.cursor > 0 {
-				m.cursor--
-				if m.cursor < m.offset {
-					m.offset = m.cursor
-				}
-			}
-		case key.Matches(msg, DefaultKeyMap.Down):
-			if m.cursor < len(m.entries)-1 {
-				m.cursor++
-				if m.cursor >= m.offset+m.maxVisible {
-					m.offset = m.cursor - m.maxVisible + 1
-				}
+		case key.Matches(msg, DefaultKeyMap.Back):
+			if m.invoice != nil {
+				return m, m.filterByInvoice(nil)
 			}
-		case msg.String() == "n":
+		case m.list.Update(msg):
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.New):
 			m.loading = true
 			return m, m.loadFormClients()
-		case msg.String() == "enter":
-			if len(m.entries) > 0 && m.cursor < len(m.entries) {
-				entry := m.entries[m.cursor]
+		case key.Matches(msg, DefaultKeyMap.Select):
+			if entry, ok := m.list.Selected(); ok {
 				if entry.IsLocked() {
 					m.err = fmt.Errorf("cannot edit: entry is locked by an invoice")
 					return m, nil
@@ -347,18 +676,49 @@ func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				ti.SetValue(entry.Description)
 				ti.CharLimit = 200
 				ti.Width = 50
+				suggestDescriptions(m.app, &ti, entry.ClientID)
 				m.descInput = ti
 				m.mode = entryModeEditDesc
 				return m, m.descInput.Focus()
 			}
-		case msg.String() == "d":
-			if len(m.entries) > 0 && m.cursor < len(m.entries) {
-				entry := m.entries[m.cursor]
+		case key.Matches(msg, DefaultKeyMap.StartTimer):
+			if entry, ok := m.list.Selected(); ok {
+				return m, startTimerCmd(m.app, entry.ClientID, entry.Description, entry.IsBillable)
+			}
+		case key.Matches(msg, DefaultKeyMap.Undo):
+			if lastDeletedID != 0 {
+				return m, m.restoreEntry(lastDeletedID)
+			}
+		case key.Matches(msg, DefaultKeyMap.Split):
+			if entry, ok := m.list.Selected(); ok {
+				if entry.IsLocked() {
+					m.err = fmt.Errorf("cannot split: entry is locked by an invoice")
+					return m, nil
+				}
+				if entry.IsRunning() {
+					m.err = fmt.Errorf("cannot split: entry is still running")
+					return m, nil
+				}
+				// Default to the midpoint of the entry
+				mid := entry.StartTime.Add(entry.Span() / 2)
+				ti := textinput.New()
+				ti.Placeholder = "HH:MM"
+				ti.SetValue(mid.Format("15:04"))
+				ti.CharLimit = 5
+				ti.Width = 10
+				m.splitInput = ti
+				m.mode = entryModeSplit
+				return m, m.splitInput.Focus()
+			}
+		case key.Matches(msg, DefaultKeyMap.Delete):
+			if entry, ok := m.list.Selected(); ok {
 				if entry.IsLocked() {
 					m.err = fmt.Errorf("cannot delete: entry is locked by an invoice")
 					return m, nil
 				}
-				m.mode = entryModeConfirmDelete
+				m.confirm.Ask("Delete Entry", "Delete this entry?", []string{m.describeEntry(entry)}, func() tea.Cmd {
+					return m.promptReason(entryModeConfirmDelete)
+				})
 				return m, nil
 			}
 		}
@@ -367,30 +727,47 @@ func (m *EntriesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *EntriesModel) updatePickClient(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, DefaultKeyMap.Back):
-			m.mode = entryModeList
-			m.formClients = nil
-			return m, nil
-		case key.Matches(msg, DefaultKeyMap.Up):
-			if m.clientCursor > 0 {
-				m.clientCursor--
-			}
-		case key.Matches(msg, DefaultKeyMap.Down):
-			if m.clientCursor < len(m.formClients)-1 {
-				m.clientCursor++
-			}
-		case key.Matches(msg, DefaultKeyMap.Select):
-			if len(m.formClients) > 0 {
-				m.selectClient(m.formClients[m.clientCursor])
-				return m, m.fields[m.fieldFocus].Focus()
-			}
+// filterByInvoice lists the entries of an invoice, or the last 30 days
+// again when invoice is nil
+func (m *EntriesModel) filterByInvoice(invoice *domain.Invoice) tea.Cmd {
+	m.invoice = invoice
+	m.list.Reset()
+	if invoice != nil {
+		m.list.SetEmpty("No entries are billed on this invoice. Press esc to show all entries.")
+	} else {
+		m.list.SetEmpty("No time entries yet. Press 'n' to add one.")
+	}
+	m.loading = true
+	return m.loadEntries()
+}
+
+// selectEntry moves the cursor to the entry asked for by selectID, scrolling
+// it into view, and forgets the request once the entry is found
+func (m *EntriesModel) selectEntry() {
+	if m.selectID == 0 {
+		return
+	}
+	for i, entry := range m.list.Items() {
+		if entry.ID != m.selectID {
+			continue
 		}
+		m.list.Select(i)
+		m.selectID = 0
+		return
 	}
-	return m, nil
+}
+
+func (m *EntriesModel) updatePickClient(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, DefaultKeyMap.Cancel) {
+		m.mode = entryModeList
+		return m, nil
+	}
+	client, cmd := m.picker.Update(msg)
+	if client != nil {
+		m.selectClient(client)
+		return m, m.fields[m.fieldFocus].Focus()
+	}
+	return m, cmd
 }
 
 func (m *EntriesModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -401,32 +778,34 @@ func (m *EntriesModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.mode = entryModeList
-		m.statusMsg = "Entry saved"
 		m.loading = true
-		return m, m.loadEntries()
+		return m, tea.Batch(m.loadEntries(), notify(NotifySuccess, "Entry saved"))
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = entryModePickClient
 			m.err = nil
 			// Go back to client picker (or list if only one client)
-			if len(m.formClients) <= 1 {
+			if m.picker.Len() <= 1 {
 				m.mode = entryModeList
 			}
+			return m, m.picker.Focus()
+
+		case key.Matches(msg, textinput.DefaultKeyMap.AcceptSuggestion) && acceptCompletion(&m.fields[m.fieldFocus]):
 			return m, nil
 
-		case "tab", "down":
+		case key.Matches(msg, DefaultKeyMap.NextField):
 			m.fields[m.fieldFocus].Blur()
 			m.fieldFocus = (m.fieldFocus + 1) % entryFieldCount
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "shift+tab", "up":
+		case key.Matches(msg, DefaultKeyMap.PrevField):
 			m.fields[m.fieldFocus].Blur()
 			m.fieldFocus = (m.fieldFocus - 1 + entryFieldCount) % entryFieldCount
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "enter":
+		case key.Matches(msg, DefaultKeyMap.Select):
 			if m.fieldFocus == entryFieldCount-1 {
 				return m, m.saveEntry()
 			}
@@ -434,8 +813,12 @@ func (m *EntriesModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.fieldFocus++
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "ctrl+s":
+		case key.Matches(msg, DefaultKeyMap.Save):
 			return m, m.saveEntry()
+
+		case key.Matches(msg, DefaultKeyMap.EntryDuration):
+			m.toggleDurationInput()
+			return m, nil
 		}
 	}
 
@@ -447,62 +830,112 @@ func (m *EntriesModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m *EntriesModel) updateEditDesc(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case entryDescUpdatedMsg:
-		if msg.err != nil {
-			m.err = msg.err
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			m.pendingDesc = m.descInput.Value()
+			return m, m.promptReason(entryModeEditDesc)
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = entryModeList
 			return m, nil
+		default:
+			var cmd tea.Cmd
+			m.descInput, cmd = m.descInput.Update(msg)
+			return m, cmd
 		}
+	}
+	return m, nil
+}
+
+func (m *EntriesModel) updateReason(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case entryDescUpdatedMsg:
 		m.mode = entryModeList
-		m.statusMsg = "Description updated"
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
 		m.loading = true
-		return m, m.loadEntries()
+		return m, tea.Batch(m.loadEntries(), notify(NotifySuccess, "Description updated"))
+
+	case entryDeletedMsg:
+		m.mode = entryModeList
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		m.lastDeletedID = msg.id
+		m.loading = true
+		return m, tea.Batch(m.loadEntries(),
+			notify(NotifySuccess, fmt.Sprintf("Entry deleted (%s: undo)", DefaultKeyMap.Undo.Help().Key)))
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "enter":
-			entry := m.entries[m.cursor]
-			desc := m.descInput.Value()
-			return m, func() tea.Msg {
-				entry.Description = desc
-				entry.UpdatedAt = time.Now()
-				err := m.app.EntryRepo.Update(context.Background(), entry, "description updated")
-				return entryDescUpdatedMsg{err: err}
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			entry, _ := m.list.Selected()
+			reason := strings.TrimSpace(m.reasonInput.Value())
+			if reason == "" {
+				if m.app.Config.Audit.RequireReason {
+					m.err = fmt.Errorf("a reason is required")
+					return m, nil
+				}
+				reason = "description updated"
+				if m.reasonFor == entryModeConfirmDelete {
+					reason = "deleted by user"
+				}
 			}
-		case "esc":
+			m.err = nil
+			if m.reasonFor == entryModeConfirmDelete {
+				return m, m.deleteEntry(entry.ID, reason)
+			}
+			return m, m.updateDescription(entry, m.pendingDesc, reason)
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = entryModeList
+			m.err = nil
 			return m, nil
 		default:
 			var cmd tea.Cmd
-			m.descInput, cmd = m.descInput.Update(msg)
+			m.reasonInput, cmd = m.reasonInput.Update(msg)
 			return m, cmd
 		}
 	}
 	return m, nil
 }
 
-func (m *EntriesModel) updateConfirmDelete(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m *EntriesModel) updateSplit(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case entryDeletedMsg:
+	case entrySplitMsg:
 		if msg.err != nil {
 			m.err = msg.err
 			m.mode = entryModeList
 			return m, nil
 		}
 		m.mode = entryModeList
-		m.statusMsg = "Entry deleted"
 		m.loading = true
-		return m, m.loadEntries()
+		return m, tea.Batch(m.loadEntries(), notify(NotifySuccess, "Entry split"))
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "y":
-			entry := m.entries[m.cursor]
-			return m, m.deleteEntry(entry.ID)
-		default:
-			// Any other key cancels
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			entry, _ := m.list.Selected()
+			t, err := time.Parse("15:04", strings.TrimSpace(m.splitInput.Value()))
+			if err != nil {
+				m.err = fmt.Errorf("invalid time (use HH:MM)")
+				m.mode = entryModeList
+				return m, nil
+			}
+			at := time.Date(entry.StartTime.Year(), entry.StartTime.Month(), entry.StartTime.Day(),
+				t.Hour(), t.Minute(), 0, 0, entry.StartTime.Location())
+			return m, func() tea.Msg {
+				reason := fmt.Sprintf("split at %s", at.Format("15:04"))
+				_, err := m.app.EntryRepo.Split(context.Background(), entry.ID, at, reason)
+				return entrySplitMsg{err: err}
+			}
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = entryModeList
 			return m, nil
+		default:
+			var cmd tea.Cmd
+			m.splitInput, cmd = m.splitInput.Update(msg)
+			return m, cmd
 		}
 	}
 	return m, nil
@@ -513,45 +946,86 @@ func (m *EntriesModel) View() string {
 		return "Loading entries..."
 	}
 
+	if m.confirm.Active() {
+		return m.confirm.View()
+	}
+
 	switch m.mode {
 	case entryModePickClient:
 		return m.viewPickClient()
 	case entryModeNew:
 		return m.viewForm()
-	case entryModeConfirmDelete:
-		return m.viewConfirmDelete()
 	case entryModeEditDesc:
 		return m.viewEditDesc()
+	case entryModeSplit:
+		return m.viewSplit()
+	case entryModeReason:
+		return m.viewReason()
 	default:
 		return m.viewList()
 	}
 }
 
+// describeEntry sums up an entry on one line for a confirmation
+func (m *EntriesModel) describeEntry(entry *domain.TimeEntry) string {
+	return fmt.Sprintf("%s  %s  %s  %s", formatShortDate(entry.StartTime), m.clientNames[entry.ClientID],
+		formatHours(entry.Duration().Hours()), truncateStr(entry.Description, 40))
+}
+
 func (m *EntriesModel) viewEditDesc() string {
-	entry := m.entries[m.cursor]
+	entry, _ := m.list.Selected()
 	clientName := m.clientNames[entry.ClientID]
-	date := entry.StartTime.Format("Jan 2")
+	date := formatShortDate(entry.StartTime)
 	hours := formatHours(entry.Duration().Hours())
 
 	var s string
 	s += titleStyle.Render("Edit Description") + "\n\n"
 	s += fmt.Sprintf("  %s  %s  %s\n\n", date, clientName, hours)
 	s += fmt.Sprintf("  Description: %s\n\n", m.descInput.View())
-	s += helpStyle.Render("  enter: save  esc: cancel") + "\n"
+	s += renderKeyHelp(m.KeyHelp()...) + "\n"
 	return s
 }
 
-func (m *EntriesModel) viewConfirmDelete() string {
-	entry := m.entries[m.cursor]
+func (m *EntriesModel) viewReason() string {
+	entry, _ := m.list.Selected()
 	clientName := m.clientNames[entry.ClientID]
-	date := entry.StartTime.Format("Jan 2")
+	date := formatShortDate(entry.StartTime)
 	hours := formatHours(entry.Duration().Hours())
+
+	title := "Edit Description"
+	action := fmt.Sprintf("New description: %s", truncateStr(m.pendingDesc, 40))
+	if m.reasonFor == entryModeConfirmDelete {
+		title = "Delete Entry"
+		action = fmt.Sprintf("Delete: %s", truncateStr(entry.Description, 40))
+	}
+
+	var s string
+	s += titleStyle.Render(title) + "\n\n"
+	s += fmt.Sprintf("  %s  %s  %s\n", date, clientName, hours)
+	s += fmt.Sprintf("  %s\n\n", action)
+	s += fmt.Sprintf("  Reason: %s\n\n", m.reasonInput.View())
+
+	if m.err != nil {
+		s += lipgloss.NewStyle().Foreground(errorColor).
+			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
+	}
+
+	s += renderKeyHelp(m.KeyHelp()...) + "\n"
+	return s
+}
+
+func (m *EntriesModel) viewSplit() string {
+	entry, _ := m.list.Selected()
+	clientName := m.clientNames[entry.ClientID]
+	date := formatShortDate(entry.StartTime)
+	span := fmt.Sprintf("%s-%s", entry.StartTime.Format("15:04"), entry.EndTime.Format("15:04"))
 	desc := truncateStr(entry.Description, 40)
 
 	var s string
-	s += titleStyle.Render("Delete Entry") + "\n\n"
-	s += fmt.Sprintf("  %s  %s  %s  %s\n\n", date, clientName, hours, desc)
-	s += lipgloss.NewStyle().Foreground(warningColor).Render("  Delete this entry? (y/n)") + "\n"
+	s += titleStyle.Render("Split Entry") + "\n\n"
+	s += fmt.Sprintf("  %s  %s  %s  %s\n\n", date, clientName, span, desc)
+	s += fmt.Sprintf("  Split at: %s\n\n", m.splitInput.View())
+	s += renderKeyHelp(m.KeyHelp()...) + "\n"
 	return s
 }
 
@@ -563,56 +1037,61 @@ func (m *EntriesModel) viewList() string {
 
 	var s string
 
-	s += titleStyle.Render("Time Entries") + "\n"
-
-	if m.statusMsg != "" {
-		s += lipgloss.NewStyle().Foreground(successColor).
-			Render("  "+m.statusMsg) + "\n"
+	if m.invoice != nil {
+		s += titleStyle.Render("Time Entries on "+m.invoice.InvoiceNumber) + "\n"
+	} else {
+		s += titleStyle.Render("Time Entries") + "\n"
 	}
 
-	if len(m.entries) == 0 {
-		s += "\n" + subtitleStyle.Render("  No time entries yet. Press 'n' to add one.")
+	if m.list.Len() == 0 {
+		s += "\n" + m.list.View()
+		s += "\n" + renderKeyHelp(m.KeyHelp()...)
 		return s
 	}
 
 	// Summary
 	totalHours, totalValue := m.calcTotals()
-	s += subtitleStyle.Render(fmt.Sprintf(
-		"  %d entries  |  %s total  |  %s value",
-		len(m.entries), formatHours(totalHours), formatMoney(totalValue),
-	)) + "\n\n"
-
-	// Column header
-	s += subtitleStyle.Render(fmt.Sprintf(
-		"     %-7s  %-20s  %6s  %10s  %s",
-		"Date", "Client", "Hours", "Amount", "Description",
-	)) + "\n"
-
-	// Entries
-	end := m.offset + m.maxVisible
-	if end > len(m.entries) {
-		end = len(m.entries)
+	summary := fmt.Sprintf("  %d entries  |  %s total  |  %s value",
+		m.list.Len(), formatHours(totalHours), formatMoney(totalValue.Float()))
+	if n := m.countLapsed(); n > 0 {
+		summary += fmt.Sprintf("  |  ! %d after contract end", n)
 	}
-
-	for i := m.offset; i < end; i++ {
-		entry := m.entries[i]
-		s += m.renderEntry(entry, i == m.cursor) + "\n"
+	if n := m.countOtherRates(); n > 0 {
+		summary += fmt.Sprintf("  |  * %d at another rate", n)
 	}
+	s += subtitleStyle.Render(summary) + "\n\n"
 
-	// Scroll indicators
-	if m.offset > 0 {
-		s += subtitleStyle.Render("  ... more above") + "\n"
-	}
-	if end < len(m.entries) {
-		s += subtitleStyle.Render("  ... more below") + "\n"
-	}
+	// Entries under their column header
+	s += m.list.View()
 
 	// Totals
 	s += "\n" + lipgloss.NewStyle().Bold(true).Render(
-		fmt.Sprintf("     %-7s  %-20s  %6s  %10s", "Total", "", formatHours(totalHours), formatMoney(totalValue)),
+		fmt.Sprintf("     %-7s  %-20s  %6s  %10s", "Total", "", formatHours(totalHours), formatMoney(totalValue.Float())),
 	) + "\n"
 
-	s += "\n" + helpStyle.Render("  j/k: navigate  n: new entry  enter: edit desc  d: delete")
+	// Why the selected entry is billed at another rate, its rate card role,
+	// and where it was done
+	entry, _ := m.list.Selected()
+	var note string
+	if entry.Role != "" {
+		note = "  + " + entry.Role
+	}
+	if m.otherRate(entry) {
+		reason := entry.RateReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		note += fmt.Sprintf("  * %s/hr instead of %s/hr: %s",
+			formatMoney(entry.HourlyRate), formatMoney(m.clientRates[entry.ID]), reason)
+	}
+	if entry.Location != "" {
+		note += "  @ " + entry.Location
+	}
+	if note != "" {
+		s += subtitleStyle.Render(note) + "\n"
+	}
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -620,24 +1099,8 @@ func (m *EntriesModel) viewList() string {
 func (m *EntriesModel) viewPickClient() string {
 	var s string
 	s += titleStyle.Render("New Entry - Select Client") + "\n\n"
-
-	for i, client := range m.formClients {
-		indicator := "  "
-		if i == m.clientCursor {
-			indicator = "> "
-		}
-
-		rate := fmt.Sprintf("$%.0f/hr", client.HourlyRate)
-		clientLine := fmt.Sprintf("%s%-25s  %s", indicator, client.Name, rate)
-
-		if i == m.clientCursor {
-			s += lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(clientLine) + "\n"
-		} else {
-			s += clientLine + "\n"
-		}
-	}
-
-	s += "\n" + helpStyle.Render("  j/k: navigate  enter: select  esc: cancel")
+	s += m.picker.View()
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -651,7 +1114,12 @@ func (m *EntriesModel) viewForm() string {
 	}
 	s += titleStyle.Render(fmt.Sprintf("New Entry - %s", clientName)) + "\n\n"
 
-	labels := []string{"Date:", "Start Time:", "End Time:", "Description:", "Rate ($/hr):"}
+	startLabel, endLabel := "Start Time:", "End Time:"
+	if m.durationInput {
+		startLabel = "Start Time (blank for " + m.workdayStart().Format("15:04") + "):"
+		endLabel = "Duration:"
+	}
+	labels := []string{"Date:", startLabel, endLabel, "Description:", "Rate (" + activeLocale.CurrencySymbol + "/hr, blank for the client's rate that day):", "Rate reason (if not the client's rate):", "Location (blank for none):", "Billable (y/n):"}
 	for i, label := range labels {
 		indicator := "  "
 		if i == m.fieldFocus {
@@ -669,7 +1137,7 @@ func (m *EntriesModel) viewForm() string {
 			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
 	}
 
-	s += helpStyle.Render("  tab/shift+tab: navigate fields  ctrl+s: save  enter: next/save  esc: back")
+	s += renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -681,28 +1149,79 @@ func (m *EntriesModel) renderEntry(entry *domain.TimeEntry, selected bool) strin
 		lock = "🔒"
 	}
 
-	date := entry.StartTime.Format("Jan 2")
+	date := formatShortDate(entry.StartTime)
 	clientName := truncateStr(m.clientNames[entry.ClientID], 20)
 	hours := formatHours(entry.Duration().Hours())
-	amount := formatMoney(entry.Amount())
+	amount := formatMoney(entry.Amount().Float())
 	desc := truncateStr(entry.Description, 35)
 
-	line := fmt.Sprintf("%s %-7s  %-20s  %6s  %10s  %s",
-		lock, date, clientName, hours, amount, desc,
+	// Work logged after the client's contracts ended
+	flag := " "
+	lapsed := m.isLapsed(entry)
+	if lapsed {
+		flag = "!"
+	}
+
+	// Billed at a rate other than the client's that day
+	rateFlag := " "
+	if m.otherRate(entry) {
+		rateFlag = "*"
+	}
+
+	line := fmt.Sprintf("%s%s%-7s  %-20s  %6s  %10s%s %s",
+		lock, flag, date, clientName, hours, amount, rateFlag, desc,
 	)
 
 	if selected {
 		return "  " + selectedStyle.Render(line)
 	}
+	if lapsed {
+		return "  " + lapsedStyle.Render(line)
+	}
 	if !entry.IsBillable {
-		return "  " + lipgloss.NewStyle().Foreground(mutedColor).Render(line)
+		return "  " + nonBillableStyle.Render(line)
 	}
 	return "  " + line
 }
 
-func (m *EntriesModel) calcTotals() (float64, float64) {
-	var totalHours, totalValue float64
-	for _, entry := range m.entries {
+// otherRate reports whether an entry is billed at a rate other than its
+// client's rate that day
+func (m *EntriesModel) otherRate(entry *domain.TimeEntry) bool {
+	rate, ok := m.clientRates[entry.ID]
+	return ok && entry.RateDiffers(rate)
+}
+
+// countOtherRates counts the listed entries billed at another rate
+func (m *EntriesModel) countOtherRates() int {
+	n := 0
+	for _, entry := range m.list.Items() {
+		if m.otherRate(entry) {
+			n++
+		}
+	}
+	return n
+}
+
+// isLapsed reports whether an entry was logged after its client's contracts ended
+func (m *EntriesModel) isLapsed(entry *domain.TimeEntry) bool {
+	return domain.ContractLapsed(m.contracts[entry.ClientID], entry.StartTime)
+}
+
+// countLapsed counts the listed entries logged after a contract ended
+func (m *EntriesModel) countLapsed() int {
+	n := 0
+	for _, entry := range m.list.Items() {
+		if m.isLapsed(entry) {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *EntriesModel) calcTotals() (float64, domain.Money) {
+	var totalHours float64
+	var totalValue domain.Money
+	for _, entry := range m.list.Items() {
 		totalHours += entry.Duration().Hours()
 		totalValue += entry.Amount()
 	}
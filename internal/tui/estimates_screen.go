@@ -0,0 +1,405 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type estimateViewMode int
+
+const (
+	estimateViewList   estimateViewMode = iota
+	estimateViewDetail                  // Viewing a single estimate
+)
+
+// EstimatesModel lists estimates, records the client's answer, and converts
+// accepted estimates into draft invoices. Estimates are created with
+// `timesink estimates create`.
+type EstimatesModel struct {
+	app       *app.App
+	mode      estimateViewMode
+	estimates []*domain.Estimate
+	cursor    int
+	selected  *domain.Estimate
+	loading   bool
+	err       error
+}
+
+// KeyHelp lists the keys for the current view, offering only the status
+// changes the selected estimate allows
+func (m *EstimatesModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.mode == estimateViewDetail && m.selected != nil {
+		est := m.selected
+		var bindings []key.Binding
+		if est.Status == domain.EstimateStatusDraft {
+			bindings = append(bindings, k.MarkSent)
+		}
+		if est.CanRespond() {
+			bindings = append(bindings, k.Accept, k.Decline)
+		}
+		if est.CanConvert() {
+			bindings = append(bindings, k.Convert)
+		}
+		return append(bindings, withHelp(k.Back, "back to list"))
+	}
+	if len(m.estimates) == 0 {
+		return nil
+	}
+	return []key.Binding{navigateKeys(), withHelp(k.Select, "view detail")}
+}
+
+type estimatesDataMsg struct {
+	estimates []*domain.Estimate
+	err       error
+}
+
+type estimateDetailMsg struct {
+	estimate *domain.Estimate
+	err      error
+}
+
+// estimateChangedMsg signals the selected estimate's status changed
+type estimateChangedMsg struct {
+	id   int64
+	text string
+	err  error
+}
+
+// NewEstimatesModel creates a new estimates screen model
+func NewEstimatesModel(a *app.App) tea.Model {
+	return &EstimatesModel{
+		app:     a,
+		mode:    estimateViewList,
+		loading: true,
+	}
+}
+
+func (m *EstimatesModel) Init() tea.Cmd {
+	return m.loadEstimates()
+}
+
+func (m *EstimatesModel) loadEstimates() tea.Cmd {
+	key := "estimates:" + today()
+	return func() tea.Msg {
+		msg, _ := cached(screenData, key, func() (estimatesDataMsg, error) {
+			msg := m.fetchEstimates()
+			return msg, msg.err
+		})
+		return msg
+	}
+}
+
+func (m *EstimatesModel) fetchEstimates() estimatesDataMsg {
+	ctx := context.Background()
+	estimates, err := m.app.EstimateService.ListEstimates(ctx, nil, nil)
+	if err != nil {
+		return estimatesDataMsg{err: err}
+	}
+
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		return estimatesDataMsg{err: err}
+	}
+	for _, est := range estimates {
+		est.Client = clients[est.ClientID]
+	}
+
+	return estimatesDataMsg{estimates: estimates}
+}
+
+func (m *EstimatesModel) loadDetail(id int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		est, err := m.app.EstimateService.GetEstimate(ctx, id)
+		if err != nil {
+			return estimateDetailMsg{err: err}
+		}
+		if client, err := m.app.ClientRepo.GetByID(ctx, est.ClientID); err == nil {
+			est.Client = client
+		}
+		return estimateDetailMsg{estimate: est}
+	}
+}
+
+// change runs a status change on the selected estimate
+func (m *EstimatesModel) change(done string, fn func(ctx context.Context, id int64) error) tea.Cmd {
+	est := m.selected
+	return func() tea.Msg {
+		if err := fn(context.Background(), est.ID); err != nil {
+			return estimateChangedMsg{err: err}
+		}
+		return estimateChangedMsg{id: est.ID, text: fmt.Sprintf("Estimate %s %s", est.EstimateNumber, done)}
+	}
+}
+
+// convert copies the selected estimate into a draft invoice with the
+// client's configured taxes and the default invoice footer
+func (m *EstimatesModel) convert() tea.Cmd {
+	est := m.selected
+	a := m.app
+	return func() tea.Msg {
+		ctx := context.Background()
+		client := est.Client
+		if client == nil {
+			var err error
+			if client, err = a.ClientRepo.GetByID(ctx, est.ClientID); err != nil {
+				return estimateChangedMsg{err: err}
+			}
+		}
+
+		invoice, err := a.EstimateService.ConvertToInvoice(ctx, est.ID, a.Config.Invoice.NumberPrefix,
+			a.InvoiceTaxes(client), a.InvoiceFooter())
+		if err != nil {
+			return estimateChangedMsg{err: err}
+		}
+		return estimateChangedMsg{id: est.ID, text: fmt.Sprintf("Draft invoice %s created from %s", invoice.InvoiceNumber, est.EstimateNumber)}
+	}
+}
+
+func (m *EstimatesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case RefreshDataMsg:
+		m.loading = true
+		if m.mode == estimateViewDetail && m.selected != nil {
+			return m, tea.Batch(m.loadEstimates(), m.loadDetail(m.selected.ID))
+		}
+		return m, m.loadEstimates()
+
+	case estimatesDataMsg:
+		m.loading = false
+		m.err = msg.err
+		m.estimates = msg.estimates
+		if m.cursor >= len(m.estimates) {
+			m.cursor = max(len(m.estimates)-1, 0)
+		}
+		return m, nil
+
+	case estimateDetailMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.selected = msg.estimate
+		m.mode = estimateViewDetail
+		return m, nil
+
+	case estimateChangedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		cmds := []tea.Cmd{m.loadEstimates(), notify(NotifySuccess, msg.text)}
+		if m.mode == estimateViewDetail {
+			cmds = append(cmds, m.loadDetail(msg.id))
+		}
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+
+		switch m.mode {
+		case estimateViewList:
+			return m.updateList(msg)
+		case estimateViewDetail:
+			return m.updateDetail(msg)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *EstimatesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.err = nil
+
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, DefaultKeyMap.Down):
+		if m.cursor < len(m.estimates)-1 {
+			m.cursor++
+		}
+	case key.Matches(msg, DefaultKeyMap.Select):
+		if len(m.estimates) > 0 {
+			m.loading = true
+			return m, m.loadDetail(m.estimates[m.cursor].ID)
+		}
+	}
+
+	return m, nil
+}
+
+func (m *EstimatesModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	est := m.selected
+	svc := m.app.EstimateService
+
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Back):
+		m.mode = estimateViewList
+		m.selected = nil
+	case key.Matches(msg, DefaultKeyMap.MarkSent):
+		if est.Status == domain.EstimateStatusDraft {
+			return m, m.change("marked as sent", svc.MarkSent)
+		}
+	case key.Matches(msg, DefaultKeyMap.Accept):
+		if est.CanRespond() {
+			return m, m.change("accepted", svc.Accept)
+		}
+	case key.Matches(msg, DefaultKeyMap.Decline):
+		if est.CanRespond() {
+			return m, m.change("declined", svc.Decline)
+		}
+	case key.Matches(msg, DefaultKeyMap.Convert):
+		if est.CanConvert() {
+			return m, m.convert()
+		}
+	}
+	return m, nil
+}
+
+func (m *EstimatesModel) View() string {
+	if m.loading {
+		return "Loading..."
+	}
+	if m.mode == estimateViewDetail {
+		return m.viewDetail()
+	}
+	return m.viewList()
+}
+
+func (m *EstimatesModel) viewList() string {
+	var s string
+	s += titleStyle.Render("Estimates") + "\n\n"
+
+	if m.err != nil {
+		s += lipgloss.NewStyle().Foreground(errorColor).
+			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
+	}
+
+	if len(m.estimates) == 0 && m.err == nil {
+		s += subtitleStyle.Render("  No estimates yet. Create one with `timesink estimates create <client>`.")
+		return s
+	}
+
+	s += subtitleStyle.Render(fmt.Sprintf(
+		"  %-14s  %-20s  %-12s  %10s  %s",
+		"Number", "Client", "Date", "Total", "Status",
+	)) + "\n"
+
+	now := time.Now()
+	for i, est := range m.estimates {
+		clientName := "Unknown"
+		if est.Client != nil {
+			clientName = est.Client.Name
+		}
+
+		line := fmt.Sprintf("  %-14s  %-20s  %-12s  %10s  %s",
+			est.EstimateNumber,
+			truncateStr(clientName, 20),
+			formatShortDate(est.IssueDate),
+			formatMoney(est.Total.Float()),
+			estimateBadge(est, now),
+		)
+
+		if i == m.cursor {
+			s += selectedStyle.Render(line) + "\n"
+		} else {
+			s += line + "\n"
+		}
+	}
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
+
+	return s
+}
+
+func (m *EstimatesModel) viewDetail() string {
+	est := m.selected
+	if est == nil {
+		return "No estimate selected"
+	}
+
+	clientName := "Unknown"
+	if est.Client != nil {
+		clientName = est.Client.Name
+	}
+
+	var s string
+	s += titleStyle.Render(fmt.Sprintf("Estimate %s", est.EstimateNumber)) + "\n\n"
+	s += fmt.Sprintf("  Client:   %s\n", clientName)
+	s += fmt.Sprintf("  Date:     %s\n", formatLongDate(est.IssueDate))
+	if est.ValidUntil != nil {
+		s += fmt.Sprintf("  Expires:  %s\n", formatLongDate(*est.ValidUntil))
+	}
+	s += fmt.Sprintf("  Status:   %s\n", estimateBadge(est, time.Now()))
+	if est.InvoiceID != nil {
+		s += fmt.Sprintf("  Invoice:  #%d\n", *est.InvoiceID)
+	}
+	s += "\n"
+
+	if len(est.LineItems) == 0 {
+		s += subtitleStyle.Render("  No line items") + "\n"
+	} else {
+		s += subtitleStyle.Render(fmt.Sprintf(
+			"  %-40s  %8s  %10s",
+			"Description", "Hours", "Amount",
+		)) + "\n"
+
+		for _, item := range est.LineItems {
+			hours := ""
+			if !item.IsFixed() {
+				hours = formatInvoiceHours(m.app.Config.Invoice, item.Hours)
+			}
+			s += fmt.Sprintf("  %-40s  %8s  %10s\n",
+				truncateStr(item.Description, 40),
+				hours,
+				formatMoney(item.Amount.Float()),
+			)
+		}
+	}
+
+	s += "\n"
+	s += lipgloss.NewStyle().Bold(true).Render(
+		fmt.Sprintf("  Total:     %10s", formatMoney(est.Total.Float())),
+	) + "\n"
+	s += subtitleStyle.Render("  Before tax") + "\n"
+	s += viewFooterText("Notes", est.Notes)
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
+
+	return s
+}
+
+// estimateBadge renders an estimate status with color, noting open
+// estimates that have passed their expiry date
+func estimateBadge(est *domain.Estimate, now time.Time) string {
+	switch {
+	case est.InvoiceID != nil:
+		return lipgloss.NewStyle().Foreground(successColor).Render("INVOICED")
+	case est.IsExpired(now):
+		return lipgloss.NewStyle().Foreground(errorColor).Render("EXPIRED")
+	}
+	switch est.Status {
+	case domain.EstimateStatusDraft:
+		return lipgloss.NewStyle().Foreground(mutedColor).Render("DRAFT")
+	case domain.EstimateStatusSent:
+		return lipgloss.NewStyle().Foreground(warningColor).Render("SENT")
+	case domain.EstimateStatusAccepted:
+		return lipgloss.NewStyle().Foreground(primaryColor).Render("ACCEPTED")
+	case domain.EstimateStatusDeclined:
+		return lipgloss.NewStyle().Foreground(errorColor).Render("DECLINED")
+	default:
+		return string(est.Status)
+	}
+}
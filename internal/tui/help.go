@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// helpView renders the help overlay: the global keys followed by the keys
+// the active screen accepts in its current mode
+func (m *Model) helpView() string {
+	k := DefaultKeyMap
+	s := titleStyle.Render("Keyboard Shortcuts") + "\n\n"
+	s += helpSection("Global", append(globalKeys(), k.PageUp, k.PageDown))
+
+	if kh, ok := m.activeScreen().(KeyHelper); ok {
+		if bindings := kh.KeyHelp(); len(bindings) > 0 {
+			s += "\n" + helpSection(m.currentScreen.String(), bindings)
+		}
+	}
+
+	s += "\n" + renderKeyHelp(withHelp(k.Help, "close help"))
+	return s
+}
+
+// helpSection renders a titled list of bindings, one per line
+func helpSection(title string, bindings []key.Binding) string {
+	s := subtitleStyle.Render(title) + "\n"
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		s += fmt.Sprintf("  %-12s %s\n", b.Help().Key, b.Help().Desc)
+	}
+	return s
+}
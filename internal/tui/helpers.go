@@ -1,9 +1,23 @@
 package tui
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/locale"
+)
+
+// activeLocale controls money and date formatting across all screens. It is
+// set from the config when the TUI starts.
+var activeLocale = locale.Default()
 
 // formatHours formats hours as "Xh Ym"
 func formatHours(hours float64) string {
+	if hours < 0 {
+		return "-" + formatHours(-hours)
+	}
 	h := int(hours)
 	m := int((hours - float64(h)) * 60)
 	if h == 0 {
@@ -15,34 +29,47 @@ func formatHours(hours float64) string {
 	return fmt.Sprintf("%dh %dm", h, m)
 }
 
-// formatMoney formats money as "$X,XXX.XX" with comma separators
-func formatMoney(amount float64) string {
-	negative := amount < 0
-	if negative {
-		amount = -amount
+// formatInvoiceHours formats hours on invoices and their line items, which
+// may use decimal hours even when the rest of the TUI uses "Xh Ym"
+func formatInvoiceHours(cfg config.InvoiceConfig, hours float64) string {
+	if cfg.HourFormat == config.HourFormatDecimal {
+		return activeLocale.Number(hours, 2)
 	}
+	return formatHours(hours)
+}
 
-	s := fmt.Sprintf("%.2f", amount)
+// formatMoney formats money using the configured locale, e.g. "$1,234.56"
+func formatMoney(amount float64) string {
+	return activeLocale.Money(amount)
+}
 
-	// Split at decimal point
-	dotPos := len(s) - 3
-	intPart := s[:dotPos]
-	decPart := s[dotPos:]
+// formatRate formats an hourly rate rounded to whole units, e.g. "$150/hr"
+func formatRate(rate float64) string {
+	return activeLocale.MoneyWhole(rate) + "/hr"
+}
 
-	// Add commas to integer part
-	result := make([]byte, 0, len(intPart)+len(intPart)/3)
-	for i, c := range intPart {
-		if i > 0 && (len(intPart)-i)%3 == 0 {
-			result = append(result, ',')
-		}
-		result = append(result, byte(c))
-	}
+// formatShortDate formats a date without the year using the configured locale
+func formatShortDate(t time.Time) string {
+	return activeLocale.FormatShortDate(t)
+}
+
+// formatLongDate formats a date with the year using the configured locale
+func formatLongDate(t time.Time) string {
+	return activeLocale.FormatLongDate(t)
+}
 
-	prefix := "$"
-	if negative {
-		prefix = "-$"
+// formatSize formats a byte count with a binary unit, e.g. "1.5 MiB"
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
 	}
-	return prefix + string(result) + decPart
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 // truncateStr truncates a string to the specified length with ellipsis
@@ -55,3 +82,15 @@ func truncateStr(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// escapeNewlines shows line breaks as \n so multi-line text fits a single
+// line input
+func escapeNewlines(s string) string {
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// unescapeNewlines turns \n typed into a single line input back into
+// line breaks
+func unescapeNewlines(s string) string {
+	return strings.ReplaceAll(s, `\n`, "\n")
+}
@@ -1,15 +1,21 @@
 package tui
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/config"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/render"
+	"github.com/andy/timesink/internal/service"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -23,32 +29,179 @@ const (
 	invoiceViewDetail                        // Viewing a single invoice
 	invoiceViewGenPickClient                 // Step 1: pick client
 	invoiceViewGenPreview                    // Step 2: preview entries
+	invoiceViewGenRange                      // Step 2a: change the preview's dates
 	invoiceViewGenSavePath                   // Step 3: choose save path
+	invoiceViewDraftAll                      // y/n before drafting every client's invoice
 )
 
+// invoicesListChrome is the number of lines in the list view that are not
+// invoice rows: title, an error, column header, scroll indicators and help
+const invoicesListChrome = 9
+
+// invoiceSort is the order of the invoices list
+type invoiceSort int
+
+const (
+	invoiceSortDate   invoiceSort = iota // newest first
+	invoiceSortTotal                     // largest first
+	invoiceSortStatus                    // most urgent first
+	invoiceSortClient                    // A to Z
+	invoiceSortCount
+)
+
+var invoiceSortNames = [invoiceSortCount]string{"date", "total", "status", "client"}
+
+// invoiceStatusOrder ranks statuses for sorting by status, those needing
+// attention first
+var invoiceStatusOrder = map[domain.InvoiceStatus]int{
+	domain.InvoiceStatusOverdue:   0,
+	domain.InvoiceStatusSent:      1,
+	domain.InvoiceStatusFinalized: 2,
+	domain.InvoiceStatusDraft:     3,
+	domain.InvoiceStatusPaid:      4,
+	domain.InvoiceStatusVoid:      5,
+}
+
+// invoiceStatusFilters are the filters the status filter key steps through,
+// starting from every invoice
+var invoiceStatusFilters = [][]domain.InvoiceStatus{
+	nil,
+	{domain.InvoiceStatusSent, domain.InvoiceStatusOverdue},
+	{domain.InvoiceStatusOverdue},
+	{domain.InvoiceStatusDraft},
+	{domain.InvoiceStatusFinalized},
+	{domain.InvoiceStatusPaid},
+	{domain.InvoiceStatusVoid},
+}
+
 // InvoicesModel displays invoices in list and detail views
 type InvoicesModel struct {
 	app       *app.App
 	mode      invoiceViewMode
-	invoices  []*domain.Invoice
-	cursor    int
+	list      listView[*domain.Invoice] // those shown, after the status filter
 	selected  *domain.Invoice
 	lineItems []*domain.InvoiceLineItem
 	loading   bool
 	err       error
-	statusMsg string
+
+	// Every invoice, the statuses to show when the list is filtered, and
+	// the order to list them in
+	allInvoices  []*domain.Invoice
+	statusFilter []domain.InvoiceStatus
+	sortBy       invoiceSort
+
+	// Deliveries and attachments of the selected invoice
+	deliveries   []*domain.InvoiceDelivery
+	attachments  []*domain.Attachment
+	attachCursor int
+	attaching    bool
+	attachInput  textinput.Model
+
+	// Rewording a line item of a draft: picking the line, then editing it
+	itemPicking bool
+	itemCursor  int
+	itemEditing bool
+	itemInput   textinput.Model
+
+	// Asks before finalizing or voiding the selected draft
+	confirm confirmDialog
 
 	// Invoice generation state
-	genClients   []*domain.Client
-	genCursor    int
-	genClient    *domain.Client
-	genEntries   []*domain.TimeEntry
+	genPicker     clientPicker
+	genClient     *domain.Client
+	genEntries    []*domain.TimeEntry
+	genHeld       int
 	savePathInput textinput.Model
+
+	// Dates of the previewed entries; a zero start is since the beginning.
+	// genUnbilled counts each client's invoiceable entries at any date, so
+	// the preview can tell how many fall outside the dates.
+	genStart    time.Time
+	genEnd      time.Time
+	genUnbilled map[int64]int
+	rangeInputs [2]textinput.Model
+	rangeFocus  int
+	rangeErr    error
+
+	// Month to draft every client's invoice for
+	draftMonth time.Time
+
+	// File of the invoice last generated, for opening it
+	lastFile string
+
+	// Footer of the invoice being generated, editable with the save path
+	notesInput   textinput.Model
+	paymentInput textinput.Model
+	genFocus     int
 }
 
-// IsCapturingInput returns true when the save path input is active
+// Inputs of the save path step, in tab order
+const (
+	genFieldSavePath = iota
+	genFieldNotes
+	genFieldPayment
+	genFieldCount
+)
+
+// IsCapturingInput returns true when the client picker, a confirmation or
+// the save path, date range, attach or line item input is active
 func (m *InvoicesModel) IsCapturingInput() bool {
-	return m.mode == invoiceViewGenSavePath
+	return m.mode == invoiceViewGenPickClient || m.mode == invoiceViewGenSavePath || m.mode == invoiceViewGenRange || m.mode == invoiceViewDraftAll || m.attaching || m.itemEditing || m.confirm.Active()
+}
+
+// KeyHelp lists the keys for the current step
+func (m *InvoicesModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	switch m.mode {
+	case invoiceViewDetail:
+		if m.confirm.Active() {
+			return m.confirm.KeyHelp()
+		}
+		if m.attaching {
+			return []key.Binding{withHelp(k.Select, "attach"), k.Cancel}
+		}
+		if m.itemEditing {
+			return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+		}
+		if m.itemPicking {
+			return []key.Binding{navigateKeys(), withHelp(k.Select, "reword"), withHelp(k.Back, "done")}
+		}
+		keys := []key.Binding{k.Attach, k.InvoiceEntries}
+		if len(m.attachments) > 0 {
+			keys = []key.Binding{navigateKeys(), k.OpenAttachment, k.Attach, k.InvoiceEntries}
+		}
+		if m.selected != nil && m.selected.CanEdit() && len(m.lineItems) > 0 {
+			keys = append(keys, k.RewordItem)
+		}
+		if m.selected != nil && m.selected.CanEdit() {
+			keys = append(keys, k.Finalize, withHelp(k.Delete, "void draft"))
+		}
+		return append(keys, withHelp(k.Back, "back to list"))
+	case invoiceViewGenPickClient:
+		return pickerKeys("select")
+	case invoiceViewGenPreview:
+		return []key.Binding{withHelp(k.Select, "generate"), k.ChangeDates, withHelp(k.Back, "back to client selection")}
+	case invoiceViewGenRange:
+		return []key.Binding{k.NextField, withHelp(k.Select, "show entries"), withHelp(k.Cancel, "back")}
+	case invoiceViewGenSavePath:
+		return []key.Binding{k.NextField, withHelp(k.Select, "generate and save"), withHelp(k.Cancel, "back")}
+	case invoiceViewDraftAll:
+		return []key.Binding{withHelp(k.Confirm, "draft"), withHelp(k.Left, "previous month"), withHelp(k.Right, "next month"), denyKey()}
+	}
+	keys := []key.Binding{withHelp(k.New, "new invoice"), k.DraftAll}
+	if m.list.Len() > 0 {
+		keys = append([]key.Binding{navigateKeys(), withHelp(k.Select, "view detail")}, keys...)
+	}
+	if len(m.allInvoices) > 0 {
+		keys = append(keys, withHelp(k.SortList, "sort by "+invoiceSortNames[(m.sortBy+1)%invoiceSortCount]), k.StatusFilter)
+	}
+	if m.lastFile != "" {
+		keys = append(keys, k.OpenFile)
+	}
+	if len(m.statusFilter) > 0 {
+		keys = append(keys, withHelp(k.Back, "show all"))
+	}
+	return keys
 }
 
 type invoicesDataMsg struct {
@@ -57,37 +210,76 @@ type invoicesDataMsg struct {
 }
 
 type invoiceDetailMsg struct {
-	invoice   *domain.Invoice
-	lineItems []*domain.InvoiceLineItem
-	err       error
+	invoice     *domain.Invoice
+	lineItems   []*domain.InvoiceLineItem
+	deliveries  []*domain.InvoiceDelivery
+	attachments []*domain.Attachment
+	err         error
+}
+
+// attachDoneMsg signals a file was attached to the selected invoice
+type attachDoneMsg struct {
+	attachment *domain.Attachment
+	err        error
+}
+
+// itemRewordedMsg signals a line item of the selected draft was reworded
+type itemRewordedMsg struct {
+	item *domain.InvoiceLineItem
+	err  error
+}
+
+// invoiceChangedMsg signals the selected draft was finalized or voided
+type invoiceChangedMsg struct {
+	id     int64
+	text   string // what was done, for the notification
+	voided bool   // the invoice left the list, so the detail closes
+	err    error
 }
 
 // genClientsMsg carries clients that have unbilled time
 type genClientsMsg struct {
-	clients []*domain.Client
-	err     error
+	clients  []*domain.Client
+	unbilled map[int64]int // invoiceable entries by client ID
+	err      error
 }
 
-// genEntriesMsg carries unbilled entries for a selected client
+// genEntriesMsg carries unbilled entries for a selected client between two
+// dates
 type genEntriesMsg struct {
 	entries []*domain.TimeEntry
+	held    int // entries left out until the client approves them
+	start   time.Time
+	end     time.Time
 	err     error
 }
 
 // genDoneMsg signals invoice generation completed
 type genDoneMsg struct {
-	invoice  *domain.Invoice
-	filePath string
-	err      error
+	invoice       *domain.Invoice
+	filePath      string
+	timesheetPath string
+	err           error
+}
+
+// draftAllMsg carries what drafting every client's invoice did
+type draftAllMsg struct {
+	results []*service.GroupDraft
+	err     error
 }
 
 // NewInvoicesModel creates a new invoices screen model
 func NewInvoicesModel(a *app.App) tea.Model {
-	return &InvoicesModel{
+	m := &InvoicesModel{
 		app:     a,
 		mode:    invoiceViewList,
 		loading: true,
 	}
+	m.list = newListView(renderInvoiceRow)
+	m.list.SetHeader(fmt.Sprintf("  %-14s  %-20s  %-22s  %10s  %s",
+		"Number", "Client", "Period", "Total", "Status"))
+	m.list.SetEmpty("No invoices yet. Press 'n' to generate one.")
+	return m
 }
 
 func (m *InvoicesModel) Init() tea.Cmd {
@@ -95,25 +287,35 @@ func (m *InvoicesModel) Init() tea.Cmd {
 }
 
 func (m *InvoicesModel) loadInvoices() tea.Cmd {
+	key := "invoices:" + today()
 	return func() tea.Msg {
-		ctx := context.Background()
-		invoices, err := m.app.InvoiceService.ListInvoices(ctx, nil, nil)
-		if err != nil {
-			return invoicesDataMsg{err: err}
-		}
+		msg, _ := cached(screenData, key, func() (invoicesDataMsg, error) {
+			msg := m.fetchInvoices()
+			return msg, msg.err
+		})
+		return msg
+	}
+}
 
-		// Resolve client names
-		for _, inv := range invoices {
-			if inv.ClientID > 0 && inv.Client == nil {
-				client, err := m.app.ClientRepo.GetByID(ctx, inv.ClientID)
-				if err == nil {
-					inv.Client = client
-				}
-			}
-		}
+func (m *InvoicesModel) fetchInvoices() invoicesDataMsg {
+	ctx := context.Background()
+	invoices, err := m.app.InvoiceService.ListInvoices(ctx, nil, nil)
+	if err != nil {
+		return invoicesDataMsg{err: err}
+	}
 
-		return invoicesDataMsg{invoices: invoices}
+	// Resolve client names
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		return invoicesDataMsg{err: err}
+	}
+	for _, inv := range invoices {
+		if inv.ClientID > 0 && inv.Client == nil {
+			inv.Client = clients[inv.ClientID]
+		}
 	}
+
+	return invoicesDataMsg{invoices: invoices}
 }
 
 func (m *InvoicesModel) loadDetail(id int64) tea.Cmd {
@@ -130,6 +332,10 @@ func (m *InvoicesModel) loadDetail(id int64) tea.Cmd {
 			return invoiceDetailMsg{err: err}
 		}
 
+		if invoice.Taxes, err = m.app.InvoiceRepo.GetTaxes(ctx, id); err != nil {
+			return invoiceDetailMsg{err: err}
+		}
+
 		if invoice.Client == nil && invoice.ClientID > 0 {
 			client, err := m.app.ClientRepo.GetByID(ctx, invoice.ClientID)
 			if err == nil {
@@ -137,11 +343,22 @@ func (m *InvoicesModel) loadDetail(id int64) tea.Cmd {
 			}
 		}
 
-		return invoiceDetailMsg{invoice: invoice, lineItems: lineItems}
+		deliveries, err := m.app.DeliveryService.List(ctx, id)
+		if err != nil {
+			return invoiceDetailMsg{err: err}
+		}
+
+		attachments, err := m.app.AttachmentService.List(ctx, domain.AttachmentOwnerInvoice, id)
+		if err != nil {
+			return invoiceDetailMsg{err: err}
+		}
+
+		return invoiceDetailMsg{invoice: invoice, lineItems: lineItems, deliveries: deliveries, attachments: attachments}
 	}
 }
 
-// loadGenClients loads active clients that have unbilled time entries
+// loadGenClients loads active clients that have unbilled time entries they
+// can be invoiced for, from a single scan of the unbilled entries
 func (m *InvoicesModel) loadGenClients() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -150,48 +367,99 @@ func (m *InvoicesModel) loadGenClients() tea.Cmd {
 		if err != nil {
 			return genClientsMsg{err: err}
 		}
+		byID := make(map[int64]*domain.Client, len(allClients))
+		for _, c := range allClients {
+			byID[c.ID] = c
+		}
 
-		// Filter to clients with unbilled entries
-		start := time.Now().AddDate(-10, 0, 0)
-		end := time.Now()
+		entries, err := m.app.EntryRepo.List(ctx, nil, nil, nil, false)
+		if err != nil {
+			return genClientsMsg{err: err}
+		}
+		unbilled := make(map[int64]int)
+		for _, entry := range entries {
+			client, ok := byID[entry.ClientID]
+			if ok && entry.EndTime != nil && client.CanInvoice(entry) {
+				unbilled[client.ID]++
+			}
+		}
 
 		var withUnbilled []*domain.Client
 		for _, client := range allClients {
-			entries, err := m.app.EntryRepo.GetUnbilledByClient(ctx, client.ID, start, end)
-			if err != nil {
-				continue
-			}
-			if len(entries) > 0 {
+			if unbilled[client.ID] > 0 {
 				withUnbilled = append(withUnbilled, client)
 			}
 		}
 
-		return genClientsMsg{clients: withUnbilled}
+		return genClientsMsg{clients: withUnbilled, unbilled: unbilled}
 	}
 }
 
-// loadGenEntries loads unbilled entries for the selected client
+// loadGenEntries loads the selected client's unbilled entries since their
+// last invoice's period ended, leaving out unapproved ones if the client
+// requires approval
 func (m *InvoicesModel) loadGenEntries() tea.Cmd {
-	clientID := m.genClient.ID
+	client := m.genClient
 	return func() tea.Msg {
 		ctx := context.Background()
-		start := time.Now().AddDate(-10, 0, 0)
-		end := time.Now()
+		now := time.Now()
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
-		entries, err := m.app.EntryRepo.GetUnbilledByClient(ctx, clientID, start, end)
+		lastEnd, err := m.app.InvoiceRepo.GetLastPeriodEnd(ctx, client.ID)
 		if err != nil {
 			return genEntriesMsg{err: err}
 		}
-		return genEntriesMsg{entries: entries}
+		entries, err := m.app.EntryRepo.GetUnbilledSinceLastInvoice(ctx, client.ID, now)
+		if err != nil {
+			return genEntriesMsg{err: err}
+		}
+
+		var start time.Time
+		if lastEnd != nil {
+			start = time.Date(lastEnd.Year(), lastEnd.Month(), lastEnd.Day(), 0, 0, 0, 0, lastEnd.Location()).AddDate(0, 0, 1)
+		}
+		return splitApproved(client, entries, start, end)
 	}
 }
 
-// generateInvoice creates draft, adds entries, calculates totals, finalizes, and exports .txt
+// loadGenEntriesBetween loads the selected client's unbilled entries that
+// started on the days from start through end
+func (m *InvoicesModel) loadGenEntriesBetween(start, end time.Time) tea.Cmd {
+	client := m.genClient
+	return func() tea.Msg {
+		entries, err := m.app.EntryRepo.GetUnbilledByClient(context.Background(), client.ID, start, end.AddDate(0, 0, 1).Add(-time.Second))
+		if err != nil {
+			return genEntriesMsg{err: err}
+		}
+		return splitApproved(client, entries, start, end)
+	}
+}
+
+// splitApproved separates the entries the client can be invoiced for from
+// those held back for approval
+func splitApproved(client *domain.Client, entries []*domain.TimeEntry, start, end time.Time) genEntriesMsg {
+	msg := genEntriesMsg{start: start, end: end}
+	for _, entry := range entries {
+		if client.CanInvoice(entry) {
+			msg.entries = append(msg.entries, entry)
+		} else {
+			msg.held++
+		}
+	}
+	return msg
+}
+
+// generateInvoice creates draft, adds entries, calculates totals, finalizes, and exports
+// the invoice in the format of the save path's extension
 func (m *InvoicesModel) generateInvoice() tea.Cmd {
 	client := m.genClient
 	entries := m.genEntries
 	a := m.app
 	savePath := m.savePathInput.Value()
+	footer := domain.InvoiceFooter{
+		Notes:               unescapeNewlines(m.notesInput.Value()),
+		PaymentInstructions: unescapeNewlines(m.paymentInput.Value()),
+	}
 
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -215,154 +483,161 @@ func (m *InvoicesModel) generateInvoice() tea.Cmd {
 		periodEnd = time.Date(periodEnd.Year(), periodEnd.Month(), periodEnd.Day(),
 			23, 59, 59, 0, periodEnd.Location())
 
-		// 1. Create draft
 		prefix := a.Config.Invoice.NumberPrefix
 		if prefix == "" {
 			prefix = "INV"
 		}
-		invoice, err := a.InvoiceService.CreateDraft(ctx, client.ID, periodStart, periodEnd, prefix)
-		if err != nil {
-			return genDoneMsg{err: fmt.Errorf("create draft: %w", err)}
-		}
-
-		// 2. Add entries
 		entryIDs := make([]int64, len(entries))
 		for i, e := range entries {
 			entryIDs[i] = e.ID
 		}
-		if err := a.InvoiceService.AddEntriesToInvoice(ctx, invoice.ID, entryIDs); err != nil {
-			return genDoneMsg{err: fmt.Errorf("add entries: %w", err)}
-		}
-
-		// 3. Calculate totals
-		taxRate := a.Config.Invoice.DefaultTaxRate
-		if err := a.InvoiceService.CalculateTotals(ctx, invoice.ID, taxRate); err != nil {
-			return genDoneMsg{err: fmt.Errorf("calculate totals: %w", err)}
-		}
-
-		// 4. Finalize (locks entries)
-		if err := a.InvoiceService.Finalize(ctx, invoice.ID); err != nil {
-			return genDoneMsg{err: fmt.Errorf("finalize: %w", err)}
-		}
 
-		// Reload invoice for final totals
-		invoice, err = a.InvoiceService.GetInvoice(ctx, invoice.ID)
+		// 1-4. Create draft, add entries, total and finalize in one transaction
+		invoice, err := a.InvoiceService.Generate(ctx, client.ID, periodStart, periodEnd, prefix,
+			a.InvoiceTaxes(client), footer, entryIDs, a.Config.Invoice.DefaultDueDays)
 		if err != nil {
-			return genDoneMsg{err: fmt.Errorf("reload invoice: %w", err)}
+			return genDoneMsg{err: err}
 		}
 		invoice.Client = client
 
-		// Set due date
-		dueDays := a.Config.Invoice.DefaultDueDays
-		if dueDays <= 0 {
-			dueDays = 30
-		}
-		dueDate := time.Now().AddDate(0, 0, dueDays)
-		invoice.DueDate = &dueDate
-
-		// Load line items for the .txt
+		// Load line items for the export
 		lineItems, err := a.InvoiceRepo.GetLineItems(ctx, invoice.ID)
 		if err != nil {
 			return genDoneMsg{err: fmt.Errorf("load line items: %w", err)}
 		}
 
-		// 5. Generate .txt file — replace placeholder in save path with real invoice number
-		finalPath := strings.Replace(savePath, fmt.Sprintf("%s-%d-xxx.txt", prefix, time.Now().Year()), invoice.InvoiceNumber+".txt", 1)
-		if finalPath == savePath && !strings.HasSuffix(finalPath, ".txt") {
+		// 5. Export — replace placeholder in save path with real invoice number;
+		// the extension picks the format
+		finalPath := strings.Replace(savePath, fmt.Sprintf("%s-%d-xxx", prefix, time.Now().Year()), invoice.InvoiceNumber, 1)
+		exporter, ok := render.ExporterFor(finalPath)
+		if !ok {
 			// User typed a directory — append the invoice filename
-			finalPath = filepath.Join(finalPath, invoice.InvoiceNumber+".txt")
+			exporter, _ = render.ExporterNamed(render.DefaultExporter)
+			finalPath = filepath.Join(finalPath, invoice.InvoiceNumber+exporter.Extensions()[0])
 		}
-		filePath, err := writeInvoiceTxt(a, invoice, lineItems, finalPath)
+		data, err := renderInvoiceDoc(a, exporter, invoice, lineItems)
 		if err != nil {
-			return genDoneMsg{err: fmt.Errorf("write txt: %w", err)}
+			return genDoneMsg{err: fmt.Errorf("render %s: %w", exporter.Name(), err)}
+		}
+		if err := saveFile(finalPath, data); err != nil {
+			return genDoneMsg{err: fmt.Errorf("write %s: %w", exporter.Name(), err)}
 		}
 
-		return genDoneMsg{invoice: invoice, filePath: filePath}
-	}
-}
+		// 6. Timesheet appendix for clients that want one
+		var timesheetPath string
+		if client.AttachTimesheet {
+			timesheetPath = strings.TrimSuffix(finalPath, filepath.Ext(finalPath)) + "-timesheet.txt"
+			var b bytes.Buffer
+			if err := render.Timesheet(&b, invoice, entries, renderOptions(a)); err != nil {
+				return genDoneMsg{err: fmt.Errorf("render timesheet: %w", err)}
+			}
+			if err := saveFile(timesheetPath, b.Bytes()); err != nil {
+				return genDoneMsg{err: fmt.Errorf("write timesheet: %w", err)}
+			}
+		}
 
-// writeInvoiceTxt writes a formatted text invoice to the given file path
-func writeInvoiceTxt(a *app.App, inv *domain.Invoice, items []*domain.InvoiceLineItem, filePath string) (string, error) {
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-		return "", fmt.Errorf("create output dir: %w", err)
+		return genDoneMsg{invoice: invoice, filePath: finalPath, timesheetPath: timesheetPath}
 	}
-	var b strings.Builder
+}
 
-	sep := strings.Repeat("=", 56)
-	line := strings.Repeat("-", 56)
+// draftAll creates a draft invoice for every client with unbilled time in
+// draftMonth
+func (m *InvoicesModel) draftAll() tea.Cmd {
+	a := m.app
+	start := m.draftMonth
+	end := start.AddDate(0, 1, 0).Add(-time.Second)
 
-	b.WriteString("INVOICE\n")
-	b.WriteString(sep + "\n")
-	b.WriteString(fmt.Sprintf("Invoice #:  %s\n", inv.InvoiceNumber))
-	b.WriteString(fmt.Sprintf("Date:       %s\n", time.Now().Format("Jan 02, 2006")))
-	if inv.DueDate != nil {
-		b.WriteString(fmt.Sprintf("Due:        %s\n", inv.DueDate.Format("Jan 02, 2006")))
+	return func() tea.Msg {
+		prefix := a.Config.Invoice.NumberPrefix
+		if prefix == "" {
+			prefix = "INV"
+		}
+		results, err := a.InvoiceService.DraftAll(context.Background(), start, end, prefix,
+			a.InvoiceTaxes, a.InvoiceFooter())
+		return draftAllMsg{results: results, err: err}
 	}
+}
 
-	// From section (user info)
-	user := a.Config.User
-	if user.Name != "" || user.Email != "" {
-		b.WriteString("\nFrom:\n")
-		if user.Name != "" {
-			b.WriteString(fmt.Sprintf("  %s\n", user.Name))
-		}
-		if user.Email != "" {
-			b.WriteString(fmt.Sprintf("  %s\n", user.Email))
+// draftAllSummary describes what drafting every client's invoice did, and
+// how loudly to say it
+func draftAllSummary(month time.Time, results []*service.GroupDraft) (NotifyLevel, string) {
+	var drafted, failed, held int
+	var total domain.Money
+	for _, r := range results {
+		if r.Invoice != nil {
+			drafted++
+			total += r.Invoice.Total
 		}
-		if user.Address != "" {
-			b.WriteString(fmt.Sprintf("  %s\n", user.Address))
-		}
-		if user.Phone != "" {
-			b.WriteString(fmt.Sprintf("  %s\n", user.Phone))
+		if r.Err != nil {
+			failed++
 		}
+		held += r.Held
 	}
-
-	// Bill To section
-	b.WriteString("\nBill To:\n")
-	if inv.Client != nil {
-		b.WriteString(fmt.Sprintf("  %s\n", inv.Client.Name))
-		if inv.Client.Email != "" {
-			b.WriteString(fmt.Sprintf("  %s\n", inv.Client.Email))
-		}
+	if drafted == 0 && failed == 0 && held == 0 {
+		return NotifyWarning, fmt.Sprintf("No unbilled time in %s", month.Format("January 2006"))
 	}
 
-	b.WriteString("\n" + line + "\n")
-	b.WriteString(fmt.Sprintf("%-12s %-24s %8s %10s\n", "Date", "Description", "Hours", "Amount"))
-	b.WriteString(line + "\n")
+	level := NotifySuccess
+	text := fmt.Sprintf("Drafted %d invoice(s) for %s totalling %s", drafted, month.Format("January 2006"), formatMoney(total.Float()))
+	if held > 0 {
+		level = NotifyWarning
+		text += fmt.Sprintf("; %d entries await approval", held)
+	}
+	if failed > 0 {
+		level = NotifyError
+		text += fmt.Sprintf("; %d client(s) failed", failed)
+	}
+	return level, text
+}
 
-	for _, item := range items {
-		desc := item.Description
-		if len(desc) > 24 {
-			desc = desc[:21] + "..."
-		}
-		b.WriteString(fmt.Sprintf("%-12s %-24s %8s %10s\n",
-			item.Date.Format("Jan 02"),
-			desc,
-			formatHours(item.Hours),
-			formatMoney(item.Amount),
-		))
+// renderOptions formats exported documents with the TUI's locale and the
+// user's invoice settings
+func renderOptions(a *app.App) render.Options {
+	return render.Options{
+		Locale:     activeLocale,
+		HourFormat: a.Config.Invoice.HourFormat,
+		From:       a.Config.User,
+		Date:       time.Now(),
 	}
+}
 
-	b.WriteString(line + "\n")
-	b.WriteString(fmt.Sprintf("%46s %10s\n", "Subtotal", formatMoney(inv.Subtotal)))
-	if inv.TaxRate > 0 {
-		b.WriteString(fmt.Sprintf("%38s (%.1f%%) %10s\n", "Tax", inv.TaxRate*100, formatMoney(inv.TaxAmount)))
-	} else {
-		b.WriteString(fmt.Sprintf("%46s %10s\n", "Tax", formatMoney(inv.TaxAmount)))
+// renderInvoiceDoc renders the invoice with exporter in the client's
+// language
+func renderInvoiceDoc(a *app.App, exporter render.Exporter, inv *domain.Invoice, items []*domain.InvoiceLineItem) ([]byte, error) {
+	opts := renderOptions(a)
+	labels, err := a.InvoiceLabels(inv.Client)
+	if err != nil {
+		return nil, err
 	}
-	b.WriteString(fmt.Sprintf("%46s %10s\n", "TOTAL", formatMoney(inv.Total)))
-	b.WriteString(sep + "\n")
+	opts.Labels = labels
+	return exporter.Render(inv, items, opts)
+}
 
-	if err := os.WriteFile(filePath, []byte(b.String()), 0644); err != nil {
-		return "", err
+// saveFile writes data to filePath, creating its directory if needed
+func saveFile(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
 	}
+	return os.WriteFile(filePath, data, 0644)
+}
 
-	return filePath, nil
+// exportExtensions lists the file extensions the save path can end in, e.g.
+// ".html, .md, .txt"
+func exportExtensions() string {
+	var exts []string
+	for _, e := range render.Exporters() {
+		exts = append(exts, e.Extensions()[0])
+	}
+	return strings.Join(exts, ", ")
 }
 
 func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Fit the list to the space the root model gives us
+		m.list.SetHeight(msg.Height - invoicesListChrome)
+		return m, nil
+
 	case RefreshDataMsg:
 		m.loading = true
 		return m, m.loadInvoices()
@@ -370,7 +645,17 @@ func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case invoicesDataMsg:
 		m.loading = false
 		m.err = msg.err
-		m.invoices = msg.invoices
+		m.allInvoices = msg.invoices
+		m.filterInvoices()
+		return m, nil
+
+	case filterInvoicesMsg:
+		m.mode = invoiceViewList
+		m.selected = nil
+		m.itemPicking = false
+		m.statusFilter = msg.statuses
+		m.list.Reset()
+		m.filterInvoices()
 		return m, nil
 
 	case invoiceDetailMsg:
@@ -381,9 +666,43 @@ func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.selected = msg.invoice
 		m.lineItems = msg.lineItems
+		m.deliveries = msg.deliveries
+		m.attachments = msg.attachments
+		if m.attachCursor >= len(m.attachments) {
+			m.attachCursor = max(len(m.attachments)-1, 0)
+		}
 		m.mode = invoiceViewDetail
 		return m, nil
 
+	case attachDoneMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		m.attachCursor = len(m.attachments)
+		text := fmt.Sprintf("Attached %s", msg.attachment.Name)
+		return m, tea.Batch(m.loadDetail(m.selected.ID), notify(NotifySuccess, text))
+
+	case itemRewordedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		text := "Line item reworded"
+		if !msg.item.IsReworded() {
+			text = "Line item description restored"
+		}
+		return m, tea.Batch(m.loadDetail(m.selected.ID), notify(NotifySuccess, text))
+
+	case invoiceChangedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		if msg.voided {
+			m.mode = invoiceViewList
+			m.selected = nil
+			return m, tea.Batch(m.loadInvoices(), notify(NotifySuccess, msg.text))
+		}
+		return m, tea.Batch(m.loadDetail(msg.id), m.loadInvoices(), notify(NotifySuccess, msg.text))
+
 	case genClientsMsg:
 		m.loading = false
 		if msg.err != nil {
@@ -396,10 +715,10 @@ func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.mode = invoiceViewList
 			return m, nil
 		}
-		m.genClients = msg.clients
-		m.genCursor = 0
+		m.genPicker = newClientPicker(msg.clients)
+		m.genUnbilled = msg.unbilled
 		m.mode = invoiceViewGenPickClient
-		return m, nil
+		return m, m.genPicker.Focus()
 
 	case genEntriesMsg:
 		m.loading = false
@@ -409,27 +728,46 @@ func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.genEntries = msg.entries
+		m.genHeld = msg.held
+		m.genStart, m.genEnd = msg.start, msg.end
 		m.mode = invoiceViewGenPreview
 		return m, nil
 
 	case genDoneMsg:
 		m.loading = false
 		if msg.err != nil {
-			m.err = msg.err
 			m.mode = invoiceViewList
-			return m, nil
+			return m, notifyErr(msg.err)
 		}
-		m.statusMsg = fmt.Sprintf("Invoice %s created -> %s", msg.invoice.InvoiceNumber, msg.filePath)
 		m.mode = invoiceViewList
-		m.genClients = nil
 		m.genEntries = nil
 		m.genClient = nil
-		return m, m.loadInvoices()
+		m.lastFile = msg.filePath
+		text := fmt.Sprintf("Invoice %s created -> %s", msg.invoice.InvoiceNumber, msg.filePath)
+		if msg.timesheetPath != "" {
+			text += " (+ timesheet)"
+		}
+		if m.app.Config.Invoice.OpenAfterGenerate {
+			return m, tea.Batch(m.loadInvoices(), notify(NotifySuccess, text), m.openLastFile())
+		}
+		return m, tea.Batch(m.loadInvoices(), notify(NotifySuccess, text))
+
+	case draftAllMsg:
+		m.loading = false
+		m.mode = invoiceViewList
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		level, text := draftAllSummary(m.draftMonth, msg.results)
+		return m, tea.Batch(m.loadInvoices(), notify(level, text))
 
 	case tea.KeyMsg:
 		if m.loading {
 			return m, nil
 		}
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
+		}
 
 		switch m.mode {
 		case invoiceViewList:
@@ -440,88 +778,398 @@ func (m *InvoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateGenPickClient(msg)
 		case invoiceViewGenPreview:
 			return m.updateGenPreview(msg)
+		case invoiceViewGenRange:
+			return m.updateGenRange(msg)
 		case invoiceViewGenSavePath:
 			return m.updateGenSavePath(msg)
+		case invoiceViewDraftAll:
+			return m.updateDraftAll(msg)
 		}
 	}
 
-	// Forward all non-key messages to save path input (for cursor blink, etc.)
+	// Forward all non-key messages to the focused input (for cursor blink, etc.)
+	if m.attaching {
+		var cmd tea.Cmd
+		m.attachInput, cmd = m.attachInput.Update(msg)
+		return m, cmd
+	}
+	if m.itemEditing {
+		var cmd tea.Cmd
+		m.itemInput, cmd = m.itemInput.Update(msg)
+		return m, cmd
+	}
 	if m.mode == invoiceViewGenSavePath {
+		input := m.genInput(m.genFocus)
+		var cmd tea.Cmd
+		*input, cmd = input.Update(msg)
+		return m, cmd
+	}
+	if m.mode == invoiceViewGenRange {
 		var cmd tea.Cmd
-		m.savePathInput, cmd = m.savePathInput.Update(msg)
+		m.rangeInputs[m.rangeFocus], cmd = m.rangeInputs[m.rangeFocus].Update(msg)
 		return m, cmd
 	}
 
 	return m, nil
 }
 
+// filterInvoices lists the invoices with a status in statusFilter, or all
+// of them without a filter, in the chosen order
+func (m *InvoicesModel) filterInvoices() {
+	var shown []*domain.Invoice
+	for _, inv := range m.allInvoices {
+		if len(m.statusFilter) == 0 || slices.Contains(m.statusFilter, inv.Status) {
+			shown = append(shown, inv)
+		}
+	}
+	sortInvoices(shown, m.sortBy)
+	m.list.SetItems(shown)
+
+	if len(m.statusFilter) == 0 {
+		m.list.SetEmpty("No invoices yet. Press 'n' to generate one.")
+	} else {
+		m.list.SetEmpty(fmt.Sprintf("No %s invoices. Press esc to show all.", strings.Join(m.statusNames(), " or ")))
+	}
+}
+
+// sortInvoices orders invoices by, newest first among equals
+func sortInvoices(invoices []*domain.Invoice, by invoiceSort) {
+	slices.SortStableFunc(invoices, func(a, b *domain.Invoice) int {
+		var c int
+		switch by {
+		case invoiceSortTotal:
+			c = cmp.Compare(b.Total, a.Total)
+		case invoiceSortStatus:
+			c = invoiceStatusOrder[a.Status] - invoiceStatusOrder[b.Status]
+		case invoiceSortClient:
+			c = strings.Compare(strings.ToLower(invoiceClientName(a)), strings.ToLower(invoiceClientName(b)))
+		}
+		if c == 0 {
+			c = b.CreatedAt.Compare(a.CreatedAt)
+		}
+		return c
+	})
+}
+
+// nextStatusFilter is the filter after current in invoiceStatusFilters. A
+// filter from elsewhere, such as the dashboard's unpaid invoices, steps back
+// to the start.
+func nextStatusFilter(current []domain.InvoiceStatus) []domain.InvoiceStatus {
+	for i, filter := range invoiceStatusFilters {
+		if slices.Equal(filter, current) {
+			return invoiceStatusFilters[(i+1)%len(invoiceStatusFilters)]
+		}
+	}
+	return invoiceStatusFilters[0]
+}
+
+// statusNames lists the statuses in the filter
+func (m *InvoicesModel) statusNames() []string {
+	names := make([]string, len(m.statusFilter))
+	for i, status := range m.statusFilter {
+		names[i] = string(status)
+	}
+	return names
+}
+
 func (m *InvoicesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.err = nil
 
 	switch {
-	case key.Matches(msg, DefaultKeyMap.Up):
-		if m.cursor > 0 {
-			m.cursor--
-		}
-	case key.Matches(msg, DefaultKeyMap.Down):
-		if m.cursor < len(m.invoices)-1 {
-			m.cursor++
+	case key.Matches(msg, DefaultKeyMap.Back):
+		if len(m.statusFilter) > 0 {
+			m.statusFilter = nil
+			m.list.Reset()
+			m.filterInvoices()
 		}
+	case m.list.Update(msg):
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.SortList):
+		m.sortBy = (m.sortBy + 1) % invoiceSortCount
+		m.list.Reset()
+		m.filterInvoices()
+	case key.Matches(msg, DefaultKeyMap.StatusFilter):
+		m.statusFilter = nextStatusFilter(m.statusFilter)
+		m.list.Reset()
+		m.filterInvoices()
 	case key.Matches(msg, DefaultKeyMap.Select):
-		if len(m.invoices) > 0 {
+		if inv, ok := m.list.Selected(); ok {
 			m.loading = true
-			return m, m.loadDetail(m.invoices[m.cursor].ID)
+			return m, m.loadDetail(inv.ID)
 		}
-	case msg.String() == "n":
+	case key.Matches(msg, DefaultKeyMap.New):
 		m.loading = true
 		m.err = nil
-		m.statusMsg = ""
 		return m, m.loadGenClients()
+	case key.Matches(msg, DefaultKeyMap.DraftAll):
+		now := time.Now()
+		m.draftMonth = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+		m.mode = invoiceViewDraftAll
+	case key.Matches(msg, DefaultKeyMap.OpenFile):
+		if m.lastFile != "" {
+			return m, m.openLastFile()
+		}
 	}
 
 	return m, nil
 }
 
+// openLastFile opens the invoice file generated last with the system's
+// default application
+func (m *InvoicesModel) openLastFile() tea.Cmd {
+	path := m.lastFile
+	return func() tea.Msg {
+		if err := m.app.OpenFile(path); err != nil {
+			return notifyErr(err)()
+		}
+		return nil
+	}
+}
+
+func (m *InvoicesModel) updateDraftAll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Confirm):
+		m.loading = true
+		return m, m.draftAll()
+	case key.Matches(msg, DefaultKeyMap.Left):
+		m.draftMonth = m.draftMonth.AddDate(0, -1, 0)
+	case key.Matches(msg, DefaultKeyMap.Right):
+		// Time after this month has yet to be worked
+		if next := m.draftMonth.AddDate(0, 1, 0); !next.After(time.Now()) {
+			m.draftMonth = next
+		}
+	case key.Matches(msg, denyKey()):
+		m.mode = invoiceViewList
+	}
+	return m, nil
+}
+
 func (m *InvoicesModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if key.Matches(msg, DefaultKeyMap.Back) {
+	if m.attaching {
+		return m.updateAttach(msg)
+	}
+	if m.itemEditing {
+		return m.updateItemInput(msg)
+	}
+	if m.itemPicking {
+		return m.updateItemPick(msg)
+	}
+
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Back):
 		m.mode = invoiceViewList
 		m.selected = nil
 		m.lineItems = nil
+		m.deliveries = nil
+		m.attachments = nil
+		m.attachCursor = 0
+		m.itemCursor = 0
+	case key.Matches(msg, DefaultKeyMap.Up):
+		if m.attachCursor > 0 {
+			m.attachCursor--
+		}
+	case key.Matches(msg, DefaultKeyMap.Down):
+		if m.attachCursor < len(m.attachments)-1 {
+			m.attachCursor++
+		}
+	case key.Matches(msg, DefaultKeyMap.OpenAttachment):
+		if len(m.attachments) > 0 {
+			attachment := m.attachments[m.attachCursor]
+			return m, func() tea.Msg {
+				if err := m.app.OpenAttachment(attachment); err != nil {
+					return notifyErr(err)()
+				}
+				return nil
+			}
+		}
+	case key.Matches(msg, DefaultKeyMap.Attach):
+		m.attachInput = textinput.New()
+		m.attachInput.Placeholder = "path/to/file.pdf"
+		m.attachInput.Width = 60
+		m.attachInput.CharLimit = 512
+		m.attaching = true
+		return m, m.attachInput.Focus()
+	case key.Matches(msg, DefaultKeyMap.InvoiceEntries):
+		invoice := m.selected
+		return m, func() tea.Msg {
+			return SwitchScreenMsg{Screen: ScreenEntries, Select: filterEntriesMsg{invoice: invoice}}
+		}
+	case key.Matches(msg, DefaultKeyMap.RewordItem):
+		if !m.selected.CanEdit() {
+			return m, notify(NotifyWarning, "Only draft invoices can be reworded")
+		}
+		if len(m.lineItems) > 0 {
+			m.itemCursor = min(m.itemCursor, len(m.lineItems)-1)
+			m.itemPicking = true
+		}
+	case key.Matches(msg, DefaultKeyMap.Finalize):
+		if !m.selected.CanEdit() {
+			return m, notify(NotifyWarning, "Only draft invoices can be finalized")
+		}
+		m.confirm.Ask("Finalize Invoice", "Finalize this invoice?",
+			append(m.describeInvoice(), "Its line items and time entries will be locked."),
+			func() tea.Cmd { return m.finalize(m.selected) })
+	case key.Matches(msg, DefaultKeyMap.Delete):
+		if !m.selected.CanDelete() {
+			return m, notify(NotifyWarning, "Only draft invoices can be voided")
+		}
+		m.confirm.Ask("Void Invoice", "Void this draft?",
+			append(m.describeInvoice(), "Its time entries can be invoiced again; the number stays taken."),
+			func() tea.Cmd { return m.void(m.selected) })
 	}
 	return m, nil
 }
 
-func (m *InvoicesModel) updateGenPickClient(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// describeInvoice sums up the selected invoice for a confirmation
+func (m *InvoicesModel) describeInvoice() []string {
+	inv := m.selected
+	return []string{
+		fmt.Sprintf("%s  %s  %s", inv.InvoiceNumber, invoiceClientName(inv), formatMoney(inv.Total.Float())),
+		fmt.Sprintf("%s - %s, %d line items", formatShortDate(inv.PeriodStart), formatLongDate(inv.PeriodEnd), len(m.lineItems)),
+	}
+}
+
+// finalize locks a draft, setting its due date from the configured terms
+func (m *InvoicesModel) finalize(inv *domain.Invoice) tea.Cmd {
+	a := m.app
+	return func() tea.Msg {
+		err := a.InvoiceService.Finalize(context.Background(), inv.ID, a.Config.Invoice.DefaultDueDays)
+		return invoiceChangedMsg{id: inv.ID, text: "Invoice " + inv.InvoiceNumber + " finalized", err: err}
+	}
+}
+
+// void deletes a draft, releasing its time entries
+func (m *InvoicesModel) void(inv *domain.Invoice) tea.Cmd {
+	a := m.app
+	return func() tea.Msg {
+		err := a.InvoiceService.Delete(context.Background(), inv.ID)
+		return invoiceChangedMsg{id: inv.ID, text: "Invoice " + inv.InvoiceNumber + " voided", voided: true, err: err}
+	}
+}
+
+// updateItemPick moves between the line items of a draft to pick one to
+// reword
+func (m *InvoicesModel) updateItemPick(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
-	case key.Matches(msg, DefaultKeyMap.Back):
-		m.mode = invoiceViewList
-		m.genClients = nil
-		return m, nil
+	case key.Matches(msg, DefaultKeyMap.Back), key.Matches(msg, DefaultKeyMap.Cancel):
+		m.itemPicking = false
 	case key.Matches(msg, DefaultKeyMap.Up):
-		if m.genCursor > 0 {
-			m.genCursor--
+		if m.itemCursor > 0 {
+			m.itemCursor--
 		}
 	case key.Matches(msg, DefaultKeyMap.Down):
-		if m.genCursor < len(m.genClients)-1 {
-			m.genCursor++
+		if m.itemCursor < len(m.lineItems)-1 {
+			m.itemCursor++
 		}
 	case key.Matches(msg, DefaultKeyMap.Select):
-		if len(m.genClients) > 0 {
-			m.genClient = m.genClients[m.genCursor]
-			m.loading = true
-			return m, m.loadGenEntries()
+		item := m.lineItems[m.itemCursor]
+		if item.IsAdjustment() {
+			return m, notify(NotifyWarning, "Adjustments follow the client's billing rules and cannot be reworded")
 		}
+		m.itemInput = textinput.New()
+		m.itemInput.Placeholder = item.OriginalDescription
+		m.itemInput.SetValue(item.Description)
+		m.itemInput.Width = 60
+		m.itemInput.CharLimit = 256
+		m.itemEditing = true
+		return m, m.itemInput.Focus()
 	}
 	return m, nil
 }
 
+// updateItemInput handles the new description of the picked line item; a
+// blank description restores the original
+func (m *InvoicesModel) updateItemInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Cancel):
+		m.itemEditing = false
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.Select):
+		m.itemEditing = false
+		m.itemPicking = false
+		invoiceID, itemID := m.selected.ID, m.lineItems[m.itemCursor].ID
+		description := m.itemInput.Value()
+		return m, func() tea.Msg {
+			item, err := m.app.InvoiceService.RewordLineItem(context.Background(), invoiceID, itemID, description)
+			return itemRewordedMsg{item: item, err: err}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.itemInput, cmd = m.itemInput.Update(msg)
+	return m, cmd
+}
+
+// updateAttach handles the file path prompt for a new attachment
+func (m *InvoicesModel) updateAttach(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Cancel):
+		m.attaching = false
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.Select):
+		path := strings.TrimSpace(m.attachInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.attaching = false
+		invoiceID := m.selected.ID
+		return m, func() tea.Msg {
+			attachment, err := m.app.AttachmentService.Attach(context.Background(), domain.AttachmentOwnerInvoice, invoiceID, path)
+			return attachDoneMsg{attachment: attachment, err: err}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.attachInput, cmd = m.attachInput.Update(msg)
+	return m, cmd
+}
+
+func (m *InvoicesModel) updateGenPickClient(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, DefaultKeyMap.Cancel) {
+		m.mode = invoiceViewList
+		return m, nil
+	}
+	client, cmd := m.genPicker.Update(msg)
+	if client != nil {
+		m.genClient = client
+		m.loading = true
+		return m, m.loadGenEntries()
+	}
+	return m, cmd
+}
+
 func (m *InvoicesModel) updateGenPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch {
 	case key.Matches(msg, DefaultKeyMap.Back):
 		m.mode = invoiceViewGenPickClient
 		m.genEntries = nil
 		return m, nil
+	case key.Matches(msg, DefaultKeyMap.ChangeDates):
+		start := m.genStart
+		if start.IsZero() {
+			start = m.genEnd
+			for _, e := range m.genEntries {
+				if e.StartTime.Before(start) {
+					start = e.StartTime
+				}
+			}
+		}
+		for i, t := range []time.Time{start, m.genEnd} {
+			ti := textinput.New()
+			ti.Placeholder = "2006-01-02"
+			ti.CharLimit = 10
+			ti.Width = 12
+			ti.SetValue(t.Format("2006-01-02"))
+			m.rangeInputs[i] = ti
+		}
+		m.rangeFocus = 0
+		m.rangeErr = nil
+		m.mode = invoiceViewGenRange
+		return m, m.rangeInputs[0].Focus()
 	case key.Matches(msg, DefaultKeyMap.Select):
+		if len(m.genEntries) == 0 {
+			return m, nil
+		}
 		// Initialize save path input with default
 		m.savePathInput = textinput.New()
 		m.savePathInput.Placeholder = "path/to/invoice.txt"
@@ -530,8 +1178,7 @@ func (m *InvoicesModel) updateGenPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 		outputDir := m.app.Config.Invoice.OutputDir
 		if outputDir == "" {
-			homeDir, _ := os.UserHomeDir()
-			outputDir = filepath.Join(homeDir, ".config", "timesink", "invoices")
+			outputDir = filepath.Join(config.ProfileDataDir(m.app.Profile), "invoices")
 		}
 		// Use a placeholder name since we don't have the invoice number yet
 		prefix := m.app.Config.Invoice.NumberPrefix
@@ -541,20 +1188,73 @@ func (m *InvoicesModel) updateGenPreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		defaultPath := filepath.Join(outputDir, fmt.Sprintf("%s-%d-xxx.txt", prefix, time.Now().Year()))
 		m.savePathInput.SetValue(defaultPath)
 
+		// Footer starts from config; line breaks are shown as \n
+		footer := m.app.InvoiceFooter()
+		m.notesInput = textinput.New()
+		m.notesInput.Placeholder = "Optional, e.g. Thank you for your business"
+		m.notesInput.Width = 60
+		m.notesInput.SetValue(escapeNewlines(footer.Notes))
+		m.paymentInput = textinput.New()
+		m.paymentInput.Placeholder = "Optional, e.g. IBAN DE89 3704 0044 0532 0130 00"
+		m.paymentInput.Width = 60
+		m.paymentInput.SetValue(escapeNewlines(footer.PaymentInstructions))
+
+		m.genFocus = genFieldSavePath
 		m.mode = invoiceViewGenSavePath
 		return m, m.savePathInput.Focus()
 	}
 	return m, nil
 }
 
+// updateGenRange handles the From and To dates of the entries to preview
+func (m *InvoicesModel) updateGenRange(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Cancel):
+		m.mode = invoiceViewGenPreview
+		return m, nil
+	case key.Matches(msg, DefaultKeyMap.NextField), key.Matches(msg, DefaultKeyMap.PrevField):
+		m.rangeInputs[m.rangeFocus].Blur()
+		m.rangeFocus = 1 - m.rangeFocus
+		return m, m.rangeInputs[m.rangeFocus].Focus()
+	case key.Matches(msg, DefaultKeyMap.Select):
+		var dates [2]time.Time
+		for i, input := range m.rangeInputs {
+			t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(input.Value()), time.Local)
+			if err != nil {
+				m.rangeErr = fmt.Errorf("dates must be YYYY-MM-DD")
+				return m, nil
+			}
+			dates[i] = t
+		}
+		if dates[1].Before(dates[0]) {
+			m.rangeErr = fmt.Errorf("the end date is before the start date")
+			return m, nil
+		}
+		m.loading = true
+		return m, m.loadGenEntriesBetween(dates[0], dates[1])
+	}
+
+	var cmd tea.Cmd
+	m.rangeInputs[m.rangeFocus], cmd = m.rangeInputs[m.rangeFocus].Update(msg)
+	return m, cmd
+}
+
 func (m *InvoicesModel) updateGenSavePath(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = invoiceViewGenPreview
 			return m, nil
-		case "enter":
+		case key.Matches(msg, DefaultKeyMap.NextField), key.Matches(msg, DefaultKeyMap.PrevField):
+			m.genInput(m.genFocus).Blur()
+			if key.Matches(msg, DefaultKeyMap.NextField) {
+				m.genFocus = (m.genFocus + 1) % genFieldCount
+			} else {
+				m.genFocus = (m.genFocus - 1 + genFieldCount) % genFieldCount
+			}
+			return m, m.genInput(m.genFocus).Focus()
+		case key.Matches(msg, DefaultKeyMap.Select):
 			savePath := m.savePathInput.Value()
 			if savePath == "" {
 				m.err = fmt.Errorf("save path cannot be empty")
@@ -565,17 +1265,34 @@ func (m *InvoicesModel) updateGenSavePath(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Update the text input
+	// Update the focused input
+	input := m.genInput(m.genFocus)
 	var cmd tea.Cmd
-	m.savePathInput, cmd = m.savePathInput.Update(msg)
+	*input, cmd = input.Update(msg)
 	return m, cmd
 }
 
+// genInput returns the save path step's input at index i
+func (m *InvoicesModel) genInput(i int) *textinput.Model {
+	switch i {
+	case genFieldNotes:
+		return &m.notesInput
+	case genFieldPayment:
+		return &m.paymentInput
+	default:
+		return &m.savePathInput
+	}
+}
+
 func (m *InvoicesModel) View() string {
 	if m.loading {
 		return "Loading..."
 	}
 
+	if m.confirm.Active() {
+		return m.confirm.View()
+	}
+
 	switch m.mode {
 	case invoiceViewDetail:
 		return m.viewDetail()
@@ -583,8 +1300,12 @@ func (m *InvoicesModel) View() string {
 		return m.viewGenPickClient()
 	case invoiceViewGenPreview:
 		return m.viewGenPreview()
+	case invoiceViewGenRange:
+		return m.viewGenRange()
 	case invoiceViewGenSavePath:
 		return m.viewGenSavePath()
+	case invoiceViewDraftAll:
+		return m.viewDraftAll()
 	default:
 		return m.viewList()
 	}
@@ -592,57 +1313,66 @@ func (m *InvoicesModel) View() string {
 
 func (m *InvoicesModel) viewList() string {
 	var s string
-	s += titleStyle.Render("Invoices") + "\n\n"
-
-	if m.statusMsg != "" {
-		s += lipgloss.NewStyle().Foreground(successColor).
-			Render("  "+m.statusMsg) + "\n\n"
+	title := "Invoices"
+	if len(m.statusFilter) > 0 {
+		title += " (" + strings.Join(m.statusNames(), ", ") + ")"
 	}
+	s += titleStyle.Render(title) + subtitleStyle.Render("  sorted by "+invoiceSortNames[m.sortBy]) + "\n\n"
 
 	if m.err != nil {
 		s += lipgloss.NewStyle().Foreground(errorColor).
 			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
 	}
 
-	if len(m.invoices) == 0 && m.err == nil {
-		s += subtitleStyle.Render("  No invoices yet. Press 'n' to generate one.")
-		return s
+	if m.err == nil || m.list.Len() > 0 {
+		s += m.list.View()
 	}
 
-	// Header
-	s += subtitleStyle.Render(fmt.Sprintf(
-		"  %-14s  %-20s  %-22s  %10s  %s",
-		"Number", "Client", "Period", "Total", "Status",
-	)) + "\n"
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
-	for i, inv := range m.invoices {
-		clientName := "Unknown"
-		if inv.Client != nil {
-			clientName = inv.Client.Name
-		}
+	return s
+}
 
-		period := fmt.Sprintf("%s - %s",
-			inv.PeriodStart.Format("Jan 02"),
-			inv.PeriodEnd.Format("Jan 02, 2006"),
-		)
+// invoiceClientName is the name of the invoice's client, if it was loaded
+func invoiceClientName(inv *domain.Invoice) string {
+	if inv.Client == nil {
+		return "Unknown"
+	}
+	return inv.Client.Name
+}
 
-		invLine := fmt.Sprintf("  %-14s  %-20s  %-22s  %10s  %s",
-			inv.InvoiceNumber,
-			truncateStr(clientName, 20),
-			period,
-			formatMoney(inv.Total),
-			statusBadge(inv.Status),
-		)
+// renderInvoiceRow draws an invoice as a row of the invoice list
+func renderInvoiceRow(inv *domain.Invoice, selected bool) string {
+	clientName := invoiceClientName(inv)
 
-		if i == m.cursor {
-			s += selectedStyle.Render(invLine) + "\n"
-		} else {
-			s += invLine + "\n"
-		}
-	}
+	period := fmt.Sprintf("%s - %s",
+		formatShortDate(inv.PeriodStart),
+		formatLongDate(inv.PeriodEnd),
+	)
 
-	s += "\n" + helpStyle.Render("  j/k: navigate  enter: view detail  n: new invoice")
+	invLine := fmt.Sprintf("  %-14s  %-20s  %-22s  %10s  %s",
+		inv.InvoiceNumber,
+		truncateStr(clientName, 20),
+		period,
+		formatMoney(inv.Total.Float()),
+		statusBadge(inv.Status),
+	)
+
+	if selected {
+		return selectedStyle.Render(invLine)
+	}
+	return invLine
+}
 
+func (m *InvoicesModel) viewDraftAll() string {
+	var s string
+	s += titleStyle.Render("Draft All Invoices") + "\n\n"
+	s += fmt.Sprintf("  Period: %s to %s\n", formatShortDate(m.draftMonth),
+		formatLongDate(m.draftMonth.AddDate(0, 1, -1)))
+	s += subtitleStyle.Render("  Each client with unbilled time gets a draft to review and finalize.") + "\n\n"
+	s += lipgloss.NewStyle().Foreground(warningColor).
+		Render(fmt.Sprintf("  Draft invoices for every client for %s? (y/n)", m.draftMonth.Format("January 2006"))) + "\n\n"
+	s += renderKeyHelp(m.KeyHelp()...)
 	return s
 }
 
@@ -663,11 +1393,11 @@ func (m *InvoicesModel) viewDetail() string {
 	s += titleStyle.Render(fmt.Sprintf("Invoice %s", inv.InvoiceNumber)) + "\n\n"
 	s += fmt.Sprintf("  Client:   %s\n", clientName)
 	s += fmt.Sprintf("  Period:   %s - %s\n",
-		inv.PeriodStart.Format("Jan 02, 2006"),
-		inv.PeriodEnd.Format("Jan 02, 2006"),
+		formatLongDate(inv.PeriodStart),
+		formatLongDate(inv.PeriodEnd),
 	)
 	if inv.DueDate != nil {
-		s += fmt.Sprintf("  Due:      %s\n", inv.DueDate.Format("Jan 02, 2006"))
+		s += fmt.Sprintf("  Due:      %s\n", formatLongDate(*inv.DueDate))
 	}
 	s += fmt.Sprintf("  Status:   %s\n", statusBadge(inv.Status))
 	s += "\n"
@@ -681,24 +1411,85 @@ func (m *InvoicesModel) viewDetail() string {
 			"Date", "Description", "Hours", "Amount",
 		)) + "\n"
 
-		for _, item := range m.lineItems {
-			s += fmt.Sprintf("  %-12s  %-35s  %8s  %10s\n",
-				item.Date.Format("Jan 02"),
+		for i, item := range m.lineItems {
+			line := fmt.Sprintf("  %-12s  %-35s  %8s  %10s",
+				formatShortDate(item.Date),
 				truncateStr(item.Description, 35),
-				formatHours(item.Hours),
-				formatMoney(item.Amount),
+				formatInvoiceHours(m.app.Config.Invoice, item.Hours),
+				formatMoney(item.Amount.Float()),
 			)
+			if m.itemPicking && i == m.itemCursor {
+				s += selectedStyle.Render(line) + "\n"
+			} else {
+				s += line + "\n"
+			}
+			if item.Role != "" {
+				s += subtitleStyle.Render(fmt.Sprintf("  %-12s  + %s at %s/hr", "", item.Role, formatMoney(item.Rate))) + "\n"
+			}
+			if item.RateReason != "" {
+				s += subtitleStyle.Render(fmt.Sprintf("  %-12s  * %s/hr: %s", "", formatMoney(item.Rate), item.RateReason)) + "\n"
+			}
+			if item.IsReworded() {
+				s += subtitleStyle.Render(fmt.Sprintf("  %-12s  ~ was: %s", "", item.OriginalDescription)) + "\n"
+			}
+		}
+		if m.itemEditing {
+			s += "\n  Description (blank restores the original): " + m.itemInput.View() + "\n"
 		}
 	}
 
 	s += "\n"
-	s += fmt.Sprintf("  Subtotal:  %10s\n", formatMoney(inv.Subtotal))
-	s += fmt.Sprintf("  Tax:       %10s\n", formatMoney(inv.TaxAmount))
+	s += fmt.Sprintf("  Subtotal:  %10s\n", formatMoney(inv.Subtotal.Float()))
+	if len(inv.Taxes) == 0 {
+		s += fmt.Sprintf("  Tax:       %10s\n", formatMoney(inv.TaxAmount.Float()))
+	}
+	for _, tax := range inv.Taxes {
+		label := fmt.Sprintf("%s (%s%%):", tax.Label, activeLocale.Number(tax.Rate*100, 1))
+		s += fmt.Sprintf("  %-10s %10s\n", label, formatMoney(tax.Amount.Float()))
+	}
 	s += lipgloss.NewStyle().Bold(true).Render(
-		fmt.Sprintf("  Total:     %10s", formatMoney(inv.Total)),
+		fmt.Sprintf("  Total:     %10s", formatMoney(inv.Total.Float())),
 	) + "\n"
+	for _, tax := range inv.Taxes {
+		if tax.Note != "" {
+			s += subtitleStyle.Render("  "+tax.Note) + "\n"
+		}
+	}
+	s += viewFooterText("Notes", inv.Footer.Notes)
+	s += viewFooterText("Payment Instructions", inv.Footer.PaymentInstructions)
+
+	if len(m.deliveries) > 0 {
+		s += "\n" + subtitleStyle.Render("  Deliveries") + "\n"
+		for _, d := range m.deliveries {
+			recipient := d.Recipient
+			if recipient == "" {
+				recipient = "(no recipient)"
+			}
+			opened := "not opened"
+			if d.OpenedAt != nil {
+				opened = "opened " + formatShortDate(*d.OpenedAt) + d.OpenedAt.Format(" 15:04")
+			}
+			s += fmt.Sprintf("  %-12s  %-30s  %s\n",
+				formatShortDate(d.SentAt)+d.SentAt.Format(" 15:04"), truncateStr(recipient, 30), opened)
+		}
+	}
 
-	s += "\n" + helpStyle.Render("  esc: back to list")
+	if len(m.attachments) > 0 {
+		s += "\n" + subtitleStyle.Render("  Attachments") + "\n"
+		for i, a := range m.attachments {
+			line := fmt.Sprintf("  %-40s  %10s  %s", truncateStr(a.Name, 40), formatSize(a.Size), formatShortDate(a.CreatedAt))
+			if i == m.attachCursor {
+				s += selectedStyle.Render(line) + "\n"
+			} else {
+				s += line + "\n"
+			}
+		}
+	}
+	if m.attaching {
+		s += "\n  Attach file: " + m.attachInput.View() + "\n"
+	}
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -706,32 +1497,9 @@ func (m *InvoicesModel) viewDetail() string {
 func (m *InvoicesModel) viewGenPickClient() string {
 	var s string
 	s += titleStyle.Render("New Invoice - Select Client") + "\n\n"
-
-	if len(m.genClients) == 0 {
-		s += subtitleStyle.Render("  No clients with unbilled time") + "\n"
-		s += "\n" + helpStyle.Render("  esc: back")
-		return s
-	}
-
 	s += subtitleStyle.Render("  Clients with unbilled time:") + "\n\n"
-
-	for i, client := range m.genClients {
-		indicator := "  "
-		if i == m.genCursor {
-			indicator = "> "
-		}
-
-		rate := fmt.Sprintf("$%.0f/hr", client.HourlyRate)
-		clientLine := fmt.Sprintf("%s%-25s  %s", indicator, client.Name, rate)
-
-		if i == m.genCursor {
-			s += lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(clientLine) + "\n"
-		} else {
-			s += clientLine + "\n"
-		}
-	}
-
-	s += "\n" + helpStyle.Render("  j/k: navigate  enter: select  esc: cancel")
+	s += m.genPicker.View()
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -741,26 +1509,44 @@ func (m *InvoicesModel) viewGenPreview() string {
 
 	clientName := m.genClient.Name
 	s += titleStyle.Render(fmt.Sprintf("New Invoice - %s", clientName)) + "\n\n"
+	s += m.viewGenDates()
 
 	if len(m.genEntries) == 0 {
-		s += subtitleStyle.Render("  No unbilled entries found") + "\n"
-		s += "\n" + helpStyle.Render("  esc: back")
+		s += subtitleStyle.Render("  No unbilled entries between these dates") + "\n"
+		s += "\n" + renderKeyHelp(DefaultKeyMap.ChangeDates, withHelp(DefaultKeyMap.Back, "back"))
 		return s
 	}
 
 	// Summary
-	var totalHours, totalValue float64
+	var totalHours float64
+	var totalValue domain.Money
 	for _, e := range m.genEntries {
 		totalHours += e.Duration().Hours()
 		totalValue += e.Amount()
 	}
 
-	taxRate := m.app.Config.Invoice.DefaultTaxRate
-	taxAmount := totalValue * taxRate
+	taxes := m.app.InvoiceTaxes(m.genClient)
+	var taxAmount domain.Money
+	for _, tax := range taxes {
+		taxAmount += totalValue.Times(tax.Rate)
+	}
 	total := totalValue + taxAmount
 
-	s += fmt.Sprintf("  %d entries  |  %s  |  %s\n\n",
-		len(m.genEntries), formatHours(totalHours), formatMoney(totalValue))
+	s += fmt.Sprintf("  %d entries  |  %s  |  %s\n",
+		len(m.genEntries), formatHours(totalHours), formatMoney(totalValue.Float()))
+	if m.genHeld > 0 {
+		s += subtitleStyle.Render(fmt.Sprintf("  %d entries awaiting client approval are left out", m.genHeld)) + "\n"
+	}
+	if m.app.Config.Invoice.LintEntries {
+		if lints := service.LintEntries(m.genEntries); len(lints) > 0 {
+			s += lipgloss.NewStyle().Foreground(warningColor).Render(fmt.Sprintf(
+				"  %d entries have data-quality issues (fix with timesink entries lint --fix)", len(lints))) + "\n"
+		}
+	}
+	if m.genClient.AttachTimesheet {
+		s += subtitleStyle.Render("  A timesheet will be saved alongside the invoice") + "\n"
+	}
+	s += "\n"
 
 	// Entry table
 	s += subtitleStyle.Render(fmt.Sprintf(
@@ -775,28 +1561,74 @@ func (m *InvoicesModel) viewGenPreview() string {
 		}
 
 		s += fmt.Sprintf("  %-10s  %-30s  %8s  %10s\n",
-			entry.StartTime.Format("Jan 02"),
+			formatShortDate(entry.StartTime),
 			truncateStr(desc, 30),
-			formatHours(entry.Duration().Hours()),
-			formatMoney(entry.Amount()),
+			formatInvoiceHours(m.app.Config.Invoice, entry.Duration().Hours()),
+			formatMoney(entry.Amount().Float()),
 		)
 	}
 
 	// Totals
 	s += "\n"
-	s += fmt.Sprintf("  %42s  %10s\n", "Subtotal:", formatMoney(totalValue))
-	if taxRate > 0 {
-		s += fmt.Sprintf("  %35s (%.1f%%)  %10s\n", "Tax:", taxRate*100, formatMoney(taxAmount))
-	} else {
-		s += fmt.Sprintf("  %42s  %10s\n", "Tax:", formatMoney(taxAmount))
+	s += fmt.Sprintf("  %42s  %10s\n", "Subtotal:", formatMoney(totalValue.Float()))
+	if len(taxes) == 0 {
+		s += fmt.Sprintf("  %42s  %10s\n", "Tax:", formatMoney(taxAmount.Float()))
+	}
+	for _, tax := range taxes {
+		label := fmt.Sprintf("%s (%s%%):", tax.Label, activeLocale.Number(tax.Rate*100, 1))
+		s += fmt.Sprintf("  %42s  %10s\n", label, formatMoney(totalValue.Times(tax.Rate).Float()))
 	}
 	s += lipgloss.NewStyle().Bold(true).Render(
-		fmt.Sprintf("  %42s  %10s", "Total:", formatMoney(total)),
+		fmt.Sprintf("  %42s  %10s", "Total:", formatMoney(total.Float())),
 	) + "\n"
+	for _, tax := range taxes {
+		if tax.Note != "" {
+			s += subtitleStyle.Render("  "+tax.Note) + "\n"
+		}
+	}
 
 	s += "\n" + lipgloss.NewStyle().Foreground(warningColor).Render(
 		"  Press enter to generate invoice and lock these entries") + "\n"
-	s += helpStyle.Render("  esc: back to client selection")
+	s += renderKeyHelp(m.KeyHelp()...)
+
+	return s
+}
+
+// viewGenDates describes the dates of the previewed entries and counts the
+// client's unbilled entries outside them
+func (m *InvoicesModel) viewGenDates() string {
+	var s string
+	if m.genStart.IsZero() {
+		s += fmt.Sprintf("  All unbilled time through %s\n", formatLongDate(m.genEnd))
+	} else {
+		s += fmt.Sprintf("  %s - %s\n", formatLongDate(m.genStart), formatLongDate(m.genEnd))
+	}
+	if outside := m.genUnbilled[m.genClient.ID] - len(m.genEntries); outside > 0 {
+		s += lipgloss.NewStyle().Foreground(warningColor).Render(
+			fmt.Sprintf("  %d unbilled entries outside these dates are left out", outside)) + "\n"
+	}
+	return s + "\n"
+}
+
+func (m *InvoicesModel) viewGenRange() string {
+	var s string
+	s += titleStyle.Render(fmt.Sprintf("New Invoice - %s", m.genClient.Name)) + "\n\n"
+	s += subtitleStyle.Render("  Invoice unbilled time logged between:") + "\n\n"
+
+	for i, label := range []string{"From", "To"} {
+		style := subtitleStyle
+		if i == m.rangeFocus {
+			style = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+		}
+		s += style.Render(fmt.Sprintf("  %-5s", label)) + " " + m.rangeInputs[i].View() + "\n"
+	}
+
+	if m.rangeErr != nil {
+		s += "\n" + lipgloss.NewStyle().Foreground(errorColor).
+			Render(fmt.Sprintf("  Error: %v", m.rangeErr)) + "\n"
+	}
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -808,27 +1640,50 @@ func (m *InvoicesModel) viewGenSavePath() string {
 	s += titleStyle.Render(fmt.Sprintf("New Invoice - %s", clientName)) + "\n\n"
 
 	// Summary
-	var totalHours, totalValue float64
+	var totalHours float64
+	var totalValue domain.Money
 	for _, e := range m.genEntries {
 		totalHours += e.Duration().Hours()
 		totalValue += e.Amount()
 	}
-	taxRate := m.app.Config.Invoice.DefaultTaxRate
-	total := totalValue + (totalValue * taxRate)
+	total := totalValue
+	for _, tax := range m.app.InvoiceTaxes(m.genClient) {
+		total += totalValue.Times(tax.Rate)
+	}
 
 	s += fmt.Sprintf("  %d entries  |  %s  |  %s\n\n",
-		len(m.genEntries), formatHours(totalHours), formatMoney(total))
+		len(m.genEntries), formatHours(totalHours), formatMoney(total.Float()))
 
-	s += lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render("  Save invoice to:") + "\n"
-	s += "  " + m.savePathInput.View() + "\n"
+	labels := []string{"Save invoice to (" + exportExtensions() + "):", "Notes (\\n for a new line):", "Payment instructions (\\n for a new line):"}
+	for i, label := range labels {
+		style := subtitleStyle
+		if i == m.genFocus {
+			style = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+		}
+		s += style.Render("  "+label) + "\n"
+		s += "  " + m.genInput(i).View() + "\n\n"
+	}
 
 	if m.err != nil {
 		s += "\n" + lipgloss.NewStyle().Foreground(errorColor).
 			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n"
 	}
 
-	s += "\n" + helpStyle.Render("  enter: generate and save  esc: back")
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
+
+	return s
+}
 
+// viewFooterText renders one block of an invoice footer, if set
+func viewFooterText(title, text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	s := "\n" + subtitleStyle.Render("  "+title+":") + "\n"
+	for _, line := range strings.Split(text, "\n") {
+		s += "  " + line + "\n"
+	}
 	return s
 }
 
@@ -845,6 +1700,8 @@ func statusBadge(status domain.InvoiceStatus) string {
 		return lipgloss.NewStyle().Foreground(successColor).Render("PAID")
 	case domain.InvoiceStatusOverdue:
 		return lipgloss.NewStyle().Foreground(errorColor).Render("OVERDUE")
+	case domain.InvoiceStatusVoid:
+		return lipgloss.NewStyle().Foreground(mutedColor).Strikethrough(true).Render("VOID")
 	default:
 		return string(status)
 	}
@@ -1,19 +1,27 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
-	Quit key.Binding
-	Help key.Binding
-	Back key.Binding
+	Quit   key.Binding
+	Help   key.Binding
+	Back   key.Binding
+	Search key.Binding
 
 	// Navigation
-	Timer    key.Binding
-	Entries  key.Binding
-	Clients  key.Binding
-	Invoices key.Binding
-	Reports  key.Binding
-	Settings key.Binding
+	Timer     key.Binding
+	Entries   key.Binding
+	Clients   key.Binding
+	Invoices  key.Binding
+	Estimates key.Binding
+	Reports   key.Binding
+	Settings  key.Binding
 
 	// Actions
 	Select key.Binding
@@ -26,24 +34,348 @@ type KeyMap struct {
 	Down  key.Binding
 	Left  key.Binding
 	Right key.Binding
+
+	// Scrolling long screens
+	PageUp   key.Binding
+	PageDown key.Binding
+
+	// Forms and prompts
+	NextField key.Binding
+	PrevField key.Binding
+	Save      key.Binding
+	Cancel    key.Binding
+	Confirm   key.Binding
+
+	// Screen actions
+	StartTimer     key.Binding
+	QuickStart     key.Binding
+	QuickLog       key.Binding
+	ToggleBillable key.Binding
+	Pause          key.Binding
+	Resume         key.Binding
+	Stop           key.Binding
+	StopReview     key.Binding
+	Note           key.Binding
+	AdjustStart    key.Binding
+	Archive        key.Binding
+	ShowArchived   key.Binding
+	Split          key.Binding
+	Undo           key.Binding
+	PrevYear       key.Binding
+	NextYear       key.Binding
+	RevenueBasis   key.Binding
+	HeatmapRange   key.Binding
+	RequireReason  key.Binding
+	Attach         key.Binding
+	OpenAttachment key.Binding
+	MarkSent       key.Binding
+	Accept         key.Binding
+	Decline        key.Binding
+	Convert        key.Binding
+	ChangeDates    key.Binding
+	DraftAll       key.Binding
+	RewordItem     key.Binding
+	InvoiceEntries key.Binding
+	NextIssue      key.Binding
+	PrevWeek       key.Binding
+	NextWeek       key.Binding
+	EntryDuration  key.Binding
+	OpenFile       key.Binding
+	SortList       key.Binding
+	StatusFilter   key.Binding
+	Finalize       key.Binding
+	PrevMonth      key.Binding
+	NextMonth      key.Binding
+	Reset          key.Binding
 }
 
 var DefaultKeyMap = KeyMap{
-	Quit:     key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-	Help:     key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-	Back:     key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
-	Timer:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "timer")),
-	Entries:  key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "entries")),
-	Clients:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clients")),
-	Invoices: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "invoices")),
-	Reports:  key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reports")),
-	Settings: key.NewBinding(key.WithKeys(","), key.WithHelp(",", "settings")),
-	Select:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
-	New:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new")),
-	Edit:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
-	Delete:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
-	Up:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-	Down:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-	Left:     key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
-	Right:    key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")),
+	Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+	Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+	Back:      key.NewBinding(key.WithKeys("esc", "backspace"), key.WithHelp("esc", "back")),
+	Search:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+	Timer:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "timer")),
+	Entries:   key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "entries")),
+	Clients:   key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clients")),
+	Invoices:  key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "invoices")),
+	Estimates: key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "estimates")),
+	Reports:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reports")),
+	Settings:  key.NewBinding(key.WithKeys(","), key.WithHelp(",", "settings")),
+	Select:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "select")),
+	New:       key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new")),
+	Edit:      key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit")),
+	Delete:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+	Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	Left:      key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "left")),
+	Right:     key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "right")),
+	PageUp:    key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "scroll up")),
+	PageDown:  key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "scroll down")),
+
+	NextField: key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab", "next field")),
+	PrevField: key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab", "previous field")),
+	Save:      key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save")),
+	Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+	Confirm:   key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "confirm")),
+
+	StartTimer:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "start timer")),
+	QuickStart:     key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "quick start")),
+	QuickLog:       key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quick log")),
+	ToggleBillable: key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "toggle billable")),
+	Pause:          key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause")),
+	Resume:         key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "resume")),
+	Stop:           key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "stop")),
+	StopReview:     key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "stop and review")),
+	Note:           key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "add note")),
+	AdjustStart:    key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "adjust start")),
+	Archive:        key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "archive/unarchive")),
+	ShowArchived:   key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "toggle archived")),
+	Split:          key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "split")),
+	Undo:           key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo delete")),
+	PrevYear:       key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous year")),
+	NextYear:       key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next year")),
+	RevenueBasis:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "cash/accrual")),
+	HeatmapRange:   key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "heatmap range")),
+	RequireReason:  key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle required reason")),
+	Attach:         key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "attach file")),
+	OpenAttachment: key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open attachment")),
+	MarkSent:       key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "mark sent")),
+	Accept:         key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "accept")),
+	Decline:        key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "decline")),
+	Convert:        key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "convert to invoice")),
+	ChangeDates:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "change dates")),
+	DraftAll:       key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "draft all clients")),
+	RewordItem:     key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "reword line item")),
+	InvoiceEntries: key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "entries on invoice")),
+	NextIssue:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next to fix")),
+	PrevWeek:       key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous week")),
+	NextWeek:       key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next week")),
+	EntryDuration:  key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "enter duration")),
+	OpenFile:       key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open invoice file")),
+	SortList:       key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
+	StatusFilter:   key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filter status")),
+	Finalize:       key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "finalize")),
+	PrevMonth:      key.NewBinding(key.WithKeys("["), key.WithHelp("[", "previous month")),
+	NextMonth:      key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "next month")),
+	Reset:          key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "reset data")),
+}
+
+// globalKeys are the keys that work on every screen, in the order shown in
+// the footer and help overlay
+func globalKeys() []key.Binding {
+	k := DefaultKeyMap
+	return []key.Binding{k.Timer, k.Entries, k.Clients, k.Invoices, k.Estimates, k.Reports, k.Settings, k.Search, k.Help, k.Quit}
+}
+
+// withHelp returns a copy of the binding with a screen-specific description,
+// e.g. Select shown as "enter: edit" on the clients list
+func withHelp(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// navigateKeys combines Up and Down into a single help entry, e.g. "j/k: navigate"
+func navigateKeys() key.Binding {
+	up, down := DefaultKeyMap.Up, DefaultKeyMap.Down
+	keys := append(append([]string{}, down.Keys()...), up.Keys()...)
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(
+		lastKey(down.Help().Key)+"/"+lastKey(up.Help().Key), "navigate"))
+}
+
+// lastKey returns the final alternative of a help key, e.g. "k" for "↑/k"
+func lastKey(helpKey string) string {
+	parts := strings.Split(helpKey, "/")
+	return parts[len(parts)-1]
+}
+
+// KeyHelper is implemented by screens to list the keys available in their
+// current mode. The list drives both the screen footer and the help overlay.
+type KeyHelper interface {
+	KeyHelp() []key.Binding
+}
+
+// renderKeyHelp renders bindings as a footer line, e.g. "  j/k: navigate  n: new"
+func renderKeyHelp(bindings ...key.Binding) string {
+	return helpStyle.Render("  " + keyHelpText(bindings))
+}
+
+// keyHelpText joins the enabled bindings' help as "key: desc" pairs
+func keyHelpText(bindings []key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		parts = append(parts, b.Help().Key+": "+b.Help().Desc)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// formKeys are the keys shared by every multi-field form
+func formKeys() []key.Binding {
+	k := DefaultKeyMap
+	return []key.Binding{k.NextField, k.PrevField, withHelp(k.Select, "next/save"), k.Save, k.Cancel}
+}
+
+// namedBinding pairs a binding with the action name used in config.yaml
+type namedBinding struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions returns every binding in the key map under its config name
+func (k *KeyMap) actions() []namedBinding {
+	return []namedBinding{
+		{"quit", &k.Quit},
+		{"help", &k.Help},
+		{"back", &k.Back},
+		{"search", &k.Search},
+		{"timer", &k.Timer},
+		{"entries", &k.Entries},
+		{"clients", &k.Clients},
+		{"invoices", &k.Invoices},
+		{"estimates", &k.Estimates},
+		{"reports", &k.Reports},
+		{"settings", &k.Settings},
+		{"select", &k.Select},
+		{"new", &k.New},
+		{"edit", &k.Edit},
+		{"delete", &k.Delete},
+		{"up", &k.Up},
+		{"down", &k.Down},
+		{"left", &k.Left},
+		{"right", &k.Right},
+		{"page_up", &k.PageUp},
+		{"page_down", &k.PageDown},
+		{"next_field", &k.NextField},
+		{"prev_field", &k.PrevField},
+		{"save", &k.Save},
+		{"cancel", &k.Cancel},
+		{"confirm", &k.Confirm},
+		{"start_timer", &k.StartTimer},
+		{"quick_start", &k.QuickStart},
+		{"quick_log", &k.QuickLog},
+		{"toggle_billable", &k.ToggleBillable},
+		{"pause", &k.Pause},
+		{"resume", &k.Resume},
+		{"stop", &k.Stop},
+		{"stop_review", &k.StopReview},
+		{"note", &k.Note},
+		{"adjust_start", &k.AdjustStart},
+		{"archive", &k.Archive},
+		{"show_archived", &k.ShowArchived},
+		{"split", &k.Split},
+		{"undo", &k.Undo},
+		{"prev_year", &k.PrevYear},
+		{"next_year", &k.NextYear},
+		{"revenue_basis", &k.RevenueBasis},
+		{"heatmap_range", &k.HeatmapRange},
+		{"require_reason", &k.RequireReason},
+		{"attach", &k.Attach},
+		{"open_attachment", &k.OpenAttachment},
+		{"mark_sent", &k.MarkSent},
+		{"accept", &k.Accept},
+		{"decline", &k.Decline},
+		{"convert", &k.Convert},
+		{"change_dates", &k.ChangeDates},
+		{"draft_all", &k.DraftAll},
+		{"reword_item", &k.RewordItem},
+		{"invoice_entries", &k.InvoiceEntries},
+		{"next_issue", &k.NextIssue},
+		{"prev_week", &k.PrevWeek},
+		{"next_week", &k.NextWeek},
+		{"entry_duration", &k.EntryDuration},
+		{"open_file", &k.OpenFile},
+		{"sort", &k.SortList},
+		{"status_filter", &k.StatusFilter},
+		{"finalize", &k.Finalize},
+		{"prev_month", &k.PrevMonth},
+		{"next_month", &k.NextMonth},
+		{"reset", &k.Reset},
+	}
+}
+
+// globalActions are handled by the root model on every screen
+var globalActions = []string{"quit", "help", "search", "timer", "entries", "clients", "invoices", "estimates", "reports", "settings", "page_up", "page_down"}
+
+// keyGroups lists actions that are live at the same time and so must not
+// share a key. Screens that claim a global key for themselves (start_timer
+// over timer, quick_log over quit, and every key while a timer runs) leave
+// it out of their group.
+var keyGroups = []struct {
+	context string
+	actions []string
+}{
+	{"global", globalActions},
+	{"form", []string{"next_field", "prev_field", "select", "save", "cancel"}},
+	{"entry form", []string{"next_field", "prev_field", "select", "save", "cancel", "entry_duration"}},
+	{"dashboard", append([]string{"up", "down", "select", "quick_log"}, without(globalActions, "quit")...)},
+	{"timer", append([]string{"quick_start", "toggle_billable"}, globalActions...)},
+	{"running timer", []string{"help", "pause", "resume", "edit", "note", "adjust_start", "stop", "stop_review", "delete"}},
+	{"entries", append([]string{"up", "down", "back", "new", "select", "start_timer", "split", "delete", "undo"}, without(globalActions, "timer")...)},
+	{"clients", append([]string{"up", "down", "new", "select", "start_timer", "archive", "show_archived"}, without(globalActions, "timer")...)},
+	{"invoices", append([]string{"up", "down", "new", "select", "back", "draft_all", "open_file", "sort", "status_filter"}, globalActions...)},
+	{"draft all", []string{"confirm", "left", "right"}},
+	{"invoice preview", append([]string{"select", "back", "change_dates"}, globalActions...)},
+	{"invoice detail", append([]string{"up", "down", "back", "attach", "open_attachment", "reword_item", "invoice_entries", "finalize", "delete"}, globalActions...)},
+	{"estimates", append([]string{"up", "down", "select", "back", "mark_sent", "accept", "decline", "convert"}, globalActions...)},
+	{"reports", append([]string{"up", "down", "left", "right", "prev_year", "next_year", "revenue_basis", "heatmap_range"}, globalActions...)},
+	{"settings", append([]string{"select", "require_reason", "reset"}, globalActions...)},
+	{"reset data", []string{"up", "down", "select", "cancel"}},
+	{"date range", []string{"up", "down", "left", "right", "select", "cancel", "prev_month", "next_month"}},
+	{"review", append([]string{"up", "down", "left", "right", "select", "next_issue", "prev_week", "next_week"}, globalActions...)},
+}
+
+// without returns names minus the given action
+func without(names []string, action string) []string {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != action {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// keyMapFromConfig builds the key map from the defaults plus any remapped
+// actions, rejecting unknown actions and keys bound twice in one context
+func keyMapFromConfig(overrides map[string][]string) (KeyMap, error) {
+	km := DefaultKeyMap
+	byName := make(map[string]*key.Binding)
+	for _, a := range km.actions() {
+		byName[a.name] = a.binding
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := byName[name]
+		if !ok {
+			return KeyMap{}, fmt.Errorf("unknown keybinding action %q", name)
+		}
+		keys := overrides[name]
+		if len(keys) == 0 {
+			return KeyMap{}, fmt.Errorf("keybinding %s must list at least one key", name)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+
+	for _, g := range keyGroups {
+		owner := make(map[string]string)
+		for _, name := range g.actions {
+			for _, k := range byName[name].Keys() {
+				if other, taken := owner[k]; taken && other != name {
+					return KeyMap{}, fmt.Errorf("key %q is bound to both %s and %s on the %s screen", k, other, name, g.context)
+				}
+				owner[k] = name
+			}
+		}
+	}
+
+	return km, nil
 }
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listView is the selectable, scrolling list behind the entries, clients and
+// invoices screens and the client picker. It keeps the cursor on an item and
+// in view as items change and the window resizes, and renders the column
+// header, the visible rows, scroll indicators and the empty state the same
+// way everywhere. Screens own the items' meaning: render draws one row, which
+// may span several lines, with selected set for the row under the cursor.
+type listView[T any] struct {
+	items  []T
+	cursor int
+	offset int
+	height int // rows shown at once
+
+	header string // column header, laid out like the rows; "" for none
+	empty  string // shown instead of the rows when there are none
+	render func(item T, selected bool) string
+}
+
+// listMinRows keeps a few rows visible however small the terminal is
+const listMinRows = 3
+
+// newListView creates an empty list that draws its rows with render
+func newListView[T any](render func(item T, selected bool) string) listView[T] {
+	return listView[T]{height: 15, render: render}
+}
+
+// SetItems replaces the items, keeping the cursor where it was when it still
+// points at an item and on the last one otherwise
+func (l *listView[T]) SetItems(items []T) {
+	l.items = items
+	l.cursor = min(l.cursor, max(len(items)-1, 0))
+	l.scrollToCursor()
+}
+
+// SetHeight sets how many rows fit, e.g. from a WindowSizeMsg
+func (l *listView[T]) SetHeight(rows int) {
+	l.height = max(rows, listMinRows)
+	l.scrollToCursor()
+}
+
+// SetHeader sets the column header shown above the rows
+func (l *listView[T]) SetHeader(header string) {
+	l.header = header
+}
+
+// SetEmpty sets the text shown when there are no items
+func (l *listView[T]) SetEmpty(text string) {
+	l.empty = text
+}
+
+// Items returns every item, visible or not
+func (l *listView[T]) Items() []T {
+	return l.items
+}
+
+// Len is the number of items
+func (l *listView[T]) Len() int {
+	return len(l.items)
+}
+
+// Index is the cursor's position in the items
+func (l *listView[T]) Index() int {
+	return l.cursor
+}
+
+// Selected returns the item under the cursor. ok is false when the list is
+// empty.
+func (l *listView[T]) Selected() (item T, ok bool) {
+	if l.cursor >= len(l.items) {
+		return item, false
+	}
+	return l.items[l.cursor], true
+}
+
+// Select moves the cursor to index i, scrolling to show it
+func (l *listView[T]) Select(i int) {
+	if i < 0 || i >= len(l.items) {
+		return
+	}
+	l.cursor = i
+	l.scrollToCursor()
+}
+
+// Reset moves the cursor back to the first item
+func (l *listView[T]) Reset() {
+	l.cursor, l.offset = 0, 0
+}
+
+// MoveUp moves the cursor up one item
+func (l *listView[T]) MoveUp() {
+	l.Select(l.cursor - 1)
+}
+
+// MoveDown moves the cursor down one item
+func (l *listView[T]) MoveDown() {
+	l.Select(l.cursor + 1)
+}
+
+// Update moves the cursor for the navigation and paging keys, reporting
+// whether msg was one of them
+func (l *listView[T]) Update(msg tea.KeyMsg) bool {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Up):
+		l.MoveUp()
+	case key.Matches(msg, DefaultKeyMap.Down):
+		l.MoveDown()
+	case key.Matches(msg, DefaultKeyMap.PageUp):
+		l.Select(max(l.cursor-l.height, 0))
+	case key.Matches(msg, DefaultKeyMap.PageDown):
+		l.Select(min(l.cursor+l.height, len(l.items)-1))
+	default:
+		return false
+	}
+	return true
+}
+
+// scrollToCursor moves the window just enough to show the cursor
+func (l *listView[T]) scrollToCursor() {
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	} else if l.cursor >= l.offset+l.height {
+		l.offset = l.cursor - l.height + 1
+	}
+	// Don't leave blank rows at the bottom after items were removed
+	l.offset = max(min(l.offset, len(l.items)-l.height), 0)
+}
+
+// View renders the header and the rows that fit, with indicators for rows
+// scrolled out of view, or the empty text
+func (l *listView[T]) View() string {
+	if len(l.items) == 0 {
+		return subtitleStyle.Render("  "+l.empty) + "\n"
+	}
+
+	var s string
+	if l.header != "" {
+		s += subtitleStyle.Render(l.header) + "\n"
+	}
+	if l.offset > 0 {
+		s += subtitleStyle.Render("  ... more above") + "\n"
+	}
+	end := min(l.offset+l.height, len(l.items))
+	for i := l.offset; i < end; i++ {
+		s += l.render(l.items[i], i == l.cursor) + "\n"
+	}
+	if end < len(l.items) {
+		s += subtitleStyle.Render("  ... more below") + "\n"
+	}
+	return s
+}
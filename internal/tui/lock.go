@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"errors"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lockCheckInterval is how often the TUI checks for inactivity and for a
+// lock made with `timesink lock` in another terminal
+const lockCheckInterval = 5 * time.Second
+
+// lockCheckMsg triggers a lock check
+type lockCheckMsg struct{}
+
+// lockCheckCmd schedules the next lock check
+func lockCheckCmd() tea.Cmd {
+	return tea.Tick(lockCheckInterval, func(time.Time) tea.Msg { return lockCheckMsg{} })
+}
+
+// unlockResultMsg reports whether the entered passphrase unlocked the session
+type unlockResultMsg struct {
+	err error
+}
+
+// unlockKeys are the only keys that work on the lock screen
+var unlockKeys = []key.Binding{
+	key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "unlock")),
+	key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
+}
+
+// lockScreen replaces every screen while the session is locked, so no
+// figures are visible until the passphrase is entered
+type lockScreen struct {
+	input textinput.Model
+	err   error
+}
+
+func newLockScreen() *lockScreen {
+	input := textinput.New()
+	input.Placeholder = "passphrase"
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '•'
+	input.Width = 30
+	input.Focus()
+	return &lockScreen{input: input}
+}
+
+// update handles a key on the lock screen
+func (l *lockScreen) update(a *app.App, msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, unlockKeys[1]):
+		return tea.Quit
+	case key.Matches(msg, unlockKeys[0]):
+		passphrase := l.input.Value()
+		l.input.SetValue("")
+		return func() tea.Msg { return unlockResultMsg{err: a.Unlock(passphrase)} }
+	}
+
+	var cmd tea.Cmd
+	l.input, cmd = l.input.Update(msg)
+	return cmd
+}
+
+func (l *lockScreen) View() string {
+	s := titleStyle.Render("🔒 Locked") + "\n\n"
+	s += subtitleStyle.Render("  Enter the database passphrase to continue.") + "\n\n"
+	s += "  " + l.input.View() + "\n"
+
+	if l.err != nil {
+		text := "Error: " + l.err.Error()
+		if errors.Is(l.err, app.ErrWrongPassphrase) {
+			text = "Wrong passphrase"
+		}
+		s += "\n" + lipgloss.NewStyle().Foreground(errorColor).Render("  "+text) + "\n"
+	}
+
+	return s
+}
+
+// autoLockAfter returns the configured inactivity timeout, or 0 if off
+func autoLockAfter(a *app.App) time.Duration {
+	return time.Duration(a.Config.Security.AutoLockMinutes) * time.Minute
+}
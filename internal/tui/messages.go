@@ -1,8 +1,15 @@
 package tui
 
-// SwitchScreenMsg requests a screen change
+import (
+	"github.com/andy/timesink/internal/domain"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SwitchScreenMsg requests a screen change. Select, if set, is passed on to
+// the screen once it is open, e.g. a selectEntryMsg to highlight one row
 type SwitchScreenMsg struct {
 	Screen Screen
+	Select tea.Msg
 }
 
 // RefreshDataMsg requests data refresh
@@ -16,6 +23,24 @@ type ErrorMsg struct {
 // OpenNewClientFormMsg tells the clients screen to open the new client form
 type OpenNewClientFormMsg struct{}
 
+// selectEntryMsg tells the entries screen to select an entry once it is
+// listed
+type selectEntryMsg struct {
+	id int64
+}
+
+// filterEntriesMsg tells the entries screen to list only the entries billed
+// on an invoice
+type filterEntriesMsg struct {
+	invoice *domain.Invoice
+}
+
+// filterInvoicesMsg tells the invoices screen to list only invoices with the
+// given statuses
+type filterInvoicesMsg struct {
+	statuses []domain.InvoiceStatus
+}
+
 // firstRunCheckMsg reports whether the database has any clients
 type firstRunCheckMsg struct {
 	hasClients bool
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/locale"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -20,8 +24,10 @@ const (
 	ScreenEntries
 	ScreenClients
 	ScreenInvoices
+	ScreenEstimates
 	ScreenReports
 	ScreenSettings
+	ScreenReview
 )
 
 // String returns the screen name
@@ -37,15 +43,47 @@ func (s Screen) String() string {
 		return "Clients"
 	case ScreenInvoices:
 		return "Invoices"
+	case ScreenEstimates:
+		return "Estimates"
 	case ScreenReports:
 		return "Reports"
 	case ScreenSettings:
 		return "Settings"
+	case ScreenReview:
+		return "Weekly Review"
 	default:
 		return "Unknown"
 	}
 }
 
+// screenNames maps the names accepted by `timesink tui --screen` to screens
+var screenNames = []struct {
+	name   string
+	screen Screen
+}{
+	{"dashboard", ScreenDashboard},
+	{"timer", ScreenTimer},
+	{"entries", ScreenEntries},
+	{"clients", ScreenClients},
+	{"invoices", ScreenInvoices},
+	{"estimates", ScreenEstimates},
+	{"reports", ScreenReports},
+	{"settings", ScreenSettings},
+	{"review", ScreenReview},
+}
+
+// ParseScreen looks up a screen by name, e.g. "invoices"
+func ParseScreen(name string) (Screen, error) {
+	names := make([]string, 0, len(screenNames))
+	for _, s := range screenNames {
+		if strings.EqualFold(s.name, name) {
+			return s.screen, nil
+		}
+		names = append(names, s.name)
+	}
+	return ScreenDashboard, fmt.Errorf("unknown screen %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
 // Model is the root Bubble Tea model
 type Model struct {
 	app           *app.App
@@ -53,41 +91,163 @@ type Model struct {
 	width         int
 	height        int
 
+	// Scrollable area holding the current screen's view
+	content viewport.Model
+
 	// Screen models (lazy initialized)
 	dashboard tea.Model
 	timer     tea.Model
 	entries   tea.Model
 	clients   tea.Model
 	invoices  tea.Model
+	estimates tea.Model
 	reports   tea.Model
 	settings  tea.Model
+	review    tea.Model
+
+	// Whether the help overlay is shown in place of the screen
+	showHelp bool
+
+	// Search overlay shown in place of the screen while open
+	search *searchOverlay
+
+	// Screen to open once the TUI starts, and the message it is sent once
+	// open, if any
+	startScreen Screen
+	startSelect tea.Msg
 
 	// First-run state
 	checkedFirstRun bool
 
-	// Error state
-	err     error
-	quitMsg string // shown when quit is blocked
+	// Toast notification shown below the screen, and the id of the last one
+	toast    *toast
+	toastSeq int
+
+	// Lock screen shown in place of everything while locked, and the time
+	// of the last key press for auto-lock
+	lock         *lockScreen
+	lastActivity time.Time
+
+	// Terminal title last set for the active timer
+	windowTitle string
+
+	// Time of the last check for the computer having slept
+	lastSleepCheck time.Time
 }
 
 // New creates a new root model
 func New(a *app.App) Model {
+	activeLocale = locale.FromConfig(a.Config.Locale)
+	screenData = newDataCache(a.DB)
 	dashboard := NewDashboardModel(a)
-	return Model{
+	m := Model{
 		app:           a,
 		currentScreen: ScreenDashboard,
 		dashboard:     dashboard,
+		content:       viewport.New(0, 0),
+		lastActivity:  time.Now(),
+
+		lastSleepCheck: time.Now(),
+	}
+	if a.IsLocked() {
+		m.lock = newLockScreen()
+	}
+	return m
+}
+
+// navFooter lists the global navigation keys
+func navFooter() string {
+	return keyHelpText(globalKeys())
+}
+
+// Layout overhead around the screen content: the frame's border and
+// padding, plus the header, dividers, and blank lines. The footer is
+// measured separately since it wraps on narrow terminals.
+const (
+	frameChromeWidth  = 10
+	frameChromeHeight = 11
+)
+
+// contentSize returns the space available to the current screen's view
+func (m *Model) contentSize() (int, int) {
+	width := m.width - frameChromeWidth
+	if width < 16 {
+		width = 16
+	}
+	footerHeight := lipgloss.Height(lipgloss.NewStyle().Width(width).Render(navFooter()))
+	height := m.height - frameChromeHeight - footerHeight
+	if height < 3 {
+		height = 3
 	}
+	return width, height
+}
+
+// screenSizeCmd tells a newly created screen how much space it has
+func (m *Model) screenSizeCmd() tea.Cmd {
+	if m.width == 0 {
+		return nil
+	}
+	width, height := m.contentSize()
+	return func() tea.Msg { return tea.WindowSizeMsg{Width: width, Height: height} }
+}
+
+// resizeScreens passes the content size to every initialized screen
+func (m *Model) resizeScreens() {
+	width, height := m.contentSize()
+	size := tea.WindowSizeMsg{Width: width, Height: height}
+	for _, screen := range []*tea.Model{
+		&m.dashboard, &m.timer, &m.entries, &m.clients, &m.invoices, &m.estimates, &m.reports, &m.settings, &m.review,
+	} {
+		if *screen != nil {
+			*screen, _ = (*screen).Update(size)
+		}
+	}
+}
+
+// syncContent refreshes the viewport with the current screen's view,
+// leaving room for the toast and scroll hint when they are shown
+func (m *Model) syncContent() {
+	view := "Loading..."
+	if m.lock != nil {
+		view = m.lock.View()
+	} else if m.showHelp {
+		view = m.helpView()
+	} else if m.search != nil {
+		view = m.search.View()
+	} else if screen := m.activeScreen(); screen != nil {
+		view = screen.View()
+	}
+
+	_, height := m.contentSize()
+	if m.toast != nil {
+		height--
+	}
+	if lipgloss.Height(view) > height {
+		height--
+	}
+	m.content.Height = max(height, 1)
+	m.content.SetContent(view)
 }
 
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		m.checkFirstRun(),
+		lockCheckCmd(),
+	}
+	if m.app.Config.Timer.OnSleep != config.OnSleepOff {
+		cmds = append(cmds, sleepCheckCmd())
+	}
+	if m.app.Config.Timer.WindowTitle {
+		cmds = append(cmds, func() tea.Msg { return windowTitleMsg{title: timerWindowTitle(m.app)} })
 	}
 	if m.dashboard != nil {
 		cmds = append(cmds, m.dashboard.Init())
 	}
+	if m.startScreen != ScreenDashboard {
+		start, selectMsg := m.startScreen, m.startSelect
+		cmds = append(cmds, func() tea.Msg { return SwitchScreenMsg{Screen: start, Select: selectMsg} })
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -109,43 +269,55 @@ func (m *Model) initScreen(screen Screen) tea.Cmd {
 	case ScreenDashboard:
 		if m.dashboard == nil {
 			m.dashboard = NewDashboardModel(m.app)
-			return m.dashboard.Init()
+			return tea.Batch(m.dashboard.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenTimer:
 		if m.timer == nil {
 			m.timer = NewTimerModel(m.app)
-			return m.timer.Init()
+			return tea.Batch(m.timer.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenEntries:
 		if m.entries == nil {
 			m.entries = NewEntriesModel(m.app)
-			return m.entries.Init()
+			return tea.Batch(m.entries.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenClients:
 		if m.clients == nil {
 			m.clients = NewClientsModel(m.app)
-			return m.clients.Init()
+			return tea.Batch(m.clients.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenInvoices:
 		if m.invoices == nil {
 			m.invoices = NewInvoicesModel(m.app)
-			return m.invoices.Init()
+			return tea.Batch(m.invoices.Init(), m.screenSizeCmd())
+		}
+		return func() tea.Msg { return RefreshDataMsg{} }
+	case ScreenEstimates:
+		if m.estimates == nil {
+			m.estimates = NewEstimatesModel(m.app)
+			return tea.Batch(m.estimates.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenReports:
 		if m.reports == nil {
 			m.reports = NewReportsModel(m.app)
-			return m.reports.Init()
+			return tea.Batch(m.reports.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	case ScreenSettings:
 		if m.settings == nil {
 			m.settings = NewSettingsModel(m.app)
-			return m.settings.Init()
+			return tea.Batch(m.settings.Init(), m.screenSizeCmd())
+		}
+		return func() tea.Msg { return RefreshDataMsg{} }
+	case ScreenReview:
+		if m.review == nil {
+			m.review = NewReviewModel(m.app)
+			return tea.Batch(m.review.Init(), m.screenSizeCmd())
 		}
 		return func() tea.Msg { return RefreshDataMsg{} }
 	}
@@ -158,52 +330,134 @@ type InputCapturer interface {
 	IsCapturingInput() bool
 }
 
-// activeScreenCapturingInput returns true if the current screen is capturing text input
-func (m *Model) activeScreenCapturingInput() bool {
-	var screen tea.Model
+// KeyOverrider is implemented by screens that reuse a global navigation key
+// for a screen-specific action (e.g. 't' to start a timer for the selected row).
+type KeyOverrider interface {
+	OverridesKey(msg tea.KeyMsg) bool
+}
+
+// activeScreen returns the model for the current screen (nil if not yet initialized)
+func (m *Model) activeScreen() tea.Model {
 	switch m.currentScreen {
 	case ScreenDashboard:
-		screen = m.dashboard
+		return m.dashboard
 	case ScreenTimer:
-		screen = m.timer
+		return m.timer
 	case ScreenEntries:
-		screen = m.entries
+		return m.entries
 	case ScreenClients:
-		screen = m.clients
+		return m.clients
 	case ScreenInvoices:
-		screen = m.invoices
+		return m.invoices
+	case ScreenEstimates:
+		return m.estimates
 	case ScreenReports:
-		screen = m.reports
+		return m.reports
 	case ScreenSettings:
-		screen = m.settings
+		return m.settings
+	case ScreenReview:
+		return m.review
 	}
-	if ic, ok := screen.(InputCapturer); ok {
+	return nil
+}
+
+// activeScreenCapturingInput returns true if the current screen is capturing text input
+func (m *Model) activeScreenCapturingInput() bool {
+	if ic, ok := m.activeScreen().(InputCapturer); ok {
 		return ic.IsCapturingInput()
 	}
 	return false
 }
 
+// activeScreenOverridesKey returns true if the current screen claims the key for itself
+func (m *Model) activeScreenOverridesKey(msg tea.KeyMsg) bool {
+	if ko, ok := m.activeScreen().(KeyOverrider); ok {
+		return ko.OverridesKey(msg)
+	}
+	return false
+}
+
 // Update implements tea.Model - routes keys to screens
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	prevScreen := m.currentScreen
+	updated, cmd := m.update(msg)
+
+	next := updated.(Model)
+	if next.currentScreen != prevScreen || next.showHelp != m.showHelp || (next.search == nil) != (m.search == nil) {
+		next.content.GotoTop()
+	}
+	next.syncContent()
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.content.Width, _ = m.contentSize()
+		m.resizeScreens()
 		return m, nil
 
 	case tea.KeyMsg:
-		// Clear quit warning on any keypress
-		m.quitMsg = ""
+		// Keys go to the lock screen alone while locked
+		if m.lock != nil {
+			return m, m.lock.update(m.app, msg)
+		}
+		m.lastActivity = time.Now()
+
+		// The help overlay scrolls with the page keys and closes on any other key
+		if m.showHelp {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.PageUp):
+				m.content.PageUp()
+			case key.Matches(msg, DefaultKeyMap.PageDown):
+				m.content.PageDown()
+			default:
+				m.showHelp = false
+			}
+			return m, nil
+		}
+
+		// The search overlay takes every key until it closes
+		if m.search != nil {
+			cmd, done := m.search.update(m.app, msg)
+			if done {
+				m.search = nil
+			}
+			return m, cmd
+		}
+
+		// Page keys scroll screens taller than the terminal
+		switch {
+		case key.Matches(msg, DefaultKeyMap.PageUp):
+			m.content.PageUp()
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.PageDown):
+			m.content.PageDown()
+			return m, nil
+		}
 
 		// Skip global navigation when a screen is capturing text input
-		if !m.activeScreenCapturingInput() {
+		// or has claimed this key for one of its own actions
+		if !m.activeScreenCapturingInput() && !m.activeScreenOverridesKey(msg) {
 			// Global key handlers (screen navigation)
 			switch {
+			case key.Matches(msg, DefaultKeyMap.Help):
+				m.showHelp = true
+				return m, nil
+
+			case key.Matches(msg, DefaultKeyMap.Search):
+				m.search = newSearchOverlay()
+				return m, nil
+
 			case key.Matches(msg, DefaultKeyMap.Quit):
 				t, _ := m.app.TimerService.GetActiveTimer(context.Background())
 				if t != nil {
-					m.quitMsg = "Timer is running. Stop or discard it before quitting."
-					return m, nil
+					return m, notify(NotifyWarning, "Timer is running. Stop or discard it before quitting.")
+				}
+				if m.windowTitle != "" {
+					return m, tea.Sequence(tea.SetWindowTitle(defaultWindowTitle), tea.Quit)
 				}
 				return m, tea.Quit
 
@@ -227,6 +481,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmd := m.initScreen(ScreenInvoices)
 				return m, cmd
 
+			case key.Matches(msg, DefaultKeyMap.Estimates):
+				m.currentScreen = ScreenEstimates
+				cmd := m.initScreen(ScreenEstimates)
+				return m, cmd
+
 			case key.Matches(msg, DefaultKeyMap.Reports):
 				m.currentScreen = ScreenReports
 				cmd := m.initScreen(ScreenReports)
@@ -250,13 +509,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.checkedFirstRun = true
 		return m, nil
 
+	case searchResultsMsg:
+		if m.search != nil {
+			m.search.setResults(msg)
+		}
+		return m, nil
+
 	case SwitchScreenMsg:
+		m.showHelp = false
+		m.search = nil
 		m.currentScreen = msg.Screen
 		cmd := m.initScreen(msg.Screen)
+		if msg.Select != nil {
+			cmd = tea.Batch(cmd, func() tea.Msg { return msg.Select })
+		}
 		return m, cmd
 
 	case ErrorMsg:
-		m.err = msg.Err
+		return m, notifyErr(msg.Err)
+
+	case NotifyMsg:
+		m.toastSeq++
+		m.toast = &toast{level: msg.Level, text: msg.Text, id: m.toastSeq}
+		return m, dismissToastCmd(m.toastSeq)
+
+	case toastExpiredMsg:
+		if m.toast != nil && m.toast.id == msg.id {
+			m.toast = nil
+		}
+		return m, nil
+
+	case lockCheckMsg:
+		if m.lock == nil {
+			idle := autoLockAfter(m.app)
+			if m.app.IsLocked() {
+				m.lock = newLockScreen()
+			} else if idle > 0 && time.Since(m.lastActivity) >= idle {
+				if err := m.app.Lock(); err != nil {
+					return m, tea.Batch(notifyErr(err), lockCheckCmd())
+				}
+				m.lock = newLockScreen()
+			}
+		}
+		return m, lockCheckCmd()
+
+	case sleepCheckMsg:
+		prev := m.lastSleepCheck
+		m.lastSleepCheck = msg.at
+		if sleptBetween(prev, msg.at) < sleepThreshold {
+			return m, sleepCheckCmd()
+		}
+		return m, tea.Batch(pauseForSleepCmd(m.app, prev.Round(0)), sleepCheckCmd())
+
+	case windowTitleMsg:
+		next := windowTitleCmd(m.app)
+		if msg.title == m.windowTitle {
+			return m, next
+		}
+		m.windowTitle = msg.title
+		return m, tea.Batch(tea.SetWindowTitle(msg.title), next)
+
+	case unlockResultMsg:
+		if m.lock == nil {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.lock.err = msg.err
+			return m, nil
+		}
+		m.lock = nil
+		m.lastActivity = time.Now()
 		return m, nil
 	}
 
@@ -283,6 +605,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.invoices != nil {
 			m.invoices, cmd = m.invoices.Update(msg)
 		}
+	case ScreenEstimates:
+		if m.estimates != nil {
+			m.estimates, cmd = m.estimates.Update(msg)
+		}
 	case ScreenReports:
 		if m.reports != nil {
 			m.reports, cmd = m.reports.Update(msg)
@@ -305,66 +631,21 @@ func (m Model) View() string {
 	// Header
 	header := headerStyle.Render(fmt.Sprintf("timesink - %s", m.currentScreen.String()))
 
-	// Footer with navigation keys
-	footer := footerStyle.Render("[T]imer  [E]ntries  [C]lients  [I]nvoices  [R]eports  [,] Settings  [Q]uit")
+	footer := footerStyle.Render(navFooter())
+	if m.lock != nil {
+		footer = footerStyle.Render(keyHelpText(unlockKeys))
+	} else if m.search != nil {
+		footer = footerStyle.Render(keyHelpText(searchHelp()))
+	}
 
-	// Current screen content
-	var content string
-	switch m.currentScreen {
-	case ScreenDashboard:
-		if m.dashboard != nil {
-			content = m.dashboard.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenTimer:
-		if m.timer != nil {
-			content = m.timer.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenEntries:
-		if m.entries != nil {
-			content = m.entries.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenClients:
-		if m.clients != nil {
-			content = m.clients.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenInvoices:
-		if m.invoices != nil {
-			content = m.invoices.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenReports:
-		if m.reports != nil {
-			content = m.reports.View()
-		} else {
-			content = "Loading..."
-		}
-	case ScreenSettings:
-		if m.settings != nil {
-			content = m.settings.View()
-		} else {
-			content = "Loading..."
-		}
+	// Toast notification, hidden while locked since it may name amounts
+	toastDisplay := ""
+	if m.toast != nil && m.lock == nil {
+		toastDisplay = "\n" + m.toast.View()
 	}
 
-	// Error/warning display
-	errorDisplay := ""
-	if m.quitMsg != "" {
-		errorDisplay = lipgloss.NewStyle().
-			Foreground(warningColor).
-			Render(fmt.Sprintf("\n%s", m.quitMsg))
-	} else if m.err != nil {
-		errorDisplay = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Render(fmt.Sprintf("\nError: %s", m.err.Error()))
+	if m.content.TotalLineCount() > m.content.Height {
+		footer += "\n" + helpStyle.Render(fmt.Sprintf("pgup/pgdn: scroll (%d%%)", int(m.content.ScrollPercent()*100)))
 	}
 
 	// Divider line between header and content
@@ -380,7 +661,7 @@ func (m Model) View() string {
 		strings.Repeat("─", dividerWidth),
 	)
 
-	body := fmt.Sprintf("%s\n%s\n\n%s%s\n\n%s\n%s", header, divider, content, errorDisplay, divider, footer)
+	body := fmt.Sprintf("%s\n%s\n\n%s%s\n\n%s\n%s", header, divider, m.content.View(), toastDisplay, divider, footer)
 
 	// Wrap in border, sized to terminal
 	frame := appBorderStyle.
@@ -389,9 +670,46 @@ func (m Model) View() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, frame.Render(body))
 }
 
-// Run starts the TUI
-func Run(a *app.App) error {
-	p := tea.NewProgram(New(a), tea.WithAltScreen())
+// Run starts the TUI on the given screen
+func Run(a *app.App, start Screen) error {
+	return run(a, start, nil)
+}
+
+// RunReview starts the TUI on the weekly review of the week holding day
+func RunReview(a *app.App, day time.Time) error {
+	return run(a, ScreenReview, reviewWeekMsg{week: reviewWeekStart(day)})
+}
+
+// run starts the TUI on start, passing it selectMsg once it is open
+func run(a *app.App, start Screen, selectMsg tea.Msg) error {
+	if err := applyConfig(a.Config); err != nil {
+		return err
+	}
+
+	m := New(a)
+	m.startScreen = start
+	m.startSelect = selectMsg
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
+
+// applyConfig applies the theme, keybindings, and locale from cfg. It runs
+// at startup and again whenever Settings saves, so changes show up without
+// a restart.
+func applyConfig(cfg *config.Config) error {
+	t, err := themeFromConfig(cfg.Theme)
+	if err != nil {
+		return fmt.Errorf("failed to load theme: %w", err)
+	}
+
+	km, err := keyMapFromConfig(cfg.Keybindings)
+	if err != nil {
+		return fmt.Errorf("failed to load keybindings: %w", err)
+	}
+
+	applyTheme(t)
+	DefaultKeyMap = km
+	activeLocale = locale.FromConfig(cfg.Locale)
+	return nil
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NotifyLevel is the severity of a toast notification
+type NotifyLevel int
+
+const (
+	NotifySuccess NotifyLevel = iota
+	NotifyWarning
+	NotifyError
+)
+
+// NotifyMsg asks the root model to show a toast below the current screen
+type NotifyMsg struct {
+	Level NotifyLevel
+	Text  string
+}
+
+// toastDuration is how long a toast stays up before it is dismissed
+const toastDuration = 4 * time.Second
+
+// notify returns a command that shows a toast
+func notify(level NotifyLevel, text string) tea.Cmd {
+	return func() tea.Msg { return NotifyMsg{Level: level, Text: text} }
+}
+
+// notifyErr returns a command that shows an error toast
+func notifyErr(err error) tea.Cmd {
+	return notify(NotifyError, "Error: "+err.Error())
+}
+
+// toast is the notification currently on screen. The id ties it to its
+// dismissal tick so an older tick cannot clear a newer toast.
+type toast struct {
+	level NotifyLevel
+	text  string
+	id    int
+}
+
+// toastExpiredMsg dismisses the toast with the matching id
+type toastExpiredMsg struct {
+	id int
+}
+
+// dismissToastCmd schedules the toast's dismissal
+func dismissToastCmd(id int) tea.Cmd {
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+// View renders the toast in its level's color
+func (t toast) View() string {
+	color := successColor
+	switch t.level {
+	case NotifyWarning:
+		color = warningColor
+	case NotifyError:
+		color = errorColor
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(t.text)
+}
@@ -3,10 +3,13 @@ package tui
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/domain"
 	"github.com/andy/timesink/internal/service"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,6 +21,8 @@ type ReportsModel struct {
 	app       *app.App
 	weekStart time.Time
 	revenueYear int
+	revenueBasis service.RevenueBasis
+	heatmapRange dateRange
 
 	// Week data
 	weekSummary *service.WeekSummary
@@ -29,9 +34,24 @@ type ReportsModel struct {
 	dailySummary *service.DailySummary
 
 	// Financial data
-	outstanding float64
-	unbilled    float64
-	monthly     map[time.Month]float64
+	outstanding domain.Money
+	unbilled    domain.Money
+	monthly     map[time.Month]domain.Money
+	aging       *service.AgingReport
+
+	// Each client's share of revenueYear's revenue
+	clientRevenue *service.RevenueByClient
+
+	// Working hours over heatmapRange
+	heatmap *service.HoursHeatmap
+
+	// Choosing the heatmap's range
+	picking     bool
+	rangePicker dateRangePicker
+
+	// Billable target against client demand for the coming capacityWeeks
+	// weeks; nil without a weekly target
+	capacity []*service.CapacityWeek
 
 	loading bool
 	err     error
@@ -41,12 +61,21 @@ type reportsDataMsg struct {
 	weekSummary *service.WeekSummary
 	clientNames map[int64]string
 	clientRates map[int64]float64
-	outstanding float64
-	unbilled    float64
-	monthly     map[time.Month]float64
-	err         error
+	outstanding domain.Money
+	unbilled    domain.Money
+	monthly     map[time.Month]domain.Money
+	aging       *service.AgingReport
+	heatmap     *service.HoursHeatmap
+
+	clientRevenue *service.RevenueByClient
+	capacity      []*service.CapacityWeek
+
+	err error
 }
 
+// capacityWeeks is how far ahead the capacity panel plans
+const capacityWeeks = 6
+
 type dailyDetailMsg struct {
 	summary *service.DailySummary
 	err     error
@@ -58,6 +87,8 @@ func NewReportsModel(a *app.App) tea.Model {
 		app:         a,
 		weekStart:   weekMonday(time.Now()),
 		revenueYear: time.Now().Year(),
+		revenueBasis: service.RevenueCash,
+		heatmapRange: dateRangePresets[0].rangeOn(midnight(time.Now())),
 		loading:     true,
 	}
 }
@@ -67,39 +98,88 @@ func (m *ReportsModel) Init() tea.Cmd {
 }
 
 func (m *ReportsModel) loadData() tea.Cmd {
+	m.heatmapRange = m.heatmapRange.asOf(midnight(time.Now()))
+	key := fmt.Sprintf("reports:%s:%s:%s:%d:%s:%d:%s", m.weekStart.Format("2006-01-02"),
+		m.heatmapRange.start.Format("2006-01-02"), m.heatmapRange.end.Format("2006-01-02"), m.revenueYear, m.revenueBasis, m.app.Config.Invoice.DefaultDueDays, today())
 	return func() tea.Msg {
-		ctx := context.Background()
-		msg := reportsDataMsg{
-			clientNames: make(map[int64]string),
-			clientRates: make(map[int64]float64),
-		}
+		msg, _ := cached(screenData, key, func() (reportsDataMsg, error) {
+			msg := m.fetchData()
+			return msg, msg.err
+		})
+		return msg
+	}
+}
 
-		// Week summary
-		ws, err := m.app.ReportService.GetWeekSummary(ctx, m.weekStart)
-		if err != nil {
-			msg.err = err
-			return msg
+func (m *ReportsModel) fetchData() reportsDataMsg {
+	ctx := context.Background()
+	msg := reportsDataMsg{
+		clientNames: make(map[int64]string),
+		clientRates: make(map[int64]float64),
+	}
+
+	// Week summary
+	ws, err := m.app.ReportService.GetWeekSummary(ctx, m.weekStart)
+	if err != nil {
+		msg.err = err
+		return msg
+	}
+	msg.weekSummary = ws
+
+	// Resolve client names and rates
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		msg.err = err
+		return msg
+	}
+	for cid := range ws.ByClient {
+		if client, ok := clients[cid]; ok {
+			msg.clientNames[cid] = client.Name
+			msg.clientRates[cid] = client.HourlyRate
 		}
-		msg.weekSummary = ws
-
-		// Resolve client names and rates
-		for cid := range ws.ByClient {
-			client, err := m.app.ClientRepo.GetByID(ctx, cid)
-			if err == nil && client != nil {
-				msg.clientNames[cid] = client.Name
-				msg.clientRates[cid] = client.HourlyRate
-			}
+	}
+
+	// Working hours over the chosen days
+	now := time.Now()
+	msg.heatmap, _ = m.app.ReportService.GetHoursHeatmap(ctx, m.heatmapRange.start, m.heatmapRange.until())
+
+	// Coming weeks' capacity against client demand
+	if target := m.app.Config.Workday.WeeklyBillableHours; target > 0 {
+		list := make([]*domain.Client, 0, len(clients))
+		for _, client := range clients {
+			list = append(list, client)
 		}
+		msg.capacity, _ = m.app.ReportService.GetCapacity(ctx, now, capacityWeeks, target, list)
+	}
 
-		// Financial
-		msg.outstanding, _ = m.app.ReportService.GetOutstandingTotal(ctx)
-		msg.unbilled, _ = m.app.ReportService.GetUnbilledTotal(ctx)
+	// Financial
+	msg.outstanding, _ = m.app.ReportService.GetOutstandingTotal(ctx)
+	msg.unbilled, _ = m.app.ReportService.GetUnbilledTotal(ctx)
 
-		// Monthly revenue
-		msg.monthly, _ = m.app.ReportService.GetRevenueByMonth(ctx, m.revenueYear)
+	// Monthly revenue
+	msg.monthly, _ = m.app.ReportService.GetRevenueByMonth(ctx, m.revenueYear, m.revenueBasis)
+
+	// Revenue by client over the same year
+	yearStart := time.Date(m.revenueYear, time.January, 1, 0, 0, 0, 0, time.Local)
+	msg.clientRevenue, _ = m.app.ReportService.GetRevenueByClient(ctx, yearStart, yearStart.AddDate(1, 0, 0), m.revenueBasis)
+	if msg.clientRevenue != nil {
+		for _, row := range msg.clientRevenue.Clients {
+			if client, ok := clients[row.ClientID]; ok {
+				msg.clientNames[row.ClientID] = client.Name
+			}
+		}
+	}
 
-		return msg
+	// Receivables aging
+	msg.aging, _ = m.app.ReportService.GetAging(ctx, time.Now(), m.app.Config.Invoice.DefaultDueDays)
+	if msg.aging != nil {
+		for _, row := range msg.aging.Clients {
+			if client, ok := clients[row.ClientID]; ok {
+				msg.clientNames[row.ClientID] = client.Name
+			}
+		}
 	}
+
+	return msg
 }
 
 func (m *ReportsModel) loadDailyDetail() tea.Cmd {
@@ -130,6 +210,10 @@ func (m *ReportsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.outstanding = msg.outstanding
 			m.unbilled = msg.unbilled
 			m.monthly = msg.monthly
+			m.aging = msg.aging
+			m.clientRevenue = msg.clientRevenue
+			m.heatmap = msg.heatmap
+			m.capacity = msg.capacity
 		}
 		// Load daily detail for current cursor
 		if msg.err == nil {
@@ -149,6 +233,9 @@ func (m *ReportsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.loading {
 			return m, nil
 		}
+		if m.picking {
+			return m.updateRangePicker(msg)
+		}
 
 		switch {
 		case key.Matches(msg, DefaultKeyMap.Left):
@@ -180,30 +267,87 @@ func (m *ReportsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.loadDailyDetail()
 			}
 
-		case msg.String() == "[":
+		case key.Matches(msg, DefaultKeyMap.PrevYear):
 			// Previous year for revenue
 			m.revenueYear--
 			m.loading = true
 			return m, m.loadData()
 
-		case msg.String() == "]":
+		case key.Matches(msg, DefaultKeyMap.NextYear):
 			// Next year for revenue
 			if m.revenueYear < time.Now().Year() {
 				m.revenueYear++
 				m.loading = true
 				return m, m.loadData()
 			}
+
+		case key.Matches(msg, DefaultKeyMap.RevenueBasis):
+			// Switch revenue between cash and accrual
+			if m.revenueBasis == service.RevenueCash {
+				m.revenueBasis = service.RevenueAccrual
+			} else {
+				m.revenueBasis = service.RevenueCash
+			}
+			m.loading = true
+			return m, m.loadData()
+
+		case key.Matches(msg, DefaultKeyMap.HeatmapRange):
+			m.rangePicker = newDateRangePicker("Working Hours Range", m.heatmapRange)
+			m.picking = true
 		}
 	}
 
 	return m, nil
 }
 
+// updateRangePicker picks the heatmap's range, reloading once one is picked
+func (m *ReportsModel) updateRangePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, DefaultKeyMap.Cancel) {
+		m.picking = false
+		return m, nil
+	}
+	r, ok := m.rangePicker.Update(msg)
+	if !ok {
+		return m, nil
+	}
+	m.picking = false
+	m.heatmapRange = r
+	m.loading = true
+	return m, m.loadData()
+}
+
+// IsCapturingInput returns true while the heatmap's range is being picked
+func (m *ReportsModel) IsCapturingInput() bool {
+	return m.picking
+}
+
+// KeyHelp lists the keys for moving between days, weeks, and revenue years,
+// and for switching the revenue basis and heatmap range
+func (m *ReportsModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.picking {
+		return m.rangePicker.KeyHelp()
+	}
+	return []key.Binding{
+		withHelp(navigateKeys(), "select day"),
+		withHelp(k.Left, "previous week"),
+		withHelp(k.Right, "next week"),
+		k.PrevYear,
+		k.NextYear,
+		k.RevenueBasis,
+		k.HeatmapRange,
+	}
+}
+
 func (m *ReportsModel) View() string {
 	if m.loading {
 		return titleStyle.Render("Reports") + "\n\n  Loading..."
 	}
 
+	if m.picking {
+		return m.rangePicker.View()
+	}
+
 	if m.err != nil {
 		return titleStyle.Render("Reports") + "\n\n" +
 			lipgloss.NewStyle().Foreground(errorColor).Render(fmt.Sprintf("  Error: %v", m.err))
@@ -215,13 +359,16 @@ func (m *ReportsModel) View() string {
 	weekEnd := m.weekStart.AddDate(0, 0, 6)
 	s += titleStyle.Render("Reports") + "\n"
 	s += fmt.Sprintf("  Week of %s - %s\n\n",
-		m.weekStart.Format("Jan 2"),
-		weekEnd.Format("Jan 2, 2006"),
+		formatShortDate(m.weekStart),
+		formatLongDate(weekEnd),
 	)
 
 	// Weekly hours bar chart with day selection
-	s += lipgloss.NewStyle().Bold(true).Render("  Hours by Day") + "\n"
-	s += m.renderWeekChart()
+	s += lipgloss.NewStyle().Bold(true).Render("  Hours by Day")
+	if target := m.app.Config.Workday.TargetHours; target > 0 {
+		s += subtitleStyle.Render(fmt.Sprintf("  │ target %s/day", formatHours(target)))
+	}
+	s += "\n" + m.renderWeekChart()
 	s += "\n"
 
 	// Weekly totals with utilization
@@ -235,17 +382,29 @@ func (m *ReportsModel) View() string {
 	// Hours & value by client
 	s += m.renderClientBreakdown()
 
+	// Working hours by day and hour
+	s += m.renderHeatmap()
+
+	// Coming weeks over- or underbooked
+	s += m.renderCapacity()
+
 	// Financial overview
 	s += lipgloss.NewStyle().Bold(true).Render("  Financial Overview") + "\n"
-	s += fmt.Sprintf("    Outstanding: %s\n", formatMoney(m.outstanding))
-	s += fmt.Sprintf("    Unbilled:    %s\n", formatMoney(m.unbilled))
+	s += fmt.Sprintf("    Outstanding: %s\n", formatMoney(m.outstanding.Float()))
+	s += fmt.Sprintf("    Unbilled:    %s\n", formatMoney(m.unbilled.Float()))
 	s += "\n"
 
+	// Receivables by days past due
+	s += m.renderAging()
+
 	// Monthly revenue
 	s += m.renderMonthlyRevenue()
 
+	// Revenue by client, warning of dependence on one
+	s += m.renderClientRevenue()
+
 	// Key help
-	s += "\n" + helpStyle.Render("  j/k: select day  h/l: prev/next week  [/]: prev/next year")
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -261,8 +420,9 @@ func (m *ReportsModel) renderWeekChart() string {
 		return "    No data\n"
 	}
 
-	// Find max for scaling
-	maxHours := 0.0
+	// Find max for scaling, leaving room for the target marker
+	target := m.app.Config.Workday.TargetHours
+	maxHours := target
 	for _, h := range ws.ByDay {
 		if h > maxHours {
 			maxHours = h
@@ -277,31 +437,31 @@ func (m *ReportsModel) renderWeekChart() string {
 		if maxHours > 0 {
 			barLen = int((hours / maxHours) * float64(maxBar))
 		}
-		bar := ""
-		for j := 0; j < barLen; j++ {
-			bar += "█"
-		}
+		bar := targetBar(barLen, maxBar, target, maxHours)
 
 		selected := i == m.dayCursor
 
 		dayName := day.String()[:3]
-		dateStr := m.weekStart.AddDate(0, 0, i).Format("Jan 2")
+		dateStr := formatShortDate(m.weekStart.AddDate(0, 0, i))
 		label := fmt.Sprintf("%s %s", dayName, dateStr)
 
 		dayStyle := lipgloss.NewStyle().Width(12)
 		barStyle := lipgloss.NewStyle().Foreground(primaryColor)
 		hoursStr := formatHours(hours)
+		if off, ok := ws.DaysOff[day]; ok {
+			hoursStr += " " + subtitleStyle.Render(string(off))
+		}
 
 		line := fmt.Sprintf("    %s %s %s",
 			dayStyle.Render(label),
-			barStyle.Render(fmt.Sprintf("%-25s", bar)),
+			barStyle.Render(bar),
 			hoursStr,
 		)
 
 		if selected {
 			chart += lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Render(fmt.Sprintf("  > %s %s %s",
 				dayStyle.Render(label),
-				barStyle.Render(fmt.Sprintf("%-25s", bar)),
+				barStyle.Render(bar),
 				hoursStr,
 			)) + "\n"
 		} else {
@@ -312,6 +472,22 @@ func (m *ReportsModel) renderWeekChart() string {
 	return chart
 }
 
+// targetBar draws a bar barLen cells long in a chart width cells wide, with
+// a marker at the daily target when there is one. The marker sits on the
+// last cell a day reaching the target fills.
+func targetBar(barLen, width int, target, maxHours float64) string {
+	cells := []rune(strings.Repeat("█", barLen) + strings.Repeat(" ", width-barLen))
+	if target > 0 && maxHours > 0 {
+		pos := max(int(target/maxHours*float64(width))-1, 0)
+		if pos < barLen {
+			cells[pos] = '┃'
+		} else {
+			cells[pos] = '│'
+		}
+	}
+	return string(cells)
+}
+
 func (m *ReportsModel) renderWeekTotals() string {
 	ws := m.weekSummary
 	if ws == nil {
@@ -321,11 +497,11 @@ func (m *ReportsModel) renderWeekTotals() string {
 	s := lipgloss.NewStyle().Bold(true).Render("  Weekly Totals") + "\n"
 	s += fmt.Sprintf("    Total:       %s\n", formatHours(ws.TotalHours))
 	s += fmt.Sprintf("    Billable:    %s\n", formatHours(ws.BillableHours))
-	s += fmt.Sprintf("    Value:       %s\n", formatMoney(ws.TotalValue))
+	s += fmt.Sprintf("    Value:       %s\n", formatMoney(ws.TotalValue.Float()))
 
-	// Utilization rate
-	if ws.TotalHours > 0 {
-		utilization := (ws.BillableHours / ws.TotalHours) * 100
+	// Utilization rate, leaving out days off
+	if ws.TotalHours > ws.OffHours {
+		utilization := ws.Utilization()
 		utilStr := fmt.Sprintf("%.0f%%", utilization)
 		style := lipgloss.NewStyle()
 		if utilization >= 80 {
@@ -361,7 +537,7 @@ func (m *ReportsModel) renderDailyDetail() string {
 	s += subtitleStyle.Render(fmt.Sprintf("    %s total  |  %s billable  |  %s",
 		formatHours(ds.TotalHours),
 		formatHours(ds.BillableHours),
-		formatMoney(ds.TotalValue),
+		formatMoney(ds.TotalValue.Float()),
 	)) + "\n"
 
 	for _, entry := range ds.Entries {
@@ -383,19 +559,19 @@ func (m *ReportsModel) renderDailyDetail() string {
 
 		billable := " "
 		if !entry.IsBillable {
-			billable = lipgloss.NewStyle().Foreground(mutedColor).Render("nb")
+			billable = nonBillableStyle.Render("nb")
 		}
 
 		line := fmt.Sprintf("    %s  %-15s  %s  %10s  %s",
 			timeRange,
 			truncateStr(clientName, 15),
 			formatHours(entry.Duration().Hours()),
-			formatMoney(entry.Amount()),
+			formatMoney(entry.Amount().Float()),
 			billable,
 		)
 
 		if !entry.IsBillable {
-			s += lipgloss.NewStyle().Foreground(mutedColor).Render(line) + "\n"
+			s += nonBillableStyle.Render(line) + "\n"
 		} else {
 			s += line + "\n"
 		}
@@ -414,6 +590,8 @@ func (m *ReportsModel) renderClientBreakdown() string {
 	}
 
 	s := lipgloss.NewStyle().Bold(true).Render("  Hours & Value by Client") + "\n"
+	s += subtitleStyle.Render(fmt.Sprintf("    %-20s  %6s  %6s  %6s  %10s",
+		"", "Total", "Bill", "Non-b", "Value")) + "\n"
 
 	// Sort clients by hours descending
 	type clientEntry struct {
@@ -434,15 +612,23 @@ func (m *ReportsModel) renderClientBreakdown() string {
 			name = fmt.Sprintf("Client #%d", ce.id)
 		}
 		rate := m.clientRates[ce.id]
-		value := ce.hours * rate
+		billable := ws.BillableByClient[ce.id]
+		nonBillable := ce.hours - billable
+		value := billable * rate
 
-		s += fmt.Sprintf("    %-20s  %s  %s",
+		s += fmt.Sprintf("    %-20s  %6s  %6s  %s  %10s",
 			truncateStr(name, 20),
 			formatHours(ce.hours),
+			formatHours(billable),
+			nonBillableStyle.Render(fmt.Sprintf("%6s", formatHours(nonBillable))),
 			formatMoney(value),
 		)
 		if rate > 0 {
 			s += subtitleStyle.Render(fmt.Sprintf("  @ %s/hr", formatMoney(rate)))
+			// Unpaid admin time, valued at the client's rate
+			if nonBillable > 0 {
+				s += nonBillableStyle.Render(fmt.Sprintf("  (%s unbilled)", formatMoney(nonBillable*rate)))
+			}
 		}
 		s += "\n"
 	}
@@ -451,9 +637,87 @@ func (m *ReportsModel) renderClientBreakdown() string {
 	return s
 }
 
+func (m *ReportsModel) renderAging() string {
+	if m.aging == nil || len(m.aging.Clients) == 0 {
+		return ""
+	}
+
+	s := lipgloss.NewStyle().Bold(true).Render("  Receivables Aging (days past due)") + "\n"
+	b := service.AgingBuckets
+	s += subtitleStyle.Render(fmt.Sprintf("    %-20s  %10s  %10s  %10s  %10s  %10s",
+		"", b[0], b[1], b[2], b[3], "Total")) + "\n"
+
+	row := func(name string, buckets [4]domain.Money, total domain.Money) string {
+		line := fmt.Sprintf("    %-20s", truncateStr(name, 20))
+		for i, amount := range buckets {
+			cell := fmt.Sprintf("  %10s", formatMoney(amount.Float()))
+			// Anything over 60 days late needs chasing
+			if i >= 2 && amount > 0 {
+				cell = lipgloss.NewStyle().Foreground(errorColor).Render(cell)
+			}
+			line += cell
+		}
+		return line + fmt.Sprintf("  %10s", formatMoney(total.Float()))
+	}
+
+	for _, client := range m.aging.Clients {
+		name := m.clientNames[client.ClientID]
+		if name == "" {
+			name = fmt.Sprintf("Client #%d", client.ClientID)
+		}
+		s += row(name, client.Buckets, client.Total) + "\n"
+	}
+	if len(m.aging.Clients) > 1 {
+		s += lipgloss.NewStyle().Bold(true).Render(row("Total", m.aging.Buckets, m.aging.Total)) + "\n"
+	}
+
+	s += "\n"
+	return s
+}
+
+func (m *ReportsModel) renderHeatmap() string {
+	s := lipgloss.NewStyle().Bold(true).Render(
+		fmt.Sprintf("  Working Hours (%s)", m.heatmapRange),
+	) + "\n"
+
+	h := m.heatmap
+	if h == nil || h.Total == 0 {
+		return s + subtitleStyle.Render("    No time tracked") + "\n\n"
+	}
+
+	header := "        "
+	for hour := 0; hour < 24; hour += 3 {
+		header += fmt.Sprintf("%-6d", hour)
+	}
+	s += subtitleStyle.Render(header) + "\n"
+
+	shades := [5]string{" ·", "░░", "▒▒", "▓▓", "██"}
+	days := []time.Weekday{
+		time.Monday, time.Tuesday, time.Wednesday,
+		time.Thursday, time.Friday, time.Saturday, time.Sunday,
+	}
+	cell := lipgloss.NewStyle().Foreground(primaryColor)
+	for _, day := range days {
+		line := ""
+		for hour := 0; hour < 24; hour++ {
+			level := h.Level(day, hour)
+			if level == 0 {
+				line += subtitleStyle.Render(shades[0])
+			} else {
+				line += cell.Render(shades[level])
+			}
+		}
+		s += fmt.Sprintf("    %-4s%s\n", day.String()[:3], line)
+	}
+
+	day, hour := h.Busiest()
+	s += subtitleStyle.Render(fmt.Sprintf("    Busiest: %s %02d:00 (%s)", day.String()[:3], hour, formatHours(h.Max))) + "\n\n"
+	return s
+}
+
 func (m *ReportsModel) renderMonthlyRevenue() string {
 	s := lipgloss.NewStyle().Bold(true).Render(
-		fmt.Sprintf("  Revenue by Month (%d)", m.revenueYear),
+		fmt.Sprintf("  Revenue by Month (%d, %s basis)", m.revenueYear, m.revenueBasis),
 	) + "\n"
 
 	months := []time.Month{
@@ -464,13 +728,13 @@ func (m *ReportsModel) renderMonthlyRevenue() string {
 	}
 
 	hasRevenue := false
-	yearTotal := 0.0
+	var yearTotal domain.Money
 	for _, month := range months {
 		revenue := m.monthly[month]
 		if revenue > 0 {
 			hasRevenue = true
 			yearTotal += revenue
-			s += fmt.Sprintf("    %-10s %s\n", month.String()[:3], formatMoney(revenue))
+			s += fmt.Sprintf("    %-10s %s\n", month.String()[:3], formatMoney(revenue.Float()))
 		}
 	}
 
@@ -478,13 +742,82 @@ func (m *ReportsModel) renderMonthlyRevenue() string {
 		s += subtitleStyle.Render("    No revenue recorded") + "\n"
 	} else {
 		s += "    " + lipgloss.NewStyle().Bold(true).Render(
-			fmt.Sprintf("%-10s %s", "Total", formatMoney(yearTotal)),
+			fmt.Sprintf("%-10s %s", "Total", formatMoney(yearTotal.Float())),
 		) + "\n"
 	}
 
 	return s
 }
 
+func (m *ReportsModel) renderCapacity() string {
+	if len(m.capacity) == 0 {
+		return ""
+	}
+
+	s := lipgloss.NewStyle().Bold(true).Render(
+		fmt.Sprintf("  Capacity (next %d weeks)", len(m.capacity)),
+	)
+	s += subtitleStyle.Render(fmt.Sprintf("  │ target %s/week", formatHours(m.app.Config.Workday.WeeklyBillableHours))) + "\n"
+	s += subtitleStyle.Render(fmt.Sprintf("    %-10s  %8s  %8s  %8s", "Week of", "Capacity", "Demand", "Balance")) + "\n"
+
+	for _, week := range m.capacity {
+		line := fmt.Sprintf("    %-10s  %8s  %8s  %8s",
+			formatShortDate(week.Start),
+			formatHours(week.Capacity),
+			formatHours(week.Demand),
+			formatHours(week.Balance()),
+		)
+		switch balance := week.Balance(); {
+		case balance < 0:
+			line = lipgloss.NewStyle().Foreground(errorColor).Render(line + "  overbooked")
+		case balance > 0:
+			line = lipgloss.NewStyle().Foreground(warningColor).Render(line + "  underbooked")
+		}
+		if week.DaysOff > 0 {
+			line += subtitleStyle.Render(fmt.Sprintf("  (%d days off)", week.DaysOff))
+		}
+		s += line + "\n"
+	}
+
+	s += "\n"
+	return s
+}
+
+func (m *ReportsModel) renderClientRevenue() string {
+	r := m.clientRevenue
+	if r == nil || len(r.Clients) == 0 {
+		return ""
+	}
+
+	s := "\n" + lipgloss.NewStyle().Bold(true).Render(
+		fmt.Sprintf("  Revenue by Client (%d, %s basis)", m.revenueYear, m.revenueBasis),
+	) + "\n"
+
+	bar := lipgloss.NewStyle().Foreground(primaryColor)
+	for _, row := range r.Clients {
+		name := m.clientNames[row.ClientID]
+		if name == "" {
+			name = fmt.Sprintf("Client #%d", row.ClientID)
+		}
+		s += fmt.Sprintf("    %-20s  %10s  %5.1f%%  %s\n",
+			truncateStr(name, 20),
+			formatMoney(row.Revenue.Float()),
+			row.Share*100,
+			bar.Render(strings.Repeat("█", int(math.Round(row.Share*20)))),
+		)
+	}
+
+	limit := m.app.Config.Reports.ConcentrationLimit
+	if top := r.Concentrated(limit); top != nil {
+		name := m.clientNames[top.ClientID]
+		s += lipgloss.NewStyle().Foreground(warningColor).Render(fmt.Sprintf(
+			"    ! %s earns %.0f%% of revenue, over the %.0f%% limit", name, top.Share*100, limit*100,
+		)) + "\n"
+	}
+
+	return s
+}
+
 // weekMonday returns the Monday of the week containing t
 func weekMonday(t time.Time) time.Time {
 	for t.Weekday() != time.Monday {
@@ -0,0 +1,472 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/andy/timesink/internal/service"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type reviewMode int
+
+const (
+	reviewModeDays     reviewMode = iota
+	reviewModeEditDesc            // inline description editing
+	reviewModeReason              // reason prompt before saving the description
+)
+
+// ReviewModel walks through a week's days before invoicing them, flagging
+// weekdays below the daily target and entries without a description, and
+// fixes descriptions in place. It opens on last week.
+type ReviewModel struct {
+	app         *app.App
+	week        time.Time // Monday
+	days        []*service.ReviewDay
+	clientNames map[int64]string
+	day         int // selected day
+	cursor      int // selected entry of the day
+	loading     bool
+	err         error
+
+	// Description editing, with the reason prompt for the audit trail
+	mode        reviewMode
+	descInput   textinput.Model
+	reasonInput textinput.Model
+	pendingDesc string
+}
+
+type reviewDataMsg struct {
+	week        time.Time
+	days        []*service.ReviewDay
+	clientNames map[int64]string
+	err         error
+}
+
+// reviewWeekMsg tells the review screen which week to review
+type reviewWeekMsg struct {
+	week time.Time
+}
+
+type reviewDescUpdatedMsg struct {
+	err error
+}
+
+// reviewWeekStart returns the Monday of the week holding day
+func reviewWeekStart(day time.Time) time.Time {
+	d := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	for d.Weekday() != time.Monday {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// NewReviewModel creates a new weekly review screen model
+func NewReviewModel(a *app.App) tea.Model {
+	return &ReviewModel{
+		app:         a,
+		week:        reviewWeekStart(time.Now().AddDate(0, 0, -7)),
+		clientNames: make(map[int64]string),
+		loading:     true,
+	}
+}
+
+// IsCapturingInput returns true while a description or reason is typed
+func (m *ReviewModel) IsCapturingInput() bool {
+	return m.mode != reviewModeDays
+}
+
+// KeyHelp lists the keys for the current mode
+func (m *ReviewModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	if m.mode != reviewModeDays {
+		return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+	}
+	keys := []key.Binding{withHelp(k.Left, "previous day"), withHelp(k.Right, "next day")}
+	if d := m.selectedDay(); d != nil && len(d.Entries) > 0 {
+		keys = append(keys, navigateKeys(), withHelp(k.Select, "edit desc"))
+	}
+	return append(keys, k.NextIssue, k.PrevWeek, k.NextWeek)
+}
+
+func (m *ReviewModel) Init() tea.Cmd {
+	return m.loadWeek()
+}
+
+func (m *ReviewModel) loadWeek() tea.Cmd {
+	week := m.week
+	key := "review:" + week.Format(time.DateOnly)
+	return func() tea.Msg {
+		msg, _ := cached(screenData, key, func() (reviewDataMsg, error) {
+			msg := m.fetchWeek(week)
+			return msg, msg.err
+		})
+		return msg
+	}
+}
+
+func (m *ReviewModel) fetchWeek(week time.Time) reviewDataMsg {
+	workday := m.app.Config.Workday
+	days, err := m.app.ReportService.GetWeekReview(context.Background(), week,
+		workday.TargetHours, workday.DayOffWords)
+	if err != nil {
+		return reviewDataMsg{week: week, err: err}
+	}
+
+	clients, err := clientsByID(m.app)
+	if err != nil {
+		return reviewDataMsg{week: week, err: err}
+	}
+	clientNames := make(map[int64]string)
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			clientNames[entry.ClientID] = fmt.Sprintf("Client #%d", entry.ClientID)
+			if c, ok := clients[entry.ClientID]; ok {
+				clientNames[entry.ClientID] = c.Name
+			}
+		}
+	}
+	return reviewDataMsg{week: week, days: days, clientNames: clientNames}
+}
+
+// changeWeek reviews another week, starting again from its first day to fix
+func (m *ReviewModel) changeWeek(week time.Time) tea.Cmd {
+	m.week = week
+	m.days = nil
+	m.day, m.cursor = 0, 0
+	m.loading = true
+	return m.loadWeek()
+}
+
+func (m *ReviewModel) selectedDay() *service.ReviewDay {
+	if m.day < 0 || m.day >= len(m.days) {
+		return nil
+	}
+	return m.days[m.day]
+}
+
+func (m *ReviewModel) selectedEntry() *domain.TimeEntry {
+	d := m.selectedDay()
+	if d == nil || m.cursor >= len(d.Entries) {
+		return nil
+	}
+	return d.Entries[m.cursor]
+}
+
+// nextIssue moves to the next entry without a description, or the next day
+// below target, after the selection, going round the week
+func (m *ReviewModel) nextIssue() bool {
+	for i := 0; i <= len(m.days); i++ {
+		di := (m.day + i) % len(m.days)
+		day := m.days[di]
+		from := 0
+		if i == 0 {
+			from = m.cursor + 1
+		} else if day.Short {
+			m.day, m.cursor = di, 0
+			return true
+		}
+		for ei := from; ei < len(day.Entries); ei++ {
+			if strings.TrimSpace(day.Entries[ei].Description) == "" {
+				m.day, m.cursor = di, ei
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *ReviewModel) updateDescription(entry *domain.TimeEntry, desc, reason string) tea.Cmd {
+	return func() tea.Msg {
+		// Edit a copy: the loaded entries are shared with the screen cache
+		updated := *entry
+		updated.Description = desc
+		updated.UpdatedAt = time.Now()
+		err := m.app.EntryRepo.Update(context.Background(), &updated, reason)
+		return reviewDescUpdatedMsg{err: err}
+	}
+}
+
+func (m *ReviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case reviewWeekMsg:
+		m.mode = reviewModeDays
+		return m, m.changeWeek(msg.week)
+
+	case reviewDataMsg:
+		// A week loaded before moving to another is stale
+		if !msg.week.Equal(m.week) {
+			return m, nil
+		}
+		first := m.days == nil
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.days = msg.days
+			m.clientNames = msg.clientNames
+			// A new week opens on its first thing to fix
+			if first {
+				m.day = len(m.days) - 1
+				m.cursor = len(m.days[m.day].Entries)
+				if !m.nextIssue() {
+					m.day, m.cursor = 0, 0
+				}
+			}
+			if d := m.selectedDay(); d != nil && m.cursor >= len(d.Entries) {
+				m.cursor = max(len(d.Entries)-1, 0)
+			}
+		}
+		return m, nil
+
+	case reviewDescUpdatedMsg:
+		m.mode = reviewModeDays
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		m.loading = true
+		return m, tea.Batch(m.loadWeek(), notify(NotifySuccess, "Description updated"))
+	}
+
+	switch m.mode {
+	case reviewModeEditDesc:
+		return m.updateEditDesc(msg)
+	case reviewModeReason:
+		return m.updateReason(msg)
+	}
+
+	switch msg := msg.(type) {
+	case RefreshDataMsg:
+		m.loading = true
+		return m, m.loadWeek()
+
+	case tea.KeyMsg:
+		if m.loading {
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, DefaultKeyMap.PrevWeek):
+			return m, m.changeWeek(m.week.AddDate(0, 0, -7))
+		case key.Matches(msg, DefaultKeyMap.NextWeek):
+			return m, m.changeWeek(m.week.AddDate(0, 0, 7))
+		}
+		if m.err != nil {
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Left):
+			if m.day > 0 {
+				m.day--
+				m.cursor = 0
+			}
+		case key.Matches(msg, DefaultKeyMap.Right):
+			if m.day < len(m.days)-1 {
+				m.day++
+				m.cursor = 0
+			}
+		case key.Matches(msg, DefaultKeyMap.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, DefaultKeyMap.Down):
+			if d := m.selectedDay(); d != nil && m.cursor < len(d.Entries)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, DefaultKeyMap.NextIssue):
+			if !m.nextIssue() {
+				return m, notify(NotifySuccess, "Nothing left to fix this week")
+			}
+		case key.Matches(msg, DefaultKeyMap.Select):
+			entry := m.selectedEntry()
+			if entry == nil {
+				return m, nil
+			}
+			if entry.IsLocked() {
+				return m, notifyErr(fmt.Errorf("cannot edit: entry is locked by an invoice"))
+			}
+			ti := textinput.New()
+			ti.Placeholder = "What was this time spent on?"
+			ti.SetValue(entry.Description)
+			ti.CharLimit = 200
+			ti.Width = 50
+			m.descInput = ti
+			m.mode = reviewModeEditDesc
+			return m, m.descInput.Focus()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *ReviewModel) updateEditDesc(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			m.pendingDesc = strings.TrimSpace(m.descInput.Value())
+			if m.pendingDesc == "" {
+				return m, notifyErr(fmt.Errorf("a description is required"))
+			}
+			ti := textinput.New()
+			ti.Placeholder = "Reason for change..."
+			if !m.app.Config.Audit.RequireReason {
+				ti.Placeholder = "Reason for change (optional)..."
+			}
+			ti.CharLimit = 200
+			ti.Width = 50
+			m.reasonInput = ti
+			m.mode = reviewModeReason
+			return m, m.reasonInput.Focus()
+		case key.Matches(msg, DefaultKeyMap.Cancel):
+			m.mode = reviewModeDays
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.descInput, cmd = m.descInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *ReviewModel) updateReason(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			reason := strings.TrimSpace(m.reasonInput.Value())
+			if reason == "" {
+				if m.app.Config.Audit.RequireReason {
+					return m, notifyErr(fmt.Errorf("a reason is required"))
+				}
+				reason = "description updated in weekly review"
+			}
+			return m, m.updateDescription(m.selectedEntry(), m.pendingDesc, reason)
+		case key.Matches(msg, DefaultKeyMap.Cancel):
+			m.mode = reviewModeDays
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.reasonInput, cmd = m.reasonInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *ReviewModel) View() string {
+	if m.loading {
+		return "Loading week..."
+	}
+	if m.err != nil {
+		return lipgloss.NewStyle().Foreground(errorColor).Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n" +
+			renderKeyHelp(DefaultKeyMap.PrevWeek, DefaultKeyMap.NextWeek)
+	}
+
+	switch m.mode {
+	case reviewModeEditDesc, reviewModeReason:
+		return m.viewEditDesc()
+	}
+	return m.viewDays()
+}
+
+func (m *ReviewModel) viewDays() string {
+	var s string
+	weekEnd := m.week.AddDate(0, 0, 6)
+	s += titleStyle.Render(fmt.Sprintf("Weekly Review: %s - %s", formatShortDate(m.week), formatLongDate(weekEnd))) + "\n"
+
+	toFix := 0
+	for _, d := range m.days {
+		if d.NeedsReview() {
+			toFix++
+		}
+	}
+	if toFix == 0 {
+		s += lipgloss.NewStyle().Foreground(successColor).Render("Nothing to fix: the week is ready to invoice") + "\n\n"
+	} else {
+		s += lapsedStyle.Render(fmt.Sprintf("%d days to fix before invoicing", toFix)) + "\n\n"
+	}
+
+	// The week at a glance, with the days to fix marked
+	cells := make([]string, len(m.days))
+	for i, d := range m.days {
+		mark := " "
+		if d.NeedsReview() {
+			mark = "!"
+		}
+		cell := fmt.Sprintf(" %s %s%s ", d.Date.Weekday().String()[:3], formatHours(d.Hours), mark)
+		switch {
+		case i == m.day:
+			cell = selectedStyle.Render(cell)
+		case d.NeedsReview():
+			cell = lapsedStyle.Render(cell)
+		}
+		cells[i] = cell
+	}
+	s += "  " + strings.Join(cells, " ") + "\n\n"
+
+	day := m.selectedDay()
+	s += titleStyle.Render(fmt.Sprintf("%s, %s", day.Date.Weekday(), formatShortDate(day.Date)))
+	s += fmt.Sprintf("  %s logged\n", formatHours(day.Hours))
+	if day.Short {
+		target := m.app.Config.Workday.TargetHours
+		hint := fmt.Sprintf("  ! %s short of the %s target: log the missing time", formatHours(target-day.Hours), formatHours(target))
+		if words := m.app.Config.Workday.DayOffWords; len(words) > 0 {
+			hint += fmt.Sprintf(", or an entry mentioning %q to explain the day", words[0])
+		}
+		s += lapsedStyle.Render(hint) + "\n"
+	}
+	if n := len(day.Undescribed()); n > 0 {
+		s += lapsedStyle.Render(fmt.Sprintf("  ! %d entries without a description", n)) + "\n"
+	}
+	s += "\n"
+
+	if len(day.Entries) == 0 {
+		s += subtitleStyle.Render("  Nothing logged") + "\n"
+	}
+	for i, entry := range day.Entries {
+		end := "running"
+		if entry.EndTime != nil {
+			end = entry.EndTime.Format("15:04")
+		}
+		desc := entry.Description
+		if strings.TrimSpace(desc) == "" {
+			desc = "(no description)"
+		}
+		line := fmt.Sprintf("  %s-%-7s %-15s %7s  %s", entry.StartTime.Format("15:04"), end,
+			truncateStr(m.clientNames[entry.ClientID], 15), formatHours(entry.Duration().Hours()), truncateStr(desc, 40))
+		if entry.IsLocked() {
+			line += subtitleStyle.Render("  invoiced")
+		}
+		switch {
+		case i == m.cursor:
+			line = selectedStyle.Render(line)
+		case strings.TrimSpace(entry.Description) == "":
+			line = lapsedStyle.Render(line)
+		}
+		s += line + "\n"
+	}
+
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
+	return s
+}
+
+func (m *ReviewModel) viewEditDesc() string {
+	entry := m.selectedEntry()
+	var s string
+	s += titleStyle.Render("Edit Description") + "\n\n"
+	s += fmt.Sprintf("  %s  %s  %s\n\n", formatShortDate(entry.StartTime), m.clientNames[entry.ClientID],
+		formatHours(entry.Duration().Hours()))
+	if m.mode == reviewModeReason {
+		s += fmt.Sprintf("  New description: %s\n\n", truncateStr(m.pendingDesc, 40))
+		s += fmt.Sprintf("  Reason: %s\n\n", m.reasonInput.View())
+	} else {
+		s += fmt.Sprintf("  Description: %s\n\n", m.descInput.View())
+	}
+	s += renderKeyHelp(m.KeyHelp()...) + "\n"
+	return s
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/domain"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchLimit is how many results the search overlay shows
+const searchLimit = 20
+
+// searchResultsMsg carries the results for one version of the query
+type searchResultsMsg struct {
+	seq     int
+	results []*domain.SearchResult
+	err     error
+}
+
+// searchKeys are the keys the search overlay takes from its input. Letters
+// go to the query, so only the arrow keys move the selection.
+var searchKeys = struct {
+	Open, Up, Down, Close key.Binding
+}{
+	Open:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+	Up:    key.NewBinding(key.WithKeys("up"), key.WithHelp("↑", "previous")),
+	Down:  key.NewBinding(key.WithKeys("down"), key.WithHelp("↓", "next")),
+	Close: key.NewBinding(key.WithKeys("esc", "ctrl+c"), key.WithHelp("esc", "close")),
+}
+
+// searchHelp lists the search overlay's keys for the footer
+func searchHelp() []key.Binding {
+	k := searchKeys
+	return []key.Binding{k.Open, k.Up, k.Down, k.Close}
+}
+
+// searchOverlay searches entries and clients as the query is typed, shown in
+// place of the screen until closed
+type searchOverlay struct {
+	input   textinput.Model
+	results []*domain.SearchResult
+	cursor  int
+	err     error
+	seq     int // bumped on every edit, so late results for an old query are dropped
+}
+
+func newSearchOverlay() *searchOverlay {
+	input := textinput.New()
+	input.Placeholder = "words to find"
+	input.Width = 40
+	input.Focus()
+	return &searchOverlay{input: input}
+}
+
+// update handles a key in the search overlay. It returns true once the
+// overlay should close.
+func (s *searchOverlay) update(a *app.App, msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, searchKeys.Close):
+		return nil, true
+	case key.Matches(msg, searchKeys.Up):
+		if s.cursor > 0 {
+			s.cursor--
+		}
+		return nil, false
+	case key.Matches(msg, searchKeys.Down):
+		if s.cursor < len(s.results)-1 {
+			s.cursor++
+		}
+		return nil, false
+	case key.Matches(msg, searchKeys.Open):
+		if len(s.results) == 0 {
+			return nil, false
+		}
+		screen := ScreenEntries
+		if s.results[s.cursor].Kind == domain.SearchClient {
+			screen = ScreenClients
+		}
+		return func() tea.Msg { return SwitchScreenMsg{Screen: screen} }, true
+	}
+
+	before := s.input.Value()
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() == before {
+		return cmd, false
+	}
+
+	s.seq++
+	seq, query := s.seq, s.input.Value()
+	return tea.Batch(cmd, func() tea.Msg {
+		results, err := a.SearchRepo.Search(context.Background(), query, searchLimit)
+		return searchResultsMsg{seq: seq, results: results, err: err}
+	}), false
+}
+
+// setResults shows the results if they are for the current query
+func (s *searchOverlay) setResults(msg searchResultsMsg) {
+	if msg.seq != s.seq {
+		return
+	}
+	s.results, s.err = msg.results, msg.err
+	s.cursor = 0
+}
+
+func (s *searchOverlay) View() string {
+	v := titleStyle.Render("Search") + "\n\n"
+	v += "  " + s.input.View() + "\n\n"
+
+	switch {
+	case s.err != nil:
+		return v + lipgloss.NewStyle().Foreground(errorColor).Render("  Error: "+s.err.Error()) + "\n"
+	case strings.TrimSpace(s.input.Value()) == "":
+		return v + subtitleStyle.Render("  Type to search entry descriptions and notes, and client names and notes.") + "\n"
+	case len(s.results) == 0:
+		return v + subtitleStyle.Render("  No matches") + "\n"
+	}
+
+	matchStyle := lipgloss.NewStyle().Bold(true).Foreground(accentColor)
+	for i, r := range s.results {
+		kind, date := "client", ""
+		if r.Kind == domain.SearchEntry {
+			kind = "entry"
+			date = formatShortDate(*r.Date)
+		}
+		prefix := fmt.Sprintf("  %-7s %-16s  %-7s ", kind, truncateStr(r.ClientName, 16), date)
+		snippet := strings.ReplaceAll(r.Snippet, "\n", " ")
+
+		if i == s.cursor {
+			v += selectedStyle.Render(prefix+domain.HighlightSnippet(snippet, "", "")) + "\n"
+			continue
+		}
+		v += prefix + highlightMatches(snippet, matchStyle) + "\n"
+	}
+
+	if len(s.results) == searchLimit {
+		v += "\n" + subtitleStyle.Render("  Showing the first matches; type more words to narrow the search.") + "\n"
+	}
+	return v
+}
+
+// highlightMatches renders the matched words of a snippet in style
+func highlightMatches(snippet string, style lipgloss.Style) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(snippet, domain.MatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(snippet[start:], domain.MatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(snippet[:start])
+		b.WriteString(style.Render(snippet[start+len(domain.MatchStart) : end]))
+		snippet = snippet[end+len(domain.MatchEnd):]
+	}
+	b.WriteString(domain.HighlightSnippet(snippet, "", ""))
+	return b.String()
+}
@@ -1,10 +1,18 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/locale"
+	"github.com/andy/timesink/internal/logging"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -17,117 +25,459 @@ const (
 	settingsModeEdit
 )
 
-// settings form field indices
-const (
-	settingsFieldOutputDir = iota
-	settingsFieldPrefix
-	settingsFieldDueDays
-	settingsFieldTaxRate
-	settingsFieldCount
-)
+// settingsField is one setting within a section. Fields without set are
+// shown but cannot be edited from the TUI.
+type settingsField struct {
+	label       string
+	hint        string // shown after the label in the form, e.g. "(hm/decimal)"
+	placeholder string
+	width       int
+	value       func(cfg *config.Config) string          // form value
+	display     func(cfg *config.Config) string          // view value; defaults to value
+	set         func(cfg *config.Config, v string) error // validates and stores a form value
+}
+
+func (f settingsField) editable() bool {
+	return f.set != nil
+}
+
+// settingsSection is a tab of related settings
+type settingsSection struct {
+	title  string
+	note   string // shown under the section, e.g. when changes take effect
+	fields []settingsField
+	reset  bool // offers the reset commands
+}
+
+// editableFields returns the fields shown in the section's edit form
+func (s settingsSection) editableFields() []settingsField {
+	var fields []settingsField
+	for _, f := range s.fields {
+		if f.editable() {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
 
 type settingsSavedMsg struct {
 	err error
 }
 
+type resetDoneMsg struct {
+	label string
+	err   error
+}
+
+// resetChoice is one of the reset commands, with what it deletes
+type resetChoice struct {
+	scope   app.ResetScope
+	label   string
+	details []string
+}
+
+// resetChoices are offered on the Data section, smallest first
+var resetChoices = []resetChoice{
+	{app.ResetInvoices, "Invoices", []string{
+		"Every invoice, with its taxes, deliveries and attached files",
+		"Entries on them are unlocked and kept",
+	}},
+	{app.ResetEntries, "Entries", []string{
+		"Every time entry and its history",
+		"Every invoice, with its taxes, deliveries and attached files",
+		"The running timer",
+	}},
+	{app.ResetAll, "Everything", []string{
+		"Every client, with its rates, contracts and time off",
+		"Every entry, invoice and estimate",
+		"The running timer",
+	}},
+}
+
 // SettingsModel manages the settings screen
 type SettingsModel struct {
 	app        *app.App
 	mode       settingsMode
+	sections   []settingsSection
+	section    int
 	fields     []textinput.Model
 	fieldFocus int
 	err        error
-	statusMsg  string
+
+	resetting bool // picking a reset command
+	reset     int  // highlighted reset command
+	confirm   confirmDialog
 }
 
 // NewSettingsModel creates a new settings screen
 func NewSettingsModel(a *app.App) tea.Model {
 	return &SettingsModel{
-		app:  a,
-		mode: settingsModeView,
+		app:      a,
+		mode:     settingsModeView,
+		sections: settingsSections(a),
+	}
+}
+
+// settingsSections lists every section of the settings screen in tab order
+func settingsSections(a *app.App) []settingsSection {
+	return []settingsSection{
+		{
+			title: "Invoice",
+			fields: []settingsField{
+				{
+					label: "Output Directory", placeholder: "/path/to/invoices", width: 60,
+					value: func(c *config.Config) string { return c.Invoice.OutputDir },
+					set: func(c *config.Config, v string) error {
+						if v == "" {
+							return fmt.Errorf("output directory is required")
+						}
+						c.Invoice.OutputDir = v
+						return nil
+					},
+				},
+				{
+					label: "Number Prefix", placeholder: "INV", width: 20,
+					value: func(c *config.Config) string { return c.Invoice.NumberPrefix },
+					set: func(c *config.Config, v string) error {
+						if v == "" {
+							return fmt.Errorf("invoice prefix is required")
+						}
+						c.Invoice.NumberPrefix = v
+						return nil
+					},
+				},
+				{
+					label: "Default Due Days", placeholder: "30", width: 10,
+					value: func(c *config.Config) string { return strconv.Itoa(c.Invoice.DefaultDueDays) },
+					set: func(c *config.Config, v string) error {
+						dueDays, err := strconv.Atoi(v)
+						if err != nil || dueDays <= 0 {
+							return fmt.Errorf("due days must be a positive number")
+						}
+						c.Invoice.DefaultDueDays = dueDays
+						return nil
+					},
+				},
+				{
+					// Entered as a percentage, stored as a decimal
+					label: "Default Tax Rate", hint: "(%)", placeholder: "0.0", width: 10,
+					value: func(c *config.Config) string { return fmt.Sprintf("%.2f", c.Invoice.DefaultTaxRate*100) },
+					display: func(c *config.Config) string {
+						// Tax lines in config.yaml take precedence
+						if len(c.Invoice.Taxes) > 0 {
+							lines := make([]string, len(c.Invoice.Taxes))
+							for i, tax := range c.Invoice.Taxes {
+								lines[i] = fmt.Sprintf("%s %s%%", tax.Label, activeLocale.Number(tax.Rate*100, 2))
+							}
+							return strings.Join(lines, " + ") + " (invoice.taxes)"
+						}
+						return activeLocale.Number(c.Invoice.DefaultTaxRate*100, 2) + "%"
+					},
+					set: func(c *config.Config, v string) error {
+						taxRate, err := activeLocale.ParseNumber(v)
+						if err != nil || taxRate < 0 {
+							return fmt.Errorf("tax rate must be a non-negative number")
+						}
+						c.Invoice.DefaultTaxRate = taxRate / 100
+						return nil
+					},
+				},
+				{
+					label: "Hour Format", hint: "(hm/decimal)", placeholder: config.HourFormatHM, width: 10,
+					value: func(c *config.Config) string { return orDefault(c.Invoice.HourFormat, config.HourFormatHM) },
+					display: func(c *config.Config) string {
+						return fmt.Sprintf("%s (%s)", orDefault(c.Invoice.HourFormat, config.HourFormatHM),
+							formatInvoiceHours(c.Invoice, 7.5))
+					},
+					set: func(c *config.Config, v string) error {
+						v = orDefault(v, config.HourFormatHM)
+						if v != config.HourFormatHM && v != config.HourFormatDecimal {
+							return fmt.Errorf("hour format must be %q or %q",
+								config.HourFormatHM, config.HourFormatDecimal)
+						}
+						c.Invoice.HourFormat = v
+						return nil
+					},
+				},
+				multilineField("Notes", "Thank you for your business", func(c *config.Config) *string { return &c.Invoice.Notes }),
+			},
+		},
+		{
+			title: "User",
+			note:  "Printed in the From block of invoices; payment instructions at the bottom of new ones.",
+			fields: []settingsField{
+				userField("Name", "Jane Doe", func(c *config.Config) *string { return &c.User.Name }),
+				{
+					label: "Email", placeholder: "jane@example.com", width: 40,
+					value: func(c *config.Config) string { return c.User.Email },
+					set: func(c *config.Config, v string) error {
+						if v != "" && !strings.Contains(v, "@") {
+							return fmt.Errorf("email %q is missing an @", v)
+						}
+						c.User.Email = v
+						return nil
+					},
+				},
+				userField("Address", "1 Main St, Springfield", func(c *config.Config) *string { return &c.User.Address }),
+				userField("Phone", "+1 555 0100", func(c *config.Config) *string { return &c.User.Phone }),
+				multilineField("Payment Instructions", "IBAN DE89 3704 0044 0532 0130 00", func(c *config.Config) *string { return &c.User.PaymentInstructions }),
+			},
+		},
+		{
+			title: "Display",
+			note:  "Blank overrides use the locale's own format.",
+			fields: []settingsField{
+				{
+					label: "Locale", hint: "(" + strings.Join(locale.Names(), "/") + ")", placeholder: locale.DefaultName, width: 10,
+					value: func(c *config.Config) string { return c.Locale.Name },
+					set: func(c *config.Config, v string) error {
+						v = orDefault(v, locale.DefaultName)
+						if !slices.Contains(locale.Names(), v) {
+							return fmt.Errorf("unknown locale %q (supported: %s)", v, strings.Join(locale.Names(), ", "))
+						}
+						c.Locale.Name = v
+						return nil
+					},
+				},
+				localeField("Currency Symbol", "$", func(c *config.Config) *string { return &c.Locale.CurrencySymbol }),
+				localeField("Decimal Separator", ".", func(c *config.Config) *string { return &c.Locale.DecimalSeparator }),
+				localeField("Thousands Separator", ",", func(c *config.Config) *string { return &c.Locale.ThousandsSeparator }),
+				dateLayoutField("Short Date", "Jan 2", func(c *config.Config) *string { return &c.Locale.ShortDate }),
+				dateLayoutField("Long Date", "Jan 2, 2006", func(c *config.Config) *string { return &c.Locale.LongDate }),
+				{
+					label: "Example",
+					value: func(c *config.Config) string {
+						return formatMoney(1234.5) + "  " + formatShortDate(time.Now()) + "  " + formatLongDate(time.Now())
+					},
+				},
+				{
+					label: "Theme", hint: "(dark/light/high-contrast)", placeholder: defaultThemeName, width: 20,
+					value: func(c *config.Config) string { return orDefault(c.Theme.Name, defaultThemeName) },
+					set: func(c *config.Config, v string) error {
+						c.Theme.Name = orDefault(v, defaultThemeName)
+						_, err := themeFromConfig(c.Theme)
+						return err
+					},
+				},
+			},
+		},
+		{
+			title: "Workday",
+			note:  "Marked on the week chart. Weekdays below it show on the dashboard unless an entry explains them.",
+			fields: []settingsField{
+				{
+					label: "Target Hours", hint: "(per weekday, 0 = off)", placeholder: "0", width: 10,
+					value: func(c *config.Config) string { return strconv.FormatFloat(c.Workday.TargetHours, 'f', -1, 64) },
+					set: func(c *config.Config, v string) error {
+						hours, err := activeLocale.ParseNumber(orDefault(v, "0"))
+						if err != nil || hours < 0 || hours > 24 {
+							return fmt.Errorf("target hours must be a number from 0 to 24")
+						}
+						c.Workday.TargetHours = hours
+						return nil
+					},
+				},
+				{
+					label: "Start", hint: "(HH:MM, for entries logged as a duration)", placeholder: "09:00", width: 10,
+					value: func(c *config.Config) string { return c.Workday.Start },
+					set: func(c *config.Config, v string) error {
+						v = orDefault(strings.TrimSpace(v), "09:00")
+						if _, err := time.Parse("15:04", v); err != nil {
+							return fmt.Errorf("start must be a time of day as HH:MM, e.g. 09:00")
+						}
+						c.Workday.Start = v
+						return nil
+					},
+				},
+				{
+					label: "Day Off Words",
+					value: func(c *config.Config) string { return strings.Join(c.Workday.DayOffWords, ", ") },
+				},
+			},
+		},
+		{
+			title: "Data",
+			note:  "The database and log paths are set in config.yaml. Log changes apply on next start.",
+			reset: true,
+			fields: []settingsField{
+				{label: "Profile", value: func(c *config.Config) string { return a.Profile }},
+				{label: "Database", value: func(c *config.Config) string { return c.Database.Path }},
+				{label: "Attachments", value: func(c *config.Config) string { return c.Database.AttachmentsDir }},
+				{label: "Log File", value: func(c *config.Config) string { return orDefault(c.Log.Path, "(disabled)") }},
+				{
+					label: "Log Level", hint: "(debug/info/warn/error)", placeholder: "info", width: 10,
+					value: func(c *config.Config) string { return orDefault(c.Log.Level, "info") },
+					set: func(c *config.Config, v string) error {
+						v = orDefault(v, "info")
+						if _, err := logging.ParseLevel(v); err != nil {
+							return err
+						}
+						c.Log.Level = v
+						return nil
+					},
+				},
+				{
+					label: "Auto-Lock", hint: "(minutes idle, 0 = off)", placeholder: "0", width: 10,
+					value: func(c *config.Config) string { return strconv.Itoa(c.Security.AutoLockMinutes) },
+					set: func(c *config.Config, v string) error {
+						minutes, err := strconv.Atoi(orDefault(v, "0"))
+						if err != nil || minutes < 0 {
+							return fmt.Errorf("auto-lock must be a number of minutes, 0 to turn it off")
+						}
+						c.Security.AutoLockMinutes = minutes
+						return nil
+					},
+				},
+				{
+					label: "Require Edit Reason",
+					value: func(c *config.Config) string {
+						if c.Audit.RequireReason {
+							return "yes"
+						}
+						return "no"
+					},
+				},
+			},
+		},
+	}
+}
+
+// userField is a free-text invoice sender field
+func userField(label, placeholder string, ptr func(c *config.Config) *string) settingsField {
+	return settingsField{
+		label: label, placeholder: placeholder, width: 60,
+		value: func(c *config.Config) string { return *ptr(c) },
+		set: func(c *config.Config, v string) error {
+			*ptr(c) = v
+			return nil
+		},
+	}
+}
+
+// multilineField is free text whose line breaks are edited as \n
+func multilineField(label, placeholder string, ptr func(c *config.Config) *string) settingsField {
+	return settingsField{
+		label: label, hint: `(\n for a new line)`, placeholder: placeholder, width: 60,
+		value: func(c *config.Config) string { return escapeNewlines(*ptr(c)) },
+		set: func(c *config.Config, v string) error {
+			*ptr(c) = unescapeNewlines(v)
+			return nil
+		},
 	}
 }
 
-// IsCapturingInput returns true when the edit form is active
+// localeField is an optional override of a locale preset's formatting
+func localeField(label, placeholder string, ptr func(c *config.Config) *string) settingsField {
+	return settingsField{
+		label: label, hint: "(blank for locale default)", placeholder: placeholder, width: 10,
+		value: func(c *config.Config) string { return *ptr(c) },
+		display: func(c *config.Config) string {
+			return orDefault(*ptr(c), "locale default")
+		},
+		set: func(c *config.Config, v string) error {
+			*ptr(c) = v
+			return nil
+		},
+	}
+}
+
+// dateLayoutField is an optional Go time layout override
+func dateLayoutField(label, placeholder string, ptr func(c *config.Config) *string) settingsField {
+	f := localeField(label, placeholder, ptr)
+	f.hint = "(Go layout, e.g. " + placeholder + ")"
+	f.width = 20
+	f.set = func(c *config.Config, v string) error {
+		// A layout with no date elements formats to itself
+		if v != "" && time.Now().Format(v) == v {
+			return fmt.Errorf("%s %q has no date elements (use Go layout like %q)", strings.ToLower(label), v, placeholder)
+		}
+		*ptr(c) = v
+		return nil
+	}
+	return f
+}
+
+// orDefault returns v, or def when v is blank
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// IsCapturingInput returns true when the edit form, the reset commands or
+// a confirmation is active
 func (m *SettingsModel) IsCapturingInput() bool {
-	return m.mode == settingsModeEdit
+	return m.mode == settingsModeEdit || m.resetting || m.confirm.Active()
+}
+
+// KeyHelp lists the keys for the settings view, the edit form or the reset
+// commands
+func (m *SettingsModel) KeyHelp() []key.Binding {
+	if m.mode == settingsModeEdit {
+		return formKeys()
+	}
+	k := DefaultKeyMap
+	switch {
+	case m.confirm.Active():
+		return m.confirm.KeyHelp()
+	case m.resetting:
+		return []key.Binding{navigateKeys(), withHelp(k.Select, "reset"), k.Cancel}
+	}
+	keys := []key.Binding{
+		withHelp(k.Left, "previous section"),
+		withHelp(k.Right, "next section"),
+		withHelp(k.Select, "edit section"),
+		k.RequireReason,
+	}
+	if m.sections[m.section].reset {
+		keys = append(keys, k.Reset)
+	}
+	return keys
 }
 
 func (m *SettingsModel) Init() tea.Cmd {
 	return nil
 }
 
+// initForm builds inputs for the editable fields of the current section
 func (m *SettingsModel) initForm() {
-	m.fields = make([]textinput.Model, settingsFieldCount)
-	cfg := m.app.Config.Invoice
-
-	// Output directory
-	m.fields[settingsFieldOutputDir] = textinput.New()
-	m.fields[settingsFieldOutputDir].Placeholder = "/path/to/invoices"
-	m.fields[settingsFieldOutputDir].CharLimit = 256
-	m.fields[settingsFieldOutputDir].Width = 60
-	m.fields[settingsFieldOutputDir].SetValue(cfg.OutputDir)
-
-	// Number prefix
-	m.fields[settingsFieldPrefix] = textinput.New()
-	m.fields[settingsFieldPrefix].Placeholder = "INV"
-	m.fields[settingsFieldPrefix].CharLimit = 20
-	m.fields[settingsFieldPrefix].Width = 20
-	m.fields[settingsFieldPrefix].SetValue(cfg.NumberPrefix)
-
-	// Default due days
-	m.fields[settingsFieldDueDays] = textinput.New()
-	m.fields[settingsFieldDueDays].Placeholder = "30"
-	m.fields[settingsFieldDueDays].CharLimit = 5
-	m.fields[settingsFieldDueDays].Width = 10
-	m.fields[settingsFieldDueDays].SetValue(strconv.Itoa(cfg.DefaultDueDays))
-
-	// Default tax rate (display as percentage)
-	m.fields[settingsFieldTaxRate] = textinput.New()
-	m.fields[settingsFieldTaxRate].Placeholder = "0.0"
-	m.fields[settingsFieldTaxRate].CharLimit = 10
-	m.fields[settingsFieldTaxRate].Width = 10
-	m.fields[settingsFieldTaxRate].SetValue(fmt.Sprintf("%.2f", cfg.DefaultTaxRate*100))
-
-	m.fieldFocus = settingsFieldOutputDir
-	m.fields[settingsFieldOutputDir].Focus()
+	fields := m.sections[m.section].editableFields()
+	m.fields = make([]textinput.Model, len(fields))
+	for i, f := range fields {
+		m.fields[i] = textinput.New()
+		m.fields[i].Placeholder = f.placeholder
+		m.fields[i].CharLimit = 256
+		m.fields[i].Width = f.width
+		m.fields[i].SetValue(f.value(m.app.Config))
+	}
+
+	m.fieldFocus = 0
+	m.fields[0].Focus()
 }
 
 func (m *SettingsModel) saveSettings() tea.Cmd {
 	return func() tea.Msg {
-		outputDir := m.fields[settingsFieldOutputDir].Value()
-		prefix := m.fields[settingsFieldPrefix].Value()
-		dueDaysStr := m.fields[settingsFieldDueDays].Value()
-		taxRateStr := m.fields[settingsFieldTaxRate].Value()
-
-		if outputDir == "" {
-			return settingsSavedMsg{err: fmt.Errorf("output directory is required")}
-		}
-		if prefix == "" {
-			return settingsSavedMsg{err: fmt.Errorf("invoice prefix is required")}
-		}
-
-		dueDays, err := strconv.Atoi(dueDaysStr)
-		if err != nil || dueDays <= 0 {
-			return settingsSavedMsg{err: fmt.Errorf("due days must be a positive number")}
+		// Validate against a copy so a bad field leaves the config untouched
+		updated := *m.app.Config
+		for i, f := range m.sections[m.section].editableFields() {
+			if err := f.set(&updated, strings.TrimSpace(m.fields[i].Value())); err != nil {
+				return settingsSavedMsg{err: err}
+			}
 		}
 
-		taxRate, err := strconv.ParseFloat(taxRateStr, 64)
-		if err != nil || taxRate < 0 {
-			return settingsSavedMsg{err: fmt.Errorf("tax rate must be a non-negative number")}
+		if _, err := themeFromConfig(updated.Theme); err != nil {
+			return settingsSavedMsg{err: err}
 		}
 
-		// Update config (tax rate stored as decimal)
-		m.app.Config.Invoice.OutputDir = outputDir
-		m.app.Config.Invoice.NumberPrefix = prefix
-		m.app.Config.Invoice.DefaultDueDays = dueDays
-		m.app.Config.Invoice.DefaultTaxRate = taxRate / 100
-
+		previous := *m.app.Config
+		*m.app.Config = updated
 		if err := m.app.SaveConfig(); err != nil {
+			*m.app.Config = previous
 			return settingsSavedMsg{err: fmt.Errorf("failed to save config: %w", err)}
 		}
 
-		return settingsSavedMsg{}
+		return settingsSavedMsg{err: applyConfig(m.app.Config)}
 	}
 }
 
@@ -138,20 +488,88 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.confirm.Active() {
+			return m, m.confirm.Update(msg)
+		}
 		m.err = nil
+		if m.resetting {
+			return m.updateReset(msg)
+		}
 		switch {
-		case msg.String() == "enter":
+		case key.Matches(msg, DefaultKeyMap.Reset) && m.sections[m.section].reset:
+			m.resetting, m.reset = true, 0
+		case key.Matches(msg, DefaultKeyMap.Left):
+			m.section = (m.section - 1 + len(m.sections)) % len(m.sections)
+		case key.Matches(msg, DefaultKeyMap.Right):
+			m.section = (m.section + 1) % len(m.sections)
+		case key.Matches(msg, DefaultKeyMap.Select):
+			if len(m.sections[m.section].editableFields()) == 0 {
+				return m, nil
+			}
 			m.mode = settingsModeEdit
-			m.statusMsg = ""
 			m.initForm()
 			return m, m.fields[m.fieldFocus].Focus()
+		case key.Matches(msg, DefaultKeyMap.RequireReason):
+			return m, m.toggleRequireReason()
+		}
+
+	case settingsSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m, notify(NotifySuccess, "Settings saved")
+
+	case resetDoneMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
 		}
+		return m, notify(NotifySuccess, msg.label+" reset")
 	}
 
 	return m, nil
 }
 
+// updateReset picks a reset command, which runs once confirmed
+func (m *SettingsModel) updateReset(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := DefaultKeyMap
+	switch {
+	case key.Matches(msg, k.Up):
+		m.reset = max(m.reset-1, 0)
+	case key.Matches(msg, k.Down):
+		m.reset = min(m.reset+1, len(resetChoices)-1)
+	case key.Matches(msg, k.Cancel):
+		m.resetting = false
+	case key.Matches(msg, k.Select):
+		m.resetting = false
+		choice := resetChoices[m.reset]
+		m.confirm.Ask("Reset "+choice.label, "Delete this for good?", choice.details, func() tea.Cmd {
+			return m.runReset(choice)
+		})
+	}
+	return m, nil
+}
+
+func (m *SettingsModel) runReset(choice resetChoice) tea.Cmd {
+	return func() tea.Msg {
+		return resetDoneMsg{label: choice.label, err: m.app.Reset(context.Background(), choice.scope)}
+	}
+}
+
+func (m *SettingsModel) toggleRequireReason() tea.Cmd {
+	return func() tea.Msg {
+		m.app.Config.Audit.RequireReason = !m.app.Config.Audit.RequireReason
+		if err := m.app.SaveConfig(); err != nil {
+			return settingsSavedMsg{err: fmt.Errorf("failed to save config: %w", err)}
+		}
+		return settingsSavedMsg{}
+	}
+}
+
 func (m *SettingsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	count := len(m.fields)
+
 	switch msg := msg.(type) {
 	case settingsSavedMsg:
 		if msg.err != nil {
@@ -159,35 +577,34 @@ func (m *SettingsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.mode = settingsModeView
-		m.statusMsg = "Settings saved"
-		return m, nil
+		return m, notify(NotifySuccess, "Settings saved")
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Cancel):
 			m.mode = settingsModeView
 			m.err = nil
 			return m, nil
 
-		case "tab", "down":
+		case key.Matches(msg, DefaultKeyMap.NextField):
 			m.fields[m.fieldFocus].Blur()
-			m.fieldFocus = (m.fieldFocus + 1) % settingsFieldCount
+			m.fieldFocus = (m.fieldFocus + 1) % count
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "shift+tab", "up":
+		case key.Matches(msg, DefaultKeyMap.PrevField):
 			m.fields[m.fieldFocus].Blur()
-			m.fieldFocus = (m.fieldFocus - 1 + settingsFieldCount) % settingsFieldCount
+			m.fieldFocus = (m.fieldFocus - 1 + count) % count
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "enter":
-			if m.fieldFocus == settingsFieldCount-1 {
+		case key.Matches(msg, DefaultKeyMap.Select):
+			if m.fieldFocus == count-1 {
 				return m, m.saveSettings()
 			}
 			m.fields[m.fieldFocus].Blur()
 			m.fieldFocus++
 			return m, m.fields[m.fieldFocus].Focus()
 
-		case "ctrl+s":
+		case key.Matches(msg, DefaultKeyMap.Save):
 			return m, m.saveSettings()
 		}
 	}
@@ -199,45 +616,87 @@ func (m *SettingsModel) updateForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *SettingsModel) View() string {
-	if m.mode == settingsModeEdit {
+	switch {
+	case m.mode == settingsModeEdit:
 		return m.viewForm()
+	case m.confirm.Active():
+		return m.confirm.View()
+	case m.resetting:
+		return m.viewReset()
 	}
 	return m.viewSettings()
 }
 
-func (m *SettingsModel) viewSettings() string {
+// viewReset lists the reset commands with what the highlighted one deletes
+func (m *SettingsModel) viewReset() string {
 	var s string
-	s += titleStyle.Render("Settings") + "\n\n"
+	s += titleStyle.Render("Reset Data") + "\n\n"
+	for i, choice := range resetChoices {
+		if i == m.reset {
+			s += selectedStyle.Render("> "+choice.label) + "\n"
+		} else {
+			s += "  " + choice.label + "\n"
+		}
+	}
+	s += "\n"
+	for _, line := range resetChoices[m.reset].details {
+		s += subtitleStyle.Render("  "+line) + "\n"
+	}
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
+	return s
+}
+
+// viewTabs renders the section names with the current one highlighted
+func (m *SettingsModel) viewTabs() string {
+	activeStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Underline(true)
 
-	if m.statusMsg != "" {
-		s += lipgloss.NewStyle().Foreground(successColor).
-			Render("  "+m.statusMsg) + "\n\n"
+	tabs := make([]string, len(m.sections))
+	for i, section := range m.sections {
+		if i == m.section {
+			tabs[i] = activeStyle.Render(section.title)
+		} else {
+			tabs[i] = subtitleStyle.Render(section.title)
+		}
 	}
+	return "  " + strings.Join(tabs, subtitleStyle.Render(" │ "))
+}
 
-	cfg := m.app.Config.Invoice
+func (m *SettingsModel) viewSettings() string {
+	var s string
+	s += titleStyle.Render("Settings") + "\n\n"
+	s += m.viewTabs() + "\n\n"
 
 	labelStyle := lipgloss.NewStyle().Bold(true).Width(22)
 	valueStyle := lipgloss.NewStyle().Foreground(primaryColor)
 
-	s += subtitleStyle.Render("  Invoice Settings") + "\n\n"
-	s += fmt.Sprintf("  %s %s\n", labelStyle.Render("Output Directory:"), valueStyle.Render(cfg.OutputDir))
-	s += fmt.Sprintf("  %s %s\n", labelStyle.Render("Number Prefix:"), valueStyle.Render(cfg.NumberPrefix))
-	s += fmt.Sprintf("  %s %s\n", labelStyle.Render("Default Due Days:"), valueStyle.Render(strconv.Itoa(cfg.DefaultDueDays)))
+	section := m.sections[m.section]
+	for _, f := range section.fields {
+		display := f.display
+		if display == nil {
+			display = f.value
+		}
+		s += fmt.Sprintf("  %s %s\n", labelStyle.Render(f.label+":"), valueStyle.Render(display(m.app.Config)))
+	}
+	if section.note != "" {
+		s += "\n" + subtitleStyle.Render("  "+section.note) + "\n"
+	}
 
-	taxDisplay := fmt.Sprintf("%.2f%%", cfg.DefaultTaxRate*100)
-	s += fmt.Sprintf("  %s %s\n", labelStyle.Render("Default Tax Rate:"), valueStyle.Render(taxDisplay))
+	if m.err != nil {
+		s += "\n" + lipgloss.NewStyle().Foreground(errorColor).
+			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n"
+	}
 
-	s += "\n" + helpStyle.Render("  enter: edit settings")
+	s += "\n" + renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
 
 func (m *SettingsModel) viewForm() string {
 	var s string
-	s += titleStyle.Render("Edit Settings") + "\n\n"
+	section := m.sections[m.section]
+	s += titleStyle.Render("Edit "+section.title+" Settings") + "\n\n"
 
-	labels := []string{"Output Directory:", "Number Prefix:", "Default Due Days:", "Tax Rate (%):"}
-	for i, label := range labels {
+	for i, f := range section.editableFields() {
 		indicator := "  "
 		if i == m.fieldFocus {
 			indicator = "> "
@@ -246,7 +705,11 @@ func (m *SettingsModel) viewForm() string {
 		if i == m.fieldFocus {
 			labelStyle = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
 		}
-		s += fmt.Sprintf("%s%s\n  %s\n\n", indicator, labelStyle.Render(label), m.fields[i].View())
+		label := f.label
+		if f.hint != "" {
+			label += " " + f.hint
+		}
+		s += fmt.Sprintf("%s%s\n  %s\n\n", indicator, labelStyle.Render(label+":"), m.fields[i].View())
 	}
 
 	if m.err != nil {
@@ -254,7 +717,7 @@ func (m *SettingsModel) viewForm() string {
 			Render(fmt.Sprintf("  Error: %v", m.err)) + "\n\n"
 	}
 
-	s += helpStyle.Render("  tab/shift+tab: navigate fields  ctrl+s: save  enter: next/save  esc: cancel")
+	s += renderKeyHelp(m.KeyHelp()...)
 
 	return s
 }
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/config"
+	"github.com/andy/timesink/internal/domain"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sleepCheckInterval is how often the TUI checks whether the computer slept
+// since the last check
+const sleepCheckInterval = 5 * time.Second
+
+// sleepThreshold is the shortest gap between checks taken for a sleep
+// rather than a busy machine
+const sleepThreshold = time.Minute
+
+// sleepCheckMsg carries the time of a sleep check
+type sleepCheckMsg struct {
+	at time.Time
+}
+
+// sleepCheckCmd schedules the next sleep check
+func sleepCheckCmd() tea.Cmd {
+	return tea.Tick(sleepCheckInterval, func(t time.Time) tea.Msg { return sleepCheckMsg{at: t} })
+}
+
+// sleptBetween returns how much longer than a check interval passed between
+// two checks. Depending on the platform, either the wall clock or the
+// monotonic clock runs on while the computer sleeps, so the longer of the
+// two gaps is used.
+func sleptBetween(prev, now time.Time) time.Duration {
+	gap := max(now.Round(0).Sub(prev.Round(0)), now.Sub(prev))
+	return gap - sleepCheckInterval
+}
+
+// pauseForSleepCmd pauses a running timer as of asleep, when the computer
+// went to sleep, and resumes it again if timer.on_sleep is "resume" so the
+// sleep is left out
+func pauseForSleepCmd(a *app.App, asleep time.Time) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		timer, err := a.TimerService.GetActiveTimer(ctx)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		if timer == nil || timer.State() != domain.TimerStateRunning {
+			return nil
+		}
+		if err := a.TimerService.PauseAt(ctx, asleep); err != nil {
+			return ErrorMsg{Err: err}
+		}
+		if a.Config.Timer.OnSleep == config.OnSleepResume {
+			if err := a.TimerService.Resume(ctx); err != nil {
+				return ErrorMsg{Err: err}
+			}
+			return NotifyMsg{Level: NotifyWarning, Text: fmt.Sprintf(
+				"Left the %s the computer slept out of the timer", formatHours(time.Since(asleep).Hours()))}
+		}
+		return NotifyMsg{Level: NotifyWarning, Text: fmt.Sprintf(
+			"Timer paused at %s while the computer slept. Resume it on the Timer screen.", asleep.Format("15:04"))}
+	}
+}
@@ -4,35 +4,78 @@ import "github.com/charmbracelet/lipgloss"
 
 var (
 	// Colors
-	primaryColor = lipgloss.Color("39")  // Blue
-	accentColor  = lipgloss.Color("205") // Pink
-	mutedColor   = lipgloss.Color("241") // Gray
-	successColor = lipgloss.Color("76")  // Green
-	warningColor = lipgloss.Color("214") // Orange
-	errorColor   = lipgloss.Color("196") // Red
+	primaryColor lipgloss.Color
+	accentColor  lipgloss.Color
+	mutedColor   lipgloss.Color
+	successColor lipgloss.Color
+	warningColor lipgloss.Color
+	errorColor   lipgloss.Color
+
+	nonBillableColor lipgloss.Color
 
 	// Base styles
-	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
-	subtitleStyle = lipgloss.NewStyle().Foreground(mutedColor)
-	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("117")) // Bright cyan
-	selectedStyle = lipgloss.NewStyle().Bold(true).Background(primaryColor).Foreground(lipgloss.Color("0"))
+	titleStyle    lipgloss.Style
+	subtitleStyle lipgloss.Style
+	helpStyle     lipgloss.Style
+	selectedStyle lipgloss.Style
+
+	// Non-billable time in lists
+	nonBillableStyle lipgloss.Style
+
+	// Entries logged after the client's contracts ended
+	lapsedStyle lipgloss.Style
 
 	// Box styles
-	boxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1)
+	boxStyle lipgloss.Style
 
 	// Layout
-	borderColor    = lipgloss.Color("63") // Soft purple
-	appBorderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(borderColor).
-			Padding(1, 2)
+	borderColor    lipgloss.Color
+	appBorderStyle lipgloss.Style
 
 	// Header/Footer
-	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Padding(0, 1)
-	footerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("226")).Bold(true) // Bright yellow
+	headerStyle lipgloss.Style
+	footerStyle lipgloss.Style
 
 	// Timer specific
-	timerRunningStyle = lipgloss.NewStyle().Bold(true).Foreground(successColor)
-	timerPausedStyle  = lipgloss.NewStyle().Bold(true).Foreground(warningColor)
-	timerValueStyle   = lipgloss.NewStyle().Foreground(accentColor)
+	timerRunningStyle lipgloss.Style
+	timerPausedStyle  lipgloss.Style
+	timerValueStyle   lipgloss.Style
 )
+
+func init() {
+	applyTheme(themes[defaultThemeName])
+}
+
+// applyTheme sets the package colors and rebuilds every style from them
+func applyTheme(t theme) {
+	primaryColor = t.Primary
+	accentColor = t.Accent
+	mutedColor = t.Muted
+	successColor = t.Success
+	warningColor = t.Warning
+	errorColor = t.Error
+	nonBillableColor = t.NonBillable
+	borderColor = t.Border
+
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+	subtitleStyle = lipgloss.NewStyle().Foreground(mutedColor)
+	helpStyle = lipgloss.NewStyle().Foreground(t.Help)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Background(primaryColor).Foreground(t.SelectedText)
+
+	nonBillableStyle = lipgloss.NewStyle().Foreground(nonBillableColor)
+	lapsedStyle = lipgloss.NewStyle().Foreground(warningColor)
+
+	boxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1)
+
+	appBorderStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(1, 2)
+
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(primaryColor).Padding(0, 1)
+	footerStyle = lipgloss.NewStyle().Foreground(t.Footer).Bold(true)
+
+	timerRunningStyle = lipgloss.NewStyle().Bold(true).Foreground(successColor)
+	timerPausedStyle = lipgloss.NewStyle().Bold(true).Foreground(warningColor)
+	timerValueStyle = lipgloss.NewStyle().Foreground(accentColor)
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// descriptionSuggestions is how many past descriptions are offered for
+// completion
+const descriptionSuggestions = 100
+
+// suggestDescriptions lets ti complete descriptions used before for the
+// client, so recurring tasks are named the same way on every invoice. Typing
+// shows the most recent match greyed out; tab accepts it and ctrl+n/ctrl+p
+// pick another.
+func suggestDescriptions(a *app.App, ti *textinput.Model, clientID int64) {
+	descs, err := a.EntryRepo.RecentDescriptions(context.Background(), clientID, descriptionSuggestions)
+	if err != nil || len(descs) == 0 {
+		return // completion is a convenience; typing works without it
+	}
+	ti.ShowSuggestions = true
+	ti.SetSuggestions(descs)
+}
+
+// hasCompletion reports whether ti shows a suggestion longer than its value
+func hasCompletion(ti textinput.Model) bool {
+	return ti.ShowSuggestions && len(ti.MatchedSuggestions()) > 0 &&
+		len([]rune(ti.CurrentSuggestion())) > len([]rune(ti.Value()))
+}
+
+// completionKey is the help entry for accepting a suggestion
+func completionKey() key.Binding {
+	b := textinput.DefaultKeyMap.AcceptSuggestion
+	b.SetHelp(b.Keys()[0], "complete")
+	return b
+}
+
+// acceptCompletion fills in the suggestion ti shows, for forms where tab
+// would otherwise move to the next field. It reports whether there was one.
+func acceptCompletion(ti *textinput.Model) bool {
+	if !hasCompletion(*ti) {
+		return false
+	}
+	value := []rune(ti.Value())
+	ti.SetValue(string(value) + string([]rune(ti.CurrentSuggestion())[len(value):]))
+	ti.CursorEnd()
+	return true
+}
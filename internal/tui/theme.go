@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andy/timesink/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme is the set of colors used to build the TUI styles
+type theme struct {
+	Primary      lipgloss.Color
+	Accent       lipgloss.Color
+	Muted        lipgloss.Color
+	Success      lipgloss.Color
+	Warning      lipgloss.Color
+	Error        lipgloss.Color
+	NonBillable  lipgloss.Color
+	Help         lipgloss.Color
+	Border       lipgloss.Color
+	Footer       lipgloss.Color
+	SelectedText lipgloss.Color
+}
+
+const defaultThemeName = "dark"
+
+// themes are keyed by the name used in config.yaml
+var themes = map[string]theme{
+	"dark": {
+		Primary:      "39",  // Blue
+		Accent:       "205", // Pink
+		Muted:        "241", // Gray
+		Success:      "76",  // Green
+		Warning:      "214", // Orange
+		Error:        "196", // Red
+		NonBillable:  "180", // Tan
+		Help:         "117", // Bright cyan
+		Border:       "63",  // Soft purple
+		Footer:       "226", // Bright yellow
+		SelectedText: "0",   // Black
+	},
+	"light": {
+		Primary:      "25",  // Dark blue
+		Accent:       "162", // Magenta
+		Muted:        "243", // Gray
+		Success:      "28",  // Dark green
+		Warning:      "130", // Dark orange
+		Error:        "160", // Dark red
+		NonBillable:  "94",  // Brown
+		Help:         "30",  // Teal
+		Border:       "61",  // Slate purple
+		Footer:       "94",  // Brown
+		SelectedText: "15",  // White
+	},
+	"high-contrast": {
+		Primary:      "#00FFFF",
+		Accent:       "#FF00FF",
+		Muted:        "#FFFFFF",
+		Success:      "#00FF00",
+		Warning:      "#FFFF00",
+		Error:        "#FF0000",
+		NonBillable:  "#FFAF00",
+		Help:         "#FFFFFF",
+		Border:       "#FFFFFF",
+		Footer:       "#FFFF00",
+		SelectedText: "#000000",
+	},
+}
+
+// themeNames returns the built-in theme names
+func themeNames() []string {
+	return []string{"dark", "light", "high-contrast"}
+}
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// themeFromConfig builds a theme from a preset plus any custom hex colors
+func themeFromConfig(cfg config.ThemeConfig) (theme, error) {
+	name := cfg.Name
+	if name == "" {
+		name = defaultThemeName
+	}
+	t, ok := themes[name]
+	if !ok {
+		return theme{}, fmt.Errorf("unknown theme %q (supported: %s)", name, strings.Join(themeNames(), ", "))
+	}
+
+	overrides := []struct {
+		key   string
+		value string
+		dest  *lipgloss.Color
+	}{
+		{"primary", cfg.Colors.Primary, &t.Primary},
+		{"accent", cfg.Colors.Accent, &t.Accent},
+		{"muted", cfg.Colors.Muted, &t.Muted},
+		{"success", cfg.Colors.Success, &t.Success},
+		{"warning", cfg.Colors.Warning, &t.Warning},
+		{"error", cfg.Colors.Error, &t.Error},
+		{"non_billable", cfg.Colors.NonBillable, &t.NonBillable},
+		{"help", cfg.Colors.Help, &t.Help},
+		{"border", cfg.Colors.Border, &t.Border},
+		{"footer", cfg.Colors.Footer, &t.Footer},
+		{"selected_text", cfg.Colors.SelectedText, &t.SelectedText},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		if !hexColorPattern.MatchString(o.value) {
+			return theme{}, fmt.Errorf("theme color %s must be a hex color like #1E90FF, got %q", o.key, o.value)
+		}
+		*o.dest = lipgloss.Color(o.value)
+	}
+
+	return t, nil
+}
@@ -3,10 +3,12 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/andy/timesink/internal/app"
 	"github.com/andy/timesink/internal/domain"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -30,7 +32,7 @@ type clientsLoadedMsg struct {
 
 // timerStoppedMsg is sent when a timer is stopped successfully
 type timerStoppedMsg struct {
-	entry *domain.TimeEntry
+	entries []*domain.TimeEntry // one per day the timer ran on
 }
 
 // loadClientsCmd loads the list of active clients
@@ -41,34 +43,133 @@ func loadClientsCmd(a *app.App) tea.Cmd {
 	}
 }
 
+// timerStartedMsg is sent when a timer was started from another screen
+type timerStartedMsg struct {
+	err error
+}
+
+// startTimerCmd starts a timer for the given client and description. Screens
+// handle the resulting timerStartedMsg by switching to the Timer screen.
+func startTimerCmd(a *app.App, clientID int64, description string, billable bool) tea.Cmd {
+	return func() tea.Msg {
+		err := a.TimerService.Start(context.Background(), clientID, description, billable, a.Location(), "")
+		return timerStartedMsg{err: err}
+	}
+}
+
+// switchToTimerCmd requests a switch to the Timer screen
+func switchToTimerCmd() tea.Cmd {
+	return func() tea.Msg { return SwitchScreenMsg{Screen: ScreenTimer} }
+}
+
 // descSavedMsg is sent when a description update completes
 type descSavedMsg struct {
 	err error
 }
 
+// noteSavedMsg is sent when a timer note has been appended
+type noteSavedMsg struct {
+	timer *domain.ActiveTimer
+	err   error
+}
+
+// startAdjustedMsg is sent when the timer's start has been moved
+type startAdjustedMsg struct {
+	timer *domain.ActiveTimer
+	err   error
+}
+
 // TimerModel is a simple screen showing the active timer and controls
 type TimerModel struct {
-	app       *app.App
-	timer     *domain.ActiveTimer
-	clients   []*domain.Client
-	client    *domain.Client // current timer's client
-	err       error
-	statusMsg string
+	app     *app.App
+	timer   *domain.ActiveTimer
+	clients []*domain.Client
+	client  *domain.Client // current timer's client
+	err     error
 
 	// Description editing
 	editingDesc bool
 	descInput   textinput.Model
+
+	// Note entry
+	addingNote bool
+	noteInput  textinput.Model
+
+	// Start time adjustment
+	adjustingStart bool
+	adjustInput    textinput.Model
+
+	// Review of the entry before it is saved: stopFields holds the
+	// description, end time and billable flag, and stopEnd is when the
+	// timer was stopped, so time spent in the form is not logged
+	reviewingStop bool
+	stopFields    []textinput.Model
+	stopFocus     int
+	stopEnd       time.Time
+
+	// Whether the next timer started from this screen is billable
+	startBillable bool
+
+	// Finding the client to start a timer for by name, for clients beyond
+	// the nine with number keys
+	picking bool
+	picker  clientPicker
+
+	// Client picked to start a timer for while its description is typed
+	startClient *domain.Client
+	startInput  textinput.Model
 }
 
-// IsCapturingInput returns true when a timer is active so that keys like
-// r (resume), s, p, d are not intercepted by global screen navigation.
+// IsCapturingInput returns true while a client is being found or the
+// description or a note is being typed
 func (m *TimerModel) IsCapturingInput() bool {
-	return m.timer != nil
+	return m.picking || m.editingDesc || m.addingNote || m.adjustingStart || m.reviewingStop || m.startClient != nil
+}
+
+// OverridesKey claims every key except help while a timer is active so that
+// keys like r (resume), e (edit), and d are not intercepted by global navigation.
+func (m *TimerModel) OverridesKey(msg tea.KeyMsg) bool {
+	return m.timer != nil && !key.Matches(msg, DefaultKeyMap.Help)
+}
+
+// startFirstKey starts a timer for the first client in the list
+var startFirstKey = key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "start with first client"))
+
+// startBillableKey toggles billable while a new timer's description is typed,
+// where b would be part of the description
+var startBillableKey = key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "toggle billable"))
+
+// KeyHelp lists the keys for the idle or running timer
+func (m *TimerModel) KeyHelp() []key.Binding {
+	k := DefaultKeyMap
+	switch {
+	case m.editingDesc:
+		if hasCompletion(m.descInput) {
+			return []key.Binding{completionKey(), withHelp(k.Select, "save"), k.Cancel}
+		}
+		return []key.Binding{withHelp(k.Select, "save"), k.Cancel}
+	case m.addingNote:
+		return []key.Binding{withHelp(k.Select, "add"), k.Cancel}
+	case m.adjustingStart:
+		return []key.Binding{withHelp(k.Select, "move start"), k.Cancel}
+	case m.reviewingStop:
+		return []key.Binding{k.NextField, k.PrevField, withHelp(k.Select, "next/save"), k.Save, withHelp(k.Cancel, "keep running")}
+	case m.picking:
+		return pickerKeys("start")
+	case m.startClient != nil:
+		if hasCompletion(m.startInput) {
+			return []key.Binding{completionKey(), withHelp(k.Select, "start"), startBillableKey, k.Cancel}
+		}
+		return []key.Binding{withHelp(k.Select, "start"), startBillableKey, k.Cancel}
+	case m.timer == nil:
+		return []key.Binding{k.QuickStart, startFirstKey, withHelp(k.Select, "find client"), k.ToggleBillable}
+	}
+	return []key.Binding{k.Pause, k.Resume, withHelp(k.Edit, "edit description"), k.Note, k.AdjustStart, k.Stop, k.StopReview, withHelp(k.Delete, "discard")}
 }
 
 // NewTimerModel creates a new TimerModel
 func NewTimerModel(a *app.App) tea.Model {
-	m := &TimerModel{app: a}
+	m := &TimerModel{app: a, startBillable: true}
 	t, err := a.TimerService.GetActiveTimer(context.Background())
 	if err != nil {
 		m.err = err
@@ -119,9 +220,16 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case timerStoppedMsg:
 		m.timer = nil
 		m.client = nil
-		m.statusMsg = fmt.Sprintf("Entry saved: %.1fh",
-			msg.entry.Duration().Hours())
-		return m, nil
+		var hours float64
+		for _, e := range msg.entries {
+			hours += e.Duration().Hours()
+		}
+		if len(msg.entries) > 1 {
+			return m, notify(NotifySuccess, fmt.Sprintf("%d entries saved, split at midnight: %sh",
+				len(msg.entries), activeLocale.Number(hours, 1)))
+		}
+		return m, notify(NotifySuccess, fmt.Sprintf("Entry saved: %sh",
+			activeLocale.Number(hours, 1)))
 
 	case TimerTickMsg:
 		// Only continue ticking if we have an active timer
@@ -148,14 +256,32 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case startAdjustedMsg:
+		if msg.err != nil {
+			return m, notifyErr(msg.err)
+		}
+		if msg.timer != nil {
+			m.timer = msg.timer
+		}
+		return m, notify(NotifySuccess, "Timer now started at "+m.timer.StartTime.Format("15:04"))
+
+	case noteSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		if msg.timer != nil {
+			m.timer = msg.timer
+		}
+		return m, notify(NotifySuccess, "Note added")
+
 	case tea.KeyMsg:
 		m.err = nil
-		m.statusMsg = ""
 
 		// Description editing mode intercepts all keys
 		if m.editingDesc {
-			switch msg.String() {
-			case "enter":
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
 				desc := m.descInput.Value()
 				m.editingDesc = false
 				m.timer.Description = desc
@@ -163,7 +289,7 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					err := m.app.TimerService.UpdateDescription(context.Background(), desc)
 					return descSavedMsg{err: err}
 				}
-			case "esc":
+			case key.Matches(msg, DefaultKeyMap.Cancel):
 				m.editingDesc = false
 				return m, nil
 			default:
@@ -173,19 +299,111 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		switch msg.String() {
-		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		// The client picker intercepts all keys
+		if m.picking {
+			if key.Matches(msg, DefaultKeyMap.Cancel) {
+				m.picking = false
+				return m, nil
+			}
+			client, cmd := m.picker.Update(msg)
+			if client != nil {
+				m.picking = false
+				return m, m.promptStart(client)
+			}
+			return m, cmd
+		}
+
+		// The description prompt for a new timer intercepts all keys
+		if m.startClient != nil {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
+				client := m.startClient
+				m.startClient = nil
+				return m, m.startTimer(client, strings.TrimSpace(m.startInput.Value()))
+			case key.Matches(msg, textinput.DefaultKeyMap.AcceptSuggestion) && acceptCompletion(&m.startInput):
+				return m, nil
+			case key.Matches(msg, startBillableKey):
+				m.startBillable = !m.startBillable
+				return m, nil
+			case key.Matches(msg, DefaultKeyMap.Cancel):
+				m.startClient = nil
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.startInput, cmd = m.startInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// The stop review form intercepts all keys
+		if m.reviewingStop {
+			return m, m.updateStopReview(msg)
+		}
+
+		// Start adjustment mode intercepts all keys
+		if m.adjustingStart {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
+				start, err := domain.ParseTimerStart(m.adjustInput.Value(), m.timer.StartTime)
+				if err != nil {
+					return m, notifyErr(err)
+				}
+				m.adjustingStart = false
+				return m, m.adjustStart(start)
+			case key.Matches(msg, DefaultKeyMap.Cancel):
+				m.adjustingStart = false
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.adjustInput, cmd = m.adjustInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		// Note entry mode intercepts all keys
+		if m.addingNote {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.Select):
+				note := m.noteInput.Value()
+				m.addingNote = false
+				if note == "" {
+					return m, nil
+				}
+				return m, m.addNote(note)
+			case key.Matches(msg, DefaultKeyMap.Cancel):
+				m.addingNote = false
+				return m, nil
+			default:
+				var cmd tea.Cmd
+				m.noteInput, cmd = m.noteInput.Update(msg)
+				return m, cmd
+			}
+		}
+
+		switch {
+		case key.Matches(msg, DefaultKeyMap.QuickStart):
 			if m.timer == nil && m.clients != nil {
 				idx := int(msg.String()[0] - '1')
 				if idx >= 0 && idx < len(m.clients) && idx < 9 {
-					return m, m.startTimer(m.clients[idx])
+					return m, m.promptStart(m.clients[idx])
 				}
 			}
-		case "s":
+		case key.Matches(msg, startFirstKey):
 			if m.timer == nil && len(m.clients) > 0 {
-				return m, m.startTimer(m.clients[0])
+				return m, m.promptStart(m.clients[0])
+			}
+		case key.Matches(msg, DefaultKeyMap.Select):
+			if m.timer == nil && len(m.clients) > 0 {
+				m.picker = newClientPicker(m.clients)
+				m.picking = true
+				return m, m.picker.Focus()
+			}
+		case key.Matches(msg, DefaultKeyMap.ToggleBillable):
+			if m.timer == nil {
+				m.startBillable = !m.startBillable
 			}
-		case "p":
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Pause):
 			if m.timer != nil {
 				if err := m.app.TimerService.Pause(context.Background()); err != nil {
 					m.err = err
@@ -194,7 +412,7 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.timer, _ = m.app.TimerService.GetActiveTimer(context.Background())
 			}
 			return m, nil
-		case "r":
+		case key.Matches(msg, DefaultKeyMap.Resume):
 			if m.timer != nil {
 				if err := m.app.TimerService.Resume(context.Background()); err != nil {
 					m.err = err
@@ -203,24 +421,52 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.timer, _ = m.app.TimerService.GetActiveTimer(context.Background())
 				return m, tickTimer()
 			}
-		case "x":
+		case key.Matches(msg, DefaultKeyMap.Stop):
 			if m.timer != nil {
 				return m, m.stopTimer()
 			}
 			return m, nil
-		case "n":
+		case key.Matches(msg, DefaultKeyMap.Edit):
 			if m.timer != nil {
 				ti := textinput.New()
 				ti.Placeholder = "Enter description..."
 				ti.SetValue(m.timer.Description)
 				ti.Width = 40
+				suggestDescriptions(m.app, &ti, m.timer.ClientID)
 				ti.Focus()
 				m.descInput = ti
 				m.editingDesc = true
 				return m, ti.Focus()
 			}
 			return m, nil
-		case "d":
+		case key.Matches(msg, DefaultKeyMap.Note):
+			if m.timer != nil {
+				ti := textinput.New()
+				ti.Placeholder = "What are you doing right now?"
+				ti.CharLimit = 200
+				ti.Width = 50
+				m.noteInput = ti
+				m.addingNote = true
+				return m, m.noteInput.Focus()
+			}
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.StopReview):
+			if m.timer != nil {
+				return m, m.openStopReview()
+			}
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.AdjustStart):
+			if m.timer != nil {
+				ti := textinput.New()
+				ti.Placeholder = "-20m or " + m.timer.StartTime.Add(-20*time.Minute).Format("15:04")
+				ti.CharLimit = 20
+				ti.Width = 20
+				m.adjustInput = ti
+				m.adjustingStart = true
+				return m, m.adjustInput.Focus()
+			}
+			return m, nil
+		case key.Matches(msg, DefaultKeyMap.Delete):
 			if m.timer != nil {
 				if err := m.app.TimerService.Discard(context.Background()); err != nil {
 					m.err = err
@@ -228,7 +474,7 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				m.timer = nil
 				m.client = nil
-				m.statusMsg = "Timer discarded"
+				return m, notify(NotifySuccess, "Timer discarded")
 			}
 			return m, nil
 		}
@@ -237,10 +483,24 @@ func (m *TimerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *TimerModel) startTimer(client *domain.Client) tea.Cmd {
+// promptStart asks for the description of a new timer for client. An empty
+// description is allowed, so enter alone starts the timer.
+func (m *TimerModel) promptStart(client *domain.Client) tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = "What are you working on? (optional)"
+	ti.CharLimit = 200
+	ti.Width = 50
+	suggestDescriptions(m.app, &ti, client.ID)
+	m.startInput = ti
+	m.startClient = client
+	return m.startInput.Focus()
+}
+
+func (m *TimerModel) startTimer(client *domain.Client, description string) tea.Cmd {
+	billable := m.startBillable
 	return func() tea.Msg {
 		ctx := context.Background()
-		if err := m.app.TimerService.Start(ctx, client.ID, ""); err != nil {
+		if err := m.app.TimerService.Start(ctx, client.ID, description, billable, m.app.Location(), ""); err != nil {
 			return ErrorMsg{Err: err}
 		}
 		t, err := m.app.TimerService.GetActiveTimer(ctx)
@@ -253,14 +513,171 @@ func (m *TimerModel) startTimer(client *domain.Client) tea.Cmd {
 	}
 }
 
+func (m *TimerModel) addNote(note string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := m.app.TimerService.AddNote(ctx, note); err != nil {
+			return noteSavedMsg{err: err}
+		}
+		t, err := m.app.TimerService.GetActiveTimer(ctx)
+		return noteSavedMsg{timer: t, err: err}
+	}
+}
+
+// Fields of the stop review form
+const (
+	stopFieldDescription = iota
+	stopFieldEnd
+	stopFieldBillable
+	stopFieldCount
+)
+
+// openStopReview opens the form for correcting the timer's entry before it
+// is saved, prefilled with the timer as it is now
+func (m *TimerModel) openStopReview() tea.Cmd {
+	m.stopEnd = time.Now()
+	m.stopFields = make([]textinput.Model, stopFieldCount)
+
+	m.stopFields[stopFieldDescription] = textinput.New()
+	m.stopFields[stopFieldDescription].Placeholder = "What did you work on?"
+	m.stopFields[stopFieldDescription].CharLimit = 0 // notes can run long
+	m.stopFields[stopFieldDescription].Width = 50
+	m.stopFields[stopFieldDescription].SetValue(m.timer.DescriptionWithNotes())
+
+	m.stopFields[stopFieldEnd] = textinput.New()
+	m.stopFields[stopFieldEnd].Placeholder = m.stopEnd.Format("15:04")
+	m.stopFields[stopFieldEnd].CharLimit = 5
+	m.stopFields[stopFieldEnd].Width = 10
+	m.stopFields[stopFieldEnd].SetValue(m.stopEnd.Format("15:04"))
+
+	billable := "y"
+	if !m.timer.IsBillable {
+		billable = "n"
+	}
+	m.stopFields[stopFieldBillable] = textinput.New()
+	m.stopFields[stopFieldBillable].Placeholder = "y"
+	m.stopFields[stopFieldBillable].CharLimit = 3
+	m.stopFields[stopFieldBillable].Width = 5
+	m.stopFields[stopFieldBillable].SetValue(billable)
+
+	m.stopFocus = stopFieldDescription
+	m.reviewingStop = true
+	return m.stopFields[m.stopFocus].Focus()
+}
+
+// updateStopReview handles a key in the stop review form
+func (m *TimerModel) updateStopReview(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, DefaultKeyMap.Cancel):
+		m.reviewingStop = false
+		return nil
+	case key.Matches(msg, DefaultKeyMap.NextField):
+		m.stopFields[m.stopFocus].Blur()
+		m.stopFocus = (m.stopFocus + 1) % stopFieldCount
+		return m.stopFields[m.stopFocus].Focus()
+	case key.Matches(msg, DefaultKeyMap.PrevField):
+		m.stopFields[m.stopFocus].Blur()
+		m.stopFocus = (m.stopFocus - 1 + stopFieldCount) % stopFieldCount
+		return m.stopFields[m.stopFocus].Focus()
+	case key.Matches(msg, DefaultKeyMap.Select) && m.stopFocus < stopFieldCount-1:
+		m.stopFields[m.stopFocus].Blur()
+		m.stopFocus++
+		return m.stopFields[m.stopFocus].Focus()
+	case key.Matches(msg, DefaultKeyMap.Select), key.Matches(msg, DefaultKeyMap.Save):
+		edit, err := m.reviewedStop()
+		if err != nil {
+			return notifyErr(err)
+		}
+		m.reviewingStop = false
+		return m.stopTimerEdited(edit)
+	}
+	var cmd tea.Cmd
+	m.stopFields[m.stopFocus], cmd = m.stopFields[m.stopFocus].Update(msg)
+	return cmd
+}
+
+// reviewedStop reads the stop review form. An end left as shown keeps the
+// exact stop time; an end later in the day than the stop is taken to be
+// the day before, for timers stopped just after midnight.
+func (m *TimerModel) reviewedStop() (domain.TimerStop, error) {
+	edit := domain.TimerStop{
+		End:         m.stopEnd,
+		Description: m.stopFields[stopFieldDescription].Value(),
+	}
+
+	endStr := strings.TrimSpace(m.stopFields[stopFieldEnd].Value())
+	if endStr != m.stopEnd.Format("15:04") {
+		t, err := time.Parse("15:04", endStr)
+		if err != nil {
+			return edit, fmt.Errorf("end time must be HH:MM: %s", endStr)
+		}
+		end := time.Date(m.stopEnd.Year(), m.stopEnd.Month(), m.stopEnd.Day(), t.Hour(), t.Minute(), 0, 0, m.stopEnd.Location())
+		if end.After(m.stopEnd) {
+			end = end.AddDate(0, 0, -1)
+		}
+		edit.End = end
+	}
+
+	switch billable := strings.ToLower(strings.TrimSpace(m.stopFields[stopFieldBillable].Value())); billable {
+	case "y", "yes", "":
+		edit.IsBillable = true
+	case "n", "no":
+		edit.IsBillable = false
+	default:
+		return edit, fmt.Errorf("billable must be y or n: %s", billable)
+	}
+	return edit, nil
+}
+
+func (m *TimerModel) stopTimerEdited(edit domain.TimerStop) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := m.app.TimerService.StopEdited(context.Background(), edit)
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return timerStoppedMsg{entries: entries}
+	}
+}
+
+// viewStopReview renders the stop review form
+func (m *TimerModel) viewStopReview() string {
+	var b string
+	elapsed := m.stopEnd.Sub(m.timer.StartTime)
+	b += fmt.Sprintf("Review the entry before saving (ran %s from %s):\n\n",
+		formatHours(elapsed.Hours()), m.timer.StartTime.Format("15:04"))
+	labels := []string{"Description:", "End (HH:MM, earlier to trim):", "Billable (y/n):"}
+	for i, label := range labels {
+		indicator := "  "
+		labelStyle := subtitleStyle
+		if i == m.stopFocus {
+			indicator = "> "
+			labelStyle = lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+		}
+		b += fmt.Sprintf("%s%s\n  %s\n\n", indicator, labelStyle.Render(label), m.stopFields[i].View())
+	}
+	b += renderKeyHelp(m.KeyHelp()...) + "\n"
+	return b
+}
+
+func (m *TimerModel) adjustStart(start time.Time) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := m.app.TimerService.AdjustStart(ctx, start); err != nil {
+			return startAdjustedMsg{err: err}
+		}
+		t, err := m.app.TimerService.GetActiveTimer(ctx)
+		return startAdjustedMsg{timer: t, err: err}
+	}
+}
+
 func (m *TimerModel) stopTimer() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		entry, err := m.app.TimerService.Stop(ctx)
+		entries, err := m.app.TimerService.Stop(ctx)
 		if err != nil {
 			return ErrorMsg{Err: err}
 		}
-		return timerStoppedMsg{entry: entry}
+		return timerStoppedMsg{entries: entries}
 	}
 }
 
@@ -290,14 +707,29 @@ func (m *TimerModel) View() string {
 			"\n\nPress any key to dismiss"
 	}
 
-	if m.timer == nil {
-		// No active timer - show client selection
+	if m.timer == nil && m.picking {
 		b += title + "\n\n"
+		b += "Start a timer for:\n\n"
+		b += m.picker.View()
+		b += "\n" + renderKeyHelp(m.KeyHelp()...) + "\n"
+		return b
+	}
 
-		if m.statusMsg != "" {
-			b += lipgloss.NewStyle().Foreground(successColor).
-				Render("  "+m.statusMsg) + "\n\n"
+	if m.timer == nil && m.startClient != nil {
+		billable := "billable"
+		if !m.startBillable {
+			billable = nonBillableStyle.Render("non-billable")
 		}
+		b += title + "\n\n"
+		b += fmt.Sprintf("Start a %s timer for %s\n\n", billable, m.startClient.Name)
+		b += "Description: " + m.startInput.View() + "\n"
+		b += "\n" + renderKeyHelp(m.KeyHelp()...) + "\n"
+		return b
+	}
+
+	if m.timer == nil {
+		// No active timer - show client selection
+		b += title + "\n\n"
 
 		b += "No active timer. Select a client to start:\n\n"
 
@@ -315,7 +747,12 @@ func (m *TimerModel) View() string {
 				b += fmt.Sprintf("%s %s (%s/hr)\n", shortcut, client.Name, rate)
 			}
 		}
-		b += "\nKeys: 1-9=quick start, s=start with first client\n"
+		billable := "billable"
+		if !m.startBillable {
+			billable = nonBillableStyle.Render("non-billable")
+		}
+		b += fmt.Sprintf("\nNew timers are %s.\n", billable)
+		b += "\n" + renderKeyHelp(m.KeyHelp()...) + "\n"
 		return b
 	}
 
@@ -348,23 +785,49 @@ func (m *TimerModel) View() string {
 	}
 
 	b += title + "\n\n"
+	if m.reviewingStop {
+		b += fmt.Sprintf("Client: %s\n\n", clientName)
+		return b + m.viewStopReview()
+	}
 	b += fmt.Sprintf("State: %s\n", stateStr)
 	b += fmt.Sprintf("Client: %s\n", clientName)
+	if !m.timer.IsBillable {
+		b += nonBillableStyle.Render("Non-billable") + "\n"
+		rate = 0
+	}
 	if rate > 0 {
 		b += fmt.Sprintf("Rate: %s/hr\n", formatMoney(rate))
 	}
 	if m.editingDesc {
 		b += fmt.Sprintf("Description: %s\n", m.descInput.View())
-		b += helpStyle.Render("  enter=save, esc=cancel") + "\n"
+		b += renderKeyHelp(m.KeyHelp()...) + "\n"
 	} else if m.timer.Description != "" {
 		b += fmt.Sprintf("Description: %s\n", m.timer.Description)
 	}
-	b += fmt.Sprintf("Started: %s\n", m.timer.StartTime.Format("2006-01-02 15:04:05"))
+	if m.adjustingStart {
+		b += fmt.Sprintf("Started: %s  move to: %s\n", m.timer.StartTime.Format("2006-01-02 15:04:05"), m.adjustInput.View())
+		b += renderKeyHelp(m.KeyHelp()...) + "\n"
+	} else {
+		b += fmt.Sprintf("Started: %s\n", m.timer.StartTime.Format("2006-01-02 15:04:05"))
+	}
 	b += fmt.Sprintf("Elapsed: %s\n", elapsedStr)
 	if rate > 0 {
 		valueStr := timerValueStyle.Render(formatMoney(valueAccrued))
 		b += fmt.Sprintf("Value accrued: %s\n", valueStr)
 	}
-	b += "\nKeys: p=pause, r=resume, n=note, x=stop, d=discard\n"
+
+	if notes := m.timer.NoteLines(); len(notes) > 0 {
+		b += "\nNotes:\n"
+		for _, note := range notes {
+			b += subtitleStyle.Render("  "+note) + "\n"
+		}
+	}
+	if m.addingNote {
+		b += fmt.Sprintf("\nNote: %s\n", m.noteInput.View())
+		b += renderKeyHelp(m.KeyHelp()...) + "\n"
+	}
+	if !m.editingDesc && !m.addingNote && !m.adjustingStart {
+		b += "\n" + renderKeyHelp(m.KeyHelp()...) + "\n"
+	}
 	return b
 }
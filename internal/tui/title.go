@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andy/timesink/internal/app"
+	"github.com/andy/timesink/internal/domain"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// windowTitleInterval is how often the terminal title is refreshed while
+// timer.window_title is on; the title shows whole minutes
+const windowTitleInterval = 5 * time.Second
+
+// defaultWindowTitle is the terminal title when no timer is active
+const defaultWindowTitle = "timesink"
+
+// windowTitleMsg carries the terminal title for the active timer
+type windowTitleMsg struct {
+	title string
+}
+
+// windowTitleCmd schedules the next title refresh. The timer is read from
+// the database each time, so one started with `timesink timer start` in
+// another terminal shows up too.
+func windowTitleCmd(a *app.App) tea.Cmd {
+	return tea.Tick(windowTitleInterval, func(time.Time) tea.Msg {
+		return windowTitleMsg{title: timerWindowTitle(a)}
+	})
+}
+
+// timerWindowTitle returns the terminal title for the active timer, e.g.
+// "▶ Acme 1h 5m · timesink"
+func timerWindowTitle(a *app.App) string {
+	ctx := context.Background()
+	timer, err := a.TimerService.GetActiveTimer(ctx)
+	if err != nil || timer == nil {
+		return defaultWindowTitle
+	}
+
+	name := fmt.Sprintf("Client #%d", timer.ClientID)
+	if client, err := a.ClientRepo.GetByID(ctx, timer.ClientID); err == nil {
+		name = client.Name
+	}
+	icon := "▶"
+	if timer.State() == domain.TimerStatePaused {
+		icon = "⏸"
+	}
+	return fmt.Sprintf("%s %s %s · %s", icon, truncateStr(name, 30), formatHours(timer.Elapsed().Hours()), defaultWindowTitle)
+}